@@ -1,32 +1,138 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/omarshaarawi/gx/internal/commands/adopt"
+	"github.com/omarshaarawi/gx/internal/commands/annotate"
 	"github.com/omarshaarawi/gx/internal/commands/audit"
+	"github.com/omarshaarawi/gx/internal/commands/changelog"
+	"github.com/omarshaarawi/gx/internal/commands/compare"
+	"github.com/omarshaarawi/gx/internal/commands/deprecations"
+	"github.com/omarshaarawi/gx/internal/commands/doctor"
+	"github.com/omarshaarawi/gx/internal/commands/downgrade"
+	"github.com/omarshaarawi/gx/internal/commands/export"
+	"github.com/omarshaarawi/gx/internal/commands/fmtcmd"
+	"github.com/omarshaarawi/gx/internal/commands/initcmd"
+	"github.com/omarshaarawi/gx/internal/commands/lsplite"
+	"github.com/omarshaarawi/gx/internal/commands/migrate"
 	"github.com/omarshaarawi/gx/internal/commands/outdated"
+	"github.com/omarshaarawi/gx/internal/commands/policy"
+	"github.com/omarshaarawi/gx/internal/commands/prefetch"
+	"github.com/omarshaarawi/gx/internal/commands/prune"
+	"github.com/omarshaarawi/gx/internal/commands/resolve"
+	"github.com/omarshaarawi/gx/internal/commands/retract"
+	"github.com/omarshaarawi/gx/internal/commands/rollback"
+	"github.com/omarshaarawi/gx/internal/commands/search"
+	"github.com/omarshaarawi/gx/internal/commands/selfupdatecmd"
+	"github.com/omarshaarawi/gx/internal/commands/size"
+	"github.com/omarshaarawi/gx/internal/commands/stats"
+	"github.com/omarshaarawi/gx/internal/commands/toolchain"
+	"github.com/omarshaarawi/gx/internal/commands/tree"
+	"github.com/omarshaarawi/gx/internal/commands/undo"
 	"github.com/omarshaarawi/gx/internal/commands/update"
+	"github.com/omarshaarawi/gx/internal/commands/verifybuild"
+	"github.com/omarshaarawi/gx/internal/commands/vulndbcmd"
+	"github.com/omarshaarawi/gx/internal/commands/watch"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/diag"
+	"github.com/omarshaarawi/gx/internal/migration"
+	"github.com/omarshaarawi/gx/internal/modflag"
 	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
 	"github.com/spf13/cobra"
 )
 
 var (
-	version     = "dev"
-	flagVerbose bool
-	flagQuiet   bool
+	version      = "dev"
+	flagVerbose  bool
+	flagQuiet    bool
+	flagProgress string
+	flagAccess   bool
+	flagNoInput  bool
+	flagTimeout  time.Duration
+
+	// timeout is the limit applied to the running command, zero for none
+	timeout time.Duration
+	runCtx  context.Context    = context.Background()
+	cancel  context.CancelFunc = func() {}
 )
 
 var rootCmd = &cobra.Command{
 	Use:     "gx",
 	Short:   "My personal tooling for Go",
 	Version: version,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The module --mod names brings its own .gx.yaml
+		if err := modflag.Check(cmd); err != nil {
+			return err
+		}
+		config.SetProjectDir(modflag.Dir(cmd))
+
 		if flagQuiet {
 			ui.SetVerbosity(ui.VerbosityQuiet)
 		} else if flagVerbose {
 			ui.SetVerbosity(ui.VerbosityVerbose)
 		}
+
+		// Config errors are reported by the commands that need the config
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = config.Default()
+		}
+		ui.SetLocale(cfg.Locale)
+		ui.SetDateFormat(cfg.DateFormat)
+		if err := ui.SetTheme(cfg.Theme); err != nil {
+			ui.Error("⚠️  Warning: %v\n", err)
+		}
+		if err := ui.SetKeys(cfg.Keys); err != nil {
+			ui.Error("⚠️  Warning: %v\n", err)
+		}
+		ui.SetMouse(cfg.Mouse)
+		vulndb.SetDatabase(cfg.VulnDB)
+		migration.SetReplacements(cfg.Replacements)
+
+		timeout = flagTimeout
+		if !cmd.Flags().Changed("timeout") {
+			timeout = cfg.CommandTimeoutFor(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+		}
+		if timeout > 0 {
+			runCtx, cancel = context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(runCtx)
+
+			// A timeout isn't a usage error
+			if run := cmd.RunE; run != nil {
+				cmd.RunE = func(cmd *cobra.Command, args []string) error {
+					err := run(cmd, args)
+					if runCtx.Err() != nil {
+						cmd.SilenceUsage = true
+					}
+					return err
+				}
+			}
+		}
+
+		if err := ui.SetProgressMode(flagProgress); err != nil {
+			return err
+		}
+		ui.SetAccessible(flagAccess || cfg.Accessible)
+
+		// CI jobs have no one to answer, so they don't wait for input
+		// unless --non-interactive=false says otherwise
+		if cmd.Flags().Changed("non-interactive") {
+			if flagNoInput {
+				ui.SetNonInteractive("--non-interactive")
+			}
+		} else if name := ui.DetectCI(); name != "" {
+			ui.SetNonInteractive(name + " is set")
+		}
+		return nil
 	},
 }
 
@@ -34,14 +140,84 @@ func init() {
 	rootCmd.SetVersionTemplate(`{{.Version}}`)
 	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "Suppress non-essential output")
+	rootCmd.PersistentFlags().DurationVar(&flagTimeout, "timeout", 0, "Stop the command after this long, 0 for no limit (default from config, none)")
+	rootCmd.PersistentFlags().StringVar(&flagProgress, "progress", "auto", "Progress reporting: auto, json (NDJSON events on stderr), plain (lines of text on stderr), or none")
+	rootCmd.PersistentFlags().BoolVar(&flagNoInput, "non-interactive", false, "Never ask for input: fail where a command would, and show no spinners (default true in CI)")
+	rootCmd.PersistentFlags().BoolVar(&flagAccess, "accessible", false, "Screen reader mode: plain text progress and prompts instead of spinners and full-screen views, no box drawing")
+	modflag.Add(rootCmd)
 	rootCmd.AddCommand(outdated.NewCommand())
 	rootCmd.AddCommand(audit.NewCommand())
 	rootCmd.AddCommand(update.NewCommand())
+	rootCmd.AddCommand(export.NewCommand())
+	rootCmd.AddCommand(initcmd.NewCommand())
+	rootCmd.AddCommand(prune.NewCommand())
+	rootCmd.AddCommand(downgrade.NewCommand())
+	rootCmd.AddCommand(rollback.NewCommand())
+	rootCmd.AddCommand(undo.NewCommand())
+	rootCmd.AddCommand(lsplite.NewCommand())
+	rootCmd.AddCommand(changelog.NewCommand())
+	rootCmd.AddCommand(annotate.NewCommand())
+	rootCmd.AddCommand(size.NewCommand())
+	rootCmd.AddCommand(watch.NewCommand())
+	rootCmd.AddCommand(deprecations.NewCommand())
+	rootCmd.AddCommand(policy.NewCommand())
+	rootCmd.AddCommand(fmtcmd.NewCommand())
+	rootCmd.AddCommand(resolve.NewCommand())
+	rootCmd.AddCommand(selfupdatecmd.NewCommand())
+	rootCmd.AddCommand(adopt.NewCommand())
+	rootCmd.AddCommand(stats.NewCommand())
+	rootCmd.AddCommand(compare.NewCommand())
+	rootCmd.AddCommand(search.NewCommand())
+	rootCmd.AddCommand(verifybuild.NewCommand())
+	rootCmd.AddCommand(tree.NewCommand())
+	rootCmd.AddCommand(retract.NewCommand())
+	rootCmd.AddCommand(doctor.NewCommand())
+	rootCmd.AddCommand(toolchain.NewCommand())
+	rootCmd.AddCommand(prefetch.NewCommand())
+	rootCmd.AddCommand(vulndbcmd.NewCommand())
+	rootCmd.AddCommand(migrate.NewCommand())
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Keep a trace of HTTP requests for gx doctor --bundle
+	transport := diag.NewTransport(http.DefaultTransport)
+	http.DefaultTransport = transport
+
+	start := time.Now()
+	err := rootCmd.Execute()
+	recordRun(transport, start, err)
+
+	// Commands that skip failed lookups can finish without an error after the
+	// deadline, and killed subprocesses don't report it, so check the context
+	timedOut := errors.Is(runCtx.Err(), context.DeadlineExceeded)
+	cancel()
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
+	}
+	if timedOut {
+		fmt.Fprintf(os.Stderr, "gx: timed out after %s; raise the limit with --timeout or command_timeouts in the config\n", timeout)
+	}
+	if err != nil || timedOut {
 		os.Exit(1)
 	}
 }
+
+// recordRun adds the run to the diagnostics trail. Failures are ignored, and
+// shell completion requests are not recorded.
+func recordRun(transport *diag.Transport, start time.Time, err error) {
+	if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "__complete") {
+		return
+	}
+
+	entry := diag.Command{
+		Time:     start,
+		Args:     append([]string(nil), os.Args[1:]...),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	diag.RecordCommand(entry)
+	transport.Save()
+}
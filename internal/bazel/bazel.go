@@ -0,0 +1,110 @@
+// Package bazel renders a module's dependencies for Gazelle, either as
+// go_deps.module calls for a bzlmod MODULE.bazel or as go_repository rules
+// for a WORKSPACE deps.bzl macro.
+package bazel
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Repository is one external Go module
+type Repository struct {
+	Path    string // import path of the module
+	Version string
+	Sum     string // go.sum h1: hash of the module zip
+	// Replace is the module path that replaces Path, if any; Version and Sum
+	// then refer to the replacement
+	Replace string
+}
+
+// RepoName returns the repository name Gazelle derives from an import path,
+// e.g. github.com/spf13/cobra -> com_github_spf13_cobra
+func RepoName(importPath string) string {
+	importPath = strings.ToLower(importPath)
+	components := strings.Split(importPath, "/")
+
+	labels := strings.Split(components[0], ".")
+	reversed := make([]string, 0, len(labels)+len(components)-1)
+	for i := len(labels) - 1; i >= 0; i-- {
+		reversed = append(reversed, labels[i])
+	}
+
+	name := strings.Join(append(reversed, components[1:]...), "_")
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func sorted(repos []Repository) []Repository {
+	out := append([]Repository(nil), repos...)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// WriteModule writes a MODULE.bazel snippet declaring every repository with
+// go_deps.module. go_deps.module has no replace attribute, so replaced
+// modules are listed as comments pointing at go_deps.from_file.
+func WriteModule(w io.Writer, repos []Repository) error {
+	var b strings.Builder
+	b.WriteString(`go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")` + "\n")
+
+	var names []string
+	for _, r := range sorted(repos) {
+		b.WriteString("\n")
+		if r.Replace != "" {
+			fmt.Fprintf(&b, "# %s is replaced by %s@%s; use go_deps.from_file to apply replace directives\n", r.Path, r.Replace, r.Version)
+			continue
+		}
+		fmt.Fprintf(&b, "go_deps.module(\n    path = %q,\n    sum = %q,\n    version = %q,\n)\n", r.Path, r.Sum, r.Version)
+		names = append(names, RepoName(r.Path))
+	}
+
+	if len(names) > 0 {
+		b.WriteString("\nuse_repo(\n    go_deps,\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "    %q,\n", name)
+		}
+		b.WriteString(")\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteWorkspace writes a .bzl file defining macro, which declares every
+// repository with go_repository
+func WriteWorkspace(w io.Writer, repos []Repository, macro string) error {
+	var b strings.Builder
+	b.WriteString(`load("@bazel_gazelle//:deps.bzl", "go_repository")` + "\n\n")
+	fmt.Fprintf(&b, "def %s():\n", macro)
+
+	if len(repos) == 0 {
+		b.WriteString("    pass\n")
+	}
+
+	for i, r := range sorted(repos) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("    go_repository(\n")
+		fmt.Fprintf(&b, "        name = %q,\n", RepoName(r.Path))
+		fmt.Fprintf(&b, "        importpath = %q,\n", r.Path)
+		if r.Replace != "" {
+			fmt.Fprintf(&b, "        replace = %q,\n", r.Replace)
+		}
+		fmt.Fprintf(&b, "        sum = %q,\n", r.Sum)
+		fmt.Fprintf(&b, "        version = %q,\n", r.Version)
+		b.WriteString("    )\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,107 @@
+package bazel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepoName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/spf13/cobra":          "com_github_spf13_cobra",
+		"golang.org/x/mod":                "org_golang_x_mod",
+		"gopkg.in/yaml.v3":                "in_gopkg_yaml_v3",
+		"github.com/BurntSushi/toml":      "com_github_burntsushi_toml",
+		"github.com/mattn/go-isatty":      "com_github_mattn_go_isatty",
+		"go.uber.org/zap":                 "org_uber_go_zap",
+		"cloud.google.com/go/storage":     "com_google_cloud_go_storage",
+		"github.com/jackc/pgx/v5":         "com_github_jackc_pgx_v5",
+		"example.com/a~b":                 "com_example_a_b",
+		"github.com/charmbracelet/x/ansi": "com_github_charmbracelet_x_ansi",
+	}
+	for path, want := range tests {
+		if got := RepoName(path); got != want {
+			t.Errorf("RepoName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+var testRepos = []Repository{
+	{Path: "golang.org/x/mod", Version: "v0.14.0", Sum: "h1:mod="},
+	{Path: "github.com/spf13/cobra", Version: "v1.8.0", Sum: "h1:cobra="},
+	{Path: "github.com/pkg/errors", Version: "v0.9.2", Sum: "h1:fork=", Replace: "github.com/fork/errors"},
+}
+
+func TestWriteModule(t *testing.T) {
+	var b strings.Builder
+	if err := WriteModule(&b, testRepos); err != nil {
+		t.Fatalf("WriteModule() error: %v", err)
+	}
+
+	want := `go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")
+
+# github.com/pkg/errors is replaced by github.com/fork/errors@v0.9.2; use go_deps.from_file to apply replace directives
+
+go_deps.module(
+    path = "github.com/spf13/cobra",
+    sum = "h1:cobra=",
+    version = "v1.8.0",
+)
+
+go_deps.module(
+    path = "golang.org/x/mod",
+    sum = "h1:mod=",
+    version = "v0.14.0",
+)
+
+use_repo(
+    go_deps,
+    "com_github_spf13_cobra",
+    "org_golang_x_mod",
+)
+`
+	if b.String() != want {
+		t.Errorf("WriteModule() =\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteWorkspace(t *testing.T) {
+	var b strings.Builder
+	if err := WriteWorkspace(&b, testRepos, "go_dependencies"); err != nil {
+		t.Fatalf("WriteWorkspace() error: %v", err)
+	}
+
+	want := `load("@bazel_gazelle//:deps.bzl", "go_repository")
+
+def go_dependencies():
+    go_repository(
+        name = "com_github_pkg_errors",
+        importpath = "github.com/pkg/errors",
+        replace = "github.com/fork/errors",
+        sum = "h1:fork=",
+        version = "v0.9.2",
+    )
+
+    go_repository(
+        name = "com_github_spf13_cobra",
+        importpath = "github.com/spf13/cobra",
+        sum = "h1:cobra=",
+        version = "v1.8.0",
+    )
+
+    go_repository(
+        name = "org_golang_x_mod",
+        importpath = "golang.org/x/mod",
+        sum = "h1:mod=",
+        version = "v0.14.0",
+    )
+`
+	if b.String() != want {
+		t.Errorf("WriteWorkspace() =\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	WriteWorkspace(&b, nil, "deps")
+	if !strings.Contains(b.String(), "def deps():\n    pass\n") {
+		t.Errorf("empty macro should contain pass:\n%s", b.String())
+	}
+}
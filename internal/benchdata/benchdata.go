@@ -0,0 +1,229 @@
+// Package benchdata generates large, realistic module sets for benchmarks:
+// the go.mod requiring them, the go.mod of each module, and a module proxy
+// serving their versions. Sets are deterministic, so results compare across
+// runs. Only tests import it; a test checks that gx doesn't link it.
+package benchdata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Sizes are the requirement counts the benchmarks run at
+var Sizes = []int{200, 1000, 5000}
+
+// Set is a generated set of modules required by one go.mod
+type Set struct {
+	// Modules are the required module versions, direct ones first
+	Modules []module.Version
+	Direct  int
+
+	latest map[string]string // newest published version by module path
+	index  map[string]int    // position in Modules by module path
+}
+
+// New generates a set of n required modules. A quarter of them are direct,
+// paths mix GitHub, golang.org/x, gopkg.in and major version suffixes,
+// versions mix releases, pseudo-versions and +incompatible ones, and most
+// modules have a newer release.
+func New(n int) *Set {
+	s := &Set{
+		Direct: n / 4,
+		latest: make(map[string]string, n),
+		index:  make(map[string]int, n),
+	}
+	for i := range n {
+		path, version, latest := generate(i)
+		s.Modules = append(s.Modules, module.Version{Path: path, Version: version})
+		s.latest[path] = latest
+		s.index[path] = i
+	}
+	return s
+}
+
+// generate returns the path, required version and latest version of the
+// i-th module
+func generate(i int) (path, version, latest string) {
+	minor, patch := i%17, i%7
+	switch i % 10 {
+	case 0:
+		path = fmt.Sprintf("golang.org/x/tool%d", i)
+		version = fmt.Sprintf("v0.%d.%d", minor+10, patch)
+	case 1:
+		path = fmt.Sprintf("github.com/org%d/lib%d/v2", i%50, i)
+		version = fmt.Sprintf("v2.%d.%d", minor, patch)
+	case 2:
+		path = fmt.Sprintf("gopkg.in/pkg%d.v3", i)
+		version = fmt.Sprintf("v3.0.%d", patch)
+	case 3:
+		path = fmt.Sprintf("github.com/org%d/legacy%d", i%50, i)
+		version = fmt.Sprintf("v%d.%d.%d+incompatible", 2+i%3, minor, patch)
+	case 4:
+		path = fmt.Sprintf("github.com/org%d/snapshot%d", i%50, i)
+		version = fmt.Sprintf("v0.0.0-2023%02d%02d120000-%012x", 1+i%12, 1+i%28, i*2654435761%(1<<48))
+	default:
+		path = fmt.Sprintf("github.com/org%d/repo%d", i%50, i)
+		version = fmt.Sprintf("v1.%d.%d", minor, patch)
+	}
+
+	// Pseudo-versions and every fifth module are already up to date; the
+	// others have a patch or minor update
+	switch {
+	case i%10 == 4 || i%5 == 0:
+		latest = version
+	case i%2 == 0:
+		latest = bump(version, 0, 1)
+	default:
+		latest = bump(version, 1, 0)
+	}
+	return path, version, latest
+}
+
+// bump adds to the minor and patch of a version, keeping +incompatible
+func bump(version string, minor, patch int) string {
+	var major, mi, pa int
+	fmt.Sscanf(semver.Canonical(version), "v%d.%d.%d", &major, &mi, &pa)
+	if minor > 0 {
+		pa = 0
+	}
+	v := fmt.Sprintf("v%d.%d.%d", major, mi+minor, pa+patch)
+	if semver.Build(version) != "" {
+		v += semver.Build(version)
+	}
+	return v
+}
+
+// GoMod returns the go.mod requiring the set, with the direct and indirect
+// requirements in separate blocks, a replace and an exclude
+func (s *Set) GoMod() []byte {
+	var b strings.Builder
+	b.WriteString("module example.com/bench\n\ngo 1.22\n\ntoolchain go1.23.4\n\nrequire (\n")
+	for _, m := range s.Modules[:s.Direct] {
+		fmt.Fprintf(&b, "\t%s %s\n", m.Path, m.Version)
+	}
+	b.WriteString(")\n\nrequire (\n")
+	for _, m := range s.Modules[s.Direct:] {
+		fmt.Fprintf(&b, "\t%s %s // indirect\n", m.Path, m.Version)
+	}
+	b.WriteString(")\n")
+
+	if len(s.Modules) > 0 {
+		last := s.Modules[len(s.Modules)-1]
+		fmt.Fprintf(&b, "\nreplace %s => ../%s\n", last.Path, strings.ReplaceAll(last.Path, "/", "-"))
+		fmt.Fprintf(&b, "\nexclude %s v0.0.1\n", s.Modules[0].Path)
+	}
+	return []byte(b.String())
+}
+
+// ModFile returns the go.mod of a module in the set. Each requires up to
+// three other modules of the set, at the versions the set requires, so the
+// modules form one graph.
+func (s *Set) ModFile(modulePath, version string) ([]byte, bool) {
+	i, ok := s.index[modulePath]
+	if !ok || (version != s.Modules[i].Version && version != s.latest[modulePath]) {
+		return nil, false
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s\n\ngo 1.21\n", modulePath)
+	n := len(s.Modules)
+	if deps := i % 4; deps > 0 && n > 1 {
+		b.WriteString("\nrequire (\n")
+		for k := range deps {
+			j := (i*7 + k*13 + 1) % n
+			if j == i {
+				continue
+			}
+			fmt.Fprintf(&b, "\t%s %s\n", s.Modules[j].Path, s.Modules[j].Version)
+		}
+		b.WriteString(")\n")
+	}
+	return []byte(b.String()), true
+}
+
+// GetModFile serves the go.mod files of the set from memory, like a proxy
+// client's method of the same name
+func (s *Set) GetModFile(_ context.Context, modulePath, version string) ([]byte, error) {
+	data, ok := s.ModFile(modulePath, version)
+	if !ok {
+		return nil, fmt.Errorf("%s@%s is not in the set", modulePath, version)
+	}
+	return data, nil
+}
+
+// Latest returns the newest version published for a module of the set
+func (s *Set) Latest(modulePath string) string {
+	return s.latest[modulePath]
+}
+
+// Handler returns a module proxy handler serving the set, for tests and
+// benchmarks to start with httptest.NewServer. It answers @latest, @v/list
+// and the .info and .mod of the required and latest versions.
+func (s *Set) Handler() http.Handler {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		escaped, query, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/@")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		modulePath, err := module.UnescapePath(escaped)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		latest, ok := s.latest[modulePath]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case query == "latest":
+			fmt.Fprintf(w, `{"Version":%q,"Time":%q}`, latest, published.Format(time.RFC3339))
+		case query == "v/list":
+			for _, v := range s.versions(modulePath) {
+				if !module.IsPseudoVersion(v) {
+					fmt.Fprintln(w, v)
+				}
+			}
+		case strings.HasSuffix(query, ".info"):
+			version, err := module.UnescapeVersion(strings.TrimSuffix(strings.TrimPrefix(query, "v/"), ".info"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, `{"Version":%q,"Time":%q}`, version, published.Format(time.RFC3339))
+		case strings.HasSuffix(query, ".mod"):
+			version, err := module.UnescapeVersion(strings.TrimSuffix(strings.TrimPrefix(query, "v/"), ".mod"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			data, ok := s.ModFile(modulePath, version)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// versions returns the required and latest versions of a module, oldest first
+func (s *Set) versions(modulePath string) []string {
+	required, latest := s.Modules[s.index[modulePath]].Version, s.latest[modulePath]
+	if required == latest {
+		return []string{latest}
+	}
+	return []string{required, latest}
+}
@@ -0,0 +1,114 @@
+package benchdata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+func TestSet_GoMod(t *testing.T) {
+	s := New(200)
+
+	f, err := modfile.Parse("go.mod", s.GoMod(), nil)
+	if err != nil {
+		t.Fatalf("generated go.mod doesn't parse: %v", err)
+	}
+	if len(f.Require) != 200 {
+		t.Errorf("go.mod has %d requirements, want 200", len(f.Require))
+	}
+	direct := 0
+	for _, r := range f.Require {
+		if err := module.Check(r.Mod.Path, r.Mod.Version); err != nil {
+			t.Errorf("invalid requirement: %v", err)
+		}
+		if !r.Indirect {
+			direct++
+		}
+	}
+	if direct != s.Direct {
+		t.Errorf("go.mod has %d direct requirements, want %d", direct, s.Direct)
+	}
+	if len(f.Replace) != 1 || len(f.Exclude) != 1 {
+		t.Errorf("go.mod has %d replaces and %d excludes, want 1 of each", len(f.Replace), len(f.Exclude))
+	}
+}
+
+func TestSet_ModFile(t *testing.T) {
+	s := New(50)
+
+	for _, m := range s.Modules {
+		for _, version := range []string{m.Version, s.Latest(m.Path)} {
+			data, err := s.GetModFile(t.Context(), m.Path, version)
+			if err != nil {
+				t.Fatalf("GetModFile(%s, %s) error: %v", m.Path, version, err)
+			}
+			if _, err := modfile.Parse("go.mod", data, nil); err != nil {
+				t.Fatalf("go.mod of %s@%s doesn't parse: %v", m.Path, version, err)
+			}
+		}
+	}
+	if _, err := s.GetModFile(t.Context(), "example.com/missing", "v1.0.0"); err == nil {
+		t.Error("GetModFile() of a module outside the set succeeded, want an error")
+	}
+}
+
+func TestSet_Proxy(t *testing.T) {
+	s := New(20)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+	url := server.URL
+	m := s.Modules[1]
+
+	escaped, _ := module.EscapePath(m.Path)
+	resp, err := http.Get(url + "/" + escaped + "/@latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var info struct{ Version string }
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("decoding @latest: %v", err)
+	}
+	if info.Version != s.Latest(m.Path) {
+		t.Errorf("@latest = %s, want %s", info.Version, s.Latest(m.Path))
+	}
+
+	resp, err = http.Get(url + "/" + escaped + "/@v/" + m.Version + ".mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if want, _ := s.ModFile(m.Path, m.Version); string(data) != string(want) {
+		t.Errorf(".mod = %q, want %q", data, want)
+	}
+}
+
+// TestNotLinkedIntoGx keeps the fixtures and the test-only packages they
+// and their callers use out of the gx binary
+func TestNotLinkedIntoGx(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	out, err := exec.Command("go", "list", "-deps", "github.com/omarshaarawi/gx/cmd/gx").Output()
+	if err != nil {
+		t.Fatalf("go list -deps: %v", err)
+	}
+	forbidden := []string{"github.com/omarshaarawi/gx/internal/benchdata", "testing", "net/http/httptest"}
+	for _, dep := range strings.Fields(string(out)) {
+		for _, pkg := range forbidden {
+			if dep == pkg {
+				t.Errorf("gx imports %s, which only tests may use", pkg)
+			}
+		}
+	}
+}
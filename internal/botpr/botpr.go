@@ -0,0 +1,97 @@
+// Package botpr recognizes dependency bump pull requests opened by bots such
+// as Dependabot and Renovate, from their titles and descriptions.
+package botpr
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Bump is a single module version change proposed by a pull request
+type Bump struct {
+	Module string
+	From   string // empty when the title does not say
+	To     string
+	// Dir is the directory the bump applies to ("/" for the repository root)
+	Dir string
+}
+
+var (
+	// Bump github.com/a/b from 1.2.3 to 1.3.0 [in /tools]
+	dependabotTitle = regexp.MustCompile(`(?i)^(?:[\w()!/-]+:\s*)?bump (\S+) from (\S+) to (\S+?)(?: in (\S+))?$`)
+	// Updates `github.com/a/b` from 1.2.3 to 1.3.0 (grouped Dependabot pull requests)
+	dependabotBody = regexp.MustCompile("(?im)^\\s*updates? `([^`]+)` from (\\S+) to (\\S+?)\\s*$")
+	// Update module github.com/a/b to v1.3.0
+	renovateTitle = regexp.MustCompile(`(?i)^(?:[\w()!/-]+:\s*)?update (?:module |dependency )?(\S+) to (v\S+)$`)
+)
+
+// Parse returns the module bumps described by a pull request. Titles that
+// name a single module are preferred; grouped Dependabot pull requests are
+// read from their description. Updates that are not to a full semantic
+// version (digests, "to v2" majors) are ignored.
+func Parse(title, body string) []Bump {
+	title = strings.TrimSpace(title)
+
+	if m := dependabotTitle.FindStringSubmatch(title); m != nil {
+		if b, ok := newBump(m[1], m[2], m[3], m[4]); ok {
+			return []Bump{b}
+		}
+		return nil
+	}
+
+	if m := renovateTitle.FindStringSubmatch(title); m != nil {
+		if b, ok := newBump(m[1], "", m[2], ""); ok {
+			return []Bump{b}
+		}
+		return nil
+	}
+
+	var bumps []Bump
+	for _, m := range dependabotBody.FindAllStringSubmatch(body, -1) {
+		if b, ok := newBump(m[1], m[2], m[3], ""); ok {
+			bumps = append(bumps, b)
+		}
+	}
+	return bumps
+}
+
+func newBump(module, from, to, dir string) (Bump, bool) {
+	to = normalizeVersion(to)
+	if !fullVersion(to) || !strings.Contains(module, ".") {
+		return Bump{}, false
+	}
+	if from != "" {
+		from = normalizeVersion(from)
+		if !semver.IsValid(from) {
+			from = ""
+		}
+	}
+	if dir == "" {
+		dir = "/"
+	}
+	return Bump{Module: module, From: from, To: to, Dir: dir}, true
+}
+
+// normalizeVersion adds the leading "v" Dependabot leaves off
+func normalizeVersion(v string) string {
+	v = strings.TrimRight(v, ".,")
+	if v != "" && !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
+// fullVersion reports whether v is a complete semantic version rather than
+// a "v2" style major shorthand
+func fullVersion(v string) bool {
+	if !semver.IsValid(v) {
+		return false
+	}
+	core := strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	return strings.Count(core, ".") == 2
+}
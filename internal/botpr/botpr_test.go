@@ -0,0 +1,68 @@
+package botpr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		body  string
+		want  []Bump
+	}{
+		{
+			name:  "dependabot",
+			title: "Bump github.com/spf13/cobra from 1.8.0 to 1.9.1",
+			want:  []Bump{{Module: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.9.1", Dir: "/"}},
+		},
+		{
+			name:  "dependabot with prefix and directory",
+			title: "build(deps): bump golang.org/x/mod from 0.14.0 to 0.20.0 in /tools",
+			want:  []Bump{{Module: "golang.org/x/mod", From: "v0.14.0", To: "v0.20.0", Dir: "/tools"}},
+		},
+		{
+			name:  "renovate",
+			title: "fix(deps): update module github.com/stretchr/testify to v1.10.0",
+			want:  []Bump{{Module: "github.com/stretchr/testify", To: "v1.10.0", Dir: "/"}},
+		},
+		{
+			name:  "renovate major shorthand",
+			title: "Update module github.com/jackc/pgx to v5",
+		},
+		{
+			name:  "renovate digest",
+			title: "Update golang.org/x/exp digest to 4bf6b4c",
+		},
+		{
+			name:  "dependabot group",
+			title: "Bump the go-deps group with 2 updates",
+			body: "Bumps the go-deps group with 2 updates: [a](https://x) and [b](https://y).\n\n" +
+				"Updates `github.com/a/a` from 1.0.0 to 1.1.0\n<details>...</details>\n\n" +
+				"Updates `github.com/b/b` from 0.2.0 to 0.3.0-rc.1\n",
+			want: []Bump{
+				{Module: "github.com/a/a", From: "v1.0.0", To: "v1.1.0", Dir: "/"},
+				{Module: "github.com/b/b", From: "v0.2.0", To: "v0.3.0-rc.1", Dir: "/"},
+			},
+		},
+		{
+			name:  "unrelated",
+			title: "Fix typo in README",
+			body:  "Bump the version in docs",
+		},
+		{
+			name:  "npm package",
+			title: "Bump lodash from 4.17.20 to 4.17.21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.title, tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package adopt
+
+import (
+	"fmt"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// choosePlain asks which pull requests to adopt with a numbered prompt
+// instead of the list, for accessible mode
+func choosePlain(candidates []*Candidate) ([]*Candidate, error) {
+	options := make([]string, len(candidates))
+	for i, c := range candidates {
+		options[i] = fmt.Sprintf("pull request %d by %s: %s", c.PR.Number, c.PR.User.Login, item{candidate: c}.summary())
+	}
+
+	picked, err := ui.PromptChoice("Pull requests to adopt:", options, true)
+	if err != nil || len(picked) == 0 {
+		return nil, err
+	}
+	selected := make([]*Candidate, len(picked))
+	for i, idx := range picked {
+		selected[i] = candidates[idx]
+	}
+	return selected, nil
+}
@@ -0,0 +1,307 @@
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/botpr"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// Options configures the adopt command
+type Options struct {
+	Repo    string // owner/name; detected from the origin remote when empty
+	All     bool
+	Close   bool
+	DryRun  bool
+	NoTidy  bool
+	Force   bool
+	ModPath string
+}
+
+// Candidate is an open pull request with the bumps that apply to this module
+type Candidate struct {
+	PR      github.PullRequest
+	Bumps   []botpr.Bump
+	Current map[string]string // module -> version in go.mod
+}
+
+// change is one module update combined from the selected pull requests
+type change struct {
+	Module  string
+	Current string
+	Target  string
+	PRs     []int
+}
+
+// Run executes the adopt command
+func Run(ctx context.Context, opts Options) error {
+
+	workDir := filepath.Dir(opts.ModPath)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	// The pull requests are on the configured GitHub, maybe an Enterprise server
+	client := cfg.NewGitHubClient()
+
+	repo, err := resolveRepo(ctx, opts.Repo, workDir, client.Host())
+	if err != nil {
+		return err
+	}
+
+	if opts.Close && !opts.DryRun && !client.Authenticated() {
+		return fmt.Errorf("--close needs a GitHub token in GITHUB_TOKEN, GH_TOKEN or github.token in the config")
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	prs, err := ui.RunSimpleSpinner(fmt.Sprintf("Fetching open pull requests for %s/%s...", repo.Owner, repo.Name), func() ([]github.PullRequest, error) {
+		return client.ListPullRequests(ctx, repo.Owner, repo.Name)
+	})
+	if err != nil {
+		return fmt.Errorf("listing pull requests: %w", err)
+	}
+
+	candidates := findCandidates(prs, parser, moduleDir(ctx, workDir))
+	if len(candidates) == 0 {
+		fmt.Println("✓ No open dependency pull requests to adopt")
+		return nil
+	}
+
+	selected := candidates
+	if !opts.All {
+		selected, err = RunInteractive(candidates)
+		if err != nil {
+			return fmt.Errorf("interactive selection: %w", err)
+		}
+		if selected == nil {
+			fmt.Println("Adopt cancelled")
+			return nil
+		}
+		if len(selected) == 0 {
+			fmt.Println("No pull requests selected")
+			return nil
+		}
+	}
+
+	changes := combine(selected)
+
+	fmt.Printf("\n📋 Adopting %d update(s) from %d pull request(s):\n", len(changes), len(selected))
+	for _, c := range changes {
+		fmt.Printf("  • %s: %s → %s %s\n", c.Module, c.Current, c.Target, ui.UpToDateStyle.Render("("+prList(c.PRs)+")"))
+	}
+
+	if opts.DryRun {
+		return preview(opts, parser, changes)
+	}
+
+	tx, err := apply(opts, parser, changes, len(selected))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✓ Applied %d update(s)\n", len(changes))
+
+	if !opts.NoTidy {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := tx.Record(history.EffectTidy); err != nil {
+			ui.Error("⚠️  Warning: could not record adopt history: %v\n", err)
+		}
+		if err := gocmd.Run(ctx, workDir, "mod", "tidy"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod tidy' manually")
+		} else {
+			fmt.Println("✓ go.mod and go.sum updated")
+		}
+	}
+
+	if !opts.Close {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Commit the result, then close the adopted pull requests (or re-run with --close)"))
+		return nil
+	}
+
+	closePullRequests(ctx, client, repo, selected, tx)
+	return nil
+}
+
+// resolveRepo parses --repo or detects the repository from the origin
+// remote, which has to be on host
+func resolveRepo(ctx context.Context, flag, dir, host string) (github.Repo, error) {
+	if flag != "" {
+		owner, name, ok := strings.Cut(flag, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return github.Repo{}, fmt.Errorf("--repo must be owner/name, got %q", flag)
+		}
+		return github.Repo{Owner: owner, Name: name}, nil
+	}
+
+	remote, err := gitcmd.Output(ctx, dir, "remote", "get-url", "origin")
+	if err != nil {
+		return github.Repo{}, fmt.Errorf("detecting repository (use --repo owner/name): %w", err)
+	}
+	repo, ok := github.ParseRemoteURL(remote, host)
+	if !ok {
+		return github.Repo{}, fmt.Errorf("origin remote %s is not a repository on %s; use --repo owner/name, or set github.api_url in the config for GitHub Enterprise", remote, host)
+	}
+	return repo, nil
+}
+
+// moduleDir returns the module's directory relative to the repository root,
+// in the "/sub/dir" form Dependabot uses
+func moduleDir(ctx context.Context, dir string) string {
+	prefix, err := gitcmd.Output(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "/"
+	}
+	return path.Clean("/" + prefix)
+}
+
+// findCandidates keeps the pull requests with at least one bump that applies
+// to this module and is newer than the required version
+func findCandidates(prs []github.PullRequest, parser *modfile.Parser, dir string) []*Candidate {
+	var candidates []*Candidate
+	for _, pr := range prs {
+		c := &Candidate{PR: pr, Current: map[string]string{}}
+		for _, b := range botpr.Parse(pr.Title, pr.Body) {
+			if path.Clean(b.Dir) != dir {
+				continue
+			}
+			req := parser.FindRequire(b.Module)
+			if req == nil || semver.Compare(b.To, req.Mod.Version) <= 0 {
+				continue
+			}
+			c.Bumps = append(c.Bumps, b)
+			c.Current[b.Module] = req.Mod.Version
+		}
+		if len(c.Bumps) > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].PR.Number < candidates[j].PR.Number
+	})
+	return candidates
+}
+
+// combine merges the bumps of the selected pull requests, taking the highest
+// target when several pull requests move the same module
+func combine(selected []*Candidate) []change {
+	byModule := make(map[string]*change)
+	for _, c := range selected {
+		for _, b := range c.Bumps {
+			ch, ok := byModule[b.Module]
+			if !ok {
+				ch = &change{Module: b.Module, Current: c.Current[b.Module], Target: b.To}
+				byModule[b.Module] = ch
+			}
+			if semver.Compare(b.To, ch.Target) > 0 {
+				ch.Target = b.To
+			}
+			ch.PRs = append(ch.PRs, c.PR.Number)
+		}
+	}
+
+	changes := make([]change, 0, len(byModule))
+	for _, ch := range byModule {
+		changes = append(changes, *ch)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Module < changes[j].Module
+	})
+	return changes
+}
+
+// apply writes the combined changes to go.mod, recording the run in the history
+func apply(opts Options, parser *modfile.Parser, changes []change, prCount int) (*history.Transaction, error) {
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Begin("adopt", fmt.Sprintf("%d package(s) from %d pull request(s)", len(changes), prCount))
+	if err != nil {
+		return nil, fmt.Errorf("recording history: %w", err)
+	}
+
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	for _, c := range changes {
+		if err := writer.UpdateRequire(c.Module, c.Target); err != nil {
+			tx.Discard()
+			return nil, fmt.Errorf("updating %s: %w", c.Module, err)
+		}
+	}
+
+	if err := writer.SafeWrite(); err != nil {
+		tx.Discard()
+		return nil, fmt.Errorf("writing go.mod: %w", err)
+	}
+	if err := writer.CleanupBackup(); err != nil {
+		return nil, fmt.Errorf("cleanup backup: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record adopt history: %v\n", err)
+	}
+	return tx, nil
+}
+
+// preview prints the diff of go.mod with the combined changes and the
+// commands that would follow
+func preview(opts Options, parser *modfile.Parser, changes []change) error {
+	return dryrun.PrintModEdit(opts.ModPath, parser, !opts.NoTidy, func(writer *modfile.Writer) error {
+		for _, c := range changes {
+			if err := writer.UpdateRequire(c.Module, c.Target); err != nil {
+				return fmt.Errorf("updating %s: %w", c.Module, err)
+			}
+		}
+		return nil
+	})
+}
+
+// closePullRequests closes the adopted pull requests with a note pointing at
+// the others, recording each in the run's history since gx undo can't reopen them
+func closePullRequests(ctx context.Context, client *github.Client, repo github.Repo, selected []*Candidate, tx *history.Transaction) {
+	numbers := make([]int, len(selected))
+	for i, c := range selected {
+		numbers[i] = c.PR.Number
+	}
+
+	fmt.Println()
+	for _, c := range selected {
+		comment := fmt.Sprintf("Adopted locally with `gx adopt` as part of a combined update (%s); closing in favor of that change.", prList(numbers))
+		if err := client.ClosePullRequest(ctx, repo.Owner, repo.Name, c.PR.Number, comment); err != nil {
+			ui.Error("⚠️  Warning: %v\n", err)
+			continue
+		}
+		if err := tx.Record(fmt.Sprintf("closed pull request #%d", c.PR.Number)); err != nil {
+			ui.Error("⚠️  Warning: could not record adopt history: %v\n", err)
+		}
+		fmt.Printf("✓ Closed #%d %s\n", c.PR.Number, ui.UpToDateStyle.Render(c.PR.Title))
+	}
+}
+
+func prList(numbers []int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = fmt.Sprintf("#%d", n)
+	}
+	return strings.Join(parts, ", ")
+}
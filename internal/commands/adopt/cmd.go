@@ -0,0 +1,86 @@
+package adopt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRepo   string
+	flagAll    bool
+	flagClose  bool
+	flagDryRun bool
+	flagNoTidy bool
+	flagForce  bool
+)
+
+// NewCommand creates the adopt command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Apply open Dependabot/Renovate bumps locally in one update",
+		Long: `Find open dependency bump pull requests on GitHub, pick the ones to
+take, and apply all of their version changes to go.mod in a single update,
+so a pile of bot pull requests becomes one reviewed change.
+
+Pull requests are recognized from Dependabot and Renovate titles, and from
+the description of grouped Dependabot pull requests. Bumps for modules that
+are no longer required, or already at that version, are skipped.
+
+The repository is detected from the origin remote. Set GITHUB_TOKEN (or
+github.token in the config) to avoid rate limits; it is required for
+--close. For GitHub Enterprise, set github.api_url or GITHUB_API_URL.
+
+Examples:
+  # Pick pull requests to adopt
+  gx adopt
+
+  # Adopt every open bump and close the pull requests
+  gx adopt --all --close
+
+  # Preview the combined update
+  gx adopt --all --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runAdopt,
+	}
+
+	cmd.Flags().StringVar(&flagRepo, "repo", "", "GitHub repository as owner/name (default: from the origin remote)")
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Adopt every matching pull request without prompting")
+	cmd.Flags().BoolVar(&flagClose, "close", false, "Comment on and close the adopted pull requests")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip running go mod tidy")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if !flagAll {
+		if err := ui.RequireInteractive("choosing pull requests", "pass --all to adopt every matching one"); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	opts := Options{
+		Repo:    flagRepo,
+		All:     flagAll,
+		Close:   flagClose,
+		DryRun:  flagDryRun,
+		NoTidy:  flagNoTidy,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,235 @@
+package adopt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+var (
+	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
+	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	headerStyle       = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("241"))
+	targetStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("green"))
+	dimmedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	numberStyle  = lipgloss.NewStyle().Width(8).MaxWidth(8)
+	summaryStyle = lipgloss.NewStyle().Width(70).MaxWidth(70)
+)
+
+type item struct {
+	candidate *Candidate
+	selected  bool
+}
+
+func (i item) FilterValue() string { return i.candidate.PR.Title }
+
+// summary describes the bumps of a pull request on one line
+func (i item) summary() string {
+	c := i.candidate
+	if len(c.Bumps) == 1 {
+		b := c.Bumps[0]
+		return fmt.Sprintf("%s %s → %s", b.Module, c.Current[b.Module], targetStyle.Render(b.To))
+	}
+
+	modules := make([]string, len(c.Bumps))
+	for idx, b := range c.Bumps {
+		modules[idx] = b.Module
+	}
+	return fmt.Sprintf("%d modules: %s", len(c.Bumps), strings.Join(modules, ", "))
+}
+
+type itemDelegate struct{}
+
+func (d itemDelegate) Height() int                             { return 1 }
+func (d itemDelegate) Spacing() int                            { return 0 }
+func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	checkbox := "○"
+	if i.selected {
+		checkbox = "◉"
+	}
+
+	row := fmt.Sprintf("%s %s %s %s",
+		checkbox,
+		numberStyle.Render(fmt.Sprintf("#%d", i.candidate.PR.Number)),
+		summaryStyle.Render(i.summary()),
+		dimmedStyle.Render(i.candidate.PR.User.Login),
+	)
+
+	if index == m.Index() {
+		fmt.Fprint(w, selectedItemStyle.Render("> "+row))
+	} else {
+		fmt.Fprint(w, itemStyle.Render("  "+row))
+	}
+}
+
+// listTop is the line View starts the list on
+const listTop = 5
+
+type model struct {
+	list      list.Model
+	showHelp  bool // showing the ? overlay
+	quitting  bool
+	confirmed bool
+}
+
+// toggleCurrent selects or unselects the pull request under the cursor
+func (m *model) toggleCurrent() {
+	if i, ok := m.list.SelectedItem().(item); ok {
+		i.selected = !i.selected
+		m.list.SetItem(m.list.Index(), i)
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.showHelp {
+			switch {
+			case msg.String() == "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+			case key.Matches(msg, ui.Key("help"), key.NewBinding(key.WithKeys("esc"))):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, ui.Key("help")):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, ui.Key("quit")):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, ui.Key("toggle")):
+			m.toggleCurrent()
+			return m, nil
+
+		case key.Matches(msg, ui.Key("select-all"), ui.Key("select-none")):
+			selectAll := key.Matches(msg, ui.Key("select-all"))
+			for idx, listItem := range m.list.Items() {
+				if i, ok := listItem.(item); ok {
+					i.selected = selectAll
+					m.list.SetItem(idx, i)
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, ui.Key("confirm")):
+			m.confirmed = true
+			return m, tea.Quit
+		}
+
+	case tea.MouseMsg:
+		if !m.showHelp && ui.ListMouse(&m.list, msg, listTop, 1) {
+			m.toggleCurrent()
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m model) View() string {
+	if m.quitting {
+		return ""
+	}
+	if m.showHelp {
+		return ui.KeyHelp("⌨️  Selection keys",
+			[]key.Binding{ui.Key("up"), ui.Key("down"), ui.Key("next-page"), ui.Key("prev-page"), ui.Key("first"), ui.Key("last")},
+			[]key.Binding{ui.Key("toggle"), ui.Key("select-all"), ui.Key("select-none")},
+			[]key.Binding{ui.Key("confirm"), ui.Key("quit")},
+		)
+	}
+
+	titleText := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Render("🤖 Select pull requests to adopt")
+
+	helpText := ui.ShortKeyHelp(ui.Key("toggle"), ui.Key("confirm"), ui.Key("select-all"), ui.Key("select-none"), ui.Key("quit"), ui.Key("help"))
+
+	columnHeader := fmt.Sprintf("      %s %s %s",
+		headerStyle.Render(numberStyle.Render("PR")),
+		headerStyle.Render(summaryStyle.Render("Update")),
+		headerStyle.Render("Author"),
+	)
+
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		titleText,
+		helpText,
+		"",
+		columnHeader,
+	)
+
+	return header + "\n" + m.list.View()
+}
+
+// RunInteractive lets the user pick pull requests. It returns nil if the
+// selection was cancelled.
+func RunInteractive(candidates []*Candidate) ([]*Candidate, error) {
+	if ui.Accessible() {
+		return choosePlain(candidates)
+	}
+
+	items := make([]list.Item, len(candidates))
+	for i, c := range candidates {
+		items[i] = item{candidate: c}
+	}
+
+	const defaultWidth = 120
+	const defaultHeight = 30
+
+	l := list.New(items, itemDelegate{}, defaultWidth, defaultHeight)
+	l.Title = ""
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.KeyMap = ui.ListKeys()
+
+	p := tea.NewProgram(model{list: l}, ui.InteractiveOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running interactive UI: %w", err)
+	}
+
+	result := finalModel.(model)
+	if result.quitting && !result.confirmed {
+		return nil, nil
+	}
+
+	selected := []*Candidate{}
+	for _, listItem := range result.list.Items() {
+		if i, ok := listItem.(item); ok && i.selected {
+			selected = append(selected, i.candidate)
+		}
+	}
+	return selected, nil
+}
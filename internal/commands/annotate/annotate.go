@@ -0,0 +1,164 @@
+package annotate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// Options configures the annotate command
+type Options struct {
+	Clean   bool
+	All     bool
+	NoAudit bool
+	DryRun  bool
+	Force   bool
+	ModPath string
+}
+
+// Run executes the annotate command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	if opts.Clean {
+		return clean(parser, opts)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	requires := parser.DirectRequires()
+	if opts.All {
+		requires = parser.AllRequires()
+	}
+
+	var targets []*xmodfile.Require
+	for _, req := range requires {
+		if cfg.IgnoreRuleFor(req.Mod.Path) != nil {
+			continue
+		}
+		targets = append(targets, req)
+	}
+
+	latest, err := fetchLatestWithSpinner(ctx, targets, cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements()))
+	if err != nil {
+		return fmt.Errorf("checking for updates: %w", err)
+	}
+
+	vulnIDs := make(map[string][]string)
+	if !opts.NoAudit {
+		scanner, err := vulndb.NewScanner()
+		if err != nil {
+			return fmt.Errorf("creating scanner: %w (use --no-audit to skip)", err)
+		}
+
+		result, err := scanModuleWithSpinner(ctx, scanner, opts.ModPath)
+		if err != nil {
+			return fmt.Errorf("scanning module: %w", err)
+		}
+
+		for _, v := range result.Vulnerabilities {
+			vulnIDs[v.Package] = append(vulnIDs[v.Package], v.ID)
+		}
+	}
+
+	annotated, cleared := 0, 0
+	for _, req := range targets {
+		note := buildNote(req.Mod.Version, latest[req.Mod.Path], vulnIDs[req.Mod.Path])
+		if note == "" {
+			if modfile.ClearAnnotation(req) {
+				cleared++
+			}
+			continue
+		}
+		modfile.SetAnnotation(req, note)
+		annotated++
+	}
+
+	if annotated == 0 && cleared == 0 {
+		fmt.Println("\n✓ All dependencies are up to date, nothing to annotate")
+		return nil
+	}
+
+	if opts.DryRun {
+		return preview(opts, parser)
+	}
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	writer.CleanupBackup()
+
+	fmt.Printf("\n✓ Annotated %s requirements", ui.FormatCount(annotated))
+	if cleared > 0 {
+		fmt.Printf(" (%s stale annotations removed)", ui.FormatCount(cleared))
+	}
+	fmt.Println()
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx annotate --clean' to remove them"))
+
+	return nil
+}
+
+func clean(parser *modfile.Parser, opts Options) error {
+	cleared := 0
+	for _, req := range parser.AllRequires() {
+		if modfile.ClearAnnotation(req) {
+			cleared++
+		}
+	}
+
+	if cleared == 0 {
+		fmt.Println("✓ No gx annotations found")
+		return nil
+	}
+
+	if opts.DryRun {
+		return preview(opts, parser)
+	}
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	writer.CleanupBackup()
+
+	fmt.Printf("✓ Removed %s annotations\n", ui.FormatCount(cleared))
+	return nil
+}
+
+// preview prints the diff of go.mod with the annotations made to the parsed
+// go.mod
+func preview(opts Options, parser *modfile.Parser) error {
+	return dryrun.PrintModEdit(opts.ModPath, parser, false, nil)
+}
+
+// buildNote formats the annotation text, e.g. "v1.9.2 available (minor), GO-2025-1234"
+func buildNote(current, latest string, vulns []string) string {
+	var parts []string
+
+	if updateType := versions.Classify(current, latest); updateType != versions.None {
+		parts = append(parts, fmt.Sprintf("%s available (%s)", latest, updateType))
+	}
+
+	if len(vulns) > 0 {
+		ids := append([]string(nil), vulns...)
+		sort.Strings(ids)
+		parts = append(parts, strings.Join(ids, ", "))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,71 @@
+package annotate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagClean   bool
+	flagAll     bool
+	flagNoAudit bool
+	flagDryRun  bool
+	flagForce   bool
+)
+
+// NewCommand creates the annotate command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Write update and vulnerability status as go.mod comments",
+		Long: `Add a "// gx: ..." comment after each require line that has an update
+available or a known vulnerability, so the status shows up in code review.
+
+Running annotate again refreshes the comments. Other comments, including
+"// indirect", are left untouched.
+
+Examples:
+  # Annotate direct dependencies
+  gx annotate
+
+  # Include indirect dependencies
+  gx annotate --all
+
+  # Remove all gx annotations
+  gx annotate --clean
+
+  # Show the comments as a diff without writing them
+  gx annotate --dry-run`,
+		RunE: runAnnotate,
+	}
+
+	cmd.Flags().BoolVar(&flagClean, "clean", false, "Remove gx annotations instead of writing them")
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Annotate indirect dependencies too")
+	cmd.Flags().BoolVar(&flagNoAudit, "no-audit", false, "Skip the vulnerability scan")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runAnnotate(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Clean:   flagClean,
+		All:     flagAll,
+		NoAudit: flagNoAudit,
+		DryRun:  flagDryRun,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,57 @@
+package annotate
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// fetchLatestWithSpinner returns the latest version of each module, keyed by path.
+// Modules the proxy can't resolve are left out.
+func fetchLatestWithSpinner(ctx context.Context, requires []*xmodfile.Require, client *proxy.Client) (map[string]string, error) {
+	if len(requires) == 0 {
+		return map[string]string{}, nil
+	}
+
+	return ui.RunWithSpinner(ui.SpinnerTask[map[string]string]{
+		Message: "Checking for updates...",
+		Phase:   "check-updates",
+		Total:   len(requires),
+		Run: func(progress chan<- int) (map[string]string, error) {
+			latest := make(map[string]string, len(requires))
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for _, req := range requires {
+				wg.Add(1)
+				go func(r *xmodfile.Require) {
+					defer wg.Done()
+
+					info, err := client.Latest(ctx, r.Mod.Path)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err == nil {
+						latest[r.Mod.Path] = info.Version
+					}
+					loaded++
+					progress <- loaded
+				}(req)
+			}
+
+			wg.Wait()
+			return latest, nil
+		},
+	})
+}
+
+func scanModuleWithSpinner(ctx context.Context, scanner *vulndb.Scanner, modPath string) (*vulndb.ScanResult, error) {
+	return ui.RunSimpleSpinner("Scanning for vulnerabilities...", func() (*vulndb.ScanResult, error) {
+		return scanner.ScanModule(ctx, modPath)
+	})
+}
@@ -8,23 +8,38 @@ import (
 
 	"github.com/omarshaarawi/gx/internal/ui"
 	"github.com/omarshaarawi/gx/internal/vulndb"
+	"github.com/omarshaarawi/gx/internal/workspace"
 )
 
 // Options configures the audit command
 type Options struct {
-	Severity []string
-	JSON     bool
-	ModPath  string
+	Severity  []string
+	JSON      bool
+	Format    string // FormatVDR for a CycloneDX vulnerability disclosure report; otherwise JSON picks the format
+	Version   string // of gx, for the report's tool
+	ModPath   string
+	Workspace string // go.work path; when set, every member module is scanned
+	Porcelain bool   // print stable records for scripts on stdout, everything else on stderr
+
+	porcelain *ui.Porcelain // where records go, set by Run with Porcelain
 }
 
 // Run executes the audit command
 func Run(ctx context.Context, opts Options) error {
 
+	if opts.Porcelain {
+		opts.porcelain = ui.StartPorcelain("audit")
+	}
+
 	scanner, err := vulndb.NewScanner()
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
 	}
 
+	if opts.Workspace != "" {
+		return runWorkspace(ctx, opts, scanner)
+	}
+
 	result, err := scanModuleWithSpinner(ctx, scanner, opts.ModPath)
 	if err != nil {
 		return fmt.Errorf("scanning module: %w", err)
@@ -35,6 +50,13 @@ func Run(ctx context.Context, opts Options) error {
 		vulns = vulndb.FilterBySeverity(vulns, opts.Severity)
 	}
 
+	if opts.porcelain != nil {
+		writeVulnRecords(opts, "", vulns)
+		return nil
+	}
+	if opts.Format == FormatVDR {
+		return outputVDR(opts, vulns)
+	}
 	if opts.JSON {
 		return outputJSON(vulns, result)
 	}
@@ -42,11 +64,95 @@ func Run(ctx context.Context, opts Options) error {
 	return outputTable(vulns, result)
 }
 
+// moduleVulns holds the scan result for one workspace member
+type moduleVulns struct {
+	Module          string                  `json:"module"`
+	Dir             string                  `json:"dir"`
+	TotalVulns      int                     `json:"total_vulnerabilities"`
+	Vulnerabilities []*vulndb.Vulnerability `json:"vulnerabilities"`
+}
+
+// runWorkspace scans every module of a go.work workspace
+func runWorkspace(ctx context.Context, opts Options, scanner *vulndb.Scanner) error {
+	ws, err := workspace.Load(opts.Workspace)
+	if err != nil {
+		return fmt.Errorf("loading workspace: %w", err)
+	}
+
+	var results []moduleVulns
+	total, affected := 0, 0
+	unique := make(map[string]bool)
+
+	for _, member := range ws.Members {
+		if !opts.JSON && opts.porcelain == nil {
+			fmt.Printf("\n%s %s\n", ui.HeaderStyle.Render("🗂  "+member.ModulePath), ui.UpToDateStyle.Render("("+member.Dir+")"))
+		}
+
+		result, err := scanModuleWithSpinner(ctx, scanner, member.ModPath)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", member.Dir, err)
+		}
+
+		vulns := result.Vulnerabilities
+		if len(opts.Severity) > 0 {
+			vulns = vulndb.FilterBySeverity(vulns, opts.Severity)
+		}
+
+		results = append(results, moduleVulns{
+			Module:          member.ModulePath,
+			Dir:             member.Dir,
+			TotalVulns:      len(vulns),
+			Vulnerabilities: vulns,
+		})
+
+		total += len(vulns)
+		if len(vulns) > 0 {
+			affected++
+		}
+		for _, v := range vulns {
+			unique[v.ID] = true
+		}
+
+		if opts.porcelain != nil {
+			writeVulnRecords(opts, member.ModulePath, vulns)
+		} else if !opts.JSON {
+			if err := outputTable(vulns, result); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.porcelain != nil {
+		return nil
+	}
+
+	if opts.JSON {
+		output := map[string]any{
+			"total_vulnerabilities": total,
+			"modules":               results,
+		}
+
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("\n%s %s vulnerabilities (%s unique) in %s of %s modules\n",
+		ui.SummaryStyle.Render("📊 Workspace summary:"), ui.FormatCount(total),
+		ui.FormatCount(len(unique)), ui.FormatCount(affected), ui.FormatCount(len(ws.Members)))
+
+	return nil
+}
+
 func outputJSON(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error {
 	output := map[string]interface{}{
-		"total_scanned":      result.TotalScanned,
+		"total_scanned":         result.TotalScanned,
 		"total_vulnerabilities": len(vulns),
-		"vulnerabilities":    vulns,
+		"vulnerabilities":       vulns,
 	}
 
 	data, err := json.MarshalIndent(output, "", "  ")
@@ -60,7 +166,7 @@ func outputJSON(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error
 
 func outputTable(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error {
 	if result.TotalScanned > 0 {
-		fmt.Printf("\nScanned %d packages\n\n", result.TotalScanned)
+		fmt.Printf("\nScanned %s packages\n\n", ui.FormatCount(result.TotalScanned))
 	} else {
 		fmt.Println()
 	}
@@ -70,6 +176,15 @@ func outputTable(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error
 		return nil
 	}
 
+	printFindings(vulns)
+
+	fmt.Println("\nRun 'gx update -i' to update vulnerable packages")
+
+	return nil
+}
+
+// printFindings prints vulnerabilities grouped by severity, followed by the counts
+func printFindings(vulns []*vulndb.Vulnerability) {
 	bySeverity := make(map[string][]*vulndb.Vulnerability)
 	for _, v := range vulns {
 		severity := strings.ToUpper(v.Severity)
@@ -88,8 +203,8 @@ func outputTable(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error
 		}
 
 		style := ui.SeverityStyle(sev)
-		fmt.Printf("\n%s (%d)\n", style.Render(sev), len(sevVulns))
-		fmt.Println(strings.Repeat("─", 80))
+		fmt.Printf("\n%s (%d)\n", style.Render(ui.SeveritySymbol(sev)+" "+sev), len(sevVulns))
+		fmt.Println(ui.Rule(80))
 
 		for _, v := range sevVulns {
 			fmt.Printf("\n%s - %s\n", style.Render(v.ID), v.Package)
@@ -105,18 +220,13 @@ func outputTable(vulns []*vulndb.Vulnerability, result *vulndb.ScanResult) error
 	}
 
 	fmt.Printf("\n")
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Printf("\nFound %d vulnerabilities:\n", len(vulns))
+	fmt.Println(ui.Rule(80))
+	fmt.Printf("\nFound %s vulnerabilities:\n", ui.FormatCount(len(vulns)))
 
 	for _, sev := range severities {
 		if count, exists := bySeverity[sev]; exists && len(count) > 0 {
 			style := ui.SeverityStyle(sev)
-			fmt.Printf("  %s: %d\n", style.Render(sev), len(count))
+			fmt.Printf("  %s: %s\n", style.Render(ui.SeveritySymbol(sev)+" "+sev), ui.FormatCount(len(count)))
 		}
 	}
-
-	fmt.Println("\nRun 'gx update -i' to update vulnerable packages")
-
-	return nil
 }
-
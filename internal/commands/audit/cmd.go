@@ -3,14 +3,22 @@ package audit
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/omarshaarawi/gx/internal/image"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagSeverity string
-	flagJSON     bool
+	flagSeverity  string
+	flagJSON      bool
+	flagPlatform  string
+	flagPorcelain bool
+	flagFormat    string
 )
 
 // NewCommand creates the audit command
@@ -31,36 +39,195 @@ Examples:
   gx audit --json
 
   # Save report to file
-  gx audit --json > report.json`,
+  gx audit --json > report.json
+
+  # CycloneDX vulnerability disclosure report, to ship with the SBOM
+  gx audit --format=cyclonedx-vdr > vdr.cdx.json
+
+Inside a go.work workspace every member module is scanned separately.
+Set GOWORK=off to scan only ./go.mod.
+
+--porcelain prints tab-separated records for scripts on stdout, in the
+versioned format gx outdated --porcelain uses, and everything else on
+stderr. The first record is "version 1 audit", then one per vulnerability:
+
+  vuln <member> <id> <module> <installed> <fixed> <severity> <url>
+       <description>
+
+member is the workspace module scanned, empty outside a workspace, and
+fixed is empty when no fixed version is known.
+
+--format cyclonedx-vdr prints a CycloneDX 1.5 vulnerability disclosure
+report: the module as the subject, every module go.mod requires as a
+component with its purl and the SHA-256 digest of its go.sum hash, and each
+vulnerability found, with its severity, advisory, fixed version and the
+component it affects. --severity narrows the vulnerabilities listed. The
+report covers ./go.mod only, ignoring go.work. --format json is the same
+as --json.
+
+Use 'gx audit image' to scan the Go binaries in a container image, and
+'gx audit sbom' to check an SBOM against go.mod and go.sum.`,
 		RunE: runAudit,
 	}
 
-	cmd.Flags().StringVar(&flagSeverity, "severity", "", "Filter by severity (comma-separated: critical,high,medium,low)")
-	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output results as JSON")
+	cmd.PersistentFlags().StringVar(&flagSeverity, "severity", "", "Filter by severity (comma-separated: critical,high,medium,low)")
+	cmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "Output results as JSON")
+
+	cmd.Flags().BoolVar(&flagPorcelain, "porcelain", false, "Print stable tab-separated records for scripts")
+	cmd.Flags().StringVar(&flagFormat, "format", FormatTable, "Output format (table, json, cyclonedx-vdr)")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(Formats, cobra.ShellCompDirectiveNoFileComp))
+
+	cmd.AddCommand(newImageCommand())
+	cmd.AddCommand(newSBOMCommand())
 
 	return cmd
 }
 
 func runAudit(cmd *cobra.Command, args []string) error {
-	modPath := "go.mod"
-	if _, err := os.Stat(modPath); os.IsNotExist(err) {
-		return fmt.Errorf("go.mod not found in current directory")
+	modPath := modflag.Path(cmd)
+	workPath, inWorkspace := workspace.Detect(filepath.Dir(modPath))
+	if _, err := os.Stat(modPath); os.IsNotExist(err) && !inWorkspace {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if flagPorcelain && flagJSON {
+		return fmt.Errorf("--porcelain and --json can't be combined")
+	}
+
+	if !slices.Contains(Formats, flagFormat) {
+		return fmt.Errorf("invalid --format %q (want %s)", flagFormat, strings.Join(Formats, ", "))
+	}
+	if cmd.Flags().Changed("format") && (flagPorcelain || flagJSON) {
+		return fmt.Errorf("--format can't be combined with --porcelain or --json")
 	}
 
-	var severities []string
-	if flagSeverity != "" {
-		severities = strings.Split(flagSeverity, ",")
-		for i, s := range severities {
-			severities[i] = strings.ToUpper(strings.TrimSpace(s))
+	// The report describes a single module
+	if flagFormat == FormatVDR {
+		if _, err := os.Stat(modPath); os.IsNotExist(err) {
+			return fmt.Errorf("--format cyclonedx-vdr needs a go.mod, and none was found %s", modflag.Where(modPath))
 		}
+		workPath = ""
 	}
 
 	opts := Options{
-		Severity: severities,
-		JSON:     flagJSON,
-		ModPath:  modPath,
+		Severity:  parseSeverities(flagSeverity),
+		JSON:      flagJSON || flagFormat == FormatJSON,
+		Format:    flagFormat,
+		Version:   cmd.Root().Version,
+		ModPath:   modPath,
+		Workspace: workPath,
+		Porcelain: flagPorcelain,
 	}
 
 	return Run(cmd.Context(), opts)
 }
 
+// parseSeverities splits the --severity flag into upper-case severity names
+func parseSeverities(flag string) []string {
+	if flag == "" {
+		return nil
+	}
+	severities := strings.Split(flag, ",")
+	for i, s := range severities {
+		severities[i] = strings.ToUpper(strings.TrimSpace(s))
+	}
+	return severities
+}
+
+func newImageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image <image|Dockerfile|archive|binary>...",
+		Short: "Scan the Go binaries in a container image for vulnerabilities",
+		Long: `Find the Go binaries in a container image, read the module versions
+compiled into each one, and scan them against the Go vulnerability
+database, so the audit covers what is actually deployed rather than the
+repository.
+
+Each target is one of:
+  an image reference    pulled from its registry (ghcr.io/org/app:v1.2)
+  a Dockerfile          every external base image in its FROM lines
+  a docker save archive image.tar
+  a Go binary           scanned directly
+
+Registries are accessed anonymously; set GX_REGISTRY_USERNAME and
+GX_REGISTRY_PASSWORD for private images.
+
+Examples:
+  # Scan a published image
+  gx audit image ghcr.io/org/app:v1.2.0
+
+  # Scan the base images of a Dockerfile
+  gx audit image Dockerfile
+
+  # Scan a local image without a registry
+  docker save app:dev -o app.tar && gx audit image app.tar
+
+  # Pick the platform of a multi-platform image
+  gx audit image golang:1.22 --platform linux/arm64 --severity high,critical`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runImage,
+	}
+
+	cmd.Flags().StringVar(&flagPlatform, "platform", image.DefaultPlatform, "Platform to pull from multi-platform images (os/arch[/variant])")
+
+	return cmd
+}
+
+func runImage(cmd *cobra.Command, args []string) error {
+	opts := ImageOptions{
+		Targets:  args,
+		Platform: flagPlatform,
+		Severity: parseSeverities(flagSeverity),
+		JSON:     flagJSON,
+	}
+
+	return RunImage(cmd.Context(), opts)
+}
+
+func newSBOMCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom <file>",
+		Short: "Check that an SBOM matches go.mod and go.sum",
+		Long: `Check an externally produced SBOM against the module: every module
+go.mod requires must be listed at the required version, with the hash
+go.sum records, and nothing else may be listed. Any drift is reported and
+the command exits non-zero, for compliance audits where the SBOM is a
+deliverable.
+
+CycloneDX and SPDX SBOMs in JSON are read. Go modules are found by their
+pkg:golang package URLs; SHA-256 hashes are compared with go.sum's h1
+hashes, which is what cyclonedx-gomod and syft record. Hashes are only
+compared when both the SBOM and go.sum have one. Replaced modules, the
+main module and the standard library are left out.
+
+Examples:
+  # Check the SBOM shipped with a release
+  gx audit sbom dist/sbom.cdx.json
+
+  # Drift as JSON, for the audit trail
+  gx audit sbom sbom.spdx.json --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSBOM,
+	}
+
+	return cmd
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	// Drift is already listed; the returned error only sets the exit code
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	opts := SBOMOptions{
+		Path:    args[0],
+		ModPath: modPath,
+		JSON:    flagJSON,
+	}
+
+	return RunSBOM(cmd.Context(), opts)
+}
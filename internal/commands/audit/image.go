@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"context"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/image"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// ImageOptions configures the image audit
+type ImageOptions struct {
+	Targets  []string
+	Platform string
+	Severity []string
+	JSON     bool
+}
+
+// binaryVulns holds the scan result for one binary
+type binaryVulns struct {
+	Image           string                  `json:"image"`
+	Path            string                  `json:"path"`
+	GoVersion       string                  `json:"go_version"`
+	Main            string                  `json:"main_module,omitempty"`
+	Modules         int                     `json:"modules"`
+	TotalVulns      int                     `json:"total_vulnerabilities"`
+	Vulnerabilities []*vulndb.Vulnerability `json:"vulnerabilities"`
+}
+
+// source is an image, archive or binary to scan
+type source struct {
+	name string
+	open func() ([]image.Layer, error) // nil for a binary on disk
+}
+
+// RunImage executes the image audit
+func RunImage(ctx context.Context, opts ImageOptions) error {
+
+	scanner, err := vulndb.NewScanner()
+	if err != nil {
+		return fmt.Errorf("creating scanner: %w", err)
+	}
+
+	sources, err := resolveTargets(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "gx-image-*")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var results []binaryVulns
+	total, affected, binaries := 0, 0, 0
+
+	for i, src := range sources {
+		var found []image.Binary
+		if src.open == nil {
+			info, err := buildinfo.ReadFile(src.name)
+			if err != nil {
+				return fmt.Errorf("reading build info of %s: %w", src.name, err)
+			}
+			found = []image.Binary{{Path: src.name, File: src.name, Info: info}}
+		} else {
+			layerDir := filepath.Join(dir, fmt.Sprint(i))
+			if err := os.Mkdir(layerDir, 0o700); err != nil {
+				return fmt.Errorf("creating temp dir: %w", err)
+			}
+			if found, err = extractWithSpinner(src.name, src.open, layerDir); err != nil {
+				return fmt.Errorf("reading %s: %w", src.name, err)
+			}
+		}
+
+		if !opts.JSON {
+			fmt.Printf("\n%s %s\n", ui.HeaderStyle.Render("🐳 "+src.name), ui.UpToDateStyle.Render(fmt.Sprintf("(%d Go binaries)", len(found))))
+		}
+
+		for _, bin := range found {
+			result, err := scanBinaryWithSpinner(ctx, scanner, bin.File)
+			if err != nil {
+				return fmt.Errorf("scanning %s in %s: %w", bin.Path, src.name, err)
+			}
+
+			modules := bin.Modules()
+			vulns := result.Vulnerabilities
+			if len(opts.Severity) > 0 {
+				vulns = vulndb.FilterBySeverity(vulns, opts.Severity)
+			}
+			for _, v := range vulns {
+				v.Installed = modules[v.Package]
+			}
+
+			results = append(results, binaryVulns{
+				Image:           src.name,
+				Path:            bin.Path,
+				GoVersion:       bin.Info.GoVersion,
+				Main:            bin.Info.Main.Path,
+				Modules:         len(modules),
+				TotalVulns:      len(vulns),
+				Vulnerabilities: vulns,
+			})
+
+			binaries++
+			total += len(vulns)
+			if len(vulns) > 0 {
+				affected++
+			}
+
+			if !opts.JSON {
+				outputBinary(bin, len(modules), vulns)
+			}
+		}
+	}
+
+	if opts.JSON {
+		output := map[string]any{
+			"total_vulnerabilities": total,
+			"binaries":              results,
+		}
+
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("\n%s %s vulnerabilities in %s of %s Go binaries\n",
+		ui.SummaryStyle.Render("📊 Image summary:"), ui.FormatCount(total),
+		ui.FormatCount(affected), ui.FormatCount(binaries))
+
+	return nil
+}
+
+// resolveTargets turns the command arguments into images, archives and binaries
+func resolveTargets(ctx context.Context, opts ImageOptions) ([]source, error) {
+	client := image.NewClient()
+
+	pull := func(name string) (source, error) {
+		ref, err := image.ParseReference(name)
+		if err != nil {
+			return source{}, err
+		}
+		return source{name: ref.String(), open: func() ([]image.Layer, error) {
+			return client.Pull(ctx, ref, opts.Platform)
+		}}, nil
+	}
+
+	var sources []source
+	for _, target := range opts.Targets {
+		if _, err := os.Stat(target); err != nil {
+			src, err := pull(target)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, src)
+			continue
+		}
+
+		if _, err := buildinfo.ReadFile(target); err == nil {
+			sources = append(sources, source{name: target})
+			continue
+		}
+
+		if layers, err := image.FromArchive(target); err == nil {
+			sources = append(sources, source{name: target, open: func() ([]image.Layer, error) {
+				return layers, nil
+			}})
+			continue
+		}
+
+		data, err := os.ReadFile(target)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", target, err)
+		}
+		images, unresolved := image.BaseImages(data)
+		for _, name := range unresolved {
+			ui.Error("⚠️  Warning: skipping %s in %s: unresolved build argument\n", name, target)
+		}
+		if len(images) == 0 && len(unresolved) == 0 {
+			return nil, fmt.Errorf("%s is not a Go binary, docker save archive or Dockerfile", target)
+		}
+		for _, name := range images {
+			src, err := pull(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", target, err)
+			}
+			sources = append(sources, src)
+		}
+	}
+	return sources, nil
+}
+
+func outputBinary(bin image.Binary, modules int, vulns []*vulndb.Vulnerability) {
+	main := bin.Info.Main.Path
+	if main == "" {
+		main = bin.Info.Path
+	}
+	fmt.Printf("\n%s  %s\n", ui.HeaderStyle.Render(bin.Path), ui.UpToDateStyle.Render(fmt.Sprintf("%s, %s, %d modules", main, bin.Info.GoVersion, modules)))
+
+	if len(vulns) == 0 {
+		fmt.Println("✓ No vulnerabilities found!")
+		return
+	}
+
+	printFindings(vulns)
+	fmt.Println("\nRebuild the image with the fixed versions to resolve these")
+}
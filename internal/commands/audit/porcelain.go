@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// writeVulnRecords writes a porcelain record per vulnerability with
+// --porcelain:
+//
+//	vuln <member> <id> <module> <installed> <fixed> <severity> <url> <description>
+//
+// member is the workspace module scanned, empty outside a workspace. fixed is
+// empty when no fixed version is known.
+func writeVulnRecords(opts Options, member string, vulns []*vulndb.Vulnerability) {
+	for _, v := range vulns {
+		fixed := v.Fixed
+		if fixed == "unknown" {
+			fixed = ""
+		}
+		severity := strings.ToUpper(v.Severity)
+		if severity == "" {
+			severity = "UNKNOWN"
+		}
+		opts.porcelain.Record("vuln", member, v.ID, v.Package, v.Installed, fixed, severity, v.URL, v.Description)
+	}
+}
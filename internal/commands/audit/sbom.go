@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/sbom"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// SBOMOptions configures the SBOM cross-check
+type SBOMOptions struct {
+	Path    string // the SBOM to check
+	ModPath string
+	JSON    bool
+}
+
+// driftLabels describe each kind of drift in the text output
+var driftLabels = map[string]string{
+	sbom.DriftVersion: "version differs",
+	sbom.DriftMissing: "missing from the SBOM",
+	sbom.DriftExtra:   "not required in go.mod",
+	sbom.DriftHash:    "hash differs from go.sum",
+}
+
+// RunSBOM checks that an SBOM lists the modules go.mod requires, at their
+// versions and with the hashes go.sum records
+func RunSBOM(ctx context.Context, opts SBOMOptions) error {
+
+	data, err := os.ReadFile(opts.Path)
+	if err != nil {
+		return fmt.Errorf("reading SBOM: %w", err)
+	}
+	doc, err := sbom.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.Path, err)
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+	sums, err := modfile.ParseSum(modfile.SumPath(opts.ModPath))
+	if err != nil {
+		return err
+	}
+
+	// What an SBOM tool records for a replaced module varies, so replaced
+	// modules and their replacements are left out of the comparison. The
+	// main module and the standard library aren't requirements.
+	ignore := []string{parser.ModulePath(), "stdlib"}
+	replaced := make(map[string]bool)
+	for _, r := range parser.Replacements() {
+		replaced[r.Path] = true
+		ignore = append(ignore, r.Path)
+		if !r.Local {
+			ignore = append(ignore, r.New.Path)
+		}
+	}
+
+	var modules []sbom.Module
+	for _, req := range parser.AllRequires() {
+		if replaced[req.Mod.Path] {
+			continue
+		}
+		modules = append(modules, sbom.Module{
+			Path:    req.Mod.Path,
+			Version: req.Mod.Version,
+			Hash:    sums.Hash(req.Mod.Path, req.Mod.Version),
+		})
+	}
+
+	drift := sbom.Compare(doc, modules, ignore)
+
+	if opts.JSON {
+		if err := outputSBOMJSON(opts.Path, doc, len(modules), drift); err != nil {
+			return err
+		}
+	} else {
+		outputSBOMText(opts.Path, doc, len(modules), drift)
+	}
+
+	if len(drift) > 0 {
+		return fmt.Errorf("%d differences between %s and go.mod", len(drift), opts.Path)
+	}
+	return nil
+}
+
+func outputSBOMJSON(path string, doc *sbom.Document, checked int, drift []sbom.Drift) error {
+	if drift == nil {
+		drift = []sbom.Drift{}
+	}
+
+	output := map[string]any{
+		"sbom":       path,
+		"format":     doc.Format,
+		"components": len(doc.Components),
+		"checked":    checked,
+		"matches":    len(drift) == 0,
+		"drift":      drift,
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func outputSBOMText(path string, doc *sbom.Document, checked int, drift []sbom.Drift) {
+	fmt.Printf("\n%s %s\n", ui.HeaderStyle.Render("📄 "+path), ui.UpToDateStyle.Render(fmt.Sprintf("(%s, %s Go modules)", doc.Format, ui.FormatCount(len(doc.Components)))))
+	fmt.Printf("\nChecked %s requirements of go.mod\n", ui.FormatCount(checked))
+
+	if len(drift) == 0 {
+		fmt.Println("\n✓ The SBOM matches go.mod and go.sum")
+		return
+	}
+
+	table := ui.NewTable("Module", "go.mod / go.sum", "SBOM", "Drift")
+	for _, d := range drift {
+		expected, found := d.Expected, d.Found
+		if d.Kind == sbom.DriftHash {
+			// Digests are too long for the table; --json has them in full
+			expected, found = shortDigest(expected), shortDigest(found)
+		}
+		table.AddRow(d.Module, orDash(expected), orDash(found), driftLabels[d.Kind])
+	}
+
+	fmt.Println()
+	fmt.Print(table.Render())
+	fmt.Printf("\n%s\n", ui.CriticalStyle.Render(fmt.Sprintf("✗ %s differences", ui.FormatCount(len(drift)))))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func shortDigest(digest string) string {
+	if len(digest) <= 12 {
+		return digest
+	}
+	return digest[:12] + "…"
+}
@@ -3,6 +3,7 @@ package audit
 import (
 	"context"
 
+	"github.com/omarshaarawi/gx/internal/image"
 	"github.com/omarshaarawi/gx/internal/ui"
 	"github.com/omarshaarawi/gx/internal/vulndb"
 )
@@ -12,3 +13,19 @@ func scanModuleWithSpinner(ctx context.Context, scanner *vulndb.Scanner, modPath
 		return scanner.ScanModule(ctx, modPath)
 	})
 }
+
+func scanBinaryWithSpinner(ctx context.Context, scanner *vulndb.Scanner, path string) (*vulndb.ScanResult, error) {
+	return ui.RunSimpleSpinner("Scanning "+path+"...", func() (*vulndb.ScanResult, error) {
+		return scanner.ScanBinary(ctx, path)
+	})
+}
+
+func extractWithSpinner(name string, open func() ([]image.Layer, error), dir string) ([]image.Binary, error) {
+	return ui.RunSimpleSpinner("Pulling "+name+"...", func() ([]image.Binary, error) {
+		layers, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return image.Extract(layers, dir)
+	})
+}
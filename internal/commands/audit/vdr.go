@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/sbom"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// Output formats of gx audit
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatVDR   = "cyclonedx-vdr"
+)
+
+// Formats lists the accepted --format values
+var Formats = []string{FormatTable, FormatJSON, FormatVDR}
+
+// outputVDR prints a CycloneDX vulnerability disclosure report of the
+// module: every module go.mod requires, with its go.sum hash, and the
+// vulnerabilities found in them
+func outputVDR(opts Options, vulns []*vulndb.Vulnerability) error {
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+	sums, err := modfile.ParseSum(modfile.SumPath(opts.ModPath))
+	if err != nil {
+		return err
+	}
+
+	var modules []sbom.Module
+	for _, req := range parser.AllRequires() {
+		modules = append(modules, sbom.Module{
+			Path:    req.Mod.Path,
+			Version: req.Mod.Version,
+			Hash:    sums.Hash(req.Mod.Path, req.Mod.Version),
+		})
+	}
+
+	main := sbom.Module{Path: parser.ModulePath()}
+	bom := sbom.NewVDR(main, modules, vulns, opts.Version, time.Now())
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
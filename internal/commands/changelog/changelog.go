@@ -0,0 +1,124 @@
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// Options configures the changelog command
+type Options struct {
+	Module  string
+	From    string
+	To      string
+	NoPager bool
+	ModPath string
+}
+
+// Run executes the changelog command
+func Run(ctx context.Context, opts Options) error {
+
+	repo, ok := github.ParseModulePath(opts.Module)
+	if !ok {
+		return fmt.Errorf("%s is not hosted on GitHub; changelogs are only available for github.com modules", opts.Module)
+	}
+
+	from := opts.From
+	if from == "" {
+		parser, err := modfile.NewParser(opts.ModPath)
+		if err != nil {
+			return fmt.Errorf("parsing go.mod: %w", err)
+		}
+		req := parser.FindRequire(opts.Module)
+		if req == nil {
+			return fmt.Errorf("%s is not required in go.mod (use --from to pick a version)", opts.Module)
+		}
+		from = req.Mod.Version
+	}
+
+	to := opts.To
+	if to == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		latest, err := ui.RunSimpleSpinner("Checking latest version...", func() (string, error) {
+			info, err := cfg.NewProxyClient().Latest(ctx, opts.Module)
+			if err != nil {
+				return "", err
+			}
+			return info.Version, nil
+		})
+		if err != nil {
+			return fmt.Errorf("fetching latest version: %w", err)
+		}
+		to = latest
+	}
+
+	if !semver.IsValid(from) || !semver.IsValid(to) {
+		return fmt.Errorf("invalid version range %s..%s", from, to)
+	}
+
+	if semver.Compare(from, to) >= 0 {
+		fmt.Printf("✓ %s is already at %s, nothing to show\n", opts.Module, from)
+		return nil
+	}
+
+	client := github.NewClient("")
+	releases, err := ui.RunSimpleSpinner("Fetching release notes...", func() ([]github.Release, error) {
+		return client.ListReleases(ctx, repo.Owner, repo.Name)
+	})
+	if err != nil {
+		return fmt.Errorf("fetching releases for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	selected := repo.ReleasesBetween(releases, from, to)
+
+	return ui.Page(render(opts.Module, repo, from, to, selected), opts.NoPager)
+}
+
+func render(module string, repo github.Repo, from, to string, releases []github.Release) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\n📜 %s %s → %s\n", ui.HeaderStyle.Render(module), from, to)
+
+	if len(releases) == 0 {
+		fmt.Fprintf(&b, "\nNo GitHub releases found in this range.\n")
+	}
+
+	for _, r := range releases {
+		title := r.TagName
+		if r.Name != "" && r.Name != r.TagName {
+			title += " — " + r.Name
+		}
+		if r.Prerelease {
+			title += " (pre-release)"
+		}
+
+		fmt.Fprintf(&b, "\n%s", ui.SummaryStyle.Render(title))
+		if !r.PublishedAt.IsZero() {
+			fmt.Fprintf(&b, "  %s", ui.UpToDateStyle.Render(ui.FormatReleaseTime(r.PublishedAt)))
+		}
+		b.WriteString("\n")
+		b.WriteString(ui.BorderStyle.Render(ui.Rule(60)))
+		b.WriteString("\n")
+
+		body := strings.TrimSpace(strings.ReplaceAll(r.Body, "\r\n", "\n"))
+		if body == "" {
+			body = "(no release notes)"
+		}
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\n💡 %s\n", ui.CTAStyle.Render("Full diff: "+repo.CompareURL(from, to)))
+
+	return b.String()
+}
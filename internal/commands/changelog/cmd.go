@@ -0,0 +1,62 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/completion"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFrom    string
+	flagTo      string
+	flagNoPager bool
+)
+
+// NewCommand creates the changelog command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog <module>",
+		Short: "Show release notes between the installed and latest version",
+		Long: `Fetch GitHub release notes for a dependency, covering every release
+after the version in go.mod up to the latest version on the proxy.
+
+Long output is shown in a pager ($GX_PAGER, $PAGER, or less).
+Set GITHUB_TOKEN to avoid API rate limits.
+
+Examples:
+  # Show what changed since the installed version
+  gx changelog github.com/spf13/cobra
+
+  # Show notes for a specific range
+  gx changelog github.com/spf13/cobra --from v1.7.0 --to v1.8.0`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.Modules(),
+		RunE:              runChangelog,
+	}
+
+	cmd.Flags().StringVar(&flagFrom, "from", "", "Starting version (default: version in go.mod)")
+	cmd.Flags().StringVar(&flagTo, "to", "", "Ending version (default: latest)")
+	cmd.Flags().BoolVar(&flagNoPager, "no-pager", false, "Print directly instead of using a pager")
+
+	return cmd
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) && flagFrom == "" {
+		return fmt.Errorf("go.mod not found %s (use --from to run without one)", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Module:  args[0],
+		From:    flagFrom,
+		To:      flagTo,
+		NoPager: flagNoPager,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,59 @@
+package compare
+
+import (
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDirect bool
+	flagJSON   bool
+)
+
+// NewCommand creates the compare command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <left> [right]",
+		Short: "Diff the dependencies of two modules",
+		Long: `Compare the requirements of two modules and show which versions diverge
+and which dependencies only one side has, e.g. when consolidating modules
+in a monorepo.
+
+Each side is a go.mod file, a directory containing one, or a
+<module>@<version> whose go.mod is fetched from the proxy (@latest resolves
+the newest release). With one argument, ./go.mod (or the one --mod names) is compared against it.
+
+Examples:
+  # Compare two modules in a monorepo
+  gx compare services/api services/worker
+
+  # Compare this module with a published version of another
+  gx compare github.com/spf13/cobra@latest
+
+  # Direct requirements only, as JSON
+  gx compare ./a/go.mod ./b/go.mod --direct --json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runCompare,
+	}
+
+	cmd.Flags().BoolVar(&flagDirect, "direct", false, "Only compare direct requirements")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	left, right := modflag.Path(cmd), args[0]
+	if len(args) == 2 {
+		left, right = args[0], args[1]
+	}
+
+	opts := Options{
+		Left:   left,
+		Right:  right,
+		Direct: flagDirect,
+		JSON:   flagJSON,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,176 @@
+package compare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/compare"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/modfile"
+)
+
+// Options configures the compare command
+type Options struct {
+	Left   string
+	Right  string
+	Direct bool
+	JSON   bool
+}
+
+// report is the JSON output of the compare command
+type report struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	compare.Result
+}
+
+// Run executes the compare command
+func Run(ctx context.Context, opts Options) error {
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	client := cfg.NewProxyClient()
+
+	leftName, left, err := load(ctx, opts.Left, client)
+	if err != nil {
+		return err
+	}
+	rightName, right, err := load(ctx, opts.Right, client)
+	if err != nil {
+		return err
+	}
+
+	if opts.Direct {
+		left, right = directOnly(left), directOnly(right)
+	}
+
+	result := compare.Diff(left, right)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(report{Left: leftName, Right: rightName, Result: result}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	render(leftName, rightName, result)
+	return nil
+}
+
+// load reads the requirements of one side of the comparison: a go.mod file,
+// a directory containing one, or a module@version fetched from the proxy
+func load(ctx context.Context, spec string, client *proxy.Client) (string, []*modfile.Require, error) {
+	if info, err := os.Stat(spec); err == nil {
+		path := spec
+		if info.IsDir() {
+			path = filepath.Join(spec, "go.mod")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		file, err := modfile.ParseLax(path, data, nil)
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return path, file.Require, nil
+	}
+
+	module, version, ok := strings.Cut(spec, "@")
+	if !ok || module == "" || version == "" {
+		return "", nil, fmt.Errorf("%s: no such go.mod file or directory, and not a <module>@<version>", spec)
+	}
+
+	file, err := ui.RunSimpleSpinner(fmt.Sprintf("Fetching %s go.mod...", spec), func() (*modfile.File, error) {
+		if version == "latest" {
+			info, err := client.Latest(ctx, module)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %s: %w", spec, err)
+			}
+			version = info.Version
+		}
+
+		data, err := client.GetModFile(ctx, module, version)
+		if err != nil {
+			return nil, fmt.Errorf("fetching go.mod for %s@%s: %w", module, version, err)
+		}
+		file, err := modfile.ParseLax(module+"@"+version+"/go.mod", data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("parsing go.mod for %s@%s: %w", module, version, err)
+		}
+		return file, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return module + "@" + version, file.Require, nil
+}
+
+func directOnly(reqs []*modfile.Require) []*modfile.Require {
+	var direct []*modfile.Require
+	for _, req := range reqs {
+		if !req.Indirect {
+			direct = append(direct, req)
+		}
+	}
+	return direct
+}
+
+func render(leftName, rightName string, r compare.Result) {
+	fmt.Printf("Left:  %s\nRight: %s\n", leftName, rightName)
+
+	if r.Identical() {
+		fmt.Printf("\n✓ Both require the same %s modules at the same versions\n", ui.FormatCount(r.Shared))
+		return
+	}
+
+	if len(r.Diverged) > 0 {
+		fmt.Printf("\n%s (%d)\n\n", ui.HeaderStyle.Render("Different versions"), len(r.Diverged))
+		table := ui.NewTable("Module", "Left", "Right", "Newer", "Update")
+		for _, d := range r.Diverged {
+			update := d.UpdateType
+			if s := ui.UpdateSymbol(d.UpdateType); s != "" {
+				update = s + " " + update
+			}
+			table.AddRow(d.Path, d.Left, d.Right, d.Newer, update)
+		}
+		fmt.Print(table.RenderStyled(func(rowIdx, colIdx int, cell string) lipgloss.Style {
+			if colIdx == 4 {
+				return ui.FormatVersionUpdate(r.Diverged[rowIdx].UpdateType)
+			}
+			return ui.CellStyle
+		}))
+	}
+
+	printOnly := func(name string, entries []compare.Entry) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Printf("\n%s (%d)\n\n", ui.HeaderStyle.Render("Only in "+name), len(entries))
+		for _, e := range entries {
+			suffix := ""
+			if e.Indirect {
+				suffix = " (indirect)"
+			}
+			fmt.Printf("  %s %s%s\n", e.Path, e.Version, suffix)
+		}
+	}
+	printOnly(leftName, r.OnlyLeft)
+	printOnly(rightName, r.OnlyRight)
+
+	fmt.Printf("\n%s\n", ui.SummaryStyle.Render(fmt.Sprintf("📊 %s shared, %s different, %s only left, %s only right",
+		ui.FormatCount(r.Shared), ui.FormatCount(len(r.Diverged)),
+		ui.FormatCount(len(r.OnlyLeft)), ui.FormatCount(len(r.OnlyRight)))))
+}
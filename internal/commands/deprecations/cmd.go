@@ -0,0 +1,58 @@
+package deprecations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAll  bool
+	flagJSON bool
+)
+
+// NewCommand creates the deprecations command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deprecations",
+		Short: "Find deprecated modules and retracted versions",
+		Long: `Check each dependency's latest go.mod for a "Deprecated:" module comment
+and retract directives, and report dependencies that are deprecated or
+pinned to a retracted version. Dependencies gx knows to have moved to
+another module or to be abandoned are reported as superseded, with the
+module to use instead; replacements in the config add to that list.
+
+Examples:
+  # Check direct dependencies
+  gx deprecations
+
+  # Include indirect dependencies
+  gx deprecations --all
+
+  # Machine-readable output
+  gx deprecations --json`,
+		RunE: runDeprecations,
+	}
+
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Include indirect dependencies")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runDeprecations(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		All:     flagAll,
+		JSON:    flagJSON,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
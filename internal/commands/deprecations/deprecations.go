@@ -0,0 +1,164 @@
+package deprecations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// Options configures the deprecations command
+type Options struct {
+	All     bool
+	JSON    bool
+	ModPath string
+}
+
+// Finding describes a deprecated module or retracted version in use
+type Finding struct {
+	Module     string `json:"module"`
+	Version    string `json:"version"`
+	Latest     string `json:"latest"`
+	Direct     bool   `json:"direct"`
+	Deprecated string `json:"deprecated,omitempty"`
+	Retracted  string `json:"retracted,omitempty"`
+	Rationale  string `json:"rationale,omitempty"`
+
+	// Replacement is the module to move to, from gx's list of moved modules
+	// or the deprecation notice; Advice is what the list says about it
+	Replacement string `json:"replacement,omitempty"`
+	Advice      string `json:"advice,omitempty"`
+}
+
+// Run executes the deprecations command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	requires := parser.DirectRequires()
+	if opts.All {
+		requires = parser.AllRequires()
+	}
+
+	var targets []*xmodfile.Require
+	for _, req := range requires {
+		if cfg.IgnoreRuleFor(req.Mod.Path) != nil {
+			continue
+		}
+		targets = append(targets, req)
+	}
+
+	findings, err := checkWithSpinner(ctx, targets, cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements()))
+	if err != nil {
+		return fmt.Errorf("checking modules: %w", err)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Module < findings[j].Module
+	})
+
+	if opts.JSON {
+		return outputJSON(findings)
+	}
+
+	return outputText(findings, len(targets))
+}
+
+func outputJSON(findings []*Finding) error {
+	if findings == nil {
+		findings = []*Finding{}
+	}
+
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func outputText(findings []*Finding, checked int) error {
+	fmt.Printf("\nChecked %s modules\n", ui.FormatCount(checked))
+
+	if len(findings) == 0 {
+		fmt.Println("\n✓ No deprecated modules or retracted versions found!")
+		return nil
+	}
+
+	var deprecated, retracted, replaced []*Finding
+	for _, f := range findings {
+		if f.Retracted != "" {
+			retracted = append(retracted, f)
+		}
+		if f.Deprecated != "" {
+			deprecated = append(deprecated, f)
+		} else if f.Replacement != "" || f.Advice != "" {
+			replaced = append(replaced, f)
+		}
+	}
+
+	if len(retracted) > 0 {
+		fmt.Printf("\n%s (%d)\n", ui.HighStyle.Render("RETRACTED"), len(retracted))
+		for _, f := range retracted {
+			fmt.Printf("\n%s %s\n", ui.HighStyle.Render(f.Module), f.Version)
+			fmt.Printf("  Retracted: %s\n", f.Retracted)
+			if f.Rationale != "" {
+				fmt.Printf("  Reason:    %s\n", f.Rationale)
+			}
+			if f.Latest != "" && f.Latest != f.Version {
+				fmt.Printf("  💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("go get %s@%s", f.Module, f.Latest)))
+			}
+		}
+	}
+
+	if len(deprecated) > 0 {
+		fmt.Printf("\n%s (%d)\n", ui.MediumStyle.Render("DEPRECATED"), len(deprecated))
+		for _, f := range deprecated {
+			fmt.Printf("\n%s %s\n", ui.MediumStyle.Render(f.Module), f.Version)
+			fmt.Printf("  %s\n", f.Deprecated)
+			printSuggestion(f)
+		}
+	}
+
+	if len(replaced) > 0 {
+		fmt.Printf("\n%s (%d)\n", ui.MediumStyle.Render("SUPERSEDED"), len(replaced))
+		for _, f := range replaced {
+			fmt.Printf("\n%s %s\n", ui.MediumStyle.Render(f.Module), f.Version)
+			printSuggestion(f)
+		}
+	}
+
+	fmt.Printf("\nFound %s retracted, %s deprecated and %s superseded\n",
+		ui.FormatCount(len(retracted)), ui.FormatCount(len(deprecated)), ui.FormatCount(len(replaced)))
+
+	return nil
+}
+
+// printSuggestion says what to move a deprecated or superseded module to
+func printSuggestion(f *Finding) {
+	if f.Advice != "" {
+		fmt.Printf("  %s\n", f.Advice)
+	}
+	switch {
+	case f.Replacement == "" && f.Advice == "":
+		fmt.Printf("  💡 %s\n", ui.CTAStyle.Render("Plan a migration to a maintained replacement"))
+		return
+	case f.Replacement == "":
+		return
+	}
+	fmt.Printf("  💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Use %s instead: gx migrate %s", f.Replacement, f.Module)))
+}
@@ -0,0 +1,104 @@
+package deprecations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/migration"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+func checkWithSpinner(ctx context.Context, requires []*xmodfile.Require, client *proxy.Client) ([]*Finding, error) {
+	if len(requires) == 0 {
+		return nil, nil
+	}
+
+	return ui.RunWithSpinner(ui.SpinnerTask[[]*Finding]{
+		Message: "Checking for deprecations...",
+		Phase:   "check-deprecations",
+		Total:   len(requires),
+		Run: func(progress chan<- int) ([]*Finding, error) {
+			var findings []*Finding
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for _, req := range requires {
+				wg.Add(1)
+				go func(r *xmodfile.Require) {
+					defer wg.Done()
+
+					finding := checkModule(ctx, r, client)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if finding != nil {
+						findings = append(findings, finding)
+					}
+					loaded++
+					progress <- loaded
+				}(req)
+			}
+
+			wg.Wait()
+			return findings, nil
+		},
+	})
+}
+
+// checkModule reads the deprecation and retraction notices from the module's
+// latest go.mod, and looks the module up in the list of moved modules.
+// Modules that can't be fetched are only looked up.
+func checkModule(ctx context.Context, req *xmodfile.Require, client *proxy.Client) *Finding {
+	status, latest := moduleStatus(ctx, req.Mod.Path, client)
+	if status == nil {
+		status = &modfile.ModuleStatus{}
+	}
+
+	finding := &Finding{
+		Module:     req.Mod.Path,
+		Version:    req.Mod.Version,
+		Latest:     latest,
+		Direct:     !req.Indirect,
+		Deprecated: status.Deprecated,
+	}
+
+	if r, ok := status.Retracted(req.Mod.Version); ok {
+		finding.Retracted = r.String()
+		finding.Rationale = r.Rationale
+	}
+	if m, ok := migration.Suggest(req.Mod.Path, status.Deprecated); ok {
+		finding.Replacement, finding.Advice = m.New, m.Note
+	}
+
+	if finding.Deprecated == "" && finding.Retracted == "" && finding.Replacement == "" && finding.Advice == "" {
+		return nil
+	}
+	return finding
+}
+
+// moduleStatus reads the module's latest go.mod, returning nil when it can't
+// be fetched, and the latest version, "" when it is unknown
+func moduleStatus(ctx context.Context, modulePath string, client *proxy.Client) (*modfile.ModuleStatus, string) {
+	latest, err := client.Latest(ctx, modulePath)
+	if err != nil {
+		ui.Debug("fetching latest %s: %v", modulePath, err)
+		return nil, ""
+	}
+
+	data, err := client.GetModFile(ctx, modulePath, latest.Version)
+	if err != nil {
+		ui.Debug("fetching go.mod for %s@%s: %v", modulePath, latest.Version, err)
+		return nil, latest.Version
+	}
+
+	status, err := modfile.ParseModuleStatus(data)
+	if err != nil {
+		ui.Debug("parsing go.mod for %s@%s: %v", modulePath, latest.Version, err)
+		return nil, latest.Version
+	}
+	return status, latest.Version
+}
@@ -0,0 +1,98 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/diag"
+	"github.com/omarshaarawi/gx/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// writeBundle collects the diagnostics into a redacted tarball and returns its path
+func writeBundle(ctx context.Context, opts Options, checks []check) (string, error) {
+	name := "gx-debug-" + time.Now().Format("20060102T150405")
+	path := opts.Output
+	if path == "" {
+		path = name + ".tar.gz"
+	}
+
+	files := []diag.File{
+		{Name: "versions.txt", Data: versions(ctx, opts.Version)},
+		{Name: "checks.txt", Data: formatChecks(checks)},
+		{Name: "config.yaml", Data: effectiveConfig()},
+		{Name: "goenv.txt", Data: commandOutput(ctx, "go", "env")},
+	}
+	for _, log := range []string{diag.CommandLog, diag.HTTPLog} {
+		data, err := diag.ReadLog(log)
+		if err != nil {
+			data = []byte(fmt.Sprintf("reading %s: %v\n", log, err))
+		}
+		files = append(files, diag.File{Name: log, Data: data})
+	}
+
+	var buf bytes.Buffer
+	if err := diag.WriteBundle(&buf, name, files); err != nil {
+		return "", err
+	}
+	if err := fsutil.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func versions(ctx context.Context, version string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "gx: %s\n", version)
+	fmt.Fprintf(&buf, "gx built with: %s\n", runtime.Version())
+	fmt.Fprintf(&buf, "platform: %s/%s\n\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&buf, "$ go version\n%s\n", commandOutput(ctx, "go", "version"))
+	fmt.Fprintf(&buf, "$ govulncheck -version\n%s", commandOutput(ctx, "govulncheck", "-version"))
+	return buf.Bytes()
+}
+
+func formatChecks(checks []check) []byte {
+	var buf bytes.Buffer
+	for _, c := range checks {
+		fmt.Fprintf(&buf, "%-4s  %-12s  %s\n", c.Status, c.Name, c.Detail)
+	}
+	return buf.Bytes()
+}
+
+// effectiveConfig returns the merged config, with the files it was read from
+func effectiveConfig() []byte {
+	var buf bytes.Buffer
+	for _, path := range configPaths() {
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&buf, "# read from %s\n", path)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(&buf, "# error loading config: %v\n", err)
+		return buf.Bytes()
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		fmt.Fprintf(&buf, "# error encoding config: %v\n", err)
+		return buf.Bytes()
+	}
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// commandOutput runs a command and returns its output, or the error in its place
+func commandOutput(ctx context.Context, name string, args ...string) []byte {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return append(out, fmt.Sprintf("error: %v\n", err)...)
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package doctor
+
+import (
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBundle bool
+	flagOutput string
+)
+
+// NewCommand creates the doctor command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the gx setup and collect debug information",
+		Long: `Check that the tools and services gx relies on are available: the go
+command, govulncheck, the config files, and the module proxy.
+
+With --bundle, also write a tarball for bug reports containing the versions
+of gx, go and govulncheck, the effective config, go env, the recent command
+trail, and a trace of recent HTTP requests (method, URL, status and timing
+only). Credentials, tokens and the home directory are redacted, but review
+the bundle before sharing it.
+
+Examples:
+  # Check the setup
+  gx doctor
+
+  # Write gx-debug-<time>.tar.gz for a bug report
+  gx doctor --bundle
+
+  # Choose the bundle path
+  gx doctor --bundle -o debug.tar.gz`,
+		RunE: runDoctor,
+	}
+
+	cmd.Flags().BoolVar(&flagBundle, "bundle", false, "Write a redacted debug bundle for bug reports")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Bundle path (default gx-debug-<time>.tar.gz)")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Check results are already printed; the returned error only sets the exit code
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	opts := Options{
+		Bundle:  flagBundle,
+		Output:  flagOutput,
+		Version: cmd.Root().Version,
+		ModPath: modflag.Path(cmd),
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,206 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/diag"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Check statuses
+const (
+	statusOK   = "ok"
+	statusWarn = "warn"
+	statusFail = "fail"
+)
+
+// Options configures the doctor command
+type Options struct {
+	Bundle  bool
+	Output  string
+	Version string
+	ModPath string
+}
+
+// check is the result of one doctor check
+type check struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// Run executes the doctor command
+func Run(ctx context.Context, opts Options) error {
+
+	checks, err := runChecksWithSpinner(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ui.HeaderStyle.Render("🩺 gx doctor"))
+	fmt.Println()
+	printChecks(checks)
+
+	if opts.Bundle {
+		path, err := writeBundle(ctx, opts, checks)
+		if err != nil {
+			return fmt.Errorf("writing debug bundle: %w", err)
+		}
+		fmt.Printf("\n✓ Wrote debug bundle to %s\n", path)
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Review it, then attach it to an issue at https://github.com/omarshaarawi/gx/issues"))
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func runChecks(ctx context.Context, opts Options) []check {
+	checks := []check{
+		{Name: "gx", Status: statusOK, Detail: fmt.Sprintf("%s (%s/%s, %s)", opts.Version, runtime.GOOS, runtime.GOARCH, runtime.Version())},
+		checkGo(ctx),
+		checkGovulncheck(ctx),
+	}
+
+	cfg, cfgCheck := checkConfig()
+	checks = append(checks, cfgCheck, checkProxy(ctx, cfg), checkGoMod(opts.ModPath))
+	return checks
+}
+
+func checkGo(ctx context.Context) check {
+	out, err := toolVersion(ctx, "go", "version")
+	if err != nil {
+		return check{Name: "go", Status: statusFail, Detail: err.Error()}
+	}
+	return check{Name: "go", Status: statusOK, Detail: strings.TrimPrefix(out, "go version ")}
+}
+
+func checkGovulncheck(ctx context.Context) check {
+	out, err := toolVersion(ctx, "govulncheck", "-version")
+	if err != nil {
+		return check{Name: "govulncheck", Status: statusWarn, Detail: "not found; gx audit needs it (go install golang.org/x/vuln/cmd/govulncheck@latest)"}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if v, ok := strings.CutPrefix(line, "Scanner: "); ok {
+			return check{Name: "govulncheck", Status: statusOK, Detail: v}
+		}
+	}
+	return check{Name: "govulncheck", Status: statusOK, Detail: firstLine(out)}
+}
+
+func checkConfig() (*config.Config, check) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Default(), check{Name: "config", Status: statusFail, Detail: err.Error()}
+	}
+
+	var found []string
+	for _, path := range configPaths() {
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		return cfg, check{Name: "config", Status: statusOK, Detail: "defaults (no config file)"}
+	}
+	return cfg, check{Name: "config", Status: statusOK, Detail: strings.Join(found, ", ")}
+}
+
+// configPaths lists the config files config.Load reads, in order
+func configPaths() []string {
+	paths := []string{config.GlobalPath(), filepath.Join(os.Getenv("HOME"), ".gx.yaml")}
+	if abs, err := filepath.Abs(config.ProjectPath()); err == nil && abs != paths[1] {
+		paths = append(paths, abs)
+	}
+	return paths
+}
+
+func checkProxy(ctx context.Context, cfg *config.Config) check {
+	url := strings.TrimSuffix(cfg.ProxyURL, "/") + "/golang.org/x/mod/@v/list"
+	proxyURL := diag.Redact(cfg.ProxyURL)
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return check{Name: "proxy", Status: statusFail, Detail: diag.Redact(err.Error())}
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return check{Name: "proxy", Status: statusFail, Detail: diag.Redact(fmt.Sprintf("%s unreachable: %v", cfg.ProxyURL, err))}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return check{Name: "proxy", Status: statusFail, Detail: fmt.Sprintf("%s returned %s", proxyURL, resp.Status)}
+	}
+	return check{Name: "proxy", Status: statusOK, Detail: fmt.Sprintf("%s (%s)", proxyURL, time.Since(start).Round(time.Millisecond))}
+}
+
+func checkGoMod(modPath string) check {
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return check{Name: "go.mod", Status: statusWarn, Detail: "not found " + modflag.Where(modPath)}
+	}
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		return check{Name: "go.mod", Status: statusFail, Detail: err.Error()}
+	}
+	return check{Name: "go.mod", Status: statusOK, Detail: fmt.Sprintf("%s (%d requirements)", parser.ModulePath(), len(parser.AllRequires()))}
+}
+
+// toolVersion runs a version command and returns its trimmed output
+func toolVersion(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s not found in PATH", name)
+	}
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func printChecks(checks []check) {
+	width := 0
+	for _, c := range checks {
+		width = max(width, len(c.Name))
+	}
+
+	for _, c := range checks {
+		var icon string
+		switch c.Status {
+		case statusOK:
+			icon = ui.PatchStyle.Render("✓")
+		case statusWarn:
+			icon = ui.MinorStyle.Render("⚠")
+		default:
+			icon = ui.MajorStyle.Render("✗")
+		}
+		fmt.Printf("%s %-*s  %s\n", icon, width, c.Name, c.Detail)
+	}
+}
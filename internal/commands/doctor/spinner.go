@@ -0,0 +1,13 @@
+package doctor
+
+import (
+	"context"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+func runChecksWithSpinner(ctx context.Context, opts Options) ([]check, error) {
+	return ui.RunSimpleSpinner("Running checks...", func() ([]check, error) {
+		return runChecks(ctx, opts), nil
+	})
+}
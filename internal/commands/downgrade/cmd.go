@@ -0,0 +1,69 @@
+package downgrade
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/completion"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNoTidy bool
+	flagDryRun bool
+	flagForce  bool
+)
+
+// NewCommand creates the downgrade command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "downgrade <module>@<version>",
+		Short: "Move a dependency back to an older version",
+		Long: `Set a dependency to a specific older version, run go mod tidy, and
+report how the rest of the module graph changed.
+
+The version must exist on the module proxy.
+
+Examples:
+  # Downgrade a module after a bad update
+  gx downgrade github.com/spf13/cobra@v1.8.0
+
+  # Show the change to go.mod without making it
+  gx downgrade github.com/spf13/cobra@v1.8.0 --dry-run`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ModuleVersions(),
+		RunE:              runDowngrade,
+	}
+
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip running go mod tidy")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runDowngrade(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	module, version, ok := strings.Cut(args[0], "@")
+	if !ok || module == "" || version == "" {
+		return fmt.Errorf("expected <module>@<version>, got %q", args[0])
+	}
+
+	opts := Options{
+		Module:  module,
+		Version: version,
+		NoTidy:  flagNoTidy,
+		DryRun:  flagDryRun,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
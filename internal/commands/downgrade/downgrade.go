@@ -0,0 +1,173 @@
+package downgrade
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// Options configures the downgrade command
+type Options struct {
+	Module  string
+	Version string
+	NoTidy  bool
+	DryRun  bool
+	Force   bool
+	ModPath string
+}
+
+// Run executes the downgrade command
+func Run(ctx context.Context, opts Options) error {
+
+	if !semver.IsValid(opts.Version) {
+		return fmt.Errorf("invalid version %q", opts.Version)
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	req := parser.FindRequire(opts.Module)
+	if req == nil {
+		return fmt.Errorf("%s is not required in go.mod", opts.Module)
+	}
+
+	current := req.Mod.Version
+	if semver.Compare(opts.Version, current) >= 0 {
+		return fmt.Errorf("%s is not older than the current version %s (use gx update instead)", opts.Version, current)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	proxyClient := cfg.NewProxyClient()
+
+	if _, err := ui.RunSimpleSpinner("Checking version on proxy...", func() (struct{}, error) {
+		_, err := proxyClient.Info(ctx, opts.Module, opts.Version)
+		return struct{}{}, err
+	}); err != nil {
+		return fmt.Errorf("%s@%s not found on proxy: %w", opts.Module, opts.Version, err)
+	}
+
+	if opts.DryRun {
+		return preview(opts, parser)
+	}
+
+	before := modfile.CloneRequires(parser.AllRequires())
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Begin("downgrade", opts.Module+"@"+opts.Version)
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.Backup(); err != nil {
+		tx.Discard()
+		return fmt.Errorf("creating backup: %w", err)
+	}
+
+	if err := writer.UpdateRequire(opts.Module, opts.Version); err != nil {
+		tx.Discard()
+		return fmt.Errorf("updating %s: %w", opts.Module, err)
+	}
+
+	if err := writer.SafeWrite(); err != nil {
+		tx.Discard()
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record downgrade history: %v\n", err)
+	}
+
+	fmt.Printf("\n✓ %s: %s → %s\n", opts.Module, current, opts.Version)
+
+	workDir := filepath.Dir(opts.ModPath)
+
+	if !opts.NoTidy {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := tx.Record(history.EffectTidy); err != nil {
+			ui.Error("⚠️  Warning: could not record downgrade history: %v\n", err)
+		}
+		if err := gocmd.Run(ctx, workDir, "mod", "tidy"); err != nil {
+			if restoreErr := writer.RestoreBackup(); restoreErr != nil {
+				return fmt.Errorf("go mod tidy failed and restore failed: %w (original error: %v)", restoreErr, err)
+			}
+			tx.Discard()
+			return fmt.Errorf("go mod tidy failed (go.mod restored): %w", err)
+		}
+		fmt.Println("✓ go.mod and go.sum updated")
+	}
+
+	if err := writer.CleanupBackup(); err != nil {
+		return fmt.Errorf("cleanup backup: %w", err)
+	}
+
+	after, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("re-reading go.mod: %w", err)
+	}
+
+	if final := after.FindRequire(opts.Module); final != nil && final.Mod.Version != opts.Version {
+		fmt.Printf("\n⚠️  %s resolved to %s: another dependency requires at least that version\n",
+			opts.Module, final.Mod.Version)
+	}
+
+	changes := modfile.DiffRequires(before, after.AllRequires())
+	renderChanges(changes, opts.Module)
+
+	return nil
+}
+
+// renderChanges prints the requirement changes other than the downgraded module itself
+func renderChanges(changes []modfile.RequireChange, skip string) {
+	table := ui.NewTable("Module", "Change", "From", "To")
+	for _, c := range changes {
+		if c.Path == skip {
+			continue
+		}
+		table.AddRow(c.Path, c.Kind(), orDash(c.From), orDash(c.To))
+	}
+
+	if len(table.Rows) == 0 {
+		fmt.Println("\nNo other requirements changed")
+		return
+	}
+
+	fmt.Printf("\n🔗 %d other requirement(s) changed\n\n", len(table.Rows))
+	fmt.Println(table.Render())
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// preview prints the diff of go.mod with the older version and the go mod
+// tidy that would follow
+func preview(opts Options, parser *modfile.Parser) error {
+	return dryrun.PrintModEdit(opts.ModPath, parser, !opts.NoTidy, func(writer *modfile.Writer) error {
+		if err := writer.UpdateRequire(opts.Module, opts.Version); err != nil {
+			return fmt.Errorf("updating %s: %w", opts.Module, err)
+		}
+		return nil
+	})
+}
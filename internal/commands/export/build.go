@@ -0,0 +1,176 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/bazel"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/nix"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// source is a requirement resolved through any replace directive
+type source struct {
+	Path    string // path as required in go.mod
+	Replace string // replacing module path, empty if not replaced
+	Version string // version of the module actually used
+}
+
+// fetchPath is the module path the source is downloaded from
+func (s source) fetchPath() string {
+	if s.Replace != "" {
+		return s.Replace
+	}
+	return s.Path
+}
+
+// resolveSources applies replace directives to every requirement. Modules
+// replaced with local directories can't be fetched and are returned separately.
+func resolveSources(parser *modfile.Parser) (sources []source, local []string) {
+	for _, req := range parser.AllRequires() {
+		s := source{Path: req.Mod.Path, Version: req.Mod.Version}
+		if rep := parser.FindReplace(req.Mod.Path, req.Mod.Version); rep != nil {
+			if rep.New.Version == "" {
+				local = append(local, req.Mod.Path)
+				continue
+			}
+			s.Replace = rep.New.Path
+			s.Version = rep.New.Version
+		}
+		sources = append(sources, s)
+	}
+	return sources, local
+}
+
+// warnSkipped reports modules left out of an export on stderr
+func warnSkipped(reason string, modules []string) {
+	if len(modules) == 0 {
+		return
+	}
+	sort.Strings(modules)
+	ui.Error("⚠️  Warning: skipped %d module(s) %s: %s\n", len(modules), reason, strings.Join(modules, ", "))
+}
+
+// RunBazel executes the bazel export
+func RunBazel(ctx context.Context, opts Options) error {
+
+	if opts.Format != "module" && opts.Format != "workspace" {
+		return fmt.Errorf("unsupported style %q (use module or workspace)", opts.Format)
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	sums, err := modfile.ParseSum(modfile.SumPath(opts.ModPath))
+	if err != nil {
+		return fmt.Errorf("parsing go.sum: %w", err)
+	}
+
+	sources, local := resolveSources(parser)
+	warnSkipped("replaced with local directories", local)
+
+	var repos []bazel.Repository
+	var missing []string
+	for _, s := range sources {
+		sum := sums.Hash(s.fetchPath(), s.Version)
+		if sum == "" {
+			missing = append(missing, s.Path)
+			continue
+		}
+		repos = append(repos, bazel.Repository{Path: s.Path, Version: s.Version, Sum: sum, Replace: s.Replace})
+	}
+	warnSkipped("without a go.sum hash (run 'go mod download' first)", missing)
+
+	return writeOutput(opts.Output, func(w io.Writer) error {
+		if opts.Format == "workspace" {
+			return bazel.WriteWorkspace(w, repos, opts.Macro)
+		}
+		return bazel.WriteModule(w, repos)
+	})
+}
+
+// RunNix executes the gomod2nix export
+func RunNix(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	sources, local := resolveSources(parser)
+	warnSkipped("replaced with local directories", local)
+
+	modules, failed, err := hashWithSpinner(ctx, sources, cfg.NewProxyClient())
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not hash %d module(s): %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return writeOutput(opts.Output, func(w io.Writer) error {
+		return nix.WriteGomod2nix(w, modules)
+	})
+}
+
+// hashWithSpinner downloads every module zip and computes its NAR hash
+func hashWithSpinner(ctx context.Context, sources []source, client *proxy.Client) ([]nix.Module, []string, error) {
+	type result struct {
+		modules []nix.Module
+		failed  []string
+	}
+
+	res, err := ui.RunWithSpinner(ui.SpinnerTask[result]{
+		Message: "Downloading module zips...",
+		Phase:   "download-zips",
+		Total:   len(sources),
+		Run: func(progress chan<- int) (result, error) {
+			var r result
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for _, s := range sources {
+				wg.Add(1)
+				go func(s source) {
+					defer wg.Done()
+
+					data, err := client.GetZip(ctx, s.fetchPath(), s.Version)
+					var hash string
+					if err == nil {
+						hash, err = nix.HashModuleZip(data, s.fetchPath(), s.Version)
+					}
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						r.failed = append(r.failed, fmt.Sprintf("%s@%s: %v", s.fetchPath(), s.Version, err))
+					} else {
+						r.modules = append(r.modules, nix.Module{Path: s.Path, Version: s.Version, Hash: hash, Replaced: s.Replace})
+					}
+					loaded++
+					progress <- loaded
+				}(s)
+			}
+
+			wg.Wait()
+			sort.Strings(r.failed)
+			return r, nil
+		},
+	})
+	return res.modules, res.failed, err
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFormat string
+	flagOutput string
+	flagStyle  string
+	flagMacro  string
+)
+
+// NewCommand creates the export command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export dependency data for external systems",
+		Long: `Export dependency data in formats suitable for external systems.
+
+Examples:
+  # Export a dependency inventory as CSV
+  gx export inventory
+
+  # Export as JSON to a file
+  gx export inventory --format json -o inventory.json
+
+  # go_deps.module declarations for MODULE.bazel
+  gx export bazel
+
+  # gomod2nix.toml for Nix builds
+  gx export nix -o gomod2nix.toml`,
+	}
+
+	cmd.AddCommand(newInventoryCommand())
+	cmd.AddCommand(newBazelCommand())
+	cmd.AddCommand(newNixCommand())
+
+	return cmd
+}
+
+func newInventoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export a normalized dependency inventory",
+		Long: `Export one record per dependency with stable field names for
+ingestion into CMDB and asset management systems.
+
+Fields: name, version, license, repo_url, hash, relationship, owner_team
+
+Owner teams are resolved from the "owners" map in the gx config file:
+
+  owners:
+    github.com/acme/*: platform-team`,
+		RunE: runInventory,
+	}
+
+	cmd.Flags().StringVar(&flagFormat, "format", "csv", "Output format (csv, json)")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Write to file instead of stdout")
+
+	return cmd
+}
+
+func runInventory(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Format:  flagFormat,
+		Output:  flagOutput,
+		ModPath: modPath,
+	}
+
+	return RunInventory(cmd.Context(), opts)
+}
+
+func newBazelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bazel",
+		Short: "Export dependencies as Gazelle go_deps or go_repository rules",
+		Long: `Export every requirement with its go.sum hash for Bazel builds that use
+Gazelle, without running gazelle update-repos.
+
+Styles:
+  module     go_deps.module calls and a use_repo list for MODULE.bazel (bzlmod)
+  workspace  a .bzl macro of go_repository rules for WORKSPACE builds
+
+Modules replaced with local directories are skipped.`,
+		RunE: runBazel,
+	}
+
+	cmd.Flags().StringVar(&flagStyle, "style", "module", "Output style (module, workspace)")
+	cmd.Flags().StringVar(&flagMacro, "macro", "go_dependencies", "Macro name for the workspace style")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Write to file instead of stdout")
+
+	return cmd
+}
+
+func runBazel(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Format:  flagStyle,
+		Macro:   flagMacro,
+		Output:  flagOutput,
+		ModPath: modPath,
+	}
+
+	return RunBazel(cmd.Context(), opts)
+}
+
+func newNixCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nix",
+		Short: "Export dependencies as a gomod2nix.toml file",
+		Long: `Write a gomod2nix.toml (schema 3) for building the module with
+gomod2nix's buildGoApplication, without running gomod2nix.
+
+The hashes are Nix NAR hashes of each module's source, so every module zip
+is downloaded from the proxy. Modules replaced with local directories are
+skipped.`,
+		RunE: runNix,
+	}
+
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Write to file instead of stdout")
+
+	return cmd
+}
+
+func runNix(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Output:  flagOutput,
+		ModPath: modPath,
+	}
+
+	return RunNix(cmd.Context(), opts)
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/inventory"
+	"github.com/omarshaarawi/gx/internal/modfile"
+)
+
+// Options configures the export command
+type Options struct {
+	Format  string
+	Macro   string
+	Output  string
+	ModPath string
+}
+
+// RunInventory executes the inventory export
+func RunInventory(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	sums, err := modfile.ParseSum(modfile.SumPath(opts.ModPath))
+	if err != nil {
+		return fmt.Errorf("parsing go.sum: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	records := inventory.Build(parser, sums, inventory.Options{
+		Owner: cfg.OwnerFor,
+	})
+
+	return writeOutput(opts.Output, func(w io.Writer) error {
+		switch opts.Format {
+		case "csv":
+			return inventory.WriteCSV(w, records)
+		case "json":
+			return inventory.WriteJSON(w, records)
+		default:
+			return fmt.Errorf("unsupported format %q (use csv or json)", opts.Format)
+		}
+	})
+}
+
+// writeOutput runs fn against stdout or the named file
+func writeOutput(path string, fn func(w io.Writer) error) error {
+	if path == "" {
+		return fn(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,71 @@
+package fmtcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCheck  bool
+	flagDryRun bool
+	flagForce  bool
+)
+
+// NewCommand creates the fmt command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Organize and format go.mod",
+		Long: `Rewrite go.mod in a consistent layout: direct requirements in one
+sorted require block followed by indirect requirements in another, sorted
+exclude, replace and retract blocks, and normalized spacing. Comments on
+require lines move with them.
+
+With --check, go.mod is left untouched and the command exits non-zero if it
+is not already formatted, so it can gate CI. --dry-run prints the changes as
+a diff instead of writing them.
+
+Examples:
+  # Format go.mod in place
+  gx fmt
+
+  # Fail if go.mod needs formatting
+  gx fmt --check
+
+  # Show what formatting would change
+  gx fmt --dry-run`,
+		RunE: runFmt,
+	}
+
+	cmd.Flags().BoolVar(&flagCheck, "check", false, "Exit non-zero if go.mod is not formatted instead of rewriting it")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if flagCheck {
+		// The check result is already printed; the returned error only sets the exit code
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+
+	opts := Options{
+		Check:   flagCheck,
+		DryRun:  flagDryRun,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,68 @@
+package fmtcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the fmt command
+type Options struct {
+	Check   bool
+	DryRun  bool
+	Force   bool
+	ModPath string
+}
+
+// Run executes the fmt command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.Organize(); err != nil {
+		return fmt.Errorf("organizing go.mod: %w", err)
+	}
+
+	formatted, err := writer.Format()
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(formatted, parser.Data()) {
+		fmt.Println("✓ go.mod is already formatted")
+		return nil
+	}
+
+	if opts.DryRun {
+		var plan dryrun.Plan
+		plan.Change(opts.ModPath, parser.Data(), formatted)
+		plan.Print(os.Stdout)
+		if !opts.Check {
+			return nil
+		}
+	}
+	if opts.Check {
+		fmt.Printf("✗ %s is not formatted\n", opts.ModPath)
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx fmt' to fix it"))
+		return fmt.Errorf("%s is not formatted", opts.ModPath)
+	}
+
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	if err := writer.CleanupBackup(); err != nil {
+		return fmt.Errorf("cleanup backup: %w", err)
+	}
+
+	fmt.Printf("✓ Formatted %s\n", opts.ModPath)
+	return nil
+}
@@ -0,0 +1,41 @@
+package initcmd
+
+import (
+	"fmt"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// runPlainForm asks for each config value in turn instead of the form, for
+// accessible mode. It returns nil if cancelled.
+func runPlainForm(values Values) (*Values, error) {
+	fmt.Println("gx config: press Enter to keep the default")
+
+	fields := newFormModel(values).fields
+	answers := make([]string, len(fields))
+	for i, field := range fields {
+		for {
+			answer, err := ui.PromptValue(field.label, field.input.Value())
+			if err != nil {
+				return nil, err
+			}
+			if err := field.validate(answer); err != nil {
+				fmt.Printf("%s: %v\n", field.label, err)
+				continue
+			}
+			answers[i] = answer
+			break
+		}
+	}
+
+	ok, err := ui.PromptConfirm("Write the config?")
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &Values{
+		ProxyURL:      answers[0],
+		Timeout:       answers[1],
+		CacheTTL:      answers[2],
+		MaxConcurrent: answers[3],
+	}, nil
+}
@@ -0,0 +1,66 @@
+package initcmd
+
+import (
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagGlobal      bool
+	flagForce       bool
+	flagInteractive bool
+	flagDryRun      bool
+)
+
+// NewCommand creates the init command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a gx config file with commented defaults",
+		Long: `Create a gx config file with commented defaults.
+
+By default a project-local .gx.yaml is written in the current directory,
+or in the module directory --mod names.
+Use --global to write ~/.config/gx/config.yaml instead.
+
+Examples:
+  # Scaffold a project-local .gx.yaml
+  gx init
+
+  # Scaffold the user config
+  gx init --global
+
+  # Fill in values with a small form
+  gx init -i
+
+  # Show the file without writing it
+  gx init --dry-run`,
+		RunE: runInit,
+	}
+
+	cmd.Flags().BoolVar(&flagGlobal, "global", false, "Write the user config instead of a project-local .gx.yaml")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite an existing config file")
+	cmd.Flags().BoolVarP(&flagInteractive, "interactive", "i", false, "Fill in values interactively")
+	dryrun.AddFlag(cmd, &flagDryRun)
+
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if flagInteractive {
+		if err := ui.RequireInteractive("gx init -i", "drop -i to write the defaults and edit the file"); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	opts := Options{
+		Global:      flagGlobal,
+		Force:       flagForce,
+		Interactive: flagInteractive,
+		DryRun:      flagDryRun,
+	}
+
+	return Run(cmd.Context(), opts)
+}
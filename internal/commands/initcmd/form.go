@@ -0,0 +1,184 @@
+package initcmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+var (
+	formTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	formLabelStyle = lipgloss.NewStyle().Width(18).Foreground(lipgloss.Color("252"))
+	formHelpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	formErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+type formField struct {
+	label    string
+	input    textinput.Model
+	validate func(string) error
+}
+
+type formModel struct {
+	fields    []formField
+	focus     int
+	err       error
+	quitting  bool
+	confirmed bool
+}
+
+func newFormModel(values Values) formModel {
+	newInput := func(value string) textinput.Model {
+		ti := textinput.New()
+		ti.SetValue(value)
+		ti.CharLimit = 256
+		ti.Width = 40
+		return ti
+	}
+
+	fields := []formField{
+		{label: "Proxy URL", input: newInput(values.ProxyURL), validate: validateURL},
+		{label: "Timeout", input: newInput(values.Timeout), validate: validateDuration},
+		{label: "Cache TTL", input: newInput(values.CacheTTL), validate: validateDuration},
+		{label: "Max concurrent", input: newInput(values.MaxConcurrent), validate: validatePositiveInt},
+	}
+	fields[0].input.Focus()
+
+	return formModel{fields: fields}
+}
+
+func (m formModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c", "esc"))):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("shift+tab", "up"))):
+			return m.moveFocus(-1), nil
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("tab", "down"))):
+			return m.moveFocus(1), nil
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+			field := m.fields[m.focus]
+			if err := field.validate(field.input.Value()); err != nil {
+				m.err = fmt.Errorf("%s: %w", field.label, err)
+				return m, nil
+			}
+			m.err = nil
+			if m.focus == len(m.fields)-1 {
+				m.confirmed = true
+				return m, tea.Quit
+			}
+			return m.moveFocus(1), nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.fields[m.focus].input, cmd = m.fields[m.focus].input.Update(msg)
+	return m, cmd
+}
+
+func (m formModel) moveFocus(delta int) formModel {
+	m.fields[m.focus].input.Blur()
+	m.focus = (m.focus + delta + len(m.fields)) % len(m.fields)
+	m.fields[m.focus].input.Focus()
+	return m
+}
+
+func (m formModel) View() string {
+	if m.quitting || m.confirmed {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + formTitleStyle.Render("⚙️  gx config") + "\n\n")
+
+	for _, field := range m.fields {
+		b.WriteString(formLabelStyle.Render(field.label))
+		b.WriteString(field.input.View())
+		b.WriteString("\n")
+	}
+
+	if m.err != nil {
+		b.WriteString("\n" + formErrorStyle.Render(m.err.Error()) + "\n")
+	}
+
+	b.WriteString("\n" + formHelpStyle.Render("Tab/↓ next • Shift+Tab/↑ previous • Enter confirm • Esc cancel") + "\n")
+	return b.String()
+}
+
+func (m formModel) values() Values {
+	return Values{
+		ProxyURL:      strings.TrimSpace(m.fields[0].input.Value()),
+		Timeout:       strings.TrimSpace(m.fields[1].input.Value()),
+		CacheTTL:      strings.TrimSpace(m.fields[2].input.Value()),
+		MaxConcurrent: strings.TrimSpace(m.fields[3].input.Value()),
+	}
+}
+
+// RunForm lets the user edit config values. It returns nil if cancelled.
+func RunForm(values Values) (*Values, error) {
+	if ui.Accessible() {
+		return runPlainForm(values)
+	}
+
+	p := tea.NewProgram(newFormModel(values))
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running form: %w", err)
+	}
+
+	result := finalModel.(formModel)
+	if !result.confirmed {
+		return nil, nil
+	}
+
+	edited := result.values()
+	for i, field := range result.fields {
+		if err := field.validate(field.input.Value()); err != nil {
+			return nil, fmt.Errorf("%s: %w", result.fields[i].label, err)
+		}
+	}
+
+	return &edited, nil
+}
+
+func validateURL(s string) error {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+		return fmt.Errorf("must start with http:// or https://")
+	}
+	return nil
+}
+
+func validateDuration(s string) error {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("invalid duration (e.g. 30s, 5m)")
+	}
+	if d <= 0 {
+		return fmt.Errorf("must be positive")
+	}
+	return nil
+}
+
+func validatePositiveInt(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return fmt.Errorf("must be a positive number")
+	}
+	return nil
+}
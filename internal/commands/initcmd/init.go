@@ -0,0 +1,233 @@
+package initcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/fsutil"
+)
+
+// Options configures the init command
+type Options struct {
+	Global      bool
+	Force       bool
+	Interactive bool
+	DryRun      bool
+}
+
+// Values holds the settings written into the scaffolded config
+type Values struct {
+	ProxyURL      string
+	Timeout       string
+	CacheTTL      string
+	MaxConcurrent string
+}
+
+var configTemplate = template.Must(template.New("config").Parse(`# gx configuration
+# Project-local .gx.yaml settings override ~/.config/gx/config.yaml.
+# Environment variables (GX_PROXY, GX_TIMEOUT, GX_CACHE_TTL, GX_MAX_CONCURRENT,
+# GX_LOOKUP_TIMEOUT, GX_COMMAND_TIMEOUT, GX_MAX_CACHE_AGE, GX_THEME, GX_MOUSE,
+# GX_ACCESSIBLE, GOVULNDB) override both.
+
+# Go module proxy used for version lookups
+proxy_url: {{.ProxyURL}}
+
+# HTTP timeout for proxy requests
+timeout: {{.Timeout}}
+
+# Deadline for a single module lookup. After 3 failed lookups in a row, other
+# modules on the same host are skipped for a minute and reported as unresolved.
+# lookup_timeout: 10s
+
+# How long proxy responses are cached in memory
+cache_ttl: {{.CacheTTL}}
+
+# Maximum number of concurrent proxy requests
+max_concurrent: {{.MaxConcurrent}}
+
+# Let gx outdated reuse latest versions found by earlier runs up to this age,
+# instead of asking the proxy again. --max-cache-age and --refresh override it.
+# max_cache_age: 2h
+
+# Vulnerability database govulncheck reads: a URL, such as an internal mirror,
+# or a directory written by 'gx vulndb mirror'. Defaults to https://vuln.go.dev.
+# vulndb: https://vulndb.example.com
+
+# How long a command may run before it is stopped (no limit by default), with
+# per-command overrides. --timeout overrides both.
+# command_timeout: 10m
+# command_timeouts:
+#   audit: 20m
+#   export nix: 30m
+
+# default_verbose: false
+# default_quiet: false
+
+# Date format: iso, us, eu, uk, long, rfc3339, or a Go time layout
+# date_format: iso
+
+# Locale for number formatting (defaults to LC_ALL/LC_NUMERIC/LANG)
+# locale: en-US
+
+# Colors for update types and severities: default, or colorblind for a
+# palette that stays distinct with red-green color blindness. Symbols
+# (▲ major, ● minor, · patch; !! critical, ! high, ~ medium) are always shown.
+# theme: default
+
+# Keys for the actions of the interactive views, replacing their defaults
+# (list them with ? in a view). Keys use bubbletea's names, with space for
+# the space bar; ctrl+c always quits.
+# keys:
+#   toggle: [x, space]
+#   quit: [q, esc]
+
+# Scroll the interactive views with the wheel and toggle rows by clicking.
+# false leaves the mouse to the terminal, for selecting text.
+# mouse: true
+
+# Plain lines of text and prompts instead of spinners and full-screen views,
+# and ASCII tables and trees, for screen readers. --accessible turns it on
+# for a run.
+# accessible: false
+
+# Modules left out of 'gx outdated' reports. Entries are module paths or
+# patterns (k8s.io/*), optionally with a reason shown in verbose mode.
+# ignore:
+#   - golang.org/x/exp
+#   - module: k8s.io/*
+#     reason: pinned to the cluster version
+
+# Modules 'gx update' never changes
+# pinned:
+#   - github.com/legacy/lib
+
+# Requirements 'gx update' works on by default: all, direct or indirect.
+# --direct-only and --indirect-only override it for a run.
+# update_scope: direct
+
+# Versions 'gx update' may move a module to; 'gx outdated' shows the newest
+# allowed version next to the latest. Bounds: <, <=, >, >=, =, !=, ~ and ^.
+# constraints:
+#   golang.org/x/tools: <0.20.0
+#   github.com/acme/*: ">=1.4, <2"
+
+# Module families that must share a version; 'gx update' moves them together
+# to the newest version all of them have published. The Kubernetes staging
+# modules (k8s.io/api, k8s.io/client-go, ...) are a family by default.
+# families:
+#   otel: [go.opentelemetry.io/otel, go.opentelemetry.io/otel/trace, go.opentelemetry.io/otel/sdk]
+
+# Modules that moved, adding to or overriding the list gx ships for 'gx migrate'
+# and the suggestions of 'gx stats' and 'gx deprecations'. An entry without
+# new marks an abandoned module with only advice to give.
+# replacements:
+#   github.com/acme/oldlib:
+#     new: github.com/acme/lib/v2
+#     note: Client options moved to lib.Options.
+
+# Owning team per module pattern, used by 'gx export inventory'
+# owners:
+#   github.com/acme/*: platform-team
+
+# Rules enforced by 'gx policy' (exits non-zero on violations)
+# policy:
+#   banned:
+#     - module: github.com/pkg/errors
+#       reason: use the standard library errors package
+#   max_age_days: 365
+#   disallowed_licenses: [GPL-3.0, AGPL-3.0]
+#   min_versions:
+#     golang.org/x/crypto: v0.31.0
+
+# GitHub API used by 'gx update --pr'. GITHUB_TOKEN or GH_TOKEN take
+# precedence over token, and GITHUB_API_URL over api_url, which is only
+# needed for GitHub Enterprise.
+# github:
+#   token: ghp_...
+#   api_url: https://github.example.com/api/v3
+
+# GitLab instance 'gx update --pr' opens merge requests on when origin is
+# there. GITLAB_TOKEN takes precedence over token, and CI_SERVER_URL over
+# url, which is only needed for a self-hosted instance.
+# gitlab:
+#   token: glpat-...
+#   url: https://gitlab.example.com
+`))
+
+// Run executes the init command
+func Run(ctx context.Context, opts Options) error {
+	path := config.ProjectPath()
+	if opts.Global {
+		path = config.GlobalPath()
+	}
+
+	if _, err := os.Stat(path); err == nil && !opts.Force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	values := defaultValues()
+
+	if opts.Interactive {
+		edited, err := RunForm(values)
+		if err != nil {
+			return fmt.Errorf("interactive form: %w", err)
+		}
+		if edited == nil {
+			fmt.Println("Init cancelled")
+			return nil
+		}
+		values = *edited
+	}
+
+	data, err := Render(values)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		var plan dryrun.Plan
+		plan.Change(path, existing, data)
+		plan.Print(os.Stdout)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	if err := fsutil.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	fmt.Printf("✓ Created %s\n", path)
+	return nil
+}
+
+// Render produces the commented config file for the given values
+func Render(values Values) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := configTemplate.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("rendering config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func defaultValues() Values {
+	cfg := config.Default()
+	return Values{
+		ProxyURL:      cfg.ProxyURL,
+		Timeout:       cfg.Timeout.String(),
+		CacheTTL:      cfg.CacheTTL.String(),
+		MaxConcurrent: fmt.Sprint(cfg.MaxConcurrent),
+	}
+}
@@ -0,0 +1,50 @@
+package lsplite
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the lsp-lite command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp-lite",
+		Short: "Answer dependency queries as JSON over stdio for editor integrations",
+		Long: `Run a long-lived server that reads newline-delimited JSON requests on
+stdin and writes one JSON response per line on stdout.
+
+Requests:
+  {"id": 1, "method": "latest", "params": {"module": "github.com/spf13/cobra"}}
+  {"id": 2, "method": "outdated", "params": {"module": "github.com/spf13/cobra"}}
+  {"id": 3, "method": "outdated"}
+  {"id": 4, "method": "vulnerabilities", "params": {"module": "golang.org/x/net"}}
+  {"id": 5, "method": "shutdown"}
+
+Responses:
+  {"id": 1, "result": {...}}
+  {"id": 1, "error": {"message": "..."}}
+
+go.mod is re-read for every request, so edits are picked up without a
+restart. Vulnerability scans are cached until go.mod changes.`,
+		Args: cobra.NoArgs,
+		RunE: runServer,
+	}
+
+	return cmd
+}
+
+func runServer(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,249 @@
+package lsplite
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// Options configures the lsp-lite server
+type Options struct {
+	ModPath string
+}
+
+// Request is a single query read from stdin
+type Request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Module string `json:"module"`
+	} `json:"params"`
+}
+
+// Response is written to stdout for every request
+type Response struct {
+	ID     json.RawMessage `json:"id"`
+	Result any             `json:"result,omitempty"`
+	Error  *ResponseError  `json:"error,omitempty"`
+}
+
+// ResponseError describes a failed request
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// LatestResult answers the latest method
+type LatestResult struct {
+	Module  string    `json:"module"`
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+}
+
+// OutdatedResult describes the update status of one requirement
+type OutdatedResult struct {
+	Module     string `json:"module"`
+	Current    string `json:"current"`
+	Latest     string `json:"latest"`
+	UpdateType string `json:"updateType"`
+	Direct     bool   `json:"direct"`
+	Line       int    `json:"line"`
+	Error      string `json:"error,omitempty"`
+}
+
+type server struct {
+	modPath string
+	proxy   *proxy.Client
+
+	out   *json.Encoder
+	outMu sync.Mutex
+
+	vulnMu    sync.Mutex
+	vulnHash  [32]byte
+	vulnCache []*vulndb.Vulnerability
+}
+
+// Run executes the lsp-lite server until stdin closes or shutdown is requested
+func Run(ctx context.Context, opts Options) error {
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	s := &server{
+		modPath: opts.ModPath,
+		proxy:   cfg.NewProxyClient(),
+		out:     json.NewEncoder(os.Stdout),
+	}
+
+	return s.serve(ctx, os.Stdin)
+}
+
+func (s *server) serve(ctx context.Context, in io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.reply(Response{Error: &ResponseError{Message: fmt.Sprintf("invalid request: %v", err)}})
+			continue
+		}
+
+		if req.Method == "shutdown" {
+			s.reply(Response{ID: req.ID, Result: "ok"})
+			return nil
+		}
+
+		wg.Add(1)
+		go func(req Request) {
+			defer wg.Done()
+			result, err := s.handle(ctx, req)
+			if err != nil {
+				s.reply(Response{ID: req.ID, Error: &ResponseError{Message: err.Error()}})
+				return
+			}
+			s.reply(Response{ID: req.ID, Result: result})
+		}(req)
+	}
+
+	return scanner.Err()
+}
+
+func (s *server) reply(resp Response) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	s.out.Encode(resp)
+}
+
+func (s *server) handle(ctx context.Context, req Request) (any, error) {
+	switch req.Method {
+	case "latest":
+		if req.Params.Module == "" {
+			return nil, fmt.Errorf("latest requires params.module")
+		}
+		info, err := s.proxy.Latest(ctx, req.Params.Module)
+		if err != nil {
+			return nil, err
+		}
+		return LatestResult{Module: req.Params.Module, Version: info.Version, Time: info.Time}, nil
+
+	case "outdated":
+		return s.outdated(ctx, req.Params.Module)
+
+	case "vulnerabilities":
+		return s.vulnerabilities(ctx, req.Params.Module)
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+}
+
+// outdated reports the status of one requirement, or all when module is empty
+func (s *server) outdated(ctx context.Context, module string) (any, error) {
+	parser, err := modfile.NewParser(s.modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.proxy.WithLocalModules(parser.LocalReplacements())
+
+	requires := parser.AllRequires()
+	if module != "" {
+		req := parser.FindRequire(module)
+		if req == nil {
+			return nil, fmt.Errorf("%s is not required in go.mod", module)
+		}
+		requires = []*xmodfile.Require{req}
+	}
+
+	results := make([]OutdatedResult, len(requires))
+	var wg sync.WaitGroup
+	for i, req := range requires {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := OutdatedResult{
+				Module:  req.Mod.Path,
+				Current: req.Mod.Version,
+				Direct:  !req.Indirect,
+			}
+			if req.Syntax != nil {
+				result.Line = req.Syntax.Start.Line
+			}
+
+			latest, err := s.proxy.Latest(ctx, req.Mod.Path)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Latest = latest.Version
+				result.UpdateType = versions.Classify(req.Mod.Version, latest.Version)
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	if module != "" {
+		return results[0], nil
+	}
+	return results, nil
+}
+
+// vulnerabilities scans the module once per go.mod revision and filters by module
+func (s *server) vulnerabilities(ctx context.Context, module string) (any, error) {
+	data, err := os.ReadFile(s.modPath)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+
+	s.vulnMu.Lock()
+	defer s.vulnMu.Unlock()
+
+	if s.vulnCache == nil || hash != s.vulnHash {
+		scanner, err := vulndb.NewScanner()
+		if err != nil {
+			return nil, err
+		}
+		result, err := scanner.ScanModule(ctx, s.modPath)
+		if err != nil {
+			return nil, err
+		}
+		s.vulnCache = result.Vulnerabilities
+		s.vulnHash = hash
+	}
+
+	vulns := []*vulndb.Vulnerability{}
+	for _, v := range s.vulnCache {
+		if module == "" || v.Package == module {
+			vulns = append(vulns, v)
+		}
+	}
+	return vulns, nil
+}
@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/completion"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDryRun bool
+	flagNoTidy bool
+	flagForce  bool
+)
+
+// NewCommand creates the migrate command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <old> [new[@version]]",
+		Short: "Move from a dependency to the module that replaced it",
+		Long: `Replace a dependency with the module it moved to: the requirement in
+go.mod moves to the new module path and every import of the old module in
+the module's .go files is rewritten, after a diff preview of both.
+
+Without a new module path, gx uses its curated list of moved modules, such
+as github.com/golang/protobuf → google.golang.org/protobuf or
+github.com/satori/go.uuid → github.com/gofrs/uuid, and otherwise the module
+the old one's "Deprecated:" comment names. Curated migrations also map
+packages that moved within the new module, such as ptypes/timestamp to
+types/known/timestamppb, and say what the rewrite leaves to do by hand.
+
+The new module is required at its latest version, or the one after @.
+Imports are the only thing touched in each file; code using APIs that
+changed still needs updating.
+
+Examples:
+  # Preview a curated migration
+  gx migrate github.com/golang/protobuf --dry-run
+
+  # Migrate to a given module and version
+  gx migrate github.com/satori/go.uuid github.com/gofrs/uuid@v4.4.0`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completion.Modules(),
+		RunE:              runMigrate,
+	}
+
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip running go mod tidy")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Old:     args[0],
+		DryRun:  flagDryRun,
+		NoTidy:  flagNoTidy,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+	if len(args) == 2 {
+		module, version, hasVersion := strings.Cut(args[1], "@")
+		if module == "" || (hasVersion && version == "") {
+			return fmt.Errorf("expected <new>[@<version>], got %q", args[1])
+		}
+		opts.New, opts.Version = module, version
+	}
+
+	// A module without a known replacement isn't a usage error
+	cmd.SilenceUsage = true
+
+	return Run(cmd.Context(), opts)
+}
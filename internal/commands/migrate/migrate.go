@@ -0,0 +1,245 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"github.com/omarshaarawi/gx/internal/migration"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// Options configures the migrate command
+type Options struct {
+	Old     string
+	New     string // empty to use the curated mapping or the deprecation notice
+	Version string // version of New to require, empty for its latest
+	DryRun  bool
+	NoTidy  bool
+	Force   bool
+	ModPath string
+}
+
+// Run executes the migrate command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	req := parser.FindRequire(opts.Old)
+	if req == nil {
+		return fmt.Errorf("%s is not required in go.mod", opts.Old)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	client := cfg.NewProxyClient()
+
+	m, source, err := resolveMigration(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	if m.New == m.Old {
+		return fmt.Errorf("%s can't be migrated to itself", m.Old)
+	}
+
+	info, err := ui.RunSimpleSpinner("Resolving "+m.New+"...", func() (*proxy.VersionInfo, error) {
+		if opts.Version != "" {
+			return client.Info(ctx, m.New, opts.Version)
+		}
+		return client.Latest(ctx, m.New)
+	})
+	if err != nil {
+		return fmt.Errorf("%s not found on proxy: %w", m.New, err)
+	}
+	if !semver.IsValid(info.Version) {
+		return fmt.Errorf("%s resolved to %q, which is not a version", m.New, info.Version)
+	}
+
+	workDir := filepath.Dir(opts.ModPath)
+	changes, err := importpath.Preview(workDir, m.Moves())
+	if err != nil {
+		return err
+	}
+
+	printPreview(m, source, req.Mod.Version, info.Version, changes)
+
+	if opts.DryRun {
+		return preview(opts, parser, m, info.Version)
+	}
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	tx, err := store.Begin("migrate", m.Old+" → "+m.New)
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.MoveRequire(m.Old, m.New, info.Version); err != nil {
+		tx.Discard()
+		return err
+	}
+	writer.Cleanup()
+	if err := writer.SafeWrite(); err != nil {
+		tx.Discard()
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	if err := writer.CleanupBackup(); err != nil {
+		ui.Error("⚠️  Warning: cleanup backup: %v\n", err)
+	}
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record migration history: %v\n", err)
+	}
+	fmt.Printf("\n✓ go.mod now requires %s %s\n", m.New, info.Version)
+
+	changed, err := importpath.Rewrite(workDir, m.Moves())
+	if len(changed) > 0 {
+		if err := tx.Record(fmt.Sprintf("rewrote imports in %d file(s)", len(changed))); err != nil {
+			ui.Error("⚠️  Warning: could not record migration history: %v\n", err)
+		}
+		fmt.Printf("✓ Rewrote imports in %s file(s)\n", ui.FormatCount(len(changed)))
+	}
+	if err != nil {
+		return fmt.Errorf("rewriting imports: %w", err)
+	}
+
+	if !opts.NoTidy {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := tx.Record(history.EffectTidy); err != nil {
+			ui.Error("⚠️  Warning: could not record migration history: %v\n", err)
+		}
+		if err := gocmd.Run(ctx, workDir, "mod", "tidy"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod tidy' manually")
+		} else {
+			fmt.Println("✓ go.mod and go.sum updated")
+		}
+	}
+	return nil
+}
+
+// preview prints the diff of go.mod and of the source files the migration
+// would change, and the go mod tidy that would follow
+func preview(opts Options, parser *modfile.Parser, m migration.Migration, version string) error {
+	var plan dryrun.Plan
+	err := plan.EditMod(opts.ModPath, parser, func(writer *modfile.Writer) error {
+		if err := writer.MoveRequire(m.Old, m.New, version); err != nil {
+			return err
+		}
+		writer.Cleanup()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	workDir := filepath.Dir(opts.ModPath)
+	files, err := importpath.Rewritten(workDir, m.Moves())
+	if err != nil {
+		return fmt.Errorf("rewriting imports: %w", err)
+	}
+	for _, f := range files {
+		plan.Change(filepath.Join(workDir, f.Path), f.Before, f.After)
+	}
+	if !opts.NoTidy {
+		plan.Tidy(opts.ModPath)
+	}
+	plan.Print(os.Stdout)
+	return nil
+}
+
+// resolveMigration works out where the old module goes: to the module given,
+// else to the curated one, else to the one its deprecation notice names. It
+// also says which of them it was.
+func resolveMigration(ctx context.Context, client *proxy.Client, opts Options) (migration.Migration, string, error) {
+	known, isKnown := migration.Known(opts.Old)
+	switch {
+	case opts.New != "" && isKnown && known.New == opts.New:
+		return known, "curated", nil
+	case opts.New != "":
+		return migration.Migration{Old: opts.Old, New: opts.New}, "given", nil
+	case isKnown && known.New != "":
+		return known, "curated", nil
+	case isKnown:
+		return migration.Migration{}, "", fmt.Errorf("%s has no replacement module: %s", opts.Old, known.Note)
+	}
+
+	deprecated, err := ui.RunSimpleSpinner("Checking "+opts.Old+" for a deprecation notice...", func() (string, error) {
+		return deprecationNotice(ctx, client, opts.Old)
+	})
+	if err != nil {
+		return migration.Migration{}, "", fmt.Errorf("no known replacement for %s (%v); give the new module path", opts.Old, err)
+	}
+	moved, ok := migration.FromDeprecation(opts.Old, deprecated)
+	if !ok {
+		return migration.Migration{}, "", fmt.Errorf("no known replacement for %s; give the new module path", opts.Old)
+	}
+	return migration.Migration{Old: opts.Old, New: moved, Note: "Deprecated: " + deprecated}, "deprecation notice", nil
+}
+
+// deprecationNotice returns the Deprecated comment of a module's latest
+// go.mod, "" when there is none
+func deprecationNotice(ctx context.Context, client *proxy.Client, modulePath string) (string, error) {
+	latest, err := client.Latest(ctx, modulePath)
+	if err != nil {
+		return "", err
+	}
+	data, err := client.GetModFile(ctx, modulePath, latest.Version)
+	if err != nil {
+		return "", err
+	}
+	status, err := modfile.ParseModuleStatus(data)
+	if err != nil {
+		return "", err
+	}
+	return status.Deprecated, nil
+}
+
+// printPreview shows the changes to go.mod and to the imports as a diff
+func printPreview(m migration.Migration, source, from, to string, changes []importpath.Change) {
+	removed := func(s string) string { return ui.MajorStyle.Render("- " + s) }
+	added := func(s string) string { return ui.PatchStyle.Render("+ " + s) }
+
+	fmt.Printf("\n📋 Migrating %s → %s %s\n", m.Old, m.New, ui.UpToDateStyle.Render("("+source+")"))
+
+	fmt.Printf("\n%s\n", ui.HeaderStyle.Render("go.mod"))
+	fmt.Printf("  %s\n  %s\n", removed(m.Old+" "+from), added(m.New+" "+to))
+
+	file := ""
+	for _, c := range changes {
+		if c.File != file {
+			file = c.File
+			fmt.Printf("\n%s\n", ui.HeaderStyle.Render(filepath.ToSlash(file)))
+		}
+		moved := fmt.Sprintf("%q", c.New)
+		if c.Name != "" {
+			moved = c.Name + " " + moved
+		}
+		fmt.Printf("  %4d %s\n       %s\n", c.Line, removed(fmt.Sprintf("%q", c.Old)), added(moved))
+	}
+
+	files := make(map[string]bool)
+	for _, c := range changes {
+		files[c.File] = true
+	}
+	fmt.Printf("\n%s import(s) in %s file(s) to rewrite\n", ui.FormatCount(len(changes)), ui.FormatCount(len(files)))
+	if m.Note != "" {
+		fmt.Printf("⚠️  %s\n", m.Note)
+	}
+}
@@ -3,13 +3,40 @@ package outdated
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"github.com/omarshaarawi/gx/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagDirectOnly bool
-	flagMajorOnly  bool
+	flagDirectOnly   bool
+	flagIndirectOnly bool
+	flagMajorOnly    bool
+	flagMinorOnly    bool
+	flagPatchOnly    bool
+	flagFilter       string
+	flagExclude      []string
+	flagColumns      []string
+	flagShow         []string
+	flagStrict       bool
+	flagFailOn       string
+	flagSort         string
+	flagFormat       string
+	flagMaxAge       time.Duration
+	flagRefresh      bool
+	flagPre          bool
+	flagRecursive    bool
+	flagImpact       bool
+	flagOffline      bool
+	flagShowReplaces bool
+	flagWithVulns    bool
+	flagPorcelain    bool
 )
 
 // NewCommand creates the outdated command
@@ -27,28 +54,305 @@ Examples:
   gx outdated --direct-only
 
   # Show only major version updates
-  gx outdated --major-only`,
+  gx outdated --major-only
+
+  # Show only the patch updates of indirect dependencies
+  gx outdated --patch-only --indirect-only
+
+  # Count newer pre-releases as updates
+  gx outdated --pre
+
+  # Check every module in a monorepo
+  gx outdated --recursive
+
+  # Only check modules matching a pattern, skipping others
+  gx outdated --filter 'github.com/aws/*' --exclude 'github.com/aws/smithy-go'
+
+  # Filter with an expression
+  gx outdated --filter 'ageDays > 365 && direct'
+
+  # Add a computed column
+  gx outdated --column 'stale=ageDays > 365'
+
+  # Show the packages furthest behind first
+  gx outdated --sort behind
+
+  # Fail a CI job when minor or major updates are available
+  gx outdated --fail-on minor
+
+  # Show fewer columns on a narrow terminal
+  gx outdated --show name,current,latest,age
+
+  # Render a markdown table for a pull request comment
+  gx outdated --format markdown > outdated.md
+
+  # Plain columns for scripts
+  gx outdated --format plain --show name,latest
+
+  # Export to a spreadsheet
+  gx outdated --format csv > outdated.csv
+
+  # Stable records for scripts: module and latest version of each package
+  gx outdated --porcelain | awk -F'\t' '$1 == "package" { print $3, $5 }'
+
+  # Reuse results from a run in the last two hours
+  gx outdated --max-cache-age 2h
+
+  # Show the last results without reaching the proxy
+  gx outdated --offline
+
+  # Preview what each direct update would pull into go.mod
+  gx outdated --direct-only --impact -v
+
+  # List the replace directives of the checked modules
+  gx outdated --show-replaces
+
+  # Scan for vulnerabilities too, most vulnerable first
+  gx outdated --with-vulns --sort vulns
+
+The Behind column is the libyear age of each package: the time between the
+release of the installed version and the latest one. The summary totals it.
+
+--sort orders the tables by name, update-type, age (of the installed
+version), behind or vulns, optionally with :asc or :desc. Name sorts ascending by
+default; the others put the largest first.
+
+--filter takes either module patterns or an expression. Patterns are globs
+("github.com/aws/*", "k8s.io/...") or regular expressions written as
+/regex/, comma-separated; they and --exclude apply before any lookups, so
+they also make the run faster.
+
+Expressions can use: name, current, latest, wanted, updateType, direct,
+indirect, ageDays (age of the installed version), latestAgeDays, libyears,
+retracted, deprecated, replaced, goVersion, newerGo and vulns, with the
+operators
+|| && ! == != < <= > >= + - * / % and the functions contains, hasPrefix,
+hasSuffix and lower.
+
+Modules with a version constraint in the config get a Wanted column: the
+newest version the constraint allows, next to the latest.
+
+Packages whose installed version is retracted are marked ✗ and deprecated
+modules †, with the notices listed after the tables, which also list up to
+date modules that are retracted or deprecated. So are updates whose go.mod
+declares a newer go directive than yours, which would raise it.
+Modules replaced by another module, such as a fork, are marked ⇄: their
+latest version is the original module's, not the replacement's. Modules
+replaced by local directories aren't looked up; --show-replaces lists every
+replace directive applying to the checked modules.
+Updates of Kubernetes staging modules such as k8s.io/client-go, versioned
+v0.X.Y for Kubernetes 1.X.Y, are summarized as a release change, e.g.
+"Kubernetes 1.29 → 1.31".
+
+Inside a go.work workspace every member module is checked, with a summary
+per module and for the whole workspace. Set GOWORK=off to check only ./go.mod.
+--recursive does the same for every go.mod under the current directory, or
+the one --mod names, skipping vendor and testdata directories and those
+starting with . or _. Modules are checked concurrently, and a dependency
+shared by several of them is looked up once.
+
+Modules whose latest version can't be looked up are listed as warnings after
+the results. With --strict, the command then exits non-zero.
+
+--fail-on major|minor|patch|any exits non-zero when any listed package has an
+update of that type or larger, so the command can gate CI without parsing
+its output. Filters narrow what counts.
+
+--show picks the table columns and their order, from name, current,
+wanted, latest, update, released, behind, age (when the installed version
+was released) and vulns. By default all but age are shown, wanted only for
+modules with a constraint. Computed columns follow them.
+
+--format markdown prints GitHub-flavored markdown tables and the summary
+instead of the terminal tables, ready to paste into a PR comment. --format
+csv prints one row per package (module, current, latest, type, direct,
+release_date, retracted, retraction_reason, deprecated, constraint, wanted,
+go_version, new_requires, raised_requires, replaced_by, vulns, then any
+computed columns); warnings go to stderr. --format plain prints the table
+columns as aligned plain text under a header of column names, with dates
+instead of relative times and no notices or summary; warnings go to stderr
+too.
+
+--porcelain prints tab-separated records in a versioned format that stays
+stable when the other outputs change, for scripts. The first record is
+"version 1 outdated", then one per package:
+
+  package <member> <module> <current> <latest> <type> <direct|indirect>
+          <release date> <wanted> <replaced by> <retracted> <deprecated>
+          <vulns> [computed columns...]
+
+and one per module that couldn't be looked up:
+
+  failed <member> <module> <reason>
+
+member is the workspace module requiring the package, empty outside a
+workspace. Empty fields stay empty, retracted and deprecated are true or
+false and vulns is empty without --with-vulns. New fields are only ever
+appended. Everything else goes to stderr.
+
+--impact reads the go.mod of each direct update's target version and counts
+the requirements it would add to go.mod or raise, the update's blast radius
+beyond the module itself. With -v each of them is listed. The counts are
+also available to expressions as newRequires and raisedRequires (-1 when not
+previewed).
+
+Every run saves the latest versions it finds, and the whole report for each
+go.mod. With --max-cache-age (or max_cache_age in the config), later runs
+reuse results up to that age instead of asking the proxy: an unchanged
+go.mod checked with the same options is reported instantly. The summary
+notes how old the results are. --refresh asks the proxy for everything.
+
+--with-vulns runs govulncheck on the module while the updates are looked
+up, and adds a Vulns column with the number of known vulnerabilities in each
+package's installed version, marked ⚠. The vulnerabilities are listed after
+the tables, noting whether the update fixes them, so security-relevant
+updates can go first. Expressions see the count as vulns (-1 without a
+scan).
+
+--offline never asks the proxy. It shows the last report saved for the
+go.mod, however old, and fails if there is none, e.g. when go.mod changed
+since the last online run.`,
 		RunE: runOutdated,
 	}
 
 	cmd.Flags().BoolVar(&flagDirectOnly, "direct-only", false, "Show only direct dependencies")
+	cmd.Flags().BoolVar(&flagIndirectOnly, "indirect-only", false, "Show only indirect dependencies")
 	cmd.Flags().BoolVar(&flagMajorOnly, "major-only", false, "Show only major version updates")
+	cmd.Flags().BoolVar(&flagMinorOnly, "minor-only", false, "Show only minor version updates")
+	cmd.Flags().BoolVar(&flagPatchOnly, "patch-only", false, "Show only patch version updates")
+	cmd.Flags().StringVar(&flagFilter, "filter", "", "Only show packages matching module patterns or an expression")
+	cmd.Flags().StringArrayVar(&flagExclude, "exclude", nil, "Skip modules matching patterns (comma-separated, repeatable)")
+	cmd.Flags().StringArrayVar(&flagColumns, "column", nil, "Add a computed column (label=expression, repeatable)")
+	cmd.Flags().StringArrayVar(&flagShow, "show", nil, "Table columns to show, in order (comma-separated, e.g. name,current,latest,age)")
+	cmd.Flags().BoolVar(&flagStrict, "strict", false, "Exit non-zero if any module could not be checked")
+	cmd.Flags().StringVar(&flagFailOn, "fail-on", "", "Exit non-zero if updates of this type exist (major|minor|patch|any)")
+	cmd.Flags().StringVar(&flagSort, "sort", "name", "Sort by name, update-type, age, behind or vulns, with optional :asc or :desc")
+	cmd.Flags().StringVar(&flagFormat, "format", FormatTable, "Output format (table, markdown, csv, plain)")
+	cmd.Flags().DurationVar(&flagMaxAge, "max-cache-age", 0, "Reuse latest versions cached by earlier runs up to this age (default from config, off)")
+	cmd.Flags().BoolVar(&flagRefresh, "refresh", false, "Look up every module on the proxy, ignoring cached results")
+	cmd.Flags().BoolVar(&flagOffline, "offline", false, "Show the last saved results without asking the proxy")
+	cmd.Flags().BoolVarP(&flagRecursive, "recursive", "r", false, "Check every go.mod under the current directory, or the one --mod names")
+	cmd.Flags().BoolVar(&flagPre, "pre", false, "Include pre-release versions when looking for the latest version")
+	cmd.Flags().BoolVar(&flagImpact, "impact", false, "Count the requirements each direct update would add or raise")
+	cmd.Flags().BoolVar(&flagShowReplaces, "show-replaces", false, "List the replace directives applying to the checked modules")
+	cmd.Flags().BoolVar(&flagWithVulns, "with-vulns", false, "Scan for vulnerabilities too and count them per package")
+	cmd.Flags().BoolVar(&flagPorcelain, "porcelain", false, "Print stable tab-separated records for scripts")
+
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions(FailOnLevels, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(SortFields, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("show", cobra.FixedCompletions(TableColumns, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(Formats, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
 
 func runOutdated(cmd *cobra.Command, args []string) error {
-	modPath := "go.mod"
-	if _, err := os.Stat(modPath); os.IsNotExist(err) {
-		return fmt.Errorf("go.mod not found in current directory")
+	modPath := modflag.Path(cmd)
+	workPath, inWorkspace := workspace.Detect(filepath.Dir(modPath))
+	if _, err := os.Stat(modPath); os.IsNotExist(err) && !inWorkspace && !flagRecursive {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if flagDirectOnly && flagIndirectOnly {
+		return fmt.Errorf("--direct-only and --indirect-only can't be combined")
+	}
+	if countTrue(flagMajorOnly, flagMinorOnly, flagPatchOnly) > 1 {
+		return fmt.Errorf("only one of --major-only, --minor-only and --patch-only can be used")
+	}
+
+	if flagFailOn != "" && !slices.Contains(FailOnLevels, flagFailOn) {
+		return fmt.Errorf("invalid --fail-on %q (want %s)", flagFailOn, strings.Join(FailOnLevels, ", "))
+	}
+
+	if flagOffline && flagRefresh {
+		return fmt.Errorf("--offline and --refresh can't be combined")
+	}
+	if flagOffline && flagWithVulns {
+		return fmt.Errorf("--offline and --with-vulns can't be combined, the scan needs the vulnerability database")
+	}
+
+	if !slices.Contains(Formats, flagFormat) {
+		return fmt.Errorf("invalid --format %q (want %s)", flagFormat, strings.Join(Formats, ", "))
+	}
+
+	if len(flagShow) > 0 && flagFormat == FormatCSV {
+		return fmt.Errorf("--show doesn't apply to --format csv, which has every field")
+	}
+
+	format := flagFormat
+	if flagPorcelain {
+		if cmd.Flags().Changed("format") {
+			return fmt.Errorf("--porcelain and --format can't be combined")
+		}
+		if len(flagShow) > 0 {
+			return fmt.Errorf("--show doesn't apply to --porcelain, which has every field")
+		}
+		format = FormatPorcelain
+	}
+
+	if flagOffline {
+		// A missing saved report isn't a usage mistake
+		cmd.SilenceUsage = true
+	}
+
+	if flagStrict || flagFailOn != "" {
+		// The results are already printed; the returned error only sets the exit code
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+
+	filter, include := flagFilter, []string(nil)
+	if IsModulePattern(flagFilter) {
+		filter, include = "", pattern.SplitFilter(flagFilter)
+	}
+	var exclude []string
+	for _, e := range flagExclude {
+		exclude = append(exclude, pattern.SplitFilter(e)...)
+	}
+
+	// A negative age falls back to the configured max_cache_age
+	maxAge := time.Duration(-1)
+	if cmd.Flags().Changed("max-cache-age") {
+		maxAge = flagMaxAge
 	}
 
 	opts := Options{
-		DirectOnly: flagDirectOnly,
-		MajorOnly:  flagMajorOnly,
-		ModPath:    modPath,
+		Workspace:    workPath,
+		DirectOnly:   flagDirectOnly,
+		IndirectOnly: flagIndirectOnly,
+		MajorOnly:    flagMajorOnly,
+		MinorOnly:    flagMinorOnly,
+		PatchOnly:    flagPatchOnly,
+		ModPath:      modPath,
+		Filter:       filter,
+		Include:      include,
+		Exclude:      exclude,
+		Columns:      flagColumns,
+		TableColumns: flagShow,
+		Strict:       flagStrict,
+		FailOn:       flagFailOn,
+		Sort:         flagSort,
+		Format:       format,
+		Pre:          flagPre,
+		Recursive:    flagRecursive,
+		Impact:       flagImpact,
+		Offline:      flagOffline,
+		ShowReplaces: flagShowReplaces,
+		WithVulns:    flagWithVulns,
+
+		MaxCacheAge: maxAge,
+		Refresh:     flagRefresh,
 	}
 
 	return Run(cmd.Context(), opts)
 }
 
+func countTrue(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package outdated
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// TableColumns lists the columns --show can select. age, when the
+// installed version was released, is only shown when selected, and vulns
+// needs --with-vulns.
+var TableColumns = []string{"name", "current", "wanted", "latest", "update", "released", "behind", "age", "vulns"}
+
+// defaultColumns are shown without --show, wanted only when a package has
+// a version constraint
+var defaultColumns = []string{"name", "current", "wanted", "latest", "update", "released", "behind"}
+
+var columnHeaders = map[string]string{
+	"name":     "Package",
+	"current":  "Current",
+	"wanted":   "Wanted",
+	"latest":   "Latest",
+	"update":   "Update",
+	"released": "Released",
+	"behind":   "Behind",
+	"age":      "Age",
+	"vulns":    "Vulns",
+}
+
+// ParseColumns parses --show values, column names separated by commas, in
+// the order to show them. It returns nil when none are given.
+func ParseColumns(specs []string) ([]string, error) {
+	var selected []string
+	for _, spec := range specs {
+		for _, name := range strings.Split(spec, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(TableColumns, name) {
+				return nil, fmt.Errorf("unknown column %q (want %s)", name, strings.Join(TableColumns, ", "))
+			}
+			if slices.Contains(selected, name) {
+				return nil, fmt.Errorf("column %q is listed twice", name)
+			}
+			selected = append(selected, name)
+		}
+	}
+	if len(specs) > 0 && len(selected) == 0 {
+		return nil, fmt.Errorf("--show names no columns")
+	}
+	return selected, nil
+}
+
+// shownColumns returns the columns to render for packages: the selected
+// ones, or the defaults and vulns after a vulnerability scan
+func shownColumns(selected []string, packages []Package) []string {
+	if len(selected) > 0 {
+		return selected
+	}
+	shown := slices.Clone(defaultColumns)
+	if !anyConstrained(packages) {
+		shown = slices.DeleteFunc(shown, func(c string) bool { return c == "wanted" })
+	}
+	if slices.ContainsFunc(packages, func(p Package) bool { return p.VulnsChecked }) {
+		shown = append(shown, "vulns")
+	}
+	return shown
+}
+
+// headers returns the header row for columns, computed columns last
+func headers(shown []string, columns []Column) []string {
+	row := make([]string, 0, len(shown)+len(columns))
+	for _, c := range shown {
+		row = append(row, columnHeaders[c])
+	}
+	for _, col := range columns {
+		row = append(row, col.Label)
+	}
+	return row
+}
+
+// cell renders one built-in column of a package as the terminal table
+// shows it
+func cell(column string, pkg Package) string {
+	switch column {
+	case "name":
+		return pkg.Name
+	case "current":
+		return pkg.Current
+	case "wanted":
+		return wantedCell(pkg)
+	case "latest":
+		return pkg.Latest
+	case "update":
+		return withSymbol(pkg.UpdateType)
+	case "released":
+		return ui.FormatReleaseTime(pkg.LatestTime)
+	case "behind":
+		return formatLibyears(pkg.Libyears())
+	case "age":
+		return ui.FormatReleaseTime(pkg.CurrentTime)
+	case "vulns":
+		return vulnsCell(pkg)
+	}
+	return ""
+}
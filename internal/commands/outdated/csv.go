@@ -0,0 +1,84 @@
+package outdated
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// csvFields lists the CSV column names, before any computed columns
+var csvFields = []string{"module", "current", "latest", "type", "direct", "release_date", "retracted", "retraction_reason", "deprecated", "constraint", "wanted", "go_version", "new_requires", "raised_requires", "replaced_by", "vulns"}
+
+// csvRow is a package and, inside a workspace, the member module requiring it
+type csvRow struct {
+	Member string
+	Package
+}
+
+func csvRows(member string, packages []Package) []csvRow {
+	rows := make([]csvRow, len(packages))
+	for i, pkg := range packages {
+		rows[i] = csvRow{Member: member, Package: pkg}
+	}
+	return rows
+}
+
+// writeCSV writes packages as CSV with a header row, for importing into a
+// spreadsheet. Workspace reports start each row with the member module.
+func writeCSV(w io.Writer, rows []csvRow, columns []Column, workspace bool) error {
+	cw := csv.NewWriter(w)
+
+	header := append([]string(nil), csvFields...)
+	for _, col := range columns {
+		header = append(header, col.Label)
+	}
+	if workspace {
+		header = append([]string{"workspace_module"}, header...)
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	for _, r := range rows {
+		released := ""
+		if !r.LatestTime.IsZero() {
+			released = r.LatestTime.UTC().Format("2006-01-02")
+		}
+		added, raised := "", ""
+		if r.ImpactChecked {
+			a, b := r.impactCounts()
+			added, raised = strconv.Itoa(a), strconv.Itoa(b)
+		}
+		row := []string{r.Name, "v" + r.Current, "v" + r.Latest, r.UpdateType, strconv.FormatBool(r.Direct), released, r.Retracted, oneLine(r.RetractionReason), oneLine(r.Deprecated), r.Constraint, withV(r.Wanted), r.GoVersion, added, raised, r.ReplacedBy, vulnIDs(r.Package)}
+		row = append(row, r.Computed...)
+		if workspace {
+			row = append([]string{r.Member}, row...)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// withV restores the v prefix of a non-empty version
+func withV(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "v" + version
+}
+
+// warnFailures reports failed lookups on stderr, keeping stdout valid CSV
+func warnFailures(failures []Failure) {
+	for _, f := range failures {
+		reason, _, _ := strings.Cut(f.Err.Error(), "\n")
+		ui.Error("⚠️  Warning: could not resolve %s: %s\n", f.Module, reason)
+	}
+}
@@ -0,0 +1,124 @@
+package outdated
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/expr"
+	"github.com/omarshaarawi/gx/internal/pattern"
+)
+
+// Column is a computed table column defined by an expression
+type Column struct {
+	Label string
+	Expr  *expr.Expr
+}
+
+var columnSpecPattern = regexp.MustCompile(`^\s*([A-Za-z_][\w-]*)\s*=([^=].*)$`)
+
+// ParseColumn parses a "label=expression" column spec. Without a label the
+// expression text is used as the header.
+func ParseColumn(spec string) (Column, error) {
+	label, src := spec, spec
+	if m := columnSpecPattern.FindStringSubmatch(spec); m != nil {
+		label, src = m[1], m[2]
+	}
+
+	e, err := expr.Parse(src)
+	if err != nil {
+		return Column{}, fmt.Errorf("column %q: %w", spec, err)
+	}
+
+	return Column{Label: label, Expr: e}, nil
+}
+
+// IsModulePattern reports whether a --filter value is a list of module
+// patterns rather than an expression. Patterns name a module path, so they
+// contain a dot, slash or wildcard and none of the expression operators;
+// "direct" or "ageDays > 365" stay expressions.
+func IsModulePattern(s string) bool {
+	s = strings.TrimSpace(s)
+	if pattern.IsRegex(s) {
+		return true
+	}
+	if s == "" || strings.ContainsAny(s, " \t&|=<>!()\"'+%") {
+		return false
+	}
+	return strings.ContainsAny(s, "./*?[")
+}
+
+// Env exposes a package to filter and column expressions
+func (p Package) Env() expr.Env {
+	// Impact counts are -1 unless --impact previewed the update, and the
+	// vulnerability count unless --with-vulns scanned
+	added, raised := -1, -1
+	if p.ImpactChecked {
+		added, raised = p.impactCounts()
+	}
+	vulns := -1
+	if p.VulnsChecked {
+		vulns = len(p.Vulns)
+	}
+	return expr.Env{
+		"name":           p.Name,
+		"current":        p.Current,
+		"latest":         p.Latest,
+		"wanted":         p.Wanted,
+		"updateType":     p.UpdateType,
+		"direct":         p.Direct,
+		"indirect":       !p.Direct,
+		"ageDays":        daysSince(p.CurrentTime),
+		"latestAgeDays":  daysSince(p.LatestTime),
+		"libyears":       p.Libyears(),
+		"deprecated":     p.Deprecated != "",
+		"retracted":      p.Retracted != "",
+		"replaced":       p.ReplacedBy != "",
+		"goVersion":      p.GoVersion,
+		"newerGo":        p.NewerGo,
+		"newRequires":    added,
+		"raisedRequires": raised,
+		"vulns":          vulns,
+	}
+}
+
+// daysSince returns whole days since t, or -1 when unknown
+func daysSince(t time.Time) int {
+	if t.IsZero() {
+		return -1
+	}
+	return int(time.Since(t).Hours() / 24)
+}
+
+// applyExpressions filters packages and evaluates computed columns
+func applyExpressions(packages []Package, filter *expr.Expr, columns []Column) ([]Package, error) {
+	var result []Package
+
+	for _, pkg := range packages {
+		env := pkg.Env()
+
+		if filter != nil {
+			keep, err := filter.EvalBool(env)
+			if err != nil {
+				return nil, fmt.Errorf("filter: %w", err)
+			}
+			if !keep {
+				continue
+			}
+		}
+
+		pkg.Computed = make([]string, len(columns))
+		for i, col := range columns {
+			v, err := col.Expr.Eval(env)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", col.Label, err)
+			}
+			pkg.Computed[i] = expr.Format(v)
+		}
+
+		result = append(result, pkg)
+	}
+
+	return result, nil
+}
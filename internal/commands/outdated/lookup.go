@@ -0,0 +1,265 @@
+package outdated
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versioncache"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// lookups resolves versions through the proxy, reusing the results that
+// earlier runs cached on disk. Within a run each lookup is made once, however
+// many go.mod files require the module.
+type lookups struct {
+	client *proxy.Client
+	cache  *versioncache.Store // nil when the cache can't be opened
+	maxAge time.Duration       // zero always asks the proxy for latest versions
+	pre    bool                // a newer pre-release counts as the latest version
+
+	// constraintFor returns the configured version constraint of a module
+	constraintFor func(modulePath string) (versions.Constraint, bool)
+
+	latests  shared[latestResult]
+	lists    shared[[]string]
+	wanteds  shared[*proxy.VersionInfo]
+	infos    shared[*proxy.VersionInfo]
+	statuses shared[*modfile.ModuleStatus]
+	modFiles shared[[]byte]
+}
+
+type latestResult struct {
+	info    *proxy.VersionInfo
+	checked time.Time
+}
+
+// shared runs each keyed lookup once, handing its result to every caller,
+// including those that ask while it is still running
+type shared[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*sharedCall[T]
+}
+
+type sharedCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+func (s *shared[T]) do(key string, fn func() (T, error)) (T, error) {
+	s.mu.Lock()
+	if c, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		<-c.done
+		return c.val, c.err
+	}
+	if s.calls == nil {
+		s.calls = make(map[string]*sharedCall[T])
+	}
+	c := &sharedCall[T]{done: make(chan struct{})}
+	s.calls[key] = c
+	s.mu.Unlock()
+
+	c.val, c.err = fn()
+	close(c.done)
+	return c.val, c.err
+}
+
+func newLookups(cfg *config.Config, maxAge time.Duration, pre bool) *lookups {
+	cache, err := versioncache.Open()
+	if err != nil {
+		ui.Debug("version cache unavailable: %v", err)
+	}
+	return &lookups{
+		client:        cfg.NewProxyClient(),
+		cache:         cache,
+		maxAge:        maxAge,
+		pre:           pre,
+		constraintFor: cfg.ConstraintFor,
+	}
+}
+
+// latest returns a module's latest version and, when it came from the
+// cache, when it was looked up
+func (l *lookups) latest(ctx context.Context, modulePath string) (*proxy.VersionInfo, time.Time, error) {
+	r, err := l.latests.do(modulePath, func() (latestResult, error) {
+		info, checked, err := l.lookupLatest(ctx, modulePath)
+		return latestResult{info, checked}, err
+	})
+	return r.info, r.checked, err
+}
+
+func (l *lookups) lookupLatest(ctx context.Context, modulePath string) (*proxy.VersionInfo, time.Time, error) {
+	cached, store, query := l.cache.Latest, l.cache.SetLatest, l.client.Latest
+	if l.pre {
+		cached, store, query = l.cache.LatestPre, l.cache.SetLatestPre, l.client.LatestPre
+	}
+
+	if l.cache != nil {
+		if e, ok := cached(modulePath, l.maxAge); ok {
+			return &proxy.VersionInfo{Version: e.Version, Time: e.Time}, e.Checked, nil
+		}
+	}
+
+	info, err := query(ctx, modulePath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if l.cache != nil {
+		store(modulePath, info.Version, info.Time)
+	}
+	return info, time.Time{}, nil
+}
+
+// wanted returns the newest version a module's constraint allows, or nil when
+// it allows none
+func (l *lookups) wanted(ctx context.Context, modulePath string, c versions.Constraint) (*proxy.VersionInfo, error) {
+	return l.wanteds.do(modulePath+" "+c.String(), func() (*proxy.VersionInfo, error) {
+		list, err := l.versions(ctx, modulePath)
+		if err != nil {
+			return nil, err
+		}
+		best := proxy.HighestAllowed(modulePath, list, l.pre, c.Allows)
+		if best == "" {
+			return nil, nil
+		}
+		return l.info(ctx, modulePath, best)
+	})
+}
+
+// versions returns the versions published for a module, from the cache when
+// they were listed within the max age like latest versions
+func (l *lookups) versions(ctx context.Context, modulePath string) ([]string, error) {
+	return l.lists.do(modulePath, func() ([]string, error) {
+		if l.cache != nil {
+			if list, ok := l.cache.Versions(modulePath, l.maxAge); ok {
+				return list, nil
+			}
+		}
+
+		list, err := l.client.Versions(ctx, modulePath)
+		if err != nil {
+			return nil, err
+		}
+		if l.cache != nil {
+			l.cache.SetVersions(modulePath, list)
+		}
+		return list, nil
+	})
+}
+
+// info returns a version's release information. Published versions don't
+// change, so cached entries are used whatever their age.
+func (l *lookups) info(ctx context.Context, modulePath, version string) (*proxy.VersionInfo, error) {
+	return l.infos.do(modulePath+"@"+version, func() (*proxy.VersionInfo, error) {
+		return l.lookupInfo(ctx, modulePath, version)
+	})
+}
+
+func (l *lookups) lookupInfo(ctx context.Context, modulePath, version string) (*proxy.VersionInfo, error) {
+	if l.cache != nil {
+		if e, ok := l.cache.Info(modulePath, version); ok {
+			return &proxy.VersionInfo{Version: e.Version, Time: e.Time}, nil
+		}
+	}
+
+	info, err := l.client.Info(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if l.cache != nil {
+		l.cache.SetInfo(modulePath, version, info.Time)
+	}
+	return info, nil
+}
+
+// status returns the deprecation and retraction notices published in a
+// module version's go.mod, which is cached whatever its age
+func (l *lookups) status(ctx context.Context, modulePath, version string) (*modfile.ModuleStatus, error) {
+	return l.statuses.do(modulePath+"@"+version, func() (*modfile.ModuleStatus, error) {
+		return l.lookupStatus(ctx, modulePath, version)
+	})
+}
+
+func (l *lookups) lookupStatus(ctx context.Context, modulePath, version string) (*modfile.ModuleStatus, error) {
+	data, err := l.modFile(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	return modfile.ParseModuleStatus(data)
+}
+
+// modFile returns a module version's go.mod, which is cached whatever its age
+func (l *lookups) modFile(ctx context.Context, modulePath, version string) ([]byte, error) {
+	return l.modFiles.do(modulePath+"@"+version, func() ([]byte, error) {
+		return l.lookupModFile(ctx, modulePath, version)
+	})
+}
+
+func (l *lookups) lookupModFile(ctx context.Context, modulePath, version string) ([]byte, error) {
+	if l.cache != nil {
+		if data, _ := l.cache.ModFile(modulePath, version); len(data) > 0 {
+			return data, nil
+		}
+	}
+
+	data, err := l.client.GetModFile(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if l.cache != nil {
+		l.cache.SetModFile(modulePath, version, data)
+	}
+	return data, nil
+}
+
+// save writes the cache back for the next run
+func (l *lookups) save() {
+	if l.cache == nil {
+		return
+	}
+	if err := l.cache.Save(); err != nil {
+		ui.Debug("saving version cache: %v", err)
+	}
+}
+
+// cachedNote tells how old the oldest cached result in a report is, e.g.
+// "(cached 2h ago)", or "" when every result is live
+func cachedNote(checked time.Time) string {
+	if checked.IsZero() {
+		return ""
+	}
+
+	d := time.Since(checked)
+	switch {
+	case d < time.Minute:
+		return "(cached just now)"
+	case d < time.Hour:
+		return fmt.Sprintf("(cached %dm ago)", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("(cached %dh ago)", int(d.Hours()))
+	}
+	return fmt.Sprintf("(cached %dd ago)", int(d.Hours()/24))
+}
+
+// withSpace prefixes a non-empty note with a space
+func withSpace(note string) string {
+	if note == "" {
+		return ""
+	}
+	return " " + note
+}
+
+// oldest returns the earlier of two cache times, ignoring zero ones
+func oldest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,209 @@
+package outdated
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Output formats accepted by --format
+const (
+	FormatTable    = "table"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// Formats lists the accepted --format values
+var Formats = []string{FormatTable, FormatMarkdown, FormatCSV, FormatPlain}
+
+// markdownReport renders one go.mod's results as GitHub-flavored markdown,
+// for pasting into a PR comment
+func markdownReport(rep *report, selected []string, columns []Column, filtered, showReplaces bool) string {
+	var b strings.Builder
+
+	switch {
+	case rep.Checked == 0 && len(rep.Ignored) > 0:
+		b.WriteString("✨ No dependencies to check besides ignored ones\n")
+	case rep.Checked == 0 && len(rep.Replaced) > 0:
+		b.WriteString("✨ No dependencies to check besides local replacements\n")
+	case rep.Checked == 0 && filtered:
+		b.WriteString("No dependencies match the module filter\n")
+	case rep.Checked == 0:
+		b.WriteString("No dependencies found\n")
+	case len(rep.Packages) == 0:
+		b.WriteString(upToDateMessage(rep.Failures) + withSpace(cachedNote(rep.CachedAt)) + "\n")
+		if len(rep.UpToDate) > 0 {
+			b.WriteString("\n")
+			writeMarkdownNotices(&b, nil, rep.UpToDate)
+		}
+	default:
+		direct, indirect := splitDirect(rep.Packages)
+		shown := shownColumns(selected, rep.Packages)
+		if len(direct) > 0 {
+			fmt.Fprintf(&b, "### 📦 Direct dependencies\n\n%s\n", markdownTable(direct, shown, columns))
+		}
+		if len(indirect) > 0 {
+			fmt.Fprintf(&b, "### 🔗 Indirect dependencies\n\n%s\n", markdownTable(indirect, shown, columns))
+		}
+		writeMarkdownNotices(&b, rep.Packages, rep.UpToDate)
+
+		fmt.Fprintf(&b, "**📊 Summary:** %s%s\n", summarize(rep.Packages, false), withSpace(cachedNote(rep.CachedAt)))
+		if total, ok := libyearsSummary(rep.Packages); ok {
+			fmt.Fprintf(&b, "\n**⏳ Total age:** %s\n", total)
+		}
+	}
+
+	writeMarkdownFailures(&b, rep.Failures, rep.Checked)
+	writeMarkdownReplaced(&b, rep.Replaced, showReplaces)
+	writeMarkdownIgnored(&b, rep.Ignored)
+	return b.String()
+}
+
+// markdownTable renders the same columns as the terminal table, without
+// truncating module paths
+func markdownTable(packages []Package, shown []string, columns []Column) string {
+	table := ui.NewMarkdownTable(headers(shown, columns)...)
+
+	for _, pkg := range packages {
+		row := make([]string, 0, len(shown)+len(pkg.Computed))
+		for _, c := range shown {
+			switch {
+			case c == "name":
+				row = append(row, ui.MarkdownCode(pkg.Name)+noticeMarks(pkg))
+			case c == "update" && pkg.UpdateType == "major":
+				row = append(row, ui.UpdateSymbol("major")+" **major**")
+			default:
+				row = append(row, ui.MarkdownEscape(cell(c, pkg)))
+			}
+		}
+		for _, v := range pkg.Computed {
+			row = append(row, ui.MarkdownEscape(v))
+		}
+		table.AddRow(row...)
+	}
+
+	return table.Render()
+}
+
+// writeMarkdownNotices mirrors printNotices
+func writeMarkdownNotices(b *strings.Builder, packages, upToDate []Package) {
+	vulnerable, retracted, deprecated, replaced, newerGo, impact := withNotices(packages, upToDate)
+
+	if len(vulnerable) > 0 {
+		fmt.Fprintf(b, "**⚠️ Vulnerable installed versions (%s):**\n\n", ui.FormatCount(len(vulnerable)))
+		for _, pkg := range vulnerable {
+			for _, v := range pkg.Vulns {
+				fmt.Fprintf(b, "- %s: [%s](%s)%s\n", ui.MarkdownCode(pkg.Name+"@v"+pkg.Current), v.ID, v.URL, ui.MarkdownEscape(strings.TrimPrefix(vulnNote(pkg, v), v.ID)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(retracted) > 0 {
+		fmt.Fprintf(b, "**%s Retracted versions in use (%s):**\n\n", ui.RetractedSymbol, ui.FormatCount(len(retracted)))
+		for _, pkg := range retracted {
+			fmt.Fprintf(b, "- %s: %s\n", ui.MarkdownCode(pkg.Name+"@v"+pkg.Current), ui.MarkdownEscape(retractionReason(pkg)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(deprecated) > 0 {
+		fmt.Fprintf(b, "**%s Deprecated modules (%s):**\n\n", ui.DeprecatedSymbol, ui.FormatCount(len(deprecated)))
+		for _, pkg := range deprecated {
+			fmt.Fprintf(b, "- %s: %s\n", ui.MarkdownCode(pkg.Name), ui.MarkdownEscape(oneLine(pkg.Deprecated)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(replaced) > 0 {
+		fmt.Fprintf(b, "**%s Replaced modules, showing the original's latest version (%s):**\n\n", ui.ReplacedSymbol, ui.FormatCount(len(replaced)))
+		for _, pkg := range replaced {
+			fmt.Fprintf(b, "- %s => %s\n", ui.MarkdownCode(pkg.Name), ui.MarkdownCode(pkg.ReplacedBy))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(newerGo) > 0 {
+		fmt.Fprintf(b, "**⚠️ Updates needing a newer Go than go.mod declares (%s):**\n\n", ui.FormatCount(len(newerGo)))
+		for _, pkg := range newerGo {
+			fmt.Fprintf(b, "- %s: requires go %s\n", ui.MarkdownCode(pkg.Name+"@v"+targetVersion(pkg)), ui.MarkdownEscape(pkg.GoVersion))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(impact) > 0 {
+		fmt.Fprintf(b, "**📈 Requirements pulled in by direct updates (%s):**\n\n", ui.FormatCount(len(impact)))
+		for _, pkg := range impact {
+			fmt.Fprintf(b, "- %s: %s\n", ui.MarkdownCode(pkg.Name+"@v"+targetVersion(pkg)), impactSummary(pkg))
+			if ui.IsVerbose() {
+				for _, c := range pkg.Impact {
+					fmt.Fprintf(b, "  - %s\n", ui.MarkdownEscape(impactChange(c)))
+				}
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if k := kubernetesUpgrade(packages); k != nil {
+		var modules []string
+		for _, m := range k.Modules {
+			modules = append(modules, ui.MarkdownCode(m))
+		}
+		fmt.Fprintf(b, "**☸️ Kubernetes %s:** %s\n\n", k, strings.Join(modules, ", "))
+	}
+}
+
+func writeMarkdownFailures(b *strings.Builder, failures []Failure, checked int) {
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n**⚠️ Could not resolve %s of %s modules:**\n\n", ui.FormatCount(len(failures)), ui.FormatCount(checked))
+	for _, f := range failures {
+		reason, _, _ := strings.Cut(f.Err.Error(), "\n")
+		fmt.Fprintf(b, "- %s: %s\n", ui.MarkdownCode(f.Module), ui.MarkdownEscape(reason))
+	}
+}
+
+// writeMarkdownReplaced mirrors printReplaced
+func writeMarkdownReplaced(b *strings.Builder, replaced []modfile.Replacement, show bool) {
+	if !show {
+		if local := localCount(replaced); local > 0 {
+			fmt.Fprintf(b, "\n%s module(s) replaced by local directories not checked\n", ui.FormatCount(local))
+		}
+		return
+	}
+	if len(replaced) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n**%s Replaced modules (%s):**\n\n", ui.ReplacedSymbol, ui.FormatCount(len(replaced)))
+	for _, r := range replaced {
+		fmt.Fprintf(b, "- %s => %s: %s\n", ui.MarkdownCode(r.Path+"@"+r.Version), ui.MarkdownCode(r.String()), replacementKind(r))
+	}
+}
+
+// writeMarkdownIgnored mirrors printIgnored: a count, or the modules and
+// reasons in verbose mode
+func writeMarkdownIgnored(b *strings.Builder, ignored []config.ModuleRule) {
+	if len(ignored) == 0 {
+		return
+	}
+
+	if !ui.IsVerbose() {
+		fmt.Fprintf(b, "\n%s module(s) ignored by config\n", ui.FormatCount(len(ignored)))
+		return
+	}
+
+	fmt.Fprintf(b, "\n**🙈 Ignored by config (%s):**\n\n", ui.FormatCount(len(ignored)))
+	for _, r := range ignored {
+		reason := r.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Fprintf(b, "- %s: %s\n", ui.MarkdownCode(r.Module), ui.MarkdownEscape(reason))
+	}
+}
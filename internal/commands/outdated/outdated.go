@@ -3,21 +3,53 @@ package outdated
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/expr"
 	"github.com/omarshaarawi/gx/internal/modfile"
-	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/pattern"
 	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	"github.com/omarshaarawi/gx/internal/workspace"
 	xmodfile "golang.org/x/mod/modfile"
-	"golang.org/x/mod/semver"
 )
 
 // Options configures the outdated command
 type Options struct {
-	DirectOnly bool
-	MajorOnly  bool
-	ModPath    string
+	DirectOnly   bool
+	IndirectOnly bool
+	MajorOnly    bool
+	MinorOnly    bool
+	PatchOnly    bool
+	ModPath      string
+	Workspace    string   // go.work path; when set, every member module is checked
+	Recursive    bool     // check every go.mod under the module directory
+	Filter       string   // boolean expression selecting packages
+	Include      []string // module patterns to check; empty means all
+	Exclude      []string // module patterns to skip
+	Columns      []string // computed columns as label=expression
+	TableColumns []string // built-in columns to show, comma-separated; empty shows the defaults
+	Strict       bool     // fail when any module couldn't be checked
+	FailOn       string   // fail when updates of this type or larger exist
+	Sort         string   // table order, field[:asc|desc]
+	Format       string   // output format, table, markdown, csv, plain or porcelain
+	Pre          bool     // a newer pre-release counts as the latest version
+	Impact       bool     // preview the requirements each direct update pulls in
+	Offline      bool     // reuse the last saved results without asking the proxy
+	ShowReplaces bool     // list the replace directives applying to checked modules
+	WithVulns    bool     // scan for vulnerabilities alongside the lookups
+
+	// MaxCacheAge reuses latest versions cached by earlier runs up to this
+	// age; negative uses the configured max_cache_age
+	MaxCacheAge time.Duration
+	Refresh     bool // ask the proxy for every module, still updating the cache
 }
 
 // Package represents a package with version information
@@ -27,96 +59,989 @@ type Package struct {
 	Latest     string
 	UpdateType string // major, minor, patch, none
 	Direct     bool
+
+	// The module's version constraint from the config and the newest version
+	// it allows, "" when it allows none; both empty when unconstrained
+	Constraint string
+	Wanted     string
+
+	// Notices from the latest go.mod: the module's deprecation message, and
+	// the retracted range covering the installed version with its reason
+	Deprecated       string
+	Retracted        string
+	RetractionReason string
+
+	// The go directive of the version to update to (Wanted when constrained),
+	// and whether it is newer than the one in the requiring go.mod
+	GoVersion string
+	NewerGo   bool
+
+	// The replacement of a module replaced by another one, such as a fork,
+	// e.g. "github.com/fork/a v1.0.1"; Latest is still the original's
+	ReplacedBy string
+
+	// With --impact, the requirements a direct update would add to go.mod or
+	// raise; ImpactChecked is false when it wasn't previewed
+	Impact        []modfile.RequireChange
+	ImpactChecked bool
+
+	// With --with-vulns, the vulnerabilities affecting the installed version;
+	// VulnsChecked is false when there was no scan
+	Vulns        []*vulndb.Vulnerability
+	VulnsChecked bool
+
+	CurrentTime time.Time
+	LatestTime  time.Time
+	Computed    []string // values of computed columns
+}
+
+// Libyears is how far the installed version lags the latest release, in
+// years between their release dates, or -1 when either date is unknown
+func (p Package) Libyears() float64 {
+	if p.CurrentTime.IsZero() || p.LatestTime.IsZero() {
+		return -1
+	}
+	return max(p.LatestTime.Sub(p.CurrentTime).Hours()/24/365.25, 0)
+}
+
+// Failure records a module whose latest version couldn't be looked up
+type Failure struct {
+	Module string
+	Err    error
+}
+
+// report is the outcome of checking one go.mod. Lookups that fail don't stop
+// the run; the modules are listed in Failures instead.
+type report struct {
+	Packages []Package
+	UpToDate []Package // already at the latest version but deprecated or retracted, for the notices
+	Failures []Failure
+	Ignored  []config.ModuleRule   // matched ignore rules, Module set to the module path
+	Replaced []modfile.Replacement // replace directives applying to the modules checked or skipped as local
+	Checked  int
+	CachedAt time.Time // lookup time of the oldest cached result used, zero if none
 }
 
 // Run executes the outdated command
 func Run(ctx context.Context, opts Options) error {
 
-	parser, err := modfile.NewParser(opts.ModPath)
+	var filter *expr.Expr
+	if opts.Filter != "" {
+		f, err := expr.Parse(opts.Filter)
+		if err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+		filter = f
+	}
+
+	modules, err := pattern.ParseFilter(opts.Include, opts.Exclude)
 	if err != nil {
-		return fmt.Errorf("parsing go.mod: %w", err)
+		return fmt.Errorf("invalid filter: %w", err)
 	}
 
-	proxyClient := proxy.NewClient("")
+	order, err := ParseOrder(opts.Sort)
+	if err != nil {
+		return err
+	}
 
-	var requires []*xmodfile.Require
-	if opts.DirectOnly {
-		requires = parser.DirectRequires()
-	} else {
-		requires = parser.AllRequires()
+	selected, err := ParseColumns(opts.TableColumns)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(selected, "vulns") && !opts.WithVulns {
+		return fmt.Errorf("--show vulns needs --with-vulns")
 	}
 
-	if len(requires) == 0 {
-		fmt.Println("No dependencies found")
-		return nil
+	var columns []Column
+	for _, spec := range opts.Columns {
+		col, err := ParseColumn(spec)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, col)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	maxAge := opts.MaxCacheAge
+	if maxAge < 0 {
+		maxAge = cfg.MaxCacheAge
+	}
+	if opts.Refresh {
+		maxAge = 0
+	}
+	var porcelain *ui.Porcelain
+	if opts.Format == FormatPorcelain {
+		porcelain = ui.StartPorcelain("outdated")
+	}
+
+	lk := newLookups(cfg, maxAge, opts.Pre)
+	defer lk.save()
+
+	var scanner *vulndb.Scanner
+	if opts.WithVulns {
+		if scanner, err = vulndb.NewScanner(); err != nil {
+			return fmt.Errorf("creating scanner: %w (drop --with-vulns to skip)", err)
+		}
+	}
+
+	if opts.Recursive {
+		ws, err := workspace.Scan(filepath.Dir(opts.ModPath))
+		if err != nil {
+			return fmt.Errorf("finding modules: %w", err)
+		}
+		return runWorkspace(ctx, opts, ws, cfg, lk, scanner, porcelain, modules, filter, selected, columns, order)
+	}
+	if opts.Workspace != "" {
+		ws, err := workspace.Load(opts.Workspace)
+		if err != nil {
+			return fmt.Errorf("loading workspace: %w", err)
+		}
+		return runWorkspace(ctx, opts, ws, cfg, lk, scanner, porcelain, modules, filter, selected, columns, order)
 	}
 
-	packages, err := fetchPackagesWithSpinner(ctx, proxyClient, requires, opts)
+	rep, err := outdatedPackages(ctx, opts, opts.ModPath, cfg, lk, scanner, modules, filter, columns)
 	if err != nil {
-		return fmt.Errorf("fetching packages: %w", err)
+		return err
 	}
+	order.Apply(rep.Packages)
 
+	switch opts.Format {
+	case FormatMarkdown:
+		fmt.Print(markdownReport(rep, selected, columns, modules != nil, opts.ShowReplaces))
+		return exitError(opts, rep.Packages, len(rep.Failures))
+	case FormatCSV:
+		warnFailures(rep.Failures)
+		if err := writeCSV(os.Stdout, csvRows("", rep.Packages), columns, false); err != nil {
+			return err
+		}
+		return exitError(opts, rep.Packages, len(rep.Failures))
+	case FormatPlain:
+		warnFailures(rep.Failures)
+		if err := writePlain(os.Stdout, csvRows("", rep.Packages), shownColumns(selected, rep.Packages), columns, false); err != nil {
+			return err
+		}
+		return exitError(opts, rep.Packages, len(rep.Failures))
+	case FormatPorcelain:
+		writePorcelain(porcelain, csvRows("", rep.Packages))
+		writePorcelainFailures(porcelain, "", rep.Failures)
+		return exitError(opts, rep.Packages, len(rep.Failures))
+	}
+
+	if rep.Checked == 0 {
+		switch {
+		case len(rep.Ignored) > 0:
+			fmt.Println("✨ No dependencies to check besides ignored ones")
+			printReplaced(rep.Replaced, opts.ShowReplaces)
+			printIgnored(rep.Ignored)
+		case len(rep.Replaced) > 0:
+			fmt.Println("✨ No dependencies to check besides local replacements")
+			printReplaced(rep.Replaced, opts.ShowReplaces)
+		case modules != nil:
+			fmt.Println("No dependencies match the module filter")
+		default:
+			fmt.Println("No dependencies found")
+		}
+		return nil
+	}
+
+	packages := rep.Packages
+	note := ""
+	if n := cachedNote(rep.CachedAt); n != "" {
+		note = " " + ui.UpToDateStyle.Render(n)
+	}
 	if len(packages) == 0 {
-		fmt.Println("✨ All packages are up to date!")
+		fmt.Println(upToDateMessage(rep.Failures) + note)
+		if len(rep.UpToDate) > 0 {
+			fmt.Println()
+			printNotices(nil, rep.UpToDate)
+		}
+	} else {
+		renderGroupedTables(packages, selected, columns)
+		printNotices(packages, rep.UpToDate)
+
+		fmt.Printf("\n%s %s%s\n", ui.SummaryStyle.Render("📊 Summary:"), summarize(packages, true), note)
+		if total, ok := libyearsSummary(packages); ok {
+			fmt.Printf("%s %s\n", ui.SummaryStyle.Render("⏳ Total age:"), total)
+		}
+	}
+
+	printFailures(rep.Failures, rep.Checked)
+	printReplaced(rep.Replaced, opts.ShowReplaces)
+	printIgnored(rep.Ignored)
+
+	if len(packages) > 0 {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run `gx update -i` to choose which packages to update"))
+	}
+
+	return exitError(opts, packages, len(rep.Failures))
+}
+
+// runWorkspace reports outdated packages for every module in a go.work
+// workspace, or found by --recursive
+func runWorkspace(ctx context.Context, opts Options, ws *workspace.Workspace, cfg *config.Config, lk *lookups, scanner *vulndb.Scanner, porcelain *ui.Porcelain, modules *pattern.Filter, filter *expr.Expr, selected []string, columns []Column, order Order) error {
+	reports, err := checkMembers(ctx, opts, ws.Members, cfg, lk, scanner, modules, filter, columns)
+	if err != nil {
+		return err
+	}
+
+	var all []Package
+	var cachedAt time.Time
+	modulesWithUpdates, failed := 0, 0
+	markdown := opts.Format == FormatMarkdown
+	// CSV and plain output print the rows of every member at the end,
+	// porcelain records are written as each member is done
+	rowsOut := opts.Format == FormatCSV || opts.Format == FormatPlain || porcelain != nil
+	var rows []csvRow
+
+	for i, member := range ws.Members {
+		rep := reports[i]
+		if markdown {
+			fmt.Printf("## %s (%s)\n\n", ui.MarkdownCode(member.ModulePath), ui.MarkdownEscape(member.Dir))
+		} else if !rowsOut {
+			fmt.Printf("\n%s %s\n", ui.HeaderStyle.Render("🗂  "+member.ModulePath), ui.UpToDateStyle.Render("("+member.Dir+")"))
+		}
+		failed += len(rep.Failures)
+		cachedAt = oldest(cachedAt, rep.CachedAt)
+
+		packages := rep.Packages
+		order.Apply(packages)
+		if markdown {
+			fmt.Print(markdownReport(rep, selected, columns, modules != nil, opts.ShowReplaces) + "\n")
+		}
+		if porcelain != nil {
+			writePorcelain(porcelain, csvRows(member.ModulePath, packages))
+			writePorcelainFailures(porcelain, member.ModulePath, rep.Failures)
+		} else if rowsOut {
+			warnFailures(rep.Failures)
+			rows = append(rows, csvRows(member.ModulePath, packages)...)
+		}
+		if len(packages) > 0 {
+			all = append(all, packages...)
+			modulesWithUpdates++
+		}
+		if markdown || rowsOut {
+			continue
+		}
+
+		if len(packages) == 0 {
+			fmt.Println("\n" + upToDateMessage(rep.Failures))
+			if len(rep.UpToDate) > 0 {
+				fmt.Println()
+				printNotices(nil, rep.UpToDate)
+			}
+			printFailures(rep.Failures, rep.Checked)
+			printReplaced(rep.Replaced, opts.ShowReplaces)
+			printIgnored(rep.Ignored)
+			continue
+		}
+
+		renderGroupedTables(packages, selected, columns)
+		printNotices(packages, rep.UpToDate)
+		fmt.Printf("%s\n", summarize(packages, true))
+		if total, ok := libyearsSummary(packages); ok {
+			fmt.Printf("%s\n", total)
+		}
+		printFailures(rep.Failures, rep.Checked)
+		printReplaced(rep.Replaced, opts.ShowReplaces)
+		printIgnored(rep.Ignored)
+	}
+
+	if porcelain != nil {
+		return exitError(opts, all, failed)
+	}
+	if opts.Format == FormatPlain {
+		if err := writePlain(os.Stdout, rows, shownColumns(selected, all), columns, true); err != nil {
+			return err
+		}
+		return exitError(opts, all, failed)
+	}
+	if opts.Format == FormatCSV {
+		if err := writeCSV(os.Stdout, rows, columns, true); err != nil {
+			return err
+		}
+		return exitError(opts, all, failed)
+	}
+
+	if markdown {
+		fmt.Printf("**📊 Workspace summary:** %s in %s of %s modules%s\n",
+			summarize(all, false), ui.FormatCount(modulesWithUpdates), ui.FormatCount(len(ws.Members)), withSpace(cachedNote(cachedAt)))
+		if total, ok := libyearsSummary(all); ok {
+			fmt.Printf("\n**⏳ Total age:** %s\n", total)
+		}
+		return exitError(opts, all, failed)
+	}
+
+	note := ""
+	if n := cachedNote(cachedAt); n != "" {
+		note = " " + ui.UpToDateStyle.Render(n)
+	}
+	fmt.Printf("\n%s %s in %s of %s modules%s\n",
+		ui.SummaryStyle.Render("📊 Workspace summary:"), summarize(all, true),
+		ui.FormatCount(modulesWithUpdates), ui.FormatCount(len(ws.Members)), note)
+	if total, ok := libyearsSummary(all); ok {
+		fmt.Printf("%s %s\n", ui.SummaryStyle.Render("⏳ Total age:"), total)
+	}
+
+	if len(all) > 0 {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run `gx update -i` to choose which packages to update"))
+	}
+
+	return exitError(opts, all, failed)
+}
+
+// outdatedPackages returns the packages in one go.mod with an available
+// update, and how many requirements were checked. With a scanner, the
+// module is scanned for vulnerabilities during the lookups.
+func outdatedPackages(ctx context.Context, opts Options, modPath string, cfg *config.Config, lk *lookups, scanner *vulndb.Scanner, modules *pattern.Filter, filter *expr.Expr, columns []Column) (*report, error) {
+	check, err := prepareCheck(opts, modPath, cfg, modules)
+	if err != nil {
+		return nil, err
+	}
+	lk.client.WithLocalModules(check.local)
+
+	if len(check.requires) == 0 {
+		return &report{Ignored: check.ignored, Replaced: check.replaced}, nil
+	}
+
+	var scans []*vulnScan
+	if scanner != nil {
+		scans = startVulnScans(ctx, scanner, []string{modPath})
+	}
+
+	rep, cached := lk.cachedReport(check, opts)
+	if !cached {
+		if opts.Offline {
+			return nil, errNoSavedReport
+		}
+		rep, err = fetchPackagesWithSpinner(ctx, lk, check, opts)
+		if err != nil {
+			return nil, fmt.Errorf("fetching packages: %w", err)
+		}
+		lk.saveReport(check, opts, rep)
+	}
+
+	// Vulnerabilities are never saved with the report
+	if scans != nil {
+		vulns, err := scans[0].wait()
+		if err != nil {
+			return nil, err
+		}
+		withVulns(rep.Packages, vulns)
+	}
+	return check.finish(rep, filter, columns)
+}
+
+// checkMembers checks several go.mod files at once, so a module required by
+// more than one of them is looked up once
+func checkMembers(ctx context.Context, opts Options, members []workspace.Member, cfg *config.Config, lk *lookups, scanner *vulndb.Scanner, modules *pattern.Filter, filter *expr.Expr, columns []Column) ([]*report, error) {
+	checks := make([]*moduleCheck, len(members))
+	for i, member := range members {
+		check, err := prepareCheck(opts, member.ModPath, cfg, modules)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Dir, err)
+		}
+		checks[i] = check
+	}
+
+	var scans []*vulnScan
+	if scanner != nil {
+		modPaths := make([]string, len(members))
+		for i, member := range members {
+			modPaths[i] = member.ModPath
+		}
+		scans = startVulnScans(ctx, scanner, modPaths)
+	}
+
+	// Members whose saved report is recent enough aren't looked up again
+	reports := make([]*report, len(checks))
+	var pending []*moduleCheck
+	var pendingAt []int
+	for i, check := range checks {
+		if rep, ok := lk.cachedReport(check, opts); ok {
+			reports[i] = rep
+			continue
+		}
+		if opts.Offline {
+			return nil, fmt.Errorf("%s: %w", members[i].Dir, errNoSavedReport)
+		}
+		pending = append(pending, check)
+		pendingAt = append(pendingAt, i)
+	}
+
+	// The client's local modules stay unset: a module replaced in one member
+	// may come from the proxy in another, and prepareCheck already left out
+	// each member's own local replacements
+	if len(pending) > 0 {
+		fetched, err := fetchModulesWithSpinner(ctx, lk, pending, opts)
+		if err != nil {
+			return nil, fmt.Errorf("fetching packages: %w", err)
+		}
+		for j, rep := range fetched {
+			reports[pendingAt[j]] = rep
+			lk.saveReport(pending[j], opts, rep)
+		}
+	}
+
+	var err error
+	for i, check := range checks {
+		if scans != nil {
+			vulns, err := scans[i].wait()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", members[i].Dir, err)
+			}
+			withVulns(reports[i].Packages, vulns)
+		}
+		if reports[i], err = check.finish(reports[i], filter, columns); err != nil {
+			return nil, fmt.Errorf("%s: %w", members[i].Dir, err)
+		}
+	}
+	return reports, nil
+}
+
+// moduleCheck is what to look up for one go.mod
+type moduleCheck struct {
+	requires  []*xmodfile.Require
+	ignored   []config.ModuleRule
+	have      []*xmodfile.Require   // every requirement, to preview update impact against
+	local     []string              // modules replaced by local directories
+	replaced  []modfile.Replacement // replacements of the candidate modules
+	goVersion string                // the go.mod's go directive
+	modSum    string                // checksum of the go.mod, keying saved reports
+}
+
+// prepareCheck picks the requirements of one go.mod to look up
+func prepareCheck(opts Options, modPath string, cfg *config.Config, modules *pattern.Filter) (*moduleCheck, error) {
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	// Look up nothing for modules replaced by local directories, not even
+	// in the cache
+	check := &moduleCheck{
+		have:      parser.AllRequires(),
+		local:     parser.LocalReplacements(),
+		goVersion: parser.GoVersion(),
+		modSum:    checksum(parser.Data()),
+	}
+	replacements := make(map[string]modfile.Replacement)
+	for _, r := range parser.Replacements() {
+		replacements[r.Path] = r
+	}
+
+	var candidates []*xmodfile.Require
+	switch {
+	case opts.DirectOnly:
+		candidates = parser.DirectRequires()
+	case opts.IndirectOnly:
+		candidates = parser.IndirectRequires()
+	default:
+		candidates = parser.AllRequires()
+	}
+
+	for _, req := range candidates {
+		if !modules.Matches(req.Mod.Path) {
+			continue
+		}
+		if r, ok := replacements[req.Mod.Path]; ok {
+			check.replaced = append(check.replaced, r)
+			if r.Local {
+				ui.Debug("skipping %s: replaced by a local directory", req.Mod.Path)
+				continue
+			}
+		}
+		// Ignored modules aren't looked up, so they never reach the summary
+		if rule := cfg.IgnoreRuleFor(req.Mod.Path); rule != nil {
+			check.ignored = append(check.ignored, config.ModuleRule{Module: req.Mod.Path, Reason: rule.Reason})
+			continue
+		}
+		check.requires = append(check.requires, req)
+	}
+
+	return check, nil
+}
+
+// finish applies the filter and computed columns to a check's results
+func (c *moduleCheck) finish(rep *report, filter *expr.Expr, columns []Column) (*report, error) {
+	replacedBy := make(map[string]string, len(c.replaced))
+	for _, r := range c.replaced {
+		replacedBy[r.Path] = r.String()
+	}
+	for i, pkg := range rep.Packages {
+		rep.Packages[i].NewerGo = pkg.GoVersion != "" && c.goVersion != "" && versions.CompareGo(pkg.GoVersion, c.goVersion) > 0
+		rep.Packages[i].ReplacedBy = replacedBy[pkg.Name]
+	}
+
+	var err error
+	rep.Packages, err = applyExpressions(rep.Packages, filter, columns)
+	if err != nil {
+		return nil, err
+	}
+	if rep.UpToDate, err = applyExpressions(rep.UpToDate, filter, columns); err != nil {
+		return nil, err
+	}
+
+	rep.Checked = len(c.requires)
+	rep.Ignored = c.ignored
+	rep.Replaced = c.replaced
+	return rep, nil
+}
+
+// onlyUpdateType is the update type selected by --major-only, --minor-only
+// or --patch-only, or "" to show all of them
+func (o Options) onlyUpdateType() string {
+	switch {
+	case o.MajorOnly:
+		return versions.Major
+	case o.MinorOnly:
+		return versions.Minor
+	case o.PatchOnly:
+		return versions.Patch
+	}
+	return ""
+}
+
+// upToDateMessage reports that nothing needs updating, qualified when some
+// modules couldn't be checked
+func upToDateMessage(failures []Failure) string {
+	if len(failures) > 0 {
+		return "✨ All checked packages are up to date"
+	}
+	return "✨ All packages are up to date!"
+}
+
+// printFailures lists the modules whose lookups failed
+func printFailures(failures []Failure, checked int) {
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", ui.MinorStyle.Render(fmt.Sprintf("⚠️  Could not resolve %s of %s modules:", ui.FormatCount(len(failures)), ui.FormatCount(checked))))
+	width := 0
+	for _, f := range failures {
+		width = max(width, len(f.Module))
+	}
+	for _, f := range failures {
+		reason, _, _ := strings.Cut(f.Err.Error(), "\n")
+		fmt.Printf("  %-*s  %s\n", width, f.Module, ui.UpToDateStyle.Render(ui.TruncateString(reason, 100)))
+	}
+}
+
+// printNotices details, after the tables, which end with a blank line: with
+// --with-vulns the vulnerabilities of installed versions, the retracted
+// versions, deprecated modules and replaced modules marked in the tables,
+// the updates needing a newer Go than go.mod declares and, with --impact,
+// what direct updates pull in. Retracted versions and deprecated modules of
+// the up to date packages are listed too.
+func printNotices(packages, upToDate []Package) {
+	vulnerable, retracted, deprecated, replaced, newerGo, impact := withNotices(packages, upToDate)
+
+	if len(vulnerable) > 0 {
+		fmt.Printf("%s\n", ui.HighStyle.Render(fmt.Sprintf("⚠️  Vulnerable installed versions (%s):", ui.FormatCount(len(vulnerable)))))
+		width := 0
+		for _, pkg := range vulnerable {
+			width = max(width, len(pkg.Name)+len(pkg.Current)+2)
+		}
+		for _, pkg := range vulnerable {
+			for i, v := range pkg.Vulns {
+				name := ""
+				if i == 0 {
+					name = pkg.Name + " v" + pkg.Current
+				}
+				fmt.Printf("  %-*s  %s\n", width, name, ui.UpToDateStyle.Render(vulnNote(pkg, v)))
+			}
+		}
+	}
+
+	if len(retracted) > 0 {
+		if len(vulnerable) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", ui.HighStyle.Render(fmt.Sprintf("%s Retracted versions in use (%s):", ui.RetractedSymbol, ui.FormatCount(len(retracted)))))
+		width := 0
+		for _, pkg := range retracted {
+			width = max(width, len(pkg.Name)+len(pkg.Current)+2)
+		}
+		for _, pkg := range retracted {
+			fmt.Printf("  %-*s  %s\n", width, pkg.Name+" v"+pkg.Current, ui.UpToDateStyle.Render(retractionReason(pkg)))
+		}
+	}
+
+	if len(deprecated) > 0 {
+		if len(vulnerable) > 0 || len(retracted) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", ui.MediumStyle.Render(fmt.Sprintf("%s Deprecated modules (%s):", ui.DeprecatedSymbol, ui.FormatCount(len(deprecated)))))
+		width := 0
+		for _, pkg := range deprecated {
+			width = max(width, len(pkg.Name))
+		}
+		for _, pkg := range deprecated {
+			fmt.Printf("  %-*s  %s\n", width, pkg.Name, ui.UpToDateStyle.Render(oneLine(pkg.Deprecated)))
+		}
+	}
+
+	if len(replaced) > 0 {
+		if len(vulnerable) > 0 || len(retracted) > 0 || len(deprecated) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", ui.UpToDateStyle.Render(fmt.Sprintf("%s Replaced modules, showing the original's latest version (%s):", ui.ReplacedSymbol, ui.FormatCount(len(replaced)))))
+		width := 0
+		for _, pkg := range replaced {
+			width = max(width, len(pkg.Name))
+		}
+		for _, pkg := range replaced {
+			fmt.Printf("  %-*s  %s\n", width, pkg.Name, ui.UpToDateStyle.Render("=> "+pkg.ReplacedBy))
+		}
+	}
+
+	if len(newerGo) > 0 {
+		if len(vulnerable) > 0 || len(retracted) > 0 || len(deprecated) > 0 || len(replaced) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", ui.MinorStyle.Render(fmt.Sprintf("⚠️  Updates needing a newer Go than go.mod declares (%s):", ui.FormatCount(len(newerGo)))))
+		width := 0
+		for _, pkg := range newerGo {
+			width = max(width, len(pkg.Name)+len(targetVersion(pkg))+2)
+		}
+		for _, pkg := range newerGo {
+			fmt.Printf("  %-*s  %s\n", width, pkg.Name+" v"+targetVersion(pkg), ui.UpToDateStyle.Render("requires go "+pkg.GoVersion))
+		}
+	}
+
+	if len(impact) > 0 {
+		if len(vulnerable) > 0 || len(retracted) > 0 || len(deprecated) > 0 || len(replaced) > 0 || len(newerGo) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", ui.SummaryStyle.Render(fmt.Sprintf("📈 Requirements pulled in by direct updates (%s):", ui.FormatCount(len(impact)))))
+		width := 0
+		for _, pkg := range impact {
+			width = max(width, len(pkg.Name)+len(targetVersion(pkg))+2)
+		}
+		for _, pkg := range impact {
+			fmt.Printf("  %-*s  %s\n", width, pkg.Name+" v"+targetVersion(pkg), ui.UpToDateStyle.Render(impactSummary(pkg)))
+			if ui.IsVerbose() {
+				for _, c := range pkg.Impact {
+					fmt.Printf("    %s\n", ui.UpToDateStyle.Render(impactChange(c)))
+				}
+			}
+		}
+	}
+
+	if k := kubernetesUpgrade(packages); k != nil {
+		if len(vulnerable) > 0 || len(retracted) > 0 || len(deprecated) > 0 || len(replaced) > 0 || len(newerGo) > 0 || len(impact) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s\n", ui.SummaryStyle.Render("☸️  Kubernetes "+k.String()+":"), ui.UpToDateStyle.Render(strings.Join(k.Modules, ", ")))
+	}
+}
+
+// kubernetesChange is how updating the Kubernetes staging modules moves the
+// Kubernetes release they belong to
+type kubernetesChange struct {
+	From    []string // releases of the installed versions, more than one when mismatched
+	To      []string
+	Modules []string
+}
+
+// String returns e.g. "1.29 → 1.31"
+func (k *kubernetesChange) String() string {
+	from := strings.Join(k.From, ", ")
+	if len(k.From) > 1 {
+		from += " (mismatched)"
+	}
+	return from + " → " + strings.Join(k.To, ", ")
+}
+
+// kubernetesUpgrade maps the updates of Kubernetes staging modules, versioned
+// v0.X.Y for Kubernetes 1.X.Y, to Kubernetes releases. It returns nil when
+// none of them has an update.
+func kubernetesUpgrade(packages []Package) *kubernetesChange {
+	k := &kubernetesChange{}
+	for _, pkg := range packages {
+		if !versions.IsKubernetesStaging(pkg.Name) || (pkg.Constraint != "" && pkg.Wanted == "") {
+			continue
+		}
+		from, ok := versions.KubernetesRelease("v" + pkg.Current)
+		to, ok2 := versions.KubernetesRelease("v" + targetVersion(pkg))
+		if !ok || !ok2 {
+			continue
+		}
+		if !slices.Contains(k.From, from) {
+			k.From = append(k.From, from)
+		}
+		if !slices.Contains(k.To, to) {
+			k.To = append(k.To, to)
+		}
+		k.Modules = append(k.Modules, pkg.Name)
+	}
+	if len(k.Modules) == 0 {
 		return nil
 	}
 
-	var directPkgs, indirectPkgs []Package
+	slices.SortFunc(k.From, versions.CompareGo)
+	slices.SortFunc(k.To, versions.CompareGo)
+	slices.Sort(k.Modules)
+	return k
+}
+
+// withNotices picks out the packages with a vulnerable or retracted
+// installed version, those whose module is deprecated or replaced by
+// another, those needing a newer Go and those whose update impact was
+// previewed, adding the up to date packages that are retracted or
+// deprecated
+func withNotices(packages, upToDate []Package) (vulnerable, retracted, deprecated, replaced, newerGo, impact []Package) {
 	for _, pkg := range packages {
-		if pkg.Direct {
-			directPkgs = append(directPkgs, pkg)
+		if len(pkg.Vulns) > 0 {
+			vulnerable = append(vulnerable, pkg)
+		}
+		if pkg.Retracted != "" {
+			retracted = append(retracted, pkg)
+		}
+		if pkg.Deprecated != "" {
+			deprecated = append(deprecated, pkg)
+		}
+		if pkg.ReplacedBy != "" {
+			replaced = append(replaced, pkg)
+		}
+		if pkg.NewerGo {
+			newerGo = append(newerGo, pkg)
+		}
+		if pkg.ImpactChecked {
+			impact = append(impact, pkg)
+		}
+	}
+	for _, pkg := range upToDate {
+		if pkg.Retracted != "" {
+			retracted = append(retracted, pkg)
+		}
+		if pkg.Deprecated != "" {
+			deprecated = append(deprecated, pkg)
+		}
+	}
+	return vulnerable, retracted, deprecated, replaced, newerGo, impact
+}
+
+// impactCounts returns how many requirements a previewed update adds to
+// go.mod and how many it raises
+func (p Package) impactCounts() (added, raised int) {
+	for _, c := range p.Impact {
+		if c.Kind() == "added" {
+			added++
 		} else {
-			indirectPkgs = append(indirectPkgs, pkg)
+			raised++
 		}
 	}
+	return added, raised
+}
 
-	renderGroupedTables(directPkgs, indirectPkgs)
+// impactSummary describes a previewed update's impact, e.g. "3 new, 2 raised"
+func impactSummary(pkg Package) string {
+	added, raised := pkg.impactCounts()
+	if added == 0 && raised == 0 {
+		return "no new or raised requirements"
+	}
+	return fmt.Sprintf("%s new, %s raised", ui.FormatCount(added), ui.FormatCount(raised))
+}
 
-	return nil
+// impactChange describes one requirement change, e.g. "+ example.com/a v1.0.0"
+func impactChange(c modfile.RequireChange) string {
+	if c.Kind() == "added" {
+		return "+ " + c.Path + " " + c.To
+	}
+	return "↑ " + c.Path + " " + c.From + " → " + c.To
 }
 
-// classifyUpdate determines the type of update (major, minor, patch, none)
-func classifyUpdate(current, latest string) string {
-	if semver.Compare(current, latest) >= 0 {
-		return "none"
+// targetVersion is the version an update moves to: the newest the
+// constraint allows, or the latest
+func targetVersion(pkg Package) string {
+	if pkg.Wanted != "" {
+		return pkg.Wanted
+	}
+	return pkg.Latest
+}
+
+// noticeMarks returns the marks appended to a package's name, e.g. " ✗"
+func noticeMarks(pkg Package) string {
+	marks := ""
+	if pkg.Retracted != "" {
+		marks += ui.RetractedSymbol
+	}
+	if pkg.Deprecated != "" {
+		marks += ui.DeprecatedSymbol
+	}
+	if pkg.ReplacedBy != "" {
+		marks += ui.ReplacedSymbol
+	}
+	return withSpace(marks)
+}
+
+func retractionReason(pkg Package) string {
+	if pkg.RetractionReason == "" {
+		return "retracted " + pkg.Retracted + ", no reason given"
+	}
+	return oneLine(pkg.RetractionReason)
+}
+
+// oneLine joins a multi-line notice into a single line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// printReplaced notes the modules skipped because they're replaced by local
+// directories, or with --show-replaces lists every replacement
+func printReplaced(replaced []modfile.Replacement, show bool) {
+	local := localCount(replaced)
+	if !show {
+		if local > 0 {
+			fmt.Printf("\n%s\n", ui.UpToDateStyle.Render(fmt.Sprintf("%s module(s) replaced by local directories not checked; run with --show-replaces to list replacements", ui.FormatCount(local))))
+		}
+		return
+	}
+	if len(replaced) == 0 {
+		return
 	}
 
-	currentMajor := semver.Major(current)
-	latestMajor := semver.Major(latest)
+	fmt.Printf("\n%s\n", ui.SummaryStyle.Render(fmt.Sprintf("%s Replaced modules (%s):", ui.ReplacedSymbol, ui.FormatCount(len(replaced)))))
+	width, newWidth := 0, 0
+	for _, r := range replaced {
+		width = max(width, len(r.Path)+len(r.Version)+1)
+		newWidth = max(newWidth, len(r.String()))
+	}
+	for _, r := range replaced {
+		fmt.Printf("  %-*s  => %-*s  %s\n", width, r.Path+" "+r.Version, newWidth, r.String(), ui.UpToDateStyle.Render(replacementKind(r)))
+	}
+}
 
-	if currentMajor != latestMajor {
-		return "major"
+// replacementKind explains how a replaced module is checked
+func replacementKind(r modfile.Replacement) string {
+	if r.Local {
+		return "local directory, not checked"
 	}
+	return "module, checked against the original"
+}
 
-	currentParts := strings.Split(strings.TrimPrefix(current, currentMajor+"."), ".")
-	latestParts := strings.Split(strings.TrimPrefix(latest, latestMajor+"."), ".")
+func localCount(replaced []modfile.Replacement) int {
+	n := 0
+	for _, r := range replaced {
+		if r.Local {
+			n++
+		}
+	}
+	return n
+}
+
+// printIgnored notes the modules skipped by ignore rules, listing them with
+// their reasons in verbose mode
+func printIgnored(ignored []config.ModuleRule) {
+	if len(ignored) == 0 {
+		return
+	}
 
-	if len(currentParts) > 0 && len(latestParts) > 0 && currentParts[0] != latestParts[0] {
-		return "minor"
+	if !ui.IsVerbose() {
+		fmt.Printf("\n%s\n", ui.UpToDateStyle.Render(fmt.Sprintf("%s module(s) ignored by config; run with -v to list them", ui.FormatCount(len(ignored)))))
+		return
 	}
 
-	return "patch"
+	fmt.Printf("\n%s\n", ui.UpToDateStyle.Render(fmt.Sprintf("🙈 Ignored by config (%s):", ui.FormatCount(len(ignored)))))
+	width := 0
+	for _, r := range ignored {
+		width = max(width, len(r.Module))
+	}
+	for _, r := range ignored {
+		reason := r.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Printf("  %-*s  %s\n", width, r.Module, ui.UpToDateStyle.Render(reason))
+	}
 }
 
-// renderGroupedTables renders packages grouped by direct/indirect
-func renderGroupedTables(directPkgs, indirectPkgs []Package) {
+// failOnRank orders --fail-on levels; an update fails the run when its type
+// ranks at or above the chosen level
+var failOnRank = map[string]int{
+	"any":          1,
+	versions.Patch: 1,
+	versions.Minor: 2,
+	versions.Major: 3,
+}
+
+// FailOnLevels lists the accepted --fail-on values
+var FailOnLevels = []string{versions.Major, versions.Minor, versions.Patch, "any"}
+
+// exitError decides the exit status once results are printed: updates at or
+// above --fail-on come first, then unchecked modules under --strict
+func exitError(opts Options, packages []Package, failed int) error {
+	if opts.FailOn != "" {
+		matching := 0
+		for _, pkg := range packages {
+			if rank, ok := failOnRank[pkg.UpdateType]; ok && rank >= failOnRank[opts.FailOn] {
+				matching++
+			}
+		}
+		if matching > 0 {
+			return fmt.Errorf("%d package(s) have %supdates available", matching, failOnLabel(opts.FailOn))
+		}
+	}
+
+	if opts.Strict && failed > 0 {
+		return fmt.Errorf("%d module(s) could not be checked", failed)
+	}
+	return nil
+}
+
+func failOnLabel(level string) string {
+	switch level {
+	case "any":
+		return ""
+	case versions.Major:
+		return "major "
+	}
+	return level + " or larger "
+}
+
+// renderGroupedTables renders packages grouped by direct/indirect, with the
+// columns selected by --show or the defaults
+func renderGroupedTables(packages []Package, selected []string, columns []Column) {
 	maxNameWidth := 45
 
+	directPkgs, indirectPkgs := splitDirect(packages)
+	shown := shownColumns(selected, packages)
+
 	if len(directPkgs) > 0 {
 		fmt.Println(ui.DirectHeaderStyle.Render("\n📦 Direct Dependencies"))
 		fmt.Println()
-		renderPackageTable(directPkgs, maxNameWidth)
+		renderPackageTable(directPkgs, shown, columns, maxNameWidth)
 	}
 
 	if len(indirectPkgs) > 0 {
 		fmt.Println(ui.IndirectHeaderStyle.Render("\n🔗 Indirect Dependencies"))
 		fmt.Println()
-		renderPackageTable(indirectPkgs, maxNameWidth)
+		renderPackageTable(indirectPkgs, shown, columns, maxNameWidth)
 	}
+}
 
-	totalPkgs := len(directPkgs) + len(indirectPkgs)
+// splitDirect separates direct from indirect dependencies, keeping the order
+func splitDirect(packages []Package) (direct, indirect []Package) {
+	for _, pkg := range packages {
+		if pkg.Direct {
+			direct = append(direct, pkg)
+		} else {
+			indirect = append(indirect, pkg)
+		}
+	}
+	return direct, indirect
+}
+
+// summarize describes how many packages can be updated, by update type.
+// Unstyled, the symbols are left uncolored.
+func summarize(packages []Package, styled bool) string {
 	major, minor, patch := 0, 0, 0
-	for _, pkg := range append(directPkgs, indirectPkgs...) {
+	for _, pkg := range packages {
 		switch pkg.UpdateType {
 		case "major":
 			major++
@@ -127,73 +1052,140 @@ func renderGroupedTables(directPkgs, indirectPkgs []Package) {
 		}
 	}
 
-	fmt.Printf("\n%s ", ui.SummaryStyle.Render("📊 Summary:"))
-	fmt.Printf("%d package(s) can be updated", totalPkgs)
+	summary := fmt.Sprintf("%s package(s) can be updated", ui.FormatCount(len(packages)))
+
+	symbol := func(updateType string) string {
+		if !styled {
+			return ui.UpdateSymbol(updateType)
+		}
+		return ui.FormatVersionUpdate(updateType).Render(ui.UpdateSymbol(updateType))
+	}
 
 	var parts []string
 	if major > 0 {
-		parts = append(parts, fmt.Sprintf("%s %d major", ui.MajorStyle.Render("●"), major))
+		parts = append(parts, fmt.Sprintf("%s %s major", symbol(versions.Major), ui.FormatCount(major)))
 	}
 	if minor > 0 {
-		parts = append(parts, fmt.Sprintf("%s %d minor", ui.MinorStyle.Render("●"), minor))
+		parts = append(parts, fmt.Sprintf("%s %s minor", symbol(versions.Minor), ui.FormatCount(minor)))
 	}
 	if patch > 0 {
-		parts = append(parts, fmt.Sprintf("%s %d patch", ui.PatchStyle.Render("●"), patch))
+		parts = append(parts, fmt.Sprintf("%s %s patch", symbol(versions.Patch), ui.FormatCount(patch)))
 	}
 
 	if len(parts) > 0 {
-		fmt.Printf(" (%s)", strings.Join(parts, ", "))
+		summary += fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+	}
+	return summary
+}
+
+// libyearsSummary totals the libyear age of the packages with known release
+// dates
+func libyearsSummary(packages []Package) (string, bool) {
+	total, known := 0.0, 0
+	for _, pkg := range packages {
+		if y := pkg.Libyears(); y >= 0 {
+			total += y
+			known++
+		}
+	}
+	if known == 0 {
+		return "", false
+	}
+
+	summary := fmt.Sprintf("%.1f libyears behind the latest releases", total)
+	if known < len(packages) {
+		summary += fmt.Sprintf(" (%s of %s packages with release dates)", ui.FormatCount(known), ui.FormatCount(len(packages)))
+	}
+	return summary, true
+}
+
+// withSymbol prefixes an update type with its symbol, e.g. "▲ major"
+func withSymbol(updateType string) string {
+	if s := ui.UpdateSymbol(updateType); s != "" {
+		return s + " " + updateType
+	}
+	return updateType
+}
+
+// formatLibyears shows a package's libyear age, in days when under a month
+func formatLibyears(y float64) string {
+	switch {
+	case y < 0:
+		return "-"
+	case y < 1.0/12:
+		return fmt.Sprintf("%dd", int(y*365.25))
+	}
+	return fmt.Sprintf("%.1fy", y)
+}
+
+// anyConstrained reports whether any package has a version constraint, so
+// the tables need a Wanted column
+func anyConstrained(packages []Package) bool {
+	for _, pkg := range packages {
+		if pkg.Constraint != "" {
+			return true
+		}
 	}
-	fmt.Println()
+	return false
+}
 
-	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run `gx update -i` to choose which packages to update"))
+// wantedCell shows the newest version allowed by a package's constraint
+func wantedCell(pkg Package) string {
+	switch {
+	case pkg.Constraint == "":
+		return "-"
+	case pkg.Wanted == "":
+		return "none"
+	}
+	return pkg.Wanted
 }
 
-// renderPackageTable renders a table of packages
-func renderPackageTable(packages []Package, maxNameWidth int) {
+// renderPackageTable renders a table of packages with the shown built-in
+// columns, then the computed ones
+func renderPackageTable(packages []Package, shown []string, columns []Column, maxNameWidth int) {
 	if len(packages) == 0 {
 		return
 	}
 
-	table := ui.NewTable("Package", "Current", "Latest", "Update")
+	table := ui.NewTable(headers(shown, columns)...)
 
 	for _, pkg := range packages {
-		pkgName := ui.TruncateString(pkg.Name, maxNameWidth)
-
-		symbol := ""
-		switch pkg.UpdateType {
-		case "major":
-			symbol = "▲ "
-		case "minor":
-			symbol = "● "
-		case "patch":
-			symbol = "· "
+		row := make([]string, 0, len(shown)+len(pkg.Computed))
+		for _, c := range shown {
+			if c == "name" {
+				row = append(row, ui.TruncateString(pkg.Name, maxNameWidth)+noticeMarks(pkg))
+				continue
+			}
+			row = append(row, cell(c, pkg))
 		}
-
-		table.AddRow(
-			pkgName,
-			pkg.Current,
-			pkg.Latest,
-			symbol+pkg.UpdateType,
-		)
+		table.AddRow(append(row, pkg.Computed...)...)
 	}
 
 	output := table.RenderStyled(func(rowIdx, colIdx int, cell string) lipgloss.Style {
 		pkg := packages[rowIdx]
-
-		switch colIdx {
-		case 0:
+		if colIdx >= len(shown) {
 			return ui.CellStyle
+		}
 
-		case 1:
+		switch shown[colIdx] {
+		case "current":
 			return lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
 
-		case 2:
-			return ui.FormatVersionUpdate(pkg.UpdateType)
+		case "wanted":
+			if pkg.Wanted == "" {
+				return ui.UpToDateStyle
+			}
+			return ui.FormatVersionUpdate(versions.Classify("v"+pkg.Current, "v"+pkg.Wanted))
 
-		case 3:
+		case "latest", "update":
 			return ui.FormatVersionUpdate(pkg.UpdateType)
 
+		case "vulns":
+			if len(pkg.Vulns) > 0 {
+				return ui.HighStyle
+			}
+			return ui.UpToDateStyle
+
 		default:
 			return ui.CellStyle
 		}
@@ -201,4 +1193,3 @@ func renderPackageTable(packages []Package, maxNameWidth int) {
 
 	fmt.Println(output)
 }
-
@@ -0,0 +1,69 @@
+package outdated
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// FormatPlain is the --format for scripts: aligned columns without styling
+const FormatPlain = "plain"
+
+// writePlain writes packages as aligned columns of plain text under a header
+// of column names, without notices or a summary. Release dates are written
+// as dates. Workspace reports start each row with the member module.
+func writePlain(w io.Writer, rows []csvRow, shown []string, columns []Column, workspace bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := append([]string(nil), shown...)
+	for _, col := range columns {
+		header = append(header, col.Label)
+	}
+	if workspace {
+		header = append([]string{"workspace_module"}, header...)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, r := range rows {
+		row := make([]string, 0, len(header))
+		if workspace {
+			row = append(row, r.Member)
+		}
+		for _, c := range shown {
+			row = append(row, plainCell(c, r.Package))
+		}
+		row = append(row, r.Computed...)
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// plainCell renders a column like the terminal table, but with the update
+// type and vulnerability count unmarked and dates instead of relative times
+func plainCell(column string, pkg Package) string {
+	switch column {
+	case "vulns":
+		if !pkg.VulnsChecked {
+			return "-"
+		}
+		return strconv.Itoa(len(pkg.Vulns))
+	case "update":
+		return pkg.UpdateType
+	case "released":
+		return plainDate(pkg.LatestTime)
+	case "age":
+		return plainDate(pkg.CurrentTime)
+	}
+	return cell(column, pkg)
+}
+
+func plainDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,51 @@
+package outdated
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// FormatPorcelain is the output of --porcelain: records for scripts in the
+// versioned format of ui.Porcelain
+const FormatPorcelain = "porcelain"
+
+// writePorcelain writes a record per package:
+//
+//	package <member> <module> <current> <latest> <type> <direct|indirect> <release date> <wanted> <replaced by> <retracted> <deprecated> <vulns> [computed...]
+//
+// member is the workspace module requiring the package, empty outside a
+// workspace. Versions have their v prefix, the release date is YYYY-MM-DD,
+// retracted and deprecated are true or false, and vulns is empty without
+// --with-vulns.
+func writePorcelain(p *ui.Porcelain, rows []csvRow) {
+	for _, r := range rows {
+		scope := "indirect"
+		if r.Direct {
+			scope = "direct"
+		}
+		released := ""
+		if !r.LatestTime.IsZero() {
+			released = r.LatestTime.UTC().Format("2006-01-02")
+		}
+		vulns := ""
+		if r.VulnsChecked {
+			vulns = strconv.Itoa(len(r.Vulns))
+		}
+		fields := []string{r.Member, r.Name, "v" + r.Current, "v" + r.Latest, r.UpdateType, scope, released, withV(r.Wanted), r.ReplacedBy,
+			strconv.FormatBool(r.Retracted != ""), strconv.FormatBool(r.Deprecated != ""), vulns}
+		p.Record("package", append(fields, r.Computed...)...)
+	}
+}
+
+// writePorcelainFailures writes a record per module that couldn't be looked
+// up, with the first line of the error:
+//
+//	failed <member> <module> <reason>
+func writePorcelainFailures(p *ui.Porcelain, member string, failures []Failure) {
+	for _, f := range failures {
+		reason, _, _ := strings.Cut(f.Err.Error(), "\n")
+		p.Record("failed", member, f.Module, reason)
+	}
+}
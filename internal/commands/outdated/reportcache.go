@@ -0,0 +1,72 @@
+package outdated
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// reportFormat changes whenever cached reports can't be read back as before
+const reportFormat = 2
+
+// savedReport is a report as kept in the report cache. Failures aren't kept,
+// since only complete reports are saved.
+type savedReport struct {
+	Packages []Package
+	UpToDate []Package
+	CachedAt time.Time
+}
+
+// reportKey identifies the lookups made for one go.mod: its content, the
+// requirements checked and the options changing what is looked up
+func reportKey(check *moduleCheck, opts Options, lk *lookups) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "outdated %d %s pre=%t impact=%t only=%s\n", reportFormat, check.modSum, opts.Pre, opts.Impact, opts.onlyUpdateType())
+	for _, req := range check.requires {
+		b.WriteString(req.Mod.Path + " " + req.Mod.Version + " " + strconv.FormatBool(req.Indirect))
+		if c, ok := lk.constraintFor(req.Mod.Path); ok {
+			b.WriteString(" " + c.String())
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// cachedReport returns the saved report for a check when it is within the
+// max cache age, or whatever its age with --offline
+func (l *lookups) cachedReport(check *moduleCheck, opts Options) (*report, bool) {
+	if l.cache == nil || (l.maxAge <= 0 && !opts.Offline) {
+		return nil, false
+	}
+
+	var saved savedReport
+	at, ok := l.cache.Report(reportKey(check, opts, l), &saved)
+	if !ok || (!opts.Offline && time.Since(at) > l.maxAge) {
+		return nil, false
+	}
+	return &report{Packages: saved.Packages, UpToDate: saved.UpToDate, CachedAt: oldest(saved.CachedAt, at)}, true
+}
+
+// saveReport keeps a complete report for later runs and --offline
+func (l *lookups) saveReport(check *moduleCheck, opts Options, rep *report) {
+	if l.cache == nil || len(rep.Failures) > 0 {
+		return
+	}
+	if err := l.cache.SetReport(reportKey(check, opts, l), savedReport{Packages: rep.Packages, UpToDate: rep.UpToDate, CachedAt: rep.CachedAt}); err != nil {
+		ui.Debug("saving report cache: %v", err)
+	}
+}
+
+// errNoSavedReport is returned by --offline for a go.mod never checked online
+var errNoSavedReport = errors.New("no saved results for this go.mod; run gx outdated online first")
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package outdated
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortFields lists the accepted --sort fields
+var SortFields = []string{"name", "update-type", "age", "behind", "vulns"}
+
+// sortKey compares two packages by one field, ascending
+type sortKey func(a, b Package) int
+
+// updateRank orders update types from smallest to largest
+var updateRank = map[string]int{"patch": 1, "minor": 2, "major": 3}
+
+var sortKeys = map[string]sortKey{
+	"name": func(a, b Package) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	"update-type": func(a, b Package) int {
+		return updateRank[a.UpdateType] - updateRank[b.UpdateType]
+	},
+	// The installed version's age: an older release is a larger age
+	"age": func(a, b Package) int {
+		return compareKnown(!a.CurrentTime.IsZero(), !b.CurrentTime.IsZero(), func() int {
+			return b.CurrentTime.Compare(a.CurrentTime)
+		})
+	},
+	"vulns": func(a, b Package) int {
+		return compareKnown(a.VulnsChecked, b.VulnsChecked, func() int {
+			return len(a.Vulns) - len(b.Vulns)
+		})
+	},
+	"behind": func(a, b Package) int {
+		ya, yb := a.Libyears(), b.Libyears()
+		return compareKnown(ya >= 0, yb >= 0, func() int {
+			switch {
+			case ya < yb:
+				return -1
+			case ya > yb:
+				return 1
+			}
+			return 0
+		})
+	},
+}
+
+// descendingByDefault are the fields whose useful order is largest first:
+// major updates, the oldest versions, the furthest behind and the most
+// vulnerable
+var descendingByDefault = map[string]bool{"update-type": true, "age": true, "behind": true, "vulns": true}
+
+// Order sorts packages by a field, breaking ties by name. Packages with an
+// unknown value sort last in either direction.
+type Order struct {
+	field string
+	desc  bool
+}
+
+// ParseOrder parses a --sort value: a field, optionally followed by :asc or
+// :desc. Without a direction, name sorts ascending and the other fields
+// descending.
+func ParseOrder(spec string) (Order, error) {
+	field, dir, hasDir := strings.Cut(strings.TrimSpace(spec), ":")
+	if field == "" {
+		field = "name"
+	}
+	if _, ok := sortKeys[field]; !ok {
+		return Order{}, fmt.Errorf("unknown sort field %q (want %s)", field, strings.Join(SortFields, ", "))
+	}
+
+	o := Order{field: field, desc: descendingByDefault[field]}
+	if hasDir {
+		switch dir {
+		case "asc":
+			o.desc = false
+		case "desc":
+			o.desc = true
+		default:
+			return Order{}, fmt.Errorf("unknown sort direction %q (want asc or desc)", dir)
+		}
+	}
+	return o, nil
+}
+
+// Apply sorts packages in place
+func (o Order) Apply(packages []Package) {
+	key := sortKeys[o.field]
+	if key == nil {
+		key = sortKeys["name"]
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if c := key(a, b); c != 0 {
+			if c == unknownLast || c == -unknownLast {
+				return c < 0
+			}
+			if o.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return a.Name < b.Name
+	})
+}
+
+// unknownLast is returned by compareKnown when only one value is known, so
+// Apply keeps the unknown one last whatever the direction
+const unknownLast = 1 << 20
+
+func compareKnown(aKnown, bKnown bool, compare func() int) int {
+	switch {
+	case aKnown && bKnown:
+		return compare()
+	case aKnown:
+		return -unknownLast
+	case bKnown:
+		return unknownLast
+	}
+	return 0
+}
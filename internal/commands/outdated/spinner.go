@@ -2,51 +2,115 @@ package outdated
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/omarshaarawi/gx/internal/modfile"
 	"github.com/omarshaarawi/gx/internal/proxy"
 	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
 	xmodfile "golang.org/x/mod/modfile"
 )
 
-func fetchPackagesWithSpinner(ctx context.Context, proxyClient *proxy.Client, requires []*xmodfile.Require, opts Options) ([]Package, error) {
-	return ui.RunWithSpinner(ui.SpinnerTask[[]Package]{
+func fetchPackagesWithSpinner(ctx context.Context, lk *lookups, check *moduleCheck, opts Options) (*report, error) {
+	return ui.RunWithSpinner(ui.SpinnerTask[*report]{
 		Message: "Checking for updates...",
-		Total:   len(requires),
-		Run: func(progress chan<- int) ([]Package, error) {
-			return fetchPackages(ctx, proxyClient, requires, opts, progress)
+		Phase:   "check-updates",
+		Total:   len(check.requires),
+		Run: func(progress chan<- int) (*report, error) {
+			return fetchPackages(ctx, lk, check, opts, counter(progress))
 		},
 	})
 }
 
-func fetchPackages(ctx context.Context, proxyClient *proxy.Client, requires []*xmodfile.Require, opts Options, progressCh chan<- int) ([]Package, error) {
+// fetchModulesWithSpinner checks several go.mod files concurrently behind a
+// single spinner
+func fetchModulesWithSpinner(ctx context.Context, lk *lookups, checks []*moduleCheck, opts Options) ([]*report, error) {
+	total := 0
+	for _, c := range checks {
+		total += len(c.requires)
+	}
+
+	return ui.RunWithSpinner(ui.SpinnerTask[[]*report]{
+		Message: "Checking for updates...",
+		Phase:   "check-updates",
+		Total:   total,
+		Run: func(progress chan<- int) ([]*report, error) {
+			done := counter(progress)
+			reports := make([]*report, len(checks))
+			errs := make([]error, len(checks))
+			var wg sync.WaitGroup
+
+			for i, c := range checks {
+				wg.Add(1)
+				go func(i int, c *moduleCheck) {
+					defer wg.Done()
+					reports[i], errs[i] = fetchPackages(ctx, lk, c, opts, done)
+				}(i, c)
+			}
+
+			wg.Wait()
+			for _, err := range errs {
+				if err != nil {
+					return nil, err
+				}
+			}
+			return reports, nil
+		},
+	})
+}
+
+// counter returns a func reporting one more finished lookup on progress
+func counter(progress chan<- int) func() {
+	var mu sync.Mutex
+	finished := 0
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		finished++
+		progress <- finished
+	}
+}
+
+func fetchPackages(ctx context.Context, lk *lookups, check *moduleCheck, opts Options, done func()) (*report, error) {
 	packages := []Package{}
+	var upToDate []Package
+	var failures []Failure
+	var cachedAt time.Time
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	checked := 0
 
-	for _, req := range requires {
+	for _, req := range check.requires {
 		wg.Add(1)
 		go func(r *xmodfile.Require) {
 			defer wg.Done()
 
-			latest, err := proxyClient.Latest(ctx, r.Mod.Path)
+			latest, lookedUp, err := lk.latest(ctx, r.Mod.Path)
 			if err != nil {
 				mu.Lock()
-				checked++
-				progressCh <- checked
+				if errors.Is(err, proxy.ErrLocalModule) {
+					ui.Debug("skipping %v", err)
+				} else {
+					failures = append(failures, Failure{Module: r.Mod.Path, Err: err})
+				}
 				mu.Unlock()
+				done()
 				return
 			}
 
-			updateType := classifyUpdate(r.Mod.Version, latest.Version)
-
-			if opts.MajorOnly && updateType != "major" {
+			if !lookedUp.IsZero() {
 				mu.Lock()
-				checked++
-				progressCh <- checked
+				cachedAt = oldest(cachedAt, lookedUp)
 				mu.Unlock()
+			}
+
+			updateType := versions.Classify(r.Mod.Version, latest.Version)
+
+			if only := opts.onlyUpdateType(); only != "" && updateType != only {
+				done()
 				return
 			}
 
@@ -56,21 +120,94 @@ func fetchPackages(ctx context.Context, proxyClient *proxy.Client, requires []*x
 				Latest:     strings.TrimPrefix(latest.Version, "v"),
 				UpdateType: updateType,
 				Direct:     !r.Indirect,
+				LatestTime: latest.Time,
 			}
 
+			// The installed version's release time gives the package's libyear age
 			if updateType != "none" {
-				mu.Lock()
-				packages = append(packages, pkg)
-				mu.Unlock()
+				if info, err := lk.info(ctx, r.Mod.Path, r.Mod.Version); err == nil {
+					pkg.CurrentTime = info.Time
+				}
+			}
+
+			// The newest version within the configured constraint is shown next to the latest
+			if c, ok := lk.constraintFor(r.Mod.Path); ok && updateType != "none" {
+				wanted, err := lk.wanted(ctx, r.Mod.Path, c)
+				if err != nil {
+					ui.Debug("resolving %s within %s: %v", r.Mod.Path, c, err)
+				} else {
+					pkg.Constraint = c.String()
+					if wanted != nil {
+						pkg.Wanted = strings.TrimPrefix(wanted.Version, "v")
+					}
+				}
+			}
+
+			// Authors publish deprecations and retractions in the latest go.mod,
+			// which apply to modules already at the latest version too
+			status, err := lk.status(ctx, r.Mod.Path, latest.Version)
+			if err != nil {
+				ui.Debug("reading notices for %s@%s: %v", r.Mod.Path, latest.Version, err)
+			} else {
+				pkg.Deprecated = status.Deprecated
+				if rt, ok := status.Retracted(r.Mod.Version); ok {
+					pkg.Retracted = rt.String()
+					pkg.RetractionReason = rt.Rationale
+				}
+				pkg.GoVersion = status.GoVersion
+			}
+
+			// A constrained update moves to Wanted instead, or nowhere
+			if pkg.Constraint != "" && "v"+pkg.Wanted != latest.Version {
+				pkg.GoVersion = ""
+				if pkg.Wanted != "" {
+					if status, err := lk.status(ctx, r.Mod.Path, "v"+pkg.Wanted); err == nil {
+						pkg.GoVersion = status.GoVersion
+					}
+				}
+			}
+
+			// With --impact, a direct update's go.mod shows what it would pull in
+			if opts.Impact && pkg.Direct && updateType != "none" && (pkg.Constraint == "" || pkg.Wanted != "") {
+				version := "v" + targetVersion(pkg)
+				if data, err := lk.modFile(ctx, r.Mod.Path, version); err != nil {
+					ui.Debug("reading go.mod of %s@%s: %v", r.Mod.Path, version, err)
+				} else if f, err := xmodfile.ParseLax("go.mod", data, nil); err != nil {
+					ui.Debug("parsing go.mod of %s@%s: %v", r.Mod.Path, version, err)
+				} else {
+					pkg.Impact = modfile.UpgradeImpact(check.have, f.Require)
+					pkg.ImpactChecked = true
+				}
 			}
 
 			mu.Lock()
-			checked++
-			progressCh <- checked
+			switch {
+			case updateType != "none":
+				packages = append(packages, pkg)
+			case pkg.Deprecated != "" || pkg.Retracted != "":
+				upToDate = append(upToDate, pkg)
+			}
 			mu.Unlock()
+			done()
 		}(req)
 	}
 
 	wg.Wait()
-	return packages, nil
+
+	// Lookups that failed because the context ended would be missing from the report
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Lookups finish in any order
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	sort.Slice(upToDate, func(i, j int) bool {
+		return upToDate[i].Name < upToDate[j].Name
+	})
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Module < failures[j].Module
+	})
+	return &report{Packages: packages, UpToDate: upToDate, Failures: failures, CachedAt: cachedAt}, nil
 }
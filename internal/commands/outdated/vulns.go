@@ -0,0 +1,109 @@
+package outdated
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	"golang.org/x/mod/semver"
+)
+
+// vulnScan is a govulncheck run started before the proxy lookups, so the
+// scan and the outdated check run at the same time
+type vulnScan struct {
+	done   chan struct{}
+	result *vulndb.ScanResult
+	err    error
+}
+
+// startVulnScans scans the modules of go.mod files in the background, one
+// after another as govulncheck needs a lot of memory
+func startVulnScans(ctx context.Context, scanner *vulndb.Scanner, modPaths []string) []*vulnScan {
+	scans := make([]*vulnScan, len(modPaths))
+	for i := range scans {
+		scans[i] = &vulnScan{done: make(chan struct{})}
+	}
+	go func() {
+		for i, s := range scans {
+			s.result, s.err = scanner.ScanModule(ctx, modPaths[i])
+			close(s.done)
+		}
+	}()
+	return scans
+}
+
+// wait returns the vulnerabilities found, by module path, with a spinner
+// while the scan is still running
+func (s *vulnScan) wait() (map[string][]*vulndb.Vulnerability, error) {
+	select {
+	case <-s.done:
+	default:
+		_, _ = ui.RunSimpleSpinner("Scanning for vulnerabilities...", func() (struct{}, error) {
+			<-s.done
+			return struct{}{}, nil
+		})
+	}
+	if s.err != nil {
+		return nil, fmt.Errorf("scanning for vulnerabilities: %w (drop --with-vulns to skip)", s.err)
+	}
+
+	byModule := make(map[string][]*vulndb.Vulnerability)
+	seen := make(map[string]bool)
+	for _, v := range s.result.Vulnerabilities {
+		if key := v.Package + "@" + v.ID; !seen[key] {
+			seen[key] = true
+			byModule[v.Package] = append(byModule[v.Package], v)
+		}
+	}
+	return byModule, nil
+}
+
+// withVulns marks packages with the vulnerabilities of their installed
+// version
+func withVulns(packages []Package, vulns map[string][]*vulndb.Vulnerability) {
+	for i := range packages {
+		packages[i].Vulns = vulns[packages[i].Name]
+		packages[i].VulnsChecked = true
+	}
+}
+
+// vulnsCell shows how many vulnerabilities affect a package, e.g. "⚠ 2"
+func vulnsCell(pkg Package) string {
+	switch {
+	case !pkg.VulnsChecked:
+		return "?"
+	case len(pkg.Vulns) == 0:
+		return "-"
+	}
+	return fmt.Sprintf("⚠ %d", len(pkg.Vulns))
+}
+
+// fixedByUpdate reports whether updating a package fixes a vulnerability
+func fixedByUpdate(pkg Package, v *vulndb.Vulnerability) bool {
+	fixed := "v" + strings.TrimPrefix(v.Fixed, "v")
+	return semver.IsValid(fixed) && semver.Compare("v"+targetVersion(pkg), fixed) >= 0
+}
+
+// vulnNote describes a vulnerability of a package, e.g.
+// "GO-2024-0001 (HIGH), fixed by the update"
+func vulnNote(pkg Package, v *vulndb.Vulnerability) string {
+	note := v.ID
+	if v.Severity != "" && v.Severity != "UNKNOWN" {
+		note += " (" + v.Severity + ")"
+	}
+	if fixedByUpdate(pkg, v) {
+		return note + ", fixed by the update"
+	}
+	return note + ", not fixed by the update"
+}
+
+// vulnIDs joins the IDs of a package's vulnerabilities, for CSV
+func vulnIDs(pkg Package) string {
+	ids := make([]string, len(pkg.Vulns))
+	for i, v := range pkg.Vulns {
+		ids[i] = v.ID
+	}
+	return strings.Join(ids, " ")
+}
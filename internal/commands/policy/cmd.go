@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDirect bool
+	flagJSON   bool
+)
+
+// NewCommand creates the policy command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Check dependencies against the policy in .gx.yaml",
+		Long: `Evaluate the "policy" rules from the gx config against every dependency
+and exit non-zero if any are violated, so it can gate CI.
+
+Supported rules: banned modules, max_age_days, disallowed_licenses and
+min_versions. License checks read the module cache, so run
+'go mod download' first in a fresh checkout.
+
+Examples:
+  # Check all dependencies
+  gx policy
+
+  # Only direct dependencies, as JSON
+  gx policy --direct --json`,
+		RunE: runPolicy,
+	}
+
+	cmd.Flags().BoolVar(&flagDirect, "direct", false, "Only check direct dependencies")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runPolicy(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	// Violations are already listed; the returned error only sets the exit code
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	opts := Options{
+		Direct:  flagDirect,
+		JSON:    flagJSON,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,128 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/license"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/policy"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the policy command
+type Options struct {
+	Direct  bool
+	JSON    bool
+	ModPath string
+}
+
+// Run executes the policy command
+func Run(ctx context.Context, opts Options) error {
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if cfg.Policy.IsEmpty() {
+		fmt.Printf("No policy rules configured\n\n💡 %s\n", ui.CTAStyle.Render("Add a 'policy' section to "+config.ProjectFile+" (see 'gx init')"))
+		return nil
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	requires := parser.AllRequires()
+	if opts.Direct {
+		requires = parser.DirectRequires()
+	}
+
+	deps := make([]policy.Dependency, len(requires))
+	for i, req := range requires {
+		deps[i] = policy.Dependency{
+			Path:     req.Mod.Path,
+			Version:  req.Mod.Version,
+			Indirect: req.Indirect,
+		}
+	}
+
+	if cfg.Policy.MaxAgeDays > 0 {
+		if err := fetchReleaseInfoWithSpinner(ctx, deps, cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements())); err != nil {
+			return fmt.Errorf("fetching release info: %w", err)
+		}
+	}
+
+	unknownLicenses := 0
+	if len(cfg.Policy.DisallowedLicenses) > 0 {
+		for i := range deps {
+			deps[i].License = license.Detect(deps[i].Path, deps[i].Version)
+			if deps[i].License == license.Unknown {
+				unknownLicenses++
+			}
+		}
+	}
+
+	violations := policy.Evaluate(cfg.Policy, deps, time.Now())
+
+	if opts.JSON {
+		if err := outputJSON(violations, len(deps)); err != nil {
+			return err
+		}
+	} else {
+		outputText(violations, len(deps), unknownLicenses)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d policy violations", len(violations))
+	}
+	return nil
+}
+
+func outputJSON(violations []policy.Violation, checked int) error {
+	if violations == nil {
+		violations = []policy.Violation{}
+	}
+
+	output := map[string]any{
+		"checked":    checked,
+		"passed":     len(violations) == 0,
+		"violations": violations,
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func outputText(violations []policy.Violation, checked, unknownLicenses int) {
+	fmt.Printf("\nChecked %s modules\n", ui.FormatCount(checked))
+
+	if unknownLicenses > 0 {
+		fmt.Printf("\n⚠️  Warning: could not determine the license of %s modules (run 'go mod download' first)\n",
+			ui.FormatCount(unknownLicenses))
+	}
+
+	if len(violations) == 0 {
+		fmt.Println("\n✓ All dependencies comply with the policy")
+		return
+	}
+
+	table := ui.NewTable("Module", "Version", "Rule", "Violation")
+	for _, v := range violations {
+		table.AddRow(v.Module, v.Version, v.Rule, v.Message)
+	}
+
+	fmt.Println()
+	fmt.Print(table.Render())
+	fmt.Printf("\n%s\n", ui.CriticalStyle.Render(fmt.Sprintf("✗ %s policy violations", ui.FormatCount(len(violations)))))
+}
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/policy"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// fetchReleaseInfoWithSpinner fills in the release time and latest version of each dependency
+func fetchReleaseInfoWithSpinner(ctx context.Context, deps []policy.Dependency, client *proxy.Client) error {
+	_, err := ui.RunWithSpinner(ui.SpinnerTask[struct{}]{
+		Message: "Checking release dates...",
+		Phase:   "check-releases",
+		Total:   len(deps),
+		Run: func(progress chan<- int) (struct{}, error) {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for i := range deps {
+				wg.Add(1)
+				go func(d *policy.Dependency) {
+					defer wg.Done()
+
+					if info, err := client.Info(ctx, d.Path, d.Version); err == nil {
+						d.Released = info.Time
+					}
+					if latest, err := client.Latest(ctx, d.Path); err == nil {
+						d.Latest = latest.Version
+					}
+
+					mu.Lock()
+					loaded++
+					progress <- loaded
+					mu.Unlock()
+				}(&deps[i])
+			}
+
+			wg.Wait()
+			return struct{}{}, nil
+		},
+	})
+	return err
+}
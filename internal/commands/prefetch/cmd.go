@@ -0,0 +1,61 @@
+package prefetch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the prefetch command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prefetch",
+		Short: "Fill the disk cache with the metadata of every dependency",
+		Long: `Look up every requirement of go.mod in one parallel pass and store what
+later commands ask the proxy for in gx's disk cache: the latest version
+(with and without pre-releases), the version list, the release time of the
+required version and the go.mod files of the required and latest versions.
+
+Run it in a container build, or before going offline, so later runs don't
+wait on the proxy. gx outdated reuses latest versions and version lists up
+to --max-cache-age (or max_cache_age in the config) and release times and
+go.mod files whatever their age, since published versions don't change.
+Those are only fetched once, so running prefetch again just refreshes the
+latest versions and version lists.
+
+Inside a go.work workspace the requirements of every member module are
+fetched, each module once. Modules replaced by local directories are
+skipped, and private modules get no go.mod files, like in gx outdated.
+
+Examples:
+  # Warm the cache for this module
+  gx prefetch
+
+  # Then check for updates without asking the proxy
+  gx outdated --max-cache-age 24h`,
+		Args: cobra.NoArgs,
+		RunE: runPrefetch,
+	}
+
+	return cmd
+}
+
+func runPrefetch(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	workPath, inWorkspace := workspace.Detect(filepath.Dir(modPath))
+	if _, err := os.Stat(modPath); os.IsNotExist(err) && !inWorkspace {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		ModPath:   modPath,
+		Workspace: workPath,
+	}
+
+	cmd.SilenceUsage = true
+	return Run(cmd.Context(), opts)
+}
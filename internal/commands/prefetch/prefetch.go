@@ -0,0 +1,219 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versioncache"
+	"github.com/omarshaarawi/gx/internal/workspace"
+)
+
+// Options configures the prefetch command
+type Options struct {
+	ModPath   string
+	Workspace string // go.work path; when set, every member module's requirements are fetched
+}
+
+// target is a required module and the versions go.mod files require
+type target struct {
+	path     string
+	versions []string
+}
+
+// failure is a module whose metadata couldn't be cached
+type failure struct {
+	module string
+	err    error
+}
+
+// Run executes the prefetch command
+func Run(ctx context.Context, opts Options) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	modPaths := []string{opts.ModPath}
+	if opts.Workspace != "" {
+		ws, err := workspace.Load(opts.Workspace)
+		if err != nil {
+			return fmt.Errorf("loading workspace: %w", err)
+		}
+		modPaths = modPaths[:0]
+		for _, member := range ws.Members {
+			modPaths = append(modPaths, member.ModPath)
+		}
+	}
+
+	targets, err := collectTargets(modPaths)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("✓ No dependencies to prefetch")
+		return nil
+	}
+
+	cache, err := versioncache.Open()
+	if err != nil {
+		return fmt.Errorf("opening cache: %w", err)
+	}
+
+	failures, err := fetchWithSpinner(ctx, targets, cfg.NewProxyClient(), cache)
+	if err != nil {
+		return err
+	}
+	if err := cache.Save(); err != nil {
+		return fmt.Errorf("saving cache: %w", err)
+	}
+
+	for _, f := range failures {
+		ui.Error("⚠️  Warning: %s: %v\n", f.module, f.err)
+	}
+	if len(failures) == len(targets) {
+		return fmt.Errorf("no module could be fetched")
+	}
+
+	dir, _ := versioncache.Dir()
+	fmt.Printf("✓ Cached metadata for %s of %s modules in %s\n",
+		ui.FormatCount(len(targets)-len(failures)), ui.FormatCount(len(targets)), dir)
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx outdated --max-cache-age 24h' to check for updates from the cache"))
+	return nil
+}
+
+// collectTargets gathers the requirements of the go.mod files, each module
+// once with every version they require, skipping local replacements
+func collectTargets(modPaths []string) ([]target, error) {
+	byPath := make(map[string]*target)
+	for _, modPath := range modPaths {
+		parser, err := modfile.NewParser(modPath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", modPath, err)
+		}
+
+		local := parser.LocalReplacements()
+		for _, req := range parser.AllRequires() {
+			if slices.Contains(local, req.Mod.Path) {
+				continue
+			}
+			t, ok := byPath[req.Mod.Path]
+			if !ok {
+				t = &target{path: req.Mod.Path}
+				byPath[req.Mod.Path] = t
+			}
+			if !slices.Contains(t.versions, req.Mod.Version) {
+				t.versions = append(t.versions, req.Mod.Version)
+			}
+		}
+	}
+
+	targets := make([]target, 0, len(byPath))
+	for _, t := range byPath {
+		targets = append(targets, *t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].path < targets[j].path
+	})
+	return targets, nil
+}
+
+func fetchWithSpinner(ctx context.Context, targets []target, client *proxy.Client, cache *versioncache.Store) ([]failure, error) {
+	return ui.RunWithSpinner(ui.SpinnerTask[[]failure]{
+		Message: "Prefetching module metadata...",
+		Phase:   "prefetch",
+		Total:   len(targets),
+		Run: func(progress chan<- int) ([]failure, error) {
+			var failures []failure
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for _, t := range targets {
+				wg.Add(1)
+				go func(t target) {
+					defer wg.Done()
+
+					err := fetchModule(ctx, client, cache, t)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						failures = append(failures, failure{module: t.path, err: err})
+					}
+					loaded++
+					progress <- loaded
+				}(t)
+			}
+
+			wg.Wait()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			sort.Slice(failures, func(i, j int) bool {
+				return failures[i].module < failures[j].module
+			})
+			return failures, nil
+		},
+	})
+}
+
+// fetchModule caches what gx outdated looks up for a module. Release times
+// and go.mod files already cached are kept, as published versions don't
+// change; latest versions and the version list are always refreshed.
+func fetchModule(ctx context.Context, client *proxy.Client, cache *versioncache.Store, t target) error {
+	latest, err := client.Latest(ctx, t.path)
+	if err != nil {
+		return fmt.Errorf("looking up latest version: %w", err)
+	}
+	cache.SetLatest(t.path, latest.Version, latest.Time)
+	cache.SetInfo(t.path, latest.Version, latest.Time)
+
+	list, err := client.Versions(ctx, t.path)
+	if err != nil {
+		return fmt.Errorf("listing versions: %w", err)
+	}
+	cache.SetVersions(t.path, list)
+
+	newest := latest
+	if pre, err := client.LatestPre(ctx, t.path); err == nil {
+		cache.SetLatestPre(t.path, pre.Version, pre.Time)
+		cache.SetInfo(t.path, pre.Version, pre.Time)
+		newest = pre
+	}
+
+	for _, version := range t.versions {
+		if _, ok := cache.Info(t.path, version); ok {
+			continue
+		}
+		info, err := client.Info(ctx, t.path, version)
+		if err != nil {
+			return fmt.Errorf("looking up %s: %w", version, err)
+		}
+		cache.SetInfo(t.path, version, info.Time)
+	}
+
+	modVersions := append(slices.Clone(t.versions), latest.Version, newest.Version)
+	slices.Sort(modVersions)
+	for _, version := range slices.Compact(modVersions) {
+		if data, _ := cache.ModFile(t.path, version); len(data) > 0 {
+			continue
+		}
+		data, err := client.GetModFile(ctx, t.path, version)
+		if errors.Is(err, proxy.ErrPrivateModule) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("fetching go.mod of %s: %w", version, err)
+		}
+		cache.SetModFile(t.path, version, data)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package prune
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFix    bool
+	flagDryRun bool
+	flagForce  bool
+)
+
+// NewCommand creates the prune command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Find direct dependencies that are never imported",
+		Long: `Find direct requirements in go.mod that no package in the module imports.
+
+Imports are collected with 'go list -deps -test ./...', so test-only
+dependencies and tools imported from a tools.go file count as used.
+
+Examples:
+  # List unused direct dependencies
+  gx prune
+
+  # Drop them from go.mod and run go mod tidy
+  gx prune --fix
+
+  # Show the change to go.mod that --fix would make
+  gx prune --dry-run`,
+		RunE: runPrune,
+	}
+
+	cmd.Flags().BoolVar(&flagFix, "fix", false, "Remove unused requirements from go.mod")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Fix:     flagFix,
+		DryRun:  flagDryRun,
+		Force:   flagForce,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,144 @@
+package prune
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// Options configures the prune command
+type Options struct {
+	Fix     bool
+	DryRun  bool // show what Fix would do
+	Force   bool
+	ModPath string
+}
+
+// Run executes the prune command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	workDir := filepath.Dir(opts.ModPath)
+
+	imported, err := listImportsWithSpinner(ctx, workDir)
+	if err != nil {
+		return fmt.Errorf("listing imports: %w", err)
+	}
+
+	unused := FindUnused(parser.DirectRequires(), imported)
+
+	if len(unused) == 0 {
+		fmt.Println("✨ All direct dependencies are in use!")
+		return nil
+	}
+
+	table := ui.NewTable("Module", "Version")
+	for _, req := range unused {
+		table.AddRow(req.Mod.Path, req.Mod.Version)
+	}
+	fmt.Printf("\n🧹 %d unused direct dependencies\n\n", len(unused))
+	fmt.Println(table.Render())
+
+	if opts.DryRun {
+		return preview(opts, parser, unused)
+	}
+	if !opts.Fix {
+		fmt.Printf("💡 %s\n", ui.CTAStyle.Render("Run `gx prune --fix` to remove them"))
+		return nil
+	}
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Begin("prune", fmt.Sprintf("%d package(s)", len(unused)))
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+
+	if err := dropRequires(modfile.NewWriter(parser).WithForce(opts.Force), unused); err != nil {
+		tx.Discard()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record prune history: %v\n", err)
+	}
+
+	fmt.Printf("✓ Removed %d requirement(s)\n", len(unused))
+
+	fmt.Println("\n🔧 Running go mod tidy...")
+	if err := tx.Record(history.EffectTidy); err != nil {
+		ui.Error("⚠️  Warning: could not record prune history: %v\n", err)
+	}
+	if err := gocmd.Run(ctx, workDir, "mod", "tidy"); err != nil {
+		fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
+		fmt.Println("   You may need to run 'go mod tidy' manually")
+		return nil
+	}
+	fmt.Println("✓ go.mod and go.sum updated")
+
+	return nil
+}
+
+// FindUnused returns the requirements whose module provides no imported package
+func FindUnused(requires []*xmodfile.Require, imported map[string]bool) []*xmodfile.Require {
+	var unused []*xmodfile.Require
+	for _, req := range requires {
+		if !imported[req.Mod.Path] {
+			unused = append(unused, req)
+		}
+	}
+	return unused
+}
+
+// preview prints the diff of go.mod without the unused requirements and the
+// go mod tidy that would follow
+func preview(opts Options, parser *modfile.Parser, unused []*xmodfile.Require) error {
+	return dryrun.PrintModEdit(opts.ModPath, parser, true, func(writer *modfile.Writer) error {
+		for _, req := range unused {
+			if err := writer.DropRequire(req.Mod.Path); err != nil {
+				return fmt.Errorf("dropping %s: %w", req.Mod.Path, err)
+			}
+		}
+		writer.Cleanup()
+		return nil
+	})
+}
+
+func dropRequires(writer *modfile.Writer, requires []*xmodfile.Require) error {
+	if err := writer.Backup(); err != nil {
+		return fmt.Errorf("creating backup: %w", err)
+	}
+
+	for _, req := range requires {
+		if err := writer.DropRequire(req.Mod.Path); err != nil {
+			writer.RestoreBackup()
+			return fmt.Errorf("dropping %s: %w", req.Mod.Path, err)
+		}
+	}
+
+	writer.Cleanup()
+
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+
+	if err := writer.CleanupBackup(); err != nil {
+		return fmt.Errorf("cleanup backup: %w", err)
+	}
+
+	return nil
+}
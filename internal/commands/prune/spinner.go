@@ -0,0 +1,14 @@
+package prune
+
+import (
+	"context"
+
+	"github.com/omarshaarawi/gx/internal/golist"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+func listImportsWithSpinner(ctx context.Context, dir string) (map[string]bool, error) {
+	return ui.RunSimpleSpinner("Analyzing imports...", func() (map[string]bool, error) {
+		return golist.ImportedModules(ctx, dir)
+	})
+}
@@ -0,0 +1,61 @@
+package resolve
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNoTidy bool
+	flagDryRun bool
+)
+
+// NewCommand creates the resolve command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolve",
+		Short: "Resolve git merge conflicts in go.mod and go.sum",
+		Long: `Resolve git conflict markers in go.mod and go.sum after a merge or rebase.
+
+Both sides of go.mod are parsed and merged the way minimal version
+selection would: every module required by either side is kept at the
+higher of the two versions. go.sum keeps the entries from both sides.
+go mod tidy then removes anything the merged code no longer needs.
+
+The conflicted files are recorded in the history; find the run with
+'gx rollback --list' and restore it with 'gx rollback --id'.
+
+Examples:
+  # After 'git merge' reports a conflict in go.mod
+  gx resolve
+
+  # Show the merged go.mod without writing it
+  gx resolve --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runResolve,
+	}
+
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip running go mod tidy")
+	dryrun.AddFlag(cmd, &flagDryRun)
+
+	return cmd
+}
+
+func runResolve(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		NoTidy:  flagNoTidy,
+		DryRun:  flagDryRun,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
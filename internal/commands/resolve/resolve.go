@@ -0,0 +1,163 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/fsutil"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// lockTimeout is how long resolve waits for another process to release go.mod
+const lockTimeout = 10 * time.Second
+
+// Options configures the resolve command
+type Options struct {
+	NoTidy  bool
+	DryRun  bool
+	ModPath string
+}
+
+// Run executes the resolve command
+func Run(ctx context.Context, opts Options) error {
+
+	modData, err := os.ReadFile(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("reading go.mod: %w", err)
+	}
+
+	sumPath := modfile.SumPath(opts.ModPath)
+	sumData, err := os.ReadFile(sumPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading go.sum: %w", err)
+	}
+
+	modConflict := modfile.HasConflictMarkers(modData)
+	sumConflict := modfile.HasConflictMarkers(sumData)
+	if !modConflict && !sumConflict {
+		fmt.Println("✓ No conflict markers in go.mod or go.sum")
+		return nil
+	}
+
+	modBefore, sumBefore := modData, sumData
+	var resolutions []modfile.Resolution
+	if modConflict {
+		modData, resolutions, err = modfile.ResolveConflict(opts.ModPath, modData)
+		if err != nil {
+			return fmt.Errorf("resolving go.mod: %w", err)
+		}
+	}
+	if sumConflict {
+		sumData, err = modfile.ResolveSumConflict(sumData)
+		if err != nil {
+			return fmt.Errorf("resolving go.sum: %w", err)
+		}
+	}
+
+	renderResolutions(resolutions)
+
+	if opts.DryRun {
+		var plan dryrun.Plan
+		if modConflict {
+			plan.Change(opts.ModPath, modBefore, modData)
+		}
+		if sumConflict {
+			plan.Change(sumPath, sumBefore, sumData)
+		}
+		if !opts.NoTidy {
+			plan.Run(filepath.Dir(opts.ModPath), "go", "mod", "tidy")
+		}
+		plan.Print(os.Stdout)
+		return nil
+	}
+
+	tx, err := writeResolved(opts, modConflict, modData, sumConflict, sumData)
+	if err != nil {
+		return err
+	}
+
+	if !opts.NoTidy {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := tx.Record(history.EffectTidy); err != nil {
+			ui.Error("⚠️  Warning: could not record resolve history: %v\n", err)
+		}
+		if err := gocmd.Run(ctx, filepath.Dir(opts.ModPath), "mod", "tidy"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod tidy' manually")
+			return nil
+		}
+		fmt.Println("✓ go.mod and go.sum updated")
+	}
+
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Review the result, then 'git add go.mod go.sum'"))
+	return nil
+}
+
+// writeResolved replaces the conflicted files, recording them in the history
+// first, and returns the history transaction for the go mod tidy that follows
+func writeResolved(opts Options, modConflict bool, modData []byte, sumConflict bool, sumData []byte) (*history.Transaction, error) {
+	lock, err := fsutil.LockFile(opts.ModPath, lockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("locking go.mod: %w", err)
+	}
+	defer lock.Unlock()
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Begin("resolve", "merge conflict")
+	if err != nil {
+		return nil, fmt.Errorf("recording history: %w", err)
+	}
+
+	if modConflict {
+		if err := fsutil.WriteFile(opts.ModPath, modData, 0o644); err != nil {
+			tx.Discard()
+			return nil, fmt.Errorf("writing go.mod: %w", err)
+		}
+		fmt.Println("\n✓ Resolved go.mod")
+	}
+	if sumConflict {
+		if err := fsutil.WriteFile(modfile.SumPath(opts.ModPath), sumData, 0o644); err != nil {
+			tx.Discard()
+			return nil, fmt.Errorf("writing go.sum: %w", err)
+		}
+		fmt.Println("✓ Resolved go.sum")
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record resolve history: %v\n", err)
+	}
+	return tx, nil
+}
+
+// renderResolutions prints the requirements that differed between the two sides
+func renderResolutions(resolutions []modfile.Resolution) {
+	if len(resolutions) == 0 {
+		return
+	}
+
+	table := ui.NewTable("Module", "Ours", "Theirs", "Chosen")
+	for _, r := range resolutions {
+		table.AddRow(r.Path, orDash(r.Ours), orDash(r.Theirs), r.Chosen)
+	}
+
+	fmt.Printf("\n🔀 %d requirement(s) differed between the two sides\n\n", len(resolutions))
+	fmt.Println(table.Render())
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package retract
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRationale string
+	flagDryRun    bool
+	flagForce     bool
+)
+
+// NewCommand creates the retract command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retract <version|low..high>",
+		Short: "Retract published versions of this module",
+		Long: `Add a retract directive to go.mod for a version of this module, or an
+inclusive range of versions, with the rationale as its comment.
+
+Versions must be canonical semantic versions matching the module path's
+major version. The retraction takes effect once a new version containing
+it is published; go get then skips the retracted versions and go list -m -u
+shows the rationale to users who depend on them.
+
+Examples:
+  # Retract a single version
+  gx retract v1.4.2 -m "Panics on empty input"
+
+  # Retract a range of versions
+  gx retract v1.3.0..v1.3.4 -m "Data race in the connection pool"
+
+  # Preview the change
+  gx retract v1.4.2 -m "Published by mistake" --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRetract,
+	}
+
+	cmd.Flags().StringVarP(&flagRationale, "rationale", "m", "", "Why the versions are retracted, shown to users (required)")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+	cmd.MarkFlagRequired("rationale")
+
+	return cmd
+}
+
+func runRetract(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	low, high, err := parseVersions(args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := Options{
+		Low:       low,
+		High:      high,
+		Rationale: flagRationale,
+		DryRun:    flagDryRun,
+		Force:     flagForce,
+		ModPath:   modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
+
+// parseVersions accepts a single version, low..high, or the go.mod [low, high] form
+func parseVersions(arg string) (low, high string, err error) {
+	arg = strings.TrimSpace(arg)
+	if inner, ok := strings.CutPrefix(arg, "["); ok {
+		inner, ok = strings.CutSuffix(inner, "]")
+		if !ok {
+			return "", "", fmt.Errorf("expected [low, high], got %q", arg)
+		}
+		low, high, ok = strings.Cut(inner, ",")
+		if !ok {
+			return "", "", fmt.Errorf("expected [low, high], got %q", arg)
+		}
+		return strings.TrimSpace(low), strings.TrimSpace(high), nil
+	}
+
+	if low, high, ok := strings.Cut(arg, ".."); ok {
+		return low, high, nil
+	}
+	return arg, arg, nil
+}
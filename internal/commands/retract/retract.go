@@ -0,0 +1,83 @@
+package retract
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// Options configures the retract command
+type Options struct {
+	Low       string
+	High      string
+	Rationale string
+	DryRun    bool
+	Force     bool
+	ModPath   string
+}
+
+// Run executes the retract command
+func Run(ctx context.Context, opts Options) error {
+
+	if strings.TrimSpace(opts.Rationale) == "" {
+		return fmt.Errorf("a rationale is required (-m)")
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.AddRetract(opts.Low, opts.High, opts.Rationale); err != nil {
+		return err
+	}
+
+	versions := opts.Low
+	if opts.Low != opts.High {
+		versions = fmt.Sprintf("[%s, %s]", opts.Low, opts.High)
+	}
+
+	if opts.DryRun {
+		return dryrun.PrintModEdit(opts.ModPath, parser, false, nil)
+	}
+
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	if err := writer.CleanupBackup(); err != nil {
+		return fmt.Errorf("cleanup backup: %w", err)
+	}
+
+	fmt.Printf("✓ Retracted %s of %s\n", versions, parser.ModulePath())
+
+	cta := "Publish a new version containing this go.mod for the retraction to take effect"
+	if next := nextPatch(opts.High); next != "" {
+		cta = fmt.Sprintf("Tag and publish %s (or later) for the retraction to take effect", next)
+	}
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(cta))
+	return nil
+}
+
+// nextPatch returns the patch release after a release version, or "" for pre-releases
+func nextPatch(v string) string {
+	v = strings.TrimSuffix(v, "+incompatible")
+	if semver.Prerelease(v) != "" {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	patch, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("v%s.%s.%d", parts[0], parts[1], patch+1)
+}
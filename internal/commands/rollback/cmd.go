@@ -0,0 +1,65 @@
+package rollback
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagList   bool
+	flagID     string
+	flagDryRun bool
+)
+
+// NewCommand creates the rollback command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore go.mod and go.sum from before the last gx update",
+		Long: `Restore go.mod and go.sum to the state they were in before the last
+'gx update' run.
+
+Every update run snapshots both files into a per-module transaction log in
+the user cache directory. The last 20 runs are kept.
+
+Examples:
+  # Undo the last update run
+  gx rollback
+
+  # Show recorded runs
+  gx rollback --list
+
+  # Restore a specific run
+  gx rollback --id 20250601T101500.000000000
+
+  # Show what would be restored
+  gx rollback --dry-run`,
+		RunE: runRollback,
+	}
+
+	cmd.Flags().BoolVar(&flagList, "list", false, "List recorded update runs")
+	cmd.Flags().StringVar(&flagID, "id", "", "Roll back a specific run")
+	dryrun.AddFlag(cmd, &flagDryRun)
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		List:    flagList,
+		ID:      flagID,
+		DryRun:  flagDryRun,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
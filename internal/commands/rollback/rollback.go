@@ -0,0 +1,97 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the rollback command
+type Options struct {
+	List    bool
+	ID      string
+	DryRun  bool
+	ModPath string
+}
+
+// Run executes the rollback command
+func Run(ctx context.Context, opts Options) error {
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+
+	if opts.List {
+		return listTransactions(store)
+	}
+
+	var tx *history.Transaction
+	if opts.ID != "" {
+		tx, err = store.Get(opts.ID)
+		if err == nil && tx.Status != history.StatusCommitted {
+			return fmt.Errorf("transaction %s is %s and cannot be rolled back", tx.ID, tx.Status)
+		}
+	} else {
+		tx, err = store.Last("update")
+	}
+	if errors.Is(err, history.ErrNoTransaction) {
+		fmt.Println("Nothing to roll back")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Would restore %s from before the %s run at %s\n",
+			strings.Join(tx.Files, " and "), tx.Command, ui.FormatDate(tx.Time)+" "+tx.Time.Format("15:04:05"))
+		return preview(opts, tx)
+	}
+
+	if err := tx.Restore(); err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	fmt.Printf("✓ Restored %s from before the %s run at %s\n",
+		strings.Join(tx.Files, " and "), tx.Command, ui.FormatDate(tx.Time)+" "+tx.Time.Format("15:04:05"))
+	fmt.Println("   Run 'go mod vendor' if the module is vendored")
+
+	return nil
+}
+
+func listTransactions(store *history.Store) error {
+	txs, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if len(txs) == 0 {
+		fmt.Println("No recorded runs")
+		return nil
+	}
+
+	table := ui.NewTable("ID", "Command", "When", "Status", "Description")
+	for _, tx := range txs {
+		table.AddRow(tx.ID, tx.Command, ui.RelativeTime(tx.Time), tx.Status, tx.Description)
+	}
+	fmt.Println(table.Render())
+
+	return nil
+}
+
+// preview prints the diff of the files the rollback would restore
+func preview(opts Options, tx *history.Transaction) error {
+	var plan dryrun.Plan
+	if err := plan.Restore(opts.ModPath, tx); err != nil {
+		return err
+	}
+	plan.Print(os.Stdout)
+	return nil
+}
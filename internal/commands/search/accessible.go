@@ -0,0 +1,26 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/omarshaarawi/gx/internal/pkgsite"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// choosePlain asks for the result to add with a numbered prompt instead of
+// the list, for accessible mode
+func choosePlain(results []pkgsite.Result) (*pkgsite.Result, error) {
+	options := make([]string, len(results))
+	for i, r := range results {
+		options[i] = fmt.Sprintf("%s %s, %s", r.Path, r.Version, details(r))
+		if r.Synopsis != "" {
+			options[i] += ". " + r.Synopsis
+		}
+	}
+
+	picked, err := ui.PromptChoice("Search results:", options, false)
+	if err != nil || len(picked) == 0 {
+		return nil, err
+	}
+	return &results[picked[0]], nil
+}
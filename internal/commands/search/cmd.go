@@ -0,0 +1,82 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagLimit       int
+	flagJSON        bool
+	flagInteractive bool
+	flagDryRun      bool
+)
+
+// NewCommand creates the search command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>...",
+		Short: "Search pkg.go.dev for packages",
+		Long: `Search pkg.go.dev for packages and show their latest version, how many
+packages import them, and their licenses.
+
+With --interactive, pick a result to add to go.mod with go get; the change
+can be undone with 'gx rollback --id'. --dry-run shows the go get instead of
+running it, so it needs --interactive.
+
+Examples:
+  # Search for a YAML parser
+  gx search yaml parser
+
+  # Pick a result and add it to go.mod
+  gx search yaml parser -i
+
+  # Pick a result and show the go get that would add it
+  gx search yaml parser -i --dry-run
+
+  # Top 5 results as JSON
+  gx search uuid --limit 5 --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runSearch,
+	}
+
+	cmd.Flags().IntVarP(&flagLimit, "limit", "n", 10, "Maximum number of results")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVarP(&flagInteractive, "interactive", "i", false, "Pick a result to add to go.mod")
+	dryrun.AddFlag(cmd, &flagDryRun)
+
+	return cmd
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if flagInteractive {
+		if _, err := os.Stat(modPath); os.IsNotExist(err) {
+			return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+		}
+	}
+
+	if flagInteractive {
+		if err := ui.RequireInteractive("gx search -i", "drop -i and go get the module"); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	opts := Options{
+		Query:       strings.Join(args, " "),
+		Limit:       flagLimit,
+		JSON:        flagJSON,
+		Interactive: flagInteractive,
+		DryRun:      flagDryRun,
+		ModPath:     modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,169 @@
+package search
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/pkgsite"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+var (
+	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
+	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	dimmedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+type item struct {
+	result pkgsite.Result
+}
+
+func (i item) FilterValue() string { return i.result.Path }
+
+type itemDelegate struct{}
+
+func (d itemDelegate) Height() int                             { return 2 }
+func (d itemDelegate) Spacing() int                            { return 1 }
+func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	title := fmt.Sprintf("%s %s  %s", i.result.Path, i.result.Version, dimmedStyle.Render(details(i.result)))
+	synopsis := dimmedStyle.Render(ui.TruncateString(i.result.Synopsis, 100))
+
+	if index == m.Index() {
+		fmt.Fprint(w, selectedItemStyle.Render("> "+title)+"\n"+itemStyle.Render(synopsis))
+	} else {
+		fmt.Fprint(w, itemStyle.Render(title)+"\n"+itemStyle.Render(synopsis))
+	}
+}
+
+// listTop is the line View starts the list on
+const listTop = 4
+
+type model struct {
+	list     list.Model
+	selected *pkgsite.Result
+	showHelp bool // showing the ? overlay
+	quitting bool
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.showHelp {
+			switch {
+			case msg.String() == "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+			case key.Matches(msg, ui.Key("help"), key.NewBinding(key.WithKeys("esc"))):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, ui.Key("help")):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, ui.Key("quit")):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, ui.Key("confirm")):
+			if i, ok := m.list.SelectedItem().(item); ok {
+				m.selected = &i.result
+			}
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+	case tea.MouseMsg:
+		if !m.showHelp {
+			ui.ListMouse(&m.list, msg, listTop, 3)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m model) View() string {
+	if m.quitting {
+		return ""
+	}
+	if m.showHelp {
+		return ui.KeyHelp("⌨️  Search keys",
+			[]key.Binding{ui.Key("up"), ui.Key("down"), ui.Key("next-page"), ui.Key("prev-page"), ui.Key("first"), ui.Key("last")},
+			[]key.Binding{ui.Key("confirm"), ui.Key("quit")},
+		)
+	}
+
+	titleText := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Render("🔍 Select a package to add")
+
+	add := ui.Key("confirm")
+	add.SetHelp(add.Help().Key, "add")
+	helpText := ui.ShortKeyHelp(ui.Key("up"), ui.Key("down"), add, ui.Key("quit"), ui.Key("help"))
+
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		titleText,
+		helpText,
+		"",
+	)
+
+	return header + "\n" + m.list.View()
+}
+
+// RunInteractive lets the user pick a search result. It returns nil if the
+// selection was cancelled.
+func RunInteractive(results []pkgsite.Result) (*pkgsite.Result, error) {
+	if ui.Accessible() {
+		return choosePlain(results)
+	}
+
+	items := make([]list.Item, len(results))
+	for i, r := range results {
+		items[i] = item{result: r}
+	}
+
+	const defaultWidth = 120
+	const defaultHeight = 30
+
+	l := list.New(items, itemDelegate{}, defaultWidth, defaultHeight)
+	l.Title = ""
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.KeyMap = ui.ListKeys()
+
+	p := tea.NewProgram(model{list: l}, ui.InteractiveOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running interactive UI: %w", err)
+	}
+
+	return finalModel.(model).selected, nil
+}
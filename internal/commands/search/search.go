@@ -0,0 +1,136 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/pkgsite"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the search command
+type Options struct {
+	Query       string
+	Limit       int
+	JSON        bool
+	Interactive bool
+	DryRun      bool // with Interactive, show the go get that would add the pick
+	ModPath     string
+}
+
+// Run executes the search command
+func Run(ctx context.Context, opts Options) error {
+
+	if opts.JSON && opts.Interactive {
+		return fmt.Errorf("--json and --interactive cannot be combined")
+	}
+	if opts.DryRun && !opts.Interactive {
+		return fmt.Errorf("--dry-run needs --interactive: without it search changes nothing")
+	}
+
+	results, err := searchWithSpinner(ctx, pkgsite.NewClient(""), opts.Query, opts.Limit)
+	if err != nil {
+		return fmt.Errorf("searching pkg.go.dev: %w", err)
+	}
+
+	if opts.JSON {
+		if results == nil {
+			results = []pkgsite.Result{}
+		}
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No packages found for %q\n", opts.Query)
+		return nil
+	}
+
+	if !opts.Interactive {
+		render(results)
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx search "+opts.Query+" -i' to add one to go.mod"))
+		return nil
+	}
+
+	selected, err := RunInteractive(results)
+	if err != nil {
+		return err
+	}
+	if selected == nil {
+		fmt.Println("No package selected")
+		return nil
+	}
+
+	return add(ctx, opts, *selected)
+}
+
+// render prints the results with their details under each package path
+func render(results []pkgsite.Result) {
+	for i, r := range results {
+		fmt.Printf("\n%2d. %s %s\n", i+1, ui.HeaderStyle.Render(r.Path), r.Version)
+		if r.Synopsis != "" {
+			fmt.Printf("    %s\n", ui.TruncateString(r.Synopsis, 100))
+		}
+		fmt.Printf("    %s\n", ui.UpToDateStyle.Render(details(r)))
+	}
+}
+
+// details summarizes the import count, licenses and release date of a result
+func details(r pkgsite.Result) string {
+	parts := []string{"imported by " + ui.FormatCount(r.ImportedBy)}
+	if len(r.Licenses) > 0 {
+		parts = append(parts, strings.Join(r.Licenses, ", "))
+	}
+	if r.Published != "" {
+		parts = append(parts, "published "+r.Published)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// add requires the package's module with go get, recording go.mod and go.sum
+// in the history, or shows the go get with DryRun
+func add(ctx context.Context, opts Options, r pkgsite.Result) error {
+	if r.Standard() {
+		fmt.Printf("✓ %s is in the standard library; import it directly\n", r.Path)
+		return nil
+	}
+	if opts.DryRun {
+		var plan dryrun.Plan
+		plan.Run(filepath.Dir(opts.ModPath), "go", "get", r.Path+"@latest")
+		plan.Print(os.Stdout)
+		return nil
+	}
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Begin("search", "add "+r.Path)
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+
+	if err := getWithSpinner(ctx, filepath.Dir(opts.ModPath), r.Path); err != nil {
+		tx.Discard()
+		return fmt.Errorf("adding %s: %w", r.Path, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record search history: %v\n", err)
+	}
+
+	fmt.Printf("✓ Added %s to go.mod\n", r.Path)
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Import it, then run 'go mod tidy' to mark it as a direct dependency"))
+	return nil
+}
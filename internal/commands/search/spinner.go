@@ -0,0 +1,22 @@
+package search
+
+import (
+	"context"
+
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/pkgsite"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+func searchWithSpinner(ctx context.Context, client *pkgsite.Client, query string, limit int) ([]pkgsite.Result, error) {
+	return ui.RunSimpleSpinner("Searching pkg.go.dev...", func() ([]pkgsite.Result, error) {
+		return client.Search(ctx, query, limit)
+	})
+}
+
+func getWithSpinner(ctx context.Context, dir, path string) error {
+	_, err := ui.RunSimpleSpinner("Running go get "+path+"@latest...", func() (struct{}, error) {
+		return struct{}{}, gocmd.Run(ctx, dir, "get", path+"@latest")
+	})
+	return err
+}
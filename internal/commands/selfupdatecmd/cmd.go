@@ -0,0 +1,47 @@
+package selfupdatecmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCheck bool
+	flagForce bool
+)
+
+// NewCommand creates the self-update command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update gx to the latest release",
+		Long: `Check GitHub for the latest gx release, download the binary for this
+platform, verify it against the release checksums, and replace the running
+executable.
+
+Set GITHUB_TOKEN to avoid API rate limits.
+
+Examples:
+  # Only report whether a newer version exists
+  gx self-update --check
+
+  # Install the latest release
+  gx self-update`,
+		Args: cobra.NoArgs,
+		RunE: runSelfUpdate,
+	}
+
+	cmd.Flags().BoolVar(&flagCheck, "check", false, "Only report whether a newer release is available")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Install the latest release even if this build is current or a development build")
+
+	return cmd
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	opts := Options{
+		Check:   flagCheck,
+		Force:   flagForce,
+		Version: cmd.Root().Version,
+	}
+
+	return Run(cmd.Context(), opts)
+}
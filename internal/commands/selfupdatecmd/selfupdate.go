@@ -0,0 +1,104 @@
+package selfupdatecmd
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/selfupdate"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the self-update command
+type Options struct {
+	Check   bool
+	Force   bool
+	Version string // version of the running binary
+}
+
+// Run executes the self-update command
+func Run(ctx context.Context, opts Options) error {
+
+	client := github.NewClient("")
+
+	release, err := ui.RunSimpleSpinner("Checking for a new gx release...", func() (*github.Release, error) {
+		return client.LatestRelease(ctx, selfupdate.Owner, selfupdate.Repo)
+	})
+	if err != nil {
+		return fmt.Errorf("fetching latest release: %w", err)
+	}
+
+	newer := selfupdate.Newer(opts.Version, release.TagName)
+	if !newer {
+		fmt.Printf("✓ gx %s is up to date\n", opts.Version)
+		if !opts.Force || opts.Check {
+			return nil
+		}
+	}
+
+	if opts.Check {
+		fmt.Printf("⬆️  gx %s is available (current: %s)\n", release.TagName, opts.Version)
+		fmt.Printf("   %s\n", release.HTMLURL)
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx self-update' to install it"))
+		return nil
+	}
+
+	if opts.Version == "dev" && !opts.Force {
+		return fmt.Errorf("this is a development build; use --force to replace it with %s", release.TagName)
+	}
+
+	exe, err := selfupdate.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gx binary: %w", err)
+	}
+
+	asset, err := selfupdate.FindAsset(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	binary, err := ui.RunSimpleSpinner(fmt.Sprintf("Downloading %s...", asset.Name), func() ([]byte, error) {
+		return download(ctx, client, release, asset)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := selfupdate.Replace(exe, binary); err != nil {
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+
+	fmt.Printf("✓ Updated gx %s → %s\n", opts.Version, release.TagName)
+	return nil
+}
+
+// download fetches an asset, verifies it against the release checksums and
+// returns the gx binary it contains
+func download(ctx context.Context, client *github.Client, release *github.Release, asset github.Asset) ([]byte, error) {
+	var checksumsAsset *github.Asset
+	for i := range release.Assets {
+		if release.Assets[i].Name == selfupdate.ChecksumsFile {
+			checksumsAsset = &release.Assets[i]
+		}
+	}
+	if checksumsAsset == nil {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, selfupdate.ChecksumsFile)
+	}
+
+	checksums, err := client.Download(ctx, *checksumsAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := client.Download(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := selfupdate.Verify(data, asset.Name, selfupdate.ParseChecksums(checksums)); err != nil {
+		return nil, err
+	}
+
+	return selfupdate.ExtractBinary(asset.Name, data, runtime.GOOS)
+}
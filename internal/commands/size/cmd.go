@@ -0,0 +1,58 @@
+package size
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAll  bool
+	flagTop  int
+	flagJSON bool
+)
+
+// NewCommand creates the size command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "size",
+		Short: "Show how much each dependency weighs",
+		Long: `Download each dependency's module zip from the proxy and report its
+unpacked size and file count, largest first.
+
+Examples:
+  # Size of direct dependencies
+  gx size
+
+  # Include indirect dependencies and show the 10 largest
+  gx size --all --top 10
+
+  # Machine-readable output
+  gx size --json`,
+		RunE: runSize,
+	}
+
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Include indirect dependencies")
+	cmd.Flags().IntVar(&flagTop, "top", 0, "Only show the N largest modules")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runSize(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		All:     flagAll,
+		Top:     flagTop,
+		JSON:    flagJSON,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
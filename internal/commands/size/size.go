@@ -0,0 +1,158 @@
+package size
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// Options configures the size command
+type Options struct {
+	All     bool
+	Top     int
+	JSON    bool
+	ModPath string
+}
+
+// ModuleSize holds the size of one module version
+type ModuleSize struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Direct   bool   `json:"direct"`
+	Files    int    `json:"files"`
+	Unpacked int64  `json:"unpacked_bytes"`
+	Zip      int64  `json:"zip_bytes"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Run executes the size command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	requires := parser.DirectRequires()
+	if opts.All {
+		requires = parser.AllRequires()
+	}
+
+	var targets []*xmodfile.Require
+	for _, req := range requires {
+		if cfg.IgnoreRuleFor(req.Mod.Path) != nil {
+			continue
+		}
+		targets = append(targets, req)
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("✓ No dependencies to measure")
+		return nil
+	}
+
+	sizes, err := measureWithSpinner(ctx, targets, cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements()))
+	if err != nil {
+		return fmt.Errorf("measuring modules: %w", err)
+	}
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Unpacked != sizes[j].Unpacked {
+			return sizes[i].Unpacked > sizes[j].Unpacked
+		}
+		return sizes[i].Path < sizes[j].Path
+	})
+
+	if opts.Top > 0 && len(sizes) > opts.Top {
+		sizes = sizes[:opts.Top]
+	}
+
+	if opts.JSON {
+		return outputJSON(sizes)
+	}
+
+	return outputTable(sizes)
+}
+
+// measureZip computes the file count and unpacked size of a module zip
+func measureZip(data []byte) (files int, unpacked int64, err error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading zip: %w", err)
+	}
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files++
+		unpacked += int64(f.UncompressedSize64)
+	}
+
+	return files, unpacked, nil
+}
+
+func outputJSON(sizes []*ModuleSize) error {
+	data, err := json.MarshalIndent(sizes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func outputTable(sizes []*ModuleSize) error {
+	table := ui.NewTable("Module", "Version", "Files", "Unpacked", "Zip")
+
+	var totalFiles int
+	var totalUnpacked, totalZip int64
+	var failed []*ModuleSize
+
+	for _, s := range sizes {
+		if s.Error != "" {
+			failed = append(failed, s)
+			continue
+		}
+
+		name := s.Path
+		if !s.Direct {
+			name += " (indirect)"
+		}
+		table.AddRow(name, s.Version, ui.FormatCount(s.Files), ui.FormatBytes(s.Unpacked), ui.FormatBytes(s.Zip))
+
+		totalFiles += s.Files
+		totalUnpacked += s.Unpacked
+		totalZip += s.Zip
+	}
+
+	fmt.Println()
+	fmt.Print(table.Render())
+
+	fmt.Printf("\n%s\n", ui.SummaryStyle.Render(fmt.Sprintf("Total: %s files, %s unpacked (%s download)",
+		ui.FormatCount(totalFiles), ui.FormatBytes(totalUnpacked), ui.FormatBytes(totalZip))))
+
+	if len(failed) > 0 {
+		names := make([]string, len(failed))
+		for i, s := range failed {
+			names[i] = s.Path
+		}
+		fmt.Printf("\n⚠️  Warning: could not download %s\n", strings.Join(names, ", "))
+	}
+
+	return nil
+}
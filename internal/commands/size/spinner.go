@@ -0,0 +1,55 @@
+package size
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+func measureWithSpinner(ctx context.Context, requires []*xmodfile.Require, client *proxy.Client) ([]*ModuleSize, error) {
+	return ui.RunWithSpinner(ui.SpinnerTask[[]*ModuleSize]{
+		Message: "Downloading module zips...",
+		Phase:   "download-zips",
+		Total:   len(requires),
+		Run: func(progress chan<- int) ([]*ModuleSize, error) {
+			sizes := make([]*ModuleSize, len(requires))
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for i, req := range requires {
+				wg.Add(1)
+				go func(idx int, r *xmodfile.Require) {
+					defer wg.Done()
+
+					s := &ModuleSize{
+						Path:    r.Mod.Path,
+						Version: r.Mod.Version,
+						Direct:  !r.Indirect,
+					}
+
+					data, err := client.GetZip(ctx, r.Mod.Path, r.Mod.Version)
+					if err == nil {
+						s.Zip = int64(len(data))
+						s.Files, s.Unpacked, err = measureZip(data)
+					}
+					if err != nil {
+						s.Error = err.Error()
+					}
+
+					mu.Lock()
+					sizes[idx] = s
+					loaded++
+					progress <- loaded
+					mu.Unlock()
+				}(i, req)
+			}
+
+			wg.Wait()
+			return sizes, nil
+		},
+	})
+}
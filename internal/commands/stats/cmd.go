@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagJSON    bool
+	flagNoAudit bool
+)
+
+// NewCommand creates the stats command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show dependency health metrics",
+		Long: `Summarize the health of the module's dependencies: direct and indirect
+counts, how old the installed versions are, how many releases behind they
+are, how outdated dependencies split across major, minor and patch
+updates, and known vulnerabilities.
+
+The vulnerability scan uses govulncheck when it is installed.
+
+Examples:
+  # Terminal dashboard
+  gx stats
+
+  # Metrics as JSON, without the vulnerability scan
+  gx stats --json --no-audit`,
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&flagNoAudit, "no-audit", false, "Skip the vulnerability scan")
+
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		JSON:    flagJSON,
+		NoAudit: flagNoAudit,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/stats"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	xmodfile "golang.org/x/mod/modfile"
+)
+
+// fetchDependenciesWithSpinner looks up the release date, latest version and
+// newer releases of every requirement
+func fetchDependenciesWithSpinner(ctx context.Context, requires []*xmodfile.Require, client *proxy.Client) ([]stats.Dependency, error) {
+	if len(requires) == 0 {
+		return nil, nil
+	}
+
+	return ui.RunWithSpinner(ui.SpinnerTask[[]stats.Dependency]{
+		Message: "Collecting dependency metrics...",
+		Phase:   "check-updates",
+		Total:   len(requires),
+		Run: func(progress chan<- int) ([]stats.Dependency, error) {
+			deps := make([]stats.Dependency, len(requires))
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for i, req := range requires {
+				wg.Add(1)
+				go func(idx int, r *xmodfile.Require) {
+					defer wg.Done()
+
+					dep := stats.Dependency{
+						Path:     r.Mod.Path,
+						Version:  r.Mod.Version,
+						Indirect: r.Indirect,
+					}
+
+					if info, err := client.Info(ctx, r.Mod.Path, r.Mod.Version); err == nil {
+						dep.Released = info.Time
+					}
+					if latest, err := client.Latest(ctx, r.Mod.Path); err == nil {
+						dep.Latest = latest.Version
+						if list, err := client.Versions(ctx, r.Mod.Path); err == nil {
+							dep.Behind = stats.CountBehind(r.Mod.Version, list)
+						}
+					}
+
+					mu.Lock()
+					deps[idx] = dep
+					loaded++
+					progress <- loaded
+					mu.Unlock()
+				}(i, req)
+			}
+
+			wg.Wait()
+			return deps, nil
+		},
+	})
+}
+
+func scanModuleWithSpinner(ctx context.Context, scanner *vulndb.Scanner, modPath string) (*vulndb.ScanResult, error) {
+	return ui.RunSimpleSpinner("Scanning for vulnerabilities...", func() (*vulndb.ScanResult, error) {
+		return scanner.ScanModule(ctx, modPath)
+	})
+}
@@ -0,0 +1,175 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/migration"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/stats"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// barWidth is the length of the longest bar in the update lag chart
+const barWidth = 30
+
+// Options configures the stats command
+type Options struct {
+	JSON    bool
+	NoAudit bool
+	ModPath string
+}
+
+// Run executes the stats command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	deps, err := fetchDependenciesWithSpinner(ctx, parser.AllRequires(), cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements()))
+	if err != nil {
+		return fmt.Errorf("collecting metrics: %w", err)
+	}
+
+	summary := stats.Compute(deps, time.Now())
+	for _, req := range parser.AllRequires() {
+		if m, ok := migration.Known(req.Mod.Path); ok {
+			summary.Replacements = append(summary.Replacements, stats.Replacement{Module: m.Old, Replacement: m.New, Note: m.Note})
+		}
+	}
+
+	if !opts.NoAudit {
+		scanner, err := vulndb.NewScanner()
+		if err != nil {
+			ui.Error("⚠️  Warning: skipping vulnerability scan: %v\n", err)
+		} else if result, err := scanModuleWithSpinner(ctx, scanner, opts.ModPath); err != nil {
+			ui.Error("⚠️  Warning: vulnerability scan failed: %v\n", err)
+		} else {
+			summary.Vulnerabilities = stats.SummarizeVulnerabilities(result.Vulnerabilities)
+		}
+	}
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	render(summary)
+	return nil
+}
+
+// render prints the summary as a terminal dashboard
+func render(s stats.Summary) {
+	if s.Total == 0 {
+		fmt.Println("No dependencies found in go.mod")
+		return
+	}
+
+	row := func(label, value string) {
+		fmt.Printf("  %-16s %s\n", label, value)
+	}
+	section := func(title string) {
+		fmt.Printf("\n%s\n", ui.HeaderStyle.Render(title))
+	}
+
+	fmt.Println(ui.SummaryStyle.Render("📊 Dependency health"))
+
+	section("Dependencies")
+	row("total", fmt.Sprintf("%s %s", ui.FormatCount(s.Total),
+		ui.UpToDateStyle.Render(fmt.Sprintf("(%s direct, %s indirect)", ui.FormatCount(s.Direct), ui.FormatCount(s.Indirect)))))
+	row("up to date", ui.FormatCount(s.UpToDate))
+	row("outdated", ui.FormatCount(s.Outdated))
+	if s.Unknown > 0 {
+		row("unknown", ui.FormatCount(s.Unknown)+ui.UpToDateStyle.Render(" (not found on the proxy)"))
+	}
+
+	if s.Outdated > 0 {
+		section("Update lag")
+		maxCount := max(s.Lag.Major, s.Lag.Minor, s.Lag.Patch)
+		for _, l := range []struct {
+			kind  string
+			count int
+			style lipgloss.Style
+		}{
+			{"major", s.Lag.Major, ui.MajorStyle},
+			{"minor", s.Lag.Minor, ui.MinorStyle},
+			{"patch", s.Lag.Patch, ui.PatchStyle},
+		} {
+			bar := strings.Repeat("█", l.count*barWidth/maxCount)
+			if bar == "" && l.count > 0 {
+				bar = "▏"
+			}
+			row(ui.UpdateSymbol(l.kind)+" "+l.kind, l.style.Render(bar)+" "+ui.FormatCount(l.count))
+		}
+	}
+
+	if s.Oldest != nil {
+		section("Age of installed versions")
+		avg := fmt.Sprintf("%s days", ui.FormatCount(int(s.AverageAgeDays)))
+		if s.Direct > 0 {
+			avg += ui.UpToDateStyle.Render(fmt.Sprintf(" (direct: %s days)", ui.FormatCount(int(s.AverageDirectAgeDays))))
+		}
+		row("average", avg)
+		row("oldest", fmt.Sprintf("%s %s %s", s.Oldest.Module, s.Oldest.Version,
+			ui.UpToDateStyle.Render(fmt.Sprintf("(%s days)", ui.FormatCount(s.Oldest.Days)))))
+	}
+
+	section("Versions behind")
+	row("total", fmt.Sprintf("%s %s", ui.FormatCount(s.VersionsBehind),
+		ui.UpToDateStyle.Render(fmt.Sprintf("(%.1f per dependency)", s.AverageVersionsBehind))))
+	if s.MostBehind != nil {
+		row("most behind", fmt.Sprintf("%s %s %s", s.MostBehind.Module, s.MostBehind.Version,
+			ui.UpToDateStyle.Render(fmt.Sprintf("(%s releases)", ui.FormatCount(s.MostBehind.Releases)))))
+	}
+
+	if len(s.Replacements) > 0 {
+		section("Replacements")
+		migratable := ""
+		for _, r := range s.Replacements {
+			if r.Replacement == "" {
+				row("abandoned", r.Module)
+				row("", ui.UpToDateStyle.Render(r.Note))
+				continue
+			}
+			row("moved", r.Module+" → "+r.Replacement)
+			if migratable == "" {
+				migratable = r.Module
+			}
+		}
+		if migratable != "" {
+			fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Run 'gx migrate %s --dry-run' to preview the move", migratable)))
+		}
+	}
+
+	if v := s.Vulnerabilities; v != nil {
+		section("Vulnerabilities")
+		if v.Total == 0 {
+			row("found", "✓ none")
+		} else {
+			var parts []string
+			for _, severity := range v.Severities() {
+				parts = append(parts, ui.SeverityStyle(severity).Render(fmt.Sprintf("%s %s %s", ui.SeveritySymbol(severity), severity, ui.FormatCount(v.BySeverity[severity]))))
+			}
+			row("found", fmt.Sprintf("%s in %s module(s)", ui.FormatCount(v.Total), ui.FormatCount(v.Modules)))
+			row("by severity", strings.Join(parts, ", "))
+			fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Run 'gx audit' for details"))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package toolchain
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagGo        string
+	flagToolchain string
+	flagPre       bool
+	flagDryRun    bool
+	flagNoTidy    bool
+	flagForce     bool
+)
+
+// NewCommand creates the toolchain command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "toolchain",
+		Short: "Show and bump the go and toolchain directives",
+		Long: `Show the go and toolchain directives in go.mod next to the installed Go
+version and the newest Go release, which is looked up on the module proxy.
+
+With --go or --toolchain, update the directives: go.mod is backed up, the
+new versions are written, and go mod tidy runs (go.mod is restored if it
+fails). A toolchain that is not newer than the go version is dropped, as the
+go command does.
+
+Examples:
+  # Show the current versions and the latest Go release
+  gx toolchain
+
+  # Move the go directive to the latest release
+  gx toolchain --go latest
+
+  # Require Go 1.22 but build with the latest toolchain
+  gx toolchain --go 1.22.0 --toolchain latest
+
+  # Remove the toolchain directive
+  gx toolchain --toolchain none`,
+		Args: cobra.NoArgs,
+		RunE: runToolchain,
+	}
+
+	cmd.Flags().StringVar(&flagGo, "go", "", "Set the go directive to a version or \"latest\"")
+	cmd.Flags().StringVar(&flagToolchain, "toolchain", "", "Set the toolchain directive to a version, \"latest\", or \"none\" to remove it")
+	cmd.Flags().BoolVar(&flagPre, "pre", false, "Let \"latest\" pick release candidates and betas")
+	dryrun.AddFlag(cmd, &flagDryRun)
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip running go mod tidy")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+
+	return cmd
+}
+
+func runToolchain(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Go:        flagGo,
+		Toolchain: flagToolchain,
+		Pre:       flagPre,
+		DryRun:    flagDryRun,
+		NoTidy:    flagNoTidy,
+		Force:     flagForce,
+		ModPath:   modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
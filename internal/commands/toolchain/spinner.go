@@ -0,0 +1,19 @@
+package toolchain
+
+import (
+	"context"
+
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+func fetchReleasesWithSpinner(ctx context.Context, proxyClient *proxy.Client) ([]string, error) {
+	return ui.RunSimpleSpinner("Checking the latest Go release...", func() ([]string, error) {
+		list, err := proxyClient.Versions(ctx, versions.ToolchainModule)
+		if err != nil {
+			return nil, err
+		}
+		return versions.GoReleases(list), nil
+	})
+}
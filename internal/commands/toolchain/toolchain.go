@@ -0,0 +1,209 @@
+package toolchain
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// Options configures the toolchain command
+type Options struct {
+	Go        string
+	Toolchain string
+	Pre       bool
+	DryRun    bool
+	NoTidy    bool
+	Force     bool
+	ModPath   string
+}
+
+// Run executes the toolchain command
+func Run(ctx context.Context, opts Options) error {
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if opts.Go == "" && opts.Toolchain == "" {
+		return show(ctx, parser, cfg, opts)
+	}
+
+	latest := ""
+	if opts.Go == "latest" || opts.Toolchain == "latest" {
+		releases, err := fetchReleasesWithSpinner(ctx, cfg.NewProxyClient())
+		if err != nil {
+			return fmt.Errorf("checking the latest Go release: %w", err)
+		}
+		if latest = versions.LatestGo(releases, opts.Pre); latest == "" {
+			return fmt.Errorf("no Go release found on the proxy")
+		}
+	}
+
+	current := parser.GoVersion()
+	goVersion := current
+	if opts.Go != "" {
+		goVersion = strings.TrimPrefix(opts.Go, "go")
+		if opts.Go == "latest" {
+			goVersion = latest
+		}
+		if !versions.IsGoVersion(goVersion) {
+			return fmt.Errorf("invalid Go version %q", opts.Go)
+		}
+		if current != "" && versions.CompareGo(goVersion, current) < 0 {
+			return fmt.Errorf("go %s is older than the current go %s; lowering it can break code that uses newer language features, edit go.mod by hand if you mean it", goVersion, current)
+		}
+	}
+
+	currentToolchain := parser.Toolchain()
+	toolchain := currentToolchain
+	switch opts.Toolchain {
+	case "":
+	case "none":
+		toolchain = ""
+	case "latest":
+		toolchain = "go" + latest
+	default:
+		toolchain = "go" + strings.TrimPrefix(opts.Toolchain, "go")
+		if !versions.IsGoVersion(strings.TrimPrefix(toolchain, "go")) {
+			return fmt.Errorf("invalid toolchain %q", opts.Toolchain)
+		}
+	}
+
+	// The go command ignores a toolchain that isn't newer than the go version
+	if toolchain != "" && versions.CompareGo(toolchain, goVersion) <= 0 {
+		if opts.Toolchain != "" && versions.CompareGo(toolchain, goVersion) < 0 {
+			return fmt.Errorf("toolchain %s is older than go %s", toolchain, goVersion)
+		}
+		fmt.Printf("⚠️  Dropping toolchain %s: it is not newer than go %s\n", toolchain, goVersion)
+		toolchain = ""
+	}
+
+	if goVersion == current && toolchain == currentToolchain {
+		fmt.Println("✓ go.mod already uses these versions")
+		return nil
+	}
+
+	printChange("go", current, goVersion)
+	printChange("toolchain", currentToolchain, toolchain)
+
+	if opts.DryRun {
+		return preview(opts, parser, goVersion, toolchain)
+	}
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := writer.Backup(); err != nil {
+		return fmt.Errorf("creating backup: %w", err)
+	}
+	if err := writer.SetGoVersion(goVersion); err != nil {
+		return err
+	}
+	if err := writer.SetToolchain(toolchain); err != nil {
+		return err
+	}
+	if err := writer.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+
+	if !opts.NoTidy {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := gocmd.Run(ctx, filepath.Dir(opts.ModPath), "mod", "tidy"); err != nil {
+			if restoreErr := writer.RestoreBackup(); restoreErr != nil {
+				return fmt.Errorf("go mod tidy failed and restore failed: %w (original error: %v)", restoreErr, err)
+			}
+			return fmt.Errorf("go mod tidy failed (go.mod restored): %w", err)
+		}
+		fmt.Println("✓ go.mod and go.sum updated")
+	}
+
+	if err := writer.CleanupBackup(); err != nil {
+		return fmt.Errorf("cleanup backup: %w", err)
+	}
+	return nil
+}
+
+// preview prints the change to go.mod and the go mod tidy that would follow
+func preview(opts Options, parser *modfile.Parser, goVersion, toolchain string) error {
+	return dryrun.PrintModEdit(opts.ModPath, parser, !opts.NoTidy, func(writer *modfile.Writer) error {
+		if err := writer.SetGoVersion(goVersion); err != nil {
+			return err
+		}
+		return writer.SetToolchain(toolchain)
+	})
+}
+
+// show prints the current directives, the installed Go and the latest release
+func show(ctx context.Context, parser *modfile.Parser, cfg *config.Config, opts Options) error {
+	goVersion := parser.GoVersion()
+
+	latest := ""
+	releases, err := fetchReleasesWithSpinner(ctx, cfg.NewProxyClient())
+	if err != nil {
+		ui.Error("⚠️  Warning: checking the latest Go release: %v\n", err)
+	} else {
+		latest = versions.LatestGo(releases, opts.Pre)
+	}
+
+	installed := "not found"
+	goenv := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	goenv.Dir = filepath.Dir(opts.ModPath)
+	if out, err := goenv.Output(); err == nil {
+		installed = strings.TrimSpace(string(out))
+	}
+
+	rows := [][2]string{
+		{"go directive", orDash(goVersion)},
+		{"toolchain", orDash(parser.Toolchain())},
+		{"installed go", installed},
+		{"latest release", orDash(latest)},
+	}
+
+	fmt.Println(ui.HeaderStyle.Render("🧰 Go toolchain"))
+	fmt.Println()
+	for _, row := range rows {
+		value := row[1]
+		if row[0] == "latest release" && latest != "" && versions.CompareGo(latest, goVersion) > 0 {
+			value = ui.MinorStyle.Render(value)
+		}
+		fmt.Printf("  %-16s %s\n", row[0], value)
+	}
+
+	if latest != "" && versions.CompareGo(latest, goVersion) > 0 {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Run 'gx toolchain --go latest' to move to Go %s", latest)))
+	}
+	return nil
+}
+
+func printChange(name, from, to string) {
+	if from == to {
+		return
+	}
+	switch {
+	case to == "":
+		fmt.Printf("✓ %s: %s → (removed)\n", name, from)
+	case from == "":
+		fmt.Printf("✓ %s: (none) → %s\n", name, to)
+	default:
+		fmt.Printf("✓ %s: %s → %s\n", name, from, to)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package tree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFormat     string
+	flagDepth      int
+	flagOutput     string
+	flagMarkers    bool
+	flagMarkedOnly bool
+	flagNoAudit    bool
+)
+
+// NewCommand creates the tree command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Show the module dependency graph",
+		Long: `Show the module dependency graph, following each direct dependency's
+requirements through the module proxy.
+
+Formats:
+  text     an indented tree; repeated subtrees are shown once
+  dot      Graphviz DOT, e.g. for 'dot -Tsvg'
+  mermaid  a Mermaid flowchart to embed in Markdown
+  json     nodes and edges
+
+Examples:
+  # Tree of dependencies, three levels deep
+  gx tree --depth 3
+
+  # Render the graph with Graphviz
+  gx tree --format dot | dot -Tsvg -o deps.svg
+
+  # Mermaid diagram for the docs
+  gx tree --format mermaid -o docs/deps.mmd
+
+  # Mark outdated and vulnerable modules
+  gx tree --markers
+
+  # Only the branches that lead to something needing attention
+  gx tree --marked-only
+
+--markers looks up every module's latest version and runs govulncheck, then
+marks modules with a newer version like [outdated: v1.9.2] and those with a
+known vulnerability like [vuln: GO-2025-0001]. --no-audit skips govulncheck.
+--marked-only implies --markers and hides branches with nothing marked.`,
+		Args: cobra.NoArgs,
+		RunE: runTree,
+	}
+
+	cmd.Flags().StringVarP(&flagFormat, "format", "f", "text", "Output format (text, dot, mermaid, json)")
+	cmd.Flags().IntVar(&flagDepth, "depth", 0, "Maximum depth of the text tree (0 for unlimited)")
+	cmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Write to file instead of stdout")
+	cmd.Flags().BoolVar(&flagMarkers, "markers", false, "Mark outdated and vulnerable modules in the tree")
+	cmd.Flags().BoolVar(&flagMarkedOnly, "marked-only", false, "Only show branches leading to a marked module (implies --markers)")
+	cmd.Flags().BoolVar(&flagNoAudit, "no-audit", false, "Skip the vulnerability scan for --markers")
+
+	return cmd
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	markers := flagMarkers || flagMarkedOnly
+	if markers && flagFormat != "text" {
+		return fmt.Errorf("--markers and --marked-only only apply to the text format")
+	}
+
+	opts := Options{
+		Format:     flagFormat,
+		Depth:      flagDepth,
+		Output:     flagOutput,
+		ModPath:    modPath,
+		Markers:    markers,
+		MarkedOnly: flagMarkedOnly,
+		NoAudit:    flagNoAudit,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,67 @@
+package tree
+
+import (
+	"context"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/graph"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+func buildWithSpinner(parser *modfile.Parser, cfg *config.Config) (*graph.Graph, error) {
+	return ui.RunSimpleSpinner("Resolving dependency graph...", func() (*graph.Graph, error) {
+		return graph.BuildWithProxy(parser, cfg.NewProxyClient())
+	})
+}
+
+// fetchLatestWithSpinner returns the latest version of each module, keyed by path.
+// Modules the proxy can't resolve are left out.
+func fetchLatestWithSpinner(ctx context.Context, paths []string, client *proxy.Client) (map[string]string, error) {
+	if len(paths) == 0 {
+		return map[string]string{}, nil
+	}
+
+	return ui.RunWithSpinner(ui.SpinnerTask[map[string]string]{
+		Message: "Checking for updates...",
+		Phase:   "check-updates",
+		Total:   len(paths),
+		Run: func(progress chan<- int) (map[string]string, error) {
+			latest := make(map[string]string, len(paths))
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			loaded := 0
+
+			for _, path := range paths {
+				wg.Add(1)
+				go func(modulePath string) {
+					defer wg.Done()
+
+					info, err := client.Latest(ctx, modulePath)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err == nil {
+						latest[modulePath] = info.Version
+					} else {
+						ui.Debug("fetching latest %s: %v", modulePath, err)
+					}
+					loaded++
+					progress <- loaded
+				}(path)
+			}
+
+			wg.Wait()
+			return latest, nil
+		},
+	})
+}
+
+func scanModuleWithSpinner(ctx context.Context, scanner *vulndb.Scanner, modPath string) (*vulndb.ScanResult, error) {
+	return ui.RunSimpleSpinner("Scanning for vulnerabilities...", func() (*vulndb.ScanResult, error) {
+		return scanner.ScanModule(ctx, modPath)
+	})
+}
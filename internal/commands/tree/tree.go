@@ -0,0 +1,218 @@
+package tree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/graph"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// Options configures the tree command
+type Options struct {
+	Format     string
+	Depth      int
+	Output     string
+	ModPath    string
+	Markers    bool // mark outdated and vulnerable modules in the text tree
+	MarkedOnly bool // show only the branches leading to a marked module
+	NoAudit    bool // leave out vulnerability markers, skipping govulncheck
+}
+
+// exporters write the graph in each machine-readable format
+var exporters = map[string]func(io.Writer, *graph.Graph) error{
+	"dot":     graph.ExportDOT,
+	"mermaid": graph.ExportMermaid,
+	"json":    graph.ExportJSON,
+}
+
+// Run executes the tree command
+func Run(ctx context.Context, opts Options) error {
+
+	export, ok := exporters[opts.Format]
+	if !ok && opts.Format != "text" {
+		return fmt.Errorf("unsupported format %q (use text, dot, mermaid or json)", opts.Format)
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	g, err := buildWithSpinner(parser, cfg)
+	if err != nil {
+		return fmt.Errorf("building dependency graph: %w", err)
+	}
+
+	var h *health
+	if opts.Markers {
+		h, err = checkHealth(ctx, g, parser, cfg, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ok {
+		// The main module is depth 0 in the rendered tree
+		maxDepth := 0
+		if opts.Depth > 0 {
+			maxDepth = opts.Depth + 1
+		}
+		export = func(w io.Writer, g *graph.Graph) error {
+			root := toTree(g.Root, map[*graph.Node]*ui.TreeNode{}, map[*graph.Node]bool{}, h)
+			if opts.MarkedOnly && !keepMarked(root, map[*ui.TreeNode]bool{}) {
+				_, err := io.WriteString(w, "✓ No outdated or vulnerable modules in the graph\n")
+				return err
+			}
+			_, err := io.WriteString(w, ui.RenderTree(root, ui.TreeOptions{
+				MaxDepth:     maxDepth,
+				ShowVersions: true,
+				Prune:        true,
+			}))
+			return err
+		}
+	}
+
+	return writeOutput(opts.Output, func(w io.Writer) error {
+		return export(w, g)
+	})
+}
+
+// writeOutput writes to the named file, or to stdout when path is empty
+func writeOutput(path string, fn func(w io.Writer) error) error {
+	if path == "" {
+		return fn(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// toTree converts a graph node for rendering, cutting cycles at the first
+// repeat. A node reached along several paths is converted once and its
+// subtree shared, so the conversion stays linear in the size of the graph;
+// the renderer prints a shared subtree once anyway.
+func toTree(n *graph.Node, built map[*graph.Node]*ui.TreeNode, onPath map[*graph.Node]bool, h *health) *ui.TreeNode {
+	if t, ok := built[n]; ok {
+		return t
+	}
+
+	t := &ui.TreeNode{Label: n.Path, Version: n.Version, Indirect: !n.Direct, Markers: h.markers(n)}
+	if onPath[n] {
+		return t
+	}
+
+	onPath[n] = true
+	for _, child := range n.Children {
+		t.Children = append(t.Children, toTree(child, built, onPath, h))
+	}
+	delete(onPath, n)
+	built[n] = t
+	return t
+}
+
+// keepMarked drops the branches without a marked module, reporting whether
+// anything marked is left. Shared subtrees are pruned once, with the result
+// kept in done.
+func keepMarked(t *ui.TreeNode, done map[*ui.TreeNode]bool) bool {
+	if marked, ok := done[t]; ok {
+		return marked
+	}
+
+	var kept []*ui.TreeNode
+	for _, child := range t.Children {
+		if keepMarked(child, done) {
+			kept = append(kept, child)
+		}
+	}
+	t.Children = kept
+	done[t] = len(kept) > 0 || len(t.Markers) > 0
+	return done[t]
+}
+
+// health is what the tree markers are built from
+type health struct {
+	latest map[string]string   // latest version by module path
+	vulns  map[string][]string // vulnerability IDs by module path
+}
+
+// checkHealth looks up the latest version of every module in the graph and,
+// unless --no-audit is set, scans the main module for vulnerabilities
+func checkHealth(ctx context.Context, g *graph.Graph, parser *modfile.Parser, cfg *config.Config, opts Options) (*health, error) {
+	seen := map[string]bool{g.Root.Path: true}
+	var paths []string
+	for _, n := range g.Nodes {
+		if !seen[n.Path] {
+			seen[n.Path] = true
+			paths = append(paths, n.Path)
+		}
+	}
+	sort.Strings(paths)
+
+	latest, err := fetchLatestWithSpinner(ctx, paths, cfg.NewProxyClient().WithLocalModules(parser.LocalReplacements()))
+	if err != nil {
+		return nil, fmt.Errorf("checking for updates: %w", err)
+	}
+	h := &health{latest: latest, vulns: make(map[string][]string)}
+
+	if opts.NoAudit {
+		return h, nil
+	}
+
+	scanner, err := vulndb.NewScanner()
+	if err != nil {
+		return nil, fmt.Errorf("creating scanner: %w (use --no-audit to skip)", err)
+	}
+	result, err := scanModuleWithSpinner(ctx, scanner, opts.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("scanning module: %w", err)
+	}
+
+	reported := make(map[string]bool)
+	for _, v := range result.Vulnerabilities {
+		if key := v.Package + "@" + v.ID; !reported[key] {
+			reported[key] = true
+			h.vulns[v.Package] = append(h.vulns[v.Package], v.ID)
+		}
+	}
+	return h, nil
+}
+
+// markers notes whether a module has a newer version and which
+// vulnerabilities affect it. Vulnerabilities are reported for the selected
+// version, so every node of an affected module is marked.
+func (h *health) markers(n *graph.Node) []string {
+	if h == nil {
+		return nil
+	}
+
+	var markers []string
+	if latest := h.latest[n.Path]; latest != "" && versions.Classify(n.Version, latest) != versions.None {
+		markers = append(markers, "outdated: "+latest)
+	}
+	for _, id := range h.vulns[n.Path] {
+		markers = append(markers, "vuln: "+id)
+	}
+	return markers
+}
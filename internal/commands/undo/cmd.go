@@ -0,0 +1,64 @@
+package undo
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNoTidy bool
+	flagDryRun bool
+)
+
+// NewCommand creates the undo command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo the last gx command that changed go.mod",
+		Long: `Undo the last gx command that changed go.mod, whichever it was: update,
+downgrade, prune, adopt, resolve or search.
+
+go.mod and go.sum are restored from the snapshot taken before the run, and
+the go mod tidy and go mod vendor steps the run executed are repeated so the
+module matches them again. Effects outside the module, such as pull requests
+closed by 'gx adopt --close', are listed but not reverted.
+
+Running it again undoes the run before that one. Use 'gx rollback --list' to
+see every recorded run.
+
+Examples:
+  # Undo the last change
+  gx undo
+
+  # Restore the files without running go mod tidy
+  gx undo --no-tidy
+
+  # Show what would be restored
+  gx undo --dry-run`,
+		RunE: runUndo,
+	}
+
+	cmd.Flags().BoolVar(&flagNoTidy, "no-tidy", false, "Skip repeating go mod tidy and go mod vendor")
+	dryrun.AddFlag(cmd, &flagDryRun)
+
+	return cmd
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		NoTidy:  flagNoTidy,
+		DryRun:  flagDryRun,
+		ModPath: modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
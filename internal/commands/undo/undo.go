@@ -0,0 +1,133 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// Options configures the undo command
+type Options struct {
+	NoTidy  bool
+	DryRun  bool
+	ModPath string
+}
+
+// Run executes the undo command
+func Run(ctx context.Context, opts Options) error {
+
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+
+	tx, err := store.Last("")
+	if errors.Is(err, history.ErrNoTransaction) {
+		fmt.Println("Nothing to undo")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Would undo gx %s%s from %s\n", tx.Command, describe(tx),
+			ui.FormatDate(tx.Time)+" "+tx.Time.Format("15:04:05"))
+		return preview(opts, tx)
+	}
+
+	if err := tx.Restore(); err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	fmt.Printf("↩️  Undid gx %s%s from %s\n", tx.Command, describe(tx),
+		ui.FormatDate(tx.Time)+" "+tx.Time.Format("15:04:05"))
+	fmt.Printf("✓ Restored %s\n", strings.Join(tx.Files, " and "))
+
+	if !opts.NoTidy {
+		repeatGoCommands(ctx, filepath.Dir(opts.ModPath), tx.Effects)
+	}
+
+	if other := notUndone(tx.Effects); len(other) > 0 {
+		fmt.Println("\n⚠️  Not undone:")
+		for _, effect := range other {
+			fmt.Printf("  • %s\n", effect)
+		}
+	}
+
+	if prev, err := store.Last(""); err == nil {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Run 'gx undo' again to undo the %s run before it", prev.Command)))
+	}
+
+	return nil
+}
+
+// repeatGoCommands re-runs the go mod tidy and go mod vendor steps of the
+// undone run against the restored files
+func repeatGoCommands(ctx context.Context, workDir string, effects []string) {
+	if slices.Contains(effects, history.EffectTidy) {
+		fmt.Println("\n🔧 Running go mod tidy...")
+		if err := gocmd.Run(ctx, workDir, "mod", "tidy"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod tidy' manually")
+			return
+		}
+		fmt.Println("✓ go.mod and go.sum updated")
+	}
+
+	if slices.Contains(effects, history.EffectVendor) {
+		fmt.Println("\n📦 Running go mod vendor...")
+		if err := gocmd.Run(ctx, workDir, "mod", "vendor"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod vendor failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod vendor' manually")
+			return
+		}
+		fmt.Println("✓ vendor directory updated")
+	}
+}
+
+// preview prints the diff of the files the undo would restore and the go
+// commands it would repeat
+func preview(opts Options, tx *history.Transaction) error {
+	var plan dryrun.Plan
+	if err := plan.Restore(opts.ModPath, tx); err != nil {
+		return err
+	}
+	if !opts.NoTidy {
+		if slices.Contains(tx.Effects, history.EffectTidy) {
+			plan.Tidy(opts.ModPath)
+		}
+		if slices.Contains(tx.Effects, history.EffectVendor) {
+			plan.Run(filepath.Dir(opts.ModPath), "go", "mod", "vendor")
+		}
+	}
+	plan.Print(os.Stdout)
+	return nil
+}
+
+// notUndone returns the recorded effects undo can't revert
+func notUndone(effects []string) []string {
+	var other []string
+	for _, effect := range effects {
+		if effect != history.EffectTidy && effect != history.EffectVendor {
+			other = append(other, effect)
+		}
+	}
+	return other
+}
+
+func describe(tx *history.Transaction) string {
+	if tx.Description == "" {
+		return ""
+	}
+	return " (" + tx.Description + ")"
+}
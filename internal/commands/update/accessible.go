@@ -0,0 +1,48 @@
+package update
+
+import (
+	"fmt"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// choosePlain asks which packages to update with numbered prompts instead of
+// the selection list, for accessible mode, then reviews the choice
+func choosePlain(deps []*Dependency, goVersion string) ([]*Dependency, error) {
+	var outdated []*Dependency
+	for _, dep := range deps {
+		if !dep.UpToDate {
+			outdated = append(outdated, dep)
+		}
+	}
+	if len(outdated) == 0 {
+		return nil, nil
+	}
+
+	options := make([]string, len(outdated))
+	for i, dep := range outdated {
+		kind := "indirect"
+		if dep.Direct {
+			kind = "direct"
+		}
+		options[i] = fmt.Sprintf("%s, %s to %s, %s update, %s", dep.label(), dep.Current, dep.Target, versions.Classify("v"+dep.Current, dep.TargetRaw), kind)
+	}
+
+	picked, err := ui.PromptChoice(fmt.Sprintf("%s package(s) can be updated:", ui.FormatCount(len(outdated))), options, true)
+	if err != nil || len(picked) == 0 {
+		return nil, err
+	}
+	selected := make([]*Dependency, len(picked))
+	for i, idx := range picked {
+		selected[i] = outdated[idx]
+	}
+
+	fmt.Printf("\nReview updates: %s\n", updateCounts(selected))
+	fmt.Println(toolchainImpact(selected, goVersion))
+	ok, err := ui.PromptConfirm("Update these packages?")
+	if err != nil || !ok {
+		return nil, err
+	}
+	return selected, nil
+}
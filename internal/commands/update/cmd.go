@@ -3,7 +3,15 @@ package update
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/omarshaarawi/gx/internal/completion"
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -12,13 +20,28 @@ var (
 	flagDryRun      bool
 	flagAll         bool
 	flagMajor       bool
+	flagRewrite     bool
 	flagVendor      bool
+	flagTest        bool
+	flagCommit      bool
+	flagPR          bool
+	flagForce       bool
+	flagAudit       bool
+	flagPre         bool
+	flagPlanOut     string
+	flagApply       string
+	flagOrg         string
+	flagPorcelain   bool
+	flagExclude     []string
+	flagDirectOnly  bool
+	flagIndirect    bool
+	flagTarget      string
 )
 
 // NewCommand creates the update command
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "update",
+		Use:   "update [module[@version]...]",
 		Short: "Update Go module dependencies",
 		Long: `Update Go module dependencies interactively or automatically.
 
@@ -30,38 +53,295 @@ Examples:
   # Update all outdated dependencies
   gx update --all
 
+  # Update specific modules, to their latest or to a given version
+  gx update github.com/spf13/cobra golang.org/x/mod@v0.22.0
+
   # Dry run (see what would be updated)
   gx update -i --dry-run
 
-  # Include major version updates
-  gx update -i --major`,
-		RunE: runUpdate,
+  # Include major version updates, moving imports to the new module paths
+  gx update -i --major
+  gx update --all --major --rewrite-imports
+
+  # Offer pre-releases newer than the latest release
+  gx update -i --pre
+
+  # Build and test after updating, rolling back if that fails
+  gx update --all --test
+
+  # One commit per update, e.g. "deps: bump golang.org/x/mod from v0.21.0 to v0.22.0"
+  gx update --all --commit
+
+  # Open a GitHub pull request (a merge request on GitLab) with the updates
+  gx update --all --pr --audit
+
+  # Report which vulnerabilities the update fixed
+  gx update --all --audit
+
+  # Update every golang.org/x module, keeping lockstep families together
+  gx update --org golang.org/x
+
+  # Update everything but the Kubernetes modules, e.g. in automation
+  gx update --all --exclude 'k8s.io/*'
+
+  # Take only patch releases, or stay within the current major version
+  gx update --all --target patch
+  gx update -i --target minor
+
+  # Update only the requirements go.mod doesn't mark // indirect
+  gx update --all --direct-only
+
+  # Plan in CI, review, then apply exactly that plan
+  gx update --all --plan-out plan.json
+  gx update --apply plan.json
+
+After updating, a plain-text summary lists the updates by type, whether the
+go directive or toolchain had to change, new indirect dependencies and the
+commands that ran, ready to paste into a commit message. With --audit,
+vulnerabilities fixed by the update are listed too.
+
+Modules given as arguments are the only ones updated, to the version given
+after @ or else as --all would. The version may be any query the proxy
+resolves, such as latest or a branch name, but not older than the required
+one (use gx downgrade for that). A module given without a version brings the
+rest of its family along; one given with a version moves alone.
+
+--plan-out writes the selected updates to a JSON plan instead of applying
+them, an empty plan when everything is up to date. --apply applies a plan
+without looking anything up, and fails if go.mod, go.sum or the planned
+requirements changed since it was made; an empty plan applies as a no-op.
+Plans cover a single module.
+
+--org updates every module under a path prefix, or lets -i choose among
+them. When all of them require the same version, as aws-sdk-go-v2 service
+modules or k8s.io/api and client-go do, they move together to the newest
+version every one of them has published.
+
+--exclude skips modules matching patterns with --all, --org and -i, like
+modules pinned in the config. Patterns are globs or /regex/, as for gx
+outdated --exclude, comma-separated, and the flag repeats; "k8s.io/*" or
+"k8s.io/..." match everything under k8s.io.
+
+--direct-only and --indirect-only limit the update to requirements go.mod
+lists without or with // indirect; nothing else is looked up, offered or
+written. update_scope in the config (direct, indirect or all) sets the
+default, which the flags override, as --direct-only=false does. Modules
+given as arguments are updated whatever the scope.
+
+--target limits how far updates go: patch takes the newest patch release of
+each module's current minor version, minor the newest version below its next
+major version, and latest (the default) the newest version. The target
+narrows any constraint configured for a module. -i shows the target next to
+the latest version, and --all and --dry-run list the latest where it differs.
+
+In -i, tab opens the release notes of the module under the cursor between
+its current and target version, or the commits between them when the
+module has no GitHub releases. / filters the list by module path, fuzzily;
+enter keeps the filter and esc clears it. Selections hidden by the filter
+are kept, and a, n and i act only on the modules it shows.
+
+--major looks past the module path for higher major versions, probing the
+proxy for example.com/lib/v2, v3 and so on (or gopkg.in/yaml.v3 after .v2),
+and updates to the latest version of the highest one, moving the requirement
+in go.mod to the new path. --rewrite-imports then points the imports of the
+module's .go files at it, leaving the rest of each file untouched; without
+it, files still importing the old path are listed and go mod tidy is left
+for after they're updated. Families, configured constraints and modules
+given with a version keep their major version.
+
+Modules grouped under families in the config always move together, to the
+newest version every module of the family has published. Selecting one of
+them with -i selects the rest. The Kubernetes staging modules (k8s.io/api,
+k8s.io/apimachinery, k8s.io/client-go, ...) are a family by default.
+
+--test runs go build ./... and go test ./... after go mod tidy (and go mod
+vendor). If tidy, the build or the tests fail, go.mod and go.sum are
+restored from the snapshot gx undo would use, imports rewritten by
+--rewrite-imports are put back, and gx lists the batch of updates that was
+rolled back and exits with an error. In a workspace each member module is a
+batch, tested after its own updates; members updated before the failing one
+keep their updates.
+
+--commit applies the updates one at a time, each followed by go mod tidy
+(and --vendor and --test when given), and commits each to git with a
+conventional message, "deps: bump <module> from <old> to <new>", whose body
+is the update's summary. A family goes in one commit. The work tree must be
+clean to start; an update that fails stops the run, keeping the commits
+made before it.
+
+--pr commits the updates on a new branch (gx/update-<time>), pushes it to
+origin and opens a pull request against the branch checked out. The
+description has a table of the updates, the GitHub release notes between
+the old and new versions, the vulnerabilities fixed when --audit is given
+and the update summary. With --commit each update gets its own commit on
+the branch. The work tree must be clean and origin on GitHub or GitLab.
+On GitHub the token comes from GITHUB_TOKEN, GH_TOKEN or github.token in
+the config (github.api_url or GITHUB_API_URL for a GitHub Enterprise
+server, which origin is then on; release notes still come from github.com). On
+GitLab a merge request is opened instead, removing the branch once merged,
+with the token from GITLAB_TOKEN or gitlab.token (gitlab.url or
+CI_SERVER_URL for a self-hosted instance). Pull requests cover ./go.mod
+only, ignoring go.work.
+
+Updates of google.golang.org/protobuf or grpc warn about .pb.go files whose
+headers show they were generated by an older protoc-gen-go or
+protoc-gen-go-grpc than the new runtime still supports, with the commands
+to install the generators to regenerate them with. Runtimes support code
+from older generators for a long time, so routine updates don't warn.
+
+--porcelain prints tab-separated records for scripts on stdout, in the
+versioned format gx outdated --porcelain uses, and everything else on
+stderr. The first record is "version 1 update", then one per update:
+
+  update <member> <module> <from> <to> <type> <state>
+
+where state is updated, would-update (--dry-run), planned (--plan-out) or
+rolled-back (--test failed),
+and with --audit one per vulnerability the update fixed:
+
+  fixed <member> <id> <module> <severity>
+
+member is the workspace module being updated, empty outside a workspace.
+
+Inside a go.work workspace each member module is updated in turn.
+Set GOWORK=off to update only ./go.mod.`,
+		ValidArgsFunction: completion.OptionalModuleVersions(),
+		RunE:              runUpdate,
 	}
 
 	cmd.Flags().BoolVarP(&flagInteractive, "interactive", "i", false, "Interactive mode with TUI")
-	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show what would be updated without making changes")
+	dryrun.AddFlag(cmd, &flagDryRun)
 	cmd.Flags().BoolVar(&flagAll, "all", false, "Update all outdated dependencies")
 	cmd.Flags().BoolVar(&flagMajor, "major", false, "Include major version updates")
+	cmd.Flags().BoolVar(&flagRewrite, "rewrite-imports", false, "Rewrite imports of modules moved to a new major version")
 	cmd.Flags().BoolVar(&flagVendor, "vendor", false, "Run 'go mod vendor' after tidy")
+	cmd.Flags().BoolVar(&flagTest, "test", false, "Run 'go build ./...' and 'go test ./...' after updating and roll back if either fails")
+	cmd.Flags().BoolVar(&flagCommit, "commit", false, "Apply and git commit each update on its own")
+	cmd.Flags().BoolVar(&flagPR, "pr", false, "Commit the updates on a new branch, push it and open a GitHub pull request or GitLab merge request")
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Overwrite go.mod even if it changed on disk while gx was running")
+	cmd.Flags().BoolVar(&flagAudit, "audit", false, "Scan for vulnerabilities before and after to report what was fixed")
+	cmd.Flags().BoolVar(&flagPre, "pre", false, "Update to pre-release versions when they are newer than the latest release")
+	cmd.Flags().StringVar(&flagPlanOut, "plan-out", "", "Write the selected updates to a plan file instead of applying them")
+	cmd.Flags().StringVar(&flagApply, "apply", "", "Apply a plan file written by --plan-out")
+	cmd.Flags().StringVar(&flagOrg, "org", "", "Update every module under this path prefix (e.g. golang.org/x)")
+	cmd.Flags().BoolVar(&flagPorcelain, "porcelain", false, "Print stable tab-separated records for scripts")
+	cmd.Flags().StringArrayVar(&flagExclude, "exclude", nil, "Skip modules matching patterns (comma-separated, repeatable)")
+	cmd.Flags().BoolVar(&flagDirectOnly, "direct-only", false, "Only update direct requirements")
+	cmd.Flags().BoolVar(&flagIndirect, "indirect-only", false, "Only update indirect requirements")
+	cmd.Flags().StringVar(&flagTarget, "target", versions.TargetLatest, "How far to update: patch, minor or latest")
+	cmd.RegisterFlagCompletionFunc("org", completion.Prefixes())
+	cmd.RegisterFlagCompletionFunc("target", cobra.FixedCompletions([]string{versions.TargetPatch, versions.TargetMinor, versions.TargetLatest}, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	modPath := "go.mod"
-	if _, err := os.Stat(modPath); os.IsNotExist(err) {
-		return fmt.Errorf("go.mod not found in current directory")
+	modPath := modflag.Path(cmd)
+	workPath, inWorkspace := workspace.Detect(filepath.Dir(modPath))
+	if _, err := os.Stat(modPath); os.IsNotExist(err) && !inWorkspace {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if flagDirectOnly && flagIndirect {
+		return fmt.Errorf("--direct-only and --indirect-only can't be combined")
+	}
+	scope := updateScope(cmd)
+
+	if flagApply != "" && (flagPlanOut != "" || flagInteractive || flagAll || flagOrg != "" || len(args) > 0 || len(flagExclude) > 0 || scope != "" || cmd.Flags().Changed("target")) {
+		return fmt.Errorf("--apply can't be combined with module arguments, --plan-out, -i, --all, --org, --exclude, --direct-only, --indirect-only or --target")
+	}
+
+	if len(args) > 0 && (flagInteractive || flagAll || flagOrg != "" || len(flagExclude) > 0 || scope != "") {
+		return fmt.Errorf("module arguments can't be combined with -i, --all, --org, --exclude, --direct-only or --indirect-only")
+	}
+
+	var exclude []string
+	for _, e := range flagExclude {
+		exclude = append(exclude, pattern.SplitFilter(e)...)
+	}
+
+	switch flagTarget {
+	case versions.TargetPatch, versions.TargetMinor:
+		if flagMajor {
+			return fmt.Errorf("--major can't be combined with --target %s", flagTarget)
+		}
+	case versions.TargetLatest:
+	default:
+		return fmt.Errorf("--target is %q, want patch, minor or latest", flagTarget)
+	}
+
+	if flagRewrite && !flagMajor && flagApply == "" {
+		return fmt.Errorf("--rewrite-imports needs --major")
+	}
+
+	if flagPorcelain && flagInteractive {
+		return fmt.Errorf("--porcelain and -i can't be combined")
+	}
+
+	if flagInteractive {
+		if err := ui.RequireInteractive("gx update -i", "name the modules to update or pass --all"); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	// Plans and pull requests cover one module, so they ignore go.work
+	if flagPlanOut != "" || flagApply != "" || flagPR {
+		if _, err := os.Stat(modPath); os.IsNotExist(err) {
+			return fmt.Errorf("--plan-out, --apply and --pr need a go.mod, and none was found %s", modflag.Where(modPath))
+		}
+		workPath = ""
+	}
+
+	if (flagTest || flagCommit || flagPR) && (flagDryRun || flagPlanOut != "") {
+		return fmt.Errorf("--test, --commit and --pr can't be combined with --dry-run or --plan-out")
+	}
+
+	if flagApply != "" || len(args) > 0 || flagTest || flagCommit || flagPR {
+		// A plan that no longer applies, a module that can't be updated,
+		// an update that fails its tests or a dirty work tree isn't a
+		// usage error
+		cmd.SilenceUsage = true
 	}
 
 	opts := Options{
-		Interactive: flagInteractive,
-		DryRun:      flagDryRun,
-		All:         flagAll,
-		Major:       flagMajor,
-		Vendor:      flagVendor,
-		ModPath:     modPath,
+		Interactive:    flagInteractive,
+		DryRun:         flagDryRun,
+		All:            flagAll,
+		Major:          flagMajor,
+		RewriteImports: flagRewrite,
+		Vendor:         flagVendor,
+		Test:           flagTest,
+		Commit:         flagCommit,
+		PR:             flagPR,
+		Force:          flagForce,
+		Audit:          flagAudit,
+		Pre:            flagPre,
+		ModPath:        modPath,
+		Workspace:      workPath,
+		PlanOut:        flagPlanOut,
+		Apply:          flagApply,
+		Org:            flagOrg,
+		Porcelain:      flagPorcelain,
+		Modules:        args,
+		Exclude:        exclude,
+		Scope:          scope,
+		Target:         flagTarget,
 	}
 
 	return Run(cmd.Context(), opts)
 }
 
+// updateScope returns the scope given by --direct-only or --indirect-only,
+// or "" to use the config. Turning either off explicitly updates both kinds.
+func updateScope(cmd *cobra.Command) string {
+	switch {
+	case flagDirectOnly:
+		return "direct"
+	case flagIndirect:
+		return "indirect"
+	case cmd.Flags().Changed("direct-only") || cmd.Flags().Changed("indirect-only"):
+		return "all"
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package update
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/omarshaarawi/gx/internal/protogen"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// maxStaleListed is how many stale generated files are listed by name
+const maxStaleListed = 10
+
+// warnStaleCodegen warns when updating google.golang.org/protobuf or grpc
+// leaves code in the module generated by an older protoc-gen-go or
+// protoc-gen-go-grpc than the new runtime supports, and says how to
+// regenerate
+func warnStaleCodegen(dir string, toUpdate []*Dependency) {
+	var runtimes []*Dependency
+	for _, dep := range toUpdate {
+		if dep.Name == protogen.ProtobufModule || dep.Name == protogen.GRPCModule {
+			runtimes = append(runtimes, dep)
+		}
+	}
+	if len(runtimes) == 0 {
+		return
+	}
+
+	files, err := protogen.Scan(dir)
+	if err != nil {
+		ui.Debug("%v", err)
+		return
+	}
+
+	var stale []protogen.Stale
+	for _, dep := range runtimes {
+		stale = append(stale, protogen.Check(files, dep.Name, dep.TargetRaw)...)
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⚠️  %s generated file(s) are older than the updated runtime supports:\n", ui.FormatCount(len(stale)))
+	width := 0
+	for _, s := range stale[:min(len(stale), maxStaleListed)] {
+		width = max(width, len(s.Path))
+	}
+	var installs []string
+	for i, s := range stale {
+		if i < maxStaleListed {
+			fmt.Printf("  %-*s  %s\n", width, s.Path, ui.UpToDateStyle.Render(fmt.Sprintf("%s %s, the runtime needs %s or later", s.Generator, s.Version, s.Oldest)))
+		}
+		if install := s.Install(); !slices.Contains(installs, install) {
+			installs = append(installs, install)
+		}
+	}
+	if len(stale) > maxStaleListed {
+		fmt.Printf("  … and %s more\n", ui.FormatCount(len(stale)-maxStaleListed))
+	}
+
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Regenerate them with the newer generators (e.g. go generate ./... or buf generate) after:"))
+	for _, install := range installs {
+		fmt.Printf("   %s\n", install)
+	}
+}
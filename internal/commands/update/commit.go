@@ -0,0 +1,149 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"golang.org/x/mod/semver"
+)
+
+// checkCleanTree fails unless dir is in a git work tree without changes, so
+// each commit holds only its update. flag names the option that commits.
+func checkCleanTree(ctx context.Context, dir, flag string) error {
+	if _, err := gitcmd.Output(ctx, dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return fmt.Errorf("%s needs a git repository: %w", flag, err)
+	}
+	status, err := gitcmd.Output(ctx, dir, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		return fmt.Errorf("%s needs a clean work tree; commit or stash these changes first:\n%s", flag, status)
+	}
+	return nil
+}
+
+// commitUpdates applies the updates one at a time, each with go mod tidy and
+// whatever else the options ask for, and commits each to git. Families move
+// in a single commit. It stops at the first update that fails, keeping the
+// commits made before it, and returns the digest of each commit.
+func commitUpdates(ctx context.Context, opts Options, parser *modfile.Parser, toUpdate []*Dependency, families []family) ([]*digest, error) {
+	workDir := filepath.Dir(opts.ModPath)
+	var commits []*digest
+
+	for _, b := range commitBatches(toUpdate, families) {
+		// Tidying an earlier update can raise or drop a later one
+		if err := parser.Reload(); err != nil {
+			return commits, fmt.Errorf("reading go.mod: %w", err)
+		}
+		batch := pending(parser, b.deps)
+		if len(batch) == 0 {
+			continue
+		}
+		subject := commitSubject(b.label, batch)
+
+		fmt.Printf("\n%s\n", ui.HeaderStyle.Render("📦 "+subject))
+		summary, err := applyBatch(ctx, opts, parser, batch)
+		if err != nil {
+			return commits, err
+		}
+		if err := commitAll(ctx, workDir, subject, summary.String()); err != nil {
+			return commits, err
+		}
+		commits = append(commits, summary)
+		fmt.Printf("✓ Committed %s\n", subject)
+	}
+
+	fmt.Printf("\n%s made %s commit(s)\n", ui.SummaryStyle.Render("📊 Commit summary:"), ui.FormatCount(len(commits)))
+	return commits, nil
+}
+
+// commitAll commits every change in dir, which holds only the update's with
+// the work tree clean to start
+func commitAll(ctx context.Context, dir, subject, body string) error {
+	if err := gitcmd.Run(ctx, dir, "add", "-A", "--", "."); err != nil {
+		return err
+	}
+	return gitcmd.Run(ctx, dir, "commit", "-q", "-m", subject, "-m", body)
+}
+
+// updatedIn counts the updates of the digests
+func updatedIn(digests []*digest) int {
+	n := 0
+	for _, d := range digests {
+		n += len(d.Updated)
+	}
+	return n
+}
+
+// commitBatch is the updates that go in one commit
+type commitBatch struct {
+	label string // the family's, empty for a single module
+	deps  []*Dependency
+}
+
+// commitBatches splits the updates into what goes in each commit: a family
+// together, every other module alone, in the order of the updates
+func commitBatches(toUpdate []*Dependency, families []family) []*commitBatch {
+	familyOf := make(map[*Dependency]family)
+	for _, f := range families {
+		for _, dep := range f.deps {
+			familyOf[dep] = f
+		}
+	}
+
+	var batches []*commitBatch
+	byFamily := make(map[string]*commitBatch)
+	for _, dep := range toUpdate {
+		f, ok := familyOf[dep]
+		if !ok {
+			batches = append(batches, &commitBatch{deps: []*Dependency{dep}})
+			continue
+		}
+		if b, ok := byFamily[f.label]; ok {
+			b.deps = append(b.deps, dep)
+			continue
+		}
+		byFamily[f.label] = &commitBatch{label: f.label, deps: []*Dependency{dep}}
+		batches = append(batches, byFamily[f.label])
+	}
+	return batches
+}
+
+// pending drops the updates go.mod no longer needs, those an earlier commit
+// dropped or already raised to the target, and refreshes the current version
+// of the rest
+func pending(parser *modfile.Parser, batch []*Dependency) []*Dependency {
+	var left []*Dependency
+	for _, dep := range batch {
+		req := parser.FindRequire(dep.Name)
+		switch {
+		case req == nil:
+			fmt.Printf("\n%s is no longer required, skipping\n", dep.Name)
+		case dep.NewPath == "" && semver.Compare(req.Mod.Version, dep.TargetRaw) >= 0:
+			fmt.Printf("\n%s is already at %s, skipping\n", dep.Name, req.Mod.Version)
+		default:
+			dep.Current = strings.TrimPrefix(req.Mod.Version, "v")
+			left = append(left, dep)
+		}
+	}
+	return left
+}
+
+// commitSubject is the conventional commit subject for a batch of updates,
+// naming the family when there's more than one module
+func commitSubject(label string, batch []*Dependency) string {
+	if len(batch) > 1 {
+		return fmt.Sprintf("deps: bump %s to %s", label, batch[0].TargetRaw)
+	}
+	dep := batch[0]
+	if dep.NewPath != "" {
+		return fmt.Sprintf("deps: bump %s from v%s to %s %s", dep.Name, dep.Current, dep.NewPath, dep.TargetRaw)
+	}
+	return fmt.Sprintf("deps: bump %s from v%s to %s", dep.Name, dep.Current, dep.TargetRaw)
+}
@@ -0,0 +1,102 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+var jumpStyle = lipgloss.NewStyle().Width(30)
+
+// confirmView summarizes the selection before go.mod is written
+func (m model) confirmView() string {
+	selected := m.selectedDeps()
+
+	titleText := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Render("📋 Review updates")
+
+	helpText := ui.ShortKeyHelp(
+		joinKeys("update", ui.Key("confirm"), ui.Key("yes")),
+		joinKeys("go back", ui.Key("back"), ui.Key("no")),
+		joinKeys("cancel", ui.Key("quit")),
+		ui.Key("help"),
+	)
+
+	var b strings.Builder
+	b.WriteString("\n" + titleText + "\n" + helpText + "\n\n")
+	b.WriteString("  " + headerStyle.Render(updateCounts(selected)) + "\n\n")
+
+	// Leave room for the header and the toolchain line
+	limit := max(m.height-10, 5)
+	for i, dep := range selected {
+		if i == limit {
+			b.WriteString(dimmedStyle.Render(fmt.Sprintf("      … and %s more", ui.FormatCount(len(selected)-limit))) + "\n")
+			break
+		}
+
+		updateType := versions.Classify("v"+dep.Current, dep.TargetRaw)
+		row := fmt.Sprintf("%s %s %s",
+			pkgNameStyle.Render(dep.label()),
+			jumpStyle.Render(dep.Current+" → "+dep.Target),
+			ui.UpdateSymbol(updateType)+" "+updateType,
+		)
+		if updateType == versions.Major {
+			row = ui.MajorStyle.Bold(true).Render(row)
+		}
+		b.WriteString("    " + row + "\n")
+	}
+
+	b.WriteString("\n  " + toolchainImpact(selected, m.goVersion) + "\n")
+	return b.String()
+}
+
+// updateCounts counts updates by type, e.g. "3 package(s): ▲ 1 major, ● 2 minor"
+func updateCounts(deps []*Dependency) string {
+	counts := make(map[string]int)
+	for _, dep := range deps {
+		counts[versions.Classify("v"+dep.Current, dep.TargetRaw)]++
+	}
+
+	var parts []string
+	for _, t := range []string{versions.Major, versions.Minor, versions.Patch} {
+		if counts[t] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s %s", ui.UpdateSymbol(t), ui.FormatCount(counts[t]), t))
+		}
+	}
+	return fmt.Sprintf("%s package(s): %s", ui.FormatCount(len(deps)), strings.Join(parts, ", "))
+}
+
+// toolchainImpact predicts whether the selected updates will raise the go
+// directive, from the go directives of the versions they update to
+func toolchainImpact(deps []*Dependency, goVersion string) string {
+	highest, requiredBy := "", ""
+	for _, dep := range deps {
+		if dep.GoVersion != "" && versions.CompareGo(dep.GoVersion, highest) > 0 {
+			highest, requiredBy = dep.GoVersion, dep.Name
+		}
+	}
+
+	switch {
+	case highest == "":
+		return "Toolchain: no go directive change expected"
+	case versions.CompareGo(highest, goVersion) > 0:
+		return ui.MinorStyle.Render(fmt.Sprintf("Toolchain: go directive %s → %s (required by %s)", orNone(goVersion), highest, requiredBy))
+	}
+	return fmt.Sprintf("Toolchain: go directive stays at %s", goVersion)
+}
+
+// joinKeys shows bindings that do the same thing as one, e.g. "enter/y update"
+func joinKeys(help string, bindings ...key.Binding) key.Binding {
+	var keys, names []string
+	for _, b := range bindings {
+		keys = append(keys, b.Keys()...)
+		names = append(names, b.Help().Key)
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(names, "/"), help))
+}
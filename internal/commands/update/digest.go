@@ -0,0 +1,174 @@
+package update
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// digest summarizes an update run in plain text that can be pasted into a
+// commit message body
+type digest struct {
+	Updated  []*Dependency
+	Commands []string
+
+	GoBefore, GoAfter               string
+	ToolchainBefore, ToolchainAfter string
+
+	NewIndirect []string
+
+	// Scanned is set when vulnerability scans ran before and after the update
+	Scanned   bool
+	Fixed     []*vulndb.Vulnerability
+	Remaining int
+}
+
+// snapshot is the part of go.mod the digest compares before and after
+type snapshot struct {
+	goVersion string
+	toolchain string
+	requires  map[string]bool
+}
+
+func takeSnapshot(parser *modfile.Parser) snapshot {
+	s := snapshot{
+		goVersion: parser.GoVersion(),
+		toolchain: parser.Toolchain(),
+		requires:  make(map[string]bool),
+	}
+	for _, req := range parser.AllRequires() {
+		s.requires[req.Mod.Path] = true
+	}
+	return s
+}
+
+// compare fills in the go directive, toolchain and new indirect requirements
+// from go.mod as it is after the update
+func (d *digest) compare(before snapshot, modPath string) error {
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		return err
+	}
+
+	d.GoBefore, d.GoAfter = before.goVersion, parser.GoVersion()
+	d.ToolchainBefore, d.ToolchainAfter = before.toolchain, parser.Toolchain()
+	moved := make(map[string]bool)
+	for _, dep := range d.Updated {
+		if dep.NewPath != "" {
+			moved[dep.NewPath] = true
+		}
+	}
+	for _, req := range parser.AllRequires() {
+		if req.Indirect && !before.requires[req.Mod.Path] && !moved[req.Mod.Path] {
+			d.NewIndirect = append(d.NewIndirect, req.Mod.Path+" "+req.Mod.Version)
+		}
+	}
+	sort.Strings(d.NewIndirect)
+	return nil
+}
+
+// compareScans records the vulnerabilities present before but not after
+func (d *digest) compareScans(before, after *vulndb.ScanResult) {
+	remaining := make(map[string]bool)
+	for _, v := range after.Vulnerabilities {
+		remaining[v.ID+" "+v.Package] = true
+	}
+	for _, v := range before.Vulnerabilities {
+		if !remaining[v.ID+" "+v.Package] {
+			d.Fixed = append(d.Fixed, v)
+		}
+	}
+	vulndb.Sort(d.Fixed)
+	d.Scanned = true
+	d.Remaining = len(after.Vulnerabilities)
+}
+
+// String renders the digest without styling
+func (d *digest) String() string {
+	var b strings.Builder
+
+	major, minor, patch := 0, 0, 0
+	for _, dep := range d.Updated {
+		switch versions.Classify("v"+dep.Current, dep.TargetRaw) {
+		case versions.Major:
+			major++
+		case versions.Minor:
+			minor++
+		case versions.Patch:
+			patch++
+		}
+	}
+	fmt.Fprintf(&b, "Updated %d module(s): %d major, %d minor, %d patch\n", len(d.Updated), major, minor, patch)
+	for _, dep := range d.Updated {
+		if dep.NewPath != "" {
+			fmt.Fprintf(&b, "  %s v%s => %s %s\n", dep.Name, dep.Current, dep.NewPath, dep.TargetRaw)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s v%s => %s\n", dep.Name, dep.Current, dep.TargetRaw)
+	}
+
+	b.WriteString("\nToolchain bump required: ")
+	switch {
+	case d.GoAfter != d.GoBefore && d.ToolchainAfter != d.ToolchainBefore:
+		fmt.Fprintf(&b, "yes (go %s => %s, toolchain %s => %s)\n", d.GoBefore, d.GoAfter, orNone(d.ToolchainBefore), orNone(d.ToolchainAfter))
+	case d.GoAfter != d.GoBefore:
+		fmt.Fprintf(&b, "yes (go %s => %s)\n", d.GoBefore, d.GoAfter)
+	case d.ToolchainAfter != d.ToolchainBefore:
+		fmt.Fprintf(&b, "yes (toolchain %s => %s)\n", orNone(d.ToolchainBefore), orNone(d.ToolchainAfter))
+	default:
+		b.WriteString("no\n")
+	}
+
+	if len(d.NewIndirect) == 0 {
+		b.WriteString("New indirect dependencies: none\n")
+	} else {
+		fmt.Fprintf(&b, "New indirect dependencies: %d\n", len(d.NewIndirect))
+		for _, dep := range d.NewIndirect {
+			fmt.Fprintf(&b, "  %s\n", dep)
+		}
+	}
+
+	if d.Scanned {
+		fmt.Fprintf(&b, "Vulnerabilities fixed: %d (%d remaining)\n", len(d.Fixed), d.Remaining)
+		for _, v := range d.Fixed {
+			fmt.Fprintf(&b, "  %s %s (%s)\n", v.ID, v.Package, strings.ToLower(v.Severity))
+		}
+	}
+
+	b.WriteString("\nCommands:\n")
+	for _, c := range d.Commands {
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	return b.String()
+}
+
+// print shows the digest under a header, ready to copy
+func (d *digest) print() {
+	fmt.Printf("\n%s\n\n", ui.HeaderStyle.Render("📝 What's new"))
+	fmt.Print(d.String())
+}
+
+// commandLine is how gx was invoked, for the digest's command list
+func commandLine() string {
+	args := append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...)
+	for i, a := range args {
+		if strings.ContainsAny(a, " \t'\"") {
+			args[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
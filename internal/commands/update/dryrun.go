@@ -0,0 +1,55 @@
+package update
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/omarshaarawi/gx/internal/dryrun"
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"github.com/omarshaarawi/gx/internal/modfile"
+)
+
+// previewUpdates prints what applying the updates would do: the diff of
+// go.mod and of the source files --rewrite-imports would change, and the go
+// commands that would follow. The parsed go.mod is left edited.
+func previewUpdates(opts Options, parser *modfile.Parser, toUpdate []*Dependency) error {
+	var plan dryrun.Plan
+	err := plan.EditMod(opts.ModPath, parser, func(writer *modfile.Writer) error {
+		for _, dep := range toUpdate {
+			if err := updateRequire(writer, dep); err != nil {
+				return fmt.Errorf("updating %s: %w", dep.Name, err)
+			}
+		}
+		writer.Cleanup()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	workDir := filepath.Dir(opts.ModPath)
+	tidy := true
+	if moves := majorMoves(toUpdate); len(moves) > 0 {
+		if opts.RewriteImports {
+			files, err := importpath.Rewritten(workDir, moves)
+			if err != nil {
+				return fmt.Errorf("rewriting imports: %w", err)
+			}
+			for _, f := range files {
+				plan.Change(filepath.Join(workDir, f.Path), f.Before, f.After)
+			}
+		} else {
+			tidy = !warnImporters(workDir, moves)
+		}
+	}
+	if tidy {
+		plan.Tidy(opts.ModPath)
+		if opts.Vendor {
+			plan.Run(workDir, "go", "mod", "vendor")
+		}
+	}
+
+	plan.Print(os.Stdout)
+	return nil
+}
@@ -0,0 +1,218 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"golang.org/x/mod/semver"
+)
+
+// family is a group of required modules that must share a version: one from
+// the families config, or the modules under --org when they already share one
+type family struct {
+	label string // "the kubernetes family" or "modules under golang.org/x"
+	deps  []*Dependency
+}
+
+// familyPattern turns an --org prefix such as golang.org/x into a pattern
+// matching the prefix and every module below it
+func familyPattern(prefix string) string {
+	prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "/..."), "/*")
+	return strings.TrimSuffix(prefix, "/") + "/..."
+}
+
+// kubernetesFamily is the family of Kubernetes staging modules without a
+// configured one
+const kubernetesFamily = "kubernetes"
+
+// familyFor returns the configured family of a module. Kubernetes staging
+// modules, released together as v0.X.Y for Kubernetes 1.X.Y, form the
+// kubernetes family unless configured otherwise.
+func familyFor(cfg *config.Config) func(modulePath string) string {
+	return func(modulePath string) string {
+		if name := cfg.FamilyFor(modulePath); name != "" {
+			return name
+		}
+		if versions.IsKubernetesStaging(modulePath) {
+			return kubernetesFamily
+		}
+		return ""
+	}
+}
+
+// configuredFamilies groups the dependencies by their configured family,
+// keeping the families with more than one module in go.mod
+func configuredFamilies(deps []*Dependency, familyFor func(modulePath string) string) []family {
+	members := make(map[string][]*Dependency)
+	for _, dep := range deps {
+		if name := familyFor(dep.Name); name != "" {
+			members[name] = append(members[name], dep)
+		}
+	}
+
+	var families []family
+	for name, deps := range members {
+		if len(deps) > 1 {
+			families = append(families, family{label: "the " + name + " family", deps: deps})
+		}
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].label < families[j].label
+	})
+	return families
+}
+
+// orgFamily treats the modules selected by --org as a family when they all
+// require the same version, as modules released in lockstep do
+func orgFamily(deps []*Dependency, org string) (family, bool) {
+	if len(deps) < 2 {
+		return family{}, false
+	}
+	for _, dep := range deps {
+		if dep.Current != deps[0].Current {
+			return family{}, false
+		}
+	}
+	return family{label: "modules under " + org, deps: deps}, true
+}
+
+// align moves every module of the family to the newest version all of them
+// have published, no older than any of them requires and no newer than the
+// lowest update target, so constraints and retractions still apply. When
+// there is no such version the family is left as it is.
+func (f family) align(ctx context.Context, client *proxy.Client, pre bool) {
+	floor, ceiling := "", ""
+	for i, dep := range f.deps {
+		if current := "v" + dep.Current; i == 0 || semver.Compare(current, floor) > 0 {
+			floor = current
+		}
+		if i == 0 || semver.Compare(dep.TargetRaw, ceiling) < 0 {
+			ceiling = dep.TargetRaw
+		}
+	}
+
+	var shared map[string]bool
+	for _, dep := range f.deps {
+		list, err := client.Versions(ctx, dep.Name)
+		if err != nil {
+			f.hold(fmt.Sprintf("listing versions of %s: %v", dep.Name, err))
+			return
+		}
+		published := make(map[string]bool, len(list))
+		for _, v := range list {
+			if shared == nil || shared[v] {
+				published[v] = true
+			}
+		}
+		shared = published
+	}
+
+	common := ""
+	for v := range shared {
+		if semver.Compare(v, floor) < 0 || semver.Compare(v, ceiling) > 0 {
+			continue
+		}
+		if !pre && semver.Prerelease(v) != "" && v != floor {
+			continue
+		}
+		if semver.Compare(v, common) > 0 {
+			common = v
+		}
+	}
+	if common == "" {
+		f.hold(fmt.Sprintf("no version from %s to %s is published for all of its modules", floor, ceiling))
+		return
+	}
+
+	changed := false
+	for _, dep := range f.deps {
+		if dep.TargetRaw == common {
+			continue
+		}
+		changed = true
+		dep.Target, dep.TargetRaw = strings.TrimPrefix(common, "v"), common
+		dep.UpToDate = dep.Current == dep.Target
+		dep.GoVersion = ""
+		if !dep.UpToDate {
+			dep.GoVersion = goDirective(ctx, client, dep.Name, common)
+		}
+	}
+	if changed {
+		fmt.Printf("🔗 Keeping %s aligned at %s%s (%s modules)\n", f.label, common, f.kubernetesRelease(common), ui.FormatCount(len(f.deps)))
+	}
+}
+
+// kubernetesRelease names the Kubernetes release of a version when the family
+// is made of staging modules, e.g. " for Kubernetes 1.31"
+func (f family) kubernetesRelease(version string) string {
+	for _, dep := range f.deps {
+		if !versions.IsKubernetesStaging(dep.Name) {
+			return ""
+		}
+	}
+	if release, ok := versions.KubernetesRelease(version); ok {
+		return " for Kubernetes " + release
+	}
+	return ""
+}
+
+// hold leaves every module of the family at its current version
+func (f family) hold(reason string) {
+	ui.Error("⚠️  Warning: not updating %s: %s\n", f.label, reason)
+	for _, dep := range f.deps {
+		dep.Target, dep.TargetRaw, dep.UpToDate = dep.Current, "v"+dep.Current, true
+	}
+}
+
+// withFamilies adds the other outdated modules of each selected module's
+// family, so picking one of them in -i moves the whole family
+func withFamilies(selected []*Dependency, families []family) []*Dependency {
+	chosen := make(map[*Dependency]bool, len(selected))
+	for _, dep := range selected {
+		chosen[dep] = true
+	}
+
+	for _, f := range families {
+		picked := false
+		for _, dep := range f.deps {
+			picked = picked || chosen[dep]
+		}
+		if !picked {
+			continue
+		}
+		for _, dep := range f.deps {
+			if !chosen[dep] && !dep.UpToDate {
+				fmt.Printf("🔗 Also updating %s with %s\n", dep.Name, f.label)
+				selected = append(selected, dep)
+				chosen[dep] = true
+			}
+		}
+	}
+	return selected
+}
+
+// inFamily reports whether a dependency belongs to one of the families
+func inFamily(families []family, dep *Dependency) bool {
+	for _, f := range families {
+		if slices.Contains(f.deps, dep) {
+			return true
+		}
+	}
+	return false
+}
+
+func allUpToDate(deps []*Dependency) bool {
+	for _, dep := range deps {
+		if !dep.UpToDate {
+			return false
+		}
+	}
+	return true
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
 )
 
 var (
@@ -70,13 +72,21 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		pkgRendered = pkgNameStyle.Render(i.dep.Name)
 	}
 
-	row := fmt.Sprintf("%s %s %s %s %s %s",
+	// The update type is spelled out next to its symbol, since ● also marks direct dependencies
+	update := ""
+	if !i.dep.UpToDate {
+		updateType := versions.Classify("v"+i.dep.Current, i.dep.TargetRaw)
+		update = ui.FormatVersionUpdate(updateType).Render(ui.UpdateSymbol(updateType) + " " + updateType)
+	}
+
+	row := fmt.Sprintf("%s %s %s %s %s %s %s",
 		checkbox,
 		depType,
 		pkgRendered,
 		currentStyle.Render(versionStyle.Render(i.dep.Current)),
 		targetStyle.Render(versionStyle.Render(i.dep.Target)),
 		latestStyle.Render(versionStyle.Render(i.dep.Latest)),
+		update,
 	)
 
 	if index == m.Index() {
@@ -89,10 +99,122 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 type model struct {
 	list         list.Model
 	dependencies []*Dependency
+	all          []list.Item // every row, kept while only the selected ones are shown
+	selectedOnly bool
+	showNotes    bool         // showing the details pane
+	notes        *notesSource // what changed in each update, for the details pane
+	confirming   bool         // showing the summary before updating
+	showHelp     bool         // showing the ? overlay
+	goVersion    string       // go directive of the module being updated
+	width        int
+	height       int
 	quitting     bool
 	confirmed    bool
 }
 
+// headerHeight is the number of lines View puts above the list
+const headerHeight = 9
+
+// toggleCurrent selects or unselects the package under the cursor
+func (m *model) toggleCurrent() {
+	if i, ok := m.list.SelectedItem().(item); ok && !i.dep.UpToDate {
+		i.selected = !i.selected
+		m.refilter(m.list.SetItem(m.list.GlobalIndex(), i))
+	}
+}
+
+// selectShown sets the selection of each outdated row the filter shows to
+// what selected returns for it. Rows the filter hides keep theirs.
+func (m *model) selectShown(selected func(item) bool) {
+	shown := make(map[*Dependency]bool)
+	for _, listItem := range m.list.VisibleItems() {
+		if i, ok := listItem.(item); ok {
+			shown[i.dep] = true
+		}
+	}
+
+	var cmd tea.Cmd
+	for idx, listItem := range m.list.Items() {
+		if i, ok := listItem.(item); ok && shown[i.dep] && !i.dep.UpToDate {
+			i.selected = selected(i)
+			cmd = m.list.SetItem(idx, i)
+		}
+	}
+	m.refilter(cmd)
+}
+
+// refilter runs the filter command SetItem returns right away. The list
+// shows copies of the rows that match, which would otherwise keep their old
+// selection until the command came back.
+func (m *model) refilter(cmd tea.Cmd) {
+	if cmd != nil {
+		m.list, _ = m.list.Update(cmd())
+	}
+}
+
+// toggleSelectedOnly switches between all rows and only the selected ones,
+// clearing any filter. Rows hidden by the selected-only view are
+// unselected, so the selection shown in the list is always the whole
+// selection.
+func (m *model) toggleSelectedOnly() {
+	m.list.ResetFilter()
+	if m.selectedOnly {
+		shown := make(map[*Dependency]bool)
+		for _, listItem := range m.list.Items() {
+			if i, ok := listItem.(item); ok {
+				shown[i.dep] = i.selected
+			}
+		}
+		for idx, listItem := range m.all {
+			if i, ok := listItem.(item); ok {
+				if selected, ok := shown[i.dep]; ok {
+					i.selected = selected
+					m.all[idx] = i
+				}
+			}
+		}
+		m.list.SetItems(m.all)
+	} else {
+		m.all = m.list.Items()
+		var selected []list.Item
+		for _, listItem := range m.all {
+			if i, ok := listItem.(item); ok && i.selected {
+				selected = append(selected, i)
+			}
+		}
+		m.list.SetItems(selected)
+	}
+
+	m.selectedOnly = !m.selectedOnly
+	m.list.ResetSelected()
+}
+
+// selectedDeps returns the selected packages in list order
+func (m model) selectedDeps() []*Dependency {
+	var selected []*Dependency
+	for _, listItem := range m.list.Items() {
+		if i, ok := listItem.(item); ok && i.selected {
+			selected = append(selected, i.dep)
+		}
+	}
+	return selected
+}
+
+// counts returns how many packages are selected, outdated and listed
+func (m model) counts() (selected, outdated, total int) {
+	for _, listItem := range m.list.Items() {
+		if i, ok := listItem.(item); ok && i.selected {
+			selected++
+		}
+	}
+	for _, dep := range m.dependencies {
+		if !dep.UpToDate {
+			outdated++
+		}
+	}
+	return selected, outdated, len(m.dependencies)
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
@@ -100,77 +222,147 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Typing a filter, every key but ctrl+c goes to the list
+		if m.list.SettingFilter() {
+			if msg.String() == "ctrl+c" {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			break
+		}
+		if m.showHelp {
+			switch {
+			case msg.String() == "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+			case key.Matches(msg, ui.Key("help"), key.NewBinding(key.WithKeys("esc"))):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if key.Matches(msg, ui.Key("help")) {
+			m.showHelp = true
+			return m, nil
+		}
+
+		if m.confirming {
+			switch {
+			case key.Matches(msg, ui.Key("quit")):
+				m.quitting = true
+				return m, tea.Quit
+			case key.Matches(msg, ui.Key("confirm"), ui.Key("yes")):
+				m.confirmed = true
+				return m, tea.Quit
+			case key.Matches(msg, ui.Key("back"), ui.Key("no")):
+				m.confirming = false
+			}
+			return m, nil
+		}
+
 		switch {
-		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c", "q"))):
+		case key.Matches(msg, ui.Key("quit")):
 			m.quitting = true
 			return m, tea.Quit
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys(" "))):
-			if i, ok := m.list.SelectedItem().(item); ok && !i.dep.UpToDate {
-				i.selected = !i.selected
-				m.list.SetItem(m.list.Index(), i)
-			}
+		case key.Matches(msg, ui.Key("toggle")):
+			m.toggleCurrent()
 			return m, nil
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
-			items := m.list.Items()
-			for idx, listItem := range items {
-				if i, ok := listItem.(item); ok && !i.dep.UpToDate {
-					i.selected = true
-					m.list.SetItem(idx, i)
-				}
-			}
+		case key.Matches(msg, ui.Key("select-all")):
+			m.selectShown(func(item) bool { return true })
 			return m, nil
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("n"))):
-			items := m.list.Items()
-			for idx, listItem := range items {
-				if i, ok := listItem.(item); ok {
-					i.selected = false
-					m.list.SetItem(idx, i)
-				}
-			}
+		case key.Matches(msg, ui.Key("select-none")):
+			m.selectShown(func(item) bool { return false })
 			return m, nil
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("i"))):
-			items := m.list.Items()
-			for idx, listItem := range items {
-				if i, ok := listItem.(item); ok && !i.dep.UpToDate {
-					i.selected = !i.selected
-					m.list.SetItem(idx, i)
-				}
+		case key.Matches(msg, ui.Key("invert")):
+			m.selectShown(func(i item) bool { return !i.selected })
+			return m, nil
+
+		case key.Matches(msg, ui.Key("selected-only")):
+			m.toggleSelectedOnly()
+			return m, m.fetchNotes()
+
+		case key.Matches(msg, ui.Key("details")):
+			m.showNotes = !m.showNotes
+			m.resize()
+			return m, m.fetchNotes()
+
+		case key.Matches(msg, ui.Key("confirm")):
+			if len(m.selectedDeps()) == 0 {
+				m.confirmed = true
+				return m, tea.Quit
 			}
+			m.confirming = true
 			return m, nil
+		}
 
-		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
-			m.confirmed = true
-			return m, tea.Quit
+	case tea.MouseMsg:
+		if m.confirming || m.showHelp {
+			return m, nil
+		}
+		if ui.ListMouse(&m.list, msg, headerHeight, 1) {
+			m.toggleCurrent()
 		}
+		return m, m.fetchNotes()
+
+	case notesMsg:
+		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.list.SetWidth(msg.Width)
-		m.list.SetHeight(msg.Height - 4)
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
 		return m, nil
 	}
 
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, m.fetchNotes())
+}
+
+// resize fits the list to the window, leaving the lower half to the details
+// pane when it's shown
+func (m *model) resize() {
+	height := m.height - headerHeight
+	if m.showNotes {
+		height /= 2
+	}
+	m.list.SetHeight(height)
 }
 
 func (m model) View() string {
 	if m.quitting {
 		return ""
 	}
+	if m.showHelp {
+		return m.helpView()
+	}
+	if m.confirming {
+		return m.confirmView()
+	}
 
 	titleText := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("12")).
 		Render("📦 Select packages to update")
 
-	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("Space to toggle • Enter to confirm • a select all • n select none • i invert • q quit")
+	helpText := ui.ShortKeyHelp(ui.Key("toggle"), ui.Key("confirm"), ui.Key("select-all"), ui.Key("select-none"), ui.Key("invert"), ui.Key("quit")) + "\n" +
+		ui.ShortKeyHelp(ui.Key("filter"), ui.Key("details"), ui.Key("selected-only"), ui.Key("next-page"), ui.Key("prev-page"), ui.Key("help"))
+
+	selected, outdated, total := m.counts()
+	counts := fmt.Sprintf("%s selected / %s outdated / %s total",
+		ui.FormatCount(selected), ui.FormatCount(outdated), ui.FormatCount(total))
+	if pages := m.list.Paginator.TotalPages; pages > 1 {
+		counts += fmt.Sprintf(" • page %d/%d", m.list.Paginator.Page+1, pages)
+	}
+	if m.selectedOnly {
+		counts += " • showing selected only"
+	}
+	if filter := m.list.FilterValue(); filter != "" {
+		counts += fmt.Sprintf(" • %s matching %q", ui.FormatCount(len(m.list.VisibleItems())), filter)
+	}
 
 	legend := fmt.Sprintf("  %s direct  %s indirect",
 		directStyle.Render("●"),
@@ -189,15 +381,62 @@ func (m model) View() string {
 		titleText,
 		helpText,
 		"",
+		"  "+headerStyle.Render(counts),
 		legend,
 		"",
 		columnHeader,
 	)
 
-	return header + "\n" + m.list.View()
+	if m.selectedOnly && len(m.list.Items()) == 0 {
+		return header + "\n\n" + dimmedStyle.Render("      Nothing selected yet • s to show all packages") + "\n"
+	}
+
+	view := header + "\n" + m.list.View()
+	if m.showNotes {
+		view += "\n" + m.notesView(m.width, m.height-headerHeight-m.list.Height())
+	}
+	return view
 }
 
-func RunInteractive(deps []*Dependency) ([]*Dependency, error) {
+// helpView is the ? overlay, listing the keys of the current screen
+func (m model) helpView() string {
+	if m.confirming {
+		return ui.KeyHelp("⌨️  Review keys",
+			[]key.Binding{ui.Key("confirm"), ui.Key("yes")},
+			[]key.Binding{ui.Key("back"), ui.Key("no"), ui.Key("quit")},
+		)
+	}
+	return ui.KeyHelp("⌨️  Selection keys",
+		[]key.Binding{ui.Key("up"), ui.Key("down"), ui.Key("next-page"), ui.Key("prev-page"), ui.Key("first"), ui.Key("last")},
+		[]key.Binding{ui.Key("toggle"), ui.Key("select-all"), ui.Key("select-none"), ui.Key("invert"), ui.Key("selected-only")},
+		[]key.Binding{ui.Key("filter"), ui.Key("details"), ui.Key("confirm"), ui.Key("quit")},
+	)
+}
+
+// RunInteractive lets the user pick the packages to update and confirm the
+// choice. goVersion is the module's go directive, to predict toolchain bumps,
+// and notes fetches the release notes the details pane shows.
+func RunInteractive(deps []*Dependency, goVersion string, notes *notesSource) ([]*Dependency, error) {
+	if ui.Accessible() {
+		return choosePlain(deps, goVersion)
+	}
+
+	p := tea.NewProgram(newPicker(deps, goVersion, notes), ui.InteractiveOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running interactive UI: %w", err)
+	}
+
+	result := finalModel.(model)
+	if result.quitting && !result.confirmed {
+		return nil, nil
+	}
+
+	return result.selectedDeps(), nil
+}
+
+// newPicker creates the selection list, direct dependencies first
+func newPicker(deps []*Dependency, goVersion string, notes *notesSource) model {
 	var directDeps, indirectDeps []*Dependency
 	for _, dep := range deps {
 		if dep.Direct {
@@ -220,32 +459,16 @@ func RunInteractive(deps []*Dependency) ([]*Dependency, error) {
 	l := list.New(items, itemDelegate{}, defaultWidth, defaultHeight)
 	l.Title = ""
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
+	l.KeyMap = ui.FilterListKeys()
 	l.Styles.Title = titleStyle
 
-	m := model{
+	return model{
 		list:         l,
 		dependencies: deps,
+		notes:        notes,
+		goVersion:    goVersion,
+		width:        defaultWidth,
+		height:       defaultHeight,
 	}
-
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	finalModel, err := p.Run()
-	if err != nil {
-		return nil, fmt.Errorf("running interactive UI: %w", err)
-	}
-
-	result := finalModel.(model)
-	if result.quitting && !result.confirmed {
-		return nil, nil
-	}
-
-	var selected []*Dependency
-	for _, listItem := range result.list.Items() {
-		if i, ok := listItem.(item); ok && i.selected {
-			selected = append(selected, i.dep)
-		}
-	}
-
-	return selected, nil
 }
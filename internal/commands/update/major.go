@@ -0,0 +1,141 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// maxImportersListed is how many files still importing a moved module are
+// listed by name
+const maxImportersListed = 10
+
+// targetPath returns the module path the dependency is updated to
+func (d *Dependency) targetPath() string {
+	if d.NewPath != "" {
+		return d.NewPath
+	}
+	return d.Name
+}
+
+// label names the dependency for the update lists, with the path it moves
+// to for a new major version
+func (d *Dependency) label() string {
+	if d.NewPath != "" {
+		return d.Name + " → " + d.NewPath
+	}
+	return d.Name
+}
+
+// applyMajorsWithSpinner probes the proxy for higher major versions of the
+// dependencies and retargets those that have one
+func applyMajorsWithSpinner(ctx context.Context, s *session, client *proxy.Client, deps []*Dependency, pre bool, skip func(*Dependency) bool) {
+	var probe []*Dependency
+	for _, dep := range deps {
+		if !skip(dep) {
+			probe = append(probe, dep)
+		}
+	}
+	if len(probe) == 0 {
+		return
+	}
+
+	if s != nil {
+		runInSession(s, "Checking for major versions...", 0, func(chan<- updateProgress) (struct{}, error) {
+			applyMajors(ctx, client, probe, pre)
+			return struct{}{}, nil
+		})
+		return
+	}
+	ui.RunSimpleSpinner("Checking for major versions...", func() (struct{}, error) {
+		applyMajors(ctx, client, probe, pre)
+		return struct{}{}, nil
+	})
+}
+
+// applyMajors moves each dependency with a higher major version to the latest
+// version of the highest one. Modules the proxy can't answer for keep their
+// target.
+func applyMajors(ctx context.Context, client *proxy.Client, deps []*Dependency, pre bool) {
+	var wg sync.WaitGroup
+	for _, dep := range deps {
+		wg.Add(1)
+		go func(dep *Dependency) {
+			defer wg.Done()
+
+			path, info, err := client.LatestMajor(ctx, dep.Name, pre)
+			if err != nil {
+				ui.Debug("probing major versions of %s: %v", dep.Name, err)
+				return
+			}
+			if info == nil {
+				return
+			}
+			dep.NewPath = path
+			dep.Target, dep.TargetRaw = strings.TrimPrefix(info.Version, "v"), info.Version
+			dep.Latest, dep.LatestRaw = dep.Target, dep.TargetRaw
+			dep.UpToDate = false
+			dep.GoVersion = goDirective(ctx, client, path, info.Version)
+		}(dep)
+	}
+	wg.Wait()
+}
+
+// majorMoves returns the module path changes among the updates
+func majorMoves(toUpdate []*Dependency) []importpath.Move {
+	var moves []importpath.Move
+	for _, dep := range toUpdate {
+		if dep.NewPath != "" {
+			moves = append(moves, importpath.Move{Old: dep.Name, New: dep.NewPath})
+		}
+	}
+	return moves
+}
+
+// rewriteImports points the imports of the module's source files at the new
+// major versions, returning how many files changed
+func rewriteImports(dir string, moves []importpath.Move) (int, error) {
+	changed, err := importpath.Rewrite(dir, moves)
+	if err != nil {
+		return len(changed), fmt.Errorf("rewriting imports: %w", err)
+	}
+	if len(changed) > 0 {
+		fmt.Printf("✓ Rewrote imports in %s file(s)\n", ui.FormatCount(len(changed)))
+	}
+	return len(changed), nil
+}
+
+// warnImporters lists the files that still import the old major versions and
+// reports whether there are any, in which case go mod tidy would put the old
+// requirements back
+func warnImporters(dir string, moves []importpath.Move) bool {
+	var old []string
+	for _, m := range moves {
+		old = append(old, m.Old)
+	}
+	files, err := importpath.Importers(dir, old)
+	if err != nil {
+		ui.Error("⚠️  Warning: could not check imports: %v\n", err)
+		return false
+	}
+	if len(files) == 0 {
+		return false
+	}
+
+	fmt.Printf("\n⚠️  %s file(s) still import the old major version(s):\n", ui.FormatCount(len(files)))
+	for i, f := range files {
+		if i == maxImportersListed {
+			fmt.Printf("  … and %s more\n", ui.FormatCount(len(files)-maxImportersListed))
+			break
+		}
+		fmt.Printf("  %s\n", filepath.ToSlash(f))
+	}
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Point them at the new paths (--rewrite-imports does this), then run 'go mod tidy'"))
+	return true
+}
@@ -0,0 +1,188 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// maxNoteCommits is how many commit subjects the notes list when a module
+// has no releases in the range
+const maxNoteCommits = 50
+
+var notesTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+
+// notesSource fetches what changed in each update for the selection list's
+// details pane, once per update and only when it's shown
+type notesSource struct {
+	ctx    context.Context
+	client *github.Client
+
+	mu      sync.Mutex
+	fetched map[string]string // notes by notesKey
+	loading map[string]bool
+}
+
+func newNotesSource(ctx context.Context, client *github.Client) *notesSource {
+	return &notesSource{
+		ctx:     ctx,
+		client:  client,
+		fetched: make(map[string]string),
+		loading: make(map[string]bool),
+	}
+}
+
+// notesMsg reports that the notes of an update were fetched
+type notesMsg struct {
+	key string
+}
+
+// notesKey identifies the notes of an update
+func notesKey(dep *Dependency) string {
+	return dep.targetPath() + "@v" + dep.Current + ".." + dep.TargetRaw
+}
+
+// get returns the notes of dep and whether they were fetched yet
+func (n *notesSource) get(dep *Dependency) (string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	notes, ok := n.fetched[notesKey(dep)]
+	return notes, ok
+}
+
+// fetch returns a command fetching the notes of dep, nil when they're
+// fetched or on their way
+func (n *notesSource) fetch(dep *Dependency) tea.Cmd {
+	key := notesKey(dep)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.fetched[key]; ok || n.loading[key] {
+		return nil
+	}
+	n.loading[key] = true
+
+	return func() tea.Msg {
+		notes := n.describe(dep)
+		n.mu.Lock()
+		n.fetched[key] = notes
+		delete(n.loading, key)
+		n.mu.Unlock()
+		return notesMsg{key: key}
+	}
+}
+
+// describe tells what changed between the current and target version of
+// dep: the GitHub release notes in between, or else the commits
+func (n *notesSource) describe(dep *Dependency) string {
+	repo, ok := github.ParseModulePath(dep.targetPath())
+	if !ok {
+		return fmt.Sprintf("%s isn't hosted on GitHub, so there are no release notes to show.", dep.targetPath())
+	}
+	from := "v" + dep.Current
+
+	releases, err := n.client.ListReleases(n.ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return fmt.Sprintf("Couldn't fetch the releases of %s/%s: %v", repo.Owner, repo.Name, err)
+	}
+	if selected := repo.ReleasesBetween(releases, from, dep.TargetRaw); len(selected) > 0 {
+		var b strings.Builder
+		for _, r := range selected {
+			title := r.TagName
+			if r.Name != "" && r.Name != r.TagName {
+				title += " — " + r.Name
+			}
+			if !r.PublishedAt.IsZero() {
+				title += "  " + ui.UpToDateStyle.Render(ui.FormatReleaseTime(r.PublishedAt))
+			}
+			body := strings.TrimSpace(strings.ReplaceAll(r.Body, "\r\n", "\n"))
+			if body == "" {
+				body = "(no release notes)"
+			}
+			fmt.Fprintf(&b, "%s\n%s\n\n", ui.SummaryStyle.Render(title), body)
+		}
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
+	// A new major version has its tags under another path
+	if dep.NewPath != "" {
+		return fmt.Sprintf("No GitHub releases between %s and %s.", from, dep.TargetRaw)
+	}
+	commits, total, err := n.client.CompareCommits(n.ctx, repo.Owner, repo.Name, repo.Tag(from), repo.Tag(dep.TargetRaw))
+	if err != nil {
+		return fmt.Sprintf("No GitHub releases between %s and %s, and comparing the tags failed: %v", from, dep.TargetRaw, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "No GitHub releases between %s and %s; %s commit(s):\n\n", from, dep.TargetRaw, ui.FormatCount(total))
+	// The newest commits say the most about the version updated to
+	if len(commits) > maxNoteCommits {
+		commits = commits[len(commits)-maxNoteCommits:]
+	}
+	for i := len(commits) - 1; i >= 0; i-- {
+		subject, _, _ := strings.Cut(commits[i].Commit.Message, "\n")
+		fmt.Fprintf(&b, "%s %s\n", ui.UpToDateStyle.Render(shortSHA(commits[i].SHA)), subject)
+	}
+	if total > len(commits) {
+		fmt.Fprintf(&b, "… and %s older\n", ui.FormatCount(total-len(commits)))
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// fetchNotes fetches the notes of the update under the cursor when the
+// details pane is shown
+func (m model) fetchNotes() tea.Cmd {
+	if !m.showNotes || m.notes == nil {
+		return nil
+	}
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || i.dep.UpToDate {
+		return nil
+	}
+	return m.notes.fetch(i.dep)
+}
+
+// notesView is the details pane: what changed in the update under the
+// cursor, cut to height lines
+func (m model) notesView(width, height int) string {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return ""
+	}
+	dep := i.dep
+
+	title := "📜 " + dep.label()
+	var body string
+	switch {
+	case dep.UpToDate:
+		title += " " + dep.Current
+		body = "Up to date."
+	default:
+		title += " " + dep.Current + " → " + dep.Target
+		if notes, ok := m.notes.get(dep); ok {
+			body = notes
+		} else {
+			body = dimmedStyle.Render("Fetching release notes…")
+		}
+	}
+
+	lines := strings.Split(lipgloss.NewStyle().Width(max(width-4, 20)).Render(strings.TrimRight(body, "\n")), "\n")
+	if room := max(height-3, 1); len(lines) > room {
+		lines = append(lines[:room-1], dimmedStyle.Render("… gx changelog "+dep.targetPath()+" shows the rest"))
+	}
+
+	header := notesTitleStyle.Render(title) + "  " + dimmedStyle.Render(ui.Key("details").Help().Key+" to close")
+	return "  " + ui.BorderStyle.Render(ui.Rule(max(width-4, 20))) + "\n  " + header + "\n  " + strings.Join(lines, "\n  ")
+}
@@ -0,0 +1,226 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// planVersion is the format version written to plan files
+const planVersion = 1
+
+// Plan is the file written by --plan-out and applied by --apply. It records
+// the updates chosen and checksums of go.mod and go.sum, so applying fails
+// when the module changed after planning.
+type Plan struct {
+	Version int          `json:"version"`
+	Created time.Time    `json:"created"`
+	Module  string       `json:"module"`
+	GoMod   string       `json:"go_mod_sha256"`
+	GoSum   string       `json:"go_sum_sha256,omitempty"` // empty when there was no go.sum
+	Updates []PlanUpdate `json:"updates"`
+}
+
+// PlanUpdate is one planned requirement change
+type PlanUpdate struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Type   string `json:"type"`           // patch, minor or major, for reviewers
+	Path   string `json:"path,omitempty"` // module path of a new major version to move to
+}
+
+// writePlan records the selected updates in a plan file instead of applying
+// them. With none selected the plan is empty, and applying it does nothing.
+func writePlan(path, modPath string, parser *modfile.Parser, toUpdate []*Dependency) error {
+	sum, err := sumChecksum(modPath)
+	if err != nil {
+		return err
+	}
+
+	plan := Plan{
+		Version: planVersion,
+		Created: time.Now().UTC(),
+		Module:  parser.ModulePath(),
+		GoMod:   checksum(parser.Data()),
+		GoSum:   sum,
+		Updates: []PlanUpdate{},
+	}
+	for _, dep := range toUpdate {
+		plan.Updates = append(plan.Updates, PlanUpdate{
+			Module: dep.Name,
+			From:   "v" + dep.Current,
+			To:     dep.TargetRaw,
+			Type:   versions.Classify("v"+dep.Current, dep.TargetRaw),
+			Path:   dep.NewPath,
+		})
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding plan: %w", err)
+	}
+	if err := fsutil.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+
+	if len(plan.Updates) == 0 {
+		fmt.Printf("\n📋 Wrote an empty plan to %s\n", path)
+		return nil
+	}
+	fmt.Printf("\n📋 Planned %s update(s) in %s:\n", ui.FormatCount(len(plan.Updates)), path)
+	for _, u := range plan.Updates {
+		fmt.Printf("  • %s: %s → %s\n", (&Dependency{Name: u.Module, NewPath: u.Path}).label(), u.From, u.To)
+	}
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Review it, then run 'gx update --apply %s'", path)))
+	return nil
+}
+
+// writeEmptyPlan writes a plan without updates when --plan-out asks for one,
+// so a pipeline applying it later finds the file
+func writeEmptyPlan(opts Options, parser *modfile.Parser) error {
+	if opts.PlanOut == "" {
+		return nil
+	}
+	return writePlan(opts.PlanOut, opts.ModPath, parser, nil)
+}
+
+// readPlan loads a plan file written by --plan-out
+func readPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("decoding plan %s: %w", path, err)
+	}
+	if plan.Version != planVersion {
+		return nil, fmt.Errorf("plan %s has format version %d, this gx reads version %d", path, plan.Version, planVersion)
+	}
+	return &plan, nil
+}
+
+// drift lists how the module differs from when the plan was made, nothing
+// when it can be applied as is
+func (p *Plan) drift(modPath string, parser *modfile.Parser) ([]string, error) {
+	if module := parser.ModulePath(); module != p.Module {
+		return []string{fmt.Sprintf("the plan is for %s, go.mod declares %s", p.Module, module)}, nil
+	}
+
+	var changes []string
+	for _, u := range p.Updates {
+		req := parser.FindRequire(u.Module)
+		switch {
+		case req == nil:
+			changes = append(changes, fmt.Sprintf("%s is no longer required", u.Module))
+		case req.Mod.Version != u.From:
+			changes = append(changes, fmt.Sprintf("%s is at %s, the plan expects %s", u.Module, req.Mod.Version, u.From))
+		}
+	}
+
+	if checksum(parser.Data()) != p.GoMod {
+		changes = append(changes, "go.mod changed")
+	}
+	sum, err := sumChecksum(modPath)
+	if err != nil {
+		return nil, err
+	}
+	if sum != p.GoSum {
+		changes = append(changes, "go.sum changed")
+	}
+	return changes, nil
+}
+
+// runPlan applies a plan file to a single module, refusing if the module
+// drifted since planning
+func runPlan(ctx context.Context, opts Options) error {
+	plan, err := readPlan(opts.Apply)
+	if err != nil {
+		return err
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Printf("✨ %s plans no updates, nothing to apply\n", opts.Apply)
+		return nil
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	changes, err := plan.drift(opts.ModPath, parser)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("the module changed since %s was planned:\n  • %s\nmake a new plan with 'gx update --plan-out'",
+			opts.Apply, strings.Join(changes, "\n  • "))
+	}
+
+	fmt.Printf("📋 Applying %s update(s) planned %s\n", ui.FormatCount(len(plan.Updates)), ui.RelativeTime(plan.Created))
+
+	var toUpdate []*Dependency
+	for _, u := range plan.Updates {
+		toUpdate = append(toUpdate, &Dependency{
+			Name:      u.Module,
+			Current:   strings.TrimPrefix(u.From, "v"),
+			Target:    strings.TrimPrefix(u.To, "v"),
+			TargetRaw: u.To,
+			Latest:    strings.TrimPrefix(u.To, "v"),
+			LatestRaw: u.To,
+			Direct:    !parser.FindRequire(u.Module).Indirect,
+			NewPath:   u.Path,
+		})
+	}
+
+	if opts.DryRun {
+		printWouldUpdate(toUpdate)
+		writeUpdateRecords(opts, parser, toUpdate, stateWouldUpdate)
+		if err := previewUpdates(opts, parser, toUpdate); err != nil {
+			return err
+		}
+		warnStaleCodegen(filepath.Dir(opts.ModPath), toUpdate)
+		return nil
+	}
+
+	if opts.pr != nil {
+		_, err = openPullRequest(ctx, opts, parser, toUpdate, nil)
+		return err
+	}
+	if opts.Commit {
+		_, err = commitUpdates(ctx, opts, parser, toUpdate, nil)
+		return err
+	}
+	_, err = applyUpdates(ctx, opts, parser, toUpdate)
+	return err
+}
+
+// sumChecksum returns the checksum of the module's go.sum, "" when it has none
+func sumChecksum(modPath string) (string, error) {
+	data, err := os.ReadFile(modfile.SumPath(modPath))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading go.sum: %w", err)
+	}
+	return checksum(data), nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,51 @@
+package update
+
+import (
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// Update states of porcelain update records
+const (
+	stateUpdated     = "updated"
+	stateWouldUpdate = "would-update"
+	statePlanned     = "planned"
+	stateRolledBack  = "rolled-back"
+)
+
+// writeUpdateRecords writes a porcelain record per update with --porcelain:
+//
+//	update <member> <module> <from> <to> <type> <updated|would-update|planned|rolled-back>
+//
+// member is the workspace module being updated, empty outside a workspace.
+func writeUpdateRecords(opts Options, parser *modfile.Parser, toUpdate []*Dependency, state string) {
+	if opts.porcelain == nil {
+		return
+	}
+	member := porcelainMember(opts, parser)
+	for _, dep := range toUpdate {
+		opts.porcelain.Record("update", member, dep.Name, "v"+dep.Current, dep.TargetRaw, versions.Classify("v"+dep.Current, dep.TargetRaw), state)
+	}
+}
+
+// writeFixedRecords writes a porcelain record per vulnerability the update
+// fixed with --audit:
+//
+//	fixed <member> <id> <module> <severity>
+func writeFixedRecords(opts Options, parser *modfile.Parser, fixed []*vulndb.Vulnerability) {
+	if opts.porcelain == nil {
+		return
+	}
+	member := porcelainMember(opts, parser)
+	for _, v := range fixed {
+		opts.porcelain.Record("fixed", member, v.ID, v.Package, v.Severity)
+	}
+}
+
+func porcelainMember(opts Options, parser *modfile.Parser) string {
+	if opts.Workspace == "" {
+		return ""
+	}
+	return parser.ModulePath()
+}
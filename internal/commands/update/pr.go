@@ -0,0 +1,288 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/gitlab"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// maxReleaseNotes caps the release notes quoted in a pull request body, well
+// under the 65536 characters GitHub accepts; past it only links are given
+const maxReleaseNotes = 40000
+
+// prTarget is where --pr opens the pull request, resolved before anything
+// changes
+type prTarget struct {
+	host  codeHost
+	base  string         // the branch checked out, which the pull request merges into
+	notes *github.Client // fetches the release notes of modules on github.com
+}
+
+// codeHost opens pull requests on the code host of the origin remote
+type codeHost interface {
+	// kind names what it opens, "pull request" or "merge request"
+	kind() string
+	// open opens one from head into base, returning how it's referred to,
+	// such as #12, and its URL
+	open(ctx context.Context, title, head, base, body string) (ref, url string, err error)
+}
+
+// githubHost opens GitHub pull requests
+type githubHost struct {
+	client *github.Client
+	repo   github.Repo
+}
+
+func (h githubHost) kind() string { return "pull request" }
+
+func (h githubHost) open(ctx context.Context, title, head, base, body string) (string, string, error) {
+	pr, err := h.client.CreatePullRequest(ctx, h.repo.Owner, h.repo.Name, github.NewPullRequest{
+		Title: title,
+		Head:  head,
+		Base:  base,
+		Body:  body,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("#%d", pr.Number), pr.HTMLURL, nil
+}
+
+// gitlabHost opens GitLab merge requests, deleting the branch once merged
+type gitlabHost struct {
+	client  *gitlab.Client
+	project string
+}
+
+func (h gitlabHost) kind() string { return "merge request" }
+
+func (h gitlabHost) open(ctx context.Context, title, head, base, body string) (string, string, error) {
+	mr, err := h.client.CreateMergeRequest(ctx, h.project, gitlab.NewMergeRequest{
+		Title:              title,
+		SourceBranch:       head,
+		TargetBranch:       base,
+		Description:        body,
+		RemoveSourceBranch: true,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("!%d", mr.IID), mr.WebURL, nil
+}
+
+// preparePullRequest checks that a pull request can be opened for the module
+// in dir: a clean work tree with a branch checked out, an origin remote on
+// the configured GitHub or GitLab instance, and an API token for it
+func preparePullRequest(ctx context.Context, dir string) (*prTarget, error) {
+	if err := checkCleanTree(ctx, dir, "--pr"); err != nil {
+		return nil, err
+	}
+	base, err := gitcmd.Output(ctx, dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("--pr needs a branch checked out to open the pull request against: %w", err)
+	}
+
+	remote, err := gitcmd.Output(ctx, dir, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("--pr pushes to the origin remote: %w", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	target := &prTarget{base: base, notes: cfg.NewGitHubModulesClient()}
+
+	hub := cfg.NewGitHubClient()
+	if repo, ok := github.ParseRemoteURL(remote, hub.Host()); ok {
+		if !hub.Authenticated() {
+			return nil, fmt.Errorf("--pr needs a GitHub token in GITHUB_TOKEN, GH_TOKEN or github.token in the config")
+		}
+		target.host = githubHost{client: hub, repo: repo}
+		return target, nil
+	}
+
+	client := cfg.NewGitLabClient()
+	project, ok := gitlab.ParseRemoteURL(remote, client.Host())
+	if !ok {
+		return nil, fmt.Errorf("origin remote %s is neither on GitHub at %s nor on GitLab at %s (set github.api_url or gitlab.url in the config for a self-hosted instance)", remote, hub.Host(), client.Host())
+	}
+	if !client.Authenticated() {
+		return nil, fmt.Errorf("--pr needs a GitLab token in GITLAB_TOKEN or gitlab.token in the config")
+	}
+	target.host = gitlabHost{client: client, project: project}
+	return target, nil
+}
+
+// openPullRequest applies the updates on a new branch, commits them, pushes
+// the branch to origin and opens a pull request for it, or a merge request on
+// GitLab, then checks out the base branch again. The updates go in one
+// commit, or one each with --commit.
+func openPullRequest(ctx context.Context, opts Options, parser *modfile.Parser, toUpdate []*Dependency, families []family) (int, error) {
+	target := opts.pr
+	workDir := filepath.Dir(opts.ModPath)
+	branch := "gx/update-" + time.Now().Format("20060102-150405")
+
+	if err := gitcmd.Run(ctx, workDir, "switch", "-q", "-c", branch); err != nil {
+		return 0, err
+	}
+	fmt.Printf("\n🌿 Switched to a new branch %s\n", branch)
+
+	commits, err := commitOnBranch(ctx, opts, parser, toUpdate, families)
+	if len(commits) == 0 {
+		// Any changes left come along to the base branch, which the new
+		// one hasn't moved from
+		if err := gitcmd.Run(ctx, workDir, "switch", "-q", target.base); err == nil {
+			gitcmd.Run(ctx, workDir, "branch", "-q", "-D", branch)
+		}
+		return 0, err
+	}
+	if err != nil {
+		fmt.Printf("\n⚠️  The commits so far are on %s; no %s was opened\n", branch, target.host.kind())
+		return updatedIn(commits), err
+	}
+
+	fmt.Printf("\n⬆️  Pushing %s to origin...\n", branch)
+	if err := gitcmd.Run(ctx, workDir, "push", "-q", "-u", "origin", branch); err != nil {
+		return updatedIn(commits), err
+	}
+
+	fmt.Printf("📝 Opening %s...\n", target.host.kind())
+	body := pullRequestBody(ctx, target.notes, commits)
+	ref, url, err := target.host.open(ctx, pullRequestTitle(commits), branch, target.base, body)
+	if err != nil {
+		return updatedIn(commits), err
+	}
+	fmt.Printf("✓ Opened %s %s: %s\n", target.host.kind(), ref, url)
+
+	if err := gitcmd.Run(ctx, workDir, "switch", "-q", target.base); err != nil {
+		ui.Error("⚠️  Warning: could not switch back to %s: %v\n", target.base, err)
+	}
+	return updatedIn(commits), nil
+}
+
+// commitOnBranch applies and commits the updates, returning the digest of
+// each commit
+func commitOnBranch(ctx context.Context, opts Options, parser *modfile.Parser, toUpdate []*Dependency, families []family) ([]*digest, error) {
+	if opts.Commit {
+		return commitUpdates(ctx, opts, parser, toUpdate, families)
+	}
+
+	summary, err := applyBatch(ctx, opts, parser, toUpdate)
+	if err != nil {
+		return nil, err
+	}
+	commits := []*digest{summary}
+	if err := commitAll(ctx, filepath.Dir(opts.ModPath), pullRequestTitle(commits), summary.String()); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// pullRequestTitle names the single update, or counts them
+func pullRequestTitle(commits []*digest) string {
+	var updated []*Dependency
+	for _, d := range commits {
+		updated = append(updated, d.Updated...)
+	}
+	if len(updated) == 1 {
+		return commitSubject("", updated)
+	}
+	return fmt.Sprintf("deps: update %d Go modules", len(updated))
+}
+
+// pullRequestBody describes the updates in Markdown: a table of them, the
+// vulnerabilities they fixed when --audit scanned for them, the release notes
+// of modules on GitHub and the digest of each commit
+func pullRequestBody(ctx context.Context, client *github.Client, commits []*digest) string {
+	var b strings.Builder
+	b.WriteString("Updates Go module dependencies with `gx update`.\n\n")
+
+	b.WriteString("| Module | From | To | Type |\n|---|---|---|---|\n")
+	for _, d := range commits {
+		for _, dep := range d.Updated {
+			fmt.Fprintf(&b, "| `%s` | v%s | %s | %s |\n", dep.label(), dep.Current, dep.TargetRaw, versions.Classify("v"+dep.Current, dep.TargetRaw))
+		}
+	}
+
+	scanned, fixed := false, 0
+	for _, d := range commits {
+		scanned = scanned || d.Scanned
+		fixed += len(d.Fixed)
+	}
+	if scanned {
+		b.WriteString("\n### Vulnerabilities fixed\n\n")
+		if fixed == 0 {
+			b.WriteString("None.\n")
+		}
+		for _, d := range commits {
+			for _, v := range d.Fixed {
+				fmt.Fprintf(&b, "- [%s](%s) in `%s` (%s)\n", v.ID, v.URL, v.Package, strings.ToLower(v.Severity))
+			}
+		}
+	}
+
+	b.WriteString(releaseNotes(ctx, client, commits))
+
+	b.WriteString("\n<details>\n<summary>Summary</summary>\n\n```\n")
+	for i, d := range commits {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(d.String())
+	}
+	b.WriteString("```\n</details>\n")
+	return b.String()
+}
+
+// releaseNotes quotes the GitHub releases between the old and new version of
+// each updated module hosted there, in collapsed sections
+func releaseNotes(ctx context.Context, client *github.Client, commits []*digest) string {
+	var b strings.Builder
+	quoted := 0
+	for _, d := range commits {
+		for _, dep := range d.Updated {
+			repo, ok := github.ParseModulePath(dep.targetPath())
+			if !ok {
+				continue
+			}
+			from := "v" + dep.Current
+			if b.Len() == 0 {
+				b.WriteString("\n### Release notes\n")
+			}
+			fmt.Fprintf(&b, "\n<details>\n<summary><code>%s</code> %s → %s</summary>\n\n", dep.label(), from, dep.TargetRaw)
+
+			var releases []github.Release
+			if quoted < maxReleaseNotes {
+				all, err := client.ListReleases(ctx, repo.Owner, repo.Name)
+				if err != nil {
+					ui.Debug("fetching releases of %s/%s: %v", repo.Owner, repo.Name, err)
+				}
+				releases = repo.ReleasesBetween(all, from, dep.TargetRaw)
+			}
+			for _, r := range releases {
+				notes := strings.TrimSpace(strings.ReplaceAll(r.Body, "\r\n", "\n"))
+				if quoted+len(notes) > maxReleaseNotes {
+					fmt.Fprintf(&b, "#### [%s](%s)\n\n", r.TagName, r.HTMLURL)
+					continue
+				}
+				quoted += len(notes)
+				fmt.Fprintf(&b, "#### [%s](%s)\n\n%s\n\n", r.TagName, r.HTMLURL, notes)
+			}
+			if dep.NewPath == "" {
+				fmt.Fprintf(&b, "[Compare %s...%s](%s)\n", from, dep.TargetRaw, repo.CompareURL(from, dep.TargetRaw))
+			}
+			b.WriteString("</details>\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,179 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/workspace"
+	"golang.org/x/mod/semver"
+)
+
+// request is a module named on the command line, with the version asked for
+type request struct {
+	module  string
+	version string // empty for the usual update target
+}
+
+// parseRequests parses <module>[@<version>] arguments
+func parseRequests(args []string) ([]request, error) {
+	requests := make([]request, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		module, version, hasVersion := strings.Cut(arg, "@")
+		if module == "" || (hasVersion && version == "") {
+			return nil, fmt.Errorf("expected <module> or <module>@<version>, got %q", arg)
+		}
+		if seen[module] {
+			return nil, fmt.Errorf("%s is given twice", module)
+		}
+		seen[module] = true
+		requests = append(requests, request{module: module, version: version})
+	}
+	return requests, nil
+}
+
+// checkRequested fails for a requested module that no go.mod of the update
+// requires, before anything is looked up
+func checkRequested(opts Options) error {
+	modPaths := []string{opts.ModPath}
+	where := "go.mod"
+	if opts.Workspace != "" {
+		ws, err := workspace.Load(opts.Workspace)
+		if err != nil {
+			return fmt.Errorf("loading workspace: %w", err)
+		}
+		modPaths = modPaths[:0]
+		for _, member := range ws.Members {
+			modPaths = append(modPaths, member.ModPath)
+		}
+		where = "any module of the workspace"
+	}
+
+	required := make(map[string]bool)
+	for _, modPath := range modPaths {
+		parser, err := modfile.NewParser(modPath)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", modPath, err)
+		}
+		for _, req := range parser.AllRequires() {
+			required[req.Mod.Path] = true
+		}
+	}
+
+	for _, r := range opts.requests {
+		if !required[r.module] {
+			return fmt.Errorf("%s is not required in %s", r.module, where)
+		}
+	}
+	return nil
+}
+
+// requestFor returns the request naming a module
+func requestFor(requests []request, modulePath string) (request, bool) {
+	for _, r := range requests {
+		if r.module == modulePath {
+			return r, true
+		}
+	}
+	return request{}, false
+}
+
+// isRequested reports whether a module is looked up for the requests: it is
+// named, or shares a family with a module named without a version
+func isRequested(requests []request, familyFor func(string) string, modulePath string) bool {
+	if _, ok := requestFor(requests, modulePath); ok {
+		return true
+	}
+	name := familyFor(modulePath)
+	if name == "" {
+		return false
+	}
+	for _, r := range requests {
+		if r.version == "" && familyFor(r.module) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRequestedVersions sets the target of each module requested with a
+// version to that version, as the proxy resolves it. Queries such as a
+// branch name resolve to a pseudo-version; older versions are refused, as
+// moving back is gx downgrade's job.
+func applyRequestedVersions(ctx context.Context, client *proxy.Client, deps []*Dependency, requests []request) error {
+	for _, dep := range deps {
+		r, ok := requestFor(requests, dep.Name)
+		if !ok || r.version == "" {
+			continue
+		}
+
+		info, err := resolveVersion(ctx, client, r)
+		if err != nil {
+			return err
+		}
+		current := "v" + dep.Current
+		if semver.Compare(info.Version, current) < 0 {
+			return fmt.Errorf("%s@%s is older than the current version %s (use gx downgrade instead)", r.module, info.Version, current)
+		}
+
+		dep.Target, dep.TargetRaw = strings.TrimPrefix(info.Version, "v"), info.Version
+		dep.UpToDate = info.Version == current
+		dep.GoVersion = ""
+		if !dep.UpToDate {
+			dep.GoVersion = goDirective(ctx, client, dep.Name, info.Version)
+		}
+	}
+	return nil
+}
+
+// resolveVersion looks up the canonical version of a request on the proxy
+func resolveVersion(ctx context.Context, client *proxy.Client, r request) (*proxy.VersionInfo, error) {
+	var info *proxy.VersionInfo
+	var err error
+	if r.version == "latest" {
+		info, err = client.Latest(ctx, r.module)
+	} else {
+		info, err = client.Info(ctx, r.module, r.version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s not found on proxy: %w", r.module, r.version, err)
+	}
+	if !semver.IsValid(info.Version) {
+		return nil, fmt.Errorf("%s@%s resolved to %q, which is not a version", r.module, r.version, info.Version)
+	}
+	return info, nil
+}
+
+// requestedUpdates returns the outdated requested modules, with the rest of
+// the family of those requested without a version. Modules requested with a
+// version are in no family by then, see withoutVersioned.
+func requestedUpdates(deps []*Dependency, requests []request, families []family) []*Dependency {
+	var selected []*Dependency
+	for _, dep := range deps {
+		if _, ok := requestFor(requests, dep.Name); ok && !dep.UpToDate {
+			selected = append(selected, dep)
+		}
+	}
+	return withFamilies(selected, families)
+}
+
+// withoutVersioned leaves the modules requested with a version out of their
+// families, so alignment doesn't move them off that version
+func withoutVersioned(families []family, requests []request) []family {
+	var kept []family
+	for _, f := range families {
+		var deps []*Dependency
+		for _, dep := range f.deps {
+			if r, ok := requestFor(requests, dep.Name); !ok || r.version == "" {
+				deps = append(deps, dep)
+			}
+		}
+		if len(deps) > 1 {
+			kept = append(kept, family{label: f.label, deps: deps})
+		}
+	}
+	return kept
+}
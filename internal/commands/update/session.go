@@ -0,0 +1,282 @@
+package update
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// errCancelled is returned by work cut short with ctrl+c
+var errCancelled = errors.New("cancelled")
+
+// session is the one bubbletea program of an interactive update. Loading,
+// selection, applying and the summary are phases of it instead of programs
+// of their own, so the terminal isn't handed from one program to the next
+// with a flicker in between. The update runs on the calling goroutine and
+// moves the session through the phases. Meanwhile stdout and stderr are
+// printed above the session's view, so messages don't tear it.
+type session struct {
+	program *tea.Program
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	exited  chan struct{} // closed when the program has stopped
+
+	stdout, stderr *os.File // restored by close
+	pipe           *os.File // replaces both while the session runs
+	copied         chan struct{}
+
+	chosen chan []*Dependency // the selection, nil when cancelled
+}
+
+// startSession starts the program, returning a context cancelled by ctrl+c
+func startSession(ctx context.Context) (*session, context.Context, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting interactive UI: %w", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	s := &session{
+		ctx:    ctx,
+		cancel: cancel,
+		exited: make(chan struct{}),
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		pipe:   w,
+		copied: make(chan struct{}),
+		chosen: make(chan []*Dependency),
+	}
+	s.program = tea.NewProgram(newSessionModel(s), tea.WithOutput(s.stdout))
+
+	go func() {
+		defer close(s.exited)
+		if _, err := s.program.Run(); err != nil {
+			ui.Debug("interactive UI: %v", err)
+			cancel(errCancelled)
+		}
+	}()
+	go s.copy(r)
+
+	os.Stdout, os.Stderr = w, w
+	return s, ctx, nil
+}
+
+// copy prints what the update writes above the session's view, line by line
+func (s *session) copy(r io.Reader) {
+	defer close(s.copied)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			s.program.Println(strings.TrimSuffix(line, "\n"))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// close prints the last messages and stops the program, leaving the
+// terminal as it was with everything printed above
+func (s *session) close() {
+	os.Stdout, os.Stderr = s.stdout, s.stderr
+	s.pipe.Close()
+	<-s.copied
+	s.program.Send(workMsg{})
+	s.program.Quit()
+	<-s.exited
+	s.cancel(nil)
+}
+
+// choose shows the selection list and returns the confirmed selection, nil
+// when the user cancelled
+func (s *session) choose(deps []*Dependency, goVersion string, notes *notesSource) []*Dependency {
+	s.program.Send(chooseMsg{picker: newPicker(deps, goVersion, notes)})
+	select {
+	case selected := <-s.chosen:
+		return selected
+	case <-s.exited:
+		return nil
+	}
+}
+
+// runInSession runs work under the session's spinner. work reports progress
+// on the channel, which it must not close.
+func runInSession[T any](s *session, message string, total int, work func(progress chan<- updateProgress) (T, error)) (T, error) {
+	s.program.Send(workMsg{message: message, total: total})
+
+	progress := make(chan updateProgress)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for p := range progress {
+			s.program.Send(updateProgressMsg(p))
+		}
+	}()
+
+	result, err := work(progress)
+	close(progress)
+	<-forwarded
+	s.program.Send(workMsg{})
+	return result, err
+}
+
+// cancelled returns errCancelled after ctrl+c, or the error that ended the
+// context the session was started with
+func (s *session) cancelled() error {
+	return context.Cause(s.ctx)
+}
+
+// countProgress adapts a channel of completed counts to session progress
+func countProgress(progress chan<- updateProgress, total int) (chan<- int, func()) {
+	counts := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := range counts {
+			progress <- updateProgress{current: n, total: total}
+		}
+	}()
+	return counts, func() {
+		close(counts)
+		<-done
+	}
+}
+
+// workMsg starts a phase of work shown with a spinner; an empty message
+// shows nothing until the next phase
+type workMsg struct {
+	message string
+	total   int
+}
+
+// chooseMsg starts the selection phase
+type chooseMsg struct {
+	picker model
+}
+
+type sessionModel struct {
+	s        *session
+	spinner  spinner.Model
+	work     workMsg
+	progress updateProgress
+
+	choosing bool
+	picker   model
+	size     tea.WindowSizeMsg // the last window size, for the picker
+
+	cancelled bool
+}
+
+func newSessionModel(s *session) sessionModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return sessionModel{s: s, spinner: sp}
+}
+
+func (m sessionModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m sessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		if m.choosing {
+			return m.updatePicker(msg)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.choosing {
+			return m.updatePicker(msg)
+		}
+		// Cancelling stops lookups and go commands; the update winds down
+		// and closes the session
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			m.s.cancel(errCancelled)
+		}
+		return m, nil
+
+	case workMsg:
+		m.work, m.progress = msg, updateProgress{total: msg.total}
+		return m, nil
+
+	case updateProgressMsg:
+		m.progress = updateProgress(msg)
+		return m, nil
+
+	case chooseMsg:
+		m.choosing, m.picker = true, msg.picker
+		if m.size.Width > 0 {
+			updated, _ := m.picker.Update(m.size)
+			m.picker = updated.(model)
+		}
+		if ui.MouseEnabled() {
+			return m, tea.Batch(tea.EnterAltScreen, tea.EnableMouseCellMotion)
+		}
+		return m, tea.EnterAltScreen
+	}
+
+	if m.choosing {
+		return m.updatePicker(msg)
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+// updatePicker passes a message to the selection list. When the user is done
+// the session leaves the alternate screen before handing over the
+// selection, so what the update prints next isn't lost.
+func (m sessionModel) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.picker.Update(msg)
+	m.picker = updated.(model)
+	if !m.picker.confirmed && !m.picker.quitting {
+		return m, cmd
+	}
+
+	var selected []*Dependency
+	if m.picker.confirmed {
+		selected = m.picker.selectedDeps()
+	}
+	m.choosing = false
+	chosen := m.s.chosen
+	return m, tea.Sequence(tea.ExitAltScreen, tea.DisableMouse, func() tea.Msg {
+		chosen <- selected
+		return nil
+	}, m.spinner.Tick)
+}
+
+func (m sessionModel) View() string {
+	switch {
+	case m.choosing:
+		return m.picker.View()
+	case m.work.message == "":
+		return ""
+	case m.cancelled:
+		return "\n Cancelling...\n"
+	}
+
+	view := fmt.Sprintf("\n %s %s", m.spinner.View(), m.work.message)
+	if m.work.total > 0 {
+		view += fmt.Sprintf(" (%s/%s)", ui.FormatCount(m.progress.current), ui.FormatCount(m.work.total))
+	}
+	view += "\n"
+	if m.progress.pkgName != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		view += fmt.Sprintf("   %s\n   %s\n", m.progress.pkgName, statusStyle.Render(m.progress.status))
+	}
+	return view
+}
@@ -12,27 +12,46 @@ import (
 	"github.com/omarshaarawi/gx/internal/modfile"
 	"github.com/omarshaarawi/gx/internal/proxy"
 	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
 	xmodfile "golang.org/x/mod/modfile"
 	"golang.org/x/mod/semver"
 )
 
-func loadDependenciesWithSpinner(ctx context.Context, parser *modfile.Parser, client *proxy.Client) ([]*Dependency, error) {
-	allReqs := parser.AllRequires()
+func loadDependenciesWithSpinner(ctx context.Context, s *session, allReqs []*xmodfile.Require, client *proxy.Client, pre bool, constraintFor func(string) (versions.Constraint, bool)) ([]*Dependency, error) {
 	if len(allReqs) == 0 {
 		return nil, nil
 	}
 
+	if s != nil {
+		deps, err := runInSession(s, "Checking for updates...", len(allReqs), func(progress chan<- updateProgress) ([]*Dependency, error) {
+			counts, stop := countProgress(progress, len(allReqs))
+			defer stop()
+			return fetchDependenciesParallel(ctx, allReqs, client, pre, constraintFor, counts)
+		})
+		// Lookups cut short report unknown versions rather than failing
+		if err := s.cancelled(); err != nil {
+			return nil, err
+		}
+		return deps, err
+	}
+
 	return ui.RunWithSpinner(ui.SpinnerTask[[]*Dependency]{
 		Message: "Checking for updates...",
+		Phase:   "check-updates",
 		Total:   len(allReqs),
 		Run: func(progress chan<- int) ([]*Dependency, error) {
-			return fetchDependenciesParallel(ctx, allReqs, client, progress)
+			return fetchDependenciesParallel(ctx, allReqs, client, pre, constraintFor, progress)
 		},
 	})
 }
 
-func fetchDependenciesParallel(ctx context.Context, allReqs []*xmodfile.Require, client *proxy.Client, progressCh chan<- int) ([]*Dependency, error) {
+func fetchDependenciesParallel(ctx context.Context, allReqs []*xmodfile.Require, client *proxy.Client, pre bool, constraintFor func(string) (versions.Constraint, bool), progressCh chan<- int) ([]*Dependency, error) {
 	deps := make([]*Dependency, len(allReqs))
+	latestOf := client.Latest
+	if pre {
+		latestOf = client.LatestPre
+	}
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	loaded := 0
@@ -42,27 +61,40 @@ func fetchDependenciesParallel(ctx context.Context, allReqs []*xmodfile.Require,
 		go func(idx int, r *xmodfile.Require) {
 			defer wg.Done()
 
-			latest, err := client.Latest(ctx, r.Mod.Path)
+			latest, err := latestOf(ctx, r.Mod.Path)
 			if err != nil {
 				latest = &proxy.VersionInfo{Version: "unknown"}
 			}
 
+			upToDate := semver.Compare(r.Mod.Version, latest.Version) >= 0
+			if !upToDate {
+				if v := skipRetracted(ctx, client, r.Mod.Path, r.Mod.Version, latest.Version); v != latest.Version {
+					latest = &proxy.VersionInfo{Version: v}
+					upToDate = v == r.Mod.Version
+				}
+			}
+
 			target := latest.Version
-			upToDate := false
-			if semver.Compare(r.Mod.Version, latest.Version) >= 0 {
+			if upToDate {
 				target = r.Mod.Version
-				upToDate = true
+			} else if c, ok := constraintFor(r.Mod.Path); ok {
+				target = constrainedTarget(ctx, client, r.Mod.Path, r.Mod.Version, latest.Version, pre, c)
+				upToDate = target == r.Mod.Version
 			}
 
 			dep := &Dependency{
 				Name:      r.Mod.Path,
 				Current:   strings.TrimPrefix(r.Mod.Version, "v"),
 				Target:    strings.TrimPrefix(target, "v"),
+				TargetRaw: target,
 				Latest:    strings.TrimPrefix(latest.Version, "v"),
 				LatestRaw: latest.Version,
 				Direct:    !r.Indirect,
 				UpToDate:  upToDate,
 			}
+			if !upToDate {
+				dep.GoVersion = goDirective(ctx, client, r.Mod.Path, target)
+			}
 
 			mu.Lock()
 			deps[idx] = dep
@@ -76,6 +108,98 @@ func fetchDependenciesParallel(ctx context.Context, allReqs []*xmodfile.Require,
 	return deps, nil
 }
 
+// skipRetracted returns the version to update to when latest is retracted in
+// its own go.mod: the highest release newer than current that no retraction
+// covers, else the highest such pre-release, else current. Latest is kept
+// when the go.mod can't be read.
+func skipRetracted(ctx context.Context, client *proxy.Client, modulePath, current, latest string) string {
+	data, err := client.GetModFile(ctx, modulePath, latest)
+	if err != nil {
+		ui.Debug("fetching go.mod for %s@%s: %v", modulePath, latest, err)
+		return latest
+	}
+	status, err := modfile.ParseModuleStatus(data)
+	if err != nil {
+		ui.Debug("parsing go.mod for %s@%s: %v", modulePath, latest, err)
+		return latest
+	}
+	if _, ok := status.Retracted(latest); !ok {
+		return latest
+	}
+
+	list, err := client.Versions(ctx, modulePath)
+	if err != nil {
+		ui.Debug("listing versions of %s: %v", modulePath, err)
+		return current
+	}
+
+	release, prerelease := current, current
+	for _, v := range status.Unretracted(list) {
+		if semver.Prerelease(v) == "" {
+			if semver.Compare(v, release) > 0 {
+				release = v
+			}
+		} else if semver.Compare(v, prerelease) > 0 {
+			prerelease = v
+		}
+	}
+	ui.Debug("%s@%s is retracted", modulePath, latest)
+	if release != current {
+		return release
+	}
+	return prerelease
+}
+
+// constrainedTarget returns the newest version a configured constraint allows
+// and latest's go.mod doesn't retract, or current when that isn't newer
+func constrainedTarget(ctx context.Context, client *proxy.Client, modulePath, current, latest string, pre bool, c versions.Constraint) string {
+	allow := c.Allows
+	if data, err := client.GetModFile(ctx, modulePath, latest); err == nil {
+		if status, err := modfile.ParseModuleStatus(data); err == nil {
+			allow = func(v string) bool {
+				_, retracted := status.Retracted(v)
+				return c.Allows(v) && !retracted
+			}
+		}
+	}
+
+	info, err := client.LatestAllowed(ctx, modulePath, pre, allow)
+	if err != nil {
+		ui.Debug("resolving %s within %s: %v", modulePath, c, err)
+		return current
+	}
+	if info == nil || semver.Compare(info.Version, current) <= 0 {
+		return current
+	}
+	return info.Version
+}
+
+// goDirective returns the go directive in a module version's go.mod, or ""
+// when it can't be read
+func goDirective(ctx context.Context, client *proxy.Client, modulePath, version string) string {
+	data, err := client.GetModFile(ctx, modulePath, version)
+	if err != nil {
+		ui.Debug("fetching go.mod for %s@%s: %v", modulePath, version, err)
+		return ""
+	}
+	f, err := xmodfile.ParseLax("go.mod", data, nil)
+	if err != nil || f.Go == nil {
+		return ""
+	}
+	return f.Go.Version
+}
+
+func scanModuleWithSpinner(ctx context.Context, s *session, scanner *vulndb.Scanner, modPath string) (*vulndb.ScanResult, error) {
+	if s != nil {
+		return runInSession(s, "Scanning for vulnerabilities...", 0, func(chan<- updateProgress) (*vulndb.ScanResult, error) {
+			return scanner.ScanModule(ctx, modPath)
+		})
+	}
+	return ui.RunSimpleSpinner("Scanning for vulnerabilities...", func() (*vulndb.ScanResult, error) {
+		return scanner.ScanModule(ctx, modPath)
+	})
+}
+
 type updateProgress struct {
 	current int
 	total   int
@@ -146,12 +270,24 @@ func (m updateProgressModel) View() string {
 	)
 }
 
-func updateDependenciesWithProgress(parser *modfile.Parser, deps []*Dependency) error {
-	resultCh := make(chan error, 1)
+func updateDependenciesWithProgress(s *session, writer *modfile.Writer, deps []*Dependency) error {
+	if s != nil {
+		_, err := runInSession(s, "Updating go.mod...", len(deps), func(progress chan<- updateProgress) (struct{}, error) {
+			return struct{}{}, performUpdates(writer, deps, progress)
+		})
+		return err
+	}
+
 	progressCh := make(chan updateProgress, len(deps))
 
+	if ui.GetProgressMode() != ui.ProgressAuto {
+		return updateDependenciesWithEvents(writer, deps, progressCh)
+	}
+
+	resultCh := make(chan error, 1)
+
 	go func() {
-		err := performUpdates(parser, deps, progressCh)
+		err := performUpdates(writer, deps, progressCh)
 		resultCh <- err
 	}()
 
@@ -175,36 +311,55 @@ func updateDependenciesWithProgress(parser *modfile.Parser, deps []*Dependency)
 	return result
 }
 
-func performUpdates(parser *modfile.Parser, deps []*Dependency, progressCh chan<- updateProgress) error {
-	writer := modfile.NewWriter(parser)
-
-	if err := writer.Backup(); err != nil {
-		return fmt.Errorf("creating backup: %w", err)
-	}
+// updateDependenciesWithEvents applies updates reporting progress as events instead of a TUI
+func updateDependenciesWithEvents(writer *modfile.Writer, deps []*Dependency, progressCh chan updateProgress) error {
+	const phase, message = "apply-updates", "Updating go.mod..."
+	ui.EmitProgress(ui.ProgressEvent{Phase: phase, Status: "start", Total: len(deps), Message: message})
 
-	for i, dep := range deps {
-		progressCh <- updateProgress{
-			current: i + 1,
-			total:   len(deps),
-			pkgName: dep.Name,
-			status:  fmt.Sprintf("%s → %s", dep.Current, dep.Latest),
+	done := make(chan struct{})
+	go func() {
+		for p := range progressCh {
+			ui.EmitProgress(ui.ProgressEvent{Phase: phase, Status: "progress", Completed: p.current, Total: p.total, Item: p.pkgName, Message: message})
 		}
+		close(done)
+	}()
 
-		if err := writer.UpdateRequire(dep.Name, dep.LatestRaw); err != nil {
-			writer.RestoreBackup()
-			return fmt.Errorf("updating %s: %w", dep.Name, err)
-		}
+	err := performUpdates(writer, deps, progressCh)
+	close(progressCh)
+	<-done
+
+	status := "done"
+	if err != nil {
+		status = "error"
 	}
+	ui.EmitProgress(ui.ProgressEvent{Phase: phase, Status: status, Completed: len(deps), Total: len(deps), Message: message})
 
-	writer.Cleanup()
+	return err
+}
 
-	if err := writer.SafeWrite(); err != nil {
-		return fmt.Errorf("writing go.mod: %w", err)
+// updateRequire raises the requirement on dep to its target, moving it to the
+// new module path of a major version update
+func updateRequire(writer *modfile.Writer, dep *Dependency) error {
+	if dep.NewPath != "" {
+		return writer.MoveRequire(dep.Name, dep.NewPath, dep.TargetRaw)
 	}
+	return writer.UpdateRequire(dep.Name, dep.TargetRaw)
+}
 
-	if err := writer.CleanupBackup(); err != nil {
-		return fmt.Errorf("cleanup backup: %w", err)
-	}
+func performUpdates(writer *modfile.Writer, deps []*Dependency, progressCh chan<- updateProgress) error {
+	return writer.Apply(func() error {
+		for i, dep := range deps {
+			progressCh <- updateProgress{
+				current: i + 1,
+				total:   len(deps),
+				pkgName: dep.Name,
+				status:  fmt.Sprintf("%s → %s", dep.Current, dep.Target),
+			}
 
-	return nil
+			if err := updateRequire(writer, dep); err != nil {
+				return fmt.Errorf("updating %s: %w", dep.Name, err)
+			}
+		}
+		return nil
+	})
 }
@@ -2,12 +2,22 @@ package update
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
 	"path/filepath"
+	"strings"
 
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/importpath"
 	"github.com/omarshaarawi/gx/internal/modfile"
-	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	"github.com/omarshaarawi/gx/internal/workspace"
+	xmodfile "golang.org/x/mod/modfile"
 )
 
 // Dependency represents a Go module dependency with version information
@@ -15,108 +25,455 @@ type Dependency struct {
 	Name      string
 	Current   string
 	Target    string
+	TargetRaw string // the version to update to, within any configured constraint and --target
 	Latest    string
 	LatestRaw string
 	Direct    bool
 	UpToDate  bool
+	GoVersion string // go directive of the version to update to, when known
+	NewPath   string // module path of the major version to update to; empty when the path stays
 }
 
 // Options configures the update command
 type Options struct {
-	Interactive bool
-	DryRun      bool
-	All         bool
-	Major       bool
-	Vendor      bool
-	ModPath     string
+	Interactive    bool
+	DryRun         bool
+	All            bool
+	Major          bool // move to the latest version of a higher major version when there is one
+	RewriteImports bool // point imports at the new module paths of major version updates
+	Vendor         bool
+	Test           bool // build and test the module after updating, rolling back if either fails
+	Commit         bool // apply and commit each update, or family of updates, on its own
+	PR             bool // commit the updates on a new branch, push it and open a pull request, or a merge request on GitLab
+	Force          bool
+	Audit          bool // scan for vulnerabilities before and after, for the digest
+	Pre            bool // a newer pre-release counts as the latest version
+	ModPath        string
+	Workspace      string   // go.work path; when set, every member module is updated
+	PlanOut        string   // write the selected updates to this plan file instead of applying them
+	Apply          string   // apply this plan file instead of looking up updates
+	Org            string   // only update modules under this path prefix, keeping lockstep families together
+	Porcelain      bool     // print stable records for scripts on stdout, everything else on stderr
+	Modules        []string // only update these modules, each as <module> or <module>@<version>
+	Exclude        []string // never update modules matching these patterns
+	Scope          string   // direct or indirect to update only those requirements, all for both; empty uses the config
+	Target         string   // patch or minor to update no further than that, latest or empty for no limit
+
+	porcelain *ui.Porcelain // where records go, set by Run with Porcelain
+	session   *session      // the interactive UI, set by Run with Interactive on a terminal
+	pr        *prTarget     // where the pull request goes, set by Run with PR
+	requests  []request     // Modules parsed by Run
 }
 
 // Run executes the update command
 func Run(ctx context.Context, opts Options) error {
 
+	if opts.Porcelain {
+		opts.porcelain = ui.StartPorcelain("update")
+	}
+
+	if opts.PR {
+		target, err := preparePullRequest(ctx, filepath.Dir(opts.ModPath))
+		if err != nil {
+			return err
+		}
+		opts.pr = target
+	} else if opts.Commit {
+		if err := checkCleanTree(ctx, filepath.Dir(opts.ModPath), "--commit"); err != nil {
+			return err
+		}
+	}
+
+	if opts.Apply != "" {
+		return runPlan(ctx, opts)
+	}
+
+	if len(opts.Modules) > 0 {
+		requests, err := parseRequests(opts.Modules)
+		if err != nil {
+			return err
+		}
+		opts.requests = requests
+		if err := checkRequested(opts); err != nil {
+			return err
+		}
+	}
+
+	// Interactive updates run in one program from the first lookup to the
+	// summary; without a spinner there's only the selection list to show
+	if opts.Interactive && ui.GetProgressMode() == ui.ProgressAuto {
+		s, sessionCtx, err := startSession(ctx)
+		if err != nil {
+			return err
+		}
+		defer s.close()
+		ctx, opts.session = sessionCtx, s
+	}
+
+	var err error
+	if opts.Workspace != "" {
+		err = runWorkspace(ctx, opts)
+	} else {
+		_, err = runModule(ctx, opts)
+	}
+	if errors.Is(err, errCancelled) {
+		fmt.Println("Update cancelled")
+		return nil
+	}
+	return err
+}
+
+// runWorkspace updates each module of a go.work workspace in turn
+func runWorkspace(ctx context.Context, opts Options) error {
+	ws, err := workspace.Load(opts.Workspace)
+	if err != nil {
+		return fmt.Errorf("loading workspace: %w", err)
+	}
+
+	total, touched := 0, 0
+	for _, member := range ws.Members {
+		fmt.Printf("\n%s %s\n", ui.HeaderStyle.Render("🗂  "+member.ModulePath), ui.UpToDateStyle.Render("("+member.Dir+")"))
+
+		memberOpts := opts
+		memberOpts.ModPath = member.ModPath
+
+		updated, err := runModule(ctx, memberOpts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", member.Dir, err)
+		}
+		if updated > 0 {
+			total += updated
+			touched++
+		}
+	}
+
+	if !opts.DryRun {
+		fmt.Printf("\n%s updated %s package(s) in %s of %s modules\n",
+			ui.SummaryStyle.Render("📊 Workspace summary:"), ui.FormatCount(total),
+			ui.FormatCount(touched), ui.FormatCount(len(ws.Members)))
+	}
+
+	return nil
+}
+
+// runModule updates a single go.mod and returns how many packages were updated
+func runModule(ctx context.Context, opts Options) (int, error) {
+
 	parser, err := modfile.NewParser(opts.ModPath)
 	if err != nil {
-		return fmt.Errorf("parsing go.mod: %w", err)
+		return 0, fmt.Errorf("parsing go.mod: %w", err)
 	}
 
-	proxyClient := proxy.NewClient("")
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("loading config: %w", err)
+	}
 
-	deps, err := loadDependenciesWithSpinner(ctx, parser, proxyClient)
+	excludes, err := pattern.ParseFilter(nil, opts.Exclude)
 	if err != nil {
-		return fmt.Errorf("loading dependencies: %w", err)
+		return 0, fmt.Errorf("--exclude: %w", err)
+	}
+
+	proxyClient := cfg.NewProxyClient()
+	familyOf := familyFor(cfg)
+
+	// Named modules are updated whatever the configured scope
+	scope := opts.Scope
+	if scope == "" && len(opts.requests) == 0 {
+		scope = cfg.UpdateScope
+	}
+	if scope != "" && scope != "all" && scope != "direct" && scope != "indirect" {
+		return 0, fmt.Errorf("update_scope in the config is %q, want all, direct or indirect", scope)
+	}
+
+	local := make(map[string]bool)
+	for _, path := range parser.LocalReplacements() {
+		local[path] = true
+	}
+
+	// Modules replaced by local directories have nothing to update to
+	var requires []*xmodfile.Require
+	excluded, inScope := 0, 0
+	for _, req := range parser.AllRequires() {
+		if (scope == "direct" && req.Indirect) || (scope == "indirect" && !req.Indirect) {
+			continue
+		}
+		inScope++
+		if !excludes.Matches(req.Mod.Path) {
+			excluded++
+			continue
+		}
+		if len(opts.requests) > 0 && !isRequested(opts.requests, familyOf, req.Mod.Path) {
+			continue
+		}
+		if _, named := requestFor(opts.requests, req.Mod.Path); named && local[req.Mod.Path] {
+			return 0, fmt.Errorf("%s is replaced by a local directory", req.Mod.Path)
+		} else if named && cfg.IsPinned(req.Mod.Path) {
+			return 0, fmt.Errorf("%s is pinned in the config", req.Mod.Path)
+		}
+		if cfg.IsPinned(req.Mod.Path) || local[req.Mod.Path] {
+			continue
+		}
+		if opts.Org != "" && !pattern.Match(familyPattern(opts.Org), req.Mod.Path) {
+			continue
+		}
+		requires = append(requires, req)
+	}
+
+	if inScope == 0 && (scope == "direct" || scope == "indirect") {
+		fmt.Printf("No %s requirements in go.mod\n", scope)
+		return 0, nil
+	}
+	if len(requires) == 0 && opts.Org != "" {
+		fmt.Printf("No modules under %s in go.mod\n", opts.Org)
+		return 0, nil
+	}
+	if len(requires) == 0 && excluded > 0 {
+		fmt.Printf("All %s modules in go.mod are excluded\n", ui.FormatCount(excluded))
+		return 0, nil
+	}
+	if len(requires) == 0 && len(opts.requests) > 0 {
+		fmt.Println("None of the given modules are required in go.mod")
+		return 0, nil
+	}
+
+	deps, err := loadDependenciesWithSpinner(ctx, opts.session, requires, proxyClient, opts.Pre, targetConstraints(parser, cfg, opts.Target))
+	if err != nil {
+		return 0, fmt.Errorf("loading dependencies: %w", err)
 	}
 
 	if len(deps) == 0 {
 		fmt.Println("No dependencies found in go.mod")
-		return nil
+		return 0, nil
 	}
 
-	allUpToDate := true
-	for _, dep := range deps {
-		if !dep.UpToDate {
-			allUpToDate = false
-			break
+	// Configured families take precedence; without any, --org modules that
+	// share a version are kept together
+	families := withoutVersioned(configuredFamilies(deps, familyOf), opts.requests)
+	if len(families) == 0 && opts.Org != "" {
+		if f, ok := orgFamily(deps, opts.Org); ok {
+			families = append(families, f)
 		}
 	}
+	for _, f := range families {
+		f.align(ctx, proxyClient, opts.Pre)
+	}
+	if err := applyRequestedVersions(ctx, proxyClient, deps, opts.requests); err != nil {
+		return 0, err
+	}
+	if opts.Major {
+		// Families, constraints and requested versions keep their targets
+		applyMajorsWithSpinner(ctx, opts.session, proxyClient, deps, opts.Pre, func(dep *Dependency) bool {
+			_, constrained := cfg.ConstraintFor(dep.Name)
+			r, requested := requestFor(opts.requests, dep.Name)
+			return constrained || (requested && r.version != "") || inFamily(families, dep)
+		})
+	}
 
-	if allUpToDate {
-		fmt.Println("✨ All dependencies are up to date!")
-		return nil
+	if allUpToDate(deps) {
+		if len(opts.requests) > 0 {
+			fmt.Println("✨ The given modules are up to date!")
+		} else {
+			fmt.Println("✨ All dependencies are up to date!")
+		}
+		return 0, writeEmptyPlan(opts, parser)
 	}
 
 	var toUpdate []*Dependency
 	if opts.Interactive {
-		selected, err := RunInteractive(deps)
-		if err != nil {
-			return fmt.Errorf("interactive selection: %w", err)
+		var selected []*Dependency
+		notes := newNotesSource(ctx, cfg.NewGitHubModulesClient())
+		if opts.session != nil {
+			selected = opts.session.choose(deps, parser.GoVersion(), notes)
+		} else if selected, err = RunInteractive(deps, parser.GoVersion(), notes); err != nil {
+			return 0, fmt.Errorf("interactive selection: %w", err)
 		}
 		if selected == nil {
 			fmt.Println("Update cancelled")
-			return nil
+			return 0, nil
 		}
-		toUpdate = selected
-	} else if opts.All {
+		toUpdate = withFamilies(selected, families)
+	} else if len(opts.requests) > 0 {
+		toUpdate = requestedUpdates(deps, opts.requests, families)
+	} else if opts.All || opts.Org != "" {
 		for _, dep := range deps {
 			if !dep.UpToDate {
 				toUpdate = append(toUpdate, dep)
 			}
 		}
 	} else {
-		return fmt.Errorf("please specify -i (interactive) or --all")
+		return 0, fmt.Errorf("please specify modules to update, -i (interactive), --all or --org")
 	}
 
 	if len(toUpdate) == 0 {
 		fmt.Println("No packages selected for update")
-		return nil
+		return 0, writeEmptyPlan(opts, parser)
+	}
+
+	if opts.PlanOut != "" {
+		if err := writePlan(opts.PlanOut, opts.ModPath, parser, toUpdate); err != nil {
+			return 0, err
+		}
+		writeUpdateRecords(opts, parser, toUpdate, statePlanned)
+		return 0, nil
 	}
 
 	if opts.DryRun {
-		fmt.Println("\n📋 Would update:")
-		for _, dep := range toUpdate {
-			fmt.Printf("  • %s: %s → %s\n", dep.Name, dep.Current, dep.Latest)
+		printWouldUpdate(toUpdate)
+		writeUpdateRecords(opts, parser, toUpdate, stateWouldUpdate)
+		if err := previewUpdates(opts, parser, toUpdate); err != nil {
+			return 0, err
+		}
+		warnStaleCodegen(filepath.Dir(opts.ModPath), toUpdate)
+		return 0, nil
+	}
+
+	if opts.pr != nil {
+		return openPullRequest(ctx, opts, parser, toUpdate, families)
+	}
+	if opts.Commit {
+		commits, err := commitUpdates(ctx, opts, parser, toUpdate, families)
+		return updatedIn(commits), err
+	}
+	return applyUpdates(ctx, opts, parser, toUpdate)
+}
+
+func printWouldUpdate(toUpdate []*Dependency) {
+	fmt.Println("\n📋 Would update:")
+	for _, dep := range toUpdate {
+		latest := ""
+		if dep.Latest != dep.Target && dep.Latest != "unknown" {
+			latest = ui.UpToDateStyle.Render(" (latest " + dep.Latest + ")")
+		}
+		fmt.Printf("  • %s: %s → %s%s\n", dep.label(), dep.Current, dep.Target, latest)
+	}
+}
+
+// targetConstraints returns the version constraint of each module: the one
+// configured for it, narrowed by the update target for the required version
+func targetConstraints(parser *modfile.Parser, cfg *config.Config, target string) func(string) (versions.Constraint, bool) {
+	return func(modulePath string) (versions.Constraint, bool) {
+		configured, ok := cfg.ConstraintFor(modulePath)
+		req := parser.FindRequire(modulePath)
+		if req == nil {
+			return configured, ok
+		}
+		policy, limited := versions.TargetConstraint(target, req.Mod.Version)
+		switch {
+		case ok && limited:
+			return configured.And(policy), true
+		case limited:
+			return policy, true
+		}
+		return configured, ok
+	}
+}
+
+// applyUpdates writes the updates to go.mod, tidies the module and prints the
+// digest, returning how many packages were updated
+func applyUpdates(ctx context.Context, opts Options, parser *modfile.Parser, toUpdate []*Dependency) (int, error) {
+	summary, err := applyBatch(ctx, opts, parser, toUpdate)
+	if summary == nil {
+		return 0, err
+	}
+	return len(summary.Updated), err
+}
+
+// applyBatch does the work of applyUpdates, returning the digest of the run
+// once go.mod is written. Updates rolled back by --test return none.
+func applyBatch(ctx context.Context, opts Options, parser *modfile.Parser, toUpdate []*Dependency) (*digest, error) {
+	before := takeSnapshot(parser)
+	summary := &digest{Updated: toUpdate, Commands: []string{commandLine()}}
+
+	var scanner *vulndb.Scanner
+	var scanBefore *vulndb.ScanResult
+	if opts.Audit {
+		var err error
+		scanner, err = vulndb.NewScanner()
+		if err == nil {
+			scanBefore, err = scanModuleWithSpinner(ctx, opts.session, scanner, opts.ModPath)
+		}
+		if err != nil {
+			ui.Error("⚠️  Warning: skipping vulnerability scan: %v\n", err)
+			scanner = nil
 		}
-		return nil
 	}
 
-	if err := updateDependenciesWithProgress(parser, toUpdate); err != nil {
-		return fmt.Errorf("updating dependencies: %w", err)
+	store, err := history.Open(opts.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+
+	description := fmt.Sprintf("%d package(s)", len(toUpdate))
+	if opts.Org != "" {
+		description += " under " + opts.Org
+	}
+	tx, err := store.Begin("update", description)
+	if err != nil {
+		return nil, fmt.Errorf("recording history: %w", err)
 	}
 
+	writer := modfile.NewWriter(parser).WithForce(opts.Force)
+	if err := updateDependenciesWithProgress(opts.session, writer, toUpdate); err != nil {
+		tx.Discard()
+		return nil, fmt.Errorf("updating dependencies: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		ui.Error("⚠️  Warning: could not record update history: %v\n", err)
+	}
 	fmt.Printf("\n✓ Successfully updated %d package(s)\n", len(toUpdate))
+	if !opts.Test {
+		writeUpdateRecords(opts, parser, toUpdate, stateUpdated)
+	}
 
 	workDir := filepath.Dir(opts.ModPath)
 
+	// go mod tidy would put back the old major versions while they're imported
+	var rewritten []importpath.Move
+	if moves := majorMoves(toUpdate); len(moves) > 0 {
+		if opts.RewriteImports {
+			n, err := rewriteImports(workDir, moves)
+			if n > 0 {
+				rewritten = moves
+				if err := tx.Record(fmt.Sprintf("rewrote imports in %d file(s)", n)); err != nil {
+					ui.Error("⚠️  Warning: could not record update history: %v\n", err)
+				}
+			}
+			if err != nil {
+				return summary, err
+			}
+		} else if warnImporters(workDir, moves) {
+			if opts.Test {
+				return nil, rollBack(ctx, opts, parser, tx, toUpdate, nil, errors.New("the old major versions are still imported"))
+			}
+			fmt.Println("   Skipping go mod tidy until they do")
+			return summary, nil
+		}
+	}
+
 	fmt.Println("\n🔧 Running go mod tidy...")
-	if err := runGoCommand(ctx, workDir, "mod", "tidy"); err != nil {
+	summary.Commands = append(summary.Commands, "go mod tidy")
+	if err := tx.Record(history.EffectTidy); err != nil {
+		ui.Error("⚠️  Warning: could not record update history: %v\n", err)
+	}
+	if err := runGoCommand(ctx, opts.session, workDir, "mod", "tidy"); err != nil {
+		if opts.Test {
+			return nil, rollBack(ctx, opts, parser, tx, toUpdate, rewritten, &verifyFailure{Command: "go mod tidy", Output: err.Error()})
+		}
 		fmt.Printf("⚠️  Warning: go mod tidy failed: %v\n", err)
 		fmt.Println("   You may need to run 'go mod tidy' manually")
-		return nil
+		return summary, nil
 	}
 	fmt.Println("✓ go.mod and go.sum updated")
 
 	if opts.Vendor {
 		fmt.Println("\n📦 Running go mod vendor...")
-		if err := runGoCommand(ctx, workDir, "mod", "vendor"); err != nil {
+		summary.Commands = append(summary.Commands, "go mod vendor")
+		if err := tx.Record(history.EffectVendor); err != nil {
+			ui.Error("⚠️  Warning: could not record update history: %v\n", err)
+		}
+		if err := runGoCommand(ctx, opts.session, workDir, "mod", "vendor"); err != nil {
 			fmt.Printf("⚠️  Warning: go mod vendor failed: %v\n", err)
 			fmt.Println("   You may need to run 'go mod vendor' manually")
 		} else {
@@ -124,17 +481,40 @@ func Run(ctx context.Context, opts Options) error {
 		}
 	}
 
-	return nil
-}
+	if opts.Test {
+		if err := verifyUpdates(ctx, opts.session, workDir, summary); err != nil {
+			return nil, rollBack(ctx, opts, parser, tx, toUpdate, rewritten, err)
+		}
+		writeUpdateRecords(opts, parser, toUpdate, stateUpdated)
+	}
+
+	warnStaleCodegen(workDir, toUpdate)
+
+	if scanner != nil {
+		if scanAfter, err := scanModuleWithSpinner(ctx, opts.session, scanner, opts.ModPath); err != nil {
+			ui.Error("⚠️  Warning: vulnerability scan failed: %v\n", err)
+		} else {
+			summary.compareScans(scanBefore, scanAfter)
+			writeFixedRecords(opts, parser, summary.Fixed)
+		}
+	}
 
-func runGoCommand(ctx context.Context, dir string, args ...string) error {
-	cmd := exec.CommandContext(ctx, "go", args...)
-	if dir != "" && dir != "." {
-		cmd.Dir = dir
+	if err := summary.compare(before, opts.ModPath); err != nil {
+		ui.Error("⚠️  Warning: could not read go.mod for the summary: %v\n", err)
+		return summary, nil
 	}
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%w: %s", err, string(output))
+	summary.print()
+
+	return summary, nil
+}
+
+// runGoCommand runs a go command, under the session's spinner when there is one
+func runGoCommand(ctx context.Context, s *session, dir string, args ...string) error {
+	if s == nil {
+		return gocmd.Run(ctx, dir, args...)
 	}
-	return nil
+	_, err := runInSession(s, "Running go "+strings.Join(args, " ")+"...", 0, func(chan<- updateProgress) (struct{}, error) {
+		return struct{}{}, gocmd.Run(ctx, dir, args...)
+	})
+	return err
 }
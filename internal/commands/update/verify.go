@@ -0,0 +1,108 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// verifyCommands are the go commands --test runs after updating, in order
+var verifyCommands = [][]string{
+	{"build", "./..."},
+	{"test", "./..."},
+}
+
+// maxFailureLines is how much of a failed command's output is shown
+const maxFailureLines = 30
+
+// verifyFailure is a go command that failed after the update
+type verifyFailure struct {
+	Command string
+	Output  string
+}
+
+func (f *verifyFailure) Error() string {
+	return f.Command + " failed"
+}
+
+// verifyUpdates builds and tests the module, stopping at the first command
+// that fails
+func verifyUpdates(ctx context.Context, s *session, dir string, summary *digest) error {
+	for _, args := range verifyCommands {
+		command := "go " + strings.Join(args, " ")
+		fmt.Printf("\n🧪 Running %s\n", command)
+		summary.Commands = append(summary.Commands, command)
+		if err := runGoCommand(ctx, s, dir, args...); err != nil {
+			return &verifyFailure{Command: command, Output: err.Error()}
+		}
+		fmt.Printf("✓ %s passed\n", command)
+	}
+	return nil
+}
+
+// rollBack puts go.mod and go.sum back as they were before the updates,
+// along with any imports rewritten for them, and reports the batch of
+// updates that broke the module. The returned error wraps cause.
+func rollBack(ctx context.Context, opts Options, parser *modfile.Parser, tx *history.Transaction, toUpdate []*Dependency, rewritten []importpath.Move, cause error) error {
+	var failure *verifyFailure
+	if errors.As(cause, &failure) {
+		fmt.Printf("\n%s\n", ui.MajorStyle.Render("✗ "+failure.Command+" failed after the update:"))
+		for _, line := range tail(failure.Output, maxFailureLines) {
+			fmt.Printf("  %s\n", line)
+		}
+	} else {
+		fmt.Printf("\n%s\n", ui.MajorStyle.Render("✗ "+cause.Error()))
+	}
+
+	fmt.Printf("\n↩️  Rolling back this batch of %s update(s):\n", ui.FormatCount(len(toUpdate)))
+	for _, dep := range toUpdate {
+		fmt.Printf("  %s %s → %s\n", dep.label(), dep.Current, dep.Target)
+	}
+
+	if err := tx.Restore(); err != nil {
+		return fmt.Errorf("%w, and restoring go.mod failed: %v (try gx undo)", cause, err)
+	}
+	fmt.Println("✓ go.mod and go.sum restored")
+
+	workDir := filepath.Dir(opts.ModPath)
+	if len(rewritten) > 0 {
+		back := make([]importpath.Move, len(rewritten))
+		for i, m := range rewritten {
+			back[i] = importpath.Move{Old: m.New, New: m.Old}
+		}
+		changed, err := importpath.Rewrite(workDir, back)
+		if err != nil {
+			return fmt.Errorf("%w, and restoring imports failed: %v", cause, err)
+		}
+		fmt.Printf("✓ Restored imports in %s file(s)\n", ui.FormatCount(len(changed)))
+	}
+
+	if opts.Vendor {
+		if err := runGoCommand(ctx, opts.session, workDir, "mod", "vendor"); err != nil {
+			fmt.Printf("⚠️  Warning: go mod vendor failed: %v\n", err)
+			fmt.Println("   You may need to run 'go mod vendor' manually")
+		} else {
+			fmt.Println("✓ vendor directory restored")
+		}
+	}
+
+	writeUpdateRecords(opts, parser, toUpdate, stateRolledBack)
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("Update fewer modules at a time, e.g. gx update -i, to find the one that breaks"))
+	return fmt.Errorf("%w; rolled back %d update(s)", cause, len(toUpdate))
+}
+
+// tail returns the last n lines of s
+func tail(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package verifybuild
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRef     string
+	flagLDFlags string
+	flagJSON    bool
+)
+
+// NewCommand creates the verify-build command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-build <artifact>",
+		Short: "Check that a released binary can be reproduced from source",
+		Long: `Rebuild the module at the commit a released binary was built from and
+compare the result with the binary, to confirm the release is reproducible.
+
+The build information embedded in the artifact supplies the revision, the Go
+toolchain (selected with GOTOOLCHAIN), the target platform, and build flags
+such as -ldflags and -tags. The source is checked out into a temporary git
+worktree and built with -trimpath. When the binaries differ, the build
+inputs that differ (toolchain, dependencies, settings) are listed.
+
+The artifact is a file or an http(s) URL. Run this from the module's
+directory; the command exits non-zero when the build is not reproducible.
+
+Examples:
+  # Verify a downloaded release binary
+  gx verify-build ./dist/app_linux_amd64
+
+  # Verify against a tag when the binary has no VCS information
+  gx verify-build https://example.com/releases/app --ref v1.2.0
+
+  # Supply the linker flags the release used to stamp its version
+  gx verify-build ./app --ldflags "-s -w -X main.version=v1.2.0"`,
+		Args: cobra.ExactArgs(1),
+		RunE: runVerifyBuild,
+	}
+
+	cmd.Flags().StringVar(&flagRef, "ref", "", "Tag or commit to build (default: the revision recorded in the artifact)")
+	cmd.Flags().StringVar(&flagLDFlags, "ldflags", "", "Linker flags of the release build (not recorded in -trimpath binaries)")
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runVerifyBuild(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	opts := Options{
+		Artifact: args[0],
+		Ref:      flagRef,
+		LDFlags:  flagLDFlags,
+		JSON:     flagJSON,
+		ModPath:  modPath,
+	}
+
+	err := Run(cmd.Context(), opts)
+	if err == errNotReproducible {
+		// The comparison is already printed; the error only sets the exit code
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package verifybuild
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+func buildWithSpinner(ctx context.Context, info *debug.BuildInfo, ref, ldflags, moduleDir, worktree, output string) error {
+	_, err := ui.RunSimpleSpinner("Rebuilding at "+ref+"...", func() (struct{}, error) {
+		return struct{}{}, build(ctx, info, ref, ldflags, moduleDir, worktree, output)
+	})
+	return err
+}
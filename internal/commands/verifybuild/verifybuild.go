@@ -0,0 +1,242 @@
+package verifybuild
+
+import (
+	"context"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+	"github.com/omarshaarawi/gx/internal/gocmd"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/rebuild"
+	"github.com/omarshaarawi/gx/internal/ui"
+)
+
+// errNotReproducible is returned after reporting binaries that differ
+var errNotReproducible = errors.New("build is not reproducible")
+
+// Options configures the verify-build command
+type Options struct {
+	Artifact string
+	Ref      string
+	LDFlags  string
+	JSON     bool
+	ModPath  string
+}
+
+// Report is the result of a verification
+type Report struct {
+	Artifact       string               `json:"artifact"`
+	Revision       string               `json:"revision"`
+	Toolchain      string               `json:"toolchain"`
+	Platform       string               `json:"platform"`
+	ArtifactSHA256 string               `json:"artifact_sha256"`
+	RebuiltSHA256  string               `json:"rebuilt_sha256"`
+	Reproducible   bool                 `json:"reproducible"`
+	LDFlagsUnknown bool                 `json:"ldflags_unknown"`
+	Differences    []rebuild.Difference `json:"differences"`
+}
+
+// Run executes the verify-build command
+func Run(ctx context.Context, opts Options) error {
+
+	dir, err := os.MkdirTemp("", "gx-verify-build-*")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	artifact, err := fetchArtifact(ctx, opts.Artifact, dir)
+	if err != nil {
+		return err
+	}
+
+	info, err := buildinfo.ReadFile(artifact)
+	if err != nil {
+		return fmt.Errorf("reading build info of %s: %w", opts.Artifact, err)
+	}
+
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+	if info.Main.Path != parser.ModulePath() {
+		return fmt.Errorf("%s was built from module %q, but this is %q", opts.Artifact, info.Main.Path, parser.ModulePath())
+	}
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = rebuild.Revision(info)
+	}
+	if ref == "" {
+		return fmt.Errorf("%s records no revision or version; pass --ref", opts.Artifact)
+	}
+	if rebuild.Setting(info, "vcs.modified") == "true" {
+		ui.Error("⚠️  Warning: %s was built from a tree with uncommitted changes\n", opts.Artifact)
+	}
+
+	rebuilt := filepath.Join(dir, "rebuilt")
+	if err := buildWithSpinner(ctx, info, ref, opts.LDFlags, filepath.Dir(opts.ModPath), filepath.Join(dir, "src"), rebuilt); err != nil {
+		return err
+	}
+
+	rebuiltInfo, err := buildinfo.ReadFile(rebuilt)
+	if err != nil {
+		return fmt.Errorf("reading build info of the rebuilt binary: %w", err)
+	}
+
+	report := Report{
+		Artifact:  opts.Artifact,
+		Revision:  ref,
+		Toolchain: info.GoVersion,
+		Platform:  rebuild.Setting(info, "GOOS") + "/" + rebuild.Setting(info, "GOARCH"),
+	}
+	if report.ArtifactSHA256, err = hashFile(artifact); err != nil {
+		return err
+	}
+	if report.RebuiltSHA256, err = hashFile(rebuilt); err != nil {
+		return err
+	}
+	report.Reproducible = report.ArtifactSHA256 == report.RebuiltSHA256
+	report.LDFlagsUnknown = opts.LDFlags == "" && rebuild.Setting(info, "-ldflags") == "" && rebuild.Setting(info, "-trimpath") == "true"
+	report.Differences = rebuild.Compare(info, rebuiltInfo)
+	if report.Differences == nil {
+		report.Differences = []rebuild.Difference{}
+	}
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		render(report)
+	}
+
+	if !report.Reproducible {
+		return errNotReproducible
+	}
+	return nil
+}
+
+// fetchArtifact returns a local path of the artifact, downloading URLs into dir
+func fetchArtifact(ctx context.Context, artifact, dir string) (string, error) {
+	if !strings.HasPrefix(artifact, "http://") && !strings.HasPrefix(artifact, "https://") {
+		return artifact, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifact, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", artifact, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: server returned %d", artifact, resp.StatusCode)
+	}
+
+	path := filepath.Join(dir, "artifact")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("downloading %s: %w", artifact, err)
+	}
+	return path, f.Close()
+}
+
+// build checks ref out into a temporary worktree and rebuilds the artifact there
+func build(ctx context.Context, info *debug.BuildInfo, ref, ldflags, moduleDir, worktree, output string) error {
+	prefix, err := gitcmd.Output(ctx, moduleDir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return fmt.Errorf("finding the git repository: %w", err)
+	}
+
+	if err := gitcmd.Run(ctx, moduleDir, "worktree", "add", "--detach", worktree, ref); err != nil {
+		return fmt.Errorf("checking out %s: %w", ref, err)
+	}
+	defer gitcmd.Run(context.WithoutCancel(ctx), moduleDir, "worktree", "remove", "--force", worktree)
+
+	args, env, err := rebuild.Command(info, output, ldflags)
+	if err != nil {
+		return err
+	}
+	if err := gocmd.RunEnv(ctx, filepath.Join(worktree, prefix), env, args...); err != nil {
+		return fmt.Errorf("building %s at %s: %w", info.Path, ref, err)
+	}
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func render(r Report) {
+	row := func(label, value string) {
+		fmt.Printf("  %-10s %s\n", label, value)
+	}
+
+	fmt.Println()
+	row("File", r.Artifact)
+	row("Revision", r.Revision)
+	row("Toolchain", r.Toolchain+" "+r.Platform)
+	row("Artifact", "sha256:"+r.ArtifactSHA256)
+	row("Rebuilt", "sha256:"+r.RebuiltSHA256)
+	fmt.Println()
+
+	if r.Reproducible {
+		fmt.Println("✓ Reproducible: the rebuilt binary is identical to the artifact")
+		return
+	}
+
+	fmt.Println("✗ Not reproducible: the rebuilt binary differs from the artifact")
+
+	if len(r.Differences) == 0 {
+		fmt.Println("\nAll recorded build inputs match, so the difference comes from something")
+		fmt.Println("build info does not capture, such as linker flags or the C toolchain.")
+	} else {
+		fmt.Printf("\n%s (%d)\n\n", ui.HeaderStyle.Render("Differing build inputs"), len(r.Differences))
+		table := ui.NewTable("Kind", "Name", "Artifact", "Rebuilt")
+		for _, d := range r.Differences {
+			table.AddRow(d.Kind, d.Name, orNone(d.Artifact), orNone(d.Rebuilt))
+		}
+		fmt.Print(table.Render())
+	}
+
+	if r.LDFlagsUnknown {
+		fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render("-trimpath builds don't record -ldflags; if the release sets any (e.g. -X main.version=...), pass them with --ldflags"))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package vulndbcmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var flagSource string
+
+// NewCommand creates the vulndb command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vulndb",
+		Short: "Manage the vulnerability database used for scanning",
+		Long: `Manage the Go vulnerability database that gx audit and the other
+scanning commands pass to govulncheck.
+
+Scans use https://vuln.go.dev unless vulndb in the config, or GOVULNDB,
+names another one: a URL, such as an internal mirror, or a directory
+written by 'gx vulndb mirror'.
+
+Examples:
+  # Download the database for an air-gapped machine
+  gx vulndb mirror ./vulndb
+
+  # Scan there against the copy
+  GOVULNDB=./vulndb gx audit`,
+	}
+
+	cmd.AddCommand(newMirrorCommand())
+
+	return cmd
+}
+
+func newMirrorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror <dir>",
+		Short: "Download the vulnerability database for offline use",
+		Long: `Download the whole Go vulnerability database into a directory, to
+carry to an air-gapped network. There, point vulndb in the config or
+GOVULNDB at the directory, or serve it over HTTP and point them at the URL.
+
+The database is copied from the configured one when that is a URL,
+otherwise from https://vuln.go.dev; --source picks another. Running mirror
+again refreshes the copy; a directory holding anything else is refused.
+
+Examples:
+  # Copy vuln.go.dev
+  gx vulndb mirror ./vulndb
+
+  # Copy an internal mirror
+  gx vulndb mirror ./vulndb --source https://vuln.corp.example`,
+		Args: cobra.ExactArgs(1),
+		RunE: runMirror,
+	}
+
+	cmd.Flags().StringVar(&flagSource, "source", "", "Database to copy (default the configured one, or https://vuln.go.dev)")
+
+	return cmd
+}
+
+func runMirror(cmd *cobra.Command, args []string) error {
+	opts := MirrorOptions{
+		Dir:    args[0],
+		Source: flagSource,
+	}
+
+	cmd.SilenceUsage = true
+	return RunMirror(cmd.Context(), opts)
+}
@@ -0,0 +1,45 @@
+package vulndbcmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// MirrorOptions configures the mirror command
+type MirrorOptions struct {
+	Dir    string
+	Source string // database to copy; empty for the configured one
+}
+
+// RunMirror executes the mirror command
+func RunMirror(ctx context.Context, opts MirrorOptions) error {
+
+	source := opts.Source
+	if source == "" {
+		source = vulndb.Database()
+		if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+			source = vulndb.DefaultDatabase
+		}
+	}
+
+	mirror, err := ui.RunSimpleSpinner(fmt.Sprintf("Downloading the vulnerability database from %s...", source), func() (*vulndb.Mirror, error) {
+		return vulndb.MirrorDatabase(ctx, source, opts.Dir)
+	})
+	if err != nil {
+		return fmt.Errorf("mirroring %s: %w", source, err)
+	}
+
+	dir := mirror.Dir
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	fmt.Printf("✓ Mirrored %s vulnerabilities (database modified %s) to %s\n",
+		ui.FormatCount(mirror.Vulns), ui.FormatDate(mirror.Modified), dir)
+	fmt.Printf("\n💡 %s\n", ui.CTAStyle.Render(fmt.Sprintf("Copy it to the offline machine and set 'vulndb: %s' in the config, or GOVULNDB", dir)))
+	return nil
+}
@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagInterval time.Duration
+	flagAll      bool
+	flagWebhook  string
+)
+
+// NewCommand creates the watch command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Poll for new dependency releases",
+		Long: `Keep running and re-check dependencies on an interval, reporting only
+updates that were not available on the previous check.
+
+go.mod is re-read on every check, so updates applied while watching are
+picked up. New updates can also be POSTed as JSON to a webhook.
+
+Examples:
+  # Check every hour
+  gx watch --interval 1h
+
+  # Include indirect dependencies and notify a webhook
+  gx watch --all --webhook https://hooks.example.com/gx`,
+		RunE: runWatch,
+	}
+
+	cmd.Flags().DurationVar(&flagInterval, "interval", time.Hour, "Time between checks")
+	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, "Watch indirect dependencies too")
+	cmd.Flags().StringVar(&flagWebhook, "webhook", "", "POST new updates as JSON to this URL")
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	modPath := modflag.Path(cmd)
+	if _, err := os.Stat(modPath); os.IsNotExist(err) {
+		return fmt.Errorf("go.mod not found %s", modflag.Where(modPath))
+	}
+
+	if flagInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m, got %s", flagInterval)
+	}
+
+	opts := Options{
+		Interval: flagInterval,
+		All:      flagAll,
+		Webhook:  flagWebhook,
+		ModPath:  modPath,
+	}
+
+	return Run(cmd.Context(), opts)
+}
@@ -0,0 +1,215 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/omarshaarawi/gx/internal/versions"
+)
+
+// Options configures the watch command
+type Options struct {
+	Interval time.Duration
+	All      bool
+	Webhook  string
+	ModPath  string
+}
+
+// Update is a newly available dependency version
+type Update struct {
+	Module     string    `json:"module"`
+	Current    string    `json:"current"`
+	Latest     string    `json:"latest"`
+	UpdateType string    `json:"update_type"`
+	Published  time.Time `json:"published"`
+}
+
+// Run executes the watch command
+func Run(ctx context.Context, opts Options) error {
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// A single client keeps its cache between checks
+	client := cfg.NewProxyClient()
+	seen := make(map[string]string)
+	first := true
+
+	fmt.Printf("👀 Watching %s every %s (Ctrl+C to stop)\n", opts.ModPath, opts.Interval)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		updates, err := check(ctx, opts, cfg, client)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			ui.Error("⚠️  Warning: check failed: %v\n", err)
+		} else {
+			fresh := newUpdates(updates, seen)
+			report(fresh, first, len(updates))
+			if len(fresh) > 0 && opts.Webhook != "" && !first {
+				if err := notify(ctx, opts.Webhook, fresh); err != nil {
+					ui.Error("⚠️  Warning: webhook failed: %v\n", err)
+				}
+			}
+			first = false
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n✓ Stopped watching")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check returns every dependency with an update available
+func check(ctx context.Context, opts Options, cfg *config.Config, client *proxy.Client) ([]Update, error) {
+	parser, err := modfile.NewParser(opts.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	requires := parser.DirectRequires()
+	if opts.All {
+		requires = parser.AllRequires()
+	}
+	client.WithLocalModules(parser.LocalReplacements())
+
+	var updates []Update
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, req := range requires {
+		if cfg.IgnoreRuleFor(req.Mod.Path) != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(path, current string) {
+			defer wg.Done()
+
+			latest, err := client.Latest(ctx, path)
+			if err != nil {
+				ui.Debug("fetching %s: %v", path, err)
+				return
+			}
+
+			updateType := versions.Classify(current, latest.Version)
+			if updateType == versions.None {
+				return
+			}
+
+			mu.Lock()
+			updates = append(updates, Update{
+				Module:     path,
+				Current:    current,
+				Latest:     latest.Version,
+				UpdateType: updateType,
+				Published:  latest.Time,
+			})
+			mu.Unlock()
+		}(req.Mod.Path, req.Mod.Version)
+	}
+
+	wg.Wait()
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Module < updates[j].Module
+	})
+
+	return updates, nil
+}
+
+// newUpdates returns updates whose latest version differs from the last one reported
+func newUpdates(updates []Update, seen map[string]string) []Update {
+	var fresh []Update
+	for _, u := range updates {
+		if seen[u.Module] == u.Latest {
+			continue
+		}
+		seen[u.Module] = u.Latest
+		fresh = append(fresh, u)
+	}
+	return fresh
+}
+
+func report(fresh []Update, first bool, total int) {
+	stamp := ui.UpToDateStyle.Render(time.Now().Format("15:04:05"))
+
+	if first {
+		fmt.Printf("%s %s updates available\n", stamp, ui.FormatCount(total))
+		for _, u := range fresh {
+			printUpdate(u)
+		}
+		return
+	}
+
+	if len(fresh) == 0 {
+		ui.Debug("no new releases")
+		return
+	}
+
+	fmt.Printf("\n%s 🆕 %s new releases\n", stamp, ui.FormatCount(len(fresh)))
+	for _, u := range fresh {
+		printUpdate(u)
+	}
+}
+
+func printUpdate(u Update) {
+	style := ui.FormatVersionUpdate(u.UpdateType)
+	latest := u.Latest
+	if s := ui.UpdateSymbol(u.UpdateType); s != "" {
+		latest = s + " " + latest
+	}
+	fmt.Printf("  %s %s → %s %s\n",
+		u.Module, u.Current, style.Render(latest),
+		ui.UpToDateStyle.Render(ui.FormatReleaseTime(u.Published)))
+}
+
+func notify(ctx context.Context, url string, updates []Update) error {
+	body, err := json.Marshal(map[string]any{"updates": updates})
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package compare diffs the requirement lists of two modules.
+package compare
+
+import (
+	"sort"
+
+	"github.com/omarshaarawi/gx/internal/versions"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Entry is a requirement found on only one side
+type Entry struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+}
+
+// Divergence is a requirement both sides have at different versions
+type Divergence struct {
+	Path  string `json:"path"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	// Newer is "left" or "right"
+	Newer string `json:"newer"`
+	// UpdateType is the jump from the older to the newer version (major, minor, patch)
+	UpdateType string `json:"update_type"`
+}
+
+// Result is the difference between two requirement lists, each part sorted by module path
+type Result struct {
+	Diverged  []Divergence `json:"diverged"`
+	OnlyLeft  []Entry      `json:"only_left"`
+	OnlyRight []Entry      `json:"only_right"`
+	Shared    int          `json:"shared"`
+}
+
+// Identical reports whether both sides require the same versions
+func (r Result) Identical() bool {
+	return len(r.Diverged) == 0 && len(r.OnlyLeft) == 0 && len(r.OnlyRight) == 0
+}
+
+// Diff compares two requirement lists. A requirement listed more than once
+// counts at its highest version, as minimal version selection would.
+func Diff(left, right []*modfile.Require) Result {
+	l, r := index(left), index(right)
+
+	res := Result{Diverged: []Divergence{}, OnlyLeft: []Entry{}, OnlyRight: []Entry{}}
+	for path, lreq := range l {
+		rreq, ok := r[path]
+		if !ok {
+			res.OnlyLeft = append(res.OnlyLeft, entry(lreq))
+			continue
+		}
+		if lreq.Mod.Version == rreq.Mod.Version {
+			res.Shared++
+			continue
+		}
+
+		d := Divergence{Path: path, Left: lreq.Mod.Version, Right: rreq.Mod.Version, Newer: "right"}
+		older, newer := d.Left, d.Right
+		if semver.Compare(d.Left, d.Right) > 0 {
+			d.Newer = "left"
+			older, newer = newer, older
+		}
+		d.UpdateType = versions.Classify(older, newer)
+		res.Diverged = append(res.Diverged, d)
+	}
+	for path, rreq := range r {
+		if _, ok := l[path]; !ok {
+			res.OnlyRight = append(res.OnlyRight, entry(rreq))
+		}
+	}
+
+	sort.Slice(res.Diverged, func(i, j int) bool { return res.Diverged[i].Path < res.Diverged[j].Path })
+	sortEntries(res.OnlyLeft)
+	sortEntries(res.OnlyRight)
+	return res
+}
+
+func index(reqs []*modfile.Require) map[string]*modfile.Require {
+	m := make(map[string]*modfile.Require, len(reqs))
+	for _, req := range reqs {
+		if prev, ok := m[req.Mod.Path]; ok && semver.Compare(prev.Mod.Version, req.Mod.Version) >= 0 {
+			continue
+		}
+		m[req.Mod.Path] = req
+	}
+	return m
+}
+
+func entry(req *modfile.Require) Entry {
+	return Entry{Path: req.Mod.Path, Version: req.Mod.Version, Indirect: req.Indirect}
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+}
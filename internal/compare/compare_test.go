@@ -0,0 +1,61 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+func req(path, version string, indirect bool) *modfile.Require {
+	return &modfile.Require{Mod: module.Version{Path: path, Version: version}, Indirect: indirect}
+}
+
+func TestDiff(t *testing.T) {
+	left := []*modfile.Require{
+		req("example.com/same", "v1.0.0", false),
+		req("example.com/major", "v1.4.0", false),
+		req("example.com/patch", "v0.3.2", true),
+		req("example.com/left", "v1.0.0", true),
+	}
+	right := []*modfile.Require{
+		req("example.com/right", "v2.1.0", false),
+		req("example.com/patch", "v0.3.1", true),
+		req("example.com/major", "v2.0.0", false),
+		req("example.com/same", "v1.0.0", true),
+	}
+
+	got := Diff(left, right)
+
+	want := Result{
+		Diverged: []Divergence{
+			{Path: "example.com/major", Left: "v1.4.0", Right: "v2.0.0", Newer: "right", UpdateType: "major"},
+			{Path: "example.com/patch", Left: "v0.3.2", Right: "v0.3.1", Newer: "left", UpdateType: "patch"},
+		},
+		OnlyLeft:  []Entry{{Path: "example.com/left", Version: "v1.0.0", Indirect: true}},
+		OnlyRight: []Entry{{Path: "example.com/right", Version: "v2.1.0"}},
+		Shared:    1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v\nwant %+v", got, want)
+	}
+	if got.Identical() {
+		t.Error("Identical() = true, want false")
+	}
+}
+
+func TestDiff_DuplicateRequires(t *testing.T) {
+	left := []*modfile.Require{
+		req("example.com/dup", "v1.2.0", false),
+		req("example.com/dup", "v1.1.0", false),
+	}
+	right := []*modfile.Require{
+		req("example.com/dup", "v1.2.0", false),
+	}
+
+	got := Diff(left, right)
+	if !got.Identical() || got.Shared != 1 {
+		t.Errorf("Diff() = %+v, want one shared requirement", got)
+	}
+}
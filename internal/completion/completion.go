@@ -0,0 +1,223 @@
+// Package completion provides dynamic shell completion for module paths
+// read from go.mod and versions fetched from the module proxy.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// proxyTimeout bounds how long a completion waits for the proxy, so a slow
+// network never hangs the shell
+const proxyTimeout = 3 * time.Second
+
+// Modules completes the first argument with the module paths required by
+// the go.mod the command works on, as --mod selects it
+func Modules() cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return modulePaths(modflag.Path(cmd), toComplete, ""), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ModuleVersions completes a <module>@<version> argument: module paths from
+// the command's go.mod first, then the versions the proxy knows for the
+// chosen module
+func ModuleVersions() cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		modPath := modflag.Path(cmd)
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if !strings.Contains(toComplete, "@") {
+			return modulePaths(modPath, toComplete, "@"), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		}
+		return moduleVersions(cmd, modPath, toComplete)
+	}
+}
+
+// OptionalModuleVersions completes any number of <module>[@<version>]
+// arguments: the module paths from the command's go.mod not given yet, then
+// versions once an argument has an @
+func OptionalModuleVersions() cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		modPath := modflag.Path(cmd)
+		if strings.Contains(toComplete, "@") {
+			return moduleVersions(cmd, modPath, toComplete)
+		}
+
+		given := make(map[string]bool, len(args))
+		for _, arg := range args {
+			module, _, _ := strings.Cut(arg, "@")
+			given[module] = true
+		}
+		var completions []cobra.Completion
+		for _, c := range modulePaths(modPath, toComplete, "") {
+			if module, _, _ := strings.Cut(c, "\t"); !given[module] {
+				completions = append(completions, c)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// moduleVersions completes the version part of a <module>@<version> argument
+func moduleVersions(cmd *cobra.Command, modPath, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	module, prefix, _ := strings.Cut(toComplete, "@")
+
+	cfg, err := config.LoadIn(filepath.Dir(modPath))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, proxyTimeout)
+	defer cancel()
+
+	current := ""
+	if parser, err := modfile.NewParser(modPath); err == nil {
+		if req := parser.FindRequire(module); req != nil {
+			current = req.Mod.Version
+		}
+	}
+
+	completions, err := versions(ctx, cfg.NewProxyClient(), module, prefix, current)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// Prefixes completes a flag value with the path prefixes, such as
+// golang.org/x, shared by more than one module required by the command's
+// go.mod
+func Prefixes() cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		parser, err := modfile.NewParser(modflag.Path(cmd))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		requires := parser.AllRequires()
+
+		// Candidates are the parents of required modules below the host, so
+		// github.com alone isn't offered
+		counts := make(map[string]int)
+		for _, req := range requires {
+			path := req.Mod.Path
+			for i := strings.Index(path, "/") + 1; i > 0; i++ {
+				next := strings.Index(path[i:], "/")
+				if next < 0 {
+					break
+				}
+				i += next
+				counts[path[:i]] = 0
+			}
+		}
+
+		var prefixes []string
+		for prefix := range counts {
+			for _, req := range requires {
+				if pattern.Match(prefix+"/...", req.Mod.Path) {
+					counts[prefix]++
+				}
+			}
+			if counts[prefix] > 1 && strings.HasPrefix(prefix, toComplete) {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		sort.Strings(prefixes)
+
+		completions := make([]cobra.Completion, len(prefixes))
+		for i, prefix := range prefixes {
+			completions[i] = cobra.CompletionWithDesc(prefix, fmt.Sprintf("%d modules", counts[prefix]))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// modulePaths returns the required modules starting with toComplete, each followed by suffix
+func modulePaths(modPath, toComplete, suffix string) []cobra.Completion {
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		return nil
+	}
+
+	var completions []cobra.Completion
+	for _, req := range parser.AllRequires() {
+		if !strings.HasPrefix(req.Mod.Path, toComplete) {
+			continue
+		}
+		desc := req.Mod.Version
+		if req.Indirect {
+			desc += " (indirect)"
+		}
+		completions = append(completions, cobra.CompletionWithDesc(req.Mod.Path+suffix, desc))
+	}
+	return completions
+}
+
+// versions returns module@version completions matching prefix, newest first.
+// Retracted versions are left out.
+func versions(ctx context.Context, client *proxy.Client, module, prefix, current string) ([]cobra.Completion, error) {
+	list, err := client.Versions(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list is shared with the proxy cache, so sort a copy
+	list = append([]string(nil), list...)
+	semver.Sort(list)
+	list = withoutRetracted(ctx, client, module, list)
+
+	var completions []cobra.Completion
+	for i := len(list) - 1; i >= 0; i-- {
+		v := list[i]
+		if !semver.IsValid(v) || !strings.HasPrefix(v, prefix) {
+			continue
+		}
+		choice := module + "@" + v
+		if v == current {
+			completions = append(completions, cobra.CompletionWithDesc(choice, "current"))
+		} else {
+			completions = append(completions, choice)
+		}
+	}
+	return completions, nil
+}
+
+// withoutRetracted drops the versions retracted in the module's latest
+// go.mod. The list is returned unchanged when that go.mod can't be read.
+func withoutRetracted(ctx context.Context, client *proxy.Client, module string, list []string) []string {
+	latest, err := client.Latest(ctx, module)
+	if err != nil {
+		return list
+	}
+	data, err := client.GetModFile(ctx, module, latest.Version)
+	if err != nil {
+		return list
+	}
+	status, err := modfile.ParseModuleStatus(data)
+	if err != nil {
+		return list
+	}
+	return status.Unretracted(list)
+}
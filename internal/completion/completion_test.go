@@ -0,0 +1,211 @@
+package completion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/omarshaarawi/gx/internal/modflag"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/spf13/cobra"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.24
+
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/mod v0.14.0
+)
+`
+
+func writeGoMod(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(path, []byte(testGoMod), 0o644); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+	return path
+}
+
+// withMod returns a command whose --mod names modPath
+func withMod(t *testing.T, modPath string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	modflag.Add(cmd)
+	if err := cmd.ParseFlags([]string{"--mod", modPath}); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestModules(t *testing.T) {
+	modPath := writeGoMod(t)
+	complete := Modules()
+	cmd := withMod(t, modPath)
+
+	got, directive := complete(cmd, nil, "github.com/spf13/")
+	want := []cobra.Completion{
+		"github.com/spf13/cobra\tv1.8.0",
+		"github.com/spf13/pflag\tv1.0.5 (indirect)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Modules() = %q, want %q", got, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	if got, _ := complete(cmd, []string{"golang.org/x/mod"}, ""); got != nil {
+		t.Errorf("second argument completed to %q, want nothing", got)
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	goMod := `module example.com/app
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.30.0
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.58.0
+	github.com/aws/aws-sdk-go-v2/service/sqs v1.34.0
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/mod v0.14.0
+	golang.org/x/net v0.30.0
+)
+`
+	if err := os.WriteFile(path, []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	complete := Prefixes()
+	cmd := withMod(t, path)
+
+	got, _ := complete(cmd, nil, "")
+	want := []cobra.Completion{
+		"github.com/aws\t3 modules",
+		"github.com/aws/aws-sdk-go-v2\t3 modules",
+		"github.com/aws/aws-sdk-go-v2/service\t2 modules",
+		"golang.org/x\t2 modules",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Prefixes() = %q, want %q", got, want)
+	}
+
+	got, _ = complete(cmd, nil, "golang")
+	if want := []cobra.Completion{"golang.org/x\t2 modules"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Prefixes(golang) = %q, want %q", got, want)
+	}
+}
+
+func TestModules_MissingGoMod(t *testing.T) {
+	complete := Modules()
+	if got, _ := complete(withMod(t, t.TempDir()), nil, ""); got != nil {
+		t.Errorf("Modules() without go.mod = %q, want nothing", got)
+	}
+}
+
+func TestModuleVersions_ModulePart(t *testing.T) {
+	modPath := writeGoMod(t)
+	complete := ModuleVersions()
+	cmd := withMod(t, modPath)
+
+	got, directive := complete(cmd, nil, "golang.org/")
+	want := []cobra.Completion{"golang.org/x/mod@\tv0.14.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModuleVersions() = %q, want %q", got, want)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Error("module completion should not add a space after '@'")
+	}
+}
+
+func TestOptionalModuleVersions(t *testing.T) {
+	modPath := writeGoMod(t)
+	complete := OptionalModuleVersions()
+	cmd := withMod(t, modPath)
+
+	got, directive := complete(cmd, nil, "github.com/spf13/")
+	want := []cobra.Completion{
+		"github.com/spf13/cobra\tv1.8.0",
+		"github.com/spf13/pflag\tv1.0.5 (indirect)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OptionalModuleVersions() = %q, want %q", got, want)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace != 0 {
+		t.Error("the version is optional, so a module completion should end the argument")
+	}
+
+	got, _ = complete(cmd, []string{"github.com/spf13/cobra@v1.8.1"}, "github.com/spf13/")
+	if want := []cobra.Completion{"github.com/spf13/pflag\tv1.0.5 (indirect)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OptionalModuleVersions() after cobra = %q, want %q", got, want)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/github.com/spf13/cobra/@v/list" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "v1.7.0\nv1.10.0\nv1.8.0\nv1.8.1\n")
+	}))
+	defer server.Close()
+
+	client := proxy.NewClient(server.URL)
+
+	got, err := versions(context.Background(), client, "github.com/spf13/cobra", "v1.8", "v1.8.0")
+	if err != nil {
+		t.Fatalf("versions() error: %v", err)
+	}
+	want := []cobra.Completion{
+		"github.com/spf13/cobra@v1.8.1",
+		"github.com/spf13/cobra@v1.8.0\tcurrent",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("versions() = %q, want %q", got, want)
+	}
+
+	all, err := versions(context.Background(), client, "github.com/spf13/cobra", "", "")
+	if err != nil {
+		t.Fatalf("versions() error: %v", err)
+	}
+	if len(all) != 4 || all[0] != "github.com/spf13/cobra@v1.10.0" {
+		t.Errorf("versions() = %q, want newest first", all)
+	}
+}
+
+func TestVersions_Retracted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/lib/@v/list":
+			fmt.Fprint(w, "v1.0.0\nv1.1.0\nv1.2.0\nv1.2.1\n")
+		case "/example.com/lib/@latest":
+			fmt.Fprint(w, `{"Version":"v1.2.1"}`)
+		case "/example.com/lib/@v/v1.2.1.mod":
+			fmt.Fprint(w, "module example.com/lib\n\nretract (\n\tv1.2.0 // broken\n\tv1.1.0\n)\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	got, err := versions(context.Background(), proxy.NewClient(server.URL), "example.com/lib", "", "v1.0.0")
+	if err != nil {
+		t.Fatalf("versions() error: %v", err)
+	}
+	want := []cobra.Completion{
+		"example.com/lib@v1.2.1",
+		"example.com/lib@v1.0.0\tcurrent",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("versions() = %q, want %q", got, want)
+	}
+}
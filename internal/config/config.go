@@ -4,32 +4,192 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/omarshaarawi/gx/internal/github"
+	"github.com/omarshaarawi/gx/internal/gitlab"
+	"github.com/omarshaarawi/gx/internal/migration"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"github.com/omarshaarawi/gx/internal/vcs"
+	"github.com/omarshaarawi/gx/internal/versions"
 	"gopkg.in/yaml.v3"
 )
 
+// ProjectFile is the name of the project-local config file
+const ProjectFile = ".gx.yaml"
+
 type Config struct {
 	ProxyURL       string        `yaml:"proxy_url"`
 	Timeout        time.Duration `yaml:"timeout"`
+	LookupTimeout  time.Duration `yaml:"lookup_timeout"`
 	CacheTTL       time.Duration `yaml:"cache_ttl"`
 	MaxConcurrent  int           `yaml:"max_concurrent"`
 	DefaultVerbose bool          `yaml:"default_verbose"`
 	DefaultQuiet   bool          `yaml:"default_quiet"`
+
+	// MaxCacheAge lets gx outdated reuse latest versions looked up by earlier
+	// runs up to this age; zero always asks the proxy
+	MaxCacheAge time.Duration `yaml:"max_cache_age"`
+
+	// VulnDB is the vulnerability database govulncheck reads: a URL, such as
+	// an internal mirror, or a directory written by gx vulndb mirror
+	VulnDB string `yaml:"vulndb"`
+
+	// CommandTimeout limits how long a command may run; zero means no limit
+	CommandTimeout time.Duration `yaml:"command_timeout"`
+	// CommandTimeouts overrides CommandTimeout per command ("audit", "export nix")
+	CommandTimeouts map[string]time.Duration `yaml:"command_timeouts"`
+
+	// DateFormat is a preset (iso, us, eu, uk, long, rfc3339) or a Go time layout
+	DateFormat string `yaml:"date_format"`
+	// Locale controls number formatting, defaulting to LC_ALL/LC_NUMERIC/LANG
+	Locale string `yaml:"locale"`
+	// Theme picks the colors for update types and severities (default, colorblind)
+	Theme string `yaml:"theme"`
+	// Keys binds actions of the interactive views to other keys, such as
+	// toggle: [x, space]
+	Keys map[string][]string `yaml:"keys"`
+	// Mouse lets the interactive views scroll with the wheel and toggle
+	// rows by clicking; false leaves the mouse to the terminal, for selecting text
+	Mouse bool `yaml:"mouse"`
+	// Accessible replaces spinners and full-screen views with plain lines of
+	// text and prompts, for screen readers
+	Accessible bool `yaml:"accessible"`
+
+	// Owners maps module patterns to the team that owns them
+	Owners map[string]string `yaml:"owners"`
+
+	// Ignore lists modules left out of outdated reports
+	Ignore []ModuleRule `yaml:"ignore"`
+
+	// Pinned lists modules that gx update never changes
+	Pinned []string `yaml:"pinned"`
+
+	// Constraints maps module patterns to the versions gx update may move
+	// them to, such as "<0.20.0"; gx outdated shows the newest allowed one
+	Constraints map[string]versions.Constraint `yaml:"constraints"`
+
+	// UpdateScope limits gx update to direct or indirect requirements unless
+	// --direct-only or --indirect-only say otherwise; all or empty updates both
+	UpdateScope string `yaml:"update_scope"`
+
+	// Families maps a name to module patterns that must share a version;
+	// gx update moves their modules together
+	Families map[string][]string `yaml:"families"`
+
+	// Replacements adds to the modules gx knows to have moved, for gx migrate
+	// and the suggestions of gx stats and gx deprecations, by old module path
+	Replacements map[string]migration.Migration `yaml:"replacements"`
+
+	// Policy holds the rules checked by gx policy
+	Policy Policy `yaml:"policy"`
+
+	// GitHub configures the GitHub API gx update --pr opens pull requests with
+	GitHub GitHub `yaml:"github"`
+	// GitLab configures the GitLab instance gx update --pr opens merge
+	// requests on, when origin is there
+	GitLab GitLab `yaml:"gitlab"`
+}
+
+// GitHub holds the GitHub API settings. GITHUB_TOKEN or GH_TOKEN take
+// precedence over Token, and GITHUB_API_URL over APIURL.
+type GitHub struct {
+	Token  string `yaml:"token"`
+	APIURL string `yaml:"api_url"` // for GitHub Enterprise; empty for api.github.com
+}
+
+// GitLab holds the GitLab API settings. GITLAB_TOKEN takes precedence over
+// Token, and CI_SERVER_URL over URL.
+type GitLab struct {
+	Token string `yaml:"token"`
+	URL   string `yaml:"url"` // a self-hosted instance, such as https://gitlab.example.com; empty for gitlab.com
+}
+
+// Policy describes dependency rules enforced by gx policy
+type Policy struct {
+	// Banned lists modules that must not appear in the module graph
+	Banned []ModuleRule `yaml:"banned"`
+	// MaxAgeDays flags dependencies whose installed version is older than this
+	// many days while a newer version is available
+	MaxAgeDays int `yaml:"max_age_days"`
+	// DisallowedLicenses lists SPDX identifiers that dependencies must not use
+	DisallowedLicenses []string `yaml:"disallowed_licenses"`
+	// MinVersions maps module patterns to the lowest acceptable version
+	MinVersions map[string]string `yaml:"min_versions"`
+}
+
+// IsEmpty reports whether no policy rules are configured
+func (p Policy) IsEmpty() bool {
+	return len(p.Banned) == 0 && p.MaxAgeDays == 0 &&
+		len(p.DisallowedLicenses) == 0 && len(p.MinVersions) == 0
+}
+
+// ModuleRule matches modules by pattern, with an optional reason
+type ModuleRule struct {
+	Module string `yaml:"module"`
+	Reason string `yaml:"reason"`
+}
+
+// UnmarshalYAML accepts either a plain pattern string or a module/reason mapping
+func (r *ModuleRule) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		r.Module = node.Value
+		return nil
+	}
+
+	type plain ModuleRule
+	return node.Decode((*plain)(r))
 }
 
 var defaults = Config{
 	ProxyURL:      "https://proxy.golang.org",
 	Timeout:       30 * time.Second,
+	LookupTimeout: 10 * time.Second,
 	CacheTTL:      5 * time.Minute,
 	MaxConcurrent: 10,
+	Mouse:         true,
+}
+
+// commandTimeouts are the built-in limits for commands that run until they
+// are stopped, which CommandTimeout doesn't apply to
+var commandTimeouts = map[string]time.Duration{
+	"watch":    0,
+	"lsp-lite": 0,
+}
+
+// GlobalPath returns the preferred location of the user config file
+func GlobalPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "gx", "config.yaml")
 }
 
+// projectDir is the module directory the project-local .gx.yaml is read
+// from, empty for the current directory
+var projectDir string
+
+// SetProjectDir sets the module directory whose .gx.yaml Load overlays,
+// the one --mod points at
+func SetProjectDir(dir string) {
+	projectDir = dir
+}
+
+// ProjectPath returns the path of the project-local .gx.yaml Load reads
+func ProjectPath() string {
+	return filepath.Join(projectDir, ProjectFile)
+}
+
+// Load reads the user config, then overlays the project-local .gx.yaml
 func Load() (*Config, error) {
+	return LoadIn(projectDir)
+}
+
+// LoadIn is Load with the project-local .gx.yaml read from dir
+func LoadIn(dir string) (*Config, error) {
 	cfg := defaults
 
 	paths := []string{
-		filepath.Join(os.Getenv("HOME"), ".config", "gx", "config.yaml"),
+		GlobalPath(),
 		filepath.Join(os.Getenv("HOME"), ".gx.yaml"),
 	}
 
@@ -44,11 +204,23 @@ func Load() (*Config, error) {
 		break
 	}
 
+	if projectPath, err := filepath.Abs(filepath.Join(dir, ProjectFile)); err == nil && !isHomeConfig(projectPath) {
+		if data, err := os.ReadFile(projectPath); err == nil {
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	applyEnvOverrides(&cfg)
 
 	return &cfg, nil
 }
 
+func isHomeConfig(path string) bool {
+	return path == filepath.Join(os.Getenv("HOME"), ".gx.yaml")
+}
+
 func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("GX_PROXY"); v != "" {
 		cfg.ProxyURL = v
@@ -58,11 +230,42 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.Timeout = d
 		}
 	}
+	if v := os.Getenv("GX_LOOKUP_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.LookupTimeout = d
+		}
+	}
+	if v := os.Getenv("GX_COMMAND_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.CommandTimeout = d
+		}
+	}
 	if v := os.Getenv("GX_CACHE_TTL"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			cfg.CacheTTL = d
 		}
 	}
+	if v := os.Getenv("GOVULNDB"); v != "" {
+		cfg.VulnDB = v
+	}
+	if v := os.Getenv("GX_THEME"); v != "" {
+		cfg.Theme = v
+	}
+	if v := os.Getenv("GX_MOUSE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Mouse = b
+		}
+	}
+	if v := os.Getenv("GX_ACCESSIBLE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Accessible = b
+		}
+	}
+	if v := os.Getenv("GX_MAX_CACHE_AGE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.MaxCacheAge = d
+		}
+	}
 	if v := os.Getenv("GX_MAX_CONCURRENT"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			cfg.MaxConcurrent = n
@@ -73,3 +276,138 @@ func applyEnvOverrides(cfg *Config) {
 func Default() *Config {
 	return &defaults
 }
+
+// NewProxyClient creates a proxy client using the configured proxy settings
+func (c *Config) NewProxyClient() *proxy.Client {
+	client := proxy.NewClient(c.ProxyURL).
+		WithTimeout(c.Timeout).
+		WithLookupTimeout(c.LookupTimeout).
+		WithMaxConcurrent(c.MaxConcurrent).
+		WithCacheTTL(c.CacheTTL)
+
+	// Private modules are resolved from git, like the go command does
+	if patterns := vcs.NoProxyPatterns(); patterns != "" {
+		client.WithPrivate(func(modulePath string) bool {
+			return vcs.IsPrivate(patterns, modulePath)
+		}, vcs.NewResolver())
+	}
+	return client
+}
+
+// NewGitHubClient creates a GitHub API client, authenticated with the token
+// from the environment or else the config
+func (c *Config) NewGitHubClient() *github.Client {
+	apiURL := c.GitHub.APIURL
+	if v := os.Getenv("GITHUB_API_URL"); v != "" {
+		apiURL = v
+	}
+	client := github.NewClient(apiURL)
+	if !client.Authenticated() && c.GitHub.Token != "" {
+		client.WithToken(c.GitHub.Token)
+	}
+	return client
+}
+
+// NewGitHubModulesClient creates a client for github.com, where modules with
+// github.com/ paths are hosted, for their releases and tags. It takes the
+// token NewGitHubClient does unless that one is for GitHub Enterprise, which
+// github.com wouldn't accept.
+func (c *Config) NewGitHubModulesClient() *github.Client {
+	client := c.NewGitHubClient()
+	if client.Host() == "github.com" {
+		return client
+	}
+	return github.NewClient("").WithToken("")
+}
+
+// NewGitLabClient creates a GitLab API client for the configured instance,
+// authenticated with the token from the environment or else the config
+func (c *Config) NewGitLabClient() *gitlab.Client {
+	baseURL := c.GitLab.URL
+	if v := os.Getenv("CI_SERVER_URL"); v != "" {
+		baseURL = v
+	}
+	client := gitlab.NewClient(baseURL)
+	if !client.Authenticated() && c.GitLab.Token != "" {
+		client.WithToken(c.GitLab.Token)
+	}
+	return client
+}
+
+// CommandTimeoutFor returns the time limit for a command, named by its path
+// below gx ("audit image"). Subcommands without their own entry use their
+// parent's limit, and configured entries take precedence over built-in ones.
+func (c *Config) CommandTimeoutFor(command string) time.Duration {
+	for _, limits := range []map[string]time.Duration{c.CommandTimeouts, commandTimeouts} {
+		for name := command; name != ""; name = parentCommand(name) {
+			if d, ok := limits[name]; ok {
+				return d
+			}
+		}
+	}
+	return c.CommandTimeout
+}
+
+func parentCommand(name string) string {
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// OwnerFor returns the owning team for a module, preferring the most specific pattern
+func (c *Config) OwnerFor(modulePath string) string {
+	owner, best := "", ""
+	found := false
+	for p, team := range c.Owners {
+		if pattern.Match(p, modulePath) && (!found || pattern.MoreSpecific(p, best)) {
+			owner, best, found = team, p, true
+		}
+	}
+	return owner
+}
+
+// IgnoreRuleFor returns the ignore rule matching a module, if any, preferring
+// the most specific pattern so its reason is the one reported
+func (c *Config) IgnoreRuleFor(modulePath string) *ModuleRule {
+	var rule *ModuleRule
+	for i := range c.Ignore {
+		r := &c.Ignore[i]
+		if pattern.Match(r.Module, modulePath) && (rule == nil || pattern.MoreSpecific(r.Module, rule.Module)) {
+			rule = r
+		}
+	}
+	return rule
+}
+
+// ConstraintFor returns the version constraint for a module, preferring the
+// most specific pattern
+func (c *Config) ConstraintFor(modulePath string) (versions.Constraint, bool) {
+	var constraint versions.Constraint
+	best, found := "", false
+	for p, vc := range c.Constraints {
+		if pattern.Match(p, modulePath) && (!found || pattern.MoreSpecific(p, best)) {
+			constraint, best, found = vc, p, true
+		}
+	}
+	return constraint, found
+}
+
+// FamilyFor returns the family a module belongs to, or "" for none. A module
+// matched by several families gets the one whose pattern is most specific.
+func (c *Config) FamilyFor(modulePath string) string {
+	family, best := "", ""
+	for name, patterns := range c.Families {
+		for _, p := range patterns {
+			if pattern.Match(p, modulePath) && (family == "" || pattern.MoreSpecific(p, best) || (p == best && name < family)) {
+				family, best = name, p
+			}
+		}
+	}
+	return family
+}
+
+// IsPinned reports whether a module is pinned against updates
+func (c *Config) IsPinned(modulePath string) bool {
+	return pattern.MatchAny(c.Pinned, modulePath)
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad_ProjectOverridesGlobal(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+
+	global := "proxy_url: https://global.example.com\nmax_concurrent: 4\n"
+	if err := os.MkdirAll(filepath.Dir(GlobalPath()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GlobalPath(), []byte(global), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := `proxy_url: https://project.example.com
+mouse: false
+update_scope: direct
+vulndb: https://vuln.corp.example
+ignore:
+  - golang.org/x/exp
+  - module: k8s.io/*
+    reason: pinned to cluster version
+pinned:
+  - github.com/legacy/lib
+families:
+  kubernetes: [k8s.io/api, k8s.io/apimachinery, k8s.io/client-go]
+  aws: ["github.com/aws/aws-sdk-go-v2/..."]
+constraints:
+  golang.org/x/tools: <0.20.0
+  golang.org/x/*: ">=0.10, <1"
+policy:
+  banned:
+    - github.com/pkg/errors
+    - module: github.com/golang/protobuf
+      reason: use google.golang.org/protobuf
+  max_age_days: 365
+  disallowed_licenses: [GPL-3.0, AGPL-3.0]
+  min_versions:
+    golang.org/x/crypto: v0.31.0
+`
+	if err := os.WriteFile(ProjectFile, []byte(local), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if cfg.ProxyURL != "https://project.example.com" {
+		t.Errorf("ProxyURL = %q, want project value", cfg.ProxyURL)
+	}
+	if cfg.MaxConcurrent != 4 {
+		t.Errorf("MaxConcurrent = %d, want global value 4", cfg.MaxConcurrent)
+	}
+	if cfg.Mouse {
+		t.Error("Mouse = true, want the project's false")
+	}
+	if !Default().Mouse {
+		t.Error("mouse support should be on by default")
+	}
+	if cfg.UpdateScope != "direct" {
+		t.Errorf("UpdateScope = %q, want direct", cfg.UpdateScope)
+	}
+	if cfg.VulnDB != "https://vuln.corp.example" {
+		t.Errorf("VulnDB = %q, want the project's mirror", cfg.VulnDB)
+	}
+
+	if rule := cfg.IgnoreRuleFor("golang.org/x/exp"); rule == nil || rule.Reason != "" {
+		t.Errorf("IgnoreRuleFor(golang.org/x/exp) = %+v", rule)
+	}
+	if rule := cfg.IgnoreRuleFor("k8s.io/api"); rule == nil || rule.Reason != "pinned to cluster version" {
+		t.Errorf("IgnoreRuleFor(k8s.io/api) = %+v", rule)
+	}
+	if cfg.IgnoreRuleFor("github.com/spf13/cobra") != nil {
+		t.Error("IgnoreRuleFor(cobra) should be nil")
+	}
+	if !cfg.IsPinned("github.com/legacy/lib") {
+		t.Error("IsPinned(github.com/legacy/lib) = false")
+	}
+	if got := cfg.FamilyFor("k8s.io/client-go"); got != "kubernetes" {
+		t.Errorf("FamilyFor(k8s.io/client-go) = %q, want kubernetes", got)
+	}
+	if got := cfg.FamilyFor("github.com/aws/aws-sdk-go-v2/service/s3"); got != "aws" {
+		t.Errorf("FamilyFor(s3) = %q, want aws", got)
+	}
+	if got := cfg.FamilyFor("k8s.io/klog/v2"); got != "" {
+		t.Errorf("FamilyFor(k8s.io/klog/v2) = %q, want none", got)
+	}
+	if c, ok := cfg.ConstraintFor("golang.org/x/tools"); !ok || c.String() != "<0.20.0" {
+		t.Errorf("ConstraintFor(golang.org/x/tools) = %v, %v", c, ok)
+	}
+	if c, ok := cfg.ConstraintFor("golang.org/x/net"); !ok || c.Allows("v1.0.0") {
+		t.Errorf("ConstraintFor(golang.org/x/net) = %v, %v", c, ok)
+	}
+	if _, ok := cfg.ConstraintFor("github.com/spf13/cobra"); ok {
+		t.Error("ConstraintFor(cobra) should find no constraint")
+	}
+
+	policy := cfg.Policy
+	if len(policy.Banned) != 2 || policy.Banned[1].Reason != "use google.golang.org/protobuf" {
+		t.Errorf("Policy.Banned = %+v", policy.Banned)
+	}
+	if policy.MaxAgeDays != 365 || len(policy.DisallowedLicenses) != 2 {
+		t.Errorf("Policy = %+v", policy)
+	}
+	if policy.MinVersions["golang.org/x/crypto"] != "v0.31.0" {
+		t.Errorf("Policy.MinVersions = %v", policy.MinVersions)
+	}
+	if policy.IsEmpty() {
+		t.Error("Policy.IsEmpty() = true")
+	}
+}
+
+func TestLoadIn_ModuleDirectory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	module := t.TempDir()
+
+	if err := os.WriteFile(ProjectFile, []byte("proxy_url: https://cwd.example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, ProjectFile), []byte("proxy_url: https://module.example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadIn(module)
+	if err != nil {
+		t.Fatalf("LoadIn() error: %v", err)
+	}
+	if cfg.ProxyURL != "https://module.example.com" {
+		t.Errorf("ProxyURL = %q, want the module's .gx.yaml", cfg.ProxyURL)
+	}
+}
+
+func TestConfig_IgnoreRuleFor_MostSpecific(t *testing.T) {
+	cfg := &Config{Ignore: []ModuleRule{
+		{Module: "k8s.io/*", Reason: "pinned to the cluster version"},
+		{Module: "k8s.io/klog/v2", Reason: "logging follows upstream"},
+	}}
+
+	if rule := cfg.IgnoreRuleFor("k8s.io/klog/v2"); rule == nil || rule.Reason != "logging follows upstream" {
+		t.Errorf("IgnoreRuleFor(k8s.io/klog/v2) = %+v, want the exact rule", rule)
+	}
+	if rule := cfg.IgnoreRuleFor("k8s.io/api"); rule == nil || rule.Reason != "pinned to the cluster version" {
+		t.Errorf("IgnoreRuleFor(k8s.io/api) = %+v, want the wildcard rule", rule)
+	}
+}
+
+func TestConfig_OwnerFor(t *testing.T) {
+	cfg := &Config{Owners: map[string]string{
+		"github.com/acme/*":         "platform",
+		"github.com/acme/payments*": "payments",
+	}}
+
+	if got := cfg.OwnerFor("github.com/acme/payments-sdk"); got != "payments" {
+		t.Errorf("OwnerFor() = %q, want payments", got)
+	}
+	if got := cfg.OwnerFor("github.com/acme/logging"); got != "platform" {
+		t.Errorf("OwnerFor() = %q, want platform", got)
+	}
+	if got := cfg.OwnerFor("github.com/other/lib"); got != "" {
+		t.Errorf("OwnerFor() = %q, want empty", got)
+	}
+}
+
+func TestConfig_CommandTimeoutFor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+
+	local := `command_timeout: 5m
+command_timeouts:
+  audit: 15m
+  export nix: 20m
+`
+	if err := os.WriteFile(ProjectFile, []byte(local), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	tests := []struct {
+		command string
+		want    time.Duration
+	}{
+		{"outdated", 5 * time.Minute},
+		{"audit", 15 * time.Minute},
+		{"audit image", 15 * time.Minute},
+		{"export nix", 20 * time.Minute},
+		{"export bazel", 5 * time.Minute},
+		{"verify-build", 5 * time.Minute},
+		{"watch", 0},
+	}
+	for _, tt := range tests {
+		if got := cfg.CommandTimeoutFor(tt.command); got != tt.want {
+			t.Errorf("CommandTimeoutFor(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+
+	// Interactive commands and go test could run for any time, so there's no
+	// limit unless one is configured
+	if got := Default().CommandTimeoutFor("update"); got != 0 {
+		t.Errorf("default CommandTimeoutFor(update) = %v, want no limit", got)
+	}
+}
+
+func TestConfig_NewGitHubClient(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	cfg := &Config{}
+	if cfg.NewGitHubClient().Authenticated() {
+		t.Error("client without a token is authenticated")
+	}
+
+	cfg.GitHub.Token = "from-config"
+	if !cfg.NewGitHubClient().Authenticated() {
+		t.Error("client ignored github.token")
+	}
+}
+
+func TestConfig_NewGitHubModulesClient(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_API_URL", "")
+
+	cfg := &Config{GitHub: GitHub{Token: "from-config"}}
+	if client := cfg.NewGitHubModulesClient(); client.Host() != "github.com" || !client.Authenticated() {
+		t.Errorf("client host %q, authenticated %v; want github.com with the token", client.Host(), client.Authenticated())
+	}
+
+	// Modules on github.com aren't looked up on GitHub Enterprise, nor with its token
+	cfg.GitHub.APIURL = "https://github.example.com/api/v3"
+	if client := cfg.NewGitHubModulesClient(); client.Host() != "github.com" || client.Authenticated() {
+		t.Errorf("client host %q, authenticated %v; want github.com without the token", client.Host(), client.Authenticated())
+	}
+}
+
+func TestConfig_NewGitLabClient(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("CI_SERVER_URL", "")
+
+	cfg := &Config{GitLab: GitLab{URL: "https://gitlab.example.com"}}
+	client := cfg.NewGitLabClient()
+	if client.Authenticated() || client.Host() != "gitlab.example.com" {
+		t.Errorf("client host %q, authenticated %v", client.Host(), client.Authenticated())
+	}
+
+	cfg.GitLab.Token = "from-config"
+	if !cfg.NewGitLabClient().Authenticated() {
+		t.Error("client ignored gitlab.token")
+	}
+}
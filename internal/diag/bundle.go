@@ -0,0 +1,45 @@
+package diag
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// File is a file included in a debug bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// WriteBundle writes files as a gzipped tarball under a top-level dir
+// directory. Every file is redacted before it is written.
+func WriteBundle(w io.Writer, dir string, files []File) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now().Truncate(time.Second)
+
+	for _, f := range files {
+		data := []byte(Redact(string(f.Data)))
+		hdr := &tar.Header{
+			Name:    path.Join(dir, f.Name),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("writing %s: %w", f.Name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("writing %s: %w", f.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("finishing bundle: %w", err)
+	}
+	return gz.Close()
+}
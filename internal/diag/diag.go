@@ -0,0 +1,176 @@
+// Package diag keeps a short trail of recent gx activity, the commands that
+// ran and the HTTP requests they made, and packs it together with other
+// diagnostics into a redacted bundle that can be attached to bug reports.
+package diag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+)
+
+// Log files kept in the diagnostics directory
+const (
+	CommandLog = "commands.jsonl"
+	HTTPLog    = "http.jsonl"
+)
+
+// maxEntries is how many entries each log keeps
+const maxEntries = 200
+
+// Dir returns the directory holding the diagnostics logs
+var Dir = func() (string, error) {
+	if dir := os.Getenv("GX_DIAG_DIR"); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "gx", "diag"), nil
+}
+
+// Command records one gx invocation
+type Command struct {
+	Time     time.Time     `json:"time"`
+	Args     []string      `json:"args"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Request records one HTTP request. Headers and bodies are never recorded,
+// and the URL is redacted.
+type Request struct {
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// RecordCommand appends a command to the command log
+func RecordCommand(c Command) error {
+	for i, arg := range c.Args {
+		c.Args[i] = Redact(arg)
+	}
+	c.Error = Redact(c.Error)
+	return appendLog(CommandLog, c)
+}
+
+// Transport is an http.RoundTripper that records the requests it makes.
+// Call Save to append them to the HTTP log.
+type Transport struct {
+	Base http.RoundTripper
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// NewTransport wraps base, or http.DefaultTransport when base is nil
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+
+	entry := Request{
+		Time:     start,
+		Method:   req.Method,
+		URL:      RedactURL(req.URL),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		entry.Error = Redact(err.Error())
+	} else {
+		entry.Status = resp.StatusCode
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, entry)
+	t.mu.Unlock()
+
+	return resp, err
+}
+
+// Save appends the recorded requests to the HTTP log and clears them
+func (t *Transport) Save() error {
+	t.mu.Lock()
+	requests := t.requests
+	t.requests = nil
+	t.mu.Unlock()
+
+	if len(requests) == 0 {
+		return nil
+	}
+	entries := make([]any, len(requests))
+	for i, r := range requests {
+		entries[i] = r
+	}
+	return appendLog(HTTPLog, entries...)
+}
+
+// ReadLog returns the contents of a log file, or nil if it doesn't exist
+func ReadLog(name string) ([]byte, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return data, nil
+}
+
+// appendLog adds JSON lines to a log, keeping the newest maxEntries
+func appendLog(name string, entries ...any) error {
+	dir, err := Dir()
+	if err != nil {
+		return fmt.Errorf("locating diagnostics directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating diagnostics directory: %w", err)
+	}
+
+	existing, err := ReadLog(name)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("encoding %s entry: %w", name, err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > maxEntries {
+		lines = lines[len(lines)-maxEntries:]
+	}
+
+	data := append(bytes.Join(lines, []byte("\n")), '\n')
+	return fsutil.WriteFile(filepath.Join(dir, name), data, 0o600)
+}
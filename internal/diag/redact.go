@@ -0,0 +1,59 @@
+package diag
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secrets removed from diagnostics
+const Redacted = "REDACTED"
+
+// sensitive matches names of variables, settings and query parameters that hold secrets
+const sensitive = `[A-Za-z0-9_.-]*(?i:token|secret|passw(?:or)?d|api[_-]?key|credential|auth|signature|cookie)[A-Za-z0-9_.-]*`
+
+var redactions = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// user:password@ in URLs
+	{regexp.MustCompile(`(://)[^/@\s]+@`), "${1}" + Redacted + "@"},
+	// Authorization header values
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "$1 " + Redacted},
+	// GitHub tokens anywhere
+	{regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`), Redacted},
+	// NAME=value and ?name=value
+	{regexp.MustCompile(`(\b` + sensitive + `=)("[^"]*"|'[^']*'|[^\s&]+)`), "${1}" + Redacted},
+	// name: value
+	{regexp.MustCompile(`(?m)^(\s*` + sensitive + `:[ \t]*)(\S.*)$`), "${1}" + Redacted},
+}
+
+// Redact removes credentials and tokens from text and replaces the home
+// directory with ~
+func Redact(s string) string {
+	for _, r := range redactions {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
+
+// RedactURL returns u without user info and with sensitive query values removed
+func RedactURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	if q := clean.Query(); len(q) > 0 {
+		for key := range q {
+			if sensitiveName.MatchString(key) {
+				q.Set(key, Redacted)
+			}
+		}
+		clean.RawQuery = q.Encode()
+	}
+	return Redact(clean.String())
+}
+
+var sensitiveName = regexp.MustCompile(`^` + sensitive + `$`)
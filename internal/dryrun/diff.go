@@ -0,0 +1,189 @@
+package dryrun
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// contextLines is how many unchanged lines surround each change in a hunk
+const contextLines = 3
+
+// maxDiffCells bounds the table diffLines fills; past it the changed middle
+// of the files is shown as removed and added whole
+const maxDiffCells = 16 << 20
+
+// op is a line of a diff: kept (' '), removed ('-') or added ('+')
+type op struct {
+	kind byte
+	line string
+}
+
+// Diff returns the unified diff of the file at path going from before to
+// after, empty when they're the same. A nil before is a new file and a nil
+// after a removed one.
+func Diff(path string, before, after []byte) string {
+	if (before == nil) == (after == nil) && string(before) == string(after) {
+		return ""
+	}
+
+	// Relative paths get git's a/ and b/ prefixes
+	oldName, newName := "a/"+path, "b/"+path
+	if filepath.IsAbs(filepath.FromSlash(path)) {
+		oldName, newName = path, path
+	}
+	if before == nil {
+		oldName = "/dev/null"
+	}
+	if after == nil {
+		newName = "/dev/null"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	ops := diffLines(splitLines(string(before)), splitLines(string(after)))
+
+	// oldAt and newAt are the line numbers each op starts at on either side
+	oldAt, newAt := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	oldAt[0], newAt[0] = 1, 1
+	for i, o := range ops {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if o.kind != '+' {
+			oldAt[i+1]++
+		}
+		if o.kind != '-' {
+			newAt[i+1]++
+		}
+	}
+
+	for next := 0; next < len(ops); {
+		first := next
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		// Changes close enough to share context go in one hunk
+		end := first + 1
+		for i := end; i < len(ops) && i-end <= 2*contextLines; i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			}
+		}
+
+		from, to := max(first-contextLines, next), min(end+contextLines, len(ops))
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldAt[from], oldAt[to]-oldAt[from]),
+			hunkRange(newAt[from], newAt[to]-newAt[from]))
+		for _, o := range ops[from:to] {
+			b.WriteByte(o.kind)
+			b.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		next = to
+	}
+	return b.String()
+}
+
+// hunkRange formats the start and length of one side of a hunk. An empty
+// side starts at the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits text into lines, each with its newline but the last when
+// the text doesn't end in one
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit from a to b as kept, removed and added
+// lines, from the longest common subsequence of the lines in between their
+// common start and end
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle diffs the lines between the common start and end, removals
+// before additions where they touch
+func diffMiddle(a, b []string) []op {
+	var ops []op
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+		return ops
+	}
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,109 @@
+package dryrun
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	gomod := "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/a/one v1.0.0\n\tgithub.com/b/two v1.2.0\n)\n"
+
+	tests := []struct {
+		name          string
+		before, after string
+		created       bool
+		removed       bool
+		want          string
+	}{
+		{
+			name:   "unchanged",
+			before: gomod,
+			after:  gomod,
+			want:   "",
+		},
+		{
+			name:   "one line changed",
+			before: gomod,
+			after:  strings.Replace(gomod, "v1.2.0", "v1.3.0", 1),
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -4,5 +4,5 @@\n \n require (\n \tgithub.com/a/one v1.0.0\n" +
+				"-\tgithub.com/b/two v1.2.0\n+\tgithub.com/b/two v1.3.0\n )\n",
+		},
+		{
+			name:   "line removed",
+			before: gomod,
+			after:  strings.Replace(gomod, "\tgithub.com/a/one v1.0.0\n", "", 1),
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -3,6 +3,5 @@\n go 1.22\n \n require (\n" +
+				"-\tgithub.com/a/one v1.0.0\n \tgithub.com/b/two v1.2.0\n )\n",
+		},
+		{
+			name:    "new file",
+			after:   "a\nb\n",
+			created: true,
+			want:    "--- /dev/null\n+++ b/go.mod\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "removed file",
+			before:  "a\n",
+			removed: true,
+			want:    "--- a/go.mod\n+++ /dev/null\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:   "no newline at end",
+			before: "a\nb",
+			after:  "a\nc",
+			want:   "--- a/go.mod\n+++ b/go.mod\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := []byte(tt.before)
+			if tt.created {
+				before = nil
+			}
+			after := []byte(tt.after)
+			if tt.removed {
+				after = nil
+			}
+			if got := Diff("go.mod", before, after); got != tt.want {
+				t.Errorf("Diff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Hunks(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d\n", i))
+	}
+	before := strings.Join(lines, "")
+	after := strings.Replace(strings.Replace(before, "line 2\n", "two\n", 1), "line 18\n", "eighteen\n", 1)
+
+	got := Diff("f", []byte(before), []byte(after))
+	hunks := strings.Count(got, "@@ -")
+	if hunks != 2 {
+		t.Fatalf("Diff() has %d hunks, want 2:\n%s", hunks, got)
+	}
+	for _, want := range []string{"@@ -1,5 +1,5 @@", "@@ -15,6 +15,6 @@", "-line 18\n+eighteen\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Diff() missing %q:\n%s", want, got)
+		}
+	}
+
+	// Changes with no more than twice the context between them share a hunk
+	after = strings.Replace(before, "line 2\n", "two\n", 1)
+	after = strings.Replace(after, "line 9\n", "nine\n", 1)
+	if got := Diff("f", []byte(before), []byte(after)); strings.Count(got, "@@ -") != 1 {
+		t.Errorf("Diff() split nearby changes:\n%s", got)
+	}
+}
+
+func TestDiff_AbsolutePath(t *testing.T) {
+	got := Diff("/home/me/.config/gx/config.yaml", []byte("a\n"), []byte("b\n"))
+	want := "--- /home/me/.config/gx/config.yaml\n+++ /home/me/.config/gx/config.yaml\n@@ -1 +1 @@\n-a\n+b\n"
+	if got != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+}
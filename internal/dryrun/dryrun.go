@@ -0,0 +1,171 @@
+// Package dryrun previews what a command that changes the module would do:
+// the files it would change, as unified diffs, and the commands it would run.
+// Every such command takes the same --dry-run flag and prints its plan the
+// same way.
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+	"github.com/omarshaarawi/gx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+const usage = "Print the changes as a diff, and the commands that would run, without making or running them"
+
+var (
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	hunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	fileStyle    = lipgloss.NewStyle().Bold(true)
+)
+
+// AddFlag adds --dry-run to a command that changes files
+func AddFlag(cmd *cobra.Command, dryRun *bool) {
+	cmd.Flags().BoolVar(dryRun, "dry-run", false, usage)
+}
+
+// Plan is what a command would do, recorded instead of done
+type Plan struct {
+	files    []file
+	commands []string
+}
+
+type file struct {
+	path          string
+	before, after []byte
+}
+
+// Change records that the file at path would go from before to after. A nil
+// before creates the file and a nil after removes it.
+func (p *Plan) Change(path string, before, after []byte) {
+	p.files = append(p.files, file{path: filepath.ToSlash(path), before: before, after: after})
+}
+
+// EditMod records the change edit makes to the go.mod at modPath, making it
+// to the parsed go.mod only. A nil edit records the changes already made to
+// the parsed go.mod.
+func (p *Plan) EditMod(modPath string, parser *modfile.Parser, edit func(*modfile.Writer) error) error {
+	before := parser.Data()
+	writer := modfile.NewWriter(parser)
+	if edit != nil {
+		if err := edit(writer); err != nil {
+			return err
+		}
+	}
+	after, err := writer.Format()
+	if err != nil {
+		return err
+	}
+	p.Change(modPath, before, after)
+	return nil
+}
+
+// Restore records the go.mod and go.sum the history transaction would
+// restore next to the go.mod at modPath
+func (p *Plan) Restore(modPath string, tx *history.Transaction) error {
+	restores, err := tx.Restores()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range restores {
+		path := filepath.Join(filepath.Dir(modPath), r.Name)
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading %s: %w", r.Name, err)
+		}
+		p.Change(path, current, r.Data)
+	}
+	return nil
+}
+
+// Tidy records the go mod tidy that would follow a change to the go.mod at
+// modPath
+func (p *Plan) Tidy(modPath string) {
+	p.Run(filepath.Dir(modPath), "go", "mod", "tidy")
+}
+
+// PrintModEdit prints the plan of a command that only edits go.mod: the
+// change edit makes to the go.mod at modPath, as EditMod records it, and
+// the go mod tidy that would follow when tidy is set
+func PrintModEdit(modPath string, parser *modfile.Parser, tidy bool, edit func(*modfile.Writer) error) error {
+	var plan Plan
+	if err := plan.EditMod(modPath, parser, edit); err != nil {
+		return err
+	}
+	if tidy {
+		plan.Tidy(modPath)
+	}
+	plan.Print(os.Stdout)
+	return nil
+}
+
+// Run records that a command would run in dir, such as "go", "mod", "tidy"
+func (p *Plan) Run(dir string, args ...string) {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'$\\") {
+			quoted[i] = strconv.Quote(arg)
+		}
+	}
+	line := strings.Join(quoted, " ")
+	if dir != "" && dir != "." {
+		line = "cd " + dir + " && " + line
+	}
+	p.commands = append(p.commands, line)
+}
+
+// Print writes the diff of each file that would change, then the commands
+// in the order they would run. Files the commands would change are left to
+// them.
+func (p *Plan) Print(w io.Writer) {
+	fmt.Fprintf(w, "\n%s\n", ui.HeaderStyle.Render("📋 Dry run, nothing was changed"))
+
+	changed := false
+	for _, f := range p.files {
+		diff := Diff(f.path, f.before, f.after)
+		if diff == "" {
+			continue
+		}
+		changed = true
+		fmt.Fprintln(w)
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(diff, "\n"), "\n") {
+			fmt.Fprint(w, colorize(strings.TrimSuffix(line, "\n"))+"\n")
+		}
+	}
+	if !changed && len(p.files) > 0 {
+		fmt.Fprintln(w, "\nNo files would change")
+	}
+
+	if len(p.commands) > 0 {
+		fmt.Fprintln(w, "\nWould run:")
+		for _, c := range p.commands {
+			fmt.Fprintf(w, "  $ %s\n", c)
+		}
+	}
+}
+
+// colorize styles a line of a unified diff
+func colorize(line string) string {
+	switch {
+	case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+		return fileStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return hunkStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return addedStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return removedStyle.Render(line)
+	}
+	return line
+}
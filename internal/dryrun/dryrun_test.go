@@ -0,0 +1,133 @@
+package dryrun
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omarshaarawi/gx/internal/history"
+	"github.com/omarshaarawi/gx/internal/modfile"
+)
+
+func TestPlan_Print(t *testing.T) {
+	var p Plan
+	p.Change("go.mod", []byte("module a\n\ngo 1.21\n"), []byte("module a\n\ngo 1.22\n"))
+	p.Change("go.sum", []byte("same\n"), []byte("same\n"))
+	p.Run(".", "go", "mod", "tidy")
+	p.Run("sub", "git", "commit", "-m", "deps: bump x")
+
+	var out bytes.Buffer
+	p.Print(&out)
+	got := out.String()
+
+	for _, want := range []string{
+		"Dry run, nothing was changed",
+		"--- a/go.mod",
+		"-go 1.21",
+		"+go 1.22",
+		"Would run:\n  $ go mod tidy\n  $ cd sub && git commit -m \"deps: bump x\"\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Print() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "go.sum") {
+		t.Errorf("Print() shows the unchanged go.sum:\n%s", got)
+	}
+}
+
+func TestPlan_PrintNoChanges(t *testing.T) {
+	var p Plan
+	p.Change("go.mod", []byte("module a\n"), []byte("module a\n"))
+
+	var out bytes.Buffer
+	p.Print(&out)
+	if got := out.String(); !strings.Contains(got, "No files would change") || strings.Contains(got, "Would run") {
+		t.Errorf("Print() = %q, want no changes and no commands", got)
+	}
+}
+
+func TestPlan_PrintCommandsOnly(t *testing.T) {
+	var p Plan
+	p.Run(".", "go", "get", "example.com/lib@latest")
+
+	var out bytes.Buffer
+	p.Print(&out)
+	got := out.String()
+	if strings.Contains(got, "No files would change") || !strings.Contains(got, "$ go get example.com/lib@latest") {
+		t.Errorf("Print() = %q, want only the command", got)
+	}
+}
+
+func TestPlan_EditMod(t *testing.T) {
+	modPath := filepath.Join(t.TempDir(), "go.mod")
+	original := "module example.com/app\n\nrequire example.com/a v1.0.0\n"
+	if err := os.WriteFile(modPath, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p Plan
+	err = p.EditMod(modPath, parser, func(w *modfile.Writer) error {
+		return w.UpdateRequire("example.com/a", "v1.2.0")
+	})
+	if err != nil {
+		t.Fatalf("EditMod() error: %v", err)
+	}
+	p.Tidy(modPath)
+
+	var out bytes.Buffer
+	p.Print(&out)
+	got := out.String()
+	for _, want := range []string{"-require example.com/a v1.0.0", "+require example.com/a v1.2.0", "go mod tidy"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Print() missing %q:\n%s", want, got)
+		}
+	}
+	if data, _ := os.ReadFile(modPath); string(data) != original {
+		t.Errorf("EditMod() wrote go.mod:\n%s", data)
+	}
+}
+
+func TestPlan_Restore(t *testing.T) {
+	t.Setenv("GX_HISTORY_DIR", t.TempDir())
+	dir := t.TempDir()
+	modPath := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(modPath, []byte("module example.com/app\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := history.Open(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(modPath, []byte("module example.com/app\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte("example.com/a v1.0.0 h1:a=\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var p Plan
+	if err := p.Restore(modPath, tx); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	var out bytes.Buffer
+	p.Print(&out)
+	got := out.String()
+	for _, want := range []string{"-go 1.22", "+go 1.21", "+++ /dev/null"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Print() missing %q:\n%s", want, got)
+		}
+	}
+}
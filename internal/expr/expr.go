@@ -0,0 +1,140 @@
+// Package expr implements a small expression language for filtering and
+// computing columns over dependency records.
+//
+// Supported syntax:
+//
+//	literals     42, 1.5, "text", 'text', true, false
+//	identifiers  ageDays, direct, updateType
+//	operators    || && ! == != < <= > >= + - * / %
+//	functions    contains(s, sub), hasPrefix(s, p), hasSuffix(s, p), lower(s)
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Env maps identifiers to values (float64, int, string, or bool)
+type Env map[string]any
+
+// Expr is a parsed expression
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses an expression
+func Parse(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// Eval evaluates the expression against env
+func (e *Expr) Eval(env Env) (any, error) {
+	return e.root.eval(env)
+}
+
+// EvalBool evaluates the expression and requires a boolean result
+func (e *Expr) EvalBool(env Env) (bool, error) {
+	v, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q is not boolean (got %s)", e.src, typeName(v))
+	}
+	return b, nil
+}
+
+// Format renders an evaluated value for display
+func Format(v any) string {
+	switch v := v.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%.2f", v)
+	case string:
+		return v
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func normalize(v any) (any, error) {
+	switch v := v.(type) {
+	case float64, string, bool:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+var functions = map[string]func(args []any) (any, error){
+	"contains":  stringFunc2(strings.Contains),
+	"hasPrefix": stringFunc2(strings.HasPrefix),
+	"hasSuffix": stringFunc2(strings.HasSuffix),
+	"lower": func(args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("lower expects 1 argument")
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("lower expects a string")
+		}
+		return strings.ToLower(s), nil
+	},
+}
+
+func stringFunc2(fn func(a, b string) bool) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		a, ok1 := args[0].(string)
+		b, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("expected string arguments")
+		}
+		return fn(a, b), nil
+	}
+}
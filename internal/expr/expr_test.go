@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+var testEnv = Env{
+	"name":       "github.com/spf13/cobra",
+	"ageDays":    400,
+	"direct":     true,
+	"updateType": "minor",
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{"ageDays > 365 && direct", true},
+		{"ageDays > 365 && !direct", false},
+		{"updateType == 'major' || updateType == \"minor\"", true},
+		{"ageDays / 365", 400.0 / 365},
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"-ageDays < 0", true},
+		{"10 % 4", 2.0},
+		{"contains(name, 'cobra')", true},
+		{"hasPrefix(name, 'golang.org/')", false},
+		{"lower('ABC') + 'd'", "abcd"},
+		{"!!direct", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			got, err := e.Eval(testEnv)
+			if err != nil {
+				t.Fatalf("Eval() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"ageDays >",
+		"(direct",
+		"'unterminated",
+		"ageDays # 1",
+		"unknownFn(1)",
+		"direct direct",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := Parse(src); err == nil {
+				t.Errorf("Parse(%q) expected error", src)
+			}
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"missing > 1", "unknown identifier"},
+		{"name > 1", "cannot apply"},
+		{"direct && 1", "expects bool"},
+		{"ageDays / 0", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.src, err)
+			}
+			_, err = e.Eval(testEnv)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalBool(t *testing.T) {
+	numeric, err := Parse("ageDays + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := numeric.EvalBool(testEnv); err == nil {
+		t.Error("EvalBool() expected error for numeric result")
+	}
+
+	direct, err := Parse("direct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := direct.EvalBool(testEnv)
+	if err != nil || !ok {
+		t.Errorf("EvalBool() = %v, %v", ok, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{3.0, "3"},
+		{1.5, "1.50"},
+		{"x", "x"},
+		{true, "true"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.v); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package fsutil writes files atomically while keeping their permissions and ownership.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile atomically replaces path with data. If path already exists its
+// permission bits and owner are kept; otherwise the file is created with perm.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return WriteFileAs(path, data, path, perm)
+}
+
+// WriteFileAs atomically writes data to path, copying the permission bits and
+// owner of ref. If ref does not exist, path's own metadata is kept, and a
+// brand new file is created with perm.
+func WriteFileAs(path string, data []byte, ref string, perm os.FileMode) error {
+	info, err := os.Stat(ref)
+	if os.IsNotExist(err) && path != ref {
+		info, err = os.Stat(path)
+	}
+	if os.IsNotExist(err) {
+		return os.WriteFile(path, data, perm)
+	}
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", ref, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(fmt.Errorf("writing temp file: %w", err))
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return cleanup(fmt.Errorf("setting permissions: %w", err))
+	}
+	chown(tmp, info)
+	if err := tmp.Sync(); err != nil {
+		return cleanup(fmt.Errorf("syncing temp file: %w", err))
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+
+	return nil
+}
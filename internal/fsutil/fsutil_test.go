@@ -0,0 +1,95 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFile_PreservesMode(t *testing.T) {
+	for _, mode := range []os.FileMode{0o600, 0o640, 0o664} {
+		t.Run(mode.String(), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "go.mod")
+			if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(path, mode); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := WriteFile(path, []byte("new"), 0o644); err != nil {
+				t.Fatalf("WriteFile() error: %v", err)
+			}
+
+			assertFile(t, path, "new", mode)
+		})
+	}
+}
+
+func TestWriteFile_NewFileUsesPerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+
+	if err := WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	assertFile(t, path, "data", 0o600)
+}
+
+func TestWriteFileAs_CopiesReferenceMode(t *testing.T) {
+	dir := t.TempDir()
+	ref := filepath.Join(dir, "go.mod")
+	backup := filepath.Join(dir, "go.mod.backup")
+
+	if err := os.WriteFile(ref, []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAs(backup, []byte("original"), ref, 0o644); err != nil {
+		t.Fatalf("WriteFileAs() error: %v", err)
+	}
+	assertFile(t, backup, "original", 0o600)
+
+	// With the reference gone, the existing target keeps its own mode
+	os.Remove(ref)
+	if err := WriteFileAs(backup, []byte("again"), ref, 0o644); err != nil {
+		t.Fatalf("WriteFileAs() error: %v", err)
+	}
+	assertFile(t, backup, "again", 0o600)
+}
+
+func TestWriteFile_NoTempFilesLeft(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	os.WriteFile(path, []byte("old"), 0o644)
+
+	if err := WriteFile(path, []byte("new"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only go.mod in %s, found %d entries", dir, len(entries))
+	}
+}
+
+func assertFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if string(data) != content {
+		t.Errorf("content = %q, want %q", data, content)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != mode {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), mode)
+	}
+}
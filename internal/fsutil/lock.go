@@ -0,0 +1,96 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLocked is returned when a lock is still held by another process after waiting
+var ErrLocked = errors.New("file is locked by another process")
+
+const (
+	lockRetryInterval = 100 * time.Millisecond
+	// lockStaleAfter is how old a lock file without a readable pid must be
+	// before it is treated as abandoned
+	lockStaleAfter = 2 * time.Minute
+)
+
+// Lock is an advisory lock on a file, held through a sibling "<path>.lock" file
+type Lock struct {
+	path string
+}
+
+// LockFile acquires an advisory lock on path, waiting up to timeout for
+// another holder to release it. The lock file records the holder's pid, and
+// is taken over once that process is gone. A lock file without a pid, left by
+// a crash between creating and writing it, is taken over after two minutes.
+func LockFile(path string, timeout time.Duration) (*Lock, error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return &Lock{path: lockPath}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating lock file: %w", err)
+		}
+
+		if stale(lockPath) {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s: %w%s (remove %s if no other gx is running)",
+				path, ErrLocked, holder(lockPath), lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Unlock releases the lock
+func (l *Lock) Unlock() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing lock file: %w", err)
+	}
+	return nil
+}
+
+// stale reports whether a lock file was abandoned: the process it names has
+// exited, or it names none and is older than lockStaleAfter
+func stale(lockPath string) bool {
+	if pid, ok := lockOwner(lockPath); ok {
+		return !processAlive(pid)
+	}
+	info, err := os.Stat(lockPath)
+	return err == nil && time.Since(info.ModTime()) > lockStaleAfter
+}
+
+// lockOwner reads the pid recorded in a lock file
+func lockOwner(lockPath string) (int, bool) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+func holder(lockPath string) string {
+	pid, ok := lockOwner(lockPath)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (pid %d)", pid)
+}
@@ -0,0 +1,114 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+
+	lock, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile() error: %v", err)
+	}
+
+	if _, err := LockFile(path, 200*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("second LockFile() error = %v, want ErrLocked", err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("Unlock() error: %v", err)
+	}
+
+	again, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile() after unlock error: %v", err)
+	}
+	again.Unlock()
+}
+
+func TestLockFile_WaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+
+	lock, err := LockFile(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		lock.Unlock()
+	}()
+
+	second, err := LockFile(path, 2*time.Second)
+	if err != nil {
+		t.Fatalf("LockFile() should succeed once the holder releases: %v", err)
+	}
+	second.Unlock()
+}
+
+func TestLockFile_TakesOverDeadOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	lockPath := path + ".lock"
+
+	// A child that has already exited leaves a pid nothing is running under
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(cmd.Process.Pid)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LockFile(path, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LockFile() should take over a lock whose holder exited: %v", err)
+	}
+	lock.Unlock()
+}
+
+func TestLockFile_KeepsLiveOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	lockPath := path + ".lock"
+
+	// However old the lock file, a running holder keeps it
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LockFile(path, 200*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("LockFile() error = %v, want ErrLocked while the holder runs", err)
+	}
+}
+
+func TestLockFile_TakesOverStaleLockWithoutPid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	lockPath := path + ".lock"
+
+	if err := os.WriteFile(lockPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LockFile(path, 200*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("LockFile() error = %v, want ErrLocked for a fresh lock without a pid", err)
+	}
+
+	old := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := LockFile(path, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("LockFile() should take over a stale lock: %v", err)
+	}
+	lock.Unlock()
+}
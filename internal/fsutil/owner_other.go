@@ -0,0 +1,8 @@
+//go:build !unix
+
+package fsutil
+
+import "os"
+
+// chown is a no-op on platforms without Unix ownership
+func chown(*os.File, os.FileInfo) {}
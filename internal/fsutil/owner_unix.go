@@ -0,0 +1,18 @@
+//go:build unix
+
+package fsutil
+
+import (
+	"os"
+	"syscall"
+)
+
+// chown gives f the owner and group recorded in info. Failures are ignored:
+// unprivileged users can only keep ownership they already have.
+func chown(f *os.File, info os.FileInfo) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	_ = f.Chown(int(st.Uid), int(st.Gid))
+}
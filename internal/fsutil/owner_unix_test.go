@@ -0,0 +1,35 @@
+//go:build unix
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteFile_PreservesOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing ownership requires root")
+	}
+
+	path := filepath.Join(t.TempDir(), "go.mod")
+	os.WriteFile(path, []byte("old"), 0o644)
+	if err := os.Chown(path, 1234, 5678); err != nil {
+		t.Skipf("chown not supported: %v", err)
+	}
+
+	if err := WriteFile(path, []byte("new"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+	if st.Uid != 1234 || st.Gid != 5678 {
+		t.Errorf("owner = %d:%d, want 1234:5678", st.Uid, st.Gid)
+	}
+}
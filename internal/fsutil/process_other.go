@@ -0,0 +1,17 @@
+//go:build !unix
+
+package fsutil
+
+import "os"
+
+// processAlive reports whether a process with this pid exists. On Windows
+// FindProcess fails once the process is gone; elsewhere it always succeeds,
+// so the lock is kept until its holder releases it.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,40 @@
+// Package gitcmd runs git subcommands.
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Run runs a git subcommand in dir and includes its output in any error
+func Run(ctx context.Context, dir string, args ...string) error {
+	_, err := Output(ctx, dir, args...)
+	return err
+}
+
+// Output runs a git subcommand in dir and returns its trimmed stdout
+func Output(ctx context.Context, dir string, args ...string) (string, error) {
+	return OutputEnv(ctx, dir, nil, args...)
+}
+
+// OutputEnv is like Output with env added to the environment
+func OutputEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if dir != "" && dir != "." {
+		cmd.Dir = dir
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,37 @@
+package gitcmd
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestOutput(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	if err := Run(ctx, dir, "init", "-q"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	if err := Run(ctx, dir, "remote", "add", "origin", "https://github.com/omarshaarawi/gx.git"); err != nil {
+		t.Fatalf("git remote add: %v", err)
+	}
+
+	got, err := Output(ctx, dir, "remote", "get-url", "origin")
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	if got != "https://github.com/omarshaarawi/gx.git" {
+		t.Errorf("Output() = %q", got)
+	}
+
+	_, err = Output(ctx, dir, "remote", "get-url", "missing")
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Output() error = %v, want git's stderr included", err)
+	}
+}
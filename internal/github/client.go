@@ -0,0 +1,395 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+const defaultBaseURL = "https://api.github.com"
+
+// Client is a minimal GitHub REST API client
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// Release is a GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// PullRequest is a GitHub pull request
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	User    User   `json:"user"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+// User is a GitHub account
+type User struct {
+	Login string `json:"login"`
+}
+
+// Commit is a commit of a repository
+type Commit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+	} `json:"commit"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// NewClient creates a GitHub client for the API at baseURL, api.github.com
+// when empty, where modules with github.com/ paths are hosted. The token
+// defaults to GITHUB_TOKEN or GH_TOKEN.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   tokenFromEnv(),
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithToken sets the API token
+func (c *Client) WithToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// Authenticated reports whether the client has an API token
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// Host returns the host name of the instance, which its git remotes use:
+// github.com for api.github.com, else the host of the GitHub Enterprise API
+func (c *Client) Host() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+	if strings.EqualFold(u.Hostname(), "api.github.com") {
+		return "github.com"
+	}
+	return u.Hostname()
+}
+
+func tokenFromEnv() string {
+	for _, key := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	return c.do(ctx, "GET", path, nil, v)
+}
+
+// do sends an API request with an optional JSON body and decodes the response into v
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("github returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// ListReleases returns up to 100 of the most recent releases of a repository
+func (c *Client) ListReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	var releases []Release
+	path := fmt.Sprintf("/repos/%s/%s/releases?per_page=100", owner, repo)
+	if err := c.get(ctx, path, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// LatestRelease returns the newest published, non-prerelease release of a repository
+func (c *Client) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var release Release
+	path := fmt.Sprintf("/repos/%s/%s/releases/latest", owner, repo)
+	if err := c.get(ctx, path, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// Download fetches a release asset
+func (c *Client) Download(ctx context.Context, asset Asset) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", asset.BrowserDownloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: server returned %d", asset.Name, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+	return data, nil
+}
+
+// ListPullRequests returns up to 100 open pull requests of a repository
+func (c *Client) ListPullRequests(ctx context.Context, owner, repo string) ([]PullRequest, error) {
+	var prs []PullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls?state=open&per_page=100", owner, repo)
+	if err := c.get(ctx, path, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// ClosePullRequest closes a pull request, first leaving comment on it if not empty
+func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, number int, comment string) error {
+	if comment != "" {
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number)
+		if err := c.do(ctx, "POST", path, map[string]string{"body": comment}, nil); err != nil {
+			return fmt.Errorf("commenting on #%d: %w", number, err)
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number)
+	if err := c.do(ctx, "PATCH", path, map[string]string{"state": "closed"}, nil); err != nil {
+		return fmt.Errorf("closing #%d: %w", number, err)
+	}
+	return nil
+}
+
+// CompareCommits returns up to 250 commits between base and head, such as
+// two tags, oldest first, with the total number of commits between them
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]Commit, int, error) {
+	var comparison struct {
+		TotalCommits int      `json:"total_commits"`
+		Commits      []Commit `json:"commits"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", owner, repo, base, head)
+	if err := c.get(ctx, path, &comparison); err != nil {
+		return nil, 0, err
+	}
+	return comparison.Commits, comparison.TotalCommits, nil
+}
+
+// NewPullRequest describes a pull request to open
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"` // the branch with the changes
+	Base  string `json:"base"` // the branch to merge them into
+	Body  string `json:"body"`
+}
+
+// CreatePullRequest opens a pull request
+func (c *Client) CreatePullRequest(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	var created PullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls", owner, repo)
+	if err := c.do(ctx, "POST", path, pr, &created); err != nil {
+		return nil, fmt.Errorf("opening pull request: %w", err)
+	}
+	return &created, nil
+}
+
+// Repo identifies a GitHub repository and the module's location inside it
+type Repo struct {
+	Owner string
+	Name  string
+	// Subdir is the module directory within the repository, used as a tag prefix
+	Subdir string
+}
+
+// ParseModulePath maps a github.com module path to its repository
+func ParseModulePath(modulePath string) (Repo, bool) {
+	parts := strings.Split(modulePath, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return Repo{}, false
+	}
+
+	rest := parts[3:]
+	if n := len(rest); n > 0 && isMajorSuffix(rest[n-1]) {
+		rest = rest[:n-1]
+	}
+
+	return Repo{
+		Owner:  parts[1],
+		Name:   parts[2],
+		Subdir: strings.Join(rest, "/"),
+	}, true
+}
+
+// ParseRemoteURL maps a git remote URL (https, ssh or scp-style) on the
+// GitHub instance at host, github.com or a GitHub Enterprise server, to its
+// repository
+func ParseRemoteURL(remote, host string) (Repo, bool) {
+	remote = strings.TrimSpace(remote)
+
+	var remoteHost, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return Repo{}, false
+		}
+		remoteHost, path = u.Hostname(), u.Path
+	} else {
+		// scp-style: [user@]host:owner/name.git
+		hostPart, rest, ok := strings.Cut(remote, ":")
+		if !ok {
+			return Repo{}, false
+		}
+		if _, h, ok := strings.Cut(hostPart, "@"); ok {
+			hostPart = h
+		}
+		remoteHost, path = hostPart, rest
+	}
+
+	if host == "" || !strings.EqualFold(remoteHost, host) {
+		return Repo{}, false
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Repo{}, false
+	}
+	return Repo{Owner: parts[0], Name: parts[1]}, true
+}
+
+func isMajorSuffix(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Tag returns the git tag for a module version
+func (r Repo) Tag(version string) string {
+	if r.Subdir == "" {
+		return version
+	}
+	return r.Subdir + "/" + version
+}
+
+// VersionFromTag returns the module version encoded in a tag, if it belongs to this module
+func (r Repo) VersionFromTag(tag string) (string, bool) {
+	if r.Subdir == "" {
+		return tag, !strings.Contains(tag, "/")
+	}
+	return strings.CutPrefix(tag, r.Subdir+"/")
+}
+
+// URL returns the repository web URL
+func (r Repo) URL() string {
+	return fmt.Sprintf("https://github.com/%s/%s", r.Owner, r.Name)
+}
+
+// CompareURL returns the web URL comparing two module versions
+func (r Repo) CompareURL(from, to string) string {
+	return fmt.Sprintf("%s/compare/%s...%s", r.URL(), r.Tag(from), r.Tag(to))
+}
+
+// ReleasesBetween returns the published releases of the module in
+// (from, to], newest first
+func (r Repo) ReleasesBetween(releases []Release, from, to string) []Release {
+	var selected []Release
+	for _, rel := range releases {
+		if rel.Draft {
+			continue
+		}
+		v, ok := r.VersionFromTag(rel.TagName)
+		if !ok || !semver.IsValid(v) {
+			continue
+		}
+		if semver.Compare(v, from) > 0 && semver.Compare(v, to) <= 0 {
+			selected = append(selected, rel)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		vi, _ := r.VersionFromTag(selected[i].TagName)
+		vj, _ := r.VersionFromTag(selected[j].TagName)
+		return semver.Compare(vi, vj) > 0
+	})
+
+	return selected
+}
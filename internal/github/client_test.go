@@ -0,0 +1,297 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ListReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/spf13/cobra/releases" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		json.NewEncoder(w).Encode([]Release{
+			{TagName: "v1.10.1", Body: "fixes"},
+			{TagName: "v1.10.0", Body: "features"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).WithToken("secret")
+
+	releases, err := client.ListReleases(context.Background(), "spf13", "cobra")
+	if err != nil {
+		t.Fatalf("ListReleases() error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].TagName != "v1.10.1" {
+		t.Errorf("ListReleases() = %+v", releases)
+	}
+}
+
+func TestClient_ListReleases_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).ListReleases(context.Background(), "a", "b"); err == nil {
+		t.Error("ListReleases() expected error for 404")
+	}
+}
+
+func TestClient_LatestReleaseAndDownload(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/repos/omarshaarawi/gx/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Release{
+			TagName: "v1.2.0",
+			Assets:  []Asset{{Name: "checksums.txt", BrowserDownloadURL: server.URL + "/download/checksums.txt"}},
+		})
+	})
+	mux.HandleFunc("/download/checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("abc  gx_1.2.0_linux_amd64.tar.gz\n"))
+	})
+
+	client := NewClient(server.URL)
+
+	release, err := client.LatestRelease(context.Background(), "omarshaarawi", "gx")
+	if err != nil {
+		t.Fatalf("LatestRelease() error: %v", err)
+	}
+	if release.TagName != "v1.2.0" || len(release.Assets) != 1 {
+		t.Fatalf("LatestRelease() = %+v", release)
+	}
+
+	data, err := client.Download(context.Background(), release.Assets[0])
+	if err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+	if string(data) != "abc  gx_1.2.0_linux_amd64.tar.gz\n" {
+		t.Errorf("Download() = %q", data)
+	}
+
+	if _, err := client.Download(context.Background(), Asset{Name: "missing", BrowserDownloadURL: server.URL + "/download/missing"}); err == nil {
+		t.Error("Download() expected error for 404")
+	}
+}
+
+func TestClient_PullRequests(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "GET":
+			if r.URL.Query().Get("state") != "open" {
+				t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+			}
+			json.NewEncoder(w).Encode([]PullRequest{{Number: 7, Title: "Bump x from 1.0.0 to 1.1.0", User: User{Login: "dependabot[bot]"}}})
+		case "POST":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["body"] != "adopted" {
+				t.Errorf("comment = %q", body["body"])
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("{}"))
+		case "PATCH":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["state"] != "closed" {
+				t.Errorf("state = %q, want closed", body["state"])
+			}
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	prs, err := client.ListPullRequests(ctx, "o", "r")
+	if err != nil {
+		t.Fatalf("ListPullRequests() error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 7 || prs[0].User.Login != "dependabot[bot]" {
+		t.Errorf("ListPullRequests() = %+v", prs)
+	}
+
+	if err := client.ClosePullRequest(ctx, "o", "r", 7, "adopted"); err != nil {
+		t.Fatalf("ClosePullRequest() error: %v", err)
+	}
+
+	want := []string{"GET /repos/o/r/pulls", "POST /repos/o/r/issues/7/comments", "PATCH /repos/o/r/pulls/7"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		host   string
+		want   Repo
+		wantOK bool
+	}{
+		{"https://github.com/omarshaarawi/gx.git", "github.com", Repo{Owner: "omarshaarawi", Name: "gx"}, true},
+		{"https://github.com/omarshaarawi/gx", "github.com", Repo{Owner: "omarshaarawi", Name: "gx"}, true},
+		{"git@github.com:omarshaarawi/gx.git", "github.com", Repo{Owner: "omarshaarawi", Name: "gx"}, true},
+		{"ssh://git@github.com/omarshaarawi/gx.git", "github.com", Repo{Owner: "omarshaarawi", Name: "gx"}, true},
+		{"git@github.example.com:team/app.git", "github.example.com", Repo{Owner: "team", Name: "app"}, true},
+		{"https://github.example.com/team/app", "github.com", Repo{}, false},
+		{"https://github.com/omarshaarawi/gx", "github.example.com", Repo{}, false},
+		{"https://gitlab.com/group/project.git", "github.com", Repo{}, false},
+		{"https://github.com/omarshaarawi", "github.com", Repo{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseRemoteURL(tt.remote, tt.host)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseRemoteURL(%q, %q) = %+v, %v; want %+v, %v", tt.remote, tt.host, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestClient_Host(t *testing.T) {
+	// Modules on github.com are looked up there whatever the CI's GitHub is
+	t.Setenv("GITHUB_API_URL", "https://github.example.com/api/v3")
+	if got := NewClient("").Host(); got != "github.com" {
+		t.Errorf("NewClient(\"\").Host() = %q, want github.com", got)
+	}
+
+	for base, want := range map[string]string{
+		"https://api.github.com":             "github.com",
+		"https://github.example.com/api/v3/": "github.example.com",
+	} {
+		if got := NewClient(base).Host(); got != want {
+			t.Errorf("NewClient(%q).Host() = %q, want %q", base, got, want)
+		}
+	}
+}
+
+func TestParseModulePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   Repo
+		wantOK bool
+	}{
+		{"github.com/spf13/cobra", Repo{Owner: "spf13", Name: "cobra"}, true},
+		{"github.com/jackc/pgx/v5", Repo{Owner: "jackc", Name: "pgx"}, true},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", Repo{Owner: "aws", Name: "aws-sdk-go-v2", Subdir: "service/s3"}, true},
+		{"golang.org/x/mod", Repo{}, false},
+		{"github.com/owner", Repo{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := ParseModulePath(tt.path)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseModulePath() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRepo_Tags(t *testing.T) {
+	sub := Repo{Owner: "aws", Name: "aws-sdk-go-v2", Subdir: "service/s3"}
+
+	if got := sub.Tag("v1.2.0"); got != "service/s3/v1.2.0" {
+		t.Errorf("Tag() = %q", got)
+	}
+	if v, ok := sub.VersionFromTag("service/s3/v1.2.0"); !ok || v != "v1.2.0" {
+		t.Errorf("VersionFromTag() = %q, %v", v, ok)
+	}
+	if _, ok := sub.VersionFromTag("v1.2.0"); ok {
+		t.Error("VersionFromTag() should reject tags of other modules")
+	}
+
+	root := Repo{Owner: "spf13", Name: "cobra"}
+	if _, ok := root.VersionFromTag("tools/v1.0.0"); ok {
+		t.Error("VersionFromTag() should reject nested module tags for the root module")
+	}
+	if got := root.CompareURL("v1.0.0", "v1.1.0"); got != "https://github.com/spf13/cobra/compare/v1.0.0...v1.1.0" {
+		t.Errorf("CompareURL() = %q", got)
+	}
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/repos/o/r/pulls" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var body NewPullRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Head != "gx/update" || body.Base != "main" || body.Title != "deps: update" {
+			t.Errorf("body = %+v", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(PullRequest{Number: 12, HTMLURL: "https://github.com/o/r/pull/12"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).WithToken("secret")
+	pr, err := client.CreatePullRequest(context.Background(), "o", "r", NewPullRequest{Title: "deps: update", Head: "gx/update", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if pr.Number != 12 || pr.HTMLURL != "https://github.com/o/r/pull/12" {
+		t.Errorf("CreatePullRequest() = %+v", pr)
+	}
+}
+
+func TestClient_CompareCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/o/r/compare/sub/v1.0.0...sub/v1.1.0" {
+			t.Errorf("request = %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"total_commits": 300, "commits": [{"sha": "abc123", "commit": {"message": "Fix it\n\nDetails"}}]}`))
+	}))
+	defer server.Close()
+
+	repo := Repo{Owner: "o", Name: "r", Subdir: "sub"}
+	commits, total, err := NewClient(server.URL).CompareCommits(context.Background(), repo.Owner, repo.Name, repo.Tag("v1.0.0"), repo.Tag("v1.1.0"))
+	if err != nil {
+		t.Fatalf("CompareCommits() error: %v", err)
+	}
+	if total != 300 || len(commits) != 1 || commits[0].SHA != "abc123" || commits[0].Commit.Message != "Fix it\n\nDetails" {
+		t.Errorf("CompareCommits() = %+v, %d", commits, total)
+	}
+}
+
+func TestRepo_ReleasesBetween(t *testing.T) {
+	repo := Repo{Owner: "o", Name: "r", Subdir: "sub"}
+	releases := []Release{
+		{TagName: "sub/v1.1.0"},
+		{TagName: "sub/v1.3.0"},
+		{TagName: "sub/v1.2.0", Draft: true},
+		{TagName: "sub/v1.0.0"},
+		{TagName: "v1.2.5"},
+		{TagName: "sub/v1.2.1", PublishedAt: time.Now()},
+	}
+
+	var got []string
+	for _, r := range repo.ReleasesBetween(releases, "v1.0.0", "v1.2.1") {
+		got = append(got, r.TagName)
+	}
+	want := []string{"sub/v1.2.1", "sub/v1.1.0"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ReleasesBetween() = %v, want %v", got, want)
+	}
+}
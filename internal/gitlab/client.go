@@ -0,0 +1,175 @@
+// Package gitlab is a minimal client for the GitLab REST API, on gitlab.com
+// or a self-hosted instance
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://gitlab.com"
+
+// Client is a minimal GitLab REST API client
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// MergeRequest is a GitLab merge request
+type MergeRequest struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	WebURL string `json:"web_url"`
+}
+
+// NewMergeRequest describes a merge request to open
+type NewMergeRequest struct {
+	Title              string `json:"title"`
+	SourceBranch       string `json:"source_branch"` // the branch with the changes
+	TargetBranch       string `json:"target_branch"` // the branch to merge them into
+	Description        string `json:"description"`
+	RemoveSourceBranch bool   `json:"remove_source_branch"`
+}
+
+// NewClient creates a client for the GitLab instance at baseURL, such as
+// https://gitlab.example.com. An empty baseURL uses CI_SERVER_URL (set in
+// GitLab CI) or gitlab.com. The token defaults to GITLAB_TOKEN.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = os.Getenv("CI_SERVER_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   os.Getenv("GITLAB_TOKEN"),
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// WithToken sets the API token
+func (c *Client) WithToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// Authenticated reports whether the client has an API token
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// Host returns the host name of the instance, which its git remotes use
+func (c *Client) Host() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// do sends an API request with an optional JSON body and decodes the response into v
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v4"+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gitlab returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// CreateMergeRequest opens a merge request in a project, named by its path
+// such as group/subgroup/project
+func (c *Client) CreateMergeRequest(ctx context.Context, project string, mr NewMergeRequest) (*MergeRequest, error) {
+	var created MergeRequest
+	path := fmt.Sprintf("/projects/%s/merge_requests", url.PathEscape(project))
+	if err := c.do(ctx, "POST", path, mr, &created); err != nil {
+		return nil, fmt.Errorf("opening merge request: %w", err)
+	}
+	return &created, nil
+}
+
+// ParseRemoteURL returns the project path of a git remote URL (https, ssh or
+// scp-style) on the GitLab instance at host. Projects can be in nested
+// groups, so the path has two or more elements.
+func ParseRemoteURL(remote, host string) (string, bool) {
+	remote = strings.TrimSpace(remote)
+
+	var remoteHost, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", false
+		}
+		remoteHost, path = u.Hostname(), u.Path
+	} else {
+		// scp-style: [user@]host:group/project.git
+		hostPart, rest, ok := strings.Cut(remote, ":")
+		if !ok {
+			return "", false
+		}
+		if _, h, ok := strings.Cut(hostPart, "@"); ok {
+			hostPart = h
+		}
+		remoteHost, path = hostPart, rest
+	}
+
+	if host == "" || !strings.EqualFold(remoteHost, host) {
+		return "", false
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return "", false
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,29 @@
+package gocmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Run runs a go subcommand in dir and includes its output in any error
+func Run(ctx context.Context, dir string, args ...string) error {
+	return RunEnv(ctx, dir, nil, args...)
+}
+
+// RunEnv is like Run with env added to the environment
+func RunEnv(ctx context.Context, dir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	if dir != "" && dir != "." {
+		cmd.Dir = dir
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, string(output))
+	}
+	return nil
+}
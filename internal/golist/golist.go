@@ -0,0 +1,44 @@
+package golist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ImportedModules returns the set of module paths providing packages that
+// the module in dir imports, including test imports.
+func ImportedModules(ctx context.Context, dir string) (map[string]bool, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-deps", "-test",
+		"-f", "{{with .Module}}{{.Path}}{{end}}", "./...")
+	if dir != "" && dir != "." {
+		cmd.Dir = dir
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseModuleList(output), nil
+}
+
+// ParseModuleList parses newline-separated module paths into a set
+func ParseModuleList(output []byte) map[string]bool {
+	modules := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			modules[line] = true
+		}
+	}
+
+	return modules
+}
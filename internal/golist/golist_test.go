@@ -0,0 +1,48 @@
+package golist
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseModuleList(t *testing.T) {
+	output := []byte("example.com/app\n\ngithub.com/spf13/cobra\nexample.com/app\n")
+
+	modules := ParseModuleList(output)
+
+	if len(modules) != 2 {
+		t.Errorf("ParseModuleList() returned %d modules, want 2", len(modules))
+	}
+	if !modules["github.com/spf13/cobra"] {
+		t.Error("expected github.com/spf13/cobra in result")
+	}
+}
+
+func TestImportedModules(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modules, err := ImportedModules(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("ImportedModules() error: %v", err)
+	}
+
+	if !modules["example.com/app"] {
+		t.Errorf("expected main module in result, got %v", modules)
+	}
+}
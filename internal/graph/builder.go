@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"slices"
 
 	"github.com/omarshaarawi/gx/internal/modfile"
 	"github.com/omarshaarawi/gx/internal/proxy"
@@ -19,7 +20,7 @@ type Node struct {
 // Graph represents a module dependency graph
 type Graph struct {
 	Root  *Node
-	Nodes map[string]*Node
+	Nodes map[string]*Node // by path and by path@version
 }
 
 // Build builds a dependency graph from a go.mod file
@@ -27,29 +28,48 @@ func Build(parser *modfile.Parser) (*Graph, error) {
 	return BuildWithProxy(parser, nil)
 }
 
+// modFileSource fetches the go.mod of a module version, as *proxy.Client does
+type modFileSource interface {
+	GetModFile(ctx context.Context, modulePath, version string) ([]byte, error)
+}
+
 // BuildWithProxy builds a dependency graph, optionally fetching dependencies from proxy
 func BuildWithProxy(parser *modfile.Parser, proxyClient *proxy.Client) (*Graph, error) {
+	// A nil *proxy.Client would make a non-nil source
+	if proxyClient == nil {
+		return build(parser, nil)
+	}
+	return build(parser, proxyClient)
+}
+
+// build builds a dependency graph, following requirements through the go.mod
+// files from source unless it is nil
+func build(parser *modfile.Parser, source modFileSource) (*Graph, error) {
+	direct, indirect := parser.DirectRequires(), parser.IndirectRequires()
+
 	root := &Node{
 		Path:     parser.ModulePath(),
 		Version:  "",
 		Direct:   true,
-		Children: []*Node{},
+		Children: make([]*Node, 0, len(direct)),
 	}
 
+	// Every requirement gets a path and a path@version entry
+	size := 2*(len(direct)+len(indirect)) + 1
 	graph := &Graph{
 		Root:  root,
-		Nodes: make(map[string]*Node),
+		Nodes: make(map[string]*Node, size),
 	}
 
 	graph.Nodes[root.Path] = root
 
-	if proxyClient == nil {
-		for _, req := range parser.DirectRequires() {
+	if source == nil {
+		for _, req := range direct {
 			child := graph.getOrCreateNode(req.Mod.Path, req.Mod.Version, true)
 			root.Children = append(root.Children, child)
 		}
 
-		for _, req := range parser.IndirectRequires() {
+		for _, req := range indirect {
 			graph.getOrCreateNode(req.Mod.Path, req.Mod.Version, false)
 		}
 
@@ -57,29 +77,29 @@ func BuildWithProxy(parser *modfile.Parser, proxyClient *proxy.Client) (*Graph,
 	}
 
 	ctx := context.Background()
-	visited := make(map[string]bool)
+	visited := make(map[*Node]bool, size)
 
-	for _, req := range parser.DirectRequires() {
+	for _, req := range direct {
 		child := graph.getOrCreateNode(req.Mod.Path, req.Mod.Version, true)
 		root.Children = append(root.Children, child)
 
-		graph.buildChildren(ctx, proxyClient, child, visited, 0, 10)
+		graph.buildChildren(ctx, source, child, visited, 0, 10)
 	}
 
 	return graph, nil
 }
 
 // buildChildren recursively builds the dependency tree
-func (g *Graph) buildChildren(ctx context.Context, client *proxy.Client, node *Node, visited map[string]bool, depth, maxDepth int) {
+func (g *Graph) buildChildren(ctx context.Context, client modFileSource, node *Node, visited map[*Node]bool, depth, maxDepth int) {
 	if depth >= maxDepth {
 		return
 	}
 
-	nodeKey := node.Path + "@" + node.Version
-	if visited[nodeKey] {
+	// Nodes are unique per path and version
+	if visited[node] {
 		return
 	}
-	visited[nodeKey] = true
+	visited[node] = true
 
 	modData, err := client.GetModFile(ctx, node.Path, node.Version)
 	if err != nil {
@@ -91,6 +111,7 @@ func (g *Graph) buildChildren(ctx context.Context, client *proxy.Client, node *N
 		return
 	}
 
+	node.Children = slices.Grow(node.Children, len(modFile.Require))
 	for _, req := range modFile.Require {
 		if req.Indirect {
 			continue
@@ -114,17 +135,26 @@ func (g *Graph) buildChildren(ctx context.Context, client *proxy.Client, node *N
 	}
 }
 
-// getOrCreateNode gets or creates a node in the graph
+// getOrCreateNode gets or creates a node in the graph. Nodes for other
+// versions of a module share its path string rather than each holding the
+// copy parsed from a different go.mod.
 func (g *Graph) getOrCreateNode(path, version string, direct bool) *Node {
-	nodeKey := path + "@" + version
+	// Look up path@version in a stack buffer; converting it to a string only
+	// for the map index doesn't allocate, so hits stay free
+	var buf [128]byte
+	key := append(append(append(buf[:0], path...), '@'), version...)
 
-	if node, exists := g.Nodes[nodeKey]; exists {
+	if node, exists := g.Nodes[string(key)]; exists {
 		if direct {
 			node.Direct = true
 		}
 		return node
 	}
 
+	if existing, ok := g.Nodes[path]; ok {
+		path = existing.Path
+	}
+
 	node := &Node{
 		Path:     path,
 		Version:  version,
@@ -132,7 +162,7 @@ func (g *Graph) getOrCreateNode(path, version string, direct bool) *Node {
 		Children: []*Node{},
 	}
 
-	g.Nodes[nodeKey] = node
+	g.Nodes[string(key)] = node
 	g.Nodes[path] = node
 	return node
 }
@@ -182,12 +212,12 @@ func BuildFromRequires(modulePath string, requires []*xmodfile.Require) *Graph {
 		Path:     modulePath,
 		Version:  "",
 		Direct:   true,
-		Children: []*Node{},
+		Children: make([]*Node, 0, len(requires)),
 	}
 
 	graph := &Graph{
 		Root:  root,
-		Nodes: make(map[string]*Node),
+		Nodes: make(map[string]*Node, len(requires)+1),
 	}
 
 	graph.Nodes[root.Path] = root
@@ -210,4 +240,3 @@ func BuildFromRequires(modulePath string, requires []*xmodfile.Require) *Graph {
 
 	return graph
 }
-
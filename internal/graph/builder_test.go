@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/omarshaarawi/gx/internal/benchdata"
 	internalmodfile "github.com/omarshaarawi/gx/internal/modfile"
 	"github.com/omarshaarawi/gx/internal/proxy"
 	"golang.org/x/mod/modfile"
@@ -814,3 +816,19 @@ func BenchmarkBuildFromRequires(b *testing.B) {
 		BuildFromRequires("github.com/test/module", requires)
 	}
 }
+
+func BenchmarkBuild_Large(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(fmt.Sprintf("requires=%d", n), func(b *testing.B) {
+			set := benchdata.New(n)
+			parser := createMockParser(b, string(set.GoMod()))
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := build(parser, set); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// exportNode is a node in the JSON export
+type exportNode struct {
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Direct  bool   `json:"direct"`
+}
+
+// exportEdge is a requirement edge in the JSON export
+type exportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// id identifies a node by module path and version; the main module has no version
+func (n *Node) id() string {
+	if n.Version == "" {
+		return n.Path
+	}
+	return n.Path + "@" + n.Version
+}
+
+// ordered returns every node once, the root first and the rest sorted by ID
+func (g *Graph) ordered() []*Node {
+	seen := map[*Node]bool{g.Root: true}
+	var nodes []*Node
+	for _, n := range g.Nodes {
+		if !seen[n] {
+			seen[n] = true
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].id() < nodes[j].id()
+	})
+	return append([]*Node{g.Root}, nodes...)
+}
+
+// edges returns every requirement edge once, in node order
+func (g *Graph) edges(nodes []*Node) [][2]*Node {
+	var edges [][2]*Node
+	for _, n := range nodes {
+		seen := make(map[*Node]bool)
+		for _, child := range n.Children {
+			if !seen[child] {
+				seen[child] = true
+				edges = append(edges, [2]*Node{n, child})
+			}
+		}
+	}
+	return edges
+}
+
+// ExportDOT writes the graph in Graphviz DOT format. Indirect modules are dashed.
+func ExportDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	nodes := g.ordered()
+
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, n := range nodes {
+		attrs := ""
+		switch {
+		case n == g.Root:
+			attrs = " [style=bold]"
+		case !n.Direct:
+			attrs = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "\t%q%s;\n", n.id(), attrs)
+	}
+	for _, e := range g.edges(nodes) {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", e[0].id(), e[1].id())
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportMermaid writes the graph as a Mermaid flowchart for Markdown docs.
+// Indirect modules are drawn with a dashed border.
+func ExportMermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	nodes := g.ordered()
+
+	ids := make(map[*Node]string, len(nodes))
+	var indirect []string
+
+	b.WriteString("graph LR\n")
+	for i, n := range nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[n], strings.ReplaceAll(n.id(), `"`, "#quot;"))
+		if n != g.Root && !n.Direct {
+			indirect = append(indirect, ids[n])
+		}
+	}
+	for _, e := range g.edges(nodes) {
+		fmt.Fprintf(&b, "    %s --> %s\n", ids[e[0]], ids[e[1]])
+	}
+	if len(indirect) > 0 {
+		b.WriteString("    classDef indirect stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&b, "    class %s indirect\n", strings.Join(indirect, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportJSON writes the graph as a list of nodes and a list of edges
+func ExportJSON(w io.Writer, g *Graph) error {
+	nodes := g.ordered()
+
+	out := struct {
+		Root  string       `json:"root"`
+		Nodes []exportNode `json:"nodes"`
+		Edges []exportEdge `json:"edges"`
+	}{
+		Root:  g.Root.id(),
+		Nodes: make([]exportNode, 0, len(nodes)),
+		Edges: []exportEdge{},
+	}
+	for _, n := range nodes {
+		out.Nodes = append(out.Nodes, exportNode{ID: n.id(), Path: n.Path, Version: n.Version, Direct: n.Direct})
+	}
+	for _, e := range g.edges(nodes) {
+		out.Edges = append(out.Edges, exportEdge{From: e[0].id(), To: e[1].id()})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// exportGraph is root -> a, b; a -> c; b -> c, with c indirect
+func exportGraph() *Graph {
+	g := &Graph{Nodes: make(map[string]*Node)}
+	g.Root = &Node{Path: "example.com/root", Direct: true}
+	g.Nodes[g.Root.Path] = g.Root
+
+	a := g.getOrCreateNode("example.com/a", "v1.0.0", true)
+	b := g.getOrCreateNode("example.com/b", "v2.1.0", true)
+	c := g.getOrCreateNode("example.com/c", "v0.3.0", false)
+	g.Root.Children = []*Node{b, a}
+	a.Children = []*Node{c}
+	b.Children = []*Node{c, c}
+	return g
+}
+
+func TestExportDOT(t *testing.T) {
+	var b strings.Builder
+	if err := ExportDOT(&b, exportGraph()); err != nil {
+		t.Fatalf("ExportDOT() error: %v", err)
+	}
+
+	want := `digraph dependencies {
+	rankdir=LR;
+	node [shape=box];
+	"example.com/root" [style=bold];
+	"example.com/a@v1.0.0";
+	"example.com/b@v2.1.0";
+	"example.com/c@v0.3.0" [style=dashed];
+	"example.com/root" -> "example.com/b@v2.1.0";
+	"example.com/root" -> "example.com/a@v1.0.0";
+	"example.com/a@v1.0.0" -> "example.com/c@v0.3.0";
+	"example.com/b@v2.1.0" -> "example.com/c@v0.3.0";
+}
+`
+	if b.String() != want {
+		t.Errorf("ExportDOT() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	var b strings.Builder
+	if err := ExportMermaid(&b, exportGraph()); err != nil {
+		t.Fatalf("ExportMermaid() error: %v", err)
+	}
+
+	want := `graph LR
+    n0["example.com/root"]
+    n1["example.com/a@v1.0.0"]
+    n2["example.com/b@v2.1.0"]
+    n3["example.com/c@v0.3.0"]
+    n0 --> n2
+    n0 --> n1
+    n1 --> n3
+    n2 --> n3
+    classDef indirect stroke-dasharray: 5 5
+    class n3 indirect
+`
+	if b.String() != want {
+		t.Errorf("ExportMermaid() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var b strings.Builder
+	if err := ExportJSON(&b, exportGraph()); err != nil {
+		t.Fatalf("ExportJSON() error: %v", err)
+	}
+
+	var got struct {
+		Root  string       `json:"root"`
+		Nodes []exportNode `json:"nodes"`
+		Edges []exportEdge `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if got.Root != "example.com/root" || len(got.Nodes) != 4 || len(got.Edges) != 4 {
+		t.Errorf("ExportJSON() = root %q, %d nodes, %d edges; want 4 nodes and 4 edges", got.Root, len(got.Nodes), len(got.Edges))
+	}
+	if n := got.Nodes[3]; n.Path != "example.com/c" || n.Version != "v0.3.0" || n.Direct {
+		t.Errorf("last node = %+v, want indirect example.com/c", n)
+	}
+}
+
+func TestExportJSON_Empty(t *testing.T) {
+	g := BuildFromRequires("example.com/empty", nil)
+
+	var b strings.Builder
+	if err := ExportJSON(&b, g); err != nil {
+		t.Fatalf("ExportJSON() error: %v", err)
+	}
+	if !strings.Contains(b.String(), `"edges": []`) {
+		t.Errorf("ExportJSON() = %s, want an empty edge list", b.String())
+	}
+}
@@ -0,0 +1,374 @@
+// Package history keeps a small transaction log of go.mod/go.sum snapshots
+// taken before gx modifies a module, so a run can be rolled back later.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+)
+
+// Transaction statuses
+const (
+	StatusPending    = "pending"
+	StatusCommitted  = "committed"
+	StatusRolledBack = "rolled-back"
+)
+
+// maxTransactions is how many transactions are kept per module
+const maxTransactions = 20
+
+// lockTimeout is how long Restore waits for another process to release
+// go.mod, and log updates wait for the transaction log
+const lockTimeout = 10 * time.Second
+
+// ErrNoTransaction is returned when there is nothing to roll back
+var ErrNoTransaction = errors.New("no transaction found")
+
+// Side effects that gx undo repeats after restoring the snapshot. Anything
+// else recorded is reported as not undone.
+const (
+	EffectTidy   = "go mod tidy"
+	EffectVendor = "go mod vendor"
+)
+
+// snapshotFiles are the files captured next to go.mod
+var snapshotFiles = []string{"go.mod", "go.sum"}
+
+// Transaction records the files snapshotted before a gx run
+type Transaction struct {
+	ID          string    `json:"id"`
+	Command     string    `json:"command"`
+	Description string    `json:"description,omitempty"`
+	Time        time.Time `json:"time"`
+	Status      string    `json:"status"`
+	Files       []string  `json:"files"`             // snapshot files that existed at the time
+	Effects     []string  `json:"effects,omitempty"` // what the run did besides writing the files
+
+	store *Store
+}
+
+// Store holds the transaction log for a single module
+type Store struct {
+	modDir string
+	dir    string
+}
+
+// baseDir returns the root directory for all transaction logs
+var baseDir = func() (string, error) {
+	if dir := os.Getenv("GX_HISTORY_DIR"); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "gx", "history"), nil
+}
+
+// Open returns the transaction store for the module owning modPath
+func Open(modPath string) (*Store, error) {
+	absPath, err := filepath.Abs(modPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", modPath, err)
+	}
+
+	root, err := baseDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating history directory: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(absPath))
+	return &Store{
+		modDir: filepath.Dir(absPath),
+		dir:    filepath.Join(root, hex.EncodeToString(sum[:8])),
+	}, nil
+}
+
+// Begin snapshots go.mod and go.sum and records a pending transaction
+func (s *Store) Begin(command, description string) (*Transaction, error) {
+	now := time.Now()
+	tx := &Transaction{
+		ID:          now.Format("20060102T150405.000000000"),
+		Command:     command,
+		Description: description,
+		Time:        now,
+		Status:      StatusPending,
+		store:       s,
+	}
+
+	txDir := s.txDir(tx.ID)
+	if err := os.MkdirAll(txDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	for _, name := range snapshotFiles {
+		data, err := os.ReadFile(filepath.Join(s.modDir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := fsutil.WriteFileAs(filepath.Join(txDir, name), data, filepath.Join(s.modDir, name), 0o644); err != nil {
+			return nil, fmt.Errorf("snapshotting %s: %w", name, err)
+		}
+		tx.Files = append(tx.Files, name)
+	}
+
+	if err := s.save(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// List returns all transactions, newest first
+func (s *Store) List() ([]*Transaction, error) {
+	txs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Time.After(txs[j].Time)
+	})
+	return txs, nil
+}
+
+// Last returns the newest committed transaction for a command ("" matches any)
+func (s *Store) Last(command string) (*Transaction, error) {
+	txs, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		if tx.Status == StatusCommitted && (command == "" || tx.Command == command) {
+			return tx, nil
+		}
+	}
+	return nil, ErrNoTransaction
+}
+
+// Get returns a transaction by ID
+func (s *Store) Get(id string) (*Transaction, error) {
+	txs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s: %w", id, ErrNoTransaction)
+}
+
+// Commit marks the transaction as completed so it can be rolled back
+func (tx *Transaction) Commit() error {
+	tx.Status = StatusCommitted
+	return tx.store.save(tx)
+}
+
+// Record notes a side effect of the run, such as go mod tidy or a closed pull
+// request, so gx undo can repeat or report it
+func (tx *Transaction) Record(effect string) error {
+	tx.Effects = append(tx.Effects, effect)
+	return tx.store.save(tx)
+}
+
+// Discard drops a transaction whose run did not complete
+func (tx *Transaction) Discard() error {
+	return tx.store.remove(tx.ID)
+}
+
+// Restore writes the snapshotted files back next to go.mod. Files that did
+// not exist when the snapshot was taken are removed.
+func (tx *Transaction) Restore() error {
+	lock, err := fsutil.LockFile(filepath.Join(tx.store.modDir, "go.mod"), lockTimeout)
+	if err != nil {
+		return fmt.Errorf("locking go.mod: %w", err)
+	}
+	defer lock.Unlock()
+
+	existed := make(map[string]bool, len(tx.Files))
+	for _, name := range tx.Files {
+		existed[name] = true
+	}
+
+	for _, name := range snapshotFiles {
+		target := filepath.Join(tx.store.modDir, name)
+
+		if !existed[name] {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("removing %s: %w", name, err)
+			}
+			continue
+		}
+
+		snapshot := filepath.Join(tx.store.txDir(tx.ID), name)
+		data, err := os.ReadFile(snapshot)
+		if err != nil {
+			return fmt.Errorf("reading snapshot of %s: %w", name, err)
+		}
+		if err := fsutil.WriteFileAs(target, data, snapshot, 0o644); err != nil {
+			return fmt.Errorf("restoring %s: %w", name, err)
+		}
+	}
+
+	tx.Status = StatusRolledBack
+	return tx.store.save(tx)
+}
+
+// Restored is a file Restore would write back
+type Restored struct {
+	Name string // go.mod or go.sum
+	Data []byte // nil when the file didn't exist before the run, so Restore removes it
+}
+
+// Restores returns what Restore would do to each snapshot file, without
+// doing it
+func (tx *Transaction) Restores() ([]Restored, error) {
+	var restores []Restored
+	for _, name := range snapshotFiles {
+		r := Restored{Name: name}
+		if slices.Contains(tx.Files, name) {
+			data, err := os.ReadFile(filepath.Join(tx.store.txDir(tx.ID), name))
+			if err != nil {
+				return nil, fmt.Errorf("reading snapshot of %s: %w", name, err)
+			}
+			r.Data = data
+		}
+		restores = append(restores, r)
+	}
+	return restores, nil
+}
+
+func (s *Store) txDir(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *Store) logPath() string {
+	return filepath.Join(s.dir, "log.json")
+}
+
+func (s *Store) load() ([]*Transaction, error) {
+	data, err := os.ReadFile(s.logPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading transaction log: %w", err)
+	}
+
+	var txs []*Transaction
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return nil, fmt.Errorf("decoding transaction log: %w", err)
+	}
+	for _, tx := range txs {
+		tx.store = s
+	}
+	return txs, nil
+}
+
+func (s *Store) write(txs []*Transaction) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(txs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding transaction log: %w", err)
+	}
+
+	tmp := s.logPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing transaction log: %w", err)
+	}
+	return os.Rename(tmp, s.logPath())
+}
+
+// lockLog locks the transaction log, so concurrent gx runs in one module
+// don't drop each other's entries between reading and rewriting it
+func (s *Store) lockLog() (*fsutil.Lock, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating history directory: %w", err)
+	}
+	lock, err := fsutil.LockFile(s.logPath(), lockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("locking transaction log: %w", err)
+	}
+	return lock, nil
+}
+
+// save inserts or replaces a transaction and trims old entries
+func (s *Store) save(tx *Transaction) error {
+	lock, err := s.lockLog()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	txs, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range txs {
+		if existing.ID == tx.ID {
+			txs[i] = tx
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		txs = append(txs, tx)
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Time.Before(txs[j].Time)
+	})
+
+	for len(txs) > maxTransactions {
+		os.RemoveAll(s.txDir(txs[0].ID))
+		txs = txs[1:]
+	}
+
+	return s.write(txs)
+}
+
+func (s *Store) remove(id string) error {
+	lock, err := s.lockLog()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	txs, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	kept := txs[:0]
+	for _, tx := range txs {
+		if tx.ID != id {
+			kept = append(kept, tx)
+		}
+	}
+
+	if err := os.RemoveAll(s.txDir(id)); err != nil {
+		return fmt.Errorf("removing snapshot: %w", err)
+	}
+	return s.write(kept)
+}
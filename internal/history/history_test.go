@@ -0,0 +1,282 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func setupModule(t *testing.T) (string, *Store) {
+	t.Helper()
+
+	t.Setenv("GX_HISTORY_DIR", t.TempDir())
+
+	dir := t.TempDir()
+	modPath := filepath.Join(dir, "go.mod")
+	writeFile(t, modPath, "module example.com/app\n\nrequire example.com/a v1.0.0\n")
+	writeFile(t, filepath.Join(dir, "go.sum"), "example.com/a v1.0.0 h1:a=\n")
+
+	store, err := Open(modPath)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	return dir, store
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStore_BeginCommitRestore(t *testing.T) {
+	dir, store := setupModule(t)
+	originalMod := readFile(t, filepath.Join(dir, "go.mod"))
+
+	tx, err := store.Begin("update", "1 package")
+	if err != nil {
+		t.Fatalf("Begin() error: %v", err)
+	}
+	if len(tx.Files) != 2 {
+		t.Errorf("Files = %v, want go.mod and go.sum", tx.Files)
+	}
+
+	if _, err := store.Last("update"); !errors.Is(err, ErrNoTransaction) {
+		t.Errorf("Last() before commit error = %v, want ErrNoTransaction", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\nrequire example.com/a v2.0.0\n")
+	writeFile(t, filepath.Join(dir, "go.sum"), "changed\n")
+
+	last, err := store.Last("update")
+	if err != nil {
+		t.Fatalf("Last() error: %v", err)
+	}
+	if last.ID != tx.ID || last.Description != "1 package" {
+		t.Errorf("Last() = %+v", last)
+	}
+
+	if err := last.Restore(); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(dir, "go.mod")); got != originalMod {
+		t.Errorf("go.mod not restored:\n%s", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "go.sum")); got != "example.com/a v1.0.0 h1:a=\n" {
+		t.Errorf("go.sum not restored: %q", got)
+	}
+
+	if _, err := store.Last("update"); !errors.Is(err, ErrNoTransaction) {
+		t.Error("rolled back transaction should not be returned by Last()")
+	}
+}
+
+func TestTransaction_Record(t *testing.T) {
+	_, store := setupModule(t)
+
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatalf("Begin() error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if err := tx.Record(EffectTidy); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if err := tx.Record("closed #12"); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	last, err := store.Last("")
+	if err != nil {
+		t.Fatalf("Last() error: %v", err)
+	}
+	if len(last.Effects) != 2 || last.Effects[0] != EffectTidy || last.Effects[1] != "closed #12" {
+		t.Errorf("Effects = %v, want [%s closed #12]", last.Effects, EffectTidy)
+	}
+	if last.Status != StatusCommitted {
+		t.Errorf("Status = %s, want %s", last.Status, StatusCommitted)
+	}
+}
+
+func TestTransaction_RecordConcurrently(t *testing.T) {
+	_, store := setupModule(t)
+
+	var txs []*Transaction
+	for range 8 {
+		tx, err := store.Begin("update", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+
+	// Each goroutine rewrites the log; none may drop another's effect
+	var wg sync.WaitGroup
+	for _, tx := range txs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tx.Record(EffectTidy); err != nil {
+				t.Errorf("Record() error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, tx := range txs {
+		got, err := store.Get(tx.ID)
+		if err != nil {
+			t.Fatalf("Get(%s) error: %v", tx.ID, err)
+		}
+		if len(got.Effects) != 1 {
+			t.Errorf("transaction %s has effects %v, want [%s]", tx.ID, got.Effects, EffectTidy)
+		}
+	}
+}
+
+func TestStore_RestoreRemovesNewFiles(t *testing.T) {
+	dir, store := setupModule(t)
+	os.Remove(filepath.Join(dir, "go.sum"))
+
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "go.sum"), "new\n")
+
+	if err := tx.Restore(); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.sum")); !os.IsNotExist(err) {
+		t.Error("go.sum created after snapshot should be removed")
+	}
+}
+
+func TestTransaction_Restores(t *testing.T) {
+	dir, store := setupModule(t)
+	os.Remove(filepath.Join(dir, "go.sum"))
+
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n")
+	writeFile(t, filepath.Join(dir, "go.sum"), "new\n")
+
+	restores, err := tx.Restores()
+	if err != nil {
+		t.Fatalf("Restores() error: %v", err)
+	}
+	if len(restores) != 2 {
+		t.Fatalf("Restores() = %d files, want go.mod and go.sum", len(restores))
+	}
+	if restores[0].Name != "go.mod" || string(restores[0].Data) != "module example.com/app\n\nrequire example.com/a v1.0.0\n" {
+		t.Errorf("Restores() go.mod = %s %q, want the snapshot", restores[0].Name, restores[0].Data)
+	}
+	if restores[1].Data != nil {
+		t.Errorf("Restores() go.sum = %q, want nil for a file to remove", restores[1].Data)
+	}
+	if got := readFile(t, filepath.Join(dir, "go.mod")); got != "module example.com/app\n" {
+		t.Errorf("Restores() wrote go.mod: %q", got)
+	}
+}
+
+func TestStore_RestorePreservesMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	dir, store := setupModule(t)
+	modPath := filepath.Join(dir, "go.mod")
+	if err := os.Chmod(modPath, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Remove(modPath)
+	writeFile(t, modPath, "module example.com/changed\n")
+
+	if err := tx.Restore(); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	info, err := os.Stat(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o640 {
+		t.Errorf("restored go.mod mode = %v, want 0640", got)
+	}
+}
+
+func TestStore_Discard(t *testing.T) {
+	_, store := setupModule(t)
+
+	tx, err := store.Begin("update", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Discard(); err != nil {
+		t.Fatalf("Discard() error: %v", err)
+	}
+
+	txs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("List() = %d transactions, want 0", len(txs))
+	}
+}
+
+func TestStore_TrimsOldTransactions(t *testing.T) {
+	_, store := setupModule(t)
+
+	for i := 0; i < maxTransactions+5; i++ {
+		tx, err := store.Begin("update", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Commit()
+	}
+
+	txs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != maxTransactions {
+		t.Errorf("List() = %d transactions, want %d", len(txs), maxTransactions)
+	}
+
+	entries, _ := os.ReadDir(store.dir)
+	if len(entries) != maxTransactions+1 { // snapshot dirs + log.json
+		t.Errorf("store has %d entries, want %d", len(entries), maxTransactions+1)
+	}
+}
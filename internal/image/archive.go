@@ -0,0 +1,90 @@
+package image
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// archiveManifest is an entry of manifest.json in a docker save archive
+type archiveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// FromArchive returns the layers of the first image in a docker save archive
+func FromArchive(name string) ([]Layer, error) {
+	data, err := readArchiveFile(name, "manifest.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []archiveManifest
+	if err := json.Unmarshal(data, &manifests); err != nil {
+		return nil, fmt.Errorf("parsing manifest.json: %w", err)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("%s contains no images", name)
+	}
+
+	var layers []Layer
+	for _, layer := range manifests[0].Layers {
+		layers = append(layers, archiveLayer(name, layer))
+	}
+	return layers, nil
+}
+
+// archiveLayer opens a layer stored as a file inside the archive
+func archiveLayer(archive, member string) Layer {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(archive)
+		if err != nil {
+			return nil, err
+		}
+		r, err := findMember(f, member)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{r, f}, nil
+	}
+}
+
+func readArchiveFile(archive, member string) ([]byte, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := findMember(f, member)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// findMember positions a tar reader at the named member
+func findMember(r io.Reader, member string) (io.Reader, error) {
+	want := path.Clean(member)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in archive", member)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if path.Clean(hdr.Name) == want {
+			return tr, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// argRef matches $NAME and ${NAME} references in a Dockerfile
+var argRef = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?`)
+
+// BaseImages returns the external images named in FROM instructions of a
+// Dockerfile, in order and without duplicates. Build stages and scratch are
+// skipped, and ARG defaults declared before the first FROM are substituted;
+// references that still contain variables are returned in unresolved.
+func BaseImages(data []byte) (images, unresolved []string) {
+	args := make(map[string]string)
+	stages := make(map[string]bool)
+	seen := make(map[string]bool)
+	sawFrom := false
+
+	for _, inst := range instructions(data) {
+		fields := strings.Fields(inst)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			if sawFrom {
+				continue
+			}
+			for _, decl := range fields[1:] {
+				if name, value, ok := strings.Cut(decl, "="); ok {
+					args[name] = strings.Trim(value, `"'`)
+				}
+			}
+
+		case "FROM":
+			sawFrom = true
+			rest := fields[1:]
+			for len(rest) > 0 && strings.HasPrefix(rest[0], "--") {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				continue
+			}
+
+			image := argRef.ReplaceAllStringFunc(rest[0], func(m string) string {
+				name := strings.Trim(m, "${}")
+				if v, ok := args[name]; ok {
+					return v
+				}
+				return m
+			})
+
+			switch {
+			case image == "scratch" || stages[strings.ToLower(image)] || seen[image]:
+			case strings.Contains(image, "$"):
+				unresolved = append(unresolved, image)
+				seen[image] = true
+			default:
+				images = append(images, image)
+				seen[image] = true
+			}
+
+			if len(rest) >= 3 && strings.EqualFold(rest[1], "AS") {
+				stages[strings.ToLower(rest[2])] = true
+			}
+		}
+	}
+	return images, unresolved
+}
+
+// instructions joins continuation lines and drops comments and blank lines
+func instructions(data []byte) []string {
+	var out []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || (line == "" && current.Len() == 0) {
+			continue
+		}
+		if cont, ok := strings.CutSuffix(line, "\\"); ok {
+			current.WriteString(cont + " ")
+			continue
+		}
+		current.WriteString(line)
+		out = append(out, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBaseImages(t *testing.T) {
+	dockerfile := `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+ARG RUNTIME
+
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+RUN go build -o /app ./cmd/app
+
+FROM build AS test
+RUN go test ./...
+
+FROM gcr.io/distroless/static \
+    AS final
+COPY --from=build /app /app
+
+FROM $RUNTIME
+FROM scratch
+FROM golang:1.22
+`
+
+	images, unresolved := BaseImages([]byte(dockerfile))
+
+	if want := []string{"golang:1.22", "gcr.io/distroless/static"}; !reflect.DeepEqual(images, want) {
+		t.Errorf("images = %v, want %v", images, want)
+	}
+	if want := []string{"$RUNTIME"}; !reflect.DeepEqual(unresolved, want) {
+		t.Errorf("unresolved = %v, want %v", unresolved, want)
+	}
+}
@@ -0,0 +1,210 @@
+// Package image finds Go binaries in container images so the modules
+// compiled into them can be audited.
+//
+// Images are read from a registry through the distribution API, or from a
+// docker save archive. Layers are applied in order, honouring whiteouts, and
+// every executable that carries Go build information is copied to a local
+// directory.
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"debug/buildinfo"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Whiteout markers of the OCI layer format
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// Layer opens the content of an image layer, compressed or not
+type Layer func() (io.ReadCloser, error)
+
+// Binary is a Go executable found in an image
+type Binary struct {
+	// Path is the location inside the image
+	Path string
+	// File is the extracted copy on the local disk
+	File string
+	Info *debug.BuildInfo
+}
+
+// Modules returns the path and version of every module compiled into the
+// binary, with replacements applied, keyed by module path. The standard
+// library is listed as "stdlib" at the Go version that built the binary.
+func (b Binary) Modules() map[string]string {
+	mods := map[string]string{"stdlib": b.Info.GoVersion}
+	for _, dep := range b.Info.Deps {
+		if dep.Replace != nil {
+			mods[dep.Path] = dep.Replace.Version
+			continue
+		}
+		mods[dep.Path] = dep.Version
+	}
+	return mods
+}
+
+// executable magic numbers: ELF, PE, and Mach-O (32/64-bit, both byte orders)
+var magics = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xce, 0xfa, 0xed, 0xfe},
+	{0xfe, 0xed, 0xfa, 0xcf}, {0xcf, 0xfa, 0xed, 0xfe},
+}
+
+// candidate is an executable extracted from a layer
+type candidate struct {
+	file  string
+	layer int
+}
+
+// Extract applies the layers in order and copies every Go binary in the
+// final filesystem into dir, returning them sorted by path
+func Extract(layers []Layer, dir string) ([]Binary, error) {
+	found := make(map[string]candidate)
+
+	for i, open := range layers {
+		if err := extractLayer(open, i, dir, found); err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i+1, err)
+		}
+	}
+
+	var binaries []Binary
+	for p, c := range found {
+		info, err := buildinfo.ReadFile(c.file)
+		if err != nil {
+			os.Remove(c.file)
+			continue
+		}
+		binaries = append(binaries, Binary{Path: p, File: c.file, Info: info})
+	}
+
+	sort.Slice(binaries, func(i, j int) bool {
+		return binaries[i].Path < binaries[j].Path
+	})
+	return binaries, nil
+}
+
+func extractLayer(open Layer, index int, dir string, found map[string]candidate) error {
+	rc, err := open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r, err := decompress(rc)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading tar: %w", err)
+		}
+
+		name := path.Clean("/" + hdr.Name)
+		parent, base := path.Split(name)
+
+		switch {
+		case base == whiteoutOpaque:
+			removeUnder(found, path.Clean(parent), index)
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			target := path.Join(parent, strings.TrimPrefix(base, whiteoutPrefix))
+			remove(found, target)
+			removeUnder(found, target, index)
+			continue
+		}
+
+		// Anything written to a path hides what earlier layers had there
+		remove(found, name)
+
+		if hdr.Typeflag != tar.TypeReg || hdr.Mode&0o111 == 0 {
+			continue
+		}
+
+		br := bufio.NewReader(tr)
+		head, _ := br.Peek(4)
+		if !isExecutable(head) {
+			continue
+		}
+
+		file, err := copyFile(dir, base, br)
+		if err != nil {
+			return err
+		}
+		found[name] = candidate{file: file, layer: index}
+	}
+}
+
+// decompress returns a reader of the uncompressed layer tar
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip: %w", err)
+		}
+		return gz, nil
+	case bytes.Equal(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return nil, fmt.Errorf("zstd-compressed layers are not supported")
+	}
+	return br, nil
+}
+
+func isExecutable(head []byte) bool {
+	for _, m := range magics {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyFile writes r to a new file in dir named after base
+func copyFile(dir, base string, r io.Reader) (string, error) {
+	f, err := os.CreateTemp(dir, base+"-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return "", fmt.Errorf("extracting %s: %w", base, err)
+	}
+	return f.Name(), f.Close()
+}
+
+func remove(found map[string]candidate, name string) {
+	if c, ok := found[name]; ok {
+		os.Remove(c.file)
+		delete(found, name)
+	}
+}
+
+// removeUnder removes the entries below dir that came from layers before the given one
+func removeUnder(found map[string]candidate, dir string, before int) {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for name, c := range found {
+		if strings.HasPrefix(name, prefix) && c.layer < before {
+			remove(found, name)
+		}
+	}
+}
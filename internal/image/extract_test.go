@@ -0,0 +1,162 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tarEntry struct {
+	name string
+	mode int64
+	data []byte
+}
+
+func makeTar(t *testing.T, entries []tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: e.mode, Size: int64(len(e.data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func memLayer(data []byte) Layer {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+// goBinary returns the running test binary, which carries Go build information
+func goBinary(t *testing.T) []byte {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExtract(t *testing.T) {
+	bin := goBinary(t)
+
+	base := makeTar(t, []tarEntry{
+		{name: "usr/bin/app", mode: 0o755, data: bin},
+		{name: "usr/bin/tool", mode: 0o755, data: bin},
+		{name: "opt/plugins/plugin", mode: 0o755, data: bin},
+		{name: "usr/lib/notexec", mode: 0o644, data: bin},
+		{name: "bin/script", mode: 0o755, data: []byte("#!/bin/sh\necho hi\n")},
+		{name: "bin/fake", mode: 0o755, data: []byte("\x7fELF not really")},
+	})
+	top := gzipped(t, makeTar(t, []tarEntry{
+		{name: "usr/bin/.wh.tool", mode: 0o644},
+		{name: "opt/plugins/.wh..wh..opq", mode: 0o644},
+		{name: "srv/server", mode: 0o755, data: bin},
+	}))
+
+	dir := t.TempDir()
+	binaries, err := Extract([]Layer{memLayer(base), memLayer(top)}, dir)
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+
+	var paths []string
+	for _, b := range binaries {
+		paths = append(paths, b.Path)
+		if b.Info == nil || b.Info.GoVersion == "" {
+			t.Errorf("%s: missing build info", b.Path)
+		}
+		if _, err := os.Stat(b.File); err != nil {
+			t.Errorf("%s: extracted file missing: %v", b.Path, err)
+		}
+		if b.Modules()["stdlib"] != b.Info.GoVersion {
+			t.Errorf("%s: Modules() stdlib = %q, want %q", b.Path, b.Modules()["stdlib"], b.Info.GoVersion)
+		}
+	}
+	if len(paths) != 2 || paths[0] != "/srv/server" || paths[1] != "/usr/bin/app" {
+		t.Errorf("Extract() paths = %v, want [/srv/server /usr/bin/app]", paths)
+	}
+
+	// Whited-out and non-Go files are cleaned up
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Errorf("%d files left in %s, want 2", len(files), dir)
+	}
+}
+
+func TestExtract_Overwritten(t *testing.T) {
+	bin := goBinary(t)
+
+	base := makeTar(t, []tarEntry{{name: "app", mode: 0o755, data: bin}})
+	top := makeTar(t, []tarEntry{{name: "./app", mode: 0o755, data: []byte("#!/bin/sh\n")}})
+
+	binaries, err := Extract([]Layer{memLayer(base), memLayer(top)}, t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+	if len(binaries) != 0 {
+		t.Errorf("Extract() = %d binaries, want none after overwrite", len(binaries))
+	}
+}
+
+func TestFromArchive(t *testing.T) {
+	bin := goBinary(t)
+
+	layer := makeTar(t, []tarEntry{{name: "app", mode: 0o755, data: bin}})
+	manifest, _ := json.Marshal([]archiveManifest{{
+		Config:   "config.json",
+		RepoTags: []string{"app:latest"},
+		Layers:   []string{"abc/layer.tar"},
+	}})
+	archive := makeTar(t, []tarEntry{
+		{name: "abc/layer.tar", mode: 0o644, data: layer},
+		{name: "manifest.json", mode: 0o644, data: manifest},
+	})
+
+	name := filepath.Join(t.TempDir(), "image.tar")
+	if err := os.WriteFile(name, archive, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layers, err := FromArchive(name)
+	if err != nil {
+		t.Fatalf("FromArchive() error: %v", err)
+	}
+	binaries, err := Extract(layers, t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+	if len(binaries) != 1 || binaries[0].Path != "/app" {
+		t.Errorf("binaries = %+v, want /app", binaries)
+	}
+}
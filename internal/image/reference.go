@@ -0,0 +1,97 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Docker Hub, the registry of references without a host
+const (
+	dockerHub    = "docker.io"
+	dockerHubAPI = "registry-1.docker.io"
+)
+
+// Reference identifies an image in a registry
+type Reference struct {
+	// Registry is the registry host, docker.io for Docker Hub
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses references such as alpine, ghcr.io/org/app:v1 or
+// registry.local:5000/app@sha256:..., defaulting to Docker Hub and the latest tag
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+	rest := s
+
+	if name, digest, ok := strings.Cut(rest, "@"); ok {
+		if !strings.Contains(digest, ":") {
+			return Reference{}, fmt.Errorf("invalid digest in image reference %q", s)
+		}
+		rest, ref.Digest = name, digest
+	}
+
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest, ref.Tag = rest[:i], rest[i+1:]
+	}
+
+	ref.Registry = dockerHub
+	if host, repo, ok := strings.Cut(rest, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry, rest = host, repo
+	}
+	if ref.Registry == dockerHub && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+	ref.Repository = rest
+
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) || strings.Contains(ref.Repository, "//") {
+		return Reference{}, fmt.Errorf("invalid image reference %q", s)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// String returns the reference in its canonical form
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifestRef is the tag or digest used to fetch the manifest
+func (r Reference) manifestRef() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// baseURL is the distribution API root of the registry. Registries on the
+// local machine are reached over plain HTTP, as docker does by default.
+func (r Reference) baseURL() string {
+	host := r.Registry
+	if host == dockerHub {
+		host = dockerHubAPI
+	}
+
+	scheme := "https"
+	hostname := host
+	if h, _, ok := strings.Cut(strings.TrimPrefix(host, "["), "]"); ok && strings.HasPrefix(host, "[") {
+		hostname = h
+	} else if h, _, ok := strings.Cut(host, ":"); ok {
+		hostname = h
+	}
+	if hostname == "localhost" || hostname == "127.0.0.1" || hostname == "::1" {
+		scheme = "http"
+	}
+	return scheme + "://" + host + "/v2/" + r.Repository
+}
@@ -0,0 +1,52 @@
+package image
+
+import "testing"
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Reference
+		wantURL string
+		wantErr bool
+	}{
+		{
+			in:      "alpine",
+			want:    Reference{Registry: "docker.io", Repository: "library/alpine", Tag: "latest"},
+			wantURL: "https://registry-1.docker.io/v2/library/alpine",
+		},
+		{
+			in:   "org/app:1.2",
+			want: Reference{Registry: "docker.io", Repository: "org/app", Tag: "1.2"},
+		},
+		{
+			in:      "ghcr.io/org/app:v1@sha256:abc",
+			want:    Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1", Digest: "sha256:abc"},
+			wantURL: "https://ghcr.io/v2/org/app",
+		},
+		{
+			in:      "localhost:5000/app",
+			want:    Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+			wantURL: "http://localhost:5000/v2/app",
+		},
+		{in: "Upper/Case", wantErr: true},
+		{in: "app@nodigest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseReference(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseReference() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantURL != "" && got.baseURL() != tt.wantURL {
+				t.Errorf("baseURL() = %q, want %q", got.baseURL(), tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manifest media types of the Docker and OCI formats
+const (
+	mediaDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	mediaOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// DefaultPlatform is the platform pulled from multi-platform images
+var DefaultPlatform = "linux/" + runtime.GOARCH
+
+// Client pulls images through the registry distribution API. Anonymous
+// bearer tokens are requested when a registry asks for them; set
+// GX_REGISTRY_USERNAME and GX_REGISTRY_PASSWORD for private images.
+type Client struct {
+	http     *http.Client
+	username string
+	password string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// manifest is an image manifest or an index of per-platform manifests
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	Layers    []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// NewClient creates a registry client
+func NewClient() *Client {
+	return &Client{
+		http:     &http.Client{Timeout: 10 * time.Minute},
+		username: os.Getenv("GX_REGISTRY_USERNAME"),
+		password: os.Getenv("GX_REGISTRY_PASSWORD"),
+		tokens:   make(map[string]string),
+	}
+}
+
+// Pull resolves the image manifest for the platform ("os/arch[/variant]")
+// and returns its layers, which are downloaded when opened
+func (c *Client) Pull(ctx context.Context, ref Reference, platform string) ([]Layer, error) {
+	m, err := c.manifest(ctx, ref, ref.manifestRef())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Manifests) > 0 {
+		digest, err := selectPlatform(m, platform)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		if m, err = c.manifest(ctx, ref, digest); err != nil {
+			return nil, err
+		}
+	}
+
+	var layers []Layer
+	for _, l := range m.Layers {
+		digest := l.Digest
+		layers = append(layers, func() (io.ReadCloser, error) {
+			resp, err := c.get(ctx, ref, ref.baseURL()+"/blobs/"+digest, "")
+			if err != nil {
+				return nil, fmt.Errorf("downloading %s: %w", digest, err)
+			}
+			return resp.Body, nil
+		})
+	}
+	return layers, nil
+}
+
+func (c *Client) manifest(ctx context.Context, ref Reference, tagOrDigest string) (*manifest, error) {
+	accept := strings.Join([]string{mediaOCIIndex, mediaDockerList, mediaOCIManifest, mediaDockerManifest}, ", ")
+	resp, err := c.get(ctx, ref, ref.baseURL()+"/manifests/"+tagOrDigest, accept)
+	if err != nil {
+		return nil, fmt.Errorf("fetching manifest for %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	var m manifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decoding manifest for %s: %w", ref, err)
+	}
+	return &m, nil
+}
+
+// selectPlatform returns the digest of the manifest for platform from an index
+func selectPlatform(index *manifest, platform string) (string, error) {
+	parts := strings.SplitN(platform, "/", 3)
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid platform %q (want os/arch)", platform)
+	}
+
+	var available []string
+	for _, m := range index.Manifests {
+		p := m.Platform
+		if p.OS == parts[0] && p.Architecture == parts[1] && (len(parts) < 3 || p.Variant == parts[2]) {
+			return m.Digest, nil
+		}
+		if p.OS != "" && p.OS != "unknown" {
+			available = append(available, strings.TrimSuffix(p.OS+"/"+p.Architecture+"/"+p.Variant, "/"))
+		}
+	}
+	return "", fmt.Errorf("no image for %s (available: %s)", platform, strings.Join(available, ", "))
+}
+
+// get performs an authenticated GET, answering a token challenge once
+func (c *Client) get(ctx context.Context, ref Reference, rawURL, accept string) (*http.Response, error) {
+	resp, err := c.do(ctx, ref, rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authenticate(ctx, ref, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(ctx, ref, rawURL, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, ref Reference, rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	c.mu.Lock()
+	token := c.tokens[ref.Registry+"/"+ref.Repository]
+	c.mu.Unlock()
+
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// authenticate fetches a bearer token as described by a WWW-Authenticate challenge
+func (c *Client) authenticate(ctx context.Context, ref Reference, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("registry requires %s authentication", strings.TrimSpace(scheme+" "+params))
+	}
+
+	attrs := parseChallenge(params)
+	realm := attrs["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry token challenge has no realm")
+	}
+
+	q := url.Values{}
+	if attrs["service"] != "" {
+		q.Set("service", attrs["service"])
+	}
+	scope := attrs["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	q.Set("scope", scope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decoding registry token: %w", err)
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("registry returned an empty token")
+	}
+
+	c.mu.Lock()
+	c.tokens[ref.Registry+"/"+ref.Repository] = token
+	c.mu.Unlock()
+	return nil
+}
+
+// parseChallenge parses the key="value" pairs of a WWW-Authenticate header
+func parseChallenge(params string) map[string]string {
+	attrs := make(map[string]string)
+	for params != "" {
+		key, rest, ok := strings.Cut(strings.TrimLeft(params, " ,"), "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[strings.ToLower(strings.TrimSpace(key))] = value
+		params = rest
+	}
+	return attrs
+}
@@ -0,0 +1,84 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Pull(t *testing.T) {
+	bin := goBinary(t)
+	layer := gzipped(t, makeTar(t, []tarEntry{{name: "usr/local/bin/app", mode: 0o755, data: bin}}))
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				t.Errorf("token scope = %q", r.URL.Query().Get("scope"))
+			}
+			fmt.Fprint(w, `{"token":"secret"}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/team/app/manifests/v1":
+			w.Header().Set("Content-Type", mediaOCIIndex)
+			fmt.Fprint(w, `{"mediaType":"`+mediaOCIIndex+`","manifests":[
+				{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64"}},
+				{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}}]}`)
+		case "/v2/team/app/manifests/sha256:amd":
+			fmt.Fprint(w, `{"mediaType":"`+mediaOCIManifest+`","layers":[{"digest":"sha256:layer"}]}`)
+		case "/v2/team/app/blobs/sha256:layer":
+			w.Write(layer)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ref, err := ParseReference(strings.TrimPrefix(server.URL, "http://") + "/team/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	layers, err := client.Pull(context.Background(), ref, "linux/amd64")
+	if err != nil {
+		t.Fatalf("Pull() error: %v", err)
+	}
+
+	binaries, err := Extract(layers, t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+	if len(binaries) != 1 || binaries[0].Path != "/usr/local/bin/app" {
+		t.Errorf("binaries = %+v, want /usr/local/bin/app", binaries)
+	}
+
+	if _, err := client.Pull(context.Background(), ref, "windows/amd64"); err == nil || !strings.Contains(err.Error(), "linux/arm64") {
+		t.Errorf("Pull() for a missing platform error = %v, want list of available platforms", err)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	got := parseChallenge(`realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a/b:pull,push"`)
+	want := map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull,push",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Package importpath rewrites the import paths of Go source files when a
+// module moves to a new path, such as a new major version.
+package importpath
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Move maps the imports of one module path to another. Packages inside the
+// module move along: old/sub becomes new/sub. A Move without New keeps the
+// packages at Old, for those with nowhere to move to.
+type Move struct {
+	Old string
+	New string
+}
+
+// Rewrite rewrites the imports of every .go file of the module rooted at
+// dir, returning the files it changed relative to dir. Only the import paths
+// are touched; the rest of each file is left byte for byte. An import whose
+// package is named differently at its new path is given its old name, so
+// the file still compiles. Nothing is written unless every file parses.
+func Rewrite(dir string, moves []Move) ([]string, error) {
+	type rewrite struct {
+		path  string
+		src   []byte
+		edits []edit
+	}
+	var rewrites []rewrite
+	err := goFiles(dir, func(path string, src []byte) error {
+		edits, err := importEdits(path, src, moves)
+		if len(edits) > 0 {
+			rewrites = append(rewrites, rewrite{path, src, edits})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, r := range rewrites {
+		if err := writeEdits(r.path, r.src, r.edits); err != nil {
+			return changed, err
+		}
+		rel, _ := filepath.Rel(dir, r.path)
+		changed = append(changed, rel)
+	}
+	return changed, nil
+}
+
+// Change is one import a rewrite would change
+type Change struct {
+	File string // relative to the module root
+	Line int
+	Old  string
+	New  string
+	Name string // the name given to the import to keep its old one, if any
+}
+
+// Preview returns the imports Rewrite would change, in file order, without
+// writing anything
+func Preview(dir string, moves []Move) ([]Change, error) {
+	var changes []Change
+	err := goFiles(dir, func(path string, src []byte) error {
+		edits, err := importEdits(path, src, moves)
+		rel, _ := filepath.Rel(dir, path)
+		for _, e := range edits {
+			changes = append(changes, Change{File: rel, Line: e.line, Old: e.old, New: e.path, Name: e.name})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// File is a source file a rewrite would change
+type File struct {
+	Path   string // relative to the module root
+	Before []byte
+	After  []byte
+}
+
+// Rewritten returns the files Rewrite would change with their content before
+// and after, in file order, without writing anything
+func Rewritten(dir string, moves []Move) ([]File, error) {
+	var files []File
+	err := goFiles(dir, func(path string, src []byte) error {
+		edits, err := importEdits(path, src, moves)
+		if len(edits) > 0 {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, File{Path: rel, Before: src, After: applyEdits(src, edits)})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Importers returns the .go files of the module rooted at dir that import a
+// package of one of the modules, relative to dir
+func Importers(dir string, modulePaths []string) ([]string, error) {
+	var found []string
+	err := goFiles(dir, func(path string, src []byte) error {
+		_, specs, err := parseImports(path, src)
+		if err != nil {
+			return err
+		}
+		for _, spec := range specs {
+			imported, _ := strconv.Unquote(spec.Path.Value)
+			if slices.ContainsFunc(modulePaths, func(m string) bool { return inModule(imported, m) }) {
+				rel, _ := filepath.Rel(dir, path)
+				found = append(found, rel)
+				break
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// goFiles calls fn with the content of every .go file of the module rooted
+// at dir, in lexical order. Like the go command, it skips vendor and
+// testdata directories, directories starting with . or _, and nested
+// modules.
+func goFiles(dir string, fn func(path string, src []byte) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipDir(path, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return fn(path, src)
+	})
+}
+
+// skipDir reports whether the go command leaves a directory out of the module
+func skipDir(path, name string) bool {
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(path, "go.mod"))
+	return err == nil
+}
+
+// edit replaces the quoted import path old at src[start:end], on line,
+// naming the import name when set
+type edit struct {
+	start, end int
+	line       int
+	old, path  string
+	name       string
+}
+
+// parseImports parses the import declarations of a file
+func parseImports(filename string, src []byte) (*token.FileSet, []*ast.ImportSpec, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return fset, f.Imports, nil
+}
+
+// importEdits finds the imports of a file that a move applies to
+func importEdits(filename string, src []byte, moves []Move) ([]edit, error) {
+	fset, specs, err := parseImports(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []edit
+	var qualifiers map[string]bool
+	for _, spec := range specs {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		moved, ok := movePath(path, moves)
+		if !ok || moved == path {
+			continue
+		}
+		pos := fset.Position(spec.Path.Pos())
+		e := edit{
+			start: pos.Offset,
+			end:   fset.Position(spec.Path.End()).Offset,
+			line:  pos.Line,
+			old:   path,
+			path:  moved,
+		}
+
+		// The file refers to the package by the name it had, which the
+		// import has to keep when the new package is named differently
+		if oldName := assumedName(path); spec.Name == nil && oldName != assumedName(moved) && token.IsIdentifier(oldName) {
+			if qualifiers == nil {
+				if qualifiers, err = packageQualifiers(filename, src); err != nil {
+					return nil, err
+				}
+			}
+			if qualifiers[oldName] {
+				e.name = oldName
+			}
+		}
+		edits = append(edits, e)
+	}
+	return edits, nil
+}
+
+// assumedName is the name of the package at path by convention: its last
+// element without a major version, a go- prefix, or anything from the first
+// character that can't be in a name, such as yaml for gopkg.in/yaml.v3
+func assumedName(path string) string {
+	elems := strings.Split(path, "/")
+	base := elems[len(elems)-1]
+	if rest, ok := strings.CutPrefix(base, "v"); ok && len(elems) > 1 {
+		if _, err := strconv.Atoi(rest); err == nil {
+			base = elems[len(elems)-2]
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+// packageQualifiers returns the names a file qualifies identifiers with that
+// aren't declared in it, such as any in any.Any: those of imported packages
+func packageQualifiers(filename string, src []byte) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	qualifiers := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				qualifiers[x.Name] = true
+			}
+		}
+		return true
+	})
+	return qualifiers, nil
+}
+
+// movePath returns the path of a package after the moves, preferring the
+// longest module path that contains it. Packages of a higher major version
+// of a module, such as old/v2/sub, are in a module of their own and stay.
+func movePath(path string, moves []Move) (string, bool) {
+	best := -1
+	for i, m := range moves {
+		if !inModule(path, m.Old) {
+			continue
+		}
+		if best < 0 || len(m.Old) > len(moves[best].Old) {
+			best = i
+		}
+	}
+	if best < 0 || moves[best].New == "" {
+		return "", false
+	}
+	return moves[best].New + strings.TrimPrefix(path, moves[best].Old), true
+}
+
+// applyEdits returns src with the edits applied
+func applyEdits(src []byte, edits []edit) []byte {
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		out.Write(src[last:e.start])
+		if e.name != "" {
+			out.WriteString(e.name + " ")
+		}
+		out.WriteString(strconv.Quote(e.path))
+		last = e.end
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// writeEdits writes src with the edits applied back to path
+func writeEdits(path string, src []byte, edits []edit) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, applyEdits(src, edits), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// inModule reports whether a package path is in the module at modulePath
+func inModule(path, modulePath string) bool {
+	if path == modulePath {
+		return true
+	}
+	rest, ok := strings.CutPrefix(path, modulePath+"/")
+	if !ok {
+		return false
+	}
+	first, _, _ := strings.Cut(rest, "/")
+	n, err := strconv.Atoi(strings.TrimPrefix(first, "v"))
+	return !strings.HasPrefix(first, "v") || err != nil || n < 2
+}
@@ -0,0 +1,195 @@
+package importpath
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2" // keep the comment
+	"github.com/acme/lib"
+	"github.com/acme/lib/sub/pkg"
+	"github.com/acme/library"
+	"github.com/acme/lib/v3/already"
+)
+
+func main() { fmt.Println(lib.X, pkg.Y, yaml.Z) }
+`,
+		"internal/a/a_test.go": "package a\n\nimport _ \"github.com/acme/lib/sub\"\n",
+	})
+	untouched := map[string]string{
+		"other.go":      "package main\n\nimport \"fmt\"\n",
+		"vendor/x/x.go": "package x\n\nimport \"github.com/acme/lib\"\n",
+		"testdata/t.go": "package t\n\nimport \"github.com/acme/lib\"\n",
+		"nested/go.mod": "module example.com/nested\n",
+		"nested/n.go":   "package n\n\nimport \"github.com/acme/lib\"\n",
+	}
+	writeFiles(t, dir, untouched)
+
+	moves := []Move{
+		{Old: "github.com/acme/lib", New: "github.com/acme/lib/v3"},
+		{Old: "gopkg.in/yaml.v2", New: "gopkg.in/yaml.v3"},
+	}
+	changed, err := Rewrite(dir, moves)
+	if err != nil {
+		t.Fatalf("Rewrite() error: %v", err)
+	}
+	if want := []string{filepath.Join("internal", "a", "a_test.go"), "main.go"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+
+	got, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	want := `package main
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3" // keep the comment
+	"github.com/acme/lib/v3"
+	"github.com/acme/lib/v3/sub/pkg"
+	"github.com/acme/library"
+	"github.com/acme/lib/v3/already"
+)
+
+func main() { fmt.Println(lib.X, pkg.Y, yaml.Z) }
+`
+	if string(got) != want {
+		t.Errorf("main.go =\n%s\nwant\n%s", got, want)
+	}
+	for name, content := range untouched {
+		data, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if string(data) != content {
+			t.Errorf("%s was rewritten:\n%s", name, data)
+		}
+	}
+}
+
+func TestRewrite_ParseError(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.go": "package a\n\nimport \"github.com/acme/lib\"\n",
+		"b.go": "package b\n\nimport (\n",
+	})
+
+	if _, err := Rewrite(dir, []Move{{Old: "github.com/acme/lib", New: "github.com/acme/lib/v2"}}); err == nil {
+		t.Fatal("expected an error for a file that doesn't parse")
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.go"))
+	if string(data) != "package a\n\nimport \"github.com/acme/lib\"\n" {
+		t.Error("a file was rewritten although another one doesn't parse")
+	}
+}
+
+func TestPreview(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":   "package a\n\nimport (\n\t\"fmt\"\n\t\"github.com/satori/go.uuid\"\n)\n\nvar _ = uuid.NewV4\n",
+		"b/b.go": "package b\n\nimport \"github.com/golang/protobuf/ptypes/any\"\n\nvar _ any.Any\n",
+		"c/c.go": "package c\n\nimport (\n\t\"github.com/golang/protobuf/jsonpb\"\n\t\"github.com/golang/protobuf/ptypes\"\n\tts \"github.com/golang/protobuf/ptypes/timestamp\"\n)\n\nvar _ jsonpb.Marshaler\nvar _ = ptypes.TimestampNow\nvar _ ts.Timestamp\n",
+	}
+	writeFiles(t, dir, files)
+
+	moves := []Move{
+		{Old: "github.com/satori/go.uuid", New: "github.com/gofrs/uuid"},
+		{Old: "github.com/golang/protobuf/jsonpb", New: "google.golang.org/protobuf/encoding/protojson"},
+		{Old: "github.com/golang/protobuf/ptypes", New: ""},
+		{Old: "github.com/golang/protobuf/ptypes/any", New: "google.golang.org/protobuf/types/known/anypb"},
+		{Old: "github.com/golang/protobuf/ptypes/timestamp", New: "google.golang.org/protobuf/types/known/timestamppb"},
+		{Old: "github.com/golang/protobuf", New: "google.golang.org/protobuf"},
+	}
+	got, err := Preview(dir, moves)
+	if err != nil {
+		t.Fatalf("Preview() error: %v", err)
+	}
+	want := []Change{
+		{File: "a.go", Line: 5, Old: "github.com/satori/go.uuid", New: "github.com/gofrs/uuid"},
+		{File: filepath.Join("b", "b.go"), Line: 3, Old: "github.com/golang/protobuf/ptypes/any", New: "google.golang.org/protobuf/types/known/anypb", Name: "any"},
+		{File: filepath.Join("c", "c.go"), Line: 4, Old: "github.com/golang/protobuf/jsonpb", New: "google.golang.org/protobuf/encoding/protojson", Name: "jsonpb"},
+		{File: filepath.Join("c", "c.go"), Line: 6, Old: "github.com/golang/protobuf/ptypes/timestamp", New: "google.golang.org/protobuf/types/known/timestamppb"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview() = %+v, want %+v", got, want)
+	}
+	for name, content := range files {
+		data, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if string(data) != content {
+			t.Errorf("Preview() wrote %s", name)
+		}
+	}
+
+	if _, err := Rewrite(dir, moves); err != nil {
+		t.Fatalf("Rewrite() error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "c", "c.go"))
+	wantC := "package c\n\nimport (\n\tjsonpb \"google.golang.org/protobuf/encoding/protojson\"\n\t\"github.com/golang/protobuf/ptypes\"\n\tts \"google.golang.org/protobuf/types/known/timestamppb\"\n)\n\nvar _ jsonpb.Marshaler\nvar _ = ptypes.TimestampNow\nvar _ ts.Timestamp\n"
+	if string(data) != wantC {
+		t.Errorf("c/c.go =\n%s\nwant\n%s", data, wantC)
+	}
+}
+
+func TestRewritten(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package a\n\nimport (\n\t\"fmt\"\n\t\"github.com/satori/go.uuid\"\n)\n",
+		"b.go": "package a\n\nimport \"fmt\"\n",
+	}
+	writeFiles(t, dir, files)
+
+	got, err := Rewritten(dir, []Move{{Old: "github.com/satori/go.uuid", New: "github.com/gofrs/uuid"}})
+	if err != nil {
+		t.Fatalf("Rewritten() error: %v", err)
+	}
+	want := []File{{
+		Path:   "a.go",
+		Before: []byte(files["a.go"]),
+		After:  []byte("package a\n\nimport (\n\t\"fmt\"\n\t\"github.com/gofrs/uuid\"\n)\n"),
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rewritten() = %+v, want %+v", got, want)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.go"))
+	if string(data) != files["a.go"] {
+		t.Error("Rewritten() wrote a.go")
+	}
+}
+
+func TestImporters(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.go":      "package a\n\nimport \"github.com/acme/lib/sub\"\n",
+		"b.go":      "package a\n\nimport \"github.com/acme/lib/v2\"\n",
+		"c/c.go":    "package c\n\nimport \"github.com/acme/lib\"\n",
+		"README":    "github.com/acme/lib",
+		"_old/d.go": "package d\n\nimport \"github.com/acme/lib\"\n",
+	})
+
+	got, err := Importers(dir, []string{"github.com/acme/lib"})
+	if err != nil {
+		t.Fatalf("Importers() error: %v", err)
+	}
+	if want := []string{"a.go", filepath.Join("c", "c.go")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Importers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/license"
+	"github.com/omarshaarawi/gx/internal/modfile"
+)
+
+// Record is a normalized inventory entry for a single dependency.
+// Field names are part of the export format and must stay stable.
+type Record struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	License      string `json:"license"`
+	RepoURL      string `json:"repo_url"`
+	Hash         string `json:"hash"`
+	Relationship string `json:"relationship"` // direct, indirect
+	OwnerTeam    string `json:"owner_team"`
+}
+
+// Fields lists the CSV column names in export order
+var Fields = []string{"name", "version", "license", "repo_url", "hash", "relationship", "owner_team"}
+
+// Options configures inventory building
+type Options struct {
+	// Owner resolves the owning team for a module path
+	Owner func(modulePath string) string
+	// License resolves the license for a module version, defaults to license.Detect
+	License func(modulePath, version string) string
+}
+
+// Build creates inventory records for every requirement in go.mod
+func Build(parser *modfile.Parser, sums *modfile.SumFile, opts Options) []Record {
+	if opts.License == nil {
+		opts.License = license.Detect
+	}
+
+	requires := parser.AllRequires()
+	records := make([]Record, 0, len(requires))
+
+	for _, req := range requires {
+		relationship := "direct"
+		if req.Indirect {
+			relationship = "indirect"
+		}
+
+		record := Record{
+			Name:         req.Mod.Path,
+			Version:      req.Mod.Version,
+			License:      opts.License(req.Mod.Path, req.Mod.Version),
+			RepoURL:      RepoURL(req.Mod.Path),
+			Relationship: relationship,
+		}
+		if sums != nil {
+			record.Hash = sums.Hash(req.Mod.Path, req.Mod.Version)
+		}
+		if opts.Owner != nil {
+			record.OwnerTeam = opts.Owner(req.Mod.Path)
+		}
+
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records
+}
+
+// RepoURL derives the source repository URL for a module path
+func RepoURL(modulePath string) string {
+	parts := strings.Split(modulePath, "/")
+
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		if len(parts) >= 3 {
+			return "https://" + strings.Join(parts[:3], "/")
+		}
+
+	case "golang.org":
+		if len(parts) >= 3 && parts[1] == "x" {
+			return "https://go.googlesource.com/" + parts[2]
+		}
+
+	case "gopkg.in":
+		// gopkg.in/pkg.vN -> github.com/go-pkg/pkg, gopkg.in/user/pkg.vN -> github.com/user/pkg
+		if len(parts) == 2 {
+			name := trimGopkgVersion(parts[1])
+			return "https://github.com/go-" + name + "/" + name
+		}
+		if len(parts) >= 3 {
+			return "https://github.com/" + parts[1] + "/" + trimGopkgVersion(parts[2])
+		}
+	}
+
+	return "https://" + modulePath
+}
+
+func trimGopkgVersion(name string) string {
+	if idx := strings.LastIndex(name, ".v"); idx > 0 {
+		if _, err := strconv.Atoi(name[idx+2:]); err == nil {
+			return name[:idx]
+		}
+	}
+	return name
+}
+
+// WriteJSON writes records as an indented JSON array
+func WriteJSON(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(records); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes records as CSV with a header row
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(Fields); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	for _, r := range records {
+		row := []string{r.Name, r.Version, r.License, r.RepoURL, r.Hash, r.Relationship, r.OwnerTeam}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
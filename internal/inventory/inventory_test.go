@@ -0,0 +1,149 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omarshaarawi/gx/internal/modfile"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.24.2
+
+require (
+	golang.org/x/mod v0.14.0
+	github.com/spf13/cobra v1.10.1
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+`
+
+const testGoSum = `github.com/spf13/cobra v1.10.1 h1:cobrahash=
+golang.org/x/mod v0.14.0 h1:modhash=
+`
+
+func buildTestRecords(t *testing.T) []Record {
+	t.Helper()
+
+	dir := t.TempDir()
+	modPath := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(modPath, []byte(testGoMod), 0o644); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+
+	parser, err := modfile.NewParser(modPath)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	sums, err := modfile.ParseSumData([]byte(testGoSum))
+	if err != nil {
+		t.Fatalf("ParseSumData() error: %v", err)
+	}
+
+	return Build(parser, sums, Options{
+		Owner: func(path string) string {
+			if strings.HasPrefix(path, "golang.org/") {
+				return "platform"
+			}
+			return ""
+		},
+		License: func(path, version string) string { return "MIT" },
+	})
+}
+
+func TestBuild(t *testing.T) {
+	records := buildTestRecords(t)
+
+	if len(records) != 3 {
+		t.Fatalf("Build() returned %d records, want 3", len(records))
+	}
+
+	if records[0].Name != "github.com/spf13/cobra" {
+		t.Errorf("records should be sorted by name, first = %q", records[0].Name)
+	}
+
+	mod := records[1]
+	if mod.Name != "golang.org/x/mod" {
+		t.Fatalf("records[1].Name = %q", mod.Name)
+	}
+	if mod.Hash != "h1:modhash=" {
+		t.Errorf("Hash = %q", mod.Hash)
+	}
+	if mod.OwnerTeam != "platform" {
+		t.Errorf("OwnerTeam = %q, want platform", mod.OwnerTeam)
+	}
+	if mod.Relationship != "direct" {
+		t.Errorf("Relationship = %q, want direct", mod.Relationship)
+	}
+	if mod.RepoURL != "https://go.googlesource.com/mod" {
+		t.Errorf("RepoURL = %q", mod.RepoURL)
+	}
+
+	if records[2].Relationship != "indirect" {
+		t.Errorf("yaml.v3 Relationship = %q, want indirect", records[2].Relationship)
+	}
+	if records[2].Hash != "" {
+		t.Errorf("yaml.v3 Hash = %q, want empty", records[2].Hash)
+	}
+}
+
+func TestRepoURL(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"github.com/spf13/cobra", "https://github.com/spf13/cobra"},
+		{"github.com/jackc/pgx/v5", "https://github.com/jackc/pgx"},
+		{"golang.org/x/mod", "https://go.googlesource.com/mod"},
+		{"gopkg.in/yaml.v3", "https://github.com/go-yaml/yaml"},
+		{"gopkg.in/check.v1", "https://github.com/go-check/check"},
+		{"gopkg.in/alecthomas/kingpin.v2", "https://github.com/alecthomas/kingpin"},
+		{"go.uber.org/zap", "https://go.uber.org/zap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := RepoURL(tt.path); got != tt.want {
+				t.Errorf("RepoURL(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, buildTestRecords(t)); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("WriteCSV() wrote %d lines, want 4", len(lines))
+	}
+	if lines[0] != strings.Join(Fields, ",") {
+		t.Errorf("header = %q", lines[0])
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, buildTestRecords(t)); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	for _, field := range Fields {
+		if _, ok := decoded[0][field]; !ok {
+			t.Errorf("JSON record missing field %q", field)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package license
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Unknown is reported when no license file could be classified
+const Unknown = "UNKNOWN"
+
+var licenseFiles = []string{
+	"LICENSE",
+	"LICENSE.md",
+	"LICENSE.txt",
+	"LICENCE",
+	"COPYING",
+	"license",
+	"License",
+}
+
+// matcher identifies a license by phrases found in its text. The first
+// phrase is the license's name or opening words. When version is set, it
+// must be the version named right after them.
+type matcher struct {
+	id      string
+	phrases []string
+	version string
+}
+
+// Order matters: more specific licenses are checked first.
+var matchers = []matcher{
+	{id: "AGPL-3.0", phrases: []string{"gnu affero general public license"}, version: "3"},
+	{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license"}, version: "3"},
+	{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license"}},
+	{id: "LGPL-2.0", phrases: []string{"gnu library general public license"}},
+	{id: "GPL-3.0", phrases: []string{"gnu general public license"}, version: "3"},
+	{id: "GPL-2.0", phrases: []string{"gnu general public license"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+	{id: "CC0-1.0", phrases: []string{"cc0 1.0 universal"}},
+}
+
+// versionWindow is how far past a license's name its version is looked
+// for, enough to reach it in "the GNU General Public License as published
+// by the Free Software Foundation, either version 3"
+const versionWindow = 80
+
+// Identify classifies license text and returns an SPDX identifier. License
+// texts mention other licenses, as the GPL does the LGPL and AGPL, so the
+// license named first in the text is the one it is.
+func Identify(text string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text), " "))
+
+	id, first := Unknown, len(normalized)
+	for _, m := range matchers {
+		at := strings.Index(normalized, m.phrases[0])
+		if at < 0 || at >= first || !m.matches(normalized, at) {
+			continue
+		}
+		id, first = m.id, at
+	}
+	return id
+}
+
+// matches reports whether the text, which names m's license at index at,
+// has the rest of m's phrases and its version
+func (m matcher) matches(text string, at int) bool {
+	for _, phrase := range m.phrases[1:] {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return m.version == "" || strings.TrimSuffix(versionAfter(text[at+len(m.phrases[0]):]), ".0") == m.version
+}
+
+// versionAfter returns the number of the version named at the start of
+// text, such as "2.1" in " version 2.1, february 1999", or "" when there's
+// none
+func versionAfter(text string) string {
+	text = text[:min(len(text), versionWindow)]
+	_, rest, ok := strings.Cut(text, "version ")
+	if !ok {
+		return ""
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+	if end < 0 {
+		end = len(rest)
+	}
+	return strings.TrimRight(rest[:end], ".")
+}
+
+// DetectDir looks for a license file in dir and classifies it
+func DetectDir(dir string) string {
+	for _, name := range licenseFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		return Identify(string(data))
+	}
+	return Unknown
+}
+
+// Detect classifies the license of a module version from the local module cache
+func Detect(modulePath, version string) string {
+	dir, err := ModuleDir(modulePath, version)
+	if err != nil {
+		return Unknown
+	}
+	return DetectDir(dir)
+}
+
+// ModuleDir returns the extracted module directory in the module cache
+func ModuleDir(modulePath, version string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(modCacheDir(), escapedPath+"@"+escapedVersion), nil
+}
+
+func modCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+
+	if out, err := exec.Command("go", "env", "GOMODCACHE").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = filepath.Join(os.Getenv("HOME"), "go")
+	}
+	return filepath.Join(gopath, "pkg", "mod")
+}
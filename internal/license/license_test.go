@@ -0,0 +1,125 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "MIT",
+			text: "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy",
+			want: "MIT",
+		},
+		{
+			name: "Apache",
+			text: "Apache License\n                           Version 2.0, January 2004",
+			want: "Apache-2.0",
+		},
+		{
+			name: "BSD-3-Clause",
+			text: "Redistribution and use in source and binary forms, with or without\nmodification... Neither the name of Google Inc.",
+			want: "BSD-3-Clause",
+		},
+		{
+			name: "BSD-2-Clause",
+			text: "Redistribution and use in source and binary forms, with or without modification",
+			want: "BSD-2-Clause",
+		},
+		{
+			name: "MPL",
+			text: "Mozilla Public License Version 2.0",
+			want: "MPL-2.0",
+		},
+		{
+			name: "GPL-3.0",
+			text: "GNU GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007",
+			want: "GPL-3.0",
+		},
+		{
+			name: "GPL-2.0 notice",
+			text: "This program is free software; you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation; either version 2 of the License, or (at your option) any later version.",
+			want: "GPL-2.0",
+		},
+		{
+			name: "LGPL-3.0",
+			text: "GNU LESSER GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007\n\nThis version of the GNU Lesser General Public License incorporates the terms and conditions of version 3 of the GNU General Public License",
+			want: "LGPL-3.0",
+		},
+		{
+			name: "AGPL-3.0",
+			text: "GNU AFFERO GENERAL PUBLIC LICENSE\n Version 3, 19 November 2007",
+			want: "AGPL-3.0",
+		},
+		{
+			name: "MIT naming other licenses after its own",
+			text: "Permission is hereby granted, free of charge, to any person\n\nThird-party code under the Apache License, Version 2.0",
+			want: "MIT",
+		},
+		{
+			name: "unknown",
+			text: "All rights reserved.",
+			want: Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Identify(tt.text); got != tt.want {
+				t.Errorf("Identify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentify_FullTexts(t *testing.T) {
+	// The GPLs name the LGPL and AGPL, and the MPL names the GPLs
+	for _, id := range []string{"GPL-2.0", "GPL-3.0", "LGPL-2.1", "MPL-2.0"} {
+		t.Run(id, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", id+".txt"))
+			if err != nil {
+				t.Fatalf("reading license: %v", err)
+			}
+			if got := Identify(string(text)); got != id {
+				t.Errorf("Identify() = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+func TestDetectDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := DetectDir(dir); got != Unknown {
+		t.Errorf("DetectDir() on empty dir = %q, want %q", got, Unknown)
+	}
+
+	text := "Permission is hereby granted, free of charge, to any person"
+	if err := os.WriteFile(filepath.Join(dir, "LICENSE.md"), []byte(text), 0o644); err != nil {
+		t.Fatalf("writing license: %v", err)
+	}
+
+	if got := DetectDir(dir); got != "MIT" {
+		t.Errorf("DetectDir() = %q, want %q", got, "MIT")
+	}
+}
+
+func TestModuleDir(t *testing.T) {
+	t.Setenv("GOMODCACHE", "/cache")
+
+	dir, err := ModuleDir("github.com/BurntSushi/toml", "v1.3.2")
+	if err != nil {
+		t.Fatalf("ModuleDir() error: %v", err)
+	}
+
+	want := filepath.Join("/cache", "github.com/!burnt!sushi/toml@v1.3.2")
+	if dir != want {
+		t.Errorf("ModuleDir() = %q, want %q", dir, want)
+	}
+}
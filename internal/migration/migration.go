@@ -0,0 +1,107 @@
+// Package migration knows where abandoned modules moved to, from a curated
+// list shipped with gx and extended by the config, and from the deprecation
+// notices of the modules themselves.
+package migration
+
+import (
+	_ "embed"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"golang.org/x/mod/module"
+	"gopkg.in/yaml.v3"
+)
+
+// Migration describes how to move from one module to another
+type Migration struct {
+	Old string `yaml:"-"`
+	New string `yaml:"new"`
+
+	// Packages maps packages of Old whose counterparts in New don't share
+	// their path below the module, e.g. ptypes/any moving to types/known/anypb,
+	// or to "" when they have none and stay
+	Packages map[string]string `yaml:"packages"`
+
+	// Note says what the import rewrite leaves to do by hand
+	Note string `yaml:"note"`
+}
+
+//go:embed replacements.yaml
+var shipped []byte
+
+// known are the migrations shipped with gx, by old module path
+var known = func() map[string]Migration {
+	var m map[string]Migration
+	if err := yaml.Unmarshal(shipped, &m); err != nil {
+		panic("migration: parsing replacements.yaml: " + err.Error())
+	}
+	return m
+}()
+
+// extra are the migrations from the config, overriding the shipped ones
+var extra map[string]Migration
+
+// SetReplacements adds migrations to the shipped ones, replacing any for
+// the same module
+func SetReplacements(replacements map[string]Migration) {
+	extra = replacements
+}
+
+// Known returns the curated migration away from a module. A migration
+// without New only has advice, for a module that was abandoned.
+func Known(modulePath string) (Migration, bool) {
+	m, ok := extra[modulePath]
+	if !ok {
+		m, ok = known[modulePath]
+	}
+	if !ok {
+		return Migration{}, false
+	}
+	m.Old = modulePath
+	return m, true
+}
+
+// modulePathRE matches what looks like a module path in prose: a domain
+// followed by at least one path element
+var modulePathRE = regexp.MustCompile(`[a-z0-9][a-z0-9.-]*\.[a-z]{2,}(?:/[A-Za-z0-9._~-]+)+`)
+
+// FromDeprecation picks the module a deprecation notice points to, such as
+// google.golang.org/protobuf in `Use the "google.golang.org/protobuf" module
+// instead.` It skips mentions of the deprecated module itself.
+func FromDeprecation(modulePath, message string) (string, bool) {
+	for _, candidate := range modulePathRE.FindAllString(message, -1) {
+		candidate = strings.TrimRight(candidate, ".")
+		if candidate == modulePath || strings.HasPrefix(candidate, modulePath+"/") {
+			continue
+		}
+		if module.CheckPath(candidate) == nil {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Suggest returns the migration to suggest for a module: the curated one,
+// else the module its deprecation notice names, if any
+func Suggest(modulePath, deprecated string) (Migration, bool) {
+	if m, ok := Known(modulePath); ok {
+		return m, true
+	}
+	if moved, ok := FromDeprecation(modulePath, deprecated); ok {
+		return Migration{Old: modulePath, New: moved}, true
+	}
+	return Migration{}, false
+}
+
+// Moves returns the import path moves of the migration, the package moves
+// before the module one
+func (m Migration) Moves() []importpath.Move {
+	var moves []importpath.Move
+	for old, moved := range m.Packages {
+		moves = append(moves, importpath.Move{Old: old, New: moved})
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].Old < moves[j].Old })
+	return append(moves, importpath.Move{Old: m.Old, New: m.New})
+}
@@ -0,0 +1,94 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/omarshaarawi/gx/internal/importpath"
+	"golang.org/x/mod/module"
+)
+
+func TestKnown(t *testing.T) {
+	m, ok := Known("github.com/golang/protobuf")
+	if !ok {
+		t.Fatal("Known() has no migration for github.com/golang/protobuf")
+	}
+	if m.Old != "github.com/golang/protobuf" || m.New != "google.golang.org/protobuf" {
+		t.Errorf("Known() = %s → %s", m.Old, m.New)
+	}
+
+	moves := m.Moves()
+	if last := moves[len(moves)-1]; last != (importpath.Move{Old: m.Old, New: m.New}) {
+		t.Errorf("last move = %+v, want the module move", last)
+	}
+
+	if _, ok := Known("github.com/spf13/cobra"); ok {
+		t.Error("Known() has a migration for github.com/spf13/cobra")
+	}
+}
+
+func TestKnown_Shipped(t *testing.T) {
+	for old, m := range known {
+		if err := module.CheckPath(old); err != nil {
+			t.Errorf("%s: %v", old, err)
+		}
+		if m.New == "" && m.Note == "" {
+			t.Errorf("%s has neither a replacement nor a note", old)
+		}
+		if m.New != "" {
+			if err := module.CheckPath(m.New); err != nil {
+				t.Errorf("%s: %v", old, err)
+			}
+		}
+	}
+}
+
+func TestSetReplacements(t *testing.T) {
+	t.Cleanup(func() { SetReplacements(nil) })
+	SetReplacements(map[string]Migration{
+		"github.com/golang/mock":  {New: "example.com/mock"},
+		"github.com/acme/retired": {Note: "use the standard library"},
+	})
+
+	if m, _ := Known("github.com/golang/mock"); m.New != "example.com/mock" {
+		t.Errorf("Known(github.com/golang/mock).New = %q, want the configured example.com/mock", m.New)
+	}
+	if m, ok := Known("github.com/acme/retired"); !ok || m.Old != "github.com/acme/retired" || m.New != "" {
+		t.Errorf("Known(github.com/acme/retired) = %+v, %v", m, ok)
+	}
+	if _, ok := Known("github.com/satori/go.uuid"); !ok {
+		t.Error("configured replacements hid the shipped ones")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	if m, ok := Suggest("github.com/satori/go.uuid", ""); !ok || m.New != "github.com/gofrs/uuid" {
+		t.Errorf("Suggest(github.com/satori/go.uuid) = %+v, %v, want the curated migration", m, ok)
+	}
+	if m, ok := Suggest("github.com/acme/old", "use example.com/new"); !ok || m.Old != "github.com/acme/old" || m.New != "example.com/new" {
+		t.Errorf("Suggest(github.com/acme/old) = %+v, %v, want the module the notice names", m, ok)
+	}
+	if _, ok := Suggest("github.com/acme/old", ""); ok {
+		t.Error("Suggest() found a migration for a module without one")
+	}
+}
+
+func TestFromDeprecation(t *testing.T) {
+	tests := []struct {
+		module  string
+		message string
+		want    string
+	}{
+		{"github.com/golang/protobuf", `Use the "google.golang.org/protobuf" module instead.`, "google.golang.org/protobuf"},
+		{"github.com/acme/old", "moved to github.com/acme/new/v2.", "github.com/acme/new/v2"},
+		{"github.com/acme/old", "github.com/acme/old/v2 is gone too, use example.com/lib", "example.com/lib"},
+		{"github.com/acme/old", "no longer maintained", ""},
+		{"github.com/acme/old", "see https://github.com/acme/old/issues/1", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := FromDeprecation(tt.module, tt.message)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("FromDeprecation(%q, %q) = %q, %v, want %q", tt.module, tt.message, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package modfile
+
+import (
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// AnnotationPrefix marks the part of a require line comment managed by gx
+const AnnotationPrefix = "gx:"
+
+// Annotation returns the gx annotation on a require line, if any
+func Annotation(req *modfile.Require) string {
+	comment, ok := suffixComment(req)
+	if !ok {
+		return ""
+	}
+	_, note, found := cutAnnotation(comment)
+	if !found {
+		return ""
+	}
+	return note
+}
+
+// SetAnnotation writes a gx annotation after a require line, replacing any previous one.
+// Existing comments such as "// indirect" are kept in front of it.
+func SetAnnotation(req *modfile.Require, note string) {
+	comment, _ := suffixComment(req)
+	rest, _, _ := cutAnnotation(comment)
+
+	text := AnnotationPrefix + " " + note
+	if rest != "" {
+		text = rest + "; " + text
+	}
+	setSuffixComment(req, text)
+}
+
+// ClearAnnotation removes the gx annotation from a require line.
+// It reports whether there was one to remove.
+func ClearAnnotation(req *modfile.Require) bool {
+	comment, ok := suffixComment(req)
+	if !ok {
+		return false
+	}
+	rest, _, found := cutAnnotation(comment)
+	if !found {
+		return false
+	}
+	setSuffixComment(req, rest)
+	return true
+}
+
+// cutAnnotation splits a comment body into the user-owned part and the gx note
+func cutAnnotation(comment string) (rest, note string, found bool) {
+	if n, ok := strings.CutPrefix(comment, AnnotationPrefix); ok {
+		return "", strings.TrimSpace(n), true
+	}
+	if before, n, ok := strings.Cut(comment, "; "+AnnotationPrefix); ok {
+		return strings.TrimSpace(before), strings.TrimSpace(n), true
+	}
+	return comment, "", false
+}
+
+func suffixComment(req *modfile.Require) (string, bool) {
+	if req.Syntax == nil || len(req.Syntax.Suffix) == 0 {
+		return "", false
+	}
+	token := req.Syntax.Suffix[0].Token
+	return strings.TrimSpace(strings.TrimPrefix(token, "//")), true
+}
+
+func setSuffixComment(req *modfile.Require, text string) {
+	if req.Syntax == nil {
+		return
+	}
+	if text == "" {
+		req.Syntax.Suffix = nil
+		return
+	}
+	comment := modfile.Comment{Token: "// " + text, Suffix: true}
+	if len(req.Syntax.Suffix) == 0 {
+		req.Syntax.Suffix = []modfile.Comment{comment}
+		return
+	}
+	req.Syntax.Suffix[0] = comment
+}
@@ -0,0 +1,94 @@
+package modfile
+
+import (
+	"strings"
+	"testing"
+)
+
+const annotateTestGoMod = `module omarshaarawi/annotate
+
+go 1.24.2
+
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/stretchr/testify v1.8.4 // pinned for CI
+	golang.org/x/mod v0.14.0 // indirect
+)
+`
+
+func TestAnnotations_RoundTrip(t *testing.T) {
+	tmpFile := createTempGoMod(t, annotateTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	for _, req := range parser.AllRequires() {
+		SetAnnotation(req, "v9.9.9 available (major)")
+	}
+	// Setting twice replaces rather than stacks
+	SetAnnotation(parser.FindRequire("golang.org/x/mod"), "v0.20.0 available (minor)")
+
+	writer := NewWriter(parser)
+	if err := writer.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	annotated, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() after annotate error: %v", err)
+	}
+
+	content := string(annotated.data)
+	for _, want := range []string{
+		"github.com/spf13/cobra v1.8.0 // gx: v9.9.9 available (major)",
+		"github.com/stretchr/testify v1.8.4 // pinned for CI; gx: v9.9.9 available (major)",
+		"golang.org/x/mod v0.14.0 // indirect; gx: v0.20.0 available (minor)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("annotated go.mod missing %q:\n%s", want, content)
+		}
+	}
+
+	mod := annotated.FindRequire("golang.org/x/mod")
+	if !mod.Indirect {
+		t.Error("annotating an indirect require should keep it indirect")
+	}
+	if got := Annotation(mod); got != "v0.20.0 available (minor)" {
+		t.Errorf("Annotation() = %q", got)
+	}
+
+	cleared := 0
+	for _, req := range annotated.AllRequires() {
+		if ClearAnnotation(req) {
+			cleared++
+		}
+	}
+	if cleared != 3 {
+		t.Errorf("ClearAnnotation() cleared %d lines, want 3", cleared)
+	}
+
+	data, err := NewWriter(annotated).Format()
+	if err != nil {
+		t.Fatalf("Format() error: %v", err)
+	}
+	if string(data) != annotateTestGoMod {
+		t.Errorf("clean did not restore original go.mod:\n%s", data)
+	}
+}
+
+func TestClearAnnotation_NoAnnotation(t *testing.T) {
+	tmpFile := createTempGoMod(t, annotateTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	req := parser.FindRequire("github.com/stretchr/testify")
+	if ClearAnnotation(req) {
+		t.Error("ClearAnnotation() should report false without a gx annotation")
+	}
+	if Annotation(req) != "" {
+		t.Error("Annotation() should be empty for a plain comment")
+	}
+}
@@ -0,0 +1,290 @@
+package modfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/versions"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Git conflict markers. The base marker only appears with merge.conflictStyle=diff3.
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// Resolution records how a requirement that differed between the two sides
+// of a merge was resolved. Ours or Theirs is empty when only one side had it.
+type Resolution struct {
+	Path   string
+	Ours   string
+	Theirs string
+	Chosen string
+}
+
+// HasConflictMarkers reports whether data contains git merge conflict markers
+func HasConflictMarkers(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), markerOurs) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitConflict returns the two sides of a file containing git conflict
+// markers. Lines outside conflicts are part of both sides; diff3 base
+// sections are dropped.
+func SplitConflict(data []byte) (ours, theirs []byte, err error) {
+	const (
+		stateCommon = iota
+		stateOurs
+		stateBase
+		stateTheirs
+	)
+
+	var oursBuf, theirsBuf bytes.Buffer
+	state := stateCommon
+	lineNo := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, markerOurs):
+			if state != stateCommon {
+				return nil, nil, fmt.Errorf("line %d: nested conflict marker", lineNo)
+			}
+			state = stateOurs
+			continue
+		case strings.HasPrefix(line, markerBase) && state == stateOurs:
+			state = stateBase
+			continue
+		case line == markerSplit && (state == stateOurs || state == stateBase):
+			state = stateTheirs
+			continue
+		case strings.HasPrefix(line, markerTheirs):
+			if state != stateTheirs {
+				return nil, nil, fmt.Errorf("line %d: unexpected %s", lineNo, markerTheirs)
+			}
+			state = stateCommon
+			continue
+		}
+
+		switch state {
+		case stateCommon:
+			oursBuf.WriteString(line + "\n")
+			theirsBuf.WriteString(line + "\n")
+		case stateOurs:
+			oursBuf.WriteString(line + "\n")
+		case stateTheirs:
+			theirsBuf.WriteString(line + "\n")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("scanning: %w", err)
+	}
+	if state != stateCommon {
+		return nil, nil, fmt.Errorf("unterminated conflict starting before line %d", lineNo+1)
+	}
+
+	return oursBuf.Bytes(), theirsBuf.Bytes(), nil
+}
+
+// ResolveConflict merges both sides of a conflicted go.mod the way minimal
+// version selection would: every requirement from either side is kept at
+// the higher of its two versions, and a module is direct if either side
+// requires it directly. The go and toolchain lines take the higher version,
+// and excludes, replacements and retractions are combined, preferring ours
+// when both sides replace the same module. The result is formatted with
+// requires organized as by Writer.Organize.
+func ResolveConflict(path string, data []byte) ([]byte, []Resolution, error) {
+	oursData, theirsData, err := SplitConflict(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ours, err := modfile.Parse(path, oursData, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing our side: %w", err)
+	}
+	theirs, err := modfile.Parse(path, theirsData, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing their side: %w", err)
+	}
+
+	if theirs.Go != nil && (ours.Go == nil || versions.CompareGo(theirs.Go.Version, ours.Go.Version) > 0) {
+		if err := ours.AddGoStmt(theirs.Go.Version); err != nil {
+			return nil, nil, err
+		}
+	}
+	if theirs.Toolchain != nil && (ours.Toolchain == nil || versions.CompareGo(theirs.Toolchain.Name, ours.Toolchain.Name) > 0) {
+		if err := ours.AddToolchainStmt(theirs.Toolchain.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	requires, resolutions := mergeRequires(ours.Require, theirs.Require)
+	ours.SetRequireSeparateIndirect(requires)
+
+	for _, ex := range theirs.Exclude {
+		if err := ours.AddExclude(ex.Mod.Path, ex.Mod.Version); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, rep := range theirs.Replace {
+		if hasReplace(ours.Replace, rep.Old.Path, rep.Old.Version) {
+			continue
+		}
+		if err := ours.AddReplace(rep.Old.Path, rep.Old.Version, rep.New.Path, rep.New.Version); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, ret := range theirs.Retract {
+		if hasRetract(ours.Retract, ret.VersionInterval) {
+			continue
+		}
+		if err := ours.AddRetract(ret.VersionInterval, ret.Rationale); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ours.Cleanup()
+	merged, err := ours.Format()
+	if err != nil {
+		return nil, nil, fmt.Errorf("formatting merged go.mod: %w", err)
+	}
+
+	// Lay the requires out the same way gx fmt does
+	parser := &Parser{path: path}
+	if parser.file, err = modfile.Parse(path, merged, nil); err != nil {
+		return nil, nil, fmt.Errorf("parsing merged go.mod: %w", err)
+	}
+	writer := NewWriter(parser)
+	if err := writer.Organize(); err != nil {
+		return nil, nil, err
+	}
+	merged, err = writer.Format()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, resolutions, nil
+}
+
+// mergeRequires combines two requirement lists, keeping the higher version of each module
+func mergeRequires(ours, theirs []*modfile.Require) ([]*modfile.Require, []Resolution) {
+	byPath := make(map[string]*modfile.Require, len(ours))
+	var order []string
+	for _, r := range ours {
+		if _, seen := byPath[r.Mod.Path]; !seen {
+			order = append(order, r.Mod.Path)
+		}
+		byPath[r.Mod.Path] = &modfile.Require{Mod: r.Mod, Indirect: r.Indirect}
+	}
+
+	var resolutions []Resolution
+	for _, r := range theirs {
+		mine, ok := byPath[r.Mod.Path]
+		if !ok {
+			byPath[r.Mod.Path] = &modfile.Require{Mod: r.Mod, Indirect: r.Indirect}
+			order = append(order, r.Mod.Path)
+			resolutions = append(resolutions, Resolution{Path: r.Mod.Path, Theirs: r.Mod.Version, Chosen: r.Mod.Version})
+			continue
+		}
+
+		mine.Indirect = mine.Indirect && r.Indirect
+		if mine.Mod.Version == r.Mod.Version {
+			continue
+		}
+
+		chosen := mine.Mod.Version
+		if semver.Compare(r.Mod.Version, chosen) > 0 {
+			chosen = r.Mod.Version
+		}
+		resolutions = append(resolutions, Resolution{Path: r.Mod.Path, Ours: mine.Mod.Version, Theirs: r.Mod.Version, Chosen: chosen})
+		mine.Mod.Version = chosen
+	}
+
+	ourPaths := make(map[string]bool, len(ours))
+	for _, r := range ours {
+		ourPaths[r.Mod.Path] = true
+	}
+	theirPaths := make(map[string]bool, len(theirs))
+	for _, r := range theirs {
+		theirPaths[r.Mod.Path] = true
+	}
+	for _, r := range ours {
+		if !theirPaths[r.Mod.Path] {
+			resolutions = append(resolutions, Resolution{Path: r.Mod.Path, Ours: r.Mod.Version, Chosen: r.Mod.Version})
+		}
+	}
+
+	merged := make([]*modfile.Require, 0, len(order))
+	for _, path := range order {
+		merged = append(merged, byPath[path])
+	}
+
+	sort.Slice(resolutions, func(i, j int) bool {
+		return resolutions[i].Path < resolutions[j].Path
+	})
+	return merged, resolutions
+}
+
+func hasReplace(replaces []*modfile.Replace, path, version string) bool {
+	for _, r := range replaces {
+		if r.Old.Path == path && r.Old.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRetract(retracts []*modfile.Retract, vi modfile.VersionInterval) bool {
+	for _, r := range retracts {
+		if r.VersionInterval == vi {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSumConflict merges both sides of a conflicted go.sum by keeping
+// every entry from either side; go mod tidy drops the ones no longer needed
+func ResolveSumConflict(data []byte) ([]byte, error) {
+	ours, theirs, err := SplitConflict(data)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var lines []string
+	for _, side := range [][]byte{ours, theirs} {
+		for _, line := range strings.Split(string(side), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || seen[line] {
+				continue
+			}
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+
+	merged := []byte(strings.Join(lines, "\n") + "\n")
+	if _, err := ParseSumData(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
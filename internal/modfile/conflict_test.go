@@ -0,0 +1,202 @@
+package modfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const conflictGoMod = `module example.com/app
+
+go 1.22
+
+require (
+<<<<<<< HEAD
+	github.com/spf13/cobra v1.9.0
+	golang.org/x/mod v0.14.0
+||||||| base
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/mod v0.14.0
+=======
+	github.com/spf13/cobra v1.8.1
+	golang.org/x/mod v0.14.0
+	golang.org/x/text v0.20.0
+>>>>>>> feature
+)
+
+<<<<<<< HEAD
+require github.com/spf13/pflag v1.0.5 // indirect
+=======
+require (
+	github.com/spf13/pflag v1.0.6 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+)
+>>>>>>> feature
+`
+
+func TestHasConflictMarkers(t *testing.T) {
+	if !HasConflictMarkers([]byte(conflictGoMod)) {
+		t.Error("HasConflictMarkers() = false for a conflicted file")
+	}
+	if HasConflictMarkers([]byte(writerTestGoMod)) {
+		t.Error("HasConflictMarkers() = true for a clean file")
+	}
+}
+
+func TestSplitConflict(t *testing.T) {
+	ours, theirs, err := SplitConflict([]byte(conflictGoMod))
+	if err != nil {
+		t.Fatalf("SplitConflict() error: %v", err)
+	}
+
+	if !strings.Contains(string(ours), "cobra v1.9.0") || strings.Contains(string(ours), "cobra v1.8.1") {
+		t.Errorf("ours side is wrong:\n%s", ours)
+	}
+	if !strings.Contains(string(theirs), "cobra v1.8.1") || strings.Contains(string(theirs), "cobra v1.9.0") {
+		t.Errorf("theirs side is wrong:\n%s", theirs)
+	}
+	if strings.Contains(string(ours)+string(theirs), "v1.8.0") {
+		t.Error("diff3 base section should be dropped")
+	}
+	if HasConflictMarkers(ours) || HasConflictMarkers(theirs) {
+		t.Error("markers left in split output")
+	}
+}
+
+func TestSplitConflict_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"unterminated": "<<<<<<< HEAD\na\n=======\nb\n",
+		"stray end":    "a\n>>>>>>> feature\n",
+		"nested":       "<<<<<<< HEAD\n<<<<<<< HEAD\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := SplitConflict([]byte(input)); err == nil {
+				t.Error("SplitConflict() expected error")
+			}
+		})
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	merged, resolutions, err := ResolveConflict("go.mod", []byte(conflictGoMod))
+	if err != nil {
+		t.Fatalf("ResolveConflict() error: %v", err)
+	}
+
+	want := `module example.com/app
+
+go 1.22
+
+require (
+	github.com/spf13/cobra v1.9.0
+	golang.org/x/mod v0.14.0
+	golang.org/x/text v0.20.0
+)
+
+require (
+	github.com/spf13/pflag v1.0.6 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+)
+`
+	if string(merged) != want {
+		t.Errorf("ResolveConflict() produced:\n%s\nwant:\n%s", merged, want)
+	}
+
+	wantResolutions := []Resolution{
+		{Path: "github.com/spf13/cobra", Ours: "v1.9.0", Theirs: "v1.8.1", Chosen: "v1.9.0"},
+		{Path: "github.com/spf13/pflag", Ours: "v1.0.5", Theirs: "v1.0.6", Chosen: "v1.0.6"},
+		{Path: "github.com/stretchr/testify", Theirs: "v1.9.0", Chosen: "v1.9.0"},
+		{Path: "golang.org/x/text", Theirs: "v0.20.0", Chosen: "v0.20.0"},
+	}
+	if !reflect.DeepEqual(resolutions, wantResolutions) {
+		t.Errorf("resolutions = %+v, want %+v", resolutions, wantResolutions)
+	}
+}
+
+func TestResolveConflict_DirectWinsAndGoVersion(t *testing.T) {
+	input := `module example.com/app
+
+<<<<<<< HEAD
+go 1.22
+
+require golang.org/x/mod v0.14.0 // indirect
+
+replace example.com/lib => ../lib
+=======
+go 1.23.1
+
+require golang.org/x/mod v0.14.0
+
+replace example.com/lib => ../other
+replace example.com/tool => ../tool
+>>>>>>> feature
+`
+	merged, _, err := ResolveConflict("go.mod", []byte(input))
+	if err != nil {
+		t.Fatalf("ResolveConflict() error: %v", err)
+	}
+
+	got := string(merged)
+	for _, want := range []string{"go 1.23.1", "require golang.org/x/mod v0.14.0\n", "example.com/lib => ../lib", "example.com/tool => ../tool"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged go.mod missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "../other") {
+		t.Errorf("our replacement should win:\n%s", got)
+	}
+}
+
+func TestResolveConflict_GoPrerelease(t *testing.T) {
+	input := `module example.com/app
+
+<<<<<<< HEAD
+go 1.22rc1
+
+toolchain go1.22rc2
+=======
+go 1.21.5
+
+toolchain go1.21.6
+>>>>>>> feature
+`
+	merged, _, err := ResolveConflict("go.mod", []byte(input))
+	if err != nil {
+		t.Fatalf("ResolveConflict() error: %v", err)
+	}
+
+	got := string(merged)
+	for _, want := range []string{"go 1.22rc1", "toolchain go1.22rc2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged go.mod missing %q, the newer release candidate:\n%s", want, got)
+		}
+	}
+}
+
+func TestResolveSumConflict(t *testing.T) {
+	input := `github.com/a/b v1.0.0 h1:aaa=
+<<<<<<< HEAD
+github.com/c/d v1.1.0 h1:ccc=
+github.com/c/d v1.1.0/go.mod h1:ccm=
+=======
+github.com/c/d v1.2.0 h1:ddd=
+github.com/c/d v1.2.0/go.mod h1:ddm=
+>>>>>>> feature
+`
+	merged, err := ResolveSumConflict([]byte(input))
+	if err != nil {
+		t.Fatalf("ResolveSumConflict() error: %v", err)
+	}
+
+	sums, err := ParseSumData(merged)
+	if err != nil {
+		t.Fatalf("ParseSumData() error: %v", err)
+	}
+	if sums.Len() != 5 {
+		t.Errorf("merged go.sum has %d entries, want 5:\n%s", sums.Len(), merged)
+	}
+	if sums.Hash("github.com/c/d", "v1.2.0") != "h1:ddd=" {
+		t.Error("entry from their side missing")
+	}
+}
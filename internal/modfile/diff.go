@@ -0,0 +1,101 @@
+package modfile
+
+import (
+	"sort"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// RequireChange describes how a requirement differs between two go.mod files.
+// From is empty for added requirements and To is empty for removed ones.
+type RequireChange struct {
+	Path     string
+	From     string
+	To       string
+	Indirect bool
+}
+
+// Kind returns "added", "removed", or "changed"
+func (c RequireChange) Kind() string {
+	switch {
+	case c.From == "":
+		return "added"
+	case c.To == "":
+		return "removed"
+	default:
+		return "changed"
+	}
+}
+
+// CloneRequires copies requirements so later edits to the file don't affect them
+func CloneRequires(requires []*modfile.Require) []*modfile.Require {
+	cloned := make([]*modfile.Require, len(requires))
+	for i, req := range requires {
+		c := *req
+		cloned[i] = &c
+	}
+	return cloned
+}
+
+// DiffRequires compares two requirement lists, sorted by module path
+func DiffRequires(before, after []*modfile.Require) []RequireChange {
+	old := make(map[string]*modfile.Require, len(before))
+	for _, req := range before {
+		old[req.Mod.Path] = req
+	}
+
+	var changes []RequireChange
+	seen := make(map[string]bool, len(after))
+
+	for _, req := range after {
+		seen[req.Mod.Path] = true
+		prev, ok := old[req.Mod.Path]
+		switch {
+		case !ok:
+			changes = append(changes, RequireChange{Path: req.Mod.Path, To: req.Mod.Version, Indirect: req.Indirect})
+		case prev.Mod.Version != req.Mod.Version:
+			changes = append(changes, RequireChange{Path: req.Mod.Path, From: prev.Mod.Version, To: req.Mod.Version, Indirect: req.Indirect})
+		}
+	}
+
+	for _, req := range before {
+		if !seen[req.Mod.Path] {
+			changes = append(changes, RequireChange{Path: req.Mod.Path, From: req.Mod.Version, Indirect: req.Indirect})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// UpgradeImpact lists the requirements a dependency's new go.mod would add to
+// go.mod or raise, given the current requirements. Minimal version selection
+// keeps the higher of two versions, so lower requirements change nothing.
+// Added requirements are indirect, since nothing in the module imports them.
+func UpgradeImpact(have, requires []*modfile.Require) []RequireChange {
+	current := make(map[string]*modfile.Require, len(have))
+	for _, req := range have {
+		current[req.Mod.Path] = req
+	}
+
+	var changes []RequireChange
+	for _, req := range requires {
+		prev, ok := current[req.Mod.Path]
+		switch {
+		case !ok:
+			changes = append(changes, RequireChange{Path: req.Mod.Path, To: req.Mod.Version, Indirect: true})
+		case semver.Compare(req.Mod.Version, prev.Mod.Version) > 0:
+			changes = append(changes, RequireChange{Path: req.Mod.Path, From: prev.Mod.Version, To: req.Mod.Version, Indirect: prev.Indirect})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
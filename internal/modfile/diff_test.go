@@ -0,0 +1,126 @@
+package modfile
+
+import (
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func TestDiffRequires(t *testing.T) {
+	before, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+require (
+	example.com/changed v1.2.0
+	example.com/removed v1.0.0
+	example.com/same v1.0.0
+)
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+require (
+	example.com/added v0.1.0 // indirect
+	example.com/changed v1.1.0
+	example.com/same v1.0.0
+)
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := DiffRequires(before.Require, after.Require)
+
+	want := []RequireChange{
+		{Path: "example.com/added", To: "v0.1.0", Indirect: true},
+		{Path: "example.com/changed", From: "v1.2.0", To: "v1.1.0"},
+		{Path: "example.com/removed", From: "v1.0.0"},
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("DiffRequires() returned %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	kinds := []string{"added", "changed", "removed"}
+	for i, kind := range kinds {
+		if changes[i].Kind() != kind {
+			t.Errorf("changes[%d].Kind() = %q, want %q", i, changes[i].Kind(), kind)
+		}
+	}
+}
+
+func TestDiffRequires_NoChanges(t *testing.T) {
+	f, err := modfile.Parse("go.mod", []byte("module example.com/app\n\nrequire example.com/a v1.0.0\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := DiffRequires(f.Require, f.Require); len(changes) != 0 {
+		t.Errorf("DiffRequires() = %+v, want none", changes)
+	}
+}
+
+func TestCloneRequires(t *testing.T) {
+	f, err := modfile.Parse("go.mod", []byte("module example.com/app\n\nrequire example.com/a v1.0.0\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloned := CloneRequires(f.Require)
+	if err := f.AddRequire("example.com/a", "v2.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cloned[0].Mod.Version != "v1.0.0" {
+		t.Errorf("cloned version = %q, want v1.0.0", cloned[0].Mod.Version)
+	}
+}
+
+func TestUpgradeImpact(t *testing.T) {
+	have, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+require (
+	example.com/dep v1.0.0
+	example.com/lower v1.5.0 // indirect
+	example.com/raised v1.1.0 // indirect
+)
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := modfile.Parse("go.mod", []byte(`module example.com/dep
+
+require (
+	example.com/lower v1.2.0
+	example.com/new v0.3.0
+	example.com/raised v1.4.0
+)
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := UpgradeImpact(have.Require, target.Require)
+
+	want := []RequireChange{
+		{Path: "example.com/new", To: "v0.3.0", Indirect: true},
+		{Path: "example.com/raised", From: "v1.1.0", To: "v1.4.0", Indirect: true},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("UpgradeImpact() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
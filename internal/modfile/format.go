@@ -0,0 +1,117 @@
+package modfile
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Organize rewrites the require directives as a sorted block of direct
+// requirements followed by a sorted block of indirect requirements, and sorts
+// the exclude, replace and retract blocks. Comments attached to a require
+// line move with it; comments above a require block are kept above the new
+// blocks. Requirements callers hold move to the new lines too. Call Write
+// or SafeWrite to persist the result.
+func (w *Writer) Organize() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	indirect := make(map[*modfile.Line]bool, len(p.file.Require))
+	for _, req := range p.file.Require {
+		indirect[req.Syntax] = req.Indirect
+	}
+
+	var direct, indirectLines []*modfile.Line
+	var blockComments []modfile.Comment
+	var kept []modfile.Expr
+	insertAt := -1
+
+	collect := func(line *modfile.Line, tokens []string) {
+		moved := &modfile.Line{Comments: line.Comments, Token: tokens, InBlock: true}
+		if indirect[line] {
+			indirectLines = append(indirectLines, moved)
+		} else {
+			direct = append(direct, moved)
+		}
+	}
+
+	for _, stmt := range p.file.Syntax.Stmt {
+		switch x := stmt.(type) {
+		case *modfile.Line:
+			if len(x.Token) > 0 && x.Token[0] == "require" {
+				if insertAt < 0 {
+					insertAt = len(kept)
+				}
+				collect(x, x.Token[1:])
+				continue
+			}
+		case *modfile.LineBlock:
+			if len(x.Token) > 0 && x.Token[0] == "require" {
+				if insertAt < 0 {
+					insertAt = len(kept)
+				}
+				blockComments = append(blockComments, x.Before...)
+				for _, line := range x.Line {
+					collect(line, line.Token)
+				}
+				continue
+			}
+		}
+		kept = append(kept, stmt)
+	}
+
+	if insertAt < 0 {
+		p.file.SortBlocks()
+		return nil
+	}
+
+	var blocks []modfile.Expr
+	for _, lines := range [][]*modfile.Line{direct, indirectLines} {
+		if block := requireBlock(lines); block != nil {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blockComments) > 0 {
+		blocks[0].Comment().Before = append(blockComments, blocks[0].Comment().Before...)
+	}
+
+	stmts := make([]modfile.Expr, 0, len(kept)+len(blocks))
+	stmts = append(stmts, kept[:insertAt]...)
+	stmts = append(stmts, blocks...)
+	stmts = append(stmts, kept[insertAt:]...)
+	p.file.Syntax.Stmt = stmts
+	p.file.SortBlocks()
+
+	// The require entries still point at the old syntax, so re-parse
+	data := modfile.Format(p.file.Syntax)
+	file, err := modfile.Parse(p.path, data, nil)
+	if err != nil {
+		return fmt.Errorf("parsing organized go.mod: %w", err)
+	}
+	p.replaceFile(file)
+	return nil
+}
+
+// requireBlock returns lines sorted by module path, as a single require line
+// when there is only one, or nil when there are none
+func requireBlock(lines []*modfile.Line) modfile.Expr {
+	switch len(lines) {
+	case 0:
+		return nil
+	case 1:
+		line := lines[0]
+		line.Token = append([]string{"require"}, line.Token...)
+		line.InBlock = false
+		return line
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Token[0] < lines[j].Token[0]
+	})
+	return &modfile.LineBlock{Token: []string{"require"}, Line: lines}
+}
@@ -0,0 +1,145 @@
+package modfile
+
+import (
+	"testing"
+)
+
+func TestWriter_Organize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "mixed blocks",
+			input: `module omarshaarawi/format
+
+go 1.24.2
+
+require golang.org/x/text v0.14.0 // indirect
+
+// tooling
+require (
+	github.com/stretchr/testify v1.8.4 // pinned for CI
+	golang.org/x/mod v0.14.0 // indirect
+	github.com/spf13/cobra   v1.8.0
+)
+
+replace example.com/b => ../b
+replace example.com/a => ../a
+`,
+			want: `module omarshaarawi/format
+
+go 1.24.2
+
+// tooling
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/stretchr/testify v1.8.4 // pinned for CI
+)
+
+require (
+	golang.org/x/mod v0.14.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace example.com/b => ../b
+
+replace example.com/a => ../a
+`,
+		},
+		{
+			name: "single entries stay on one line",
+			input: `module omarshaarawi/format
+
+go 1.24.2
+
+require (
+	golang.org/x/mod v0.14.0 // indirect
+	github.com/spf13/cobra v1.8.0
+)
+`,
+			want: `module omarshaarawi/format
+
+go 1.24.2
+
+require github.com/spf13/cobra v1.8.0
+
+require golang.org/x/mod v0.14.0 // indirect
+`,
+		},
+		{
+			name: "sorts blocks",
+			input: `module omarshaarawi/format
+
+go 1.24.2
+
+exclude (
+	example.com/z v1.0.0
+	example.com/a v1.0.0
+)
+`,
+			want: `module omarshaarawi/format
+
+go 1.24.2
+
+exclude (
+	example.com/a v1.0.0
+	example.com/z v1.0.0
+)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewParser(createTempGoMod(t, tt.input))
+			if err != nil {
+				t.Fatalf("NewParser() error: %v", err)
+			}
+
+			writer := NewWriter(parser)
+			if err := writer.Organize(); err != nil {
+				t.Fatalf("Organize() error: %v", err)
+			}
+
+			got, err := writer.Format()
+			if err != nil {
+				t.Fatalf("Format() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Organize() produced:\n%s\nwant:\n%s", got, tt.want)
+			}
+
+			// The parsed requirements must reflect the new layout
+			if req := parser.FindRequire("github.com/spf13/cobra"); req != nil && req.Indirect {
+				t.Error("github.com/spf13/cobra should stay direct")
+			}
+			if req := parser.FindRequire("golang.org/x/mod"); req != nil && !req.Indirect {
+				t.Error("golang.org/x/mod should stay indirect")
+			}
+		})
+	}
+}
+
+func TestWriter_OrganizeIdempotent(t *testing.T) {
+	parser, err := NewParser(createTempGoMod(t, annotateTestGoMod))
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	if err := writer.Organize(); err != nil {
+		t.Fatalf("Organize() error: %v", err)
+	}
+	first, _ := writer.Format()
+
+	if err := writer.Organize(); err != nil {
+		t.Fatalf("second Organize() error: %v", err)
+	}
+	second, _ := writer.Format()
+
+	if string(first) != string(second) {
+		t.Errorf("Organize() is not idempotent:\n%s\nthen:\n%s", first, second)
+	}
+}
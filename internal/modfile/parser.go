@@ -1,19 +1,31 @@
 package modfile
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
-// Parser wraps golang modfile with additional utilities
+// Parser wraps golang modfile with additional utilities.
+// It is safe for concurrent use with a Writer on the same file.
 type Parser struct {
+	mu   sync.RWMutex
 	path string
 	file *modfile.File
 	data []byte
+	// sum is the hash of the content last read from or written to disk
+	sum [sha256.Size]byte
 }
 
+// ErrModifiedOnDisk is returned when go.mod changed on disk after it was read
+var ErrModifiedOnDisk = errors.New("go.mod was modified by another process since gx read it")
+
 // NewParser creates a new modfile parser
 func NewParser(path string) (*Parser, error) {
 	data, err := os.ReadFile(path)
@@ -30,16 +42,91 @@ func NewParser(path string) (*Parser, error) {
 		path: path,
 		file: file,
 		data: data,
+		sum:  sha256.Sum256(data),
 	}, nil
 }
 
+// Reload re-reads go.mod from disk, discarding any unsaved changes. The
+// *modfile.File and *modfile.Require pointers handed out before stay valid
+// and see the reloaded content, except for requirements no longer in go.mod.
+func (p *Parser) Reload() error {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", p.path, err)
+	}
+
+	file, err := modfile.Parse(p.path, data, nil)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", p.path, err)
+	}
+
+	p.mu.Lock()
+	p.replaceFile(file)
+	p.setData(data)
+	p.mu.Unlock()
+	return nil
+}
+
+// replaceFile makes file the parsed go.mod in place of the current one,
+// copying it into the structs callers may hold: the file itself and each
+// requirement still in it, matched by module path. Callers must hold p.mu.
+func (p *Parser) replaceFile(file *modfile.File) {
+	held := make(map[string][]*modfile.Require, len(p.file.Require))
+	for _, req := range p.file.Require {
+		held[req.Mod.Path] = append(held[req.Mod.Path], req)
+	}
+	for i, req := range file.Require {
+		if reqs := held[req.Mod.Path]; len(reqs) > 0 {
+			*reqs[0] = *req
+			file.Require[i] = reqs[0]
+			held[req.Mod.Path] = reqs[1:]
+		}
+	}
+	*p.file = *file
+}
+
+// setData records content known to be on disk. Callers must hold p.mu.
+func (p *Parser) setData(data []byte) {
+	p.data = data
+	p.sum = sha256.Sum256(data)
+}
+
+// ModifiedOnDisk reports whether go.mod no longer matches the content last
+// read or written by gx. A missing file counts as unmodified.
+func (p *Parser) ModifiedOnDisk() (bool, error) {
+	current, err := os.ReadFile(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading %s: %w", p.path, err)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	sum := sha256.Sum256(current)
+	return !bytes.Equal(sum[:], p.sum[:]), nil
+}
+
+// Data returns the go.mod content as of the last read or successful write
+func (p *Parser) Data() []byte {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]byte(nil), p.data...)
+}
+
 // File returns the underlying modfile.File
 func (p *Parser) File() *modfile.File {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	return p.file
 }
 
 // ModulePath returns the module path
 func (p *Parser) ModulePath() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if p.file.Module == nil {
 		return ""
 	}
@@ -49,6 +136,9 @@ func (p *Parser) ModulePath() string {
 
 // DirectRequires returns all direct (non-indirect) requirements
 func (p *Parser) DirectRequires() []*modfile.Require {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	var direct []*modfile.Require
 	for _, req := range p.file.Require {
 		if !req.Indirect {
@@ -60,6 +150,9 @@ func (p *Parser) DirectRequires() []*modfile.Require {
 
 // IndirectRequires returns all indirect requirements
 func (p *Parser) IndirectRequires() []*modfile.Require {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	var indirect []*modfile.Require
 	for _, req := range p.file.Require {
 		if req.Indirect {
@@ -71,11 +164,16 @@ func (p *Parser) IndirectRequires() []*modfile.Require {
 
 // AllRequires returns all requirements
 func (p *Parser) AllRequires() []*modfile.Require {
-	return p.file.Require
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*modfile.Require(nil), p.file.Require...)
 }
 
 // FindRequire finds a requirement by module path
 func (p *Parser) FindRequire(modulePath string) *modfile.Require {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	for _, req := range p.file.Require {
 		if req.Mod.Path == modulePath {
 			return req
@@ -84,6 +182,72 @@ func (p *Parser) FindRequire(modulePath string) *modfile.Require {
 	return nil
 }
 
+// FindReplace returns the replace directive that applies to a module version:
+// one naming that exact version, otherwise one covering all versions
+func (p *Parser) FindReplace(modulePath, version string) *modfile.Replace {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var wildcard *modfile.Replace
+	for _, rep := range p.file.Replace {
+		if rep.Old.Path != modulePath {
+			continue
+		}
+		if rep.Old.Version == version {
+			return rep
+		}
+		if rep.Old.Version == "" {
+			wildcard = rep
+		}
+	}
+	return wildcard
+}
+
+// Replacement is a replace directive applying to a required module version
+type Replacement struct {
+	Path    string         // the required module
+	Version string         // its required version
+	New     module.Version // the replacement, without a version when Local
+	Local   bool           // replaced by a local directory
+}
+
+// String returns the replacement, e.g. "github.com/fork/a v1.0.1" or "../a"
+func (r Replacement) String() string {
+	if r.New.Version == "" {
+		return r.New.Path
+	}
+	return r.New.Path + " " + r.New.Version
+}
+
+// Replacements returns the replace directives applying to the required
+// modules, in the order of the requirements
+func (p *Parser) Replacements() []Replacement {
+	var replacements []Replacement
+	for _, req := range p.AllRequires() {
+		if rep := p.FindReplace(req.Mod.Path, req.Mod.Version); rep != nil {
+			replacements = append(replacements, Replacement{
+				Path:    req.Mod.Path,
+				Version: req.Mod.Version,
+				New:     rep.New,
+				Local:   modfile.IsDirectoryPath(rep.New.Path),
+			})
+		}
+	}
+	return replacements
+}
+
+// LocalReplacements returns the required modules whose required version is
+// replaced by a local directory, so nothing about them comes from a proxy
+func (p *Parser) LocalReplacements() []string {
+	var local []string
+	for _, r := range p.Replacements() {
+		if r.Local {
+			local = append(local, r.Path)
+		}
+	}
+	return local
+}
+
 // HasRequire checks if a module is required
 func (p *Parser) HasRequire(modulePath string) bool {
 	return p.FindRequire(modulePath) != nil
@@ -1,9 +1,13 @@
 package modfile
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/omarshaarawi/gx/internal/benchdata"
 )
 
 const (
@@ -339,6 +343,114 @@ func TestParser_FindRequire(t *testing.T) {
 	}
 }
 
+func TestParser_FindReplace(t *testing.T) {
+	tmpFile := createTempGoMod(t, `module example.com/app
+
+go 1.24
+
+require (
+	github.com/a/a v1.0.0
+	github.com/b/b v1.2.0
+)
+
+replace github.com/a/a => github.com/fork/a v1.0.1
+
+replace (
+	github.com/b/b v1.2.0 => ../b
+	github.com/b/b => github.com/fork/b v1.3.0
+)
+`)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	tests := []struct {
+		path, version string
+		wantNew       string
+	}{
+		{"github.com/a/a", "v1.0.0", "github.com/fork/a"},
+		{"github.com/b/b", "v1.2.0", "../b"},
+		{"github.com/b/b", "v1.1.0", "github.com/fork/b"},
+		{"github.com/c/c", "v1.0.0", ""},
+	}
+	for _, tt := range tests {
+		rep := parser.FindReplace(tt.path, tt.version)
+		got := ""
+		if rep != nil {
+			got = rep.New.Path
+		}
+		if got != tt.wantNew {
+			t.Errorf("FindReplace(%s, %s) = %q, want %q", tt.path, tt.version, got, tt.wantNew)
+		}
+	}
+}
+
+func TestParser_LocalReplacements(t *testing.T) {
+	tmpFile := createTempGoMod(t, `module example.com/app
+
+go 1.24
+
+require (
+	github.com/a/a v1.0.0
+	github.com/b/b v1.2.0
+	github.com/c/c v0.1.0
+	github.com/d/d v1.4.0
+)
+
+replace (
+	github.com/a/a => ./internal/a
+	github.com/b/b v1.1.0 => ../b
+	github.com/c/c => github.com/fork/c v0.1.1
+	github.com/d/d v1.4.0 => /src/d
+)
+`)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	got := parser.LocalReplacements()
+	want := []string{"github.com/a/a", "github.com/d/d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalReplacements() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_Replacements(t *testing.T) {
+	tmpFile := createTempGoMod(t, `module example.com/app
+
+go 1.24
+
+require (
+	github.com/a/a v1.0.0
+	github.com/b/b v1.2.0
+	github.com/c/c v0.1.0
+)
+
+replace (
+	github.com/a/a => ../a
+	github.com/b/b v1.1.0 => ../b
+	github.com/c/c => github.com/fork/c v0.1.1
+)
+`)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	got := parser.Replacements()
+	if len(got) != 2 {
+		t.Fatalf("Replacements() returned %d, want 2: %v", len(got), got)
+	}
+	if r := got[0]; r.Path != "github.com/a/a" || r.Version != "v1.0.0" || !r.Local || r.String() != "../a" {
+		t.Errorf("Replacements()[0] = %+v, want github.com/a/a v1.0.0 replaced locally by ../a", r)
+	}
+	if r := got[1]; r.Path != "github.com/c/c" || r.Local || r.String() != "github.com/fork/c v0.1.1" {
+		t.Errorf("Replacements()[1] = %+v, want github.com/c/c replaced by github.com/fork/c v0.1.1", r)
+	}
+}
+
 func TestParser_HasRequire(t *testing.T) {
 	tmpFile := createTempGoMod(t, validGoMod)
 	parser, err := NewParser(tmpFile)
@@ -464,6 +576,26 @@ func BenchmarkNewParser(b *testing.B) {
 	}
 }
 
+func BenchmarkNewParser_Large(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(fmt.Sprintf("requires=%d", n), func(b *testing.B) {
+			tmpFile := filepath.Join(b.TempDir(), "go.mod")
+			if err := os.WriteFile(tmpFile, benchdata.New(n).GoMod(), 0o644); err != nil {
+				b.Fatalf("Failed to create temp go.mod: %v", err)
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				parser, err := NewParser(tmpFile)
+				if err != nil {
+					b.Fatalf("NewParser() error: %v", err)
+				}
+				_ = parser.LocalReplacements()
+			}
+		})
+	}
+}
+
 func BenchmarkParser_DirectRequires(b *testing.B) {
 	tmpDir := b.TempDir()
 	tmpFile := filepath.Join(tmpDir, "go.mod")
@@ -499,3 +631,50 @@ func BenchmarkParser_FindRequire(b *testing.B) {
 		_ = parser.FindRequire("golang.org/x/mod")
 	}
 }
+
+func TestParser_Reload(t *testing.T) {
+	tmpFile := createTempGoMod(t, "module example.com/reload\n\ngo 1.21\n")
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	updated := "module example.com/reload\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n"
+	if err := os.WriteFile(tmpFile, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if parser.HasRequire("example.com/dep") {
+		t.Fatal("parser should not see changes before Reload()")
+	}
+
+	if err := parser.Reload(); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+
+	if !parser.HasRequire("example.com/dep") {
+		t.Error("Reload() should pick up the new requirement")
+	}
+	if string(parser.Data()) != updated {
+		t.Errorf("Data() = %q, want %q", parser.Data(), updated)
+	}
+}
+
+func TestParser_Reload_InvalidKeepsState(t *testing.T) {
+	tmpFile := createTempGoMod(t, "module example.com/reload\n\ngo 1.21\n")
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	if err := os.WriteFile(tmpFile, []byte("not a go.mod {{{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parser.Reload(); err == nil {
+		t.Fatal("Reload() should fail on an invalid go.mod")
+	}
+	if parser.ModulePath() != "example.com/reload" {
+		t.Error("a failed Reload() should keep the previous state")
+	}
+}
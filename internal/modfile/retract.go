@@ -0,0 +1,93 @@
+package modfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ErrAlreadyRetracted is returned when a retract directive already covers the versions
+var ErrAlreadyRetracted = errors.New("already retracted")
+
+// Retracts returns the module's retract directives
+func (p *Parser) Retracts() []*modfile.Retract {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*modfile.Retract(nil), p.file.Retract...)
+}
+
+// AddRetract appends a retract directive for the inclusive version range
+// [low, high] of the main module, or a single version when they are equal.
+// The rationale becomes the directive's comment, one line per line of text.
+// Versions must be canonical and match the module path's major version.
+func (w *Writer) AddRetract(low, high, rationale string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file.Module == nil {
+		return fmt.Errorf("go.mod has no module directive")
+	}
+	modulePath := p.file.Module.Mod.Path
+
+	for _, v := range []string{low, high} {
+		if err := checkRetractVersion(modulePath, v); err != nil {
+			return err
+		}
+	}
+	if semver.Compare(low, high) > 0 {
+		return fmt.Errorf("invalid range [%s, %s]: %s is newer than %s", low, high, low, high)
+	}
+
+	for _, r := range p.file.Retract {
+		if semver.Compare(r.Low, low) <= 0 && semver.Compare(high, r.High) <= 0 {
+			return fmt.Errorf("%s: %w", formatInterval(low, high), ErrAlreadyRetracted)
+		}
+	}
+
+	vi := modfile.VersionInterval{Low: low, High: high}
+	if err := p.file.AddRetract(vi, strings.TrimSpace(rationale)); err != nil {
+		return fmt.Errorf("adding retract: %w", err)
+	}
+
+	// AddRetract only updates the syntax tree, so re-parse to list the new directive
+	file, err := modfile.Parse(p.path, modfile.Format(p.file.Syntax), nil)
+	if err != nil {
+		return fmt.Errorf("parsing go.mod: %w", err)
+	}
+	p.replaceFile(file)
+	return nil
+}
+
+// checkRetractVersion reports whether v can be retracted by the module at modulePath
+func checkRetractVersion(modulePath, v string) error {
+	if !semver.IsValid(v) {
+		return fmt.Errorf("invalid version %q", v)
+	}
+	if c := semver.Canonical(v); c != strings.TrimSuffix(v, "+incompatible") {
+		return fmt.Errorf("version %q is not canonical (use %s)", v, c)
+	}
+
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return fmt.Errorf("invalid module path %q", modulePath)
+	}
+	if err := module.CheckPathMajor(v, pathMajor); err != nil {
+		return err
+	}
+	return nil
+}
+
+func formatInterval(low, high string) string {
+	if low == high {
+		return low
+	}
+	return "[" + low + ", " + high + "]"
+}
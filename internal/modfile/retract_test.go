@@ -0,0 +1,92 @@
+package modfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWriter_AddRetract(t *testing.T) {
+	input := `module example.com/lib/v2
+
+go 1.24.2
+
+require golang.org/x/mod v0.14.0
+
+// Published with a broken API
+retract v2.0.1
+`
+	want := `module example.com/lib/v2
+
+go 1.24.2
+
+require golang.org/x/mod v0.14.0
+
+retract (
+	// Published with a broken API
+	v2.0.1
+	// Data race in the cache
+	// Upgrade to v2.1.3 or later
+	[v2.1.0, v2.1.2]
+)
+`
+
+	parser, err := NewParser(createTempGoMod(t, input))
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	if err := writer.AddRetract("v2.1.0", "v2.1.2", "Data race in the cache\nUpgrade to v2.1.3 or later\n"); err != nil {
+		t.Fatalf("AddRetract() error: %v", err)
+	}
+
+	got, err := writer.Format()
+	if err != nil {
+		t.Fatalf("Format() error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("AddRetract() produced:\n%s\nwant:\n%s", got, want)
+	}
+
+	if retracts := parser.Retracts(); len(retracts) != 2 || retracts[1].Rationale != "Data race in the cache\nUpgrade to v2.1.3 or later" {
+		t.Errorf("Retracts() = %+v", retracts)
+	}
+}
+
+func TestWriter_AddRetractInvalid(t *testing.T) {
+	input := `module example.com/lib/v2
+
+go 1.24.2
+
+retract [v2.0.0, v2.0.5]
+`
+
+	tests := []struct {
+		name      string
+		low, high string
+		wantErr   error
+	}{
+		{name: "not semver", low: "2.1.0", high: "2.1.0"},
+		{name: "not canonical", low: "v2.1", high: "v2.1"},
+		{name: "wrong major", low: "v1.4.0", high: "v1.4.0"},
+		{name: "reversed range", low: "v2.2.0", high: "v2.1.0"},
+		{name: "covered", low: "v2.0.1", high: "v2.0.3", wantErr: ErrAlreadyRetracted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewParser(createTempGoMod(t, input))
+			if err != nil {
+				t.Fatalf("NewParser() error: %v", err)
+			}
+
+			err = NewWriter(parser).AddRetract(tt.low, tt.high, "")
+			if err == nil {
+				t.Fatal("AddRetract() expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddRetract() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package modfile
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Retraction is a version range retracted by a module author
+type Retraction struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// Contains reports whether version falls in the retracted range
+func (r Retraction) Contains(version string) bool {
+	return semver.Compare(version, r.Low) >= 0 && semver.Compare(version, r.High) <= 0
+}
+
+// String formats the range like the go command, e.g. "v1.0.0" or "[v1.0.0, v1.2.0]"
+func (r Retraction) String() string {
+	if r.Low == r.High {
+		return r.Low
+	}
+	return fmt.Sprintf("[%s, %s]", r.Low, r.High)
+}
+
+// ModuleStatus holds the deprecation and retraction notices published in a
+// module's go.mod. Authors publish these in the latest version, so callers
+// should parse the go.mod of the module's @latest version.
+type ModuleStatus struct {
+	Deprecated  string
+	Retractions []Retraction
+	GoVersion   string // the go directive, "" when there is none
+}
+
+// ParseModuleStatus extracts deprecation and retraction notices from go.mod content
+func ParseModuleStatus(data []byte) (*ModuleStatus, error) {
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.mod: %w", err)
+	}
+
+	status := &ModuleStatus{}
+	if f.Module != nil {
+		status.Deprecated = f.Module.Deprecated
+	}
+	if f.Go != nil {
+		status.GoVersion = f.Go.Version
+	}
+	for _, r := range f.Retract {
+		status.Retractions = append(status.Retractions, Retraction{
+			Low:       r.Low,
+			High:      r.High,
+			Rationale: r.Rationale,
+		})
+	}
+
+	return status, nil
+}
+
+// Retracted returns the retraction covering version, if any
+func (s *ModuleStatus) Retracted(version string) (Retraction, bool) {
+	for _, r := range s.Retractions {
+		if r.Contains(version) {
+			return r, true
+		}
+	}
+	return Retraction{}, false
+}
+
+// Unretracted returns the versions in list that no retraction covers, in
+// their original order
+func (s *ModuleStatus) Unretracted(list []string) []string {
+	var allowed []string
+	for _, v := range list {
+		if _, ok := s.Retracted(v); !ok {
+			allowed = append(allowed, v)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,83 @@
+package modfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+const statusTestGoMod = `// Deprecated: use example.com/new instead.
+module example.com/old
+
+go 1.21
+
+retract (
+	v1.0.1 // Published accidentally.
+	[v1.1.0, v1.1.3] // Data race in the cache.
+)
+`
+
+func TestParseModuleStatus(t *testing.T) {
+	status, err := ParseModuleStatus([]byte(statusTestGoMod))
+	if err != nil {
+		t.Fatalf("ParseModuleStatus() error: %v", err)
+	}
+
+	if status.Deprecated != "use example.com/new instead." {
+		t.Errorf("Deprecated = %q", status.Deprecated)
+	}
+	if status.GoVersion != "1.21" {
+		t.Errorf("GoVersion = %q, want 1.21", status.GoVersion)
+	}
+
+	if len(status.Retractions) != 2 {
+		t.Fatalf("Retractions = %+v, want 2", status.Retractions)
+	}
+
+	tests := []struct {
+		version   string
+		retracted bool
+		rationale string
+	}{
+		{"v1.0.0", false, ""},
+		{"v1.0.1", true, "Published accidentally."},
+		{"v1.1.0", true, "Data race in the cache."},
+		{"v1.1.2", true, "Data race in the cache."},
+		{"v1.1.4", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			r, ok := status.Retracted(tt.version)
+			if ok != tt.retracted || r.Rationale != tt.rationale {
+				t.Errorf("Retracted(%s) = %+v, %v", tt.version, r, ok)
+			}
+		})
+	}
+
+	if got := status.Retractions[1].String(); got != "[v1.1.0, v1.1.3]" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParseModuleStatus_Clean(t *testing.T) {
+	status, err := ParseModuleStatus([]byte("module example.com/fine\n\ngo 1.21\n"))
+	if err != nil {
+		t.Fatalf("ParseModuleStatus() error: %v", err)
+	}
+	if status.Deprecated != "" || len(status.Retractions) != 0 {
+		t.Errorf("ParseModuleStatus() = %+v, want empty", status)
+	}
+}
+
+func TestModuleStatus_Unretracted(t *testing.T) {
+	status, err := ParseModuleStatus([]byte(statusTestGoMod))
+	if err != nil {
+		t.Fatalf("ParseModuleStatus() error: %v", err)
+	}
+
+	list := []string{"v1.0.0", "v1.0.1", "v1.1.0", "v1.1.3", "v1.1.4", "v1.2.0"}
+	want := []string{"v1.0.0", "v1.1.4", "v1.2.0"}
+	if got := status.Unretracted(list); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unretracted() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package modfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SumFile holds the hashes recorded in a go.sum file
+type SumFile struct {
+	hashes map[string]string
+}
+
+// SumPath returns the go.sum path that sits next to the given go.mod
+func SumPath(modPath string) string {
+	return filepath.Join(filepath.Dir(modPath), "go.sum")
+}
+
+// ParseSum reads a go.sum file. A missing file yields an empty SumFile.
+func ParseSum(path string) (*SumFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SumFile{hashes: map[string]string{}}, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return ParseSumData(data)
+}
+
+// ParseSumData parses go.sum content
+func ParseSumData(data []byte) (*SumFile, error) {
+	sums := &SumFile{hashes: map[string]string{}}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("go.sum line %d: malformed entry", lineNo)
+		}
+
+		sums.hashes[fields[0]+"@"+fields[1]] = fields[2]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning go.sum: %w", err)
+	}
+
+	return sums, nil
+}
+
+// Hash returns the module zip hash for path@version
+func (s *SumFile) Hash(modulePath, version string) string {
+	return s.hashes[modulePath+"@"+version]
+}
+
+// ModHash returns the go.mod hash for path@version
+func (s *SumFile) ModHash(modulePath, version string) string {
+	return s.hashes[modulePath+"@"+version+"/go.mod"]
+}
+
+// Len returns the number of entries in the go.sum file
+func (s *SumFile) Len() int {
+	return len(s.hashes)
+}
@@ -0,0 +1,89 @@
+package modfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testGoSum = `github.com/stretchr/testify v1.8.4 h1:CcVxjf3Q8PM0mHUKJCdn+eZZtm5yQwehR5yeSVQQcUk=
+github.com/stretchr/testify v1.8.4/go.mod h1:sz/lmYIOXD/1dqDmKjjqLyZ2RngseejIcXlSw2iwfAo=
+golang.org/x/mod v0.14.0 h1:dGoOF9QVLYng8IHTm7BAyWqCqSheQ5pYWGhzW00YJr0=
+golang.org/x/mod v0.14.0/go.mod h1:hTbmBsO62+eylJbnUtE2MGJUyE7QWk4xUqPFrRgJ+7c=
+`
+
+func TestParseSumData(t *testing.T) {
+	sums, err := ParseSumData([]byte(testGoSum))
+	if err != nil {
+		t.Fatalf("ParseSumData() error: %v", err)
+	}
+
+	if sums.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", sums.Len())
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		hash    string
+		modHash string
+	}{
+		{
+			name:    "testify",
+			path:    "github.com/stretchr/testify",
+			version: "v1.8.4",
+			hash:    "h1:CcVxjf3Q8PM0mHUKJCdn+eZZtm5yQwehR5yeSVQQcUk=",
+			modHash: "h1:sz/lmYIOXD/1dqDmKjjqLyZ2RngseejIcXlSw2iwfAo=",
+		},
+		{
+			name:    "unknown module",
+			path:    "github.com/unknown/module",
+			version: "v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sums.Hash(tt.path, tt.version); got != tt.hash {
+				t.Errorf("Hash() = %q, want %q", got, tt.hash)
+			}
+			if got := sums.ModHash(tt.path, tt.version); got != tt.modHash {
+				t.Errorf("ModHash() = %q, want %q", got, tt.modHash)
+			}
+		})
+	}
+}
+
+func TestParseSumData_Malformed(t *testing.T) {
+	if _, err := ParseSumData([]byte("github.com/broken v1.0.0\n")); err == nil {
+		t.Error("ParseSumData() expected error for malformed line")
+	}
+}
+
+func TestParseSum_Missing(t *testing.T) {
+	sums, err := ParseSum(filepath.Join(t.TempDir(), "go.sum"))
+	if err != nil {
+		t.Fatalf("ParseSum() unexpected error: %v", err)
+	}
+	if sums.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", sums.Len())
+	}
+}
+
+func TestSumPath(t *testing.T) {
+	modPath := createTempGoMod(t, writerTestGoMod)
+	sumPath := SumPath(modPath)
+
+	if err := os.WriteFile(sumPath, []byte(testGoSum), 0o644); err != nil {
+		t.Fatalf("writing go.sum: %v", err)
+	}
+
+	sums, err := ParseSum(sumPath)
+	if err != nil {
+		t.Fatalf("ParseSum() error: %v", err)
+	}
+	if sums.Hash("golang.org/x/mod", "v0.14.0") == "" {
+		t.Error("expected hash for golang.org/x/mod")
+	}
+}
@@ -0,0 +1,62 @@
+package modfile
+
+import (
+	"fmt"
+)
+
+// GoVersion returns the version in the go directive, or "" if there is none
+func (p *Parser) GoVersion() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file.Go == nil {
+		return ""
+	}
+	return p.file.Go.Version
+}
+
+// Toolchain returns the name in the toolchain directive (go1.22.3), or "" if
+// there is none
+func (p *Parser) Toolchain() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file.Toolchain == nil {
+		return ""
+	}
+	return p.file.Toolchain.Name
+}
+
+// SetGoVersion sets the go directive, adding it if missing
+func (w *Writer) SetGoVersion(version string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.file.AddGoStmt(version); err != nil {
+		return fmt.Errorf("setting go version: %w", err)
+	}
+	return nil
+}
+
+// SetToolchain sets the toolchain directive, or removes it when name is empty
+func (w *Writer) SetToolchain(name string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if name == "" {
+		p.file.DropToolchainStmt()
+		return nil
+	}
+	if err := p.file.AddToolchainStmt(name); err != nil {
+		return fmt.Errorf("setting toolchain: %w", err)
+	}
+	return nil
+}
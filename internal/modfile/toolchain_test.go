@@ -0,0 +1,59 @@
+package modfile
+
+import (
+	"testing"
+)
+
+func TestWriter_SetGoVersionAndToolchain(t *testing.T) {
+	input := `module example.com/app
+
+go 1.21
+
+toolchain go1.21.5
+
+require golang.org/x/mod v0.14.0
+`
+
+	parser, err := NewParser(createTempGoMod(t, input))
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	if parser.GoVersion() != "1.21" || parser.Toolchain() != "go1.21.5" {
+		t.Fatalf("GoVersion() = %q, Toolchain() = %q", parser.GoVersion(), parser.Toolchain())
+	}
+
+	writer := NewWriter(parser)
+	if err := writer.SetGoVersion("1.22.0"); err != nil {
+		t.Fatalf("SetGoVersion() error: %v", err)
+	}
+	if err := writer.SetToolchain("go1.22.4"); err != nil {
+		t.Fatalf("SetToolchain() error: %v", err)
+	}
+	if parser.GoVersion() != "1.22.0" || parser.Toolchain() != "go1.22.4" {
+		t.Errorf("after set: GoVersion() = %q, Toolchain() = %q", parser.GoVersion(), parser.Toolchain())
+	}
+
+	if err := writer.SetToolchain(""); err != nil {
+		t.Fatalf("SetToolchain(\"\") error: %v", err)
+	}
+	got, err := writer.Format()
+	if err != nil {
+		t.Fatalf("Format() error: %v", err)
+	}
+	want := `module example.com/app
+
+go 1.22.0
+
+require golang.org/x/mod v0.14.0
+`
+	if string(got) != want {
+		t.Errorf("Format() produced:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := writer.SetGoVersion("1.22.x"); err == nil {
+		t.Error("SetGoVersion(1.22.x) expected an error")
+	}
+	if err := writer.SetToolchain("1.22.4"); err == nil {
+		t.Error("SetToolchain(1.22.4) expected an error")
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
 )
 
-// Writer handles safe writing of go.mod files
+// Writer handles safe writing of go.mod files.
+// Its methods may be called from multiple goroutines.
 type Writer struct {
+	mu         sync.Mutex
 	parser     *Parser
 	backupMade bool
 	backupPath string
+	force      bool
 }
 
 // NewWriter creates a new modfile writer
@@ -21,8 +27,36 @@ func NewWriter(parser *Parser) *Writer {
 	}
 }
 
-// Backup creates a timestamped backup of the go.mod file
+// WithForce makes writes overwrite go.mod even if it changed on disk since it was read
+func (w *Writer) WithForce(force bool) *Writer {
+	w.force = force
+	return w
+}
+
+// checkUnmodified refuses to clobber changes made to go.mod by another process
+func (w *Writer) checkUnmodified() error {
+	if w.force {
+		return nil
+	}
+
+	modified, err := w.parser.ModifiedOnDisk()
+	if err != nil {
+		return err
+	}
+	if modified {
+		return fmt.Errorf("%s: %w; re-run to pick up the changes, or use --force to overwrite them", w.parser.path, ErrModifiedOnDisk)
+	}
+	return nil
+}
+
+// Backup creates a timestamped backup of the go.mod file with the same permissions
 func (w *Writer) Backup() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.backup()
+}
+
+func (w *Writer) backup() error {
 	if w.backupMade {
 		return nil
 	}
@@ -30,7 +64,7 @@ func (w *Writer) Backup() error {
 	timestamp := time.Now().Format("20060102_150405")
 	backupPath := fmt.Sprintf("%s.backup.%s", w.parser.path, timestamp)
 
-	if err := os.WriteFile(backupPath, w.parser.data, 0o644); err != nil {
+	if err := fsutil.WriteFileAs(backupPath, w.parser.Data(), w.parser.path, 0o644); err != nil {
 		return fmt.Errorf("creating backup: %w", err)
 	}
 
@@ -39,8 +73,14 @@ func (w *Writer) Backup() error {
 	return nil
 }
 
-// RestoreBackup restores the backup file
+// RestoreBackup restores the backup file and reloads the parser from it
 func (w *Writer) RestoreBackup() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.restoreBackup()
+}
+
+func (w *Writer) restoreBackup() error {
 	if !w.backupMade {
 		return fmt.Errorf("no backup to restore")
 	}
@@ -50,15 +90,22 @@ func (w *Writer) RestoreBackup() error {
 		return fmt.Errorf("reading backup: %w", err)
 	}
 
-	if err := os.WriteFile(w.parser.path, data, 0o644); err != nil {
+	if err := fsutil.WriteFileAs(w.parser.path, data, w.backupPath, 0o644); err != nil {
 		return fmt.Errorf("restoring backup: %w", err)
 	}
 
+	if err := w.parser.Reload(); err != nil {
+		return fmt.Errorf("reloading restored go.mod: %w", err)
+	}
+
 	return nil
 }
 
 // CleanupBackup removes the backup file
 func (w *Writer) CleanupBackup() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	if !w.backupMade {
 		return nil
 	}
@@ -74,20 +121,78 @@ func (w *Writer) CleanupBackup() error {
 
 // BackupPath returns the path to the backup file (if created)
 func (w *Writer) BackupPath() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	return w.backupPath
 }
 
-// UpdateRequire updates or adds a requirement
+// UpdateRequire updates or adds a requirement, dropping any gx annotation
+// that described the old version
 func (w *Writer) UpdateRequire(modulePath, version string) error {
-	if err := w.parser.file.AddRequire(modulePath, version); err != nil {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.file.AddRequire(modulePath, version); err != nil {
 		return fmt.Errorf("updating require: %w", err)
 	}
+	for _, req := range p.file.Require {
+		if req.Mod.Path == modulePath {
+			ClearAnnotation(req)
+		}
+	}
+	return nil
+}
+
+// MoveRequire replaces the requirement on oldPath with one on newPath at
+// version, as for a new major version of the module. The requirement stays
+// direct or indirect; if go.mod already requires newPath, that one is
+// updated instead.
+func (w *Writer) MoveRequire(oldPath, newPath, version string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	indirect, found := false, false
+	for _, req := range p.file.Require {
+		if req.Mod.Path == oldPath {
+			indirect, found = req.Indirect, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("moving require: %s is not required", oldPath)
+	}
+	if err := p.file.DropRequire(oldPath); err != nil {
+		return fmt.Errorf("moving require: %w", err)
+	}
+	for _, req := range p.file.Require {
+		if req.Mod.Path == newPath {
+			if err := p.file.AddRequire(newPath, version); err != nil {
+				return fmt.Errorf("moving require: %w", err)
+			}
+			return nil
+		}
+	}
+	p.file.AddNewRequire(newPath, version, indirect)
 	return nil
 }
 
 // DropRequire removes a requirement
 func (w *Writer) DropRequire(modulePath string) error {
-	if err := w.parser.file.DropRequire(modulePath); err != nil {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.file.DropRequire(modulePath); err != nil {
 		return fmt.Errorf("dropping require: %w", err)
 	}
 	return nil
@@ -95,15 +200,30 @@ func (w *Writer) DropRequire(modulePath string) error {
 
 // Format returns the formatted go.mod content
 func (w *Writer) Format() ([]byte, error) {
-	data, err := w.parser.file.Format()
+	p := w.parser
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.file.Format()
 	if err != nil {
 		return nil, fmt.Errorf("formatting go.mod: %w", err)
 	}
 	return data, nil
 }
 
-// Write writes the formatted content to the go.mod file
+// Write atomically replaces the go.mod file, keeping its permissions and owner.
+// On success the parser's cached content matches the file.
 func (w *Writer) Write() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.checkUnmodified(); err != nil {
+		return err
+	}
+	return w.write()
+}
+
+func (w *Writer) write() error {
 	data, err := w.Format()
 	if err != nil {
 		return err
@@ -114,28 +234,52 @@ func (w *Writer) Write() error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	if err := os.WriteFile(w.parser.path, data, 0o644); err != nil {
+	if err := fsutil.WriteFile(w.parser.path, data, 0o644); err != nil {
 		return fmt.Errorf("writing go.mod: %w", err)
 	}
 
+	w.parser.mu.Lock()
+	w.parser.setData(data)
+	w.parser.mu.Unlock()
+
 	return nil
 }
 
-// SafeWrite creates a backup, writes the file, and validates it
+// lockTimeout is how long SafeWrite waits for another process to release go.mod
+const lockTimeout = 10 * time.Second
+
+// SafeWrite creates a backup, writes the file, and validates it. The file is
+// locked for the duration so concurrent gx runs can't overwrite each other,
+// and the write is refused if go.mod changed since it was read (unless forced).
+// On success the parser is reloaded from the written file, keeping the
+// requirements callers hold up to date (see Reload).
 func (w *Writer) SafeWrite() error {
-	if err := w.Backup(); err != nil {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lock, err := fsutil.LockFile(w.parser.path, lockTimeout)
+	if err != nil {
+		return fmt.Errorf("locking go.mod: %w", err)
+	}
+	defer lock.Unlock()
+
+	if err := w.checkUnmodified(); err != nil {
+		return err
+	}
+
+	if err := w.backup(); err != nil {
 		return fmt.Errorf("backup failed: %w", err)
 	}
 
-	if err := w.Write(); err != nil {
-		if restoreErr := w.RestoreBackup(); restoreErr != nil {
+	if err := w.write(); err != nil {
+		if restoreErr := w.restoreBackup(); restoreErr != nil {
 			return fmt.Errorf("write failed and restore failed: %w (original error: %v)", restoreErr, err)
 		}
 		return fmt.Errorf("write failed (backup restored): %w", err)
 	}
 
-	if _, err := NewParser(w.parser.path); err != nil {
-		if restoreErr := w.RestoreBackup(); restoreErr != nil {
+	if err := w.parser.Reload(); err != nil {
+		if restoreErr := w.restoreBackup(); restoreErr != nil {
 			return fmt.Errorf("validation failed and restore failed: %w (original error: %v)", restoreErr, err)
 		}
 		return fmt.Errorf("validation failed (backup restored): %w", err)
@@ -144,7 +288,39 @@ func (w *Writer) SafeWrite() error {
 	return nil
 }
 
+// Apply backs up go.mod, runs edit to change it in memory, and writes the
+// result with SafeWrite. The backup is removed however that ends: after
+// restoring it when edit fails, and as is when SafeWrite fails, since
+// SafeWrite then either left go.mod alone or restored it itself.
+func (w *Writer) Apply(edit func() error) (err error) {
+	if err := w.Backup(); err != nil {
+		return fmt.Errorf("creating backup: %w", err)
+	}
+	defer func() {
+		if cleanupErr := w.CleanupBackup(); cleanupErr != nil && err == nil {
+			err = fmt.Errorf("cleanup backup: %w", cleanupErr)
+		}
+	}()
+
+	if err := edit(); err != nil {
+		w.RestoreBackup()
+		return err
+	}
+
+	w.Cleanup()
+
+	if err := w.SafeWrite(); err != nil {
+		return fmt.Errorf("writing go.mod: %w", err)
+	}
+	return nil
+}
+
 // Cleanup calls modfile.Cleanup to remove empty sections
 func (w *Writer) Cleanup() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.parser.mu.Lock()
 	w.parser.file.Cleanup()
+	w.parser.mu.Unlock()
 }
@@ -1,11 +1,17 @@
 package modfile
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
 )
 
 const (
@@ -183,6 +189,55 @@ func TestWriter_RestoreBackup(t *testing.T) {
 	}
 }
 
+func TestWriter_PreservesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	if err := os.Chmod(tmpFile, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	if err := writer.Backup(); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	assertMode(t, writer.BackupPath(), 0o600)
+
+	if err := writer.UpdateRequire("golang.org/x/mod", "v0.15.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	assertMode(t, tmpFile, 0o600)
+
+	if err := os.Chmod(tmpFile, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.RestoreBackup(); err != nil {
+		t.Fatalf("RestoreBackup() error: %v", err)
+	}
+	assertMode(t, tmpFile, 0o600)
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %v, want %v", filepath.Base(path), got, want)
+	}
+}
+
 func TestWriter_RestoreBackup_NoBackup(t *testing.T) {
 	tmpFile := createTempGoMod(t, writerTestGoMod)
 	parser, err := NewParser(tmpFile)
@@ -349,6 +404,44 @@ func TestWriter_DropRequire_NonExistent(t *testing.T) {
 	}
 }
 
+func TestWriter_MoveRequire(t *testing.T) {
+	tmpFile := createTempGoMod(t, `module example.com/app
+
+go 1.24.2
+
+require (
+	github.com/acme/lib v1.4.0
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+)
+`)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	if err := writer.MoveRequire("github.com/acme/lib", "github.com/acme/lib/v3", "v3.1.0"); err != nil {
+		t.Fatalf("MoveRequire() error: %v", err)
+	}
+	if err := writer.MoveRequire("gopkg.in/yaml.v2", "gopkg.in/yaml.v3", "v3.0.1"); err != nil {
+		t.Fatalf("MoveRequire() error: %v", err)
+	}
+
+	if parser.HasRequire("github.com/acme/lib") || parser.HasRequire("gopkg.in/yaml.v2") {
+		t.Error("old module paths are still required")
+	}
+	if req := parser.FindRequire("github.com/acme/lib/v3"); req == nil || req.Mod.Version != "v3.1.0" || req.Indirect {
+		t.Errorf("github.com/acme/lib/v3 = %+v, want a direct v3.1.0 requirement", req)
+	}
+	if req := parser.FindRequire("gopkg.in/yaml.v3"); req == nil || req.Mod.Version != "v3.0.1" || !req.Indirect {
+		t.Errorf("gopkg.in/yaml.v3 = %+v, want an indirect v3.0.1 requirement", req)
+	}
+
+	if err := writer.MoveRequire("github.com/acme/missing", "github.com/acme/missing/v2", "v2.0.0"); err == nil {
+		t.Error("MoveRequire() of a module that isn't required should fail")
+	}
+}
+
 func TestWriter_Format(t *testing.T) {
 	tmpFile := createTempGoMod(t, writerTestGoMod)
 	parser, err := NewParser(tmpFile)
@@ -482,6 +575,40 @@ func TestWriter_SafeWrite(t *testing.T) {
 	}
 }
 
+func TestWriter_SafeWrite_Locked(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	lock, err := fsutil.LockFile(tmpFile, time.Second)
+	if err != nil {
+		t.Fatalf("LockFile() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	writer.UpdateRequire("github.com/new/package", "v1.0.0")
+
+	done := make(chan error, 1)
+	go func() { done <- writer.SafeWrite() }()
+
+	time.Sleep(150 * time.Millisecond)
+	data, _ := os.ReadFile(tmpFile)
+	if strings.Contains(string(data), "github.com/new/package") {
+		t.Fatal("SafeWrite() wrote go.mod while another process held the lock")
+	}
+
+	lock.Unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("SafeWrite() error after lock released: %v", err)
+	}
+
+	if _, err := os.Stat(tmpFile + ".lock"); !os.IsNotExist(err) {
+		t.Error("SafeWrite() should release its lock")
+	}
+}
+
 func TestWriter_SafeWrite_RestoresOnValidationFailure(t *testing.T) {
 	tmpFile := createTempGoMod(t, writerTestGoMod)
 	parser, err := NewParser(tmpFile)
@@ -656,3 +783,272 @@ func BenchmarkWriter_SafeWrite(b *testing.B) {
 		_ = writer.SafeWrite()
 	}
 }
+
+func TestWriter_MultiStepSession(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+
+	// Step 1: update and write
+	if err := writer.UpdateRequire("golang.org/x/mod", "v0.15.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.SafeWrite(); err != nil {
+		t.Fatalf("first SafeWrite() error: %v", err)
+	}
+	onDisk, _ := os.ReadFile(tmpFile)
+	if string(parser.Data()) != string(onDisk) {
+		t.Error("parser data should match go.mod after SafeWrite()")
+	}
+	if err := writer.CleanupBackup(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Step 2: a fresh backup must capture the step 1 content, not the original
+	if err := writer.UpdateRequire("github.com/stretchr/testify", "v1.9.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.SafeWrite(); err != nil {
+		t.Fatalf("second SafeWrite() error: %v", err)
+	}
+	backup, err := os.ReadFile(writer.BackupPath())
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if !strings.Contains(string(backup), "golang.org/x/mod v0.15.0") {
+		t.Errorf("second backup is stale:\n%s", backup)
+	}
+
+	// Step 3: restoring goes back to the step 1 state and refreshes the parser
+	if err := writer.RestoreBackup(); err != nil {
+		t.Fatalf("RestoreBackup() error: %v", err)
+	}
+	if got := parser.FindRequire("github.com/stretchr/testify").Mod.Version; got != "v1.8.4" {
+		t.Errorf("after restore testify = %s, want v1.8.4", got)
+	}
+	if got := parser.FindRequire("golang.org/x/mod").Mod.Version; got != "v0.15.0" {
+		t.Errorf("after restore x/mod = %s, want v0.15.0", got)
+	}
+	writer.CleanupBackup()
+}
+
+func TestWriter_ConcurrentUpdates(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			writer.UpdateRequire(fmt.Sprintf("example.com/dep%d", i), "v1.0.0")
+		}()
+		go func() {
+			defer wg.Done()
+			parser.DirectRequires()
+		}()
+	}
+	wg.Wait()
+
+	if err := writer.SafeWrite(); err != nil {
+		t.Fatalf("SafeWrite() error: %v", err)
+	}
+	defer writer.CleanupBackup()
+
+	if got := len(parser.AllRequires()); got != 22 {
+		t.Errorf("AllRequires() = %d entries, want 22", got)
+	}
+}
+
+func TestWriter_SafeWrite_DetectsExternalChanges(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	writer.UpdateRequire("golang.org/x/mod", "v0.15.0")
+
+	// Simulate a concurrent `go get` touching go.mod
+	external := writerTestGoMod + "\nrequire github.com/external/dep v1.0.0\n"
+	if err := os.WriteFile(tmpFile, []byte(external), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = writer.SafeWrite()
+	if !errors.Is(err, ErrModifiedOnDisk) {
+		t.Fatalf("SafeWrite() error = %v, want ErrModifiedOnDisk", err)
+	}
+
+	data, _ := os.ReadFile(tmpFile)
+	if string(data) != external {
+		t.Error("SafeWrite() must leave externally modified go.mod untouched")
+	}
+	if writer.BackupPath() != "" {
+		t.Error("SafeWrite() should not create a backup when aborting")
+	}
+
+	if err := writer.WithForce(true).SafeWrite(); err != nil {
+		t.Fatalf("forced SafeWrite() error: %v", err)
+	}
+	defer writer.CleanupBackup()
+
+	data, _ = os.ReadFile(tmpFile)
+	if !strings.Contains(string(data), "golang.org/x/mod v0.15.0") {
+		t.Error("forced SafeWrite() should overwrite go.mod")
+	}
+}
+
+// backups lists the backup files Backup left next to go.mod
+func backups(t *testing.T, modPath string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(modPath + ".backup.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestWriter_Apply(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+
+	err = writer.Apply(func() error {
+		return writer.UpdateRequire("golang.org/x/mod", "v0.15.0")
+	})
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(tmpFile)
+	if !strings.Contains(string(data), "golang.org/x/mod v0.15.0") {
+		t.Error("Apply() should write the edit")
+	}
+	if left := backups(t, tmpFile); len(left) > 0 {
+		t.Errorf("Apply() left backups behind: %v", left)
+	}
+}
+
+func TestWriter_Apply_EditFailure(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+
+	editErr := errors.New("edit failed")
+	err = writer.Apply(func() error {
+		writer.UpdateRequire("golang.org/x/mod", "v0.15.0")
+		return editErr
+	})
+	if !errors.Is(err, editErr) {
+		t.Fatalf("Apply() error = %v, want the edit's error", err)
+	}
+
+	data, _ := os.ReadFile(tmpFile)
+	if string(data) != writerTestGoMod {
+		t.Error("Apply() should leave go.mod unchanged when the edit fails")
+	}
+	if left := backups(t, tmpFile); len(left) > 0 {
+		t.Errorf("Apply() left backups behind: %v", left)
+	}
+}
+
+func TestWriter_Apply_SafeWriteFailure(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+
+	// A concurrent `go get` makes SafeWrite refuse to overwrite go.mod
+	external := writerTestGoMod + "\nrequire github.com/external/dep v1.0.0\n"
+	err = writer.Apply(func() error {
+		if err := os.WriteFile(tmpFile, []byte(external), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return writer.UpdateRequire("golang.org/x/mod", "v0.15.0")
+	})
+	if !errors.Is(err, ErrModifiedOnDisk) {
+		t.Fatalf("Apply() error = %v, want ErrModifiedOnDisk", err)
+	}
+
+	data, _ := os.ReadFile(tmpFile)
+	if string(data) != external {
+		t.Error("Apply() must leave externally modified go.mod untouched")
+	}
+	if left := backups(t, tmpFile); len(left) > 0 {
+		t.Errorf("Apply() left backups behind: %v", left)
+	}
+}
+
+func TestParser_ModifiedOnDisk_AfterWrite(t *testing.T) {
+	tmpFile := createTempGoMod(t, writerTestGoMod)
+	parser, err := NewParser(tmpFile)
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+
+	writer := NewWriter(parser)
+	writer.UpdateRequire("golang.org/x/mod", "v0.15.0")
+	if err := writer.Write(); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	if modified, err := parser.ModifiedOnDisk(); err != nil || modified {
+		t.Errorf("ModifiedOnDisk() after own write = %v, %v; want false", modified, err)
+	}
+
+	// A second write in the same session must not trip the check
+	writer.UpdateRequire("github.com/stretchr/testify", "v1.9.0")
+	if err := writer.Write(); err != nil {
+		t.Fatalf("second Write() error: %v", err)
+	}
+}
+
+func TestWriter_KeepsHeldRequires(t *testing.T) {
+	parser, err := NewParser(createTempGoMod(t, validGoMod))
+	if err != nil {
+		t.Fatalf("NewParser() error: %v", err)
+	}
+	writer := NewWriter(parser)
+	req := parser.FindRequire("golang.org/x/mod")
+
+	if err := writer.UpdateRequire("golang.org/x/mod", "v0.20.0"); err != nil {
+		t.Fatalf("UpdateRequire() error: %v", err)
+	}
+	if err := writer.SafeWrite(); err != nil {
+		t.Fatalf("SafeWrite() error: %v", err)
+	}
+	writer.CleanupBackup()
+	if req.Mod.Version != "v0.20.0" || parser.FindRequire("golang.org/x/mod") != req {
+		t.Errorf("after SafeWrite, held require = %s, want the reloaded v0.20.0", req.Mod.Version)
+	}
+
+	// Organize re-parses too; the held require must edit the new syntax
+	if err := writer.Organize(); err != nil {
+		t.Fatalf("Organize() error: %v", err)
+	}
+	SetAnnotation(req, "note")
+	data, err := writer.Format()
+	if err != nil {
+		t.Fatalf("Format() error: %v", err)
+	}
+	if !strings.Contains(string(data), "golang.org/x/mod v0.20.0 // gx: note") {
+		t.Errorf("annotation through the held require is missing:\n%s", data)
+	}
+}
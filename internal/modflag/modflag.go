@@ -0,0 +1,77 @@
+// Package modflag handles the global --mod/-C flag, which points gx at a
+// module other than the one in the current directory. Commands resolve
+// their go.mod from it and work relative to that file, so the process
+// never changes directory and other paths given on the command line keep
+// meaning what they say.
+package modflag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "mod"
+	usage = "Run in this module directory (or go.mod file) instead of the current one"
+)
+
+// Add adds --mod/-C to the root command, for every command to inherit
+func Add(root *cobra.Command) {
+	root.PersistentFlags().StringP(name, "C", "", usage)
+	root.MarkPersistentFlagDirname(name)
+}
+
+// Path returns the go.mod the command works on: the one in the directory
+// --mod names, the file it names, or go.mod in the current directory
+func Path(cmd *cobra.Command) string {
+	return Resolve(value(cmd))
+}
+
+// Dir returns the directory of the go.mod the command works on
+func Dir(cmd *cobra.Command) string {
+	return filepath.Dir(Path(cmd))
+}
+
+// Resolve returns the go.mod path a --mod value stands for, go.mod in the
+// current directory for an empty one
+func Resolve(path string) string {
+	if path == "" {
+		return "go.mod"
+	}
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path
+	}
+	return filepath.Join(path, "go.mod")
+}
+
+// Where says where a command looked for modPath, for messages such as
+// "go.mod not found in current directory"
+func Where(modPath string) string {
+	if modPath == "go.mod" {
+		return "in current directory"
+	}
+	return "at " + modPath
+}
+
+// Check reports a --mod naming nothing that exists
+func Check(cmd *cobra.Command) error {
+	path := value(cmd)
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("module directory %s: %w", path, err)
+	}
+	return nil
+}
+
+// value returns --mod as given, inherited from the root command
+func value(cmd *cobra.Command) string {
+	if f := cmd.Flag(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
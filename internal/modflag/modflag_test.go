@@ -0,0 +1,58 @@
+package modflag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPath(t *testing.T) {
+	dir := t.TempDir()
+	modPath := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(modPath, []byte("module example.com/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no flag", nil, "go.mod"},
+		{"directory", []string{"-C", dir}, modPath},
+		{"go.mod file", []string{"--mod", modPath}, modPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "gx"}
+			Add(root)
+			sub := &cobra.Command{Use: "outdated", Run: func(*cobra.Command, []string) {}}
+			root.AddCommand(sub)
+
+			root.SetArgs(append([]string{"outdated"}, tt.args...))
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if got := Path(sub); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+			if err := Check(sub); err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheck_Missing(t *testing.T) {
+	root := &cobra.Command{Use: "gx", Run: func(*cobra.Command, []string) {}}
+	Add(root)
+	root.SetArgs([]string{"-C", filepath.Join(t.TempDir(), "missing")})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(root); err == nil {
+		t.Error("Check() = nil for a missing directory")
+	}
+}
@@ -0,0 +1,172 @@
+// Package nix computes Nix content hashes for Go modules and writes the
+// gomod2nix.toml lock file used by gomod2nix's buildGoApplication.
+package nix
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Module is one entry of a gomod2nix.toml file
+type Module struct {
+	Path    string
+	Version string
+	Hash    string // SRI sha256 NAR hash of the module source
+	// Replaced is the module path that replaces Path, if any; Version and Hash
+	// then refer to the replacement
+	Replaced string
+}
+
+// tree is a directory being assembled from a module zip
+type tree struct {
+	files map[string][]byte
+	dirs  map[string]*tree
+}
+
+func newTree() *tree {
+	return &tree{files: map[string][]byte{}, dirs: map[string]*tree{}}
+}
+
+func (t *tree) add(name string, data []byte) {
+	dir, file := t, name
+	for {
+		i := strings.IndexByte(file, '/')
+		if i < 0 {
+			break
+		}
+		sub, ok := dir.dirs[file[:i]]
+		if !ok {
+			sub = newTree()
+			dir.dirs[file[:i]] = sub
+		}
+		dir, file = sub, file[i+1:]
+	}
+	if file != "" {
+		dir.files[file] = data
+	}
+}
+
+// HashModuleZip returns the NAR hash, in SRI form, of the directory that
+// "go mod download" extracts from a module zip: the zip contents with the
+// module@version/ prefix removed
+func HashModuleZip(data []byte, modulePath, version string) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("opening module zip: %w", err)
+	}
+
+	prefix := modulePath + "@" + version + "/"
+	root := newTree()
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok {
+			return "", fmt.Errorf("unexpected file %s in module zip", f.Name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		contents, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		root.add(name, contents)
+	}
+
+	h := sha256.New()
+	writeNAR(h, root)
+	return "sha256-" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeNAR serializes a directory in the Nix archive format. Module zips
+// hold only regular, non-executable files.
+func writeNAR(w io.Writer, root *tree) {
+	narString(w, "nix-archive-1")
+	narDir(w, root)
+}
+
+func narDir(w io.Writer, t *tree) {
+	narString(w, "(")
+	narString(w, "type")
+	narString(w, "directory")
+
+	names := make([]string, 0, len(t.files)+len(t.dirs))
+	for name := range t.files {
+		names = append(names, name)
+	}
+	for name := range t.dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		narString(w, "entry")
+		narString(w, "(")
+		narString(w, "name")
+		narString(w, name)
+		narString(w, "node")
+		if sub, ok := t.dirs[name]; ok {
+			narDir(w, sub)
+		} else {
+			narString(w, "(")
+			narString(w, "type")
+			narString(w, "regular")
+			narString(w, "contents")
+			narBytes(w, t.files[name])
+			narString(w, ")")
+		}
+		narString(w, ")")
+	}
+
+	narString(w, ")")
+}
+
+func narString(w io.Writer, s string) {
+	narBytes(w, []byte(s))
+}
+
+// narBytes writes a length-prefixed field padded to a multiple of 8 bytes
+func narBytes(w io.Writer, b []byte) {
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(b)))
+	w.Write(n[:])
+	w.Write(b)
+	if pad := (8 - len(b)%8) % 8; pad > 0 {
+		w.Write(make([]byte, pad))
+	}
+}
+
+// WriteGomod2nix writes modules in the gomod2nix.toml schema 3 format
+func WriteGomod2nix(w io.Writer, modules []Module) error {
+	sorted := append([]Module(nil), modules...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	var b strings.Builder
+	b.WriteString("schema = 3\n\n[mod]\n")
+	for _, m := range sorted {
+		fmt.Fprintf(&b, "  [mod.%s]\n", strconv.Quote(m.Path))
+		fmt.Fprintf(&b, "    version = %s\n", strconv.Quote(m.Version))
+		fmt.Fprintf(&b, "    hash = %s\n", strconv.Quote(m.Hash))
+		if m.Replaced != "" {
+			fmt.Fprintf(&b, "    replaced = %s\n", strconv.Quote(m.Replaced))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
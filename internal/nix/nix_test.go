@@ -0,0 +1,110 @@
+package nix
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// field encodes a NAR string the long way, to check the serializer against
+func field(s string) []byte {
+	out := make([]byte, 8)
+	binary.LittleEndian.PutUint64(out, uint64(len(s)))
+	out = append(out, s...)
+	for len(out)%8 != 0 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func TestHashModuleZip(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"example.com/m@v1.0.0/go.mod":   "module example.com/m\n",
+		"example.com/m@v1.0.0/sub/a.go": "package sub\n",
+	})
+
+	var want []byte
+	for _, s := range []string{
+		"nix-archive-1", "(", "type", "directory",
+		"entry", "(", "name", "go.mod", "node", "(", "type", "regular", "contents", "module example.com/m\n", ")", ")",
+		"entry", "(", "name", "sub", "node", "(", "type", "directory",
+		"entry", "(", "name", "a.go", "node", "(", "type", "regular", "contents", "package sub\n", ")", ")",
+		")", ")",
+		")",
+	} {
+		want = append(want, field(s)...)
+	}
+	sum := sha256.Sum256(want)
+	wantHash := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	got, err := HashModuleZip(data, "example.com/m", "v1.0.0")
+	if err != nil {
+		t.Fatalf("HashModuleZip() error: %v", err)
+	}
+	if got != wantHash {
+		t.Errorf("HashModuleZip() = %s, want %s", got, wantHash)
+	}
+
+	// File order in the zip must not matter
+	again, _ := HashModuleZip(buildZip(t, map[string]string{
+		"example.com/m@v1.0.0/sub/a.go": "package sub\n",
+		"example.com/m@v1.0.0/go.mod":   "module example.com/m\n",
+	}), "example.com/m", "v1.0.0")
+	if again != got {
+		t.Error("hash depends on zip entry order")
+	}
+}
+
+func TestHashModuleZip_WrongPrefix(t *testing.T) {
+	data := buildZip(t, map[string]string{"other@v1.0.0/go.mod": "module other\n"})
+	if _, err := HashModuleZip(data, "example.com/m", "v1.0.0"); err == nil {
+		t.Error("HashModuleZip() expected error for a file outside the module prefix")
+	}
+}
+
+func TestWriteGomod2nix(t *testing.T) {
+	var b strings.Builder
+	err := WriteGomod2nix(&b, []Module{
+		{Path: "golang.org/x/mod", Version: "v0.14.0", Hash: "sha256-mod="},
+		{Path: "github.com/pkg/errors", Version: "v0.9.2", Hash: "sha256-fork=", Replaced: "github.com/fork/errors"},
+	})
+	if err != nil {
+		t.Fatalf("WriteGomod2nix() error: %v", err)
+	}
+
+	want := `schema = 3
+
+[mod]
+  [mod."github.com/pkg/errors"]
+    version = "v0.9.2"
+    hash = "sha256-fork="
+    replaced = "github.com/fork/errors"
+  [mod."golang.org/x/mod"]
+    version = "v0.14.0"
+    hash = "sha256-mod="
+`
+	if b.String() != want {
+		t.Errorf("WriteGomod2nix() =\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,89 @@
+package pattern
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Filter selects modules by path. Patterns are globs as Match accepts them
+// ("github.com/aws/*") or regular expressions written as /regex/.
+type Filter struct {
+	include []matcher
+	exclude []matcher
+}
+
+type matcher func(modulePath string) bool
+
+// ParseFilter builds a filter keeping modules that match any include pattern
+// (all when there are none) and no exclude pattern. It returns nil when there
+// are no patterns at all.
+func ParseFilter(include, exclude []string) (*Filter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	f := &Filter{}
+	for _, p := range include {
+		m, err := parseMatcher(p)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, m)
+	}
+	for _, p := range exclude {
+		m, err := parseMatcher(p)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, m)
+	}
+	return f, nil
+}
+
+func parseMatcher(p string) (matcher, error) {
+	if IsRegex(p) {
+		compiled, err := regexp.Compile(p[1 : len(p)-1])
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", p, err)
+		}
+		return compiled.MatchString, nil
+	}
+	return func(modulePath string) bool { return Match(p, modulePath) }, nil
+}
+
+// Matches reports whether the filter keeps a module; a nil filter keeps all
+func (f *Filter) Matches(modulePath string) bool {
+	if f == nil {
+		return true
+	}
+	for _, m := range f.exclude {
+		if m(modulePath) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, m := range f.include {
+		if m(modulePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitFilter splits a comma-separated filter pattern list, keeping a
+// /regex/ whole since it may contain commas itself
+func SplitFilter(s string) []string {
+	if t := strings.TrimSpace(s); IsRegex(t) {
+		return []string{t}
+	}
+	return Split(s)
+}
+
+// IsRegex reports whether a pattern is a regular expression written as
+// /regex/
+func IsRegex(s string) bool {
+	return len(s) > 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/")
+}
@@ -0,0 +1,76 @@
+package pattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	f, err := ParseFilter([]string{"github.com/aws/*", `/^k8s\.io/(api|client-go)$/`}, []string{"github.com/aws/smithy-go", "/-v2$/"})
+	if err != nil {
+		t.Fatalf("ParseFilter() error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"github.com/aws/aws-sdk-go", true},
+		{"github.com/aws/smithy-go", false},
+		{"github.com/aws/aws-sdk-go-v2", false},
+		{"k8s.io/api", true},
+		{"k8s.io/apimachinery", false},
+		{"golang.org/x/mod", false},
+	}
+	for _, tt := range tests {
+		if got := f.Matches(tt.path); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilter_ExcludeOnly(t *testing.T) {
+	f, err := ParseFilter(nil, []string{"k8s.io/...", "/mock/"})
+	if err != nil {
+		t.Fatalf("ParseFilter() error: %v", err)
+	}
+
+	if !f.Matches("golang.org/x/mod") {
+		t.Error("a filter without includes should keep unexcluded modules")
+	}
+	if f.Matches("k8s.io/api") || f.Matches("github.com/golang/mock") {
+		t.Error("excluded modules should not match")
+	}
+}
+
+func TestParseFilter_Empty(t *testing.T) {
+	f, err := ParseFilter(nil, nil)
+	if err != nil || f != nil {
+		t.Fatalf("ParseFilter(nil, nil) = %v, %v, want nil, nil", f, err)
+	}
+	if !f.Matches("golang.org/x/mod") {
+		t.Error("a nil filter should keep every module")
+	}
+}
+
+func TestParseFilter_InvalidRegex(t *testing.T) {
+	if _, err := ParseFilter(nil, []string{"/(/"}); err == nil {
+		t.Error("ParseFilter() expected an error for an invalid regex")
+	}
+}
+
+func TestSplitFilter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"k8s.io/*, golang.org/x/mod", []string{"k8s.io/*", "golang.org/x/mod"}},
+		{" /^(a|b){1,2}$/ ", []string{"/^(a|b){1,2}$/"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitFilter(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitFilter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pattern
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether a module path matches a pattern.
+//
+// Patterns ending in "/*" or "/..." match the prefix and everything below it,
+// other patterns use path.Match glob syntax and fall back to an exact compare.
+func Match(pattern, modulePath string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+
+	for _, suffix := range []string{"/...", "/*"} {
+		if prefix, ok := strings.CutSuffix(pattern, suffix); ok {
+			if !strings.ContainsAny(prefix, "*?[") {
+				return modulePath == prefix || strings.HasPrefix(modulePath, prefix+"/")
+			}
+		}
+	}
+
+	if matched, err := path.Match(pattern, modulePath); err == nil && matched {
+		return true
+	}
+
+	return pattern == modulePath
+}
+
+// MatchAny reports whether a module path matches any of the patterns
+func MatchAny(patterns []string, modulePath string) bool {
+	for _, p := range patterns {
+		if Match(p, modulePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// MoreSpecific reports whether pattern a takes precedence over b when both
+// match: the longer pattern wins, and the lexically smaller one breaks ties so
+// the choice doesn't depend on map iteration order
+func MoreSpecific(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a < b
+}
+
+// Split parses a comma-separated pattern list, dropping empty entries
+func Split(s string) []string {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,64 @@
+package pattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"exact", "golang.org/x/mod", "golang.org/x/mod", true},
+		{"exact mismatch", "golang.org/x/mod", "golang.org/x/tools", false},
+		{"prefix star", "github.com/aws/*", "github.com/aws/aws-sdk-go-v2/service/s3", true},
+		{"prefix star matches root", "github.com/aws/*", "github.com/aws", true},
+		{"prefix dots", "k8s.io/...", "k8s.io/client-go", true},
+		{"prefix no partial segment", "github.com/aws/*", "github.com/awslabs/smithy-go", false},
+		{"glob", "golang.org/x/*o*", "golang.org/x/tools", true},
+		{"glob single segment", "github.com/*/cobra", "github.com/spf13/cobra", true},
+		{"empty pattern", "", "golang.org/x/mod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"k8s.io/*", "golang.org/x/mod"}
+
+	if !MatchAny(patterns, "k8s.io/api") {
+		t.Error("MatchAny() expected match for k8s.io/api")
+	}
+	if MatchAny(patterns, "github.com/spf13/cobra") {
+		t.Error("MatchAny() unexpected match for github.com/spf13/cobra")
+	}
+	if MatchAny(nil, "k8s.io/api") {
+		t.Error("MatchAny() with no patterns should not match")
+	}
+}
+
+func TestMoreSpecific(t *testing.T) {
+	if !MoreSpecific("github.com/acme/payments*", "github.com/acme/*") {
+		t.Error("longer pattern should be more specific")
+	}
+	if !MoreSpecific("github.com/acme/a*", "github.com/acme/b*") || MoreSpecific("github.com/acme/b*", "github.com/acme/a*") {
+		t.Error("same-length patterns should be ordered lexically")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := Split(" k8s.io/*, ,golang.org/x/mod ")
+	want := []string{"k8s.io/*", "golang.org/x/mod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() = %v, want %v", got, want)
+	}
+}
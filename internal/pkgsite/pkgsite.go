@@ -0,0 +1,152 @@
+// Package pkgsite searches for Go packages on pkg.go.dev.
+//
+// pkg.go.dev has no JSON search endpoint, so results are read from the
+// search page using the data-test-id attributes the site marks them with.
+package pkgsite
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://pkg.go.dev"
+
+// Client queries pkg.go.dev
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// Result is a package found by a search
+type Result struct {
+	Path       string   `json:"path"`
+	Synopsis   string   `json:"synopsis"`
+	Version    string   `json:"version"`
+	Published  string   `json:"published"`
+	ImportedBy int      `json:"imported_by"`
+	Licenses   []string `json:"licenses"`
+}
+
+// Standard reports whether the result is a standard library package
+func (r Result) Standard() bool {
+	first, _, _ := strings.Cut(r.Path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// NewClient creates a pkg.go.dev client. An empty baseURL uses GX_PKGSITE_URL
+// or https://pkg.go.dev.
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = os.Getenv("GX_PKGSITE_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Search returns up to limit packages matching query, in pkg.go.dev's ranking order
+func (c *Client) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("m", "package")
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pkg.go.dev returned %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	results := ParseSearch(string(body))
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+var (
+	titleTag    = regexp.MustCompile(`<a\s[^>]*data-test-id="snippet-title"[^>]*>`)
+	hrefAttr    = regexp.MustCompile(`href="/([^"?#]+)`)
+	synopsis    = regexp.MustCompile(`(?s)data-test-id="snippet-synopsis"[^>]*>(.*?)</p>`)
+	importedBy  = regexp.MustCompile(`Imported by(?:\s|<[^>]*>)*([\d,.]+)`)
+	version     = regexp.MustCompile(`((?:v|go)\d[^\s<]*)(?:\s|<[^>]*>)*published on`)
+	published   = regexp.MustCompile(`(?s)data-test-id="snippet-published"[^>]*>(.*?)</span>`)
+	license     = regexp.MustCompile(`(?s)data-test-id="snippet-license"[^>]*>(.*?)</span>`)
+	tags        = regexp.MustCompile(`<[^>]*>`)
+	whitespaces = regexp.MustCompile(`\s+`)
+)
+
+// ParseSearch extracts the results from a pkg.go.dev search page
+func ParseSearch(page string) []Result {
+	var results []Result
+
+	for _, snippet := range strings.Split(page, `class="SearchSnippet"`)[1:] {
+		tag := titleTag.FindString(snippet)
+		href := hrefAttr.FindStringSubmatch(tag)
+		if href == nil {
+			continue
+		}
+
+		r := Result{Path: html.UnescapeString(href[1])}
+		if m := synopsis.FindStringSubmatch(snippet); m != nil {
+			r.Synopsis = text(m[1])
+		}
+		if m := importedBy.FindStringSubmatch(snippet); m != nil {
+			r.ImportedBy, _ = strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(m[1]))
+		}
+		if m := version.FindStringSubmatch(snippet); m != nil {
+			r.Version = m[1]
+		}
+		if m := published.FindStringSubmatch(snippet); m != nil {
+			r.Published = text(m[1])
+		}
+		if m := license.FindStringSubmatch(snippet); m != nil {
+			for _, l := range strings.Split(text(m[1]), ",") {
+				if l = strings.TrimSpace(l); l != "" {
+					r.Licenses = append(r.Licenses, l)
+				}
+			}
+		}
+
+		results = append(results, r)
+	}
+	return results
+}
+
+// text strips markup from an HTML fragment and collapses whitespace
+func text(fragment string) string {
+	s := html.UnescapeString(tags.ReplaceAllString(fragment, " "))
+	return strings.TrimSpace(whitespaces.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,109 @@
+package pkgsite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const searchPage = `<html><body>
+<div class="SearchResults">
+<div class="SearchSnippet">
+  <div class="SearchSnippet-headerContainer">
+    <h2>
+      <a href="/gopkg.in/yaml.v3" data-gtmc="search result" data-gtmv="0" data-test-id="snippet-title">
+        yaml
+        <span class="SearchSnippet-header-path">(gopkg.in/yaml.v3)</span>
+      </a>
+    </h2>
+  </div>
+  <p class="SearchSnippet-synopsis" data-test-id="snippet-synopsis">
+    Package yaml implements YAML support for the Go language.
+  </p>
+  <div class="SearchSnippet-infoLabel">
+    <a href="/gopkg.in/yaml.v3?tab=importedby" aria-label="Go to Imported By">
+      <span class="go-textSubtle">Imported by </span><strong>23,456</strong>
+    </a>
+    <span class="go-textSubtle">|</span>
+    <span class="go-textSubtle">
+      <strong>v3.0.1</strong> published on <span data-test-id="snippet-published"><strong>May 27, 2022</strong></span>
+    </span>
+    <span class="go-textSubtle">|</span>
+    <span data-test-id="snippet-license">
+      <a href="/gopkg.in/yaml.v3?tab=licenses" aria-label="Go to Licenses">Apache-2.0, MIT</a>
+    </span>
+  </div>
+</div>
+<div class="SearchSnippet">
+  <h2><a data-test-id="snippet-title" href="/encoding/json">json <span>(encoding/json)</span></a></h2>
+  <p class="SearchSnippet-synopsis" data-test-id="snippet-synopsis">Package json implements encoding &amp; decoding of JSON.</p>
+  <div class="SearchSnippet-infoLabel">
+    <a href="/encoding/json?tab=importedby"><span>Imported by </span><strong>1,234,567</strong></a>
+    <span><strong>go1.24.2</strong> published on <span data-test-id="snippet-published"><strong>Apr 1, 2025</strong></span></span>
+    <span data-test-id="snippet-license"><a href="/encoding/json?tab=licenses">BSD-3-Clause</a></span>
+  </div>
+</div>
+</div>
+</body></html>`
+
+func TestParseSearch(t *testing.T) {
+	got := ParseSearch(searchPage)
+
+	want := []Result{
+		{
+			Path:       "gopkg.in/yaml.v3",
+			Synopsis:   "Package yaml implements YAML support for the Go language.",
+			Version:    "v3.0.1",
+			Published:  "May 27, 2022",
+			ImportedBy: 23456,
+			Licenses:   []string{"Apache-2.0", "MIT"},
+		},
+		{
+			Path:       "encoding/json",
+			Synopsis:   "Package json implements encoding & decoding of JSON.",
+			Version:    "go1.24.2",
+			Published:  "Apr 1, 2025",
+			ImportedBy: 1234567,
+			Licenses:   []string{"BSD-3-Clause"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSearch() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got[0].Standard() || !got[1].Standard() {
+		t.Error("Standard() should only be true for encoding/json")
+	}
+}
+
+func TestClient_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.URL.Query().Get("q") != "yaml parser" || r.URL.Query().Get("m") != "package" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, searchPage)
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL).Search(context.Background(), "yaml parser", 1)
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "gopkg.in/yaml.v3" {
+		t.Errorf("Search() = %+v, want only gopkg.in/yaml.v3", results)
+	}
+}
+
+func TestClient_SearchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).Search(context.Background(), "yaml", 10); err == nil {
+		t.Error("Search() expected error for 503")
+	}
+}
@@ -0,0 +1,110 @@
+// Package policy checks dependencies against the rules in the gx config.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/license"
+	"github.com/omarshaarawi/gx/internal/pattern"
+	"golang.org/x/mod/semver"
+)
+
+// Rule names reported in violations
+const (
+	RuleBanned     = "banned"
+	RuleMaxAge     = "max-age"
+	RuleLicense    = "license"
+	RuleMinVersion = "min-version"
+)
+
+// Dependency is the information about one module the rules are checked against.
+// Released, Latest and License may be left empty when the matching rule is not configured.
+type Dependency struct {
+	Path     string
+	Version  string
+	Indirect bool
+	Released time.Time
+	Latest   string
+	License  string
+}
+
+// Violation is a dependency that breaks a policy rule
+type Violation struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Evaluate checks every dependency against the policy, returning violations
+// sorted by module and rule
+func Evaluate(p config.Policy, deps []Dependency, now time.Time) []Violation {
+	var violations []Violation
+
+	add := func(d Dependency, rule, format string, args ...any) {
+		violations = append(violations, Violation{
+			Module:  d.Path,
+			Version: d.Version,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, d := range deps {
+		for _, b := range p.Banned {
+			if pattern.Match(b.Module, d.Path) {
+				if b.Reason != "" {
+					add(d, RuleBanned, "module is banned: %s", b.Reason)
+				} else {
+					add(d, RuleBanned, "module is banned")
+				}
+				break
+			}
+		}
+
+		if min, ok := minVersionFor(p.MinVersions, d.Path); ok && semver.Compare(d.Version, min) < 0 {
+			add(d, RuleMinVersion, "%s is below the required minimum %s", d.Version, min)
+		}
+
+		if p.MaxAgeDays > 0 && !d.Released.IsZero() && semver.Compare(d.Version, d.Latest) < 0 {
+			age := int(now.Sub(d.Released).Hours() / 24)
+			if age > p.MaxAgeDays {
+				add(d, RuleMaxAge, "%s is %d days old (limit %d) and %s is available", d.Version, age, p.MaxAgeDays, d.Latest)
+			}
+		}
+
+		if d.License != "" && d.License != license.Unknown {
+			for _, disallowed := range p.DisallowedLicenses {
+				if strings.EqualFold(d.License, disallowed) {
+					add(d, RuleLicense, "license %s is not allowed", d.License)
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Module != violations[j].Module {
+			return violations[i].Module < violations[j].Module
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+
+	return violations
+}
+
+// minVersionFor returns the minimum version for a module, preferring the most specific pattern
+func minVersionFor(minVersions map[string]string, modulePath string) (string, bool) {
+	version, best := "", ""
+	found := false
+	for p, v := range minVersions {
+		if pattern.Match(p, modulePath) && (!found || pattern.MoreSpecific(p, best)) {
+			version, best, found = v, p, true
+		}
+	}
+	return version, found
+}
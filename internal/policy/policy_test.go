@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/config"
+	"github.com/omarshaarawi/gx/internal/license"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	p := config.Policy{
+		Banned: []config.ModuleRule{
+			{Module: "github.com/pkg/errors", Reason: "use the standard library"},
+			{Module: "github.com/evil/*"},
+		},
+		MaxAgeDays:         365,
+		DisallowedLicenses: []string{"GPL-3.0"},
+		MinVersions: map[string]string{
+			"golang.org/x/*":      "v0.10.0",
+			"golang.org/x/crypto": "v0.31.0",
+		},
+	}
+
+	deps := []Dependency{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+		{Path: "github.com/evil/lib", Version: "v1.0.0", Indirect: true},
+		{Path: "golang.org/x/crypto", Version: "v0.20.0"},
+		{Path: "golang.org/x/text", Version: "v0.20.0"},
+		{Path: "golang.org/x/sys", Version: "v0.5.0"},
+		{Path: "github.com/old/lib", Version: "v1.0.0", Latest: "v1.1.0", Released: now.AddDate(-2, 0, 0)},
+		{Path: "github.com/stable/lib", Version: "v1.0.0", Latest: "v1.0.0", Released: now.AddDate(-5, 0, 0)},
+		{Path: "github.com/gpl/lib", Version: "v1.0.0", License: "gpl-3.0"},
+		{Path: "github.com/unknown/lib", Version: "v1.0.0", License: license.Unknown},
+	}
+
+	got := Evaluate(p, deps, now)
+
+	want := []struct{ module, rule string }{
+		{"github.com/evil/lib", RuleBanned},
+		{"github.com/gpl/lib", RuleLicense},
+		{"github.com/old/lib", RuleMaxAge},
+		{"github.com/pkg/errors", RuleBanned},
+		{"golang.org/x/crypto", RuleMinVersion},
+		{"golang.org/x/sys", RuleMinVersion},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Evaluate() = %d violations, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Module != w.module || got[i].Rule != w.rule {
+			t.Errorf("violation %d = %s/%s, want %s/%s", i, got[i].Module, got[i].Rule, w.module, w.rule)
+		}
+	}
+
+	if got[3].Message != "module is banned: use the standard library" {
+		t.Errorf("banned message = %q", got[3].Message)
+	}
+	if got[4].Message != "v0.20.0 is below the required minimum v0.31.0" {
+		t.Errorf("min-version message = %q", got[4].Message)
+	}
+}
+
+func TestEvaluate_EmptyPolicy(t *testing.T) {
+	deps := []Dependency{{Path: "github.com/pkg/errors", Version: "v0.9.1"}}
+	if got := Evaluate(config.Policy{}, deps, time.Now()); len(got) != 0 {
+		t.Errorf("Evaluate() with empty policy = %+v", got)
+	}
+}
@@ -0,0 +1,183 @@
+// Package protogen finds Go code generated from protocol buffers and checks
+// it against the protobuf and gRPC runtimes a module is updated to.
+package protogen
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// The runtime modules whose updates can leave generated code behind
+const (
+	ProtobufModule = "google.golang.org/protobuf"
+	GRPCModule     = "google.golang.org/grpc"
+)
+
+// The generators writing the code those runtimes run
+const (
+	ProtocGenGo     = "protoc-gen-go"
+	ProtocGenGoGRPC = "protoc-gen-go-grpc"
+)
+
+// headerSize is how much of a file is read for its header
+const headerSize = 2048
+
+var (
+	generatedRE = regexp.MustCompile(`(?m)^// Code generated by (protoc-gen-go(?:-grpc)?)\. DO NOT EDIT\.`)
+	versionRE   = regexp.MustCompile(`(?m)^//\s*(?:-\s*)?(protoc-gen-go(?:-grpc)?)\s+(v\S+)`)
+)
+
+// supported pairs releases of each runtime with the oldest generator whose
+// code they still run, newest first. Runtimes keep running older generated
+// code: protobuf code asserts protoimpl.EnforceVersion(20 -
+// protoimpl.MinVersion), and MinVersion has been 0 since v1.20.0, the
+// module's first release, so any protoc-gen-go of the module will do. gRPC
+// still defines every grpc.SupportPackageIsVersionN constant generated code
+// refers to, so it has no rows until a release drops one.
+var supported = map[string][]struct{ runtime, generator string }{
+	ProtobufModule: {
+		{"v1.20.0", "v1.20.0"},
+	},
+}
+
+// File is a generated Go file and the generator that wrote it
+type File struct {
+	Path      string // relative to the scanned directory
+	Generator string // protoc-gen-go or protoc-gen-go-grpc
+	Version   string // generator version, "" when the header doesn't say
+}
+
+// Stale is generated code older than the runtime it is updated to supports
+type Stale struct {
+	File
+	Oldest string // the oldest generator version the runtime supports
+	Want   string // the generator version to regenerate with
+}
+
+// Install returns the command installing the generator version to
+// regenerate with
+func (s Stale) Install() string {
+	module := ProtobufModule + "/cmd/" + ProtocGenGo
+	if s.Generator == ProtocGenGoGRPC {
+		module = GRPCModule + "/cmd/" + ProtocGenGoGRPC
+	}
+	return "go install " + module + "@" + s.Want
+}
+
+// ParseHeader reads the generator and its version from the header of a
+// generated file. ok is false for files not written by either generator.
+func ParseHeader(data []byte) (generator, version string, ok bool) {
+	m := generatedRE.FindSubmatch(data)
+	if m == nil {
+		return "", "", false
+	}
+	generator = string(m[1])
+	for _, v := range versionRE.FindAllSubmatch(data, -1) {
+		if string(v[1]) == generator && semver.IsValid(string(v[2])) {
+			version = string(v[2])
+		}
+	}
+	return generator, version, true
+}
+
+// Scan finds the generated .pb.go files of the module in root. Like the go
+// command it skips vendor and testdata directories, those starting with "."
+// or "_", and nested modules.
+func Scan(root string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".pb.go") {
+			return nil
+		}
+
+		header, err := readHeader(path)
+		if err != nil {
+			return err
+		}
+		generator, version, ok := ParseHeader(header)
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: filepath.ToSlash(rel), Generator: generator, Version: version})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s for generated code: %w", root, err)
+	}
+	return files, nil
+}
+
+func readHeader(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, headerSize))
+}
+
+// Check returns the files an update of a runtime module to version leaves
+// stale: generated by an older generator than the runtime supports. They're
+// to be regenerated with the protoc-gen-go of the same version as the
+// protobuf runtime, which it ships with, or the latest protoc-gen-go-grpc.
+// Files whose generator version is unknown are left out.
+func Check(files []File, modulePath, version string) []Stale {
+	var generator, want string
+	switch modulePath {
+	case ProtobufModule:
+		generator, want = ProtocGenGo, version
+	case GRPCModule:
+		generator, want = ProtocGenGoGRPC, "latest"
+	default:
+		return nil
+	}
+
+	oldest := ""
+	for _, s := range supported[modulePath] {
+		if semver.Compare(version, s.runtime) >= 0 {
+			oldest = s.generator
+			break
+		}
+	}
+	if oldest == "" {
+		return nil
+	}
+
+	var stale []Stale
+	for _, f := range files {
+		if f.Generator != generator || f.Version == "" {
+			continue
+		}
+		if semver.Compare(f.Version, oldest) < 0 {
+			stale = append(stale, Stale{File: f, Oldest: oldest, Want: want})
+		}
+	}
+	return stale
+}
@@ -0,0 +1,114 @@
+package protogen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const pbHeader = `// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.12
+// source: api/v1/service.proto
+
+package apiv1
+`
+
+const grpcHeader = `// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: api/v1/service.proto
+
+package apiv1
+`
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		generator string
+		version   string
+		ok        bool
+	}{
+		{"protoc-gen-go", pbHeader, ProtocGenGo, "v1.28.1", true},
+		{"protoc-gen-go-grpc", grpcHeader, ProtocGenGoGRPC, "v1.3.0", true},
+		{"no versions", "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: a.proto\n", ProtocGenGo, "", true},
+		{"unknown version", "// Code generated by protoc-gen-go. DO NOT EDIT.\n// versions:\n// \tprotoc-gen-go (unknown)\n", ProtocGenGo, "", true},
+		{"other generator", "// Code generated by mockgen. DO NOT EDIT.\n", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, version, ok := ParseHeader([]byte(tt.data))
+			if generator != tt.generator || version != tt.version || ok != tt.ok {
+				t.Errorf("ParseHeader() = %q, %q, %v, want %q, %q, %v", generator, version, ok, tt.generator, tt.version, tt.ok)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/app\n")
+	write("api/v1/service.pb.go", pbHeader)
+	write("api/v1/service_grpc.pb.go", grpcHeader)
+	write("api/v1/handwritten.pb.go", "package apiv1\n")
+	write("vendor/example.com/dep/dep.pb.go", pbHeader)
+	write("tools/go.mod", "module example.com/app/tools\n")
+	write("tools/tools.pb.go", pbHeader)
+
+	files, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	want := []File{
+		{Path: "api/v1/service.pb.go", Generator: ProtocGenGo, Version: "v1.28.1"},
+		{Path: "api/v1/service_grpc.pb.go", Generator: ProtocGenGoGRPC, Version: "v1.3.0"},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Scan() = %+v, want %+v", files, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	files := []File{
+		{Path: "a.pb.go", Generator: ProtocGenGo, Version: "v1.28.1"},
+		{Path: "b.pb.go", Generator: ProtocGenGo, Version: "v1.36.2"},
+		{Path: "c.pb.go", Generator: ProtocGenGo},
+		{Path: "d.pb.go", Generator: ProtocGenGo, Version: "v1.3.2"},
+		{Path: "a_grpc.pb.go", Generator: ProtocGenGoGRPC, Version: "v1.3.0"},
+	}
+
+	// The runtime still runs code from any protoc-gen-go since v1.20.0, so
+	// only d.pb.go, from an older generator, is stale.
+	stale := Check(files, ProtobufModule, "v1.36.0")
+	if len(stale) != 1 || stale[0].Path != "d.pb.go" || stale[0].Oldest != "v1.20.0" || stale[0].Want != "v1.36.0" {
+		t.Fatalf("Check(protobuf) = %+v", stale)
+	}
+	if got := stale[0].Install(); got != "go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.0" {
+		t.Errorf("Install() = %q", got)
+	}
+
+	// gRPC still defines the SupportPackageIsVersion constant older
+	// protoc-gen-go-grpc code refers to.
+	if stale := Check(files, GRPCModule, "v1.65.0"); len(stale) != 0 {
+		t.Errorf("Check(grpc v1.65.0) = %+v, want none", stale)
+	}
+	if stale := Check(files, "example.com/other", "v1.0.0"); len(stale) != 0 {
+		t.Errorf("Check(other) = %+v, want none", stale)
+	}
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// breakerThreshold is how many lookups in a row must fail before a host
+	// is skipped
+	breakerThreshold = 3
+	// breakerCooldown is how long a host is skipped before one lookup is
+	// allowed through again
+	breakerCooldown = time.Minute
+)
+
+// ErrHostUnavailable is returned without contacting the proxy for modules on
+// a host whose recent lookups kept failing
+var ErrHostUnavailable = errors.New("host unavailable")
+
+// breaker tracks consecutive lookup failures per module host, so one dead
+// vanity domain fails fast instead of costing a full timeout per module
+type breaker struct {
+	mu    sync.Mutex
+	hosts map[string]*hostState
+	now   func() time.Time
+}
+
+type hostState struct {
+	failures  int
+	openUntil time.Time
+}
+
+func newBreaker() *breaker {
+	return &breaker{hosts: make(map[string]*hostState), now: time.Now}
+}
+
+// allow returns ErrHostUnavailable while the host's breaker is open
+func (b *breaker) allow(host string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h := b.hosts[host]
+	if h == nil || h.failures < breakerThreshold {
+		return nil
+	}
+	if b.now().Before(h.openUntil) {
+		return fmt.Errorf("%w: %s failed %d lookups in a row, skipped", ErrHostUnavailable, host, h.failures)
+	}
+	// Half-open: let this lookup through, and reopen at once if it fails
+	h.openUntil = b.now().Add(breakerCooldown)
+	return nil
+}
+
+// record updates the host's state after a lookup
+func (b *breaker) record(host string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		delete(b.hosts, host)
+		return
+	}
+
+	h := b.hosts[host]
+	if h == nil {
+		h = &hostState{}
+		b.hosts[host] = h
+	}
+	h.failures++
+	if h.failures >= breakerThreshold {
+		h.openUntil = b.now().Add(breakerCooldown)
+	}
+}
+
+// moduleHost returns the first element of a module path, the host serving it
+func moduleHost(modulePath string) string {
+	host, _, _ := strings.Cut(modulePath, "/")
+	return host
+}
@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBreaker()
+	b.now = func() time.Time { return now }
+
+	for range breakerThreshold - 1 {
+		b.record("go.dead.dev", true)
+	}
+	if err := b.allow("go.dead.dev"); err != nil {
+		t.Fatalf("allow() before threshold = %v, want nil", err)
+	}
+
+	b.record("go.dead.dev", true)
+	if err := b.allow("go.dead.dev"); !errors.Is(err, ErrHostUnavailable) {
+		t.Fatalf("allow() after threshold = %v, want ErrHostUnavailable", err)
+	}
+	if err := b.allow("github.com"); err != nil {
+		t.Errorf("allow() for another host = %v, want nil", err)
+	}
+
+	// After the cooldown one lookup goes through; the rest wait for its result
+	now = now.Add(breakerCooldown)
+	if err := b.allow("go.dead.dev"); err != nil {
+		t.Fatalf("allow() after cooldown = %v, want nil", err)
+	}
+	if err := b.allow("go.dead.dev"); !errors.Is(err, ErrHostUnavailable) {
+		t.Errorf("second allow() while half-open = %v, want ErrHostUnavailable", err)
+	}
+
+	b.record("go.dead.dev", false)
+	if err := b.allow("go.dead.dev"); err != nil {
+		t.Errorf("allow() after a success = %v, want nil", err)
+	}
+}
+
+func TestModuleHost(t *testing.T) {
+	tests := map[string]string{
+		"github.com/spf13/cobra": "github.com",
+		"gopkg.in/yaml.v3":       "gopkg.in",
+		"rsc.io/quote/v3":        "rsc.io",
+		"example":                "example",
+	}
+	for path, want := range tests {
+		if got := moduleHost(path); got != want {
+			t.Errorf("moduleHost(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
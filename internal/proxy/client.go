@@ -3,27 +3,41 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
-	"unicode"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
-const defaultMaxConcurrent = 10
+const (
+	defaultMaxConcurrent = 10
+	defaultLookupTimeout = 10 * time.Second
+)
 
-func escapePath(path string) string {
-	var result []byte
-	for _, r := range path {
-		if unicode.IsUpper(r) {
-			result = append(result, '!')
-			result = append(result, byte(unicode.ToLower(r)))
-		} else {
-			result = append(result, byte(r))
-		}
+// moduleURL builds the URL of a module endpoint such as @latest or @v/list.
+// Paths use the proxy protocol's case encoding, so github.com/BurntSushi/toml
+// is requested as github.com/!burnt!sushi/toml.
+func (c *Client) moduleURL(modulePath, endpoint string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
 	}
-	return string(result)
+	return fmt.Sprintf("%s/%s/%s", c.baseURL, escaped, endpoint), nil
+}
+
+// versionURL builds the URL of a version's .info, .mod or .zip file
+func (c *Client) versionURL(modulePath, version, ext string) (string, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return c.moduleURL(modulePath, "@v/"+escaped+"."+ext)
 }
 
 // Client is a Go module proxy client
@@ -32,8 +46,24 @@ type Client struct {
 	http    *http.Client
 	cache   Cache
 	sem     chan struct{}
+	ttl     time.Duration
+
+	lookupTimeout time.Duration
+	breaker       *breaker
+
+	localMu sync.RWMutex
+	local   map[string]bool
+
+	isPrivate func(modulePath string) bool
+	private   Resolver
 }
 
+// Resolver looks up versions of private modules without the proxy
+type Resolver interface {
+	Latest(ctx context.Context, modulePath string) (*VersionInfo, error)
+	Versions(ctx context.Context, modulePath string) ([]string, error)
+	Info(ctx context.Context, modulePath, version string) (*VersionInfo, error)
+}
 
 // VersionInfo represents module version metadata
 type VersionInfo struct {
@@ -53,6 +83,10 @@ func NewClient(baseURL string) *Client {
 		},
 		cache: NewMemoryCache(),
 		sem:   make(chan struct{}, defaultMaxConcurrent),
+		ttl:   5 * time.Minute,
+
+		lookupTimeout: defaultLookupTimeout,
+		breaker:       newBreaker(),
 	}
 }
 
@@ -62,35 +96,226 @@ func (c *Client) WithCache(cache Cache) *Client {
 	return c
 }
 
+// WithTimeout sets the HTTP timeout for proxy requests
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	if timeout > 0 {
+		c.http.Timeout = timeout
+	}
+	return c
+}
+
+// WithLookupTimeout sets the deadline for a single metadata lookup, counted
+// from when the request starts rather than while it waits for a slot
+func (c *Client) WithLookupTimeout(timeout time.Duration) *Client {
+	if timeout > 0 {
+		c.lookupTimeout = timeout
+	}
+	return c
+}
+
+// WithLocalModules replaces the set of modules that are replaced by local
+// directories. Lookups for them fail with ErrLocalModule without a request,
+// since the proxy can't know them and asking would leak private names.
+func (c *Client) WithLocalModules(modulePaths []string) *Client {
+	local := make(map[string]bool, len(modulePaths))
+	for _, p := range modulePaths {
+		local[p] = true
+	}
+
+	c.localMu.Lock()
+	c.local = local
+	c.localMu.Unlock()
+	return c
+}
+
+// WithPrivate sends version lookups for modules matching isPrivate to
+// resolver instead of the proxy. Their go.mod files and zips aren't fetched.
+func (c *Client) WithPrivate(isPrivate func(modulePath string) bool, resolver Resolver) *Client {
+	c.isPrivate = isPrivate
+	c.private = resolver
+	return c
+}
+
+// WithMaxConcurrent limits the number of in-flight proxy requests
+func (c *Client) WithMaxConcurrent(n int) *Client {
+	if n > 0 {
+		c.sem = make(chan struct{}, n)
+	}
+	return c
+}
+
+// WithCacheTTL sets how long @latest and @v/list responses are cached
+func (c *Client) WithCacheTTL(ttl time.Duration) *Client {
+	if ttl > 0 {
+		c.ttl = ttl
+	}
+	return c
+}
+
+// ErrLocalModule is returned for modules replaced by a local directory
+var ErrLocalModule = errors.New("replaced by a local directory")
+
+func (c *Client) checkLocal(modulePath string) error {
+	c.localMu.RLock()
+	defer c.localMu.RUnlock()
+
+	if c.local[modulePath] {
+		return fmt.Errorf("%s: %w", modulePath, ErrLocalModule)
+	}
+	return nil
+}
+
+// ErrPrivateModule is returned when fetching files of a private module,
+// which the proxy must not be asked about
+var ErrPrivateModule = errors.New("private module, not fetched from the proxy")
+
+func (c *Client) privateModule(modulePath string) bool {
+	return c.private != nil && c.isPrivate(modulePath)
+}
+
+// resolvePrivate runs a private lookup under the same deadline and host
+// breaker as proxy lookups
+func (c *Client) resolvePrivate(ctx context.Context, modulePath string, fn func(ctx context.Context) error) error {
+	if err := c.checkLocal(modulePath); err != nil {
+		return err
+	}
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	host := moduleHost(modulePath)
+	if err := c.breaker.allow(host); err != nil {
+		return err
+	}
+
+	lookupCtx := ctx
+	if c.lookupTimeout > 0 {
+		var cancel context.CancelFunc
+		lookupCtx, cancel = context.WithTimeout(ctx, c.lookupTimeout)
+		defer cancel()
+	}
+
+	err := fn(lookupCtx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil && errors.Is(lookupCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("resolving %s: timed out after %s", modulePath, c.lookupTimeout)
+	}
+	c.breaker.record(host, err != nil)
+	return err
+}
+
+// StatusError is a non-200 response from the proxy
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("proxy returned %d: %s", e.Code, e.Body)
+}
+
+// lookup fetches module metadata with the per-lookup deadline, skipping the
+// request while the module's host breaker is open. Timeouts, network errors
+// and 5xx responses count against the host; a missing module does not.
+func (c *Client) lookup(ctx context.Context, modulePath, url string) ([]byte, error) {
+	if err := c.checkLocal(modulePath); err != nil {
+		return nil, err
+	}
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	// Checked once a slot is free, so lookups queued behind a failing host
+	// see its breaker open
+	host := moduleHost(modulePath)
+	if err := c.breaker.allow(host); err != nil {
+		return nil, err
+	}
+
+	body, err := c.fetch(ctx, url, c.lookupTimeout)
+	if ctx.Err() != nil {
+		// Cancelled by the caller, which says nothing about the host
+		return nil, ctx.Err()
+	}
+
+	var status *StatusError
+	c.breaker.record(host, err != nil && !(errors.As(err, &status) && status.Code < 500))
+	return body, err
+}
+
 func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	return c.fetch(ctx, url, 0)
+}
+
+// acquire waits for one of the client's request slots
+func (c *Client) acquire(ctx context.Context) error {
 	select {
 	case c.sem <- struct{}{}:
-		defer func() { <-c.sem }()
+		return nil
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return ctx.Err()
+	}
+}
+
+func (c *Client) release() {
+	<-c.sem
+}
+
+// fetch performs one GET, limited to timeout when it's positive
+func (c *Client) fetch(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	reqCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(reqCtx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
+		if ctx.Err() == nil && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("fetching %s: timed out after %s", url, timeout)
+		}
 		return nil, fmt.Errorf("fetching %s: %w", url, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("proxy returned %d: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{Code: resp.StatusCode, Body: string(body)}
 	}
 
-	return io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil && ctx.Err() == nil && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("reading %s: timed out after %s", url, timeout)
+	}
+	return body, err
 }
 
 // Latest fetches the latest version info for a module
 func (c *Client) Latest(ctx context.Context, modulePath string) (*VersionInfo, error) {
+	if c.privateModule(modulePath) {
+		var info *VersionInfo
+		err := c.resolvePrivate(ctx, modulePath, func(ctx context.Context) (err error) {
+			info, err = c.private.Latest(ctx, modulePath)
+			return err
+		})
+		return info, err
+	}
+
 	cacheKey := modulePath + "@latest"
 	if cached, ok := c.cache.Get(cacheKey); ok {
 		if info, ok := cached.(*VersionInfo); ok {
@@ -98,24 +323,165 @@ func (c *Client) Latest(ctx context.Context, modulePath string) (*VersionInfo, e
 		}
 	}
 
-	url := fmt.Sprintf("%s/%s/@latest", c.baseURL, escapePath(modulePath))
-	body, err := c.doRequest(ctx, url)
+	url, err := c.moduleURL(modulePath, "@latest")
 	if err != nil {
 		return nil, err
 	}
 
-	var info VersionInfo
-	if err := json.Unmarshal(body, &info); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	info, err := c.queryLatest(ctx, modulePath, url)
+	if err != nil {
+		return nil, err
 	}
 
-	c.cache.Set(cacheKey, &info, 5*time.Minute)
+	c.cache.Set(cacheKey, info, c.ttl)
 
-	return &info, nil
+	return info, nil
+}
+
+// LatestPre is like Latest but also considers pre-releases: when the version
+// list has a pre-release newer than the latest version, that is returned
+func (c *Client) LatestPre(ctx context.Context, modulePath string) (*VersionInfo, error) {
+	latest, err := c.Latest(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a list there is nothing newer to offer than @latest
+	list, err := c.Versions(ctx, modulePath)
+	if err != nil {
+		return latest, nil
+	}
+
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	newest := newestVersion(list, pathMajor)
+	if semver.Compare(newest, latest.Version) <= 0 {
+		return latest, nil
+	}
+	return c.Info(ctx, modulePath, newest)
+}
+
+// LatestAllowed returns the highest version for the path's major version
+// that allow accepts, considering pre-releases only when pre is set. It
+// returns nil when no listed version is allowed.
+func (c *Client) LatestAllowed(ctx context.Context, modulePath string, pre bool, allow func(version string) bool) (*VersionInfo, error) {
+	list, err := c.Versions(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	best := HighestAllowed(modulePath, list, pre, allow)
+	if best == "" {
+		return nil, nil
+	}
+	return c.Info(ctx, modulePath, best)
+}
+
+// HighestAllowed picks the version LatestAllowed looks up from a version
+// list, "" when none is allowed
+func HighestAllowed(modulePath string, list []string, pre bool, allow func(version string) bool) string {
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	best := ""
+	for _, v := range list {
+		if module.CheckPathMajor(v, pathMajor) != nil || (!pre && semver.Prerelease(v) != "") || !allow(v) {
+			continue
+		}
+		if semver.Compare(v, best) > 0 {
+			best = v
+		}
+	}
+	return best
+}
+
+// queryLatest asks the proxy for @latest and falls back to the version list
+// the way the go command does. Some proxies answer 404 or 410 for @latest on
+// modules that do have tagged versions, answer with a pseudo-version although
+// tags exist, or, for gopkg.in and vanity paths whose repository moved on,
+// answer with a version whose major doesn't match the path.
+func (c *Client) queryLatest(ctx context.Context, modulePath, url string) (*VersionInfo, error) {
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+
+	var info *VersionInfo
+	body, err := c.lookup(ctx, modulePath, url)
+	if err == nil {
+		info = &VersionInfo{}
+		if err := json.Unmarshal(body, info); err != nil {
+			return nil, fmt.Errorf("decoding response: %w", err)
+		}
+		if !semver.IsValid(info.Version) || module.CheckPathMajor(info.Version, pathMajor) != nil {
+			info = nil
+		} else if !module.IsPseudoVersion(info.Version) {
+			return info, nil
+		}
+	} else {
+		var status *StatusError
+		if !errors.As(err, &status) || (status.Code != http.StatusNotFound && status.Code != http.StatusGone) {
+			return nil, err
+		}
+	}
+
+	list, listErr := c.Versions(ctx, modulePath)
+	if listErr == nil {
+		if latest := latestVersion(list, pathMajor); latest != "" {
+			return c.Info(ctx, modulePath, latest)
+		}
+	}
+
+	switch {
+	case info != nil:
+		return info, nil
+	case err != nil:
+		return nil, err
+	case listErr != nil:
+		return nil, listErr
+	}
+	return nil, fmt.Errorf("%s: proxy has no version matching the module path", modulePath)
 }
 
-// Versions fetches all available versions for a module
+// latestVersion picks the highest release for the path's major version, or
+// the highest pre-release when there is no release
+func latestVersion(list []string, pathMajor string) string {
+	release, prerelease := "", ""
+	for _, v := range list {
+		if module.CheckPathMajor(v, pathMajor) != nil {
+			continue
+		}
+		if semver.Prerelease(v) == "" {
+			if semver.Compare(v, release) > 0 {
+				release = v
+			}
+		} else if semver.Compare(v, prerelease) > 0 {
+			prerelease = v
+		}
+	}
+	if release != "" {
+		return release
+	}
+	return prerelease
+}
+
+// newestVersion picks the highest version for the path's major version,
+// pre-releases included
+func newestVersion(list []string, pathMajor string) string {
+	newest := ""
+	for _, v := range list {
+		if module.CheckPathMajor(v, pathMajor) == nil && semver.Compare(v, newest) > 0 {
+			newest = v
+		}
+	}
+	return newest
+}
+
+// Versions fetches all available versions for a module, oldest first
 func (c *Client) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	if c.privateModule(modulePath) {
+		var versions []string
+		err := c.resolvePrivate(ctx, modulePath, func(ctx context.Context) (err error) {
+			versions, err = c.private.Versions(ctx, modulePath)
+			return err
+		})
+		return versions, err
+	}
+
 	cacheKey := modulePath + "@list"
 	if cached, ok := c.cache.Get(cacheKey); ok {
 		if versions, ok := cached.([]string); ok {
@@ -123,20 +489,48 @@ func (c *Client) Versions(ctx context.Context, modulePath string) ([]string, err
 		}
 	}
 
-	url := fmt.Sprintf("%s/%s/@v/list", c.baseURL, escapePath(modulePath))
-	body, err := c.doRequest(ctx, url)
+	url, err := c.moduleURL(modulePath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.lookup(ctx, modulePath, url)
 	if err != nil {
 		return nil, err
 	}
 
-	versions := strings.Split(strings.TrimSpace(string(body)), "\n")
-	c.cache.Set(cacheKey, versions, 5*time.Minute)
+	versions := parseVersionList(string(body))
+	c.cache.Set(cacheKey, versions, c.ttl)
 
 	return versions, nil
 }
 
+// parseVersionList reads an @v/list response, skipping blank lines and
+// anything that isn't a semantic version, oldest first
+func parseVersionList(body string) []string {
+	versions := []string{}
+	for _, line := range strings.Split(body, "\n") {
+		// Some proxies append fields after the version, or use CRLF
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !semver.IsValid(fields[0]) {
+			continue
+		}
+		versions = append(versions, fields[0])
+	}
+	semver.Sort(versions)
+	return versions
+}
+
 // Info fetches version info for a specific module version
 func (c *Client) Info(ctx context.Context, modulePath, version string) (*VersionInfo, error) {
+	if c.privateModule(modulePath) {
+		var info *VersionInfo
+		err := c.resolvePrivate(ctx, modulePath, func(ctx context.Context) (err error) {
+			info, err = c.private.Info(ctx, modulePath, version)
+			return err
+		})
+		return info, err
+	}
+
 	cacheKey := modulePath + "@" + version
 	if cached, ok := c.cache.Get(cacheKey); ok {
 		if info, ok := cached.(*VersionInfo); ok {
@@ -144,8 +538,11 @@ func (c *Client) Info(ctx context.Context, modulePath, version string) (*Version
 		}
 	}
 
-	url := fmt.Sprintf("%s/%s/@v/%s.info", c.baseURL, escapePath(modulePath), version)
-	body, err := c.doRequest(ctx, url)
+	url, err := c.versionURL(modulePath, version, "info")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.lookup(ctx, modulePath, url)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +559,9 @@ func (c *Client) Info(ctx context.Context, modulePath, version string) (*Version
 
 // GetModFile fetches the go.mod file for a specific module version
 func (c *Client) GetModFile(ctx context.Context, modulePath, version string) ([]byte, error) {
+	if c.privateModule(modulePath) {
+		return nil, fmt.Errorf("%s: %w", modulePath, ErrPrivateModule)
+	}
 	cacheKey := modulePath + "@" + version + ".mod"
 	if cached, ok := c.cache.Get(cacheKey); ok {
 		if data, ok := cached.([]byte); ok {
@@ -169,8 +569,11 @@ func (c *Client) GetModFile(ctx context.Context, modulePath, version string) ([]
 		}
 	}
 
-	url := fmt.Sprintf("%s/%s/@v/%s.mod", c.baseURL, escapePath(modulePath), version)
-	data, err := c.doRequest(ctx, url)
+	url, err := c.versionURL(modulePath, version, "mod")
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.lookup(ctx, modulePath, url)
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +583,18 @@ func (c *Client) GetModFile(ctx context.Context, modulePath, version string) ([]
 	return data, nil
 }
 
+// GetZip downloads the module zip for a specific module version.
+// Zips are not cached since they can be large.
+func (c *Client) GetZip(ctx context.Context, modulePath, version string) ([]byte, error) {
+	if err := c.checkLocal(modulePath); err != nil {
+		return nil, err
+	}
+	if c.privateModule(modulePath) {
+		return nil, fmt.Errorf("%s: %w", modulePath, ErrPrivateModule)
+	}
+	url, err := c.versionURL(modulePath, version, "zip")
+	if err != nil {
+		return nil, err
+	}
+	return c.doRequest(ctx, url)
+}
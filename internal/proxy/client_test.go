@@ -3,11 +3,19 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/omarshaarawi/gx/internal/benchdata"
+	"golang.org/x/mod/semver"
 )
 
 func TestNewClient(t *testing.T) {
@@ -75,6 +83,28 @@ func TestClient_WithCache(t *testing.T) {
 	}
 }
 
+func TestClient_WithOptions(t *testing.T) {
+	client := NewClient("").
+		WithTimeout(5 * time.Second).
+		WithMaxConcurrent(3).
+		WithCacheTTL(time.Hour)
+
+	if client.http.Timeout != 5*time.Second {
+		t.Errorf("http timeout = %v, want %v", client.http.Timeout, 5*time.Second)
+	}
+	if cap(client.sem) != 3 {
+		t.Errorf("sem capacity = %d, want 3", cap(client.sem))
+	}
+	if client.ttl != time.Hour {
+		t.Errorf("ttl = %v, want %v", client.ttl, time.Hour)
+	}
+
+	client.WithTimeout(0).WithMaxConcurrent(0).WithCacheTTL(0)
+	if client.http.Timeout != 5*time.Second || cap(client.sem) != 3 || client.ttl != time.Hour {
+		t.Error("zero values should leave settings unchanged")
+	}
+}
+
 func TestClient_Latest(t *testing.T) {
 	expectedInfo := VersionInfo{
 		Version: "v1.2.3",
@@ -196,6 +226,219 @@ func TestClient_Latest_ContextCanceled(t *testing.T) {
 	}
 }
 
+func TestClient_EscapedPaths(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		json.NewEncoder(w).Encode(VersionInfo{Version: "v3.0.1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	if _, err := client.Latest(ctx, "gopkg.in/yaml.v3"); err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if _, err := client.Info(ctx, "github.com/BurntSushi/toml", "v1.0.0-RC1"); err != nil {
+		t.Fatalf("Info() error: %v", err)
+	}
+
+	want := []string{
+		"/gopkg.in/yaml.v3/@latest",
+		"/github.com/!burnt!sushi/toml/@v/v1.0.0-!r!c1.info",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("requested %v, want %v", paths, want)
+	}
+
+	if _, err := client.Latest(ctx, "not a module"); err == nil {
+		t.Error("Latest() with a malformed path succeeded")
+	}
+}
+
+func TestClient_Latest_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name   string
+		module string
+		latest func(w http.ResponseWriter)
+		list   string
+		want   string
+	}{
+		{
+			name:   "gone",
+			module: "github.com/test/module",
+			latest: func(w http.ResponseWriter) { http.Error(w, "gone", http.StatusGone) },
+			list:   "v1.0.0\nv1.1.0\nv1.2.0-rc.1\n",
+			want:   "v1.1.0",
+		},
+		{
+			name:   "pseudo-version despite tags",
+			module: "github.com/test/module",
+			latest: func(w http.ResponseWriter) {
+				json.NewEncoder(w).Encode(VersionInfo{Version: "v0.0.0-20240101000000-abcdefabcdef"})
+			},
+			list: "v0.1.0\n",
+			want: "v0.1.0",
+		},
+		{
+			name:   "gopkg.in major mismatch",
+			module: "gopkg.in/yaml.v2",
+			latest: func(w http.ResponseWriter) { json.NewEncoder(w).Encode(VersionInfo{Version: "v3.0.1"}) },
+			list:   "v2.4.0\nv2.2.8\nv3.0.1\n",
+			want:   "v2.4.0",
+		},
+		{
+			name:   "vanity path major mismatch",
+			module: "go.example.dev/lib/v2",
+			latest: func(w http.ResponseWriter) { json.NewEncoder(w).Encode(VersionInfo{Version: "v1.8.0"}) },
+			list:   "v2.0.0-beta.1\n",
+			want:   "v2.0.0-beta.1",
+		},
+		{
+			name:   "pseudo-version without tags",
+			module: "github.com/test/module",
+			latest: func(w http.ResponseWriter) {
+				json.NewEncoder(w).Encode(VersionInfo{Version: "v0.0.0-20240101000000-abcdefabcdef"})
+			},
+			want: "v0.0.0-20240101000000-abcdefabcdef",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/@latest"):
+					tt.latest(w)
+				case strings.HasSuffix(r.URL.Path, "/@v/list"):
+					w.Write([]byte(tt.list))
+				case strings.HasSuffix(r.URL.Path, ".info"):
+					v := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".info")
+					json.NewEncoder(w).Encode(VersionInfo{Version: v})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			info, err := NewClient(server.URL).Latest(context.Background(), tt.module)
+			if err != nil {
+				t.Fatalf("Latest() error: %v", err)
+			}
+			if info.Version != tt.want {
+				t.Errorf("Latest() = %s, want %s", info.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_LatestPre(t *testing.T) {
+	tests := []struct {
+		name   string
+		module string
+		latest string
+		list   string
+		want   string
+	}{
+		{"newer pre-release", "github.com/test/module", "v1.1.0", "v1.0.0\nv1.1.0\nv1.2.0-rc.1\nv1.2.0-beta.2\n", "v1.2.0-rc.1"},
+		{"older pre-release", "github.com/test/module", "v1.1.0", "v1.0.0\nv1.1.0-rc.1\nv1.1.0\n", "v1.1.0"},
+		{"other major", "github.com/test/module", "v1.1.0", "v1.1.0\nv2.0.0-alpha.1\n", "v1.1.0"},
+		{"no list", "github.com/test/module", "v1.1.0", "", "v1.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/@latest"):
+					json.NewEncoder(w).Encode(VersionInfo{Version: tt.latest})
+				case strings.HasSuffix(r.URL.Path, "/@v/list") && tt.list != "":
+					w.Write([]byte(tt.list))
+				case strings.HasSuffix(r.URL.Path, ".info"):
+					v := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".info")
+					json.NewEncoder(w).Encode(VersionInfo{Version: v})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			info, err := NewClient(server.URL).LatestPre(context.Background(), tt.module)
+			if err != nil {
+				t.Fatalf("LatestPre() error: %v", err)
+			}
+			if info.Version != tt.want {
+				t.Errorf("LatestPre() = %s, want %s", info.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_LatestAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/@v/list"):
+			w.Write([]byte("v0.18.0\nv0.19.1\nv0.19.2-rc.1\nv0.20.0\nv2.0.0\n"))
+		case strings.HasSuffix(r.URL.Path, ".info"):
+			v := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".info")
+			json.NewEncoder(w).Encode(VersionInfo{Version: v})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	below := func(limit string) func(string) bool {
+		return func(v string) bool { return semver.Compare(v, limit) < 0 }
+	}
+
+	tests := []struct {
+		name  string
+		pre   bool
+		limit string
+		want  string
+	}{
+		{"release", false, "v0.20.0", "v0.19.1"},
+		{"pre-release", true, "v0.20.0", "v0.19.2-rc.1"},
+		{"other major skipped", false, "v3.0.0", "v0.20.0"},
+		{"none allowed", false, "v0.1.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := client.LatestAllowed(context.Background(), "github.com/test/module", tt.pre, below(tt.limit))
+			if err != nil {
+				t.Fatalf("LatestAllowed() error: %v", err)
+			}
+			got := ""
+			if info != nil {
+				got = info.Version
+			}
+			if got != tt.want {
+				t.Errorf("LatestAllowed() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Latest_NotFoundWithoutVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/@v/list") {
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).Latest(context.Background(), "github.com/test/module")
+	var status *StatusError
+	if !errors.As(err, &status) || status.Code != http.StatusNotFound {
+		t.Errorf("Latest() error = %v, want the 404 from @latest", err)
+	}
+}
+
 func TestClient_Versions(t *testing.T) {
 	expectedVersions := []string{"v1.0.0", "v1.1.0", "v1.2.0"}
 
@@ -243,8 +486,23 @@ func TestClient_Versions_EmptyList(t *testing.T) {
 		t.Fatalf("Versions() error: %v", err)
 	}
 
-	if len(versions) != 1 || versions[0] != "" {
-		t.Errorf("Empty versions list should return [\"\"], got %v", versions)
+	if len(versions) != 0 {
+		t.Errorf("Empty versions list should return no versions, got %v", versions)
+	}
+}
+
+func TestClient_Versions_Messy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v1.10.0\r\n\nv1.2.0 2023-01-01T00:00:00Z\nnot-a-version\n  v1.9.0\n"))
+	}))
+	defer server.Close()
+
+	versions, err := NewClient(server.URL).Versions(context.Background(), "github.com/test/module")
+	if err != nil {
+		t.Fatalf("Versions() error: %v", err)
+	}
+	if want := []string{"v1.2.0", "v1.9.0", "v1.10.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions() = %v, want %v", versions, want)
 	}
 }
 
@@ -487,6 +745,35 @@ func TestClient_GetModFile_Error(t *testing.T) {
 	}
 }
 
+func TestClient_GetZip(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if r.URL.Path != "/github.com/!test/module/@v/v1.0.0.zip" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte("PK-zip-data"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx := context.Background()
+
+	for range 2 {
+		data, err := client.GetZip(ctx, "github.com/Test/module", "v1.0.0")
+		if err != nil {
+			t.Fatalf("GetZip() error: %v", err)
+		}
+		if string(data) != "PK-zip-data" {
+			t.Errorf("GetZip() = %q", data)
+		}
+	}
+
+	if callCount != 2 {
+		t.Errorf("Server called %d times, want 2 (zips are not cached)", callCount)
+	}
+}
+
 func TestClient_HTTPTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(100 * time.Millisecond)
@@ -505,17 +792,147 @@ func TestClient_HTTPTimeout(t *testing.T) {
 	}
 }
 
+func TestClient_LookupTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		json.NewEncoder(w).Encode(VersionInfo{Version: "v1.0.0"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).WithLookupTimeout(10 * time.Millisecond)
+
+	_, err := client.Latest(context.Background(), "go.slow.dev/module")
+	if err == nil || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Fatalf("Latest() error = %v, want a lookup timeout", err)
+	}
+}
+
+func TestClient_HostBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/go.dead.dev/"):
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		case strings.HasPrefix(r.URL.Path, "/github.com/private/"):
+			http.NotFound(w, r)
+		default:
+			json.NewEncoder(w).Encode(VersionInfo{Version: "v1.0.0"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).WithMaxConcurrent(1)
+	ctx := context.Background()
+
+	// Lookups queued behind the failing ones see the breaker open
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Latest(ctx, "go.dead.dev/"+name)
+		}()
+	}
+	wg.Wait()
+	if n := requests.Load(); n != breakerThreshold {
+		t.Errorf("proxy got %d requests for the dead host, want %d", n, breakerThreshold)
+	}
+	if _, err := client.Latest(ctx, "go.dead.dev/f"); !errors.Is(err, ErrHostUnavailable) {
+		t.Errorf("Latest() error = %v, want ErrHostUnavailable", err)
+	}
+
+	// Missing modules don't count against the host
+	for _, name := range []string{"a", "b", "c", "d"} {
+		client.Latest(ctx, "github.com/private/"+name)
+	}
+	if _, err := client.Latest(ctx, "github.com/public/mod"); err != nil {
+		t.Errorf("Latest() after 404s = %v, want nil", err)
+	}
+}
+
+func TestClient_WithLocalModules(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(VersionInfo{Version: "v1.0.0"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).WithLocalModules([]string{"corp.internal/secret"})
+	ctx := context.Background()
+
+	if _, err := client.Latest(ctx, "corp.internal/secret"); !errors.Is(err, ErrLocalModule) {
+		t.Errorf("Latest() error = %v, want ErrLocalModule", err)
+	}
+	if _, err := client.GetZip(ctx, "corp.internal/secret", "v1.0.0"); !errors.Is(err, ErrLocalModule) {
+		t.Errorf("GetZip() error = %v, want ErrLocalModule", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("proxy got %d requests for a local module, want 0", n)
+	}
+
+	if _, err := client.Latest(ctx, "github.com/public/mod"); err != nil {
+		t.Errorf("Latest() for a remote module error = %v", err)
+	}
+}
+
+type fakeResolver struct{}
+
+func (fakeResolver) Latest(ctx context.Context, modulePath string) (*VersionInfo, error) {
+	return &VersionInfo{Version: "v1.5.0"}, nil
+}
+
+func (fakeResolver) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	return []string{"v1.4.0", "v1.5.0"}, nil
+}
+
+func (fakeResolver) Info(ctx context.Context, modulePath, version string) (*VersionInfo, error) {
+	return &VersionInfo{Version: version}, nil
+}
+
+func TestClient_WithPrivate(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(VersionInfo{Version: "v1.0.0"})
+	}))
+	defer server.Close()
+
+	isPrivate := func(path string) bool { return strings.HasPrefix(path, "corp.dev/") }
+	client := NewClient(server.URL).WithPrivate(isPrivate, fakeResolver{})
+	ctx := context.Background()
+
+	info, err := client.Latest(ctx, "corp.dev/lib")
+	if err != nil || info.Version != "v1.5.0" {
+		t.Errorf("Latest() = %v, %v; want v1.5.0 from the resolver", info, err)
+	}
+	if list, err := client.Versions(ctx, "corp.dev/lib"); err != nil || len(list) != 2 {
+		t.Errorf("Versions() = %v, %v", list, err)
+	}
+	if _, err := client.GetModFile(ctx, "corp.dev/lib", "v1.5.0"); !errors.Is(err, ErrPrivateModule) {
+		t.Errorf("GetModFile() error = %v, want ErrPrivateModule", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("proxy got %d requests for a private module, want 0", n)
+	}
+
+	if info, err := client.Latest(ctx, "github.com/public/mod"); err != nil || info.Version != "v1.0.0" {
+		t.Errorf("Latest() for a public module = %v, %v", info, err)
+	}
+}
+
 func TestClient_CacheIntegration(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case strings.Contains(r.URL.Path, "/@latest"):
-			json.NewEncoder(w).Encode(VersionInfo{Version: "v2.0.0", Time: time.Now()})
+			json.NewEncoder(w).Encode(VersionInfo{Version: "v1.1.0", Time: time.Now()})
 		case strings.Contains(r.URL.Path, ".info"):
 			json.NewEncoder(w).Encode(VersionInfo{Version: "v1.0.0", Time: time.Now()})
 		case strings.Contains(r.URL.Path, ".mod"):
 			w.Write([]byte("module test"))
 		case strings.Contains(r.URL.Path, "/list"):
-			w.Write([]byte("v1.0.0\nv2.0.0"))
+			w.Write([]byte("v1.0.0\nv1.1.0"))
 		}
 	}))
 	defer server.Close()
@@ -649,6 +1066,40 @@ go 1.24.2
 	}
 }
 
+// BenchmarkClient_Resolve looks up the latest version of every module of a
+// large go.mod concurrently, as gx outdated does
+func BenchmarkClient_Resolve(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(fmt.Sprintf("requires=%d", n), func(b *testing.B) {
+			set := benchdata.New(n)
+			server := httptest.NewServer(set.Handler())
+			defer server.Close()
+			client := NewClient(server.URL)
+			client.cache = &noOpCache{}
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				var wg sync.WaitGroup
+				var failed atomic.Int32
+				for _, m := range set.Modules {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if _, err := client.Latest(ctx, m.Path); err != nil {
+							failed.Add(1)
+						}
+					}()
+				}
+				wg.Wait()
+				if f := failed.Load(); f > 0 {
+					b.Fatalf("%d lookups failed", f)
+				}
+			}
+		})
+	}
+}
+
 type noOpCache struct{}
 
 func (n *noOpCache) Get(key string) (any, bool)                   { return nil, false }
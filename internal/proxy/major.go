@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// maxMajorProbes bounds how many major versions past the current one
+// LatestMajor asks the proxy about
+const maxMajorProbes = 20
+
+// NextMajorPath returns the module path of the major version after the
+// path's own: example.com/lib becomes example.com/lib/v2, example.com/lib/v2
+// becomes example.com/lib/v3 and gopkg.in/yaml.v2 becomes gopkg.in/yaml.v3
+func NextMajorPath(modulePath string) (string, error) {
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return "", fmt.Errorf("malformed module path %q", modulePath)
+	}
+
+	n := 1
+	if pathMajor != "" {
+		fmt.Sscanf(strings.TrimLeft(pathMajor, "/."), "v%d", &n)
+	}
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		return fmt.Sprintf("%s.v%d", prefix, n+1), nil
+	}
+	return fmt.Sprintf("%s/v%d", prefix, n+1), nil
+}
+
+// LatestMajor probes the proxy for higher major versions of a module, one
+// after the other, and returns the path and latest version of the highest
+// one with a release (or a pre-release when pre is set). It returns nil
+// when the proxy knows of no higher major version.
+func (c *Client) LatestMajor(ctx context.Context, modulePath string, pre bool) (string, *VersionInfo, error) {
+	latestOf := c.Latest
+	if pre {
+		latestOf = c.LatestPre
+	}
+
+	var bestPath string
+	var best *VersionInfo
+	path := modulePath
+	for range maxMajorProbes {
+		next, err := NextMajorPath(path)
+		if err != nil {
+			return "", nil, err
+		}
+		info, err := latestOf(ctx, next)
+		if err != nil {
+			var status *StatusError
+			if errors.As(err, &status) && (status.Code == http.StatusNotFound || status.Code == http.StatusGone) {
+				break
+			}
+			return "", nil, err
+		}
+		if module.IsPseudoVersion(info.Version) || (!pre && semver.Prerelease(info.Version) != "") {
+			break
+		}
+		bestPath, best, path = next, info, next
+	}
+	return bestPath, best, nil
+}
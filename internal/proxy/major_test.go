@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNextMajorPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"github.com/acme/lib", "github.com/acme/lib/v2"},
+		{"github.com/acme/lib/v2", "github.com/acme/lib/v3"},
+		{"github.com/acme/lib/v9", "github.com/acme/lib/v10"},
+		{"gopkg.in/yaml.v2", "gopkg.in/yaml.v3"},
+		{"gopkg.in/check.v1", "gopkg.in/check.v2"},
+	}
+
+	for _, tt := range tests {
+		got, err := NextMajorPath(tt.path)
+		if err != nil {
+			t.Fatalf("NextMajorPath(%q) error: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("NextMajorPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestClient_LatestMajor(t *testing.T) {
+	// Latest versions by module path; anything else is unknown to the proxy
+	modules := map[string]string{
+		"github.com/acme/lib/v2":  "v2.3.0",
+		"github.com/acme/lib/v3":  "v3.1.0",
+		"github.com/acme/pre/v2":  "v2.0.0-rc.1",
+		"github.com/acme/wip/v2":  "v2.0.0-20240101000000-abcdefabcdef",
+		"gopkg.in/yaml.v3":        "v3.0.1",
+		"github.com/acme/done/v5": "v5.0.0",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/@")
+		latest, ok := modules[path]
+		if !ok || !strings.HasSuffix(r.URL.Path, "/@latest") {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(VersionInfo{Version: latest})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		module      string
+		pre         bool
+		wantPath    string
+		wantVersion string
+	}{
+		{"highest of several", "github.com/acme/lib", false, "github.com/acme/lib/v3", "v3.1.0"},
+		{"from a major path", "github.com/acme/lib/v2", false, "github.com/acme/lib/v3", "v3.1.0"},
+		{"gopkg.in", "gopkg.in/yaml.v2", false, "gopkg.in/yaml.v3", "v3.0.1"},
+		{"none", "github.com/acme/none", false, "", ""},
+		{"pre-release skipped", "github.com/acme/pre", false, "", ""},
+		{"pre-release", "github.com/acme/pre", true, "github.com/acme/pre/v2", "v2.0.0-rc.1"},
+		{"pseudo-version", "github.com/acme/wip", true, "", ""},
+		{"already the highest", "github.com/acme/done/v5", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, info, err := NewClient(server.URL).LatestMajor(context.Background(), tt.module, tt.pre)
+			if err != nil {
+				t.Fatalf("LatestMajor() error: %v", err)
+			}
+			version := ""
+			if info != nil {
+				version = info.Version
+			}
+			if path != tt.wantPath || version != tt.wantVersion {
+				t.Errorf("LatestMajor() = %q %q, want %q %q", path, version, tt.wantPath, tt.wantVersion)
+			}
+		})
+	}
+}
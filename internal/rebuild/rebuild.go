@@ -0,0 +1,219 @@
+// Package rebuild reproduces the build of a Go binary from its embedded
+// build information and explains why two builds differ.
+package rebuild
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Kinds of build inputs reported by Compare
+const (
+	KindToolchain  = "toolchain"
+	KindMain       = "main module"
+	KindDependency = "dependency"
+	KindSetting    = "setting"
+)
+
+// flagSettings are build flags recorded with a value, passed as -flag=value
+var flagSettings = []string{"-asmflags", "-gcflags", "-pgo", "-tags"}
+
+// boolSettings are build flags recorded as "true"
+var boolSettings = []string{"-asan", "-msan", "-race", "-trimpath"}
+
+// envPrefixes select the settings recorded from the environment
+var envPrefixes = []string{"CGO_", "GO"}
+
+// Difference is a build input that differs between the artifact and the rebuild
+type Difference struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Artifact string `json:"artifact"`
+	Rebuilt  string `json:"rebuilt"`
+}
+
+// Setting returns a build setting such as GOOS or -ldflags, or "" if unset
+func Setting(info *debug.BuildInfo, key string) string {
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// Toolchain returns the Go toolchain that built the binary, without any
+// GOEXPERIMENT suffix ("go1.22.3 X:loopvar" becomes "go1.22.3")
+func Toolchain(info *debug.BuildInfo) string {
+	v, _, _ := strings.Cut(info.GoVersion, " ")
+	return v
+}
+
+// Revision returns the commit the binary was built from, or the main module
+// version when no VCS information was recorded
+func Revision(info *debug.BuildInfo) string {
+	if rev := Setting(info, "vcs.revision"); rev != "" {
+		return rev
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	return ""
+}
+
+// PackageDir returns the directory of the main package relative to the main
+// module root, such as "./cmd/app"
+func PackageDir(info *debug.BuildInfo) (string, error) {
+	if info.Path == info.Main.Path {
+		return ".", nil
+	}
+	rel, ok := strings.CutPrefix(info.Path, info.Main.Path+"/")
+	if !ok || info.Main.Path == "" {
+		return "", fmt.Errorf("main package %s is not in module %q", info.Path, info.Main.Path)
+	}
+	return "./" + rel, nil
+}
+
+// Command returns the go build arguments and environment that reproduce
+// the recorded build of info into output. The build always uses -trimpath so
+// the result does not depend on where the source is checked out, and the
+// recorded toolchain is selected with GOTOOLCHAIN. Builds with -trimpath do
+// not record -ldflags, so ldflags, when set, replaces the recorded value.
+func Command(info *debug.BuildInfo, output, ldflags string) (args []string, env []string, err error) {
+	pkg, err := PackageDir(info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = []string{"build", "-o", output, "-trimpath"}
+	for _, key := range flagSettings {
+		if v := Setting(info, key); v != "" {
+			args = append(args, key+"="+v)
+		}
+	}
+	// The build mode and compiler are recorded even when left at their
+	// defaults, and naming the default build mode explicitly changes the output
+	if mode := Setting(info, "-buildmode"); mode != "" && mode != defaultBuildMode(Setting(info, "GOOS"), Setting(info, "GOARCH")) {
+		args = append(args, "-buildmode="+mode)
+	}
+	if compiler := Setting(info, "-compiler"); compiler != "" && compiler != "gc" {
+		args = append(args, "-compiler="+compiler)
+	}
+	if ldflags == "" {
+		ldflags = Setting(info, "-ldflags")
+	}
+	if ldflags != "" {
+		args = append(args, "-ldflags="+ldflags)
+	}
+	for _, key := range boolSettings {
+		if key != "-trimpath" && Setting(info, key) == "true" {
+			args = append(args, key)
+		}
+	}
+	if Setting(info, "vcs") == "" {
+		args = append(args, "-buildvcs=false")
+	}
+	args = append(args, pkg)
+
+	env = []string{"GOFLAGS=", "GOTOOLCHAIN=" + Toolchain(info)}
+	if Setting(info, "CGO_ENABLED") == "" {
+		env = append(env, "CGO_ENABLED=0")
+	}
+	for _, s := range info.Settings {
+		if isEnvSetting(s.Key) {
+			env = append(env, s.Key+"="+s.Value)
+		}
+	}
+	return args, env, nil
+}
+
+// defaultBuildMode is the mode go build uses for main packages on a platform
+func defaultBuildMode(goos, goarch string) string {
+	switch {
+	case goos == "android", goos == "ios", goos == "windows", goos == "darwin" && goarch == "arm64":
+		return "pie"
+	}
+	return "exe"
+}
+
+func isEnvSetting(key string) bool {
+	if key == "DefaultGODEBUG" {
+		return false
+	}
+	for _, prefix := range envPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare lists the build inputs recorded in artifact that differ in rebuilt:
+// the toolchain, the main module, dependency versions and checksums, and
+// build settings. -ldflags is left out since -trimpath builds don't record it.
+func Compare(artifact, rebuilt *debug.BuildInfo) []Difference {
+	var diffs []Difference
+	add := func(kind, name, a, b string) {
+		if a != b {
+			diffs = append(diffs, Difference{Kind: kind, Name: name, Artifact: a, Rebuilt: b})
+		}
+	}
+
+	add(KindToolchain, "go", artifact.GoVersion, rebuilt.GoVersion)
+	add(KindMain, artifact.Main.Path, moduleString(&artifact.Main), moduleString(&rebuilt.Main))
+
+	deps := make(map[string][2]string)
+	for _, d := range artifact.Deps {
+		deps[d.Path] = [2]string{moduleString(d), ""}
+	}
+	for _, d := range rebuilt.Deps {
+		v := deps[d.Path]
+		v[1] = moduleString(d)
+		deps[d.Path] = v
+	}
+	for _, path := range sortedKeys(deps) {
+		add(KindDependency, path, deps[path][0], deps[path][1])
+	}
+
+	settings := make(map[string][2]string)
+	for _, s := range artifact.Settings {
+		settings[s.Key] = [2]string{s.Value, ""}
+	}
+	for _, s := range rebuilt.Settings {
+		v := settings[s.Key]
+		v[1] = s.Value
+		settings[s.Key] = v
+	}
+	delete(settings, "-ldflags")
+	for _, key := range sortedKeys(settings) {
+		add(KindSetting, key, settings[key][0], settings[key][1])
+	}
+
+	return diffs
+}
+
+// moduleString describes a module version with its checksum and replacement
+func moduleString(m *debug.Module) string {
+	if m == nil || m.Path == "" {
+		return ""
+	}
+	s := m.Version
+	if m.Sum != "" {
+		s += " " + m.Sum
+	}
+	if m.Replace != nil {
+		s += " => " + m.Replace.Path + " " + moduleString(m.Replace)
+	}
+	return strings.TrimSpace(s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+package rebuild
+
+import (
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// parse reads build info in the format of go version -m; ParseBuildInfo
+// ignores the go line, which binaries record separately
+func parse(t *testing.T, s string) *debug.BuildInfo {
+	t.Helper()
+	goLine, rest, _ := strings.Cut(s, "\n")
+	info, err := debug.ParseBuildInfo(rest)
+	if err != nil {
+		t.Fatalf("ParseBuildInfo() error: %v", err)
+	}
+	info.GoVersion = strings.TrimPrefix(goLine, "go\t")
+	return info
+}
+
+const artifactInfo = "go\tgo1.22.3\n" +
+	"path\texample.com/app/cmd/app\n" +
+	"mod\texample.com/app\tv1.2.0\th1:main=\n" +
+	"dep\tgithub.com/spf13/cobra\tv1.8.0\th1:cobra=\n" +
+	"dep\tgolang.org/x/sys\tv0.20.0\th1:sys=\n" +
+	"build\t-buildmode=exe\n" +
+	"build\t-compiler=gc\n" +
+	"build\t-ldflags=\"-s -w -X main.version=v1.2.0\"\n" +
+	"build\t-trimpath=true\n" +
+	"build\tCGO_ENABLED=0\n" +
+	"build\tGOARCH=arm64\n" +
+	"build\tGOOS=linux\n" +
+	"build\tvcs=git\n" +
+	"build\tvcs.revision=0123abcd\n" +
+	"build\tvcs.modified=false\n"
+
+func TestCommand(t *testing.T) {
+	info := parse(t, artifactInfo)
+
+	args, env, err := Command(info, "/tmp/out", "")
+	if err != nil {
+		t.Fatalf("Command() error: %v", err)
+	}
+
+	wantArgs := []string{"build", "-o", "/tmp/out", "-trimpath", "-ldflags=-s -w -X main.version=v1.2.0", "./cmd/app"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %q\nwant %q", args, wantArgs)
+	}
+	wantEnv := []string{"GOFLAGS=", "GOTOOLCHAIN=go1.22.3", "CGO_ENABLED=0", "GOARCH=arm64", "GOOS=linux"}
+	if !reflect.DeepEqual(env, wantEnv) {
+		t.Errorf("env = %q\nwant %q", env, wantEnv)
+	}
+
+	args, _, _ = Command(info, "/tmp/out", "-X main.version=v1.2.1")
+	if got := args[len(args)-2]; got != "-ldflags=-X main.version=v1.2.1" {
+		t.Errorf("ldflags override = %q", got)
+	}
+}
+
+func TestCommand_BuildMode(t *testing.T) {
+	info := parse(t, "go\tgo1.22.3\npath\texample.com/app\nmod\texample.com/app\tv1.0.0\t\n"+
+		"build\t-buildmode=pie\nbuild\tGOOS=linux\nbuild\tGOARCH=amd64\nbuild\tvcs=git\n")
+	args, _, _ := Command(info, "out", "")
+	if !reflect.DeepEqual(args, []string{"build", "-o", "out", "-trimpath", "-buildmode=pie", "."}) {
+		t.Errorf("args = %q, want -buildmode=pie on linux", args)
+	}
+
+	info.Settings[1].Value = "windows"
+	args, _, _ = Command(info, "out", "")
+	if !reflect.DeepEqual(args, []string{"build", "-o", "out", "-trimpath", "."}) {
+		t.Errorf("args = %q, want the default build mode left out on windows", args)
+	}
+}
+
+func TestCommand_NoVCS(t *testing.T) {
+	info := parse(t, "go\tgo1.23.0 X:rangefunc\npath\texample.com/tool\nmod\texample.com/tool\t(devel)\t\n")
+
+	args, env, err := Command(info, "out", "")
+	if err != nil {
+		t.Fatalf("Command() error: %v", err)
+	}
+
+	wantArgs := []string{"build", "-o", "out", "-trimpath", "-buildvcs=false", "."}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %q, want %q", args, wantArgs)
+	}
+	if env[1] != "GOTOOLCHAIN=go1.23.0" || env[2] != "CGO_ENABLED=0" {
+		t.Errorf("env = %q", env)
+	}
+	if Revision(info) != "" {
+		t.Errorf("Revision() = %q, want empty", Revision(info))
+	}
+}
+
+func TestRevision(t *testing.T) {
+	if got := Revision(parse(t, artifactInfo)); got != "0123abcd" {
+		t.Errorf("Revision() = %q, want 0123abcd", got)
+	}
+	if got := Revision(parse(t, "go\tgo1.22.3\npath\texample.com/app\nmod\texample.com/app\tv1.0.0\t\n")); got != "v1.0.0" {
+		t.Errorf("Revision() = %q, want v1.0.0", got)
+	}
+}
+
+func TestPackageDir_Outside(t *testing.T) {
+	info := parse(t, "go\tgo1.22.3\npath\texample.com/other/cmd\nmod\texample.com/app\tv1.0.0\t\n")
+	if _, err := PackageDir(info); err == nil {
+		t.Error("PackageDir() expected error for a main package outside the main module")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	artifact := parse(t, artifactInfo)
+	if diffs := Compare(artifact, artifact); len(diffs) != 0 {
+		t.Errorf("Compare() of identical info = %+v", diffs)
+	}
+
+	rebuilt := parse(t, "go\tgo1.22.4\n"+
+		"path\texample.com/app/cmd/app\n"+
+		"mod\texample.com/app\tv1.2.0\th1:main=\n"+
+		"dep\tgithub.com/spf13/cobra\tv1.8.1\th1:cobra2=\n"+
+		"dep\tgolang.org/x/sys\tv0.20.0\th1:sys=\n"+
+		"dep\tgolang.org/x/text\tv0.14.0\th1:text=\n"+
+		"build\t-buildmode=exe\n"+
+		"build\t-compiler=gc\n"+
+		"build\t-ldflags=\"-s -w\"\n"+
+		"build\t-trimpath=true\n"+
+		"build\tCGO_ENABLED=0\n"+
+		"build\tGOARCH=arm64\n"+
+		"build\tGOOS=linux\n"+
+		"build\tvcs=git\n"+
+		"build\tvcs.revision=0123abcd\n"+
+		"build\tvcs.modified=false\n")
+
+	want := []Difference{
+		{Kind: KindToolchain, Name: "go", Artifact: "go1.22.3", Rebuilt: "go1.22.4"},
+		{Kind: KindDependency, Name: "github.com/spf13/cobra", Artifact: "v1.8.0 h1:cobra=", Rebuilt: "v1.8.1 h1:cobra2="},
+		{Kind: KindDependency, Name: "golang.org/x/text", Artifact: "", Rebuilt: "v0.14.0 h1:text="},
+	}
+	if got := Compare(artifact, rebuilt); !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,254 @@
+// Package sbom reads the Go modules listed in CycloneDX and SPDX SBOMs and
+// compares them with what go.mod and go.sum record, and writes CycloneDX
+// vulnerability disclosure reports.
+package sbom
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Drift kinds reported by Compare
+const (
+	DriftVersion = "version" // the SBOM lists another version
+	DriftMissing = "missing" // go.mod requires a module the SBOM doesn't list
+	DriftExtra   = "extra"   // the SBOM lists a module go.mod doesn't require
+	DriftHash    = "hash"    // the SBOM's hash differs from go.sum
+)
+
+// Document is the Go modules an SBOM lists
+type Document struct {
+	Format     string // e.g. "CycloneDX 1.5" or "SPDX-2.3"
+	Components []Component
+}
+
+// Component is a Go module listed in an SBOM, found by its pkg:golang purl
+type Component struct {
+	Path    string
+	Version string
+	SHA256  []string // hex digests; for Go modules, the go.sum h1 hash
+}
+
+// Module is a required module version, with its go.sum hash ("h1:...")
+// when go.sum has one
+type Module struct {
+	Path    string
+	Version string
+	Hash    string
+}
+
+// Drift is a difference between the SBOM and go.mod or go.sum
+type Drift struct {
+	Module   string `json:"module"`
+	Kind     string `json:"kind"`
+	Expected string `json:"expected,omitempty"` // what go.mod or go.sum records
+	Found    string `json:"found,omitempty"`    // what the SBOM lists
+}
+
+// Parse reads a CycloneDX or SPDX SBOM in JSON, keeping its Go modules
+func Parse(data []byte) (*Document, error) {
+	var probe struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("parsing SBOM (only JSON SBOMs are supported): %w", err)
+	}
+
+	switch {
+	case probe.BOMFormat == "CycloneDX":
+		return parseCycloneDX(data, "CycloneDX "+probe.SpecVersion)
+	case probe.SPDXVersion != "":
+		return parseSPDX(data, probe.SPDXVersion)
+	}
+	return nil, fmt.Errorf("unrecognized SBOM format (want CycloneDX or SPDX JSON)")
+}
+
+type cdxComponent struct {
+	PURL   string `json:"purl"`
+	Hashes []struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	} `json:"hashes"`
+	Components []cdxComponent `json:"components"`
+}
+
+func parseCycloneDX(data []byte, format string) (*Document, error) {
+	var bom struct {
+		Components []cdxComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return nil, fmt.Errorf("parsing CycloneDX SBOM: %w", err)
+	}
+
+	doc := &Document{Format: format}
+	var walk func([]cdxComponent)
+	walk = func(components []cdxComponent) {
+		for _, c := range components {
+			if comp, ok := fromPURL(c.PURL); ok {
+				for _, h := range c.Hashes {
+					if isSHA256(h.Alg) {
+						comp.SHA256 = append(comp.SHA256, strings.ToLower(h.Content))
+					}
+				}
+				doc.Components = append(doc.Components, comp)
+			}
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+	return doc, nil
+}
+
+func parseSPDX(data []byte, format string) (*Document, error) {
+	var spdx struct {
+		Packages []struct {
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+			Checksums []struct {
+				Algorithm     string `json:"algorithm"`
+				ChecksumValue string `json:"checksumValue"`
+			} `json:"checksums"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		return nil, fmt.Errorf("parsing SPDX SBOM: %w", err)
+	}
+
+	doc := &Document{Format: format}
+	for _, pkg := range spdx.Packages {
+		for _, ref := range pkg.ExternalRefs {
+			if ref.ReferenceType != "purl" {
+				continue
+			}
+			comp, ok := fromPURL(ref.ReferenceLocator)
+			if !ok {
+				continue
+			}
+			for _, c := range pkg.Checksums {
+				if isSHA256(c.Algorithm) {
+					comp.SHA256 = append(comp.SHA256, strings.ToLower(c.ChecksumValue))
+				}
+			}
+			doc.Components = append(doc.Components, comp)
+			break
+		}
+	}
+	return doc, nil
+}
+
+// fromPURL returns the module of a pkg:golang package URL, such as
+// pkg:golang/github.com/spf13/cobra@v1.8.0
+func fromPURL(purl string) (Component, bool) {
+	typ, rest, ok := strings.Cut(purl, "/")
+	if !ok || !strings.EqualFold(typ, "pkg:golang") {
+		return Component{}, false
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	path, version, _ := strings.Cut(rest, "@")
+	path, err := url.PathUnescape(path)
+	if err != nil || path == "" {
+		return Component{}, false
+	}
+	if version, err = url.PathUnescape(version); err != nil {
+		return Component{}, false
+	}
+	return Component{Path: path, Version: version}, true
+}
+
+func isSHA256(alg string) bool {
+	alg = strings.ToUpper(strings.ReplaceAll(alg, "-", ""))
+	return alg == "SHA256"
+}
+
+// h1Hex converts a go.sum h1 hash to the hex digest SBOM tools record for it
+func h1Hex(hash string) string {
+	encoded, ok := strings.CutPrefix(hash, "h1:")
+	if !ok {
+		return ""
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(digest)
+}
+
+// Compare reports how the SBOM differs from the required modules, sorted by
+// module. Components at a path in ignore, such as the main module, are left
+// out, and hashes are only compared when both sides have one.
+func Compare(doc *Document, modules []Module, ignore []string) []Drift {
+	listed := make(map[string][]Component)
+	for _, c := range doc.Components {
+		listed[c.Path] = append(listed[c.Path], c)
+	}
+	skipped := make(map[string]bool, len(ignore))
+	for _, path := range ignore {
+		skipped[path] = true
+	}
+
+	var drift []Drift
+	required := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		required[m.Path] = true
+		components := listed[m.Path]
+		if len(components) == 0 {
+			drift = append(drift, Drift{Module: m.Path, Kind: DriftMissing, Expected: m.Version})
+			continue
+		}
+
+		c, ok := componentAt(components, m.Version)
+		if !ok {
+			drift = append(drift, Drift{Module: m.Path, Kind: DriftVersion, Expected: m.Version, Found: versionsOf(components)})
+			continue
+		}
+		want := h1Hex(m.Hash)
+		if want != "" && len(c.SHA256) > 0 && !slices.Contains(c.SHA256, want) {
+			drift = append(drift, Drift{Module: m.Path, Kind: DriftHash, Expected: want, Found: c.SHA256[0]})
+		}
+	}
+
+	for path, components := range listed {
+		if !required[path] && !skipped[path] {
+			drift = append(drift, Drift{Module: path, Kind: DriftExtra, Found: versionsOf(components)})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Module != drift[j].Module {
+			return drift[i].Module < drift[j].Module
+		}
+		return drift[i].Kind < drift[j].Kind
+	})
+	return drift
+}
+
+func componentAt(components []Component, version string) (Component, bool) {
+	for _, c := range components {
+		if c.Version == version {
+			return c, true
+		}
+	}
+	return Component{}, false
+}
+
+func versionsOf(components []Component) string {
+	var versions []string
+	for _, c := range components {
+		if c.Version != "" && !slices.Contains(versions, c.Version) {
+			versions = append(versions, c.Version)
+		}
+	}
+	return strings.Join(versions, ", ")
+}
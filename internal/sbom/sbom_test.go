@@ -0,0 +1,110 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+const cycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"purl": "pkg:golang/example.com/app@v0.0.0"}},
+  "components": [
+    {
+      "purl": "pkg:golang/github.com/spf13/cobra@v1.8.0?type=module",
+      "hashes": [{"alg": "SHA-256", "content": "B2E9F5C0C2B1D7F1C6C5E1A1D3C8E2F4A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0"}],
+      "components": [
+        {"purl": "pkg:golang/github.com/foo/bar@v2.0.0%2Bincompatible"}
+      ]
+    },
+    {"purl": "pkg:npm/left-pad@1.3.0"}
+  ]
+}`
+
+const spdx = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {
+      "name": "github.com/spf13/cobra",
+      "externalRefs": [
+        {"referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:spf13:cobra:1.8.0"},
+        {"referenceType": "purl", "referenceLocator": "pkg:golang/github.com/spf13/cobra@v1.8.0"}
+      ],
+      "checksums": [
+        {"algorithm": "SHA1", "checksumValue": "abc"},
+        {"algorithm": "SHA256", "checksumValue": "00ff"}
+      ]
+    },
+    {"name": "no purl"}
+  ]
+}`
+
+func TestParse_CycloneDX(t *testing.T) {
+	doc, err := Parse([]byte(cycloneDX))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	want := &Document{
+		Format: "CycloneDX 1.5",
+		Components: []Component{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0", SHA256: []string{"b2e9f5c0c2b1d7f1c6c5e1a1d3c8e2f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0"}},
+			{Path: "github.com/foo/bar", Version: "v2.0.0+incompatible"},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Parse() = %+v, want %+v", doc, want)
+	}
+}
+
+func TestParse_SPDX(t *testing.T) {
+	doc, err := Parse([]byte(spdx))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	want := &Document{
+		Format:     "SPDX-2.3",
+		Components: []Component{{Path: "github.com/spf13/cobra", Version: "v1.8.0", SHA256: []string{"00ff"}}},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Parse() = %+v, want %+v", doc, want)
+	}
+}
+
+func TestParse_Unrecognized(t *testing.T) {
+	if _, err := Parse([]byte(`{"name": "x"}`)); err == nil {
+		t.Error("expected an error for a JSON document that is no SBOM")
+	}
+	if _, err := Parse([]byte(`<bom/>`)); err == nil {
+		t.Error("expected an error for XML")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// h1:AP8= is the digest 00ff
+	doc := &Document{Components: []Component{
+		{Path: "example.com/app", Version: "v0.0.0"},
+		{Path: "example.com/same", Version: "v1.0.0", SHA256: []string{"00ff"}},
+		{Path: "example.com/old", Version: "v1.0.0"},
+		{Path: "example.com/tampered", Version: "v1.0.0", SHA256: []string{"0000"}},
+		{Path: "example.com/unhashed", Version: "v1.0.0"},
+		{Path: "example.com/stale", Version: "v0.1.0"},
+	}}
+	modules := []Module{
+		{Path: "example.com/same", Version: "v1.0.0", Hash: "h1:AP8="},
+		{Path: "example.com/old", Version: "v1.2.0"},
+		{Path: "example.com/tampered", Version: "v1.0.0", Hash: "h1:AP8="},
+		{Path: "example.com/unhashed", Version: "v1.0.0", Hash: "h1:AP8="},
+		{Path: "example.com/new", Version: "v0.3.0"},
+	}
+
+	got := Compare(doc, modules, []string{"example.com/app"})
+	want := []Drift{
+		{Module: "example.com/new", Kind: DriftMissing, Expected: "v0.3.0"},
+		{Module: "example.com/old", Kind: DriftVersion, Expected: "v1.2.0", Found: "v1.0.0"},
+		{Module: "example.com/stale", Kind: DriftExtra, Found: "v0.1.0"},
+		{Module: "example.com/tampered", Kind: DriftHash, Expected: "00ff", Found: "0000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+}
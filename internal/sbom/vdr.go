@@ -0,0 +1,217 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+// VDRSpecVersion is the CycloneDX version of the reports NewVDR builds
+const VDRSpecVersion = "1.5"
+
+// BOM is a CycloneDX document as NewVDR writes it
+type BOM struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	SerialNumber    string             `json:"serialNumber"`
+	Version         int                `json:"version"`
+	Metadata        bomMetadata        `json:"metadata"`
+	Components      []bomComponent     `json:"components"`
+	Vulnerabilities []bomVulnerability `json:"vulnerabilities"`
+}
+
+type bomMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     bomTools     `json:"tools"`
+	Component bomComponent `json:"component"`
+}
+
+type bomTools struct {
+	Components []bomComponent `json:"components"`
+}
+
+type bomComponent struct {
+	BOMRef  string    `json:"bom-ref,omitempty"`
+	Type    string    `json:"type"`
+	Name    string    `json:"name"`
+	Version string    `json:"version,omitempty"`
+	PURL    string    `json:"purl,omitempty"`
+	Hashes  []bomHash `json:"hashes,omitempty"`
+}
+
+type bomHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type bomVulnerability struct {
+	BOMRef         string        `json:"bom-ref"`
+	ID             string        `json:"id"`
+	Source         bomSource     `json:"source"`
+	Ratings        []bomRating   `json:"ratings"`
+	Description    string        `json:"description,omitempty"`
+	Recommendation string        `json:"recommendation,omitempty"`
+	Advisories     []bomAdvisory `json:"advisories,omitempty"`
+	Affects        []bomAffects  `json:"affects"`
+}
+
+type bomSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type bomRating struct {
+	Source   bomSource `json:"source"`
+	Severity string    `json:"severity"`
+}
+
+type bomAdvisory struct {
+	URL string `json:"url"`
+}
+
+type bomAffects struct {
+	Ref      string            `json:"ref"`
+	Versions []bomVersionRange `json:"versions,omitempty"`
+}
+
+type bomVersionRange struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// NewVDR builds a CycloneDX vulnerability disclosure report for the main
+// module: its required modules as components, with their go.sum hashes as
+// SHA-256 digests, and each vulnerability found pointing at the component it
+// affects. Vulnerabilities in modules that aren't required, such as the
+// standard library, get a component of their own. gxVersion is recorded as
+// the tool that made the report.
+func NewVDR(main Module, modules []Module, vulns []*vulndb.Vulnerability, gxVersion string, now time.Time) *BOM {
+	bom := &BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  VDRSpecVersion,
+		SerialNumber: serialNumber(),
+		Version:      1,
+		Metadata: bomMetadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Tools:     bomTools{Components: []bomComponent{{Type: "application", Name: "gx", Version: gxVersion}}},
+			Component: moduleComponent(main, "application"),
+		},
+		Components:      []bomComponent{},
+		Vulnerabilities: []bomVulnerability{},
+	}
+
+	refs := make(map[string]string, len(modules))
+	for _, m := range modules {
+		c := moduleComponent(m, "library")
+		bom.Components = append(bom.Components, c)
+		refs[m.Path] = c.BOMRef
+	}
+
+	source := bomSource{Name: "Go Vulnerability Database", URL: vulndb.Database()}
+	for _, v := range vulns {
+		ref, ok := refs[v.Package]
+		if !ok {
+			c := moduleComponent(Module{Path: v.Package}, "library")
+			bom.Components = append(bom.Components, c)
+			refs[v.Package], ref = c.BOMRef, c.BOMRef
+		}
+
+		affects := bomAffects{Ref: ref}
+		if installed := versionOf(modules, v.Package); installed != "" {
+			affects.Versions = []bomVersionRange{{Version: installed, Status: "affected"}}
+		}
+		vuln := bomVulnerability{
+			BOMRef:      v.ID + "/" + v.Package,
+			ID:          v.ID,
+			Source:      source,
+			Ratings:     []bomRating{{Source: source, Severity: cdxSeverity(v.Severity)}},
+			Description: v.Description,
+			Affects:     []bomAffects{affects},
+		}
+		if v.Fixed != "" && v.Fixed != "unknown" {
+			vuln.Recommendation = fmt.Sprintf("Update %s to %s or later", v.Package, fixedVersion(v.Fixed))
+		}
+		if v.URL != "" {
+			vuln.Advisories = []bomAdvisory{{URL: v.URL}}
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, vuln)
+	}
+
+	sort.SliceStable(bom.Components, func(i, j int) bool {
+		return bom.Components[i].Name < bom.Components[j].Name
+	})
+	return bom
+}
+
+// moduleComponent returns the component for a module, its purl as bom-ref
+func moduleComponent(m Module, typ string) bomComponent {
+	c := bomComponent{
+		BOMRef:  modulePURL(m.Path, m.Version),
+		Type:    typ,
+		Name:    m.Path,
+		Version: m.Version,
+		PURL:    modulePURL(m.Path, m.Version),
+	}
+	if digest := h1Hex(m.Hash); digest != "" {
+		c.Hashes = []bomHash{{Alg: "SHA-256", Content: digest}}
+	}
+	return c
+}
+
+// modulePURL returns the pkg:golang package URL of a module version, such as
+// pkg:golang/github.com/spf13/cobra@v1.8.0, without a version when it's
+// empty
+func modulePURL(path, version string) string {
+	elems := strings.Split(path, "/")
+	for i, e := range elems {
+		elems[i] = url.PathEscape(e)
+	}
+	purl := "pkg:golang/" + strings.Join(elems, "/")
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	return purl
+}
+
+func versionOf(modules []Module, path string) string {
+	for _, m := range modules {
+		if m.Path == path {
+			return m.Version
+		}
+	}
+	return ""
+}
+
+// fixedVersion adds the "v" OSV leaves off Go module versions
+func fixedVersion(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// cdxSeverity maps a Go vulnerability database severity to a CycloneDX one
+func cdxSeverity(severity string) string {
+	switch s := strings.ToLower(severity); s {
+	case "critical", "high", "medium", "low":
+		return s
+	case "moderate":
+		return "medium"
+	}
+	return "unknown"
+}
+
+// serialNumber returns a random version 4 UUID URN, as CycloneDX wants for
+// every document
+func serialNumber() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
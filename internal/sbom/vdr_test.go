@@ -0,0 +1,75 @@
+package sbom
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+func TestNewVDR(t *testing.T) {
+	main := Module{Path: "example.com/app"}
+	modules := []Module{
+		{Path: "golang.org/x/net", Version: "v0.17.0", Hash: "h1:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="},
+		{Path: "github.com/spf13/cobra", Version: "v1.8.0"},
+	}
+	vulns := []*vulndb.Vulnerability{
+		{ID: "GO-2023-2102", Package: "golang.org/x/net", Severity: "HIGH", Fixed: "0.17.1", URL: "https://pkg.go.dev/vuln/GO-2023-2102"},
+		{ID: "GO-2024-2599", Package: "stdlib", Severity: "UNKNOWN", Fixed: "unknown"},
+	}
+
+	bom := NewVDR(main, modules, vulns, "v1.2.3", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	if bom.Metadata.Timestamp != "2024-05-01T12:00:00Z" || bom.Metadata.Component.PURL != "pkg:golang/example.com/app" {
+		t.Errorf("metadata = %+v", bom.Metadata)
+	}
+	if len(bom.Components) != 3 || bom.Components[2].Name != "stdlib" {
+		t.Fatalf("components = %+v, want the modules and stdlib", bom.Components)
+	}
+
+	net := bom.Vulnerabilities[0]
+	want := []bomAffects{{Ref: "pkg:golang/golang.org/x/net@v0.17.0", Versions: []bomVersionRange{{Version: "v0.17.0", Status: "affected"}}}}
+	if !reflect.DeepEqual(net.Affects, want) {
+		t.Errorf("affects = %+v, want %+v", net.Affects, want)
+	}
+	if net.Ratings[0].Severity != "high" || net.Recommendation != "Update golang.org/x/net to v0.17.1 or later" {
+		t.Errorf("vulnerability = %+v", net)
+	}
+	if std := bom.Vulnerabilities[1]; std.Affects[0].Ref != "pkg:golang/stdlib" || std.Ratings[0].Severity != "unknown" || std.Recommendation != "" {
+		t.Errorf("stdlib vulnerability = %+v", std)
+	}
+
+	// The report reads back as an SBOM with the go.sum hashes
+	data, err := json.Marshal(bom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift := Compare(doc, modules, []string{"stdlib"}); len(drift) != 0 {
+		t.Errorf("Compare() = %+v, want no drift", drift)
+	}
+}
+
+func TestModulePURL(t *testing.T) {
+	tests := []struct {
+		path, version, want string
+	}{
+		{"github.com/spf13/cobra", "v1.8.0", "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+		{"github.com/foo/bar", "v2.0.0+incompatible", "pkg:golang/github.com/foo/bar@v2.0.0+incompatible"},
+		{"stdlib", "", "pkg:golang/stdlib"},
+	}
+	for _, tt := range tests {
+		got := modulePURL(tt.path, tt.version)
+		if got != tt.want {
+			t.Errorf("modulePURL(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+		if c, ok := fromPURL(got); !ok || c.Path != tt.path || c.Version != tt.version {
+			t.Errorf("fromPURL(%q) = %+v, %v", got, c, ok)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package selfupdate finds, verifies and installs gx release binaries.
+//
+// Releases are expected to follow the goreleaser layout: one archive per
+// platform named gx_<version>_<os>_<arch>.tar.gz (.zip on Windows) and a
+// checksums.txt file of SHA-256 sums.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+	"github.com/omarshaarawi/gx/internal/github"
+	"golang.org/x/mod/semver"
+)
+
+// Repository that publishes gx releases
+const (
+	Owner = "omarshaarawi"
+	Repo  = "gx"
+)
+
+// ChecksumsFile is the release asset listing SHA-256 sums of the other assets
+const ChecksumsFile = "checksums.txt"
+
+// ErrNoAsset is returned when a release has no binary for the platform
+var ErrNoAsset = errors.New("no release asset for this platform")
+
+// Newer reports whether latest is a newer version than current.
+// Versions may omit the leading "v"; a development build is never current.
+func Newer(current, latest string) bool {
+	current, latest = canonical(current), canonical(latest)
+	if !semver.IsValid(current) {
+		return semver.IsValid(latest)
+	}
+	return semver.Compare(latest, current) > 0
+}
+
+func canonical(v string) string {
+	if v != "" && !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// binaryName is the name of the gx executable inside release archives
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "gx.exe"
+	}
+	return "gx"
+}
+
+// FindAsset returns the release asset for the given platform
+func FindAsset(release *github.Release, goos, goarch string) (github.Asset, error) {
+	platform := "_" + goos + "_" + goarch
+	for _, asset := range release.Assets {
+		name := strings.TrimSuffix(asset.Name, ".exe")
+		for _, ext := range []string{".tar.gz", ".zip", ""} {
+			if strings.HasPrefix(name, "gx_") && strings.HasSuffix(name, platform+ext) {
+				return asset, nil
+			}
+		}
+	}
+	return github.Asset{}, fmt.Errorf("%s/%s in %s: %w", goos, goarch, release.TagName, ErrNoAsset)
+}
+
+// ParseChecksums parses a sha256sum-style file into a map of file name to hex digest
+func ParseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// Verify checks data against the checksum recorded for name
+func Verify(data []byte, name string, checksums map[string]string) error {
+	want, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("no checksum for %s", name)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return nil
+}
+
+// ExtractBinary returns the gx executable from a downloaded asset. Assets that
+// are not archives are returned as is.
+func ExtractBinary(assetName string, data []byte, goos string) ([]byte, error) {
+	want := binaryName(goos)
+
+	switch {
+	case strings.HasSuffix(assetName, ".tar.gz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", assetName, err)
+		}
+		defer gz.Close()
+
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", assetName, err)
+			}
+			if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == want {
+				return io.ReadAll(tr)
+			}
+		}
+
+	case strings.HasSuffix(assetName, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", assetName, err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || path.Base(f.Name) != want {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", assetName, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+
+	default:
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("%s not found in %s", want, assetName)
+}
+
+// Replace atomically swaps the executable at exePath for binary, keeping its
+// permissions. Windows can't overwrite a running executable, so the old one
+// is moved aside first and removed on a best-effort basis.
+func Replace(exePath string, binary []byte) error {
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			return fmt.Errorf("moving old binary aside: %w", err)
+		}
+		if err := fsutil.WriteFileAs(exePath, binary, old, 0o755); err != nil {
+			os.Rename(old, exePath)
+			return err
+		}
+		os.Remove(old)
+		return nil
+	}
+
+	return fsutil.WriteFile(exePath, binary, 0o755)
+}
+
+// Executable returns the resolved path of the running binary
+func Executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
@@ -0,0 +1,133 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omarshaarawi/gx/internal/github"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.0.0", "v1.1.0", true},
+		{"1.0.0", "v1.0.0", false},
+		{"v1.2.0", "v1.1.9", false},
+		{"dev", "v0.1.0", true},
+		{"dev", "", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestFindAsset(t *testing.T) {
+	release := &github.Release{
+		TagName: "v1.2.0",
+		Assets: []github.Asset{
+			{Name: "checksums.txt"},
+			{Name: "gx_1.2.0_darwin_arm64.tar.gz"},
+			{Name: "gx_1.2.0_linux_amd64.tar.gz"},
+			{Name: "gx_1.2.0_windows_amd64.zip"},
+		},
+	}
+
+	asset, err := FindAsset(release, "linux", "amd64")
+	if err != nil || asset.Name != "gx_1.2.0_linux_amd64.tar.gz" {
+		t.Errorf("FindAsset(linux/amd64) = %q, %v", asset.Name, err)
+	}
+
+	asset, err = FindAsset(release, "windows", "amd64")
+	if err != nil || asset.Name != "gx_1.2.0_windows_amd64.zip" {
+		t.Errorf("FindAsset(windows/amd64) = %q, %v", asset.Name, err)
+	}
+
+	if _, err := FindAsset(release, "linux", "arm64"); !errors.Is(err, ErrNoAsset) {
+		t.Errorf("FindAsset(linux/arm64) error = %v, want ErrNoAsset", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := []byte("binary")
+	sum := sha256.Sum256(data)
+	checksums := ParseChecksums([]byte(hex.EncodeToString(sum[:]) + "  gx_1.2.0_linux_amd64.tar.gz\nmalformed\n"))
+
+	if err := Verify(data, "gx_1.2.0_linux_amd64.tar.gz", checksums); err != nil {
+		t.Errorf("Verify() error: %v", err)
+	}
+	if err := Verify([]byte("tampered"), "gx_1.2.0_linux_amd64.tar.gz", checksums); err == nil {
+		t.Error("Verify() expected mismatch error")
+	}
+	if err := Verify(data, "other.tar.gz", checksums); err == nil {
+		t.Error("Verify() expected error for missing checksum")
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	var tgz bytes.Buffer
+	gz := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"README.md": "docs", "gx": "unix binary"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+
+	got, err := ExtractBinary("gx_1.2.0_linux_amd64.tar.gz", tgz.Bytes(), "linux")
+	if err != nil || string(got) != "unix binary" {
+		t.Errorf("ExtractBinary(tar.gz) = %q, %v", got, err)
+	}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	w, _ := zw.Create("gx.exe")
+	w.Write([]byte("windows binary"))
+	zw.Close()
+
+	got, err = ExtractBinary("gx_1.2.0_windows_amd64.zip", zipBuf.Bytes(), "windows")
+	if err != nil || string(got) != "windows binary" {
+		t.Errorf("ExtractBinary(zip) = %q, %v", got, err)
+	}
+
+	if _, err := ExtractBinary("gx_1.2.0_windows_amd64.zip", zipBuf.Bytes(), "linux"); err == nil {
+		t.Error("ExtractBinary() expected error when the binary is missing")
+	}
+
+	got, err = ExtractBinary("gx_linux_amd64", []byte("raw"), "linux")
+	if err != nil || string(got) != "raw" {
+		t.Errorf("ExtractBinary(raw) = %q, %v", got, err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "gx")
+	if err := os.WriteFile(exe, []byte("old"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new" {
+		t.Errorf("binary = %q, want %q", data, "new")
+	}
+	info, _ := os.Stat(exe)
+	if info.Mode().Perm() != 0o750 {
+		t.Errorf("mode = %v, want 0750", info.Mode().Perm())
+	}
+}
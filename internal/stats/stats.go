@@ -0,0 +1,220 @@
+// Package stats computes aggregate health metrics for a module's dependencies.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/versions"
+	"github.com/omarshaarawi/gx/internal/vulndb"
+	"golang.org/x/mod/semver"
+)
+
+// Dependency is what is known about one required module. Latest is empty
+// when the proxy could not be reached for it.
+type Dependency struct {
+	Path     string
+	Version  string
+	Indirect bool
+	Released time.Time // zero if unknown
+	Latest   string
+	Behind   int // released versions newer than Version
+}
+
+// Lag counts outdated dependencies by the kind of update available
+type Lag struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+}
+
+// Oldest is the dependency whose installed version was released longest ago
+type Oldest struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	Days    int    `json:"days"`
+}
+
+// MostBehind is the dependency with the most newer releases available
+type MostBehind struct {
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Releases int    `json:"releases"`
+}
+
+// Vulnerabilities summarizes a vulnerability scan
+type Vulnerabilities struct {
+	Total      int            `json:"total"`
+	Modules    int            `json:"modules"`
+	BySeverity map[string]int `json:"by_severity"`
+}
+
+// Replacement is a dependency that moved to another module or was abandoned
+type Replacement struct {
+	Module      string `json:"module"`
+	Replacement string `json:"replacement,omitempty"` // empty for an abandoned module
+	Note        string `json:"note,omitempty"`
+}
+
+// Summary holds the aggregate metrics
+type Summary struct {
+	Total    int `json:"total"`
+	Direct   int `json:"direct"`
+	Indirect int `json:"indirect"`
+	UpToDate int `json:"up_to_date"`
+	Outdated int `json:"outdated"`
+	Unknown  int `json:"unknown"`
+	Lag      Lag `json:"lag"`
+
+	AverageAgeDays       float64 `json:"average_age_days"`
+	AverageDirectAgeDays float64 `json:"average_direct_age_days"`
+	Oldest               *Oldest `json:"oldest,omitempty"`
+
+	VersionsBehind        int         `json:"versions_behind"`
+	AverageVersionsBehind float64     `json:"average_versions_behind"`
+	MostBehind            *MostBehind `json:"most_behind,omitempty"`
+
+	// Vulnerabilities is nil when no scan was run
+	Vulnerabilities *Vulnerabilities `json:"vulnerabilities,omitempty"`
+
+	// Replacements are the dependencies with a known replacement
+	Replacements []Replacement `json:"replacements,omitempty"`
+}
+
+// Compute aggregates the dependency metrics as of now
+func Compute(deps []Dependency, now time.Time) Summary {
+	s := Summary{Total: len(deps)}
+
+	var ageSum, directAgeSum float64
+	var aged, directAged int
+
+	for _, d := range deps {
+		if d.Indirect {
+			s.Indirect++
+		} else {
+			s.Direct++
+		}
+
+		if !d.Released.IsZero() {
+			days := now.Sub(d.Released).Hours() / 24
+			ageSum += days
+			aged++
+			if !d.Indirect {
+				directAgeSum += days
+				directAged++
+			}
+			if s.Oldest == nil || int(days) > s.Oldest.Days {
+				s.Oldest = &Oldest{Module: d.Path, Version: d.Version, Days: int(days)}
+			}
+		}
+
+		if d.Latest == "" {
+			s.Unknown++
+			continue
+		}
+
+		switch versions.Classify(d.Version, d.Latest) {
+		case versions.Major:
+			s.Lag.Major++
+		case versions.Minor:
+			s.Lag.Minor++
+		case versions.Patch:
+			s.Lag.Patch++
+		default:
+			s.UpToDate++
+			continue
+		}
+		s.Outdated++
+
+		s.VersionsBehind += d.Behind
+		if d.Behind > 0 && (s.MostBehind == nil || d.Behind > s.MostBehind.Releases) {
+			s.MostBehind = &MostBehind{Module: d.Path, Version: d.Version, Releases: d.Behind}
+		}
+	}
+
+	if aged > 0 {
+		s.AverageAgeDays = round1(ageSum / float64(aged))
+	}
+	if directAged > 0 {
+		s.AverageDirectAgeDays = round1(directAgeSum / float64(directAged))
+	}
+	if known := s.Total - s.Unknown; known > 0 {
+		s.AverageVersionsBehind = round1(float64(s.VersionsBehind) / float64(known))
+	}
+
+	return s
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
+
+// CountBehind returns how many released versions in list are newer than
+// current. Pre-releases only count when current is itself a pre-release.
+func CountBehind(current string, list []string) int {
+	includePre := semver.Prerelease(current) != "" && !isPseudo(current)
+
+	n := 0
+	for _, v := range list {
+		if !semver.IsValid(v) || semver.Compare(v, current) <= 0 {
+			continue
+		}
+		if semver.Prerelease(v) != "" && !includePre {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// isPseudo reports whether v looks like a pseudo-version (vX.Y.Z-yyyymmddhhmmss-abcdef123456)
+func isPseudo(v string) bool {
+	pre := strings.TrimPrefix(semver.Prerelease(v), "-")
+	parts := strings.Split(pre, "-")
+	return len(parts) >= 2 && len(parts[len(parts)-1]) == 12
+}
+
+// SummarizeVulnerabilities counts findings by severity and affected module
+func SummarizeVulnerabilities(vulns []*vulndb.Vulnerability) *Vulnerabilities {
+	v := &Vulnerabilities{BySeverity: map[string]int{}}
+	seen := map[string]bool{}
+	for _, vuln := range vulns {
+		v.Total++
+		if !seen[vuln.Package] {
+			seen[vuln.Package] = true
+			v.Modules++
+		}
+		severity := strings.ToUpper(vuln.Severity)
+		if severity == "" {
+			severity = "UNKNOWN"
+		}
+		v.BySeverity[severity]++
+	}
+	return v
+}
+
+// Severities returns the severities present in v, most severe first
+func (v *Vulnerabilities) Severities() []string {
+	order := map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "MODERATE": 2, "LOW": 3}
+	var out []string
+	for s := range v.BySeverity {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ri, ok := order[out[i]]
+		if !ok {
+			ri = len(order)
+		}
+		rj, ok := order[out[j]]
+		if !ok {
+			rj = len(order)
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
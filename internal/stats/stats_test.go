@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/vulndb"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	deps := []Dependency{
+		{Path: "a", Version: "v1.0.0", Released: days(100), Latest: "v2.0.0", Behind: 5},
+		{Path: "b", Version: "v1.2.0", Released: days(300), Latest: "v1.3.0", Behind: 2},
+		{Path: "c", Version: "v0.1.0", Indirect: true, Released: days(20), Latest: "v0.1.0"},
+		{Path: "d", Version: "v1.0.0", Indirect: true, Latest: "v1.0.1", Behind: 1},
+		{Path: "e", Version: "v1.0.0", Indirect: true},
+	}
+
+	s := Compute(deps, now)
+
+	if s.Total != 5 || s.Direct != 2 || s.Indirect != 3 {
+		t.Errorf("counts = %d/%d/%d, want 5/2/3", s.Total, s.Direct, s.Indirect)
+	}
+	if s.UpToDate != 1 || s.Outdated != 3 || s.Unknown != 1 {
+		t.Errorf("up to date/outdated/unknown = %d/%d/%d, want 1/3/1", s.UpToDate, s.Outdated, s.Unknown)
+	}
+	if s.Lag != (Lag{Major: 1, Minor: 1, Patch: 1}) {
+		t.Errorf("Lag = %+v", s.Lag)
+	}
+	if s.AverageAgeDays != 140 {
+		t.Errorf("AverageAgeDays = %v, want 140", s.AverageAgeDays)
+	}
+	if s.AverageDirectAgeDays != 200 {
+		t.Errorf("AverageDirectAgeDays = %v, want 200", s.AverageDirectAgeDays)
+	}
+	if s.Oldest == nil || s.Oldest.Module != "b" || s.Oldest.Days != 300 {
+		t.Errorf("Oldest = %+v", s.Oldest)
+	}
+	if s.VersionsBehind != 8 || s.AverageVersionsBehind != 2 {
+		t.Errorf("VersionsBehind = %d, average %v; want 8, 2", s.VersionsBehind, s.AverageVersionsBehind)
+	}
+	if s.MostBehind == nil || s.MostBehind.Module != "a" || s.MostBehind.Releases != 5 {
+		t.Errorf("MostBehind = %+v", s.MostBehind)
+	}
+}
+
+func TestCompute_Empty(t *testing.T) {
+	s := Compute(nil, time.Now())
+	if s.Total != 0 || s.AverageAgeDays != 0 || s.Oldest != nil || s.MostBehind != nil {
+		t.Errorf("Compute(nil) = %+v", s)
+	}
+}
+
+func TestCountBehind(t *testing.T) {
+	list := []string{"v1.0.0", "v1.1.0", "v1.2.0-rc.1", "v1.2.0", "v2.0.0+incompatible", "bogus"}
+
+	tests := []struct {
+		current string
+		want    int
+	}{
+		{"v1.0.0", 3},
+		{"v1.2.0", 1},
+		{"v1.2.0-rc.0", 3},
+		{"v1.0.1-0.20240101000000-abcdefabcdef", 3},
+		{"v3.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := CountBehind(tt.current, list); got != tt.want {
+			t.Errorf("CountBehind(%q) = %d, want %d", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeVulnerabilities(t *testing.T) {
+	v := SummarizeVulnerabilities([]*vulndb.Vulnerability{
+		{ID: "GO-1", Package: "a", Severity: "HIGH"},
+		{ID: "GO-2", Package: "a", Severity: "critical"},
+		{ID: "GO-3", Package: "b"},
+	})
+
+	if v.Total != 3 || v.Modules != 2 {
+		t.Errorf("Total/Modules = %d/%d, want 3/2", v.Total, v.Modules)
+	}
+	if got, want := v.Severities(), []string{"CRITICAL", "HIGH", "UNKNOWN"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Severities() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import "strings"
+
+// accessible is whether output is meant for a screen reader
+var accessible bool
+
+// SetAccessible turns accessible mode on or off. It replaces spinners and
+// full-screen views with plain lines of text and prompts, and draws tables,
+// trees and rules with ASCII instead of box-drawing characters. Progress is
+// announced in plain lines unless another progress mode was set.
+func SetAccessible(enabled bool) {
+	accessible = enabled
+	if enabled && currentProgressMode == ProgressAuto {
+		currentProgressMode = ProgressPlain
+	}
+}
+
+// Accessible reports whether accessible mode is on
+func Accessible() bool {
+	return accessible
+}
+
+// border returns the box-drawing glyph, or its ASCII stand-in in accessible
+// mode
+func border(glyph, ascii string) string {
+	if accessible {
+		return ascii
+	}
+	return glyph
+}
+
+// Rule returns a horizontal line width characters long
+func Rule(width int) string {
+	return strings.Repeat(border("─", "-"), width)
+}
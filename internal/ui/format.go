@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Date format presets accepted by SetDateFormat
+var dateFormatPresets = map[string]string{
+	"iso":     "2006-01-02",
+	"us":      "01/02/2006",
+	"eu":      "02.01.2006",
+	"uk":      "02/01/2006",
+	"long":    "Jan 2, 2006",
+	"rfc3339": time.RFC3339,
+}
+
+var (
+	dateLayout = dateFormatPresets["iso"]
+	printer    = message.NewPrinter(language.English)
+)
+
+// SetDateFormat sets the layout used by FormatDate. It accepts a preset
+// name (iso, us, eu, uk, long, rfc3339) or a Go time layout.
+func SetDateFormat(format string) {
+	if format == "" {
+		return
+	}
+	if layout, ok := dateFormatPresets[strings.ToLower(format)]; ok {
+		dateLayout = layout
+		return
+	}
+	dateLayout = format
+}
+
+// SetLocale sets the locale used for number formatting. It accepts BCP 47
+// tags (de-DE) and POSIX locale names (de_DE.UTF-8). An empty locale falls
+// back to LC_ALL, LC_NUMERIC, then LANG.
+func SetLocale(locale string) {
+	if locale == "" {
+		locale = localeFromEnv()
+	}
+	if tag, ok := parseLocale(locale); ok {
+		printer = message.NewPrinter(tag)
+	}
+}
+
+func localeFromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseLocale(locale string) (language.Tag, bool) {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return language.English, true
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, false
+	}
+	return tag, true
+}
+
+// FormatCount formats a count with locale-specific digit grouping
+func FormatCount(n int) string {
+	return printer.Sprintf("%d", n)
+}
+
+// FormatBytes formats a byte count using binary units, e.g. "1.5 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return printer.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return printer.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+// FormatDate formats a date with the configured layout
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(dateLayout)
+}
+
+// RelativeTime describes how long ago t was, e.g. "3 months ago"
+func RelativeTime(t time.Time) string {
+	return relativeTime(t, time.Now())
+}
+
+// FormatReleaseTime shows a relative release time, adding the absolute date in verbose mode
+func FormatReleaseTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if IsVerbose() {
+		return fmt.Sprintf("%s (%s)", RelativeTime(t), FormatDate(t))
+	}
+	return RelativeTime(t)
+}
+
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	d := now.Sub(t)
+	if d < 0 {
+		return "just now"
+	}
+
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < day:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	case d < 30*day:
+		return plural(int(d/day), "day") + " ago"
+	case d < 365*day:
+		return plural(int(d/(30*day)), "month") + " ago"
+	default:
+		return plural(int(d/(365*day)), "year") + " ago"
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{"zero", time.Time{}, "-"},
+		{"seconds", now.Add(-30 * time.Second), "just now"},
+		{"future", now.Add(time.Hour), "just now"},
+		{"one minute", now.Add(-time.Minute), "1 minute ago"},
+		{"hours", now.Add(-5 * time.Hour), "5 hours ago"},
+		{"days", now.AddDate(0, 0, -3), "3 days ago"},
+		{"months", now.AddDate(0, -3, 0), "3 months ago"},
+		{"years", now.AddDate(-2, 0, -1), "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relativeTime(tt.t, now); got != tt.want {
+				t.Errorf("relativeTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	defer SetLocale("en")
+
+	SetLocale("en_US.UTF-8")
+	if got := FormatCount(1234567); got != "1,234,567" {
+		t.Errorf("FormatCount() en = %q", got)
+	}
+
+	SetLocale("de-DE")
+	if got := FormatCount(1234567); got != "1.234.567" {
+		t.Errorf("FormatCount() de = %q", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	defer SetLocale("en")
+	SetLocale("en")
+
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1,023 B"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+
+	SetLocale("de")
+	if got := FormatBytes(1536); got != "1,5 KB" {
+		t.Errorf("FormatBytes() de = %q", got)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	defer SetDateFormat("iso")
+
+	d := time.Date(2025, 3, 4, 0, 0, 0, 0, time.Local)
+
+	SetDateFormat("eu")
+	if got := FormatDate(d); got != "04.03.2025" {
+		t.Errorf("FormatDate() eu = %q", got)
+	}
+
+	SetDateFormat("Jan 2006")
+	if got := FormatDate(d); got != "Mar 2025" {
+		t.Errorf("FormatDate() custom = %q", got)
+	}
+
+	if got := FormatDate(time.Time{}); got != "-" {
+		t.Errorf("FormatDate() zero = %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyAction is something a key does in the interactive views, with its
+// default keys. The keys section of the config binds actions to other keys.
+type keyAction struct {
+	keys []string
+	help string
+}
+
+var keyActions = map[string]keyAction{
+	"up":            {[]string{"up", "k"}, "move up"},
+	"down":          {[]string{"down", "j"}, "move down"},
+	"next-page":     {[]string{"pgdown", "right", "l"}, "next page"},
+	"prev-page":     {[]string{"pgup", "left", "h"}, "previous page"},
+	"first":         {[]string{"g", "home"}, "go to first"},
+	"last":          {[]string{"G", "end"}, "go to last"},
+	"toggle":        {[]string{" "}, "toggle"},
+	"select-all":    {[]string{"a"}, "select all"},
+	"select-none":   {[]string{"n"}, "select none"},
+	"invert":        {[]string{"i"}, "invert selection"},
+	"selected-only": {[]string{"s"}, "show selected only"},
+	"details":       {[]string{"tab"}, "release notes"},
+	"filter":        {[]string{"/"}, "filter"},
+	"confirm":       {[]string{"enter"}, "confirm"},
+	"yes":           {[]string{"y"}, "yes"},
+	"no":            {[]string{"n"}, "no"},
+	"back":          {[]string{"b", "esc", "backspace"}, "go back"},
+	"quit":          {[]string{"q", "ctrl+c"}, "quit"},
+	"help":          {[]string{"?"}, "toggle help"},
+}
+
+// keyOverrides holds the keys configured for actions
+var keyOverrides map[string][]string
+
+// KeyActions lists the action names the keys section of the config accepts
+func KeyActions() []string {
+	names := make([]string, 0, len(keyActions))
+	for name := range keyActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetKeys binds actions to the configured keys, replacing their defaults.
+// Keys use bubbletea's names ("x", "ctrl+d", "pgdown"), with "space" for
+// the space bar. ctrl+c always quits, whatever quit is bound to.
+func SetKeys(keys map[string][]string) error {
+	overrides := make(map[string][]string, len(keys))
+	for action, list := range keys {
+		if _, ok := keyActions[action]; !ok {
+			return fmt.Errorf("unknown key action %q (want one of %s)", action, strings.Join(KeyActions(), ", "))
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("no keys given for %s", action)
+		}
+		bound := make([]string, len(list))
+		for i, k := range list {
+			if k == "space" {
+				k = " "
+			}
+			bound[i] = k
+		}
+		if action == "quit" && !slices.Contains(bound, "ctrl+c") {
+			bound = append(bound, "ctrl+c")
+		}
+		overrides[action] = bound
+	}
+	keyOverrides = overrides
+	return nil
+}
+
+// Key returns the binding of an action, with its configured keys
+func Key(action string) key.Binding {
+	a, ok := keyActions[action]
+	if !ok {
+		panic("unknown key action " + action)
+	}
+	keys := a.keys
+	if bound, ok := keyOverrides[action]; ok {
+		keys = bound
+	}
+
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k
+		if k == " " {
+			names[i] = "space"
+		}
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(names, "/"), a.help))
+}
+
+// ListKeys returns the navigation keys of a bubbles list bound to the
+// configured keys. Quitting, filtering and help are left to the view, so the
+// list never quits behind its back.
+func ListKeys() list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.CursorUp = Key("up")
+	km.CursorDown = Key("down")
+	km.NextPage = Key("next-page")
+	km.PrevPage = Key("prev-page")
+	km.GoToStart = Key("first")
+	km.GoToEnd = Key("last")
+
+	disabled := key.NewBinding(key.WithDisabled())
+	km.Filter, km.ClearFilter, km.CancelWhileFiltering, km.AcceptWhileFiltering = disabled, disabled, disabled, disabled
+	km.ShowFullHelp, km.CloseFullHelp = disabled, disabled
+	km.Quit, km.ForceQuit = disabled, disabled
+	return km
+}
+
+// FilterListKeys returns ListKeys with the list's own filter turned on: the
+// filter key starts typing a filter, enter accepts it and esc clears it
+func FilterListKeys() list.KeyMap {
+	km := ListKeys()
+	def := list.DefaultKeyMap()
+	km.Filter = Key("filter")
+	km.ClearFilter, km.CancelWhileFiltering, km.AcceptWhileFiltering = def.ClearFilter, def.CancelWhileFiltering, def.AcceptWhileFiltering
+	return km
+}
+
+var (
+	keyHelpStyle      = lipgloss.NewStyle().Margin(1, 2)
+	keyHelpTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+)
+
+// ShortKeyHelp renders bindings on one line, for the header of a view
+func ShortKeyHelp(bindings ...key.Binding) string {
+	return help.New().ShortHelpView(bindings)
+}
+
+// KeyHelp renders the ? overlay of a view: every binding, in columns of
+// related keys
+func KeyHelp(title string, columns ...[]key.Binding) string {
+	h := help.New()
+	closeHelp := key.NewBinding(key.WithKeys("esc"), key.WithHelp(Key("help").Help().Key+"/esc", "close help"))
+	return keyHelpStyle.Render(keyHelpTitleStyle.Render(title) + "\n\n" +
+		h.FullHelpView(columns) + "\n\n" +
+		h.ShortHelpView([]key.Binding{closeHelp}))
+}
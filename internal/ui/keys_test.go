@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKey_Defaults(t *testing.T) {
+	toggle := Key("toggle")
+	if got := toggle.Keys(); !reflect.DeepEqual(got, []string{" "}) {
+		t.Errorf("toggle keys = %q, want space", got)
+	}
+	if got := toggle.Help().Key; got != "space" {
+		t.Errorf("toggle help = %q, want space", got)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}, Key("down")) {
+		t.Error("j doesn't move down")
+	}
+}
+
+func TestSetKeys(t *testing.T) {
+	defer SetKeys(nil)
+
+	err := SetKeys(map[string][]string{
+		"toggle": {"x", "space"},
+		"quit":   {"Q"},
+	})
+	if err != nil {
+		t.Fatalf("SetKeys() error: %v", err)
+	}
+	if got := Key("toggle").Keys(); !reflect.DeepEqual(got, []string{"x", " "}) {
+		t.Errorf("toggle keys = %q, want x and space", got)
+	}
+	if got := Key("toggle").Help().Key; got != "x/space" {
+		t.Errorf("toggle help = %q, want x/space", got)
+	}
+	if got := Key("quit").Keys(); !reflect.DeepEqual(got, []string{"Q", "ctrl+c"}) {
+		t.Errorf("quit keys = %q, want Q and ctrl+c kept", got)
+	}
+	if got := Key("confirm").Keys(); !reflect.DeepEqual(got, []string{"enter"}) {
+		t.Errorf("confirm keys = %q, want the default", got)
+	}
+
+	if err := SetKeys(map[string][]string{"jump": {"J"}}); err == nil || !strings.Contains(err.Error(), "jump") {
+		t.Errorf("SetKeys(jump) error = %v, want unknown action", err)
+	}
+	if err := SetKeys(map[string][]string{"toggle": nil}); err == nil {
+		t.Error("SetKeys() without keys succeeded")
+	}
+}
+
+func TestKeyHelp(t *testing.T) {
+	got := KeyHelp("Keys", []key.Binding{Key("up"), Key("down")}, []key.Binding{Key("quit")})
+	for _, want := range []string{"Keys", "up/k", "move down", "q/ctrl+c", "?/esc"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("KeyHelp() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestFilterListKeys(t *testing.T) {
+	defer SetKeys(nil)
+
+	if km := ListKeys(); km.Filter.Enabled() {
+		t.Error("ListKeys() filter is enabled")
+	}
+	if err := SetKeys(map[string][]string{"filter": {"f"}}); err != nil {
+		t.Fatalf("SetKeys() error: %v", err)
+	}
+	km := FilterListKeys()
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")}, km.Filter) {
+		t.Error("the configured key doesn't start filtering")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyEsc}, km.ClearFilter) {
+		t.Error("esc doesn't clear the filter")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyEnter}, km.AcceptWhileFiltering) {
+		t.Error("enter doesn't accept the filter")
+	}
+}
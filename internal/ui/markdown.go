@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MarkdownTable renders a GitHub-flavored markdown table. Columns are padded
+// so the source stays readable when pasted into a PR comment.
+type MarkdownTable struct {
+	Headers []string
+	Rows    [][]string
+	Widths  []int
+}
+
+// NewMarkdownTable creates a markdown table with the given headers
+func NewMarkdownTable(headers ...string) *MarkdownTable {
+	t := &MarkdownTable{Widths: make([]int, len(headers))}
+	for i, h := range headers {
+		t.Headers = append(t.Headers, MarkdownEscape(h))
+		t.Widths[i] = max(lipgloss.Width(t.Headers[i]), 3)
+	}
+	return t
+}
+
+// AddRow adds a row of cells. Cells are markdown, so plain text should go
+// through MarkdownEscape or MarkdownCode first.
+func (t *MarkdownTable) AddRow(cells ...string) {
+	if len(cells) != len(t.Headers) {
+		return
+	}
+
+	for i, cell := range cells {
+		t.Widths[i] = max(t.Widths[i], lipgloss.Width(cell))
+	}
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render renders the table as markdown
+func (t *MarkdownTable) Render() string {
+	var b strings.Builder
+
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i, cell := range cells {
+			b.WriteString(" " + padRight(cell, t.Widths[i]) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.Headers)
+	b.WriteString("|")
+	for _, w := range t.Widths {
+		b.WriteString(" " + strings.Repeat("-", w) + " |")
+	}
+	b.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+
+	return b.String()
+}
+
+// markdownEscaper escapes the characters that would end a table cell or
+// start inline formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"<", "&lt;",
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// MarkdownEscape escapes text for use in markdown, on a single line
+func MarkdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// MarkdownCode wraps text in a code span, keeping it usable inside a table
+func MarkdownCode(s string) string {
+	s = strings.NewReplacer("`", "'", "|", `\|`, "\n", " ").Replace(s)
+	return "`" + s + "`"
+}
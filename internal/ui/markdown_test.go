@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestMarkdownTable(t *testing.T) {
+	table := NewMarkdownTable("Package", "Update", "a|b")
+	table.AddRow(MarkdownCode("github.com/spf13/cobra"), "minor", "x")
+	table.AddRow(MarkdownCode("gopkg.in/yaml.v3"), MarkdownEscape("major *breaking*"), "-")
+	table.AddRow(MarkdownCode("golang.org/x/mod"), "▲ major", "-")
+	table.AddRow("too", "few")
+
+	want := "| Package                  | Update             | a\\|b |\n" +
+		"| ------------------------ | ------------------ | ---- |\n" +
+		"| `github.com/spf13/cobra` | minor              | x    |\n" +
+		"| `gopkg.in/yaml.v3`       | major \\*breaking\\* | -    |\n" +
+		"| `golang.org/x/mod`       | ▲ major            | -    |\n"
+	if got := table.Render(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := map[string]string{
+		"plain":          "plain",
+		"a|b":            `a\|b`,
+		"snake_case":     `snake\_case`,
+		"two\nlines":     "two lines",
+		"<script>":       "&lt;script>",
+		`back\slash`:     `back\\slash`,
+		"`code`":         "\\`code\\`",
+		"**bold**":       `\*\*bold\*\*`,
+		"crlf\r\nending": "crlf ending",
+	}
+	for in, want := range tests {
+		if got := MarkdownEscape(in); got != want {
+			t.Errorf("MarkdownEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	if got := MarkdownCode("a|b`c"); got != "`a\\|b'c`" {
+		t.Errorf("MarkdownCode() = %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mouseEnabled is whether the interactive views take the mouse
+var mouseEnabled = true
+
+// SetMouse turns mouse support in the interactive views on or off
+func SetMouse(enabled bool) {
+	mouseEnabled = enabled
+}
+
+// MouseEnabled reports whether the interactive views take the mouse
+func MouseEnabled() bool {
+	return mouseEnabled
+}
+
+// InteractiveOptions returns the options of a full-screen interactive view:
+// the alternate screen, and mouse cell motion unless the mouse is disabled
+func InteractiveOptions() []tea.ProgramOption {
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if mouseEnabled {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return opts
+}
+
+// ListMouse handles a mouse message for a list whose view starts on line
+// top, with rows rowHeight lines apart. The wheel moves the cursor and a left
+// click moves it to the clicked row, reporting true so the view can act on it.
+func ListMouse(l *list.Model, msg tea.MouseMsg, top, rowHeight int) bool {
+	if l.ShowTitle() {
+		top += lipgloss.Height(l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)))
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		l.CursorUp()
+	case tea.MouseButtonWheelDown:
+		l.CursorDown()
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress || msg.Y < top {
+			return false
+		}
+		row := (msg.Y - top) / rowHeight
+		if row >= l.Paginator.ItemsOnPage(len(l.VisibleItems())) {
+			return false
+		}
+		l.Select(l.Paginator.Page*l.Paginator.PerPage + row)
+		return true
+	}
+	return false
+}
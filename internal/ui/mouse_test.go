@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type mouseItem string
+
+func (i mouseItem) FilterValue() string { return string(i) }
+
+func TestListMouse(t *testing.T) {
+	items := make([]list.Item, 10)
+	for i := range items {
+		items[i] = mouseItem(string(rune('a' + i)))
+	}
+	d := list.NewDefaultDelegate()
+	d.ShowDescription = false
+	d.SetSpacing(0)
+	l := list.New(items, d, 40, 6)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	if ListMouse(&l, tea.MouseMsg{Button: tea.MouseButtonWheelDown}, 2, 1) {
+		t.Error("wheel reported a click")
+	}
+	if l.Index() != 1 {
+		t.Errorf("after wheel down, index = %d, want 1", l.Index())
+	}
+
+	click := func(y int) tea.MouseMsg {
+		return tea.MouseMsg{X: 3, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	}
+	if !ListMouse(&l, click(4), 2, 1) {
+		t.Fatal("click on a row not reported")
+	}
+	if l.Index() != 2 {
+		t.Errorf("after click, index = %d, want 2", l.Index())
+	}
+	if ListMouse(&l, click(1), 2, 1) {
+		t.Error("click above the list reported")
+	}
+	if ListMouse(&l, click(40), 2, 1) {
+		t.Error("click below the rows reported")
+	}
+
+	l.NextPage()
+	if !ListMouse(&l, click(2), 2, 1) {
+		t.Fatal("click on the second page not reported")
+	}
+	if want := l.Paginator.PerPage; l.Index() != want {
+		t.Errorf("after click on page 2, index = %d, want %d", l.Index(), want)
+	}
+}
+
+func TestInteractiveOptions(t *testing.T) {
+	defer SetMouse(true)
+	if got := len(InteractiveOptions()); got != 2 {
+		t.Errorf("with the mouse, %d options, want 2", got)
+	}
+	SetMouse(false)
+	if got := len(InteractiveOptions()); got != 1 {
+		t.Errorf("without the mouse, %d options, want 1", got)
+	}
+}
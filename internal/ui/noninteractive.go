@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ErrNonInteractive is returned by work that needs input when gx runs
+// non-interactively
+var ErrNonInteractive = errors.New("gx is running non-interactively")
+
+// nonInteractive is why gx runs without asking for input, empty when it may
+var nonInteractive string
+
+// ciVariables are set by CI systems, some of them to true and some to an ID
+var ciVariables = []string{
+	"CI", "CONTINUOUS_INTEGRATION", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE",
+	"CIRCLECI", "TRAVIS", "JENKINS_URL", "TF_BUILD", "TEAMCITY_VERSION",
+	"CODEBUILD_BUILD_ID", "BITBUCKET_BUILD_NUMBER", "DRONE", "WOODPECKER",
+}
+
+// SetNonInteractive stops gx from asking for input, with the reason given
+// in errors, such as "--non-interactive". Spinners are turned off, as they
+// read the terminal too, and the pager is skipped. An empty reason allows
+// input again.
+func SetNonInteractive(reason string) {
+	nonInteractive = reason
+	if reason != "" && currentProgressMode == ProgressAuto {
+		currentProgressMode = ProgressNone
+	}
+}
+
+// NonInteractive reports whether gx runs without asking for input
+func NonInteractive() bool {
+	return nonInteractive != ""
+}
+
+// RequireInteractive fails in non-interactive mode, naming what asks for
+// input and what to do instead, such as "pass --all"
+func RequireInteractive(what, instead string) error {
+	if nonInteractive == "" {
+		return nil
+	}
+	return fmt.Errorf("%w (%s): %s asks for input; %s", ErrNonInteractive, nonInteractive, what, instead)
+}
+
+// DetectCI returns the environment variable that says a CI system runs gx,
+// empty when none does. Variables set to a false value don't count.
+func DetectCI() string {
+	for _, name := range ciVariables {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		if b, err := strconv.ParseBool(v); err == nil && !b {
+			continue
+		}
+		return name
+	}
+	return ""
+}
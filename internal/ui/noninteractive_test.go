@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDetectCI(t *testing.T) {
+	for _, name := range ciVariables {
+		t.Setenv(name, "")
+	}
+	if got := DetectCI(); got != "" {
+		t.Errorf("DetectCI() without CI = %q", got)
+	}
+
+	t.Setenv("CI", "false")
+	if got := DetectCI(); got != "" {
+		t.Errorf("DetectCI() with CI=false = %q", got)
+	}
+
+	t.Setenv("JENKINS_URL", "https://jenkins.example.com")
+	if got := DetectCI(); got != "JENKINS_URL" {
+		t.Errorf("DetectCI() in Jenkins = %q", got)
+	}
+
+	t.Setenv("CI", "true")
+	if got := DetectCI(); got != "CI" {
+		t.Errorf("DetectCI() with CI=true = %q", got)
+	}
+}
+
+func TestRequireInteractive(t *testing.T) {
+	defer func() {
+		SetNonInteractive("")
+		SetProgressMode("auto")
+	}()
+
+	if err := RequireInteractive("gx update -i", "pass --all"); err != nil {
+		t.Fatalf("RequireInteractive() when interactive = %v", err)
+	}
+
+	SetNonInteractive("CI is set")
+	if GetProgressMode() != ProgressNone {
+		t.Errorf("progress mode = %v, want none", GetProgressMode())
+	}
+	err := RequireInteractive("gx update -i", "pass --all")
+	if !errors.Is(err, ErrNonInteractive) {
+		t.Fatalf("RequireInteractive() = %v, want ErrNonInteractive", err)
+	}
+	if want := "gx is running non-interactively (CI is set): gx update -i asks for input; pass --all"; err.Error() != want {
+		t.Errorf("RequireInteractive() = %q, want %q", err, want)
+	}
+
+	if _, err := PromptConfirm("Update?"); !errors.Is(err, ErrNonInteractive) || !strings.Contains(err.Error(), `"Update? (y/N)"`) {
+		t.Errorf("PromptConfirm() = %v, want ErrNonInteractive", err)
+	}
+}
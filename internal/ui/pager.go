@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+const defaultPager = "less -FRX"
+
+// Page writes content to stdout, piping it through a pager when stdout is a
+// terminal and gx may ask for input.
+// The pager is taken from GX_PAGER, then PAGER, falling back to less.
+func Page(content string, disable bool) error {
+	if disable || NonInteractive() || !isatty.IsTerminal(os.Stdout.Fd()) {
+		_, err := io.WriteString(os.Stdout, content)
+		return err
+	}
+
+	args := strings.Fields(pagerCommand())
+	if len(args) == 0 {
+		_, err := io.WriteString(os.Stdout, content)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			_, werr := io.WriteString(os.Stdout, content)
+			return werr
+		}
+		return fmt.Errorf("running pager: %w", err)
+	}
+	return nil
+}
+
+func pagerCommand() string {
+	for _, key := range []string{"GX_PAGER", "PAGER"} {
+		if v, ok := os.LookupEnv(key); ok {
+			return v
+		}
+	}
+	return defaultPager
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PorcelainVersion is the version of the --porcelain format, written in the
+// first record of every command's output. It changes only when existing
+// records change; new record types and fields appended to a record don't
+// change it, so scripts should ignore both.
+const PorcelainVersion = 1
+
+// Porcelain writes --porcelain output for scripts: one record per line,
+// fields separated by tabs, the record type first. Fields are never styled,
+// tabs and newlines inside them become spaces and empty fields stay empty.
+type Porcelain struct {
+	w io.Writer
+}
+
+// NewPorcelain writes records to w
+func NewPorcelain(w io.Writer) *Porcelain {
+	return &Porcelain{w: w}
+}
+
+// StartPorcelain begins porcelain output on stdout with the version record,
+// "version 1 <command>". Everything else printed afterwards goes to stderr
+// and spinners are turned off, so stdout only carries records.
+func StartPorcelain(command string) *Porcelain {
+	p := NewPorcelain(os.Stdout)
+	os.Stdout = os.Stderr
+	if currentProgressMode == ProgressAuto {
+		currentProgressMode = ProgressNone
+	}
+	p.Record("version", strconv.Itoa(PorcelainVersion), command)
+	return p
+}
+
+// Record writes one record of the given type
+func (p *Porcelain) Record(kind string, fields ...string) {
+	line := make([]string, 0, len(fields)+1)
+	line = append(line, kind)
+	for _, f := range fields {
+		line = append(line, porcelainField(f))
+	}
+	fmt.Fprintln(p.w, strings.Join(line, "\t"))
+}
+
+var porcelainReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func porcelainField(s string) string {
+	return porcelainReplacer.Replace(s)
+}
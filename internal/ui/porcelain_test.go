@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPorcelain_Record(t *testing.T) {
+	var b strings.Builder
+	p := NewPorcelain(&b)
+	p.Record("package", "example.com/a", "v1.0.0", "", "two\tfields")
+	p.Record("failed", "example.com/b", "line one\nline two\r\n")
+	p.Record("empty")
+
+	want := "package\texample.com/a\tv1.0.0\t\ttwo fields\n" +
+		"failed\texample.com/b\tline one line two \n" +
+		"empty\n"
+	if got := b.String(); got != want {
+		t.Errorf("Record() wrote\n%q\nwant\n%q", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressMode controls how long-running work reports progress
+type ProgressMode int
+
+const (
+	// ProgressAuto shows interactive spinners
+	ProgressAuto ProgressMode = iota
+	// ProgressJSON emits newline-delimited JSON events on stderr
+	ProgressJSON
+	// ProgressNone runs work without progress output
+	ProgressNone
+	// ProgressPlain announces progress in plain lines of text on stderr, one
+	// when work starts, at each quarter done and when it ends, for screen
+	// readers
+	ProgressPlain
+)
+
+var (
+	currentProgressMode           = ProgressAuto
+	progressOut         io.Writer = os.Stderr
+	progressMu          sync.Mutex
+
+	// announced is the last quarter of each phase announced in plain mode
+	announced = map[string]int{}
+)
+
+// ProgressEvent is a single progress record emitted in JSON mode
+type ProgressEvent struct {
+	Phase     string    `json:"phase"`
+	Status    string    `json:"status"` // start, progress, done, error
+	Completed int       `json:"completed"`
+	Total     int       `json:"total"`
+	Item      string    `json:"item,omitempty"`
+	Time      time.Time `json:"time"`
+
+	// Message describes the work in plain mode, such as "Checking for
+	// updates...", defaulting to the phase
+	Message string `json:"-"`
+}
+
+// SetProgressMode parses and sets the progress mode (auto, json, plain, none)
+func SetProgressMode(mode string) error {
+	switch mode {
+	case "", "auto":
+		currentProgressMode = ProgressAuto
+	case "json":
+		currentProgressMode = ProgressJSON
+	case "plain":
+		currentProgressMode = ProgressPlain
+	case "none":
+		currentProgressMode = ProgressNone
+	default:
+		return fmt.Errorf("invalid progress mode %q (use auto, json, plain, or none)", mode)
+	}
+	return nil
+}
+
+// GetProgressMode returns the current progress mode
+func GetProgressMode() ProgressMode {
+	return currentProgressMode
+}
+
+// EmitProgress writes a progress event when JSON progress is enabled, or
+// announces it in plain progress mode
+func EmitProgress(event ProgressEvent) {
+	if currentProgressMode == ProgressPlain {
+		announce(event)
+		return
+	}
+	if currentProgressMode != ProgressJSON {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	fmt.Fprintln(progressOut, string(data))
+}
+
+// announce writes a line of text for the start and end of a phase, and for
+// each quarter of it done, so a screen reader isn't flooded with counts
+func announce(event ProgressEvent) {
+	message := strings.TrimSuffix(event.Message, "...")
+	if message == "" {
+		message = event.Phase
+	}
+
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	switch event.Status {
+	case "start":
+		announced[event.Phase] = 0
+		if event.Total > 0 {
+			fmt.Fprintf(progressOut, "%s: started, %s to do\n", message, FormatCount(event.Total))
+		} else {
+			fmt.Fprintf(progressOut, "%s: started\n", message)
+		}
+	case "progress":
+		if event.Total <= 0 || event.Completed >= event.Total {
+			return
+		}
+		quarter := event.Completed * 4 / event.Total
+		if quarter <= announced[event.Phase] {
+			return
+		}
+		announced[event.Phase] = quarter
+		fmt.Fprintf(progressOut, "%s: %s of %s done\n", message, FormatCount(event.Completed), FormatCount(event.Total))
+	case "done":
+		fmt.Fprintf(progressOut, "%s: done\n", message)
+	case "error":
+		fmt.Fprintf(progressOut, "%s: failed\n", message)
+	}
+}
+
+// phaseName derives a stable phase identifier from a spinner message
+func phaseName(message string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(message) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// runWithoutSpinner runs a task reporting progress as events instead of a TUI
+func runWithoutSpinner[T any](task SpinnerTask[T]) (T, error) {
+	phase := task.Phase
+	if phase == "" {
+		phase = phaseName(task.Message)
+	}
+
+	EmitProgress(ProgressEvent{Phase: phase, Status: "start", Total: task.Total, Message: task.Message})
+
+	progressCh := make(chan int, task.Total+1)
+	done := make(chan struct{})
+	go func() {
+		for completed := range progressCh {
+			EmitProgress(ProgressEvent{Phase: phase, Status: "progress", Completed: completed, Total: task.Total, Message: task.Message})
+		}
+		close(done)
+	}()
+
+	result, err := task.Run(progressCh)
+	close(progressCh)
+	<-done
+
+	status := "done"
+	if err != nil {
+		status = "error"
+	}
+	EmitProgress(ProgressEvent{Phase: phase, Status: status, Completed: task.Total, Total: task.Total, Message: task.Message})
+
+	return result, err
+}
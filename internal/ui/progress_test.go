@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPhaseName(t *testing.T) {
+	tests := map[string]string{
+		"Checking for updates...":         "checking-for-updates",
+		"Scanning for vulnerabilities...": "scanning-for-vulnerabilities",
+		"  go.mod  ":                      "go-mod",
+	}
+
+	for message, want := range tests {
+		if got := phaseName(message); got != want {
+			t.Errorf("phaseName(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestSetProgressMode(t *testing.T) {
+	defer SetProgressMode("auto")
+
+	if err := SetProgressMode("bogus"); err == nil {
+		t.Error("SetProgressMode(bogus) expected error")
+	}
+	if err := SetProgressMode("json"); err != nil || GetProgressMode() != ProgressJSON {
+		t.Errorf("SetProgressMode(json) = %v, mode %v", err, GetProgressMode())
+	}
+}
+
+func TestRunWithSpinner_JSONProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progressOut = &buf
+	SetProgressMode("json")
+	defer func() {
+		SetProgressMode("auto")
+		progressOut = os.Stderr
+	}()
+
+	result, err := RunWithSpinner(SpinnerTask[int]{
+		Message: "Checking for updates...",
+		Phase:   "check-updates",
+		Total:   2,
+		Run: func(progress chan<- int) (int, error) {
+			progress <- 1
+			progress <- 2
+			return 42, nil
+		},
+	})
+	if err != nil || result != 42 {
+		t.Fatalf("RunWithSpinner() = %v, %v", result, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d events, want 4:\n%s", len(lines), buf.String())
+	}
+
+	var statuses []string
+	for _, line := range lines {
+		var event ProgressEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if event.Phase != "check-updates" || event.Total != 2 {
+			t.Errorf("unexpected event %+v", event)
+		}
+		statuses = append(statuses, event.Status)
+	}
+
+	if got := strings.Join(statuses, ","); got != "start,progress,progress,done" {
+		t.Errorf("statuses = %s", got)
+	}
+}
+
+func TestRunWithSpinner_PlainProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progressOut = &buf
+	SetProgressMode("plain")
+	defer func() {
+		SetProgressMode("auto")
+		progressOut = os.Stderr
+	}()
+
+	_, err := RunWithSpinner(SpinnerTask[int]{
+		Message: "Checking for updates...",
+		Total:   8,
+		Run: func(progress chan<- int) (int, error) {
+			for i := 1; i <= 8; i++ {
+				progress <- i
+			}
+			return 0, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Checking for updates: started, 8 to do\n" +
+		"Checking for updates: 2 of 8 done\n" +
+		"Checking for updates: 4 of 8 done\n" +
+		"Checking for updates: 6 of 8 done\n" +
+		"Checking for updates: done\n"
+	if buf.String() != want {
+		t.Errorf("plain progress =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The prompts read answers a line at a time from stdin. They stand in for
+// the full-screen views in accessible mode, asking one question per line.
+var (
+	promptIn            = bufio.NewReader(os.Stdin)
+	promptOut io.Writer = os.Stdout
+)
+
+// PromptChoice lists the options numbered and returns the indexes of those
+// picked, nil for none. With multiple, the answer can name several numbers
+// separated by spaces or commas, ranges such as 2-5, or "all"; otherwise
+// one number. An empty answer, or the end of input, picks none, and an
+// invalid answer is asked again.
+func PromptChoice(title string, options []string, multiple bool) ([]int, error) {
+	fmt.Fprintf(promptOut, "\n%s\n", title)
+	for i, option := range options {
+		fmt.Fprintf(promptOut, "%d. %s\n", i+1, option)
+	}
+
+	question := "Enter a number, or nothing to cancel: "
+	if multiple {
+		question = "Enter numbers or ranges (such as 1 3 5-7), all, or nothing to cancel: "
+	}
+	for {
+		answer, err := readAnswer(question)
+		if err != nil || answer == "" {
+			return nil, err
+		}
+		picked, err := parseChoice(answer, len(options), multiple)
+		if err == nil {
+			return picked, nil
+		}
+		fmt.Fprintf(promptOut, "%v\n", err)
+	}
+}
+
+// PromptConfirm asks a yes or no question, no by default
+func PromptConfirm(question string) (bool, error) {
+	for {
+		answer, err := readAnswer(question + " (y/N): ")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(promptOut, "Answer y or n")
+	}
+}
+
+// PromptValue asks for a value, returning value when the answer is empty
+func PromptValue(label, value string) (string, error) {
+	answer, err := readAnswer(fmt.Sprintf("%s (default %s): ", label, value))
+	if err != nil || answer == "" {
+		return value, err
+	}
+	return answer, nil
+}
+
+// readAnswer asks question and reads the answer, trimmed. The end of input
+// answers with nothing, and non-interactive mode fails.
+func readAnswer(question string) (string, error) {
+	if nonInteractive != "" {
+		return "", fmt.Errorf("%w (%s): can't ask %q", ErrNonInteractive, nonInteractive, strings.TrimSuffix(question, ": "))
+	}
+	fmt.Fprint(promptOut, question)
+	line, err := promptIn.ReadString('\n')
+	if err == io.EOF {
+		fmt.Fprintln(promptOut)
+		err = nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// parseChoice parses an answer to PromptChoice into zero-based indexes of n
+// options, in the order given and without repeats
+func parseChoice(answer string, n int, multiple bool) ([]int, error) {
+	if multiple && strings.EqualFold(answer, "all") {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	fields := strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' })
+	if !multiple && (len(fields) != 1 || strings.Contains(fields[0], "-")) {
+		return nil, fmt.Errorf("enter one number from 1 to %d", n)
+	}
+
+	var picked []int
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("%q is not a number or range from 1 to %d", field, n)
+		}
+		for i := first - 1; i < last; i++ {
+			if !seen[i] {
+				seen[i] = true
+				picked = append(picked, i)
+			}
+		}
+	}
+	return picked, nil
+}
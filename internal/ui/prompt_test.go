@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChoice(t *testing.T) {
+	tests := []struct {
+		answer   string
+		multiple bool
+		want     []int
+		wantErr  bool
+	}{
+		{"2", false, []int{1}, false},
+		{"1 3", false, nil, true},
+		{"1-2", false, nil, true},
+		{"1 3,5", true, []int{0, 2, 4}, false},
+		{"4-5 1 5", true, []int{3, 4, 0}, false},
+		{"all", true, []int{0, 1, 2, 3, 4}, false},
+		{"all", false, nil, true},
+		{"0", true, nil, true},
+		{"6", true, nil, true},
+		{"3-2", true, nil, true},
+		{"x", true, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseChoice(tt.answer, 5, tt.multiple)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseChoice(%q, %v) = %v, %v; want %v, error %v", tt.answer, tt.multiple, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPromptChoice(t *testing.T) {
+	var out bytes.Buffer
+	promptIn, promptOut = bufio.NewReader(strings.NewReader("9\n1, 3\n")), &out
+	defer func() { promptIn, promptOut = bufio.NewReader(os.Stdin), io.Writer(os.Stdout) }()
+
+	got, err := PromptChoice("Pick modules", []string{"a", "b", "c"}, true)
+	if err != nil || !reflect.DeepEqual(got, []int{0, 2}) {
+		t.Fatalf("PromptChoice() = %v, %v", got, err)
+	}
+	for _, want := range []string{"Pick modules\n1. a\n2. b\n3. c\n", `"9" is not a number or range from 1 to 3`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// The end of input cancels
+	got, err = PromptChoice("Pick modules", []string{"a"}, true)
+	if err != nil || got != nil {
+		t.Errorf("PromptChoice() at end of input = %v, %v", got, err)
+	}
+}
+
+func TestPromptConfirmAndValue(t *testing.T) {
+	promptIn, promptOut = bufio.NewReader(strings.NewReader("maybe\nyes\n\nhttp://proxy\n")), io.Discard
+	defer func() { promptIn, promptOut = bufio.NewReader(os.Stdin), io.Writer(os.Stdout) }()
+
+	if ok, err := PromptConfirm("Update?"); !ok || err != nil {
+		t.Errorf("PromptConfirm() = %v, %v", ok, err)
+	}
+	if v, err := PromptValue("Timeout", "30s"); v != "30s" || err != nil {
+		t.Errorf("PromptValue() empty = %q, %v", v, err)
+	}
+	if v, err := PromptValue("Proxy URL", "https://proxy.golang.org"); v != "http://proxy" || err != nil {
+		t.Errorf("PromptValue() = %q, %v", v, err)
+	}
+}
@@ -71,11 +71,11 @@ func (m spinnerModel[T]) View() string {
 	}
 
 	if m.total > 0 {
-		return fmt.Sprintf("\n %s %s (%d/%d)\n",
+		return fmt.Sprintf("\n %s %s (%s/%s)\n",
 			m.spinner.View(),
 			m.message,
-			m.progress,
-			m.total,
+			FormatCount(m.progress),
+			FormatCount(m.total),
 		)
 	}
 
@@ -84,11 +84,16 @@ func (m spinnerModel[T]) View() string {
 
 type SpinnerTask[T any] struct {
 	Message string
+	Phase   string // stable identifier for JSON progress, derived from Message if empty
 	Total   int
 	Run     func(progress chan<- int) (T, error)
 }
 
 func RunWithSpinner[T any](task SpinnerTask[T]) (T, error) {
+	if currentProgressMode != ProgressAuto {
+		return runWithoutSpinner(task)
+	}
+
 	m := newSpinnerModel[T](task.Message, task.Total)
 	p := tea.NewProgram(m)
 
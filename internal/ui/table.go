@@ -18,7 +18,7 @@ type Table struct {
 func NewTable(headers ...string) *Table {
 	widths := make([]int, len(headers))
 	for i, h := range headers {
-		widths[i] = len(h)
+		widths[i] = lipgloss.Width(h)
 	}
 	return &Table{
 		Headers: headers,
@@ -33,8 +33,8 @@ func (t *Table) AddRow(cells ...string) {
 	}
 
 	for i, cell := range cells {
-		if len(cell) > t.Widths[i] {
-			t.Widths[i] = len(cell)
+		if w := lipgloss.Width(cell); w > t.Widths[i] {
+			t.Widths[i] = w
 		}
 	}
 
@@ -43,6 +43,11 @@ func (t *Table) AddRow(cells ...string) {
 
 // Render renders the table as a string
 func (t *Table) Render() string {
+	return t.renderPlain(func(int, int, string) lipgloss.Style { return CellStyle })
+}
+
+// renderPlain renders the table without borders, the header underlined
+func (t *Table) renderPlain(styleFunc func(rowIdx, colIdx int, cell string) lipgloss.Style) string {
 	var b strings.Builder
 
 	for i, header := range t.Headers {
@@ -54,17 +59,17 @@ func (t *Table) Render() string {
 	b.WriteString("\n")
 
 	for i := range t.Headers {
-		b.WriteString(strings.Repeat("─", t.Widths[i]))
+		b.WriteString(Rule(t.Widths[i]))
 		if i < len(t.Headers)-1 {
 			b.WriteString("  ")
 		}
 	}
 	b.WriteString("\n")
 
-	for _, row := range t.Rows {
-		for i, cell := range row {
-			b.WriteString(CellStyle.Render(padRight(cell, t.Widths[i])))
-			if i < len(row)-1 {
+	for rowIdx, row := range t.Rows {
+		for colIdx, cell := range row {
+			b.WriteString(styleFunc(rowIdx, colIdx, cell).Render(padRight(cell, t.Widths[colIdx])))
+			if colIdx < len(row)-1 {
 				b.WriteString("  ")
 			}
 		}
@@ -74,8 +79,13 @@ func (t *Table) Render() string {
 	return b.String()
 }
 
-// RenderStyled renders the table with custom cell styling
+// RenderStyled renders the table with custom cell styling, in a box unless
+// in accessible mode
 func (t *Table) RenderStyled(styleFunc func(rowIdx, colIdx int, cell string) lipgloss.Style) string {
+	if accessible {
+		return t.renderPlain(styleFunc)
+	}
+
 	var b strings.Builder
 
 	b.WriteString(BorderStyle.Render("┌"))
@@ -134,12 +144,14 @@ func (t *Table) RenderStyled(styleFunc func(rowIdx, colIdx int, cell string) lip
 	return b.String()
 }
 
-// padRight pads a string to the right with spaces
+// padRight pads a string to the right with spaces, measuring it in terminal
+// cells so symbols like ▲ line up
 func padRight(s string, width int) string {
-	if len(s) >= width {
+	w := lipgloss.Width(s)
+	if w >= width {
 		return s
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return s + strings.Repeat(" ", width-w)
 }
 
 // TruncateString truncates a string to a maximum width with ellipsis
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/omarshaarawi/gx/internal/benchdata"
+)
+
+func TestTable_RenderStyled(t *testing.T) {
+	table := NewTable("Package", "Update")
+	table.AddRow("golang.org/x/mod", "▲ major")
+	table.AddRow("too", "many", "cells")
+
+	got := table.RenderStyled(func(rowIdx, colIdx int, cell string) lipgloss.Style { return CellStyle })
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("RenderStyled() has %d lines, want 5 (borders, header and one row):\n%s", len(lines), got)
+	}
+	for _, line := range lines[1:] {
+		if lipgloss.Width(line) != lipgloss.Width(lines[0]) {
+			t.Errorf("RenderStyled() lines differ in width:\n%s", got)
+			break
+		}
+	}
+}
+
+func TestTable_RenderStyledAccessible(t *testing.T) {
+	SetAccessible(true)
+	defer func() {
+		SetAccessible(false)
+		SetProgressMode("auto")
+	}()
+
+	table := NewTable("Package", "Update")
+	table.AddRow("golang.org/x/mod", "major")
+
+	got := table.RenderStyled(func(rowIdx, colIdx int, cell string) lipgloss.Style { return lipgloss.NewStyle() })
+	want := "Package           Update\n" +
+		"----------------  ------\n" +
+		"golang.org/x/mod  major \n"
+	if got != want {
+		t.Errorf("RenderStyled() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// benchmarkRows returns one outdated-style row per module of a large set
+func benchmarkRows(n int) [][]string {
+	set := benchdata.New(n)
+	rows := make([][]string, 0, n)
+	for _, m := range set.Modules {
+		rows = append(rows, []string{m.Path, m.Version, set.Latest(m.Path), "● minor", "2 years ago", "1.4y"})
+	}
+	return rows
+}
+
+func BenchmarkTable_RenderStyled(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			rows := benchmarkRows(n)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				table := NewTable("Package", "Current", "Latest", "Update", "Released", "Behind")
+				for _, row := range rows {
+					table.AddRow(row...)
+				}
+				table.RenderStyled(func(rowIdx, colIdx int, cell string) lipgloss.Style { return CellStyle })
+			}
+		})
+	}
+}
+
+func BenchmarkMarkdownTable_Render(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			rows := benchmarkRows(n)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				table := NewMarkdownTable("Package", "Current", "Latest", "Update", "Released", "Behind")
+				for _, row := range rows {
+					table.AddRow(row...)
+				}
+				table.Render()
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette holds the colors that encode update types and severities
+type palette struct {
+	patch, minor, major         lipgloss.Color
+	critical, high, medium, low lipgloss.Color
+}
+
+var palettes = map[string]palette{
+	"default": {
+		patch: "10", minor: "11", major: "9",
+		critical: "9", high: "208", medium: "11", low: "10",
+	},
+	// Okabe-Ito colors, which stay distinct with red-green color blindness
+	"colorblind": {
+		patch: "#56B4E9", minor: "#F0E442", major: "#D55E00",
+		critical: "#D55E00", high: "#E69F00", medium: "#F0E442", low: "#56B4E9",
+	},
+}
+
+// Themes lists the accepted theme names
+var Themes = []string{"default", "colorblind"}
+
+// SetTheme switches the colors used for update types and severities. An
+// empty name keeps the current theme.
+func SetTheme(name string) error {
+	if name == "" {
+		return nil
+	}
+	p, ok := palettes[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(Themes, " or "))
+	}
+
+	PatchStyle = lipgloss.NewStyle().Foreground(p.patch)
+	MinorStyle = lipgloss.NewStyle().Foreground(p.minor)
+	MajorStyle = lipgloss.NewStyle().Foreground(p.major)
+
+	CriticalStyle = lipgloss.NewStyle().Foreground(p.critical).Bold(true)
+	HighStyle = lipgloss.NewStyle().Foreground(p.high)
+	MediumStyle = lipgloss.NewStyle().Foreground(p.medium)
+	LowStyle = lipgloss.NewStyle().Foreground(p.low)
+	return nil
+}
+
+// UpdateSymbol returns the shape marking an update type, so the type can be
+// told apart without color
+func UpdateSymbol(updateType string) string {
+	switch updateType {
+	case "major":
+		return "▲"
+	case "minor":
+		return "●"
+	case "patch":
+		return "·"
+	}
+	return ""
+}
+
+// SeveritySymbol returns the mark shown next to a vulnerability severity
+func SeveritySymbol(severity string) string {
+	switch severity {
+	case "CRITICAL":
+		return "!!"
+	case "HIGH":
+		return "!"
+	case "MEDIUM":
+		return "~"
+	case "LOW":
+		return "·"
+	}
+	return "?"
+}
+
+// Marks for a retracted installed version, a deprecated module and one
+// replaced by another module
+const (
+	RetractedSymbol  = "✗"
+	DeprecatedSymbol = "†"
+	ReplacedSymbol   = "⇄"
+)
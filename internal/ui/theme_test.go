@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme("default")
+
+	if err := SetTheme("colorblind"); err != nil {
+		t.Fatalf("SetTheme(colorblind) error: %v", err)
+	}
+	if got := MajorStyle.GetForeground(); got != palettes["colorblind"].major {
+		t.Errorf("MajorStyle foreground = %v, want %v", got, palettes["colorblind"].major)
+	}
+	if !CriticalStyle.GetBold() {
+		t.Error("CriticalStyle lost bold")
+	}
+
+	if err := SetTheme("Default"); err != nil {
+		t.Fatalf("SetTheme(Default) error: %v", err)
+	}
+	if got := MajorStyle.GetForeground(); got != palettes["default"].major {
+		t.Errorf("MajorStyle foreground = %v, want %v", got, palettes["default"].major)
+	}
+
+	if err := SetTheme("neon"); err == nil {
+		t.Error("SetTheme(neon) succeeded")
+	}
+	if err := SetTheme(""); err != nil {
+		t.Errorf("SetTheme(\"\") error: %v", err)
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	seen := map[string]bool{}
+	for _, typ := range []string{"major", "minor", "patch"} {
+		s := UpdateSymbol(typ)
+		if s == "" || seen[s] {
+			t.Errorf("UpdateSymbol(%s) = %q, want a distinct symbol", typ, s)
+		}
+		seen[s] = true
+	}
+	if UpdateSymbol("none") != "" {
+		t.Error("UpdateSymbol(none) should be empty")
+	}
+
+	seen = map[string]bool{}
+	for _, sev := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"} {
+		s := SeveritySymbol(sev)
+		if seen[s] {
+			t.Errorf("SeveritySymbol(%s) = %q is not distinct", sev, s)
+		}
+		seen[s] = true
+	}
+}
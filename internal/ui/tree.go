@@ -11,6 +11,7 @@ var (
 	TreeNodeStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
 	TreeVersionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
 	TreeIndirectStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	TreeMarkerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
 )
 
 // TreeNode represents a node in a tree structure
@@ -18,6 +19,7 @@ type TreeNode struct {
 	Label    string
 	Version  string
 	Indirect bool
+	Markers  []string // notes shown after the version, e.g. "outdated: v1.9.2"
 	Children []*TreeNode
 }
 
@@ -60,9 +62,9 @@ func renderNode(b *strings.Builder, node *TreeNode, prefix string, isLast bool,
 	}
 
 	if depth > 0 {
-		branch := "├── "
+		branch := border("├── ", "|-- ")
 		if isLast {
-			branch = "└── "
+			branch = border("└── ", "`-- ")
 		}
 		b.WriteString(TreeBranchStyle.Render(prefix + branch))
 	}
@@ -78,6 +80,10 @@ func renderNode(b *strings.Builder, node *TreeNode, prefix string, isLast bool,
 		b.WriteString(TreeVersionStyle.Render("@" + strings.TrimPrefix(node.Version, "v")))
 	}
 
+	for _, marker := range node.Markers {
+		b.WriteString(" " + TreeMarkerStyle.Render("["+marker+"]"))
+	}
+
 	b.WriteString("\n")
 
 	if opts.Prune {
@@ -88,10 +94,10 @@ func renderNode(b *strings.Builder, node *TreeNode, prefix string, isLast bool,
 				if isLast {
 					newPrefix += "    "
 				} else {
-					newPrefix += "│   "
+					newPrefix += border("│   ", "|   ")
 				}
 			}
-			b.WriteString(TreeBranchStyle.Render(newPrefix + "└── "))
+			b.WriteString(TreeBranchStyle.Render(newPrefix + border("└── ", "`-- ")))
 			b.WriteString(TreeIndirectStyle.Render("(already shown above)"))
 			b.WriteString("\n")
 			return
@@ -106,7 +112,7 @@ func renderNode(b *strings.Builder, node *TreeNode, prefix string, isLast bool,
 			if isLast {
 				newPrefix += "    "
 			} else {
-				newPrefix += "│   "
+				newPrefix += border("│   ", "|   ")
 			}
 		}
 		renderNode(b, child, newPrefix, childIsLast, depth+1, opts, seen)
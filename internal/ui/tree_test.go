@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestRenderTree_Markers(t *testing.T) {
+	root := &TreeNode{
+		Label: "example.com/app",
+		Children: []*TreeNode{
+			{Label: "example.com/a", Version: "v1.2.0", Markers: []string{"outdated: v1.9.2", "vuln: GO-2025-0001"}},
+			{Label: "example.com/b", Version: "v0.3.0"},
+		},
+	}
+
+	want := "example.com/app\n" +
+		"├── example.com/a@1.2.0 [outdated: v1.9.2] [vuln: GO-2025-0001]\n" +
+		"└── example.com/b@0.3.0\n"
+	if got := SimpleTree(root); got != want {
+		t.Errorf("SimpleTree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTree_Accessible(t *testing.T) {
+	SetAccessible(true)
+	defer func() {
+		SetAccessible(false)
+		SetProgressMode("auto")
+	}()
+
+	root := &TreeNode{
+		Label: "example.com/app",
+		Children: []*TreeNode{
+			{Label: "example.com/a", Version: "v1.2.0", Children: []*TreeNode{{Label: "example.com/c", Version: "v1.0.0"}}},
+			{Label: "example.com/b", Version: "v0.3.0"},
+		},
+	}
+
+	want := "example.com/app\n" +
+		"|-- example.com/a@1.2.0\n" +
+		"|   `-- example.com/c@1.0.0\n" +
+		"`-- example.com/b@0.3.0\n"
+	if got := SimpleTree(root); got != want {
+		t.Errorf("SimpleTree() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/module"
+)
+
+// Repo is the git repository holding a module
+type Repo struct {
+	// Root is the import path of the repository root, a prefix of the
+	// module path
+	Root string
+	// URL is what git clones
+	URL string
+}
+
+// knownHosts serve repositories at host/owner/name, so they need no lookup
+var knownHosts = map[string]bool{
+	"github.com":    true,
+	"bitbucket.org": true,
+}
+
+// goImportRE matches <meta name="go-import" content="prefix vcs url">
+var goImportRE = regexp.MustCompile(`(?is)<meta\s+name=["']?go-import["']?\s+content=["']([^"']+)["']`)
+
+// discover finds a module's repository: directly for well-known hosts and
+// paths with a .git element, otherwise from the go-import meta tag served at
+// https://<module>?go-get=1
+func (r *Resolver) discover(ctx context.Context, modulePath string) (Repo, error) {
+	elems := strings.Split(modulePath, "/")
+
+	if knownHosts[elems[0]] && len(elems) >= 3 {
+		root := strings.Join(elems[:3], "/")
+		return Repo{Root: root, URL: "https://" + root}, nil
+	}
+
+	for i, elem := range elems {
+		if i > 0 && strings.HasSuffix(elem, ".git") {
+			root := strings.Join(elems[:i+1], "/")
+			return Repo{Root: root, URL: "https://" + root}, nil
+		}
+	}
+
+	return r.goImport(ctx, modulePath)
+}
+
+func (r *Resolver) goImport(ctx context.Context, modulePath string) (Repo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+modulePath+"?go-get=1", nil)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return Repo{}, fmt.Errorf("finding repository for %s: %w", modulePath, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Repo{}, fmt.Errorf("finding repository for %s: %w", modulePath, err)
+	}
+
+	if repo, ok := parseGoImport(string(body), modulePath); ok {
+		return repo, nil
+	}
+	return Repo{}, fmt.Errorf("finding repository for %s: no git go-import meta tag at https://%s?go-get=1", modulePath, modulePath)
+}
+
+// parseGoImport picks the git go-import tag whose prefix covers the module
+func parseGoImport(page, modulePath string) (Repo, bool) {
+	for _, m := range goImportRE.FindAllStringSubmatch(page, -1) {
+		fields := strings.Fields(html.UnescapeString(m[1]))
+		if len(fields) != 3 || fields[1] != "git" {
+			continue
+		}
+		if prefix := fields[0]; modulePath == prefix || strings.HasPrefix(modulePath, prefix+"/") {
+			return Repo{Root: prefix, URL: fields[2]}, true
+		}
+	}
+	return Repo{}, false
+}
+
+var (
+	noProxyOnce     sync.Once
+	noProxyPatterns string
+)
+
+// NoProxyPatterns returns the module patterns the go command fetches
+// without a proxy: GONOPROXY, which defaults to GOPRIVATE. Values set with
+// go env -w are read through the go command.
+func NoProxyPatterns() string {
+	noProxyOnce.Do(func() {
+		if v, ok := os.LookupEnv("GONOPROXY"); ok {
+			noProxyPatterns = v
+			return
+		}
+		if v, ok := os.LookupEnv("GOPRIVATE"); ok {
+			noProxyPatterns = v
+			return
+		}
+		if out, err := exec.Command("go", "env", "GONOPROXY").Output(); err == nil {
+			noProxyPatterns = strings.TrimSpace(string(out))
+		}
+	})
+	return noProxyPatterns
+}
+
+// IsPrivate reports whether a module matches the no-proxy patterns
+func IsPrivate(patterns, modulePath string) bool {
+	return patterns != "" && module.MatchPrefixPatterns(patterns, modulePath)
+}
@@ -0,0 +1,213 @@
+// Package vcs resolves module versions straight from git, for private
+// modules that no proxy serves. It only lists tags and, for release dates,
+// fetches a single commit; it never clones a full repository.
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+	"github.com/omarshaarawi/gx/internal/proxy"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// gitEnv keeps git from prompting for credentials, which would hang a
+// non-interactive lookup; the user's credential helpers and SSH keys still work
+var gitEnv = []string{"GIT_TERMINAL_PROMPT=0"}
+
+// Resolver looks up module versions from the git tags of their repositories
+type Resolver struct {
+	http    *http.Client
+	repoFor func(ctx context.Context, modulePath string) (Repo, error)
+
+	mu    sync.Mutex
+	tags  map[string]map[string]bool // tag names by repository URL
+	times map[string]time.Time       // commit times by URL and tag
+}
+
+// NewResolver creates a resolver that finds repositories the way the go
+// command does
+func NewResolver() *Resolver {
+	r := &Resolver{
+		http:  &http.Client{Timeout: 30 * time.Second},
+		tags:  make(map[string]map[string]bool),
+		times: make(map[string]time.Time),
+	}
+	r.repoFor = r.discover
+	return r
+}
+
+// Versions returns the module's release and pre-release versions, oldest first
+func (r *Resolver) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	repo, err := r.repoFor(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := r.listTags(ctx, repo.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return moduleVersions(modulePath, repo.Root, tags), nil
+}
+
+// Latest returns the highest release, or the highest pre-release when the
+// module has no releases, like a proxy's @latest
+func (r *Resolver) Latest(ctx context.Context, modulePath string) (*proxy.VersionInfo, error) {
+	list, err := r.Versions(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := ""
+	for _, v := range list {
+		if semver.Prerelease(v) == "" || latest == "" || semver.Prerelease(latest) != "" {
+			latest = v
+		}
+	}
+	if latest == "" {
+		return nil, fmt.Errorf("%s: no version tags found", modulePath)
+	}
+
+	return r.Info(ctx, modulePath, latest)
+}
+
+// Info returns a version's commit time. The time is left zero when the
+// commit can't be fetched, since the version itself is known.
+func (r *Resolver) Info(ctx context.Context, modulePath, version string) (*proxy.VersionInfo, error) {
+	repo, err := r.repoFor(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &proxy.VersionInfo{Version: version}
+	if t, err := r.tagTime(ctx, repo.URL, tagPrefix(modulePath, repo.Root)+version); err == nil {
+		info.Time = t
+	}
+	return info, nil
+}
+
+// listTags runs git ls-remote once per repository
+func (r *Resolver) listTags(ctx context.Context, url string) (map[string]bool, error) {
+	r.mu.Lock()
+	cached, ok := r.tags[url]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	out, err := gitcmd.OutputEnv(ctx, "", gitEnv, "ls-remote", "--tags", "--refs", url)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", url, err)
+	}
+
+	tags := parseTags(out)
+	r.mu.Lock()
+	r.tags[url] = tags
+	r.mu.Unlock()
+	return tags, nil
+}
+
+// tagTime fetches just the tagged commit into a scratch repository and
+// reads its commit time
+func (r *Resolver) tagTime(ctx context.Context, url, tag string) (time.Time, error) {
+	key := url + "@" + tag
+	r.mu.Lock()
+	cached, ok := r.times[key]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	dir, err := os.MkdirTemp("", "gx-vcs-")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	ref := "refs/tags/" + tag
+	if _, err := gitcmd.OutputEnv(ctx, dir, gitEnv, "init", "-q", "--bare"); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := gitcmd.OutputEnv(ctx, dir, gitEnv, "fetch", "-q", "--depth=1", "--no-tags", url, ref+":"+ref); err != nil {
+		return time.Time{}, err
+	}
+	out, err := gitcmd.OutputEnv(ctx, dir, gitEnv, "log", "-1", "--format=%cI", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, out)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing commit time %q: %w", out, err)
+	}
+	t = t.UTC()
+
+	r.mu.Lock()
+	r.times[key] = t
+	r.mu.Unlock()
+	return t, nil
+}
+
+// parseTags reads tag names from git ls-remote output
+func parseTags(out string) map[string]bool {
+	tags := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if tag, ok := strings.CutPrefix(strings.TrimSpace(ref), "refs/tags/"); ok {
+			tags[tag] = true
+		}
+	}
+	return tags
+}
+
+// tagPrefix is the prefix of a module's tags: its directory within the
+// repository, without a major version suffix
+func tagPrefix(modulePath, root string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	dir := strings.TrimPrefix(strings.TrimPrefix(prefix, root), "/")
+	if dir == "" {
+		return ""
+	}
+	return dir + "/"
+}
+
+// moduleVersions picks the tags that are versions of the module, sorted
+// oldest first. Versions must be canonical semver with a major version that
+// matches the module path.
+func moduleVersions(modulePath, root string, tags map[string]bool) []string {
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	prefix := tagPrefix(modulePath, root)
+
+	var list []string
+	for tag := range tags {
+		v, ok := strings.CutPrefix(tag, prefix)
+		if !ok || !semver.IsValid(v) || semver.Canonical(v) != v {
+			continue
+		}
+		if module.CheckPathMajor(v, pathMajor) != nil {
+			continue
+		}
+		list = append(list, v)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return semver.Compare(list[i], list[j]) < 0
+	})
+	return list
+}
@@ -0,0 +1,165 @@
+package vcs
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/gitcmd"
+)
+
+func TestParseTags(t *testing.T) {
+	out := "1111\trefs/tags/v1.0.0\n2222\trefs/tags/sub/v0.2.0\n3333\trefs/heads/main\n"
+	got := parseTags(out)
+	want := map[string]bool{"v1.0.0": true, "sub/v0.2.0": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTags() = %v, want %v", got, want)
+	}
+}
+
+func TestModuleVersions(t *testing.T) {
+	tags := map[string]bool{
+		"v0.9.0":          true,
+		"v1.0.0":          true,
+		"v1.1.0-rc.1":     true,
+		"v1.2":            true, // not canonical
+		"v2.0.0":          true,
+		"v2.1.0":          true,
+		"tools/v0.1.0":    true,
+		"tools/v0.2.0":    true,
+		"release-2024":    true,
+		"tools/v2.0.0":    true,
+		"other/v1.0.0":    true,
+		"v3.0.0+incompat": true,
+	}
+
+	tests := []struct {
+		module string
+		want   []string
+	}{
+		{"corp.dev/lib", []string{"v0.9.0", "v1.0.0", "v1.1.0-rc.1"}},
+		{"corp.dev/lib/v2", []string{"v2.0.0", "v2.1.0"}},
+		{"corp.dev/lib/tools", []string{"v0.1.0", "v0.2.0"}},
+		{"corp.dev/lib/tools/v2", []string{"v2.0.0"}},
+		{"corp.dev/lib/missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			if got := moduleVersions(tt.module, "corp.dev/lib", tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("moduleVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoImport(t *testing.T) {
+	page := `<html><head>
+<meta name="go-import" content="corp.dev/mono hg https://hg.corp.dev/mono">
+<meta name="go-import" content="corp.dev/lib git ssh://git@git.corp.dev/team/lib.git">
+</head></html>`
+
+	repo, ok := parseGoImport(page, "corp.dev/lib/v2")
+	if !ok {
+		t.Fatal("parseGoImport() found no repository")
+	}
+	if repo.Root != "corp.dev/lib" || repo.URL != "ssh://git@git.corp.dev/team/lib.git" {
+		t.Errorf("parseGoImport() = %+v", repo)
+	}
+
+	if _, ok := parseGoImport(page, "corp.dev/mono/x"); ok {
+		t.Error("parseGoImport() accepted a non-git repository")
+	}
+	if _, ok := parseGoImport(page, "corp.dev/library"); ok {
+		t.Error("parseGoImport() matched a partial path element")
+	}
+}
+
+func TestDiscover_WithoutLookup(t *testing.T) {
+	r := NewResolver()
+	tests := []struct {
+		module string
+		want   Repo
+	}{
+		{"github.com/corp/lib/v3", Repo{Root: "github.com/corp/lib", URL: "https://github.com/corp/lib"}},
+		{"git.corp.dev/team/lib.git/sub", Repo{Root: "git.corp.dev/team/lib.git", URL: "https://git.corp.dev/team/lib.git"}},
+	}
+	for _, tt := range tests {
+		got, err := r.discover(context.Background(), tt.module)
+		if err != nil {
+			t.Fatalf("discover(%s) error: %v", tt.module, err)
+		}
+		if got != tt.want {
+			t.Errorf("discover(%s) = %+v, want %+v", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestIsPrivate(t *testing.T) {
+	patterns := "corp.dev,*.internal.example.com/team"
+	tests := map[string]bool{
+		"corp.dev/lib":                        true,
+		"git.internal.example.com/team/tools": true,
+		"git.internal.example.com/other":      false,
+		"github.com/spf13/cobra":              false,
+	}
+	for path, want := range tests {
+		if got := IsPrivate(patterns, path); got != want {
+			t.Errorf("IsPrivate(%s) = %v, want %v", path, got, want)
+		}
+	}
+	if IsPrivate("", "corp.dev/lib") {
+		t.Error("IsPrivate() with no patterns = true")
+	}
+}
+
+func TestResolver_LocalRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		env := []string{
+			"GIT_AUTHOR_NAME=gx", "GIT_AUTHOR_EMAIL=gx@example.com",
+			"GIT_COMMITTER_NAME=gx", "GIT_COMMITTER_EMAIL=gx@example.com",
+			"GIT_COMMITTER_DATE=2024-03-01T12:00:00Z",
+		}
+		if _, err := gitcmd.OutputEnv(ctx, dir, env, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "first")
+	git("tag", "v1.0.0")
+	git("tag", "v1.1.0-beta.1")
+	git("tag", "-a", "v1.0.1", "-m", "patch")
+
+	r := NewResolver()
+	r.repoFor = func(context.Context, string) (Repo, error) {
+		return Repo{Root: "corp.dev/lib", URL: "file://" + dir}, nil
+	}
+
+	latest, err := r.Latest(ctx, "corp.dev/lib")
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if latest.Version != "v1.0.1" {
+		t.Errorf("Latest() = %s, want v1.0.1", latest.Version)
+	}
+	if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !latest.Time.Equal(want) {
+		t.Errorf("Latest() time = %v, want %v", latest.Time, want)
+	}
+
+	versions, err := r.Versions(ctx, "corp.dev/lib")
+	if err != nil {
+		t.Fatalf("Versions() error: %v", err)
+	}
+	if want := []string{"v1.0.0", "v1.0.1", "v1.1.0-beta.1"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions() = %v, want %v", versions, want)
+	}
+}
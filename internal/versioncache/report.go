@@ -0,0 +1,75 @@
+package versioncache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+)
+
+// reportDir holds whole cached reports, one file each, next to the cache file
+const reportDir = "reports"
+
+// savedReport is the file format of a cached report
+type savedReport struct {
+	Saved  time.Time       `json:"saved"`
+	Report json.RawMessage `json:"report"`
+}
+
+// Report decodes the report cached under key into v and returns when it was
+// saved. Callers decide how old a report may be.
+func (s *Store) Report(key string, v any) (time.Time, bool) {
+	data, err := os.ReadFile(s.reportPath(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var saved savedReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return time.Time{}, false
+	}
+	if err := json.Unmarshal(saved.Report, v); err != nil {
+		return time.Time{}, false
+	}
+	return saved.Saved, true
+}
+
+// SetReport caches a report under key, replacing any earlier one, and drops
+// reports older than a month
+func (s *Store) SetReport(key string, v any) error {
+	report, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(savedReport{Saved: s.now(), Report: report})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(filepath.Dir(s.path), reportDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating report cache directory: %w", err)
+	}
+	if err := fsutil.WriteFile(s.reportPath(key), data, 0o644); err != nil {
+		return fmt.Errorf("writing report cache: %w", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && s.now().Sub(info.ModTime()) > maxEntryAge {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+	return nil
+}
+
+// reportPath names a report's file after a hash of its key, which may be long
+func (s *Store) reportPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(filepath.Dir(s.path), reportDir, hex.EncodeToString(sum[:16])+".json")
+}
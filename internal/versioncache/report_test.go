@@ -0,0 +1,38 @@
+package versioncache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStore_Report(t *testing.T) {
+	t.Setenv("GX_CACHE_DIR", t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type report struct {
+		Modules []string
+	}
+
+	s := openStore(t, &now)
+	if err := s.SetReport("go.mod abc", report{Modules: []string{"example.com/a"}}); err != nil {
+		t.Fatalf("SetReport() error: %v", err)
+	}
+
+	s = openStore(t, &now)
+	var got report
+	saved, ok := s.Report("go.mod abc", &got)
+	if !ok || !saved.Equal(now) || len(got.Modules) != 1 || got.Modules[0] != "example.com/a" {
+		t.Fatalf("Report() = %+v, %v, %v", got, saved, ok)
+	}
+	if _, ok := s.Report("go.mod def", &got); ok {
+		t.Error("Report() returned a report for another key")
+	}
+
+	if err := os.WriteFile(s.reportPath("go.mod bad"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Report("go.mod bad", &got); ok {
+		t.Error("Report() accepted a corrupt report")
+	}
+}
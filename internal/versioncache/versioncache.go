@@ -0,0 +1,195 @@
+// Package versioncache keeps the results of version lookups on disk between
+// runs, so gx outdated can skip the proxy when it ran recently.
+package versioncache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/omarshaarawi/gx/internal/fsutil"
+)
+
+// fileName is the cache file inside the cache directory
+const fileName = "versions.json"
+
+// maxEntryAge is how long an entry is kept before Save drops it
+const maxEntryAge = 30 * 24 * time.Hour
+
+// Dir returns the directory holding the cache file
+var Dir = func() (string, error) {
+	if dir := os.Getenv("GX_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "gx"), nil
+}
+
+// Entry is a cached version lookup
+type Entry struct {
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`    // release time of the version
+	Checked time.Time `json:"checked"` // when the proxy was asked
+	GoMod   string    `json:"go_mod,omitempty"`
+
+	// Versions are the published versions of a module, oldest first
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Store holds the cached lookups. It is safe for concurrent use.
+type Store struct {
+	path string
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	dirty   bool
+}
+
+// Open loads the cache. A missing or unreadable cache file gives an empty store.
+func Open() (*Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, fmt.Errorf("locating cache directory: %w", err)
+	}
+
+	s := &Store{
+		path:    filepath.Join(dir, fileName),
+		now:     time.Now,
+		entries: make(map[string]Entry),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	// A corrupt cache is rebuilt rather than reported
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		s.entries = make(map[string]Entry)
+	}
+	return s, nil
+}
+
+// Latest returns the cached latest version of a module when it was looked up
+// no longer than maxAge ago
+func (s *Store) Latest(modulePath string, maxAge time.Duration) (Entry, bool) {
+	return s.fresh(modulePath+"@latest", maxAge)
+}
+
+// SetLatest records a module's latest version
+func (s *Store) SetLatest(modulePath, version string, released time.Time) {
+	s.set(modulePath+"@latest", Entry{Version: version, Time: released})
+}
+
+// LatestPre is Latest for lookups that include pre-releases, which are
+// cached separately
+func (s *Store) LatestPre(modulePath string, maxAge time.Duration) (Entry, bool) {
+	return s.fresh(modulePath+"@latest-pre", maxAge)
+}
+
+// SetLatestPre records a module's newest version, pre-releases included
+func (s *Store) SetLatestPre(modulePath, version string, released time.Time) {
+	s.set(modulePath+"@latest-pre", Entry{Version: version, Time: released})
+}
+
+// Versions returns a module's cached version list when it was fetched no
+// longer than maxAge ago
+func (s *Store) Versions(modulePath string, maxAge time.Duration) ([]string, bool) {
+	e, ok := s.fresh(modulePath+"@list", maxAge)
+	return e.Versions, ok
+}
+
+// SetVersions records the versions published for a module
+func (s *Store) SetVersions(modulePath string, list []string) {
+	s.set(modulePath+"@list", Entry{Versions: list})
+}
+
+func (s *Store) fresh(key string, maxAge time.Duration) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || maxAge <= 0 || s.now().Sub(e.Checked) > maxAge {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// Info returns the cached release time of a module version. Published
+// versions don't change, so these entries don't go stale.
+func (s *Store) Info(modulePath, version string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[modulePath+"@"+version]
+	return e, ok
+}
+
+// SetInfo records the release time of a module version
+func (s *Store) SetInfo(modulePath, version string, released time.Time) {
+	s.set(modulePath+"@"+version, Entry{Version: version, Time: released})
+}
+
+// ModFile returns the cached go.mod of a module version. Like release times,
+// published go.mod files don't change.
+func (s *Store) ModFile(modulePath, version string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[modulePath+"@"+version+".mod"]
+	return []byte(e.GoMod), ok
+}
+
+// SetModFile records the go.mod of a module version
+func (s *Store) SetModFile(modulePath, version string, data []byte) {
+	s.set(modulePath+"@"+version+".mod", Entry{Version: version, GoMod: string(data)})
+}
+
+func (s *Store) set(key string, e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.Checked = s.now()
+	s.entries[key] = e
+	s.dirty = true
+}
+
+// Save writes the cache back if anything changed, dropping entries older
+// than a month
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	for key, e := range s.entries {
+		if s.now().Sub(e.Checked) > maxEntryAge {
+			delete(s.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := fsutil.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", s.path, err)
+	}
+
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,150 @@
+package versioncache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	s, err := Open()
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestStore_Latest(t *testing.T) {
+	t.Setenv("GX_CACHE_DIR", t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	released := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	s := openStore(t, &now)
+	s.SetLatest("example.com/a", "v1.2.0", released)
+	s.SetInfo("example.com/a", "v1.0.0", released.AddDate(-1, 0, 0))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	s = openStore(t, &now)
+
+	e, ok := s.Latest("example.com/a", 3*time.Hour)
+	if !ok || e.Version != "v1.2.0" || !e.Time.Equal(released) {
+		t.Fatalf("Latest() = %+v, %v", e, ok)
+	}
+	if want := now.Add(-2 * time.Hour); !e.Checked.Equal(want) {
+		t.Errorf("Checked = %v, want %v", e.Checked, want)
+	}
+
+	if _, ok := s.Latest("example.com/a", time.Hour); ok {
+		t.Error("Latest() returned an entry older than maxAge")
+	}
+	if _, ok := s.Latest("example.com/a", 0); ok {
+		t.Error("Latest() with no max age returned an entry")
+	}
+	if _, ok := s.Latest("example.com/b", 3*time.Hour); ok {
+		t.Error("Latest() returned an entry for an unknown module")
+	}
+	if _, ok := s.LatestPre("example.com/a", 3*time.Hour); ok {
+		t.Error("LatestPre() returned the Latest entry")
+	}
+	s.SetLatestPre("example.com/a", "v1.3.0-rc.1", released)
+	if e, ok := s.LatestPre("example.com/a", 3*time.Hour); !ok || e.Version != "v1.3.0-rc.1" {
+		t.Errorf("LatestPre() = %+v, %v", e, ok)
+	}
+	if e, _ := s.Latest("example.com/a", 3*time.Hour); e.Version != "v1.2.0" {
+		t.Errorf("Latest() after SetLatestPre = %s, want v1.2.0", e.Version)
+	}
+
+	if e, ok := s.Info("example.com/a", "v1.0.0"); !ok || e.Time.Year() != 2024 {
+		t.Errorf("Info() = %+v, %v", e, ok)
+	}
+}
+
+func TestStore_ModFile(t *testing.T) {
+	t.Setenv("GX_CACHE_DIR", t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	goMod := "// Deprecated: use example.com/b\nmodule example.com/a\n"
+
+	s := openStore(t, &now)
+	s.SetModFile("example.com/a", "v1.2.0", []byte(goMod))
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	s = openStore(t, &now)
+	if data, ok := s.ModFile("example.com/a", "v1.2.0"); !ok || string(data) != goMod {
+		t.Errorf("ModFile() = %q, %v", data, ok)
+	}
+	if _, ok := s.ModFile("example.com/a", "v1.1.0"); ok {
+		t.Error("ModFile() returned an entry for an uncached version")
+	}
+	if _, ok := s.Info("example.com/a", "v1.2.0"); ok {
+		t.Error("Info() returned the go.mod entry")
+	}
+}
+
+func TestStore_Versions(t *testing.T) {
+	t.Setenv("GX_CACHE_DIR", t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := openStore(t, &now)
+	s.SetVersions("example.com/a", []string{"v1.0.0", "v1.1.0", "v1.2.0-rc.1"})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	s = openStore(t, &now)
+	list, ok := s.Versions("example.com/a", 3*time.Hour)
+	if !ok || len(list) != 3 || list[2] != "v1.2.0-rc.1" {
+		t.Fatalf("Versions() = %v, %v", list, ok)
+	}
+	if _, ok := s.Versions("example.com/a", time.Hour); ok {
+		t.Error("Versions() returned a list older than maxAge")
+	}
+	if _, ok := s.Latest("example.com/a", 3*time.Hour); ok {
+		t.Error("Latest() returned the version list entry")
+	}
+}
+
+func TestStore_SaveDropsOldEntries(t *testing.T) {
+	t.Setenv("GX_CACHE_DIR", t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := openStore(t, &now)
+	s.SetInfo("example.com/old", "v1.0.0", now)
+	now = now.Add(maxEntryAge + time.Hour)
+	s.SetInfo("example.com/new", "v1.0.0", now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	s = openStore(t, &now)
+	if _, ok := s.Info("example.com/old", "v1.0.0"); ok {
+		t.Error("Save() kept an expired entry")
+	}
+	if _, ok := s.Info("example.com/new", "v1.0.0"); !ok {
+		t.Error("Save() dropped a fresh entry")
+	}
+}
+
+func TestOpen_CorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GX_CACHE_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open()
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if len(s.entries) != 0 {
+		t.Errorf("entries = %v, want empty", s.entries)
+	}
+}
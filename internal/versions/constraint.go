@@ -0,0 +1,175 @@
+package versions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Constraint limits which versions of a module are acceptable, such as
+// "<0.20.0" or ">=1.4, <2". Bounds are separated by commas or spaces and must
+// all hold. "~1.4.2" allows patches of 1.4 and "^1.4.2" anything below 2.0.0
+// (below 0.5.0 for "^0.4.2"). The "v" prefix and trailing zeros are optional.
+type Constraint struct {
+	raw    string
+	bounds []bound
+}
+
+type bound struct {
+	op      string // <, <=, >, >=, = or !=
+	version string // canonical semantic version
+}
+
+// Update targets for gx update --target: how far past the required version
+// an update may go
+const (
+	TargetPatch  = "patch"
+	TargetMinor  = "minor"
+	TargetLatest = "latest"
+)
+
+// TargetConstraint returns the constraint an update target puts on a module
+// required at current: patch allows patches of current's minor version, minor
+// anything below the next major version. latest puts none.
+func TargetConstraint(target, current string) (Constraint, bool) {
+	v := strings.TrimPrefix(current, "v")
+	switch target {
+	case TargetPatch:
+		return Constraint{raw: "~" + v, bounds: []bound{{">=", current}, {"<", nextMinor(current)}}}, true
+	case TargetMinor:
+		upper := nextMajor(current)
+		return Constraint{raw: ">=" + v + ", <" + strings.TrimPrefix(upper, "v"), bounds: []bound{{">=", current}, {"<", upper}}}, true
+	}
+	return Constraint{}, false
+}
+
+// And returns a constraint allowing the versions both constraints allow
+func (c Constraint) And(other Constraint) Constraint {
+	return Constraint{raw: c.raw + ", " + other.raw, bounds: append(slices.Clone(c.bounds), other.bounds...)}
+}
+
+// constraintOps are the comparison operators, longest first so "<=" isn't
+// read as "<"
+var constraintOps = []string{"<=", ">=", "!=", "<", ">", "=", "~", "^"}
+
+// ParseConstraint parses a constraint expression
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	// Allow a space between an operator and its version, as in "< 0.20"
+	for i := 0; i < len(fields); i++ {
+		if isOp(fields[i]) && i+1 < len(fields) {
+			fields[i+1] = fields[i] + fields[i+1]
+			continue
+		}
+		b, err := parseBound(fields[i])
+		if err != nil {
+			return Constraint{}, err
+		}
+		c.bounds = append(c.bounds, b...)
+	}
+
+	if len(c.bounds) == 0 {
+		return Constraint{}, fmt.Errorf("empty version constraint")
+	}
+	return c, nil
+}
+
+func isOp(s string) bool {
+	for _, op := range constraintOps {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBound reads one operator and version. ~ and ^ expand to a range.
+func parseBound(s string) ([]bound, error) {
+	op := "="
+	for _, candidate := range constraintOps {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	v := strings.TrimPrefix(s, op)
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return nil, fmt.Errorf("invalid version %q in constraint %q", strings.TrimPrefix(s, op), s)
+	}
+	v = semver.Canonical(v)
+
+	switch op {
+	case "~":
+		return []bound{{">=", v}, {"<", nextMinor(v)}}, nil
+	case "^":
+		if semver.Major(v) == "v0" {
+			return []bound{{">=", v}, {"<", nextMinor(v)}}, nil
+		}
+		return []bound{{">=", v}, {"<", nextMajor(v)}}, nil
+	}
+	return []bound{{op, v}}, nil
+}
+
+// Allows reports whether a version satisfies every bound
+func (c Constraint) Allows(version string) bool {
+	for _, b := range c.bounds {
+		cmp := semver.Compare(version, b.version)
+		ok := false
+		switch b.op {
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the constraint as written
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// UnmarshalText parses a constraint from the config
+func (c *Constraint) UnmarshalText(text []byte) error {
+	parsed, err := ParseConstraint(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+func nextMinor(v string) string {
+	major, minor := majorMinor(v)
+	return fmt.Sprintf("v%d.%d.0", major, minor+1)
+}
+
+func nextMajor(v string) string {
+	major, _ := majorMinor(v)
+	return fmt.Sprintf("v%d.0.0", major+1)
+}
+
+func majorMinor(v string) (int, int) {
+	var major, minor int
+	fmt.Sscanf(semver.MajorMinor(v), "v%d.%d", &major, &minor)
+	return major, minor
+}
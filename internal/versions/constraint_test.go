@@ -0,0 +1,94 @@
+package versions
+
+import "testing"
+
+func TestConstraint_Allows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"<0.20.0", "v0.19.9", true},
+		{"<0.20.0", "v0.20.0", false},
+		{"< 0.20", "v0.19.0", true},
+		{">=1.4, <2", "v1.9.0", true},
+		{">=1.4, <2", "v2.0.0", false},
+		{">=1.4 <2", "v1.3.0", false},
+		{"~1.4.2", "v1.4.9", true},
+		{"~1.4.2", "v1.5.0", false},
+		{"^1.4.2", "v1.9.0", true},
+		{"^1.4.2", "v2.0.0", false},
+		{"^0.4.2", "v0.4.5", true},
+		{"^0.4.2", "v0.5.0", false},
+		{"!=v1.2.3", "v1.2.3", false},
+		{"1.2.3", "v1.2.3", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error: %v", tt.constraint, err)
+			}
+			if got := c.Allows(tt.version); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, s := range []string{"", "<", "<abc", ">=1.2, <x"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestTargetConstraint(t *testing.T) {
+	tests := []struct {
+		target  string
+		current string
+		version string
+		want    bool
+	}{
+		{TargetPatch, "v1.4.2", "v1.4.9", true},
+		{TargetPatch, "v1.4.2", "v1.5.0", false},
+		{TargetPatch, "v0.4.2", "v0.4.3", true},
+		{TargetMinor, "v1.4.2", "v1.9.0", true},
+		{TargetMinor, "v1.4.2", "v2.0.0", false},
+		{TargetMinor, "v0.4.2", "v0.9.0", true},
+		{TargetMinor, "v0.4.2", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.current+" "+tt.version, func(t *testing.T) {
+			c, ok := TargetConstraint(tt.target, tt.current)
+			if !ok {
+				t.Fatalf("TargetConstraint(%q, %q) has no constraint", tt.target, tt.current)
+			}
+			if got := c.Allows(tt.version); got != tt.want {
+				t.Errorf("%s: Allows(%q) = %v, want %v", c, tt.version, got, tt.want)
+			}
+		})
+	}
+
+	if c, ok := TargetConstraint(TargetLatest, "v1.4.2"); ok {
+		t.Errorf("TargetConstraint(latest) = %s, want none", c)
+	}
+}
+
+func TestConstraint_And(t *testing.T) {
+	configured, _ := ParseConstraint("<1.8")
+	target, _ := TargetConstraint(TargetMinor, "v1.4.2")
+	c := configured.And(target)
+
+	if c.String() != "<1.8, >=1.4.2, <2.0.0" {
+		t.Errorf("String() = %q", c.String())
+	}
+	for version, want := range map[string]bool{"v1.7.0": true, "v1.8.0": false, "v1.4.0": false} {
+		if got := c.Allows(version); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package versions
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToolchainModule is the module the proxy serves Go toolchain releases under
+const ToolchainModule = "golang.org/toolchain"
+
+// goVersionRE matches Go versions: 1.21, 1.21rc1, 1.21.0
+var goVersionRE = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+)|(alpha|beta|rc)(\d+))?$`)
+
+// preReleaseRank orders language versions (1.21) before pre-releases, and
+// pre-releases before releases (1.21.0)
+var preReleaseRank = map[string]int{"": 0, "alpha": 1, "beta": 2, "rc": 3}
+
+// IsGoVersion reports whether v is a valid Go version such as 1.22.3 or 1.23rc1
+func IsGoVersion(v string) bool {
+	return goVersionRE.MatchString(v)
+}
+
+// IsGoRelease reports whether v is a Go release (1.22.3), not a language
+// version or pre-release
+func IsGoRelease(v string) bool {
+	m := goVersionRE.FindStringSubmatch(v)
+	return m != nil && m[3] != ""
+}
+
+// CompareGo compares two Go versions, with or without the "go" prefix, the
+// way the go command does: 1.21 < 1.21rc1 < 1.21.0 < 1.21.1. Invalid versions
+// sort before valid ones.
+func CompareGo(a, b string) int {
+	ka, okA := goVersionKey(strings.TrimPrefix(a, "go"))
+	kb, okB := goVersionKey(strings.TrimPrefix(b, "go"))
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range ka {
+		if ka[i] != kb[i] {
+			if ka[i] < kb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// goVersionKey returns major, minor, kind rank and patch or pre-release number
+func goVersionKey(v string) ([4]int, bool) {
+	m := goVersionRE.FindStringSubmatch(v)
+	if m == nil {
+		return [4]int{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	switch {
+	case m[3] != "":
+		patch, _ := strconv.Atoi(m[3])
+		return [4]int{major, minor, len(preReleaseRank), patch}, true
+	case m[4] != "":
+		n, _ := strconv.Atoi(m[5])
+		return [4]int{major, minor, preReleaseRank[m[4]], n}, true
+	}
+	return [4]int{major, minor, 0, 0}, true
+}
+
+// toolchainVersionRE matches golang.org/toolchain module versions,
+// v0.0.1-go1.22.0.linux-amd64
+var toolchainVersionRE = regexp.MustCompile(`^v0\.0\.1-go(\d[^.]*\.\d+(?:\.\d+)?(?:(?:alpha|beta|rc)\d+)?)\.`)
+
+// GoReleases extracts the Go versions from golang.org/toolchain module
+// versions, newest first, without duplicates
+func GoReleases(toolchainVersions []string) []string {
+	seen := make(map[string]bool)
+	var releases []string
+	for _, v := range toolchainVersions {
+		m := toolchainVersionRE.FindStringSubmatch(v)
+		if m == nil || !IsGoVersion(m[1]) || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		releases = append(releases, m[1])
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return CompareGo(releases[i], releases[j]) > 0
+	})
+	return releases
+}
+
+// LatestGo returns the newest Go release in a list from GoReleases, skipping
+// pre-releases unless pre is set
+func LatestGo(releases []string, pre bool) string {
+	for _, v := range releases {
+		if pre || IsGoRelease(v) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package versions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareGo(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "1.21rc1", -1},
+		{"1.21rc1", "1.21rc2", -1},
+		{"1.21beta1", "1.21rc1", -1},
+		{"1.21rc2", "1.21.0", -1},
+		{"1.21.0", "1.21.1", -1},
+		{"1.21.10", "1.21.9", 1},
+		{"1.9.0", "1.21.0", -1},
+		{"go1.22.0", "1.22.0", 0},
+		{"1.22", "1.22", 0},
+		{"bogus", "1.22", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareGo(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareGo(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoReleases(t *testing.T) {
+	list := []string{
+		"v0.0.1-go1.21.0.linux-amd64",
+		"v0.0.1-go1.21.0.darwin-arm64",
+		"v0.0.1-go1.22rc1.linux-amd64",
+		"v0.0.1-go1.21.10.linux-amd64",
+		"v0.0.1-go1.9.2.linux-amd64",
+		"v1.0.0",
+	}
+
+	releases := GoReleases(list)
+	want := []string{"1.22rc1", "1.21.10", "1.21.0", "1.9.2"}
+	if !slices.Equal(releases, want) {
+		t.Errorf("GoReleases() = %v, want %v", releases, want)
+	}
+
+	if got := LatestGo(releases, false); got != "1.21.10" {
+		t.Errorf("LatestGo(pre=false) = %q, want 1.21.10", got)
+	}
+	if got := LatestGo(releases, true); got != "1.22rc1" {
+		t.Errorf("LatestGo(pre=true) = %q, want 1.22rc1", got)
+	}
+}
@@ -0,0 +1,62 @@
+package versions
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/mod/semver"
+)
+
+// kubernetesStaging lists the modules published from the staging directory
+// of the Kubernetes repository. They are released together, as v0.X.Y for
+// Kubernetes 1.X.Y.
+var kubernetesStaging = []string{
+	"k8s.io/api",
+	"k8s.io/apiextensions-apiserver",
+	"k8s.io/apimachinery",
+	"k8s.io/apiserver",
+	"k8s.io/cli-runtime",
+	"k8s.io/client-go",
+	"k8s.io/cloud-provider",
+	"k8s.io/cluster-bootstrap",
+	"k8s.io/code-generator",
+	"k8s.io/component-base",
+	"k8s.io/component-helpers",
+	"k8s.io/controller-manager",
+	"k8s.io/cri-api",
+	"k8s.io/cri-client",
+	"k8s.io/csi-translation-lib",
+	"k8s.io/dynamic-resource-allocation",
+	"k8s.io/endpointslice",
+	"k8s.io/externaljwt",
+	"k8s.io/kms",
+	"k8s.io/kube-aggregator",
+	"k8s.io/kube-controller-manager",
+	"k8s.io/kube-proxy",
+	"k8s.io/kube-scheduler",
+	"k8s.io/kubectl",
+	"k8s.io/kubelet",
+	"k8s.io/legacy-cloud-providers",
+	"k8s.io/metrics",
+	"k8s.io/mount-utils",
+	"k8s.io/pod-security-admission",
+	"k8s.io/sample-apiserver",
+}
+
+// IsKubernetesStaging reports whether a module is one of the Kubernetes
+// staging modules, such as k8s.io/client-go
+func IsKubernetesStaging(modulePath string) bool {
+	_, found := slices.BinarySearch(kubernetesStaging, modulePath)
+	return found
+}
+
+// KubernetesRelease returns the Kubernetes minor release a staging module
+// version belongs to, e.g. "1.29" for v0.29.3. Versions outside v0 don't
+// follow the mapping.
+func KubernetesRelease(version string) (string, bool) {
+	if !semver.IsValid(version) || semver.Major(version) != "v0" {
+		return "", false
+	}
+	_, minor := majorMinor(version)
+	return fmt.Sprintf("1.%d", minor), true
+}
@@ -0,0 +1,46 @@
+package versions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsKubernetesStaging(t *testing.T) {
+	if !slices.IsSorted(kubernetesStaging) {
+		t.Fatal("kubernetesStaging must stay sorted")
+	}
+
+	for path, want := range map[string]bool{
+		"k8s.io/client-go":     true,
+		"k8s.io/apimachinery":  true,
+		"k8s.io/klog/v2":       false,
+		"k8s.io/utils":         false,
+		"sigs.k8s.io/yaml":     false,
+		"k8s.io/client-go/foo": false,
+	} {
+		if got := IsKubernetesStaging(path); got != want {
+			t.Errorf("IsKubernetesStaging(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestKubernetesRelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		ok      bool
+	}{
+		{"v0.29.3", "1.29", true},
+		{"v0.31.0-alpha.1", "1.31", true},
+		{"v1.5.2", "", false},
+		{"v12.0.0+incompatible", "", false},
+		{"latest", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := KubernetesRelease(tt.version)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("KubernetesRelease(%q) = %q, %v, want %q, %v", tt.version, got, ok, tt.want, tt.ok)
+		}
+	}
+}
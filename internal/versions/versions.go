@@ -0,0 +1,38 @@
+package versions
+
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Update types returned by Classify
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+	None  = "none"
+)
+
+// Classify determines the type of update (major, minor, patch, none)
+func Classify(current, latest string) string {
+	if semver.Compare(current, latest) >= 0 {
+		return None
+	}
+
+	currentMajor := semver.Major(current)
+	latestMajor := semver.Major(latest)
+
+	if currentMajor != latestMajor {
+		return Major
+	}
+
+	currentParts := strings.Split(strings.TrimPrefix(current, currentMajor+"."), ".")
+	latestParts := strings.Split(strings.TrimPrefix(latest, latestMajor+"."), ".")
+
+	if len(currentParts) > 0 && len(latestParts) > 0 && currentParts[0] != latestParts[0] {
+		return Minor
+	}
+
+	return Patch
+}
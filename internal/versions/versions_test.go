@@ -0,0 +1,27 @@
+package versions
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    string
+	}{
+		{"v1.0.0", "v1.0.0", None},
+		{"v1.2.0", "v1.1.0", None},
+		{"v1.0.0", "v2.0.0", Major},
+		{"v1.0.0", "v1.1.0", Minor},
+		{"v1.1.0", "v1.1.5", Patch},
+		{"v0.3.0", "v0.4.0", Minor},
+		{"v1.0.0-rc.1", "v1.0.0", Patch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.latest, func(t *testing.T) {
+			if got := Classify(tt.current, tt.latest); got != tt.want {
+				t.Errorf("Classify(%q, %q) = %q, want %q", tt.current, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
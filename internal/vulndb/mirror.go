@@ -0,0 +1,260 @@
+package vulndb
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mirror describes a copy of the vulnerability database on disk
+type Mirror struct {
+	Dir      string
+	Vulns    int
+	Modified time.Time // when the database was last modified
+}
+
+// errNoArchive is returned when a database doesn't serve a usable vulndb.zip
+var errNoArchive = errors.New("no vulndb.zip")
+
+// mirrorConcurrency limits the entries fetched at once when a database has
+// to be copied entry by entry
+const mirrorConcurrency = 10
+
+// MirrorDatabase copies the vulnerability database at source, an http(s)
+// URL, into dir, which govulncheck can then read with -db file://<dir> or
+// any web server can serve. vuln.go.dev's vulndb.zip is used when the
+// source has one, otherwise the index and every entry are fetched. dir is
+// replaced only once the copy is complete.
+func MirrorDatabase(ctx context.Context, source, dir string) (*Mirror, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return nil, fmt.Errorf("mirror source must be an http(s) URL, got %q", source)
+	}
+	source = strings.TrimSuffix(source, "/")
+
+	if err := checkMirrorDir(dir); err != nil {
+		return nil, err
+	}
+	parent := filepath.Dir(filepath.Clean(dir))
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", parent, err)
+	}
+	tmp, err := os.MkdirTemp(parent, ".vulndb-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	err = fetchArchive(ctx, client, source, tmp)
+	if _, statErr := os.Stat(filepath.Join(tmp, "index", "db.json")); err == nil && statErr != nil {
+		err = errNoArchive // an archive laid out differently
+	}
+	if errors.Is(err, errNoArchive) {
+		err = fetchEntries(ctx, client, source, tmp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mirror, err := ReadMirror(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a Go vulnerability database: %w", source, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", dir, err)
+	}
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", dir, err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", dir, err)
+	}
+	mirror.Dir = dir
+	return mirror, nil
+}
+
+// checkMirrorDir refuses to replace a directory that holds anything but an
+// earlier mirror
+func checkMirrorDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) || (err == nil && len(entries) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index", "db.json")); err != nil {
+		return fmt.Errorf("%s is not empty and holds no vulnerability database; pick another directory", dir)
+	}
+	return nil
+}
+
+// ReadMirror describes the vulnerability database in dir
+func ReadMirror(dir string) (*Mirror, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index", "db.json"))
+	if err != nil {
+		return nil, err
+	}
+	var index struct {
+		Modified time.Time `json:"modified"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing index/db.json: %w", err)
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "ID", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	return &Mirror{Dir: dir, Vulns: len(entries), Modified: index.Modified}, nil
+}
+
+// fetchArchive downloads vulndb.zip and unpacks it into dir
+func fetchArchive(ctx context.Context, client *http.Client, source, dir string) error {
+	data, err := get(ctx, client, source+"/vulndb.zip")
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("reading vulndb.zip: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(f.Name)
+		if !isDatabaseFile(name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s from vulndb.zip: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s from vulndb.zip: %w", f.Name, err)
+		}
+		if err := writeFile(dir, name, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchEntries copies the index files, then every entry vulns.json lists
+func fetchEntries(ctx context.Context, client *http.Client, source, dir string) error {
+	for _, name := range []string{"index/db.json", "index/modules.json", "index/vulns.json"} {
+		data, err := get(ctx, client, source+"/"+name)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(dir, name, data); err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index", "vulns.json"))
+	if err != nil {
+		return err
+	}
+	var vulns []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &vulns); err != nil {
+		return fmt.Errorf("parsing index/vulns.json: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, mirrorConcurrency)
+	for _, v := range vulns {
+		name := "ID/" + v.ID + ".json"
+		if !isDatabaseFile(name) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			data, err := get(ctx, client, source+"/"+name)
+			if err == nil {
+				err = writeFile(dir, name, data)
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// isDatabaseFile reports whether a slash-separated path names a file of
+// the database layout, so nothing is written outside the mirror
+func isDatabaseFile(name string) bool {
+	dir, file := path.Split(name)
+	if (dir != "index/" && dir != "ID/") || !strings.HasSuffix(file, ".json") {
+		return false
+	}
+	return !strings.ContainsAny(file, `\:`) && !strings.HasPrefix(file, ".")
+}
+
+func writeFile(dir, name string, data []byte) error {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", target, err)
+	}
+	return nil
+}
+
+// get fetches a database file, returning errNoArchive for a missing vulndb.zip
+func get(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && strings.HasSuffix(url, "/vulndb.zip") {
+		return nil, errNoArchive
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,107 @@
+package vulndb
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var databaseFiles = map[string]string{
+	"/index/db.json":        `{"modified":"2025-03-01T10:00:00Z"}`,
+	"/index/modules.json":   `[{"path":"example.com/a","vulns":[{"id":"GO-2025-0001","modified":"2025-03-01T10:00:00Z"}]}]`,
+	"/index/vulns.json":     `[{"id":"GO-2025-0001","modified":"2025-03-01T10:00:00Z"},{"id":"GO-2025-0002","modified":"2025-03-01T10:00:00Z"}]`,
+	"/ID/GO-2025-0001.json": `{"id":"GO-2025-0001"}`,
+	"/ID/GO-2025-0002.json": `{"id":"GO-2025-0002"}`,
+}
+
+func TestMirrorDatabase_Entries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := databaseFiles[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "vulndb")
+	mirror, err := MirrorDatabase(context.Background(), srv.URL, dir)
+	if err != nil {
+		t.Fatalf("MirrorDatabase() error: %v", err)
+	}
+	if mirror.Vulns != 2 {
+		t.Errorf("Vulns = %d, want 2", mirror.Vulns)
+	}
+	if want := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC); !mirror.Modified.Equal(want) {
+		t.Errorf("Modified = %v, want %v", mirror.Modified, want)
+	}
+	for name, body := range databaseFiles {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(data) != body {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, body)
+		}
+	}
+
+	// An earlier mirror is replaced
+	if _, err := MirrorDatabase(context.Background(), srv.URL, dir); err != nil {
+		t.Errorf("refreshing the mirror: %v", err)
+	}
+}
+
+func TestMirrorDatabase_Archive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range databaseFiles {
+		w, _ := zw.Create(name[1:])
+		w.Write([]byte(body))
+	}
+	w, _ := zw.Create("../escape.json")
+	w.Write([]byte("{}"))
+	zw.Close()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/vulndb.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "vulndb")
+	mirror, err := MirrorDatabase(context.Background(), srv.URL+"/", dir)
+	if err != nil {
+		t.Fatalf("MirrorDatabase() error: %v", err)
+	}
+	if mirror.Vulns != 2 || requests != 1 {
+		t.Errorf("Vulns = %d after %d requests, want 2 after 1", mirror.Vulns, requests)
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape.json")); err == nil {
+		t.Error("a file outside the database layout was written")
+	}
+}
+
+func TestMirrorDatabase_Refusals(t *testing.T) {
+	if _, err := MirrorDatabase(context.Background(), "file:///tmp/db", t.TempDir()); err == nil {
+		t.Error("expected an error for a file:// source")
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep me"), 0o644)
+	if _, err := MirrorDatabase(context.Background(), "https://vuln.go.dev", dir); err == nil {
+		t.Error("expected an error for a directory holding other files")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("the directory was changed")
+	}
+}
@@ -7,9 +7,48 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
+// DefaultDatabase is the Go vulnerability database govulncheck uses unless
+// told otherwise
+const DefaultDatabase = "https://vuln.go.dev"
+
+// database is the vulnerability database scans use, "" for the default
+var database string
+
+// SetDatabase points scans at another copy of the vulnerability database: a
+// URL, such as an internal mirror, or a directory written by gx vulndb
+// mirror. An empty db restores the default.
+func SetDatabase(db string) {
+	database = DatabaseURL(db)
+}
+
+// Database returns the URL of the vulnerability database scans use
+func Database() string {
+	if database == "" {
+		return DefaultDatabase
+	}
+	return database
+}
+
+// DatabaseURL returns db as govulncheck's -db flag takes it, turning a
+// directory into a file:// URL
+func DatabaseURL(db string) string {
+	if db == "" || strings.Contains(db, "://") {
+		return db
+	}
+	if abs, err := filepath.Abs(db); err == nil {
+		db = abs
+	}
+	db = filepath.ToSlash(db)
+	if !strings.HasPrefix(db, "/") {
+		db = "/" + db // C:/db on Windows
+	}
+	return "file://" + db
+}
+
 // Vulnerability represents a security vulnerability
 type Vulnerability struct {
 	ID          string
@@ -72,7 +111,22 @@ type govulncheckMessage struct {
 
 // ScanModule scans a module for vulnerabilities using govulncheck
 func (s *Scanner) ScanModule(ctx context.Context, modPath string) (*ScanResult, error) {
-	cmd := exec.CommandContext(ctx, "govulncheck", "-json", "./...")
+	return s.run(ctx, filepath.Dir(modPath), "-json", "./...")
+}
+
+// ScanBinary scans a compiled Go executable for vulnerabilities using the
+// module and symbol information govulncheck reads from the binary
+func (s *Scanner) ScanBinary(ctx context.Context, binPath string) (*ScanResult, error) {
+	return s.run(ctx, "", "-json", "-mode=binary", binPath)
+}
+
+// run executes govulncheck and collects the vulnerabilities it reports
+func (s *Scanner) run(ctx context.Context, dir string, args ...string) (*ScanResult, error) {
+	if database != "" {
+		args = append([]string{"-db", database}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "govulncheck", args...)
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 
 	result := &ScanResult{
@@ -84,6 +138,11 @@ func (s *Scanner) ScanModule(ctx context.Context, modPath string) (*ScanResult,
 		if len(output) == 0 {
 			return nil, fmt.Errorf("govulncheck failed: %w", err)
 		}
+		// Without any JSON it failed before scanning, e.g. on a database
+		// that can't be read
+		if !bytes.Contains(output, []byte("{")) {
+			return nil, fmt.Errorf("govulncheck failed: %s", strings.TrimSpace(string(output)))
+		}
 	}
 
 	vulnMap := make(map[string]*Vulnerability)
@@ -138,6 +197,7 @@ func (s *Scanner) ScanModule(ctx context.Context, modPath string) (*ScanResult,
 	for _, vuln := range vulnMap {
 		result.Vulnerabilities = append(result.Vulnerabilities, vuln)
 	}
+	Sort(result.Vulnerabilities)
 
 	result.TotalVulns = len(result.Vulnerabilities)
 	result.TotalScanned = 1
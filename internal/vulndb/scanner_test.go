@@ -64,6 +64,44 @@ func TestNewScanner(t *testing.T) {
 	}
 }
 
+func TestDatabaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                      "",
+		"https://vuln.internal": "https://vuln.internal",
+		"file:///srv/vulndb":    "file:///srv/vulndb",
+		"/srv/vulndb":           "file:///srv/vulndb",
+	}
+	for db, want := range tests {
+		if got := DatabaseURL(db); got != want {
+			t.Errorf("DatabaseURL(%q) = %q, want %q", db, got, want)
+		}
+	}
+}
+
+func TestScanner_Database(t *testing.T) {
+	tmpDir := t.TempDir()
+	argsFile := filepath.Join(tmpDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\necho '{}'\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "govulncheck"), []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to create mock script: %v", err)
+	}
+	t.Setenv("PATH", tmpDir+":"+os.Getenv("PATH"))
+
+	SetDatabase("https://vuln.internal")
+	defer SetDatabase("")
+
+	if _, err := (&Scanner{}).ScanModule(context.Background(), "go.mod"); err != nil {
+		t.Fatalf("ScanModule() error: %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if got := strings.TrimSpace(string(args)); got != "-db https://vuln.internal -json ./..." {
+		t.Errorf("govulncheck args = %q", got)
+	}
+	if Database() != "https://vuln.internal" {
+		t.Errorf("Database() = %q", Database())
+	}
+}
+
 func TestScanner_ScanModule_MockOutput(t *testing.T) {
 	tmpDir := t.TempDir()
 	mockScript := filepath.Join(tmpDir, "govulncheck")
@@ -314,6 +352,20 @@ exit 2
 	}
 }
 
+func TestScanner_ScanModule_FailureMessage(t *testing.T) {
+	tmpDir := t.TempDir()
+	script := "#!/bin/sh\necho 'creating client: stat /srv/vulndb: no such file or directory'\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "govulncheck"), []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to create mock script: %v", err)
+	}
+	t.Setenv("PATH", tmpDir+":"+os.Getenv("PATH"))
+
+	_, err := (&Scanner{}).ScanModule(context.Background(), ".")
+	if err == nil || !strings.Contains(err.Error(), "no such file or directory") {
+		t.Errorf("ScanModule() error = %v, want govulncheck's message", err)
+	}
+}
+
 func TestScanner_ScanModule_InvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	mockScript := filepath.Join(tmpDir, "govulncheck")
@@ -418,6 +470,39 @@ exit 1
 	}
 }
 
+func TestScanner_ScanBinary(t *testing.T) {
+	tmpDir := t.TempDir()
+	mockScript := filepath.Join(tmpDir, "govulncheck")
+
+	// Fail unless invoked in binary mode on the given file
+	scriptContent := `#!/bin/sh
+[ "$2" = "-mode=binary" ] && [ "$3" = "/srv/app" ] || exit 2
+echo '{"osv":{"id":"GO-2025-0002","summary":"Binary vulnerability","database_specific":{"severity":"LOW"},"affected":[{"package":{"name":"stdlib","ecosystem":"Go"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.22.5"}]}]}]}}'
+exit 3
+`
+
+	if err := os.WriteFile(mockScript, []byte(scriptContent), 0o755); err != nil {
+		t.Fatalf("Failed to create mock script: %v", err)
+	}
+
+	originalPath := os.Getenv("PATH")
+	defer os.Setenv("PATH", originalPath)
+	os.Setenv("PATH", tmpDir+":"+originalPath)
+
+	scanner := &Scanner{}
+	result, err := scanner.ScanBinary(context.Background(), "/srv/app")
+	if err != nil {
+		t.Fatalf("ScanBinary() error: %v", err)
+	}
+
+	if len(result.Vulnerabilities) != 1 {
+		t.Fatalf("Expected 1 vulnerability, got %d", len(result.Vulnerabilities))
+	}
+	if v := result.Vulnerabilities[0]; v.ID != "GO-2025-0002" || v.Package != "stdlib" || v.Fixed != "1.22.5" {
+		t.Errorf("Vulnerability = %+v", v)
+	}
+}
+
 func TestFilterBySeverity(t *testing.T) {
 	vulns := []*Vulnerability{
 		{ID: "V1", Severity: "CRITICAL"},
@@ -0,0 +1,32 @@
+package vulndb
+
+import (
+	"sort"
+	"strings"
+)
+
+// severityRank orders severities from most to least severe; unknown
+// severities sort last
+var severityRank = map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "MODERATE": 2, "LOW": 3}
+
+func rank(severity string) int {
+	if r, ok := severityRank[strings.ToUpper(severity)]; ok {
+		return r
+	}
+	return len(severityRank)
+}
+
+// Sort orders vulnerabilities by severity, most severe first, then by ID
+// and package, so repeated scans produce identical output
+func Sort(vulns []*Vulnerability) {
+	sort.SliceStable(vulns, func(i, j int) bool {
+		a, b := vulns[i], vulns[j]
+		if ra, rb := rank(a.Severity), rank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Package < b.Package
+	})
+}
@@ -0,0 +1,32 @@
+package vulndb
+
+import (
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	vulns := []*Vulnerability{
+		{ID: "GO-2024-0003", Package: "example.com/a", Severity: "LOW"},
+		{ID: "GO-2024-0002", Package: "example.com/b", Severity: "UNKNOWN"},
+		{ID: "GO-2024-0009", Package: "example.com/a", Severity: "HIGH"},
+		{ID: "GO-2024-0001", Package: "example.com/b", Severity: "high"},
+		{ID: "GO-2024-0001", Package: "example.com/a", Severity: "HIGH"},
+		{ID: "GO-2024-0005", Package: "example.com/c", Severity: "CRITICAL"},
+	}
+
+	Sort(vulns)
+
+	want := []string{
+		"GO-2024-0005 example.com/c",
+		"GO-2024-0001 example.com/a",
+		"GO-2024-0001 example.com/b",
+		"GO-2024-0009 example.com/a",
+		"GO-2024-0003 example.com/a",
+		"GO-2024-0002 example.com/b",
+	}
+	for i, v := range vulns {
+		if got := v.ID + " " + v.Package; got != want[i] {
+			t.Errorf("position %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
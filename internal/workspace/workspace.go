@@ -0,0 +1,145 @@
+// Package workspace reads go.work files so commands can run across every
+// module in a multi-module workspace.
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// FileName is the name of a workspace file
+const FileName = "go.work"
+
+// Workspace is a parsed go.work file
+type Workspace struct {
+	// Path is the location of the go.work file, or the directory Scan searched
+	Path    string
+	Members []Member
+}
+
+// Member is a module listed in a use directive
+type Member struct {
+	// Dir is the directory as written in go.work, or relative to the
+	// directory Scan searched
+	Dir string
+	// ModPath is the path to the member's go.mod
+	ModPath string
+	// ModulePath is the module path declared in the member's go.mod
+	ModulePath string
+}
+
+// Detect returns the go.work file gx should use, honoring GOWORK like the go
+// command: "off" disables workspace mode and a path selects a specific file.
+// Otherwise a go.work in the module directory dir is used.
+func Detect(dir string) (string, bool) {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return "", false
+	case "":
+	default:
+		return gowork, true
+	}
+
+	path := filepath.Join(dir, FileName)
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// Load parses a go.work file and resolves its member modules
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	ws := &Workspace{Path: path}
+	root := filepath.Dir(path)
+
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		modPath := filepath.Join(dir, "go.mod")
+
+		modData, err := os.ReadFile(modPath)
+		if err != nil {
+			return nil, fmt.Errorf("workspace member %s: %w", use.Path, err)
+		}
+
+		ws.Members = append(ws.Members, Member{
+			Dir:        use.Path,
+			ModPath:    modPath,
+			ModulePath: modfile.ModulePath(modData),
+		})
+	}
+
+	if len(ws.Members) == 0 {
+		return nil, fmt.Errorf("%s has no use directives", path)
+	}
+
+	return ws, nil
+}
+
+// Scan finds every module under root, for monorepos without a go.work. Like
+// the go command, it skips vendor and testdata directories and those whose
+// names start with "." or "_".
+func Scan(root string) (*Workspace, error) {
+	ws := &Workspace{Path: root}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "go.mod" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dir, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if dir != "." {
+			dir = "./" + filepath.ToSlash(dir)
+		}
+
+		ws.Members = append(ws.Members, Member{
+			Dir:        dir,
+			ModPath:    path,
+			ModulePath: modfile.ModulePath(data),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", root, err)
+	}
+
+	if len(ws.Members) == 0 {
+		return nil, fmt.Errorf("no go.mod found under %s", root)
+	}
+
+	return ws, nil
+}
@@ -0,0 +1,122 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.22\n\nuse (\n\t.\n\t./tools\n)\n")
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(root, "tools", "go.mod"), "module example.com/app/tools\n\ngo 1.22\n")
+
+	ws, err := Load(filepath.Join(root, "go.work"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if len(ws.Members) != 2 {
+		t.Fatalf("Members = %+v, want 2", ws.Members)
+	}
+
+	tools := ws.Members[1]
+	if tools.Dir != "./tools" || tools.ModulePath != "example.com/app/tools" {
+		t.Errorf("Members[1] = %+v", tools)
+	}
+	if tools.ModPath != filepath.Join(root, "tools", "go.mod") {
+		t.Errorf("ModPath = %q", tools.ModPath)
+	}
+}
+
+func TestLoad_MissingMember(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.work"), "go 1.22\n\nuse ./missing\n")
+
+	if _, err := Load(filepath.Join(root, "go.work")); err == nil {
+		t.Error("Load() should fail when a member has no go.mod")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	t.Setenv("GOWORK", "")
+	if _, ok := Detect("."); ok {
+		t.Error("Detect() without go.work should be false")
+	}
+
+	writeFile(t, filepath.Join(dir, "go.work"), "go 1.22\n")
+	if path, ok := Detect("."); !ok || path != FileName {
+		t.Errorf("Detect() = %q, %v", path, ok)
+	}
+
+	// A module elsewhere, as --mod names, uses its own directory's go.work
+	other := t.TempDir()
+	if _, ok := Detect(other); ok {
+		t.Error("Detect(other) should ignore the current directory's go.work")
+	}
+	writeFile(t, filepath.Join(other, "go.work"), "go 1.22\n")
+	if path, ok := Detect(other); !ok || path != filepath.Join(other, FileName) {
+		t.Errorf("Detect(other) = %q, %v", path, ok)
+	}
+
+	t.Setenv("GOWORK", "off")
+	if _, ok := Detect("."); ok {
+		t.Error("Detect() with GOWORK=off should be false")
+	}
+
+	t.Setenv("GOWORK", "/elsewhere/go.work")
+	if path, ok := Detect("."); !ok || path != "/elsewhere/go.work" {
+		t.Errorf("Detect() with GOWORK path = %q, %v", path, ok)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(root, "services", "api", "go.mod"), "module example.com/app/api\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(root, "vendor", "example.com", "dep", "go.mod"), "module example.com/dep\n")
+	writeFile(t, filepath.Join(root, "api", "testdata", "go.mod"), "module example.com/fixture\n")
+	writeFile(t, filepath.Join(root, ".cache", "go.mod"), "module example.com/cached\n")
+	writeFile(t, filepath.Join(root, "_old", "go.mod"), "module example.com/old\n")
+
+	ws, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	var dirs, modules []string
+	for _, m := range ws.Members {
+		dirs = append(dirs, m.Dir)
+		modules = append(modules, m.ModulePath)
+	}
+	if strings.Join(dirs, ",") != ".,./services/api" {
+		t.Errorf("Dirs = %v, want [. ./services/api]", dirs)
+	}
+	if strings.Join(modules, ",") != "example.com/app,example.com/app/api" {
+		t.Errorf("ModulePaths = %v", modules)
+	}
+	if ws.Members[1].ModPath != filepath.Join(root, "services", "api", "go.mod") {
+		t.Errorf("ModPath = %q", ws.Members[1].ModPath)
+	}
+}
+
+func TestScan_NoModules(t *testing.T) {
+	if _, err := Scan(t.TempDir()); err == nil {
+		t.Error("Scan() should fail when there is no go.mod")
+	}
+}